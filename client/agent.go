@@ -32,6 +32,7 @@ type AgentQARequest struct {
 	MentionedItems   []MentionedItem `json:"mentioned_items,omitempty"`    // @mentioned knowledge bases and files
 	DisableTitle     bool            `json:"disable_title,omitempty"`      // Whether to disable auto title generation
 	MCPServiceIDs    []string        `json:"mcp_service_ids,omitempty"`    // Optional MCP service allow list (deprecated)
+	TimeoutSeconds   int             `json:"timeout_seconds,omitempty"`    // Wait limit for synchronous calls (capped by server config)
 }
 
 // AgentResponseType defines the type of agent response
@@ -98,6 +99,30 @@ func (c *Client) AgentQAStreamWithRequest(ctx context.Context,
 	return c.processAgentSSEStream(resp.Body, callback)
 }
 
+// AgentQA performs agent-based Q&A without streaming and returns the answer, references and agent steps.
+// The call blocks until the agent finishes; make sure the client timeout (see WithTimeout) is long enough.
+func (c *Client) AgentQA(ctx context.Context, sessionID string, request *AgentQARequest) (*QAResponse, error) {
+	if request == nil {
+		return nil, fmt.Errorf("agent QA request cannot be nil")
+	}
+	if strings.TrimSpace(request.Query) == "" {
+		return nil, fmt.Errorf("agent QA query cannot be empty")
+	}
+
+	path := fmt.Sprintf("/api/v1/agent-chat/%s/sync", sessionID)
+	resp, err := c.doRequest(ctx, http.MethodPost, path, request, nil)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+
+	var response QAResponseEnvelope
+	if err := parseResponse(resp, &response); err != nil {
+		return nil, err
+	}
+
+	return &response.Data, nil
+}
+
 // processAgentSSEStream processes the SSE stream and invokes callback for each event
 func (c *Client) processAgentSSEStream(reader io.Reader, callback AgentEventCallback) error {
 	scanner := bufio.NewScanner(reader)
@@ -170,6 +195,11 @@ func (as *AgentSession) AskWithRequest(
 	return as.client.AgentQAStreamWithRequest(ctx, as.sessionID, request, callback)
 }
 
+// AskSync sends a customized agent request for this session and waits for the complete answer.
+func (as *AgentSession) AskSync(ctx context.Context, request *AgentQARequest) (*QAResponse, error) {
+	return as.client.AgentQA(ctx, as.sessionID, request)
+}
+
 // GetSessionID returns the session ID
 func (as *AgentSession) GetSessionID() string {
 	return as.sessionID
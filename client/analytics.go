@@ -0,0 +1,234 @@
+// Package client provides the implementation for interacting with the WeKnora API
+// The Analytics related interfaces report how knowledge bases are used in chats
+// Hit counts, poorly answered queries and answer feedback can be queried per knowledge base
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// AnalyticsDateRange is the inclusive range of days analytics cover
+// Empty dates mean the server defaults: the last 30 days up to today
+type AnalyticsDateRange struct {
+	StartDate string // First day, YYYY-MM-DD
+	EndDate   string // Last day, YYYY-MM-DD
+}
+
+// query returns the date range as query parameters
+func (r AnalyticsDateRange) query() url.Values {
+	query := url.Values{}
+	if r.StartDate != "" {
+		query.Add("start_date", r.StartDate)
+	}
+	if r.EndDate != "" {
+		query.Add("end_date", r.EndDate)
+	}
+	return query
+}
+
+// addPage adds the pagination query parameters
+func addPage(query url.Values, page, pageSize int) {
+	if page > 0 {
+		query.Add("page", strconv.Itoa(page))
+	}
+	if pageSize > 0 {
+		query.Add("page_size", strconv.Itoa(pageSize))
+	}
+}
+
+// KnowledgeBaseAnalytics summarizes how a knowledge base was used in a date range
+type KnowledgeBaseAnalytics struct {
+	KnowledgeBaseID string `json:"knowledge_base_id"`
+	DateRange       struct {
+		StartDate time.Time `json:"start_date"`
+		EndDate   time.Time `json:"end_date"`
+	} `json:"date_range"`
+	TotalHits            int64 `json:"total_hits"`             // Times chunks were returned to the chat
+	HitChunks            int64 `json:"hit_chunks"`             // Distinct chunks returned at least once
+	ZeroResultQueries    int64 `json:"zero_result_queries"`    // Queries that found nothing
+	LowConfidenceQueries int64 `json:"low_confidence_queries"` // Queries whose best result scored low
+	UpFeedback           int64 `json:"up_feedback"`            // Up ratings of answers citing the knowledge base
+	DownFeedback         int64 `json:"down_feedback"`          // Down ratings of answers citing the knowledge base
+}
+
+// AnalyticsEntry is a FAQ entry, chunk or document with its usage in a date range
+type AnalyticsEntry struct {
+	ChunkID        string     `json:"chunk_id,omitempty"`
+	EntryID        int64      `json:"entry_id,omitempty"` // FAQ entry ID, only set for FAQ chunks
+	ChunkType      string     `json:"chunk_type,omitempty"`
+	KnowledgeID    string     `json:"knowledge_id"`
+	KnowledgeTitle string     `json:"knowledge_title,omitempty"`
+	Title          string     `json:"title"` // Standard question of a FAQ entry, or the beginning of a chunk
+	HitCount       int64      `json:"hit_count"`
+	LastHitDate    *time.Time `json:"last_hit_date,omitempty"`
+	UpFeedback     int64      `json:"up_feedback"`
+	DownFeedback   int64      `json:"down_feedback"`
+}
+
+// AnalyticsEntriesPage contains paginated analytics entries
+type AnalyticsEntriesPage struct {
+	Total    int64            `json:"total"`
+	Page     int              `json:"page"`
+	PageSize int              `json:"page_size"`
+	Entries  []AnalyticsEntry `json:"data"`
+}
+
+// QueryLog is a chat query a knowledge base answered poorly
+type QueryLog struct {
+	ID              string    `json:"id"`
+	KnowledgeBaseID string    `json:"knowledge_base_id"`
+	SessionID       string    `json:"session_id"`
+	MessageID       string    `json:"message_id"`
+	Query           string    `json:"query"`
+	RewriteQuery    string    `json:"rewrite_query"`
+	Type            string    `json:"type"` // zero_result or low_confidence
+	ResultCount     int       `json:"result_count"`
+	MaxScore        float64   `json:"max_score"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// QueryLogsPage contains paginated query logs
+type QueryLogsPage struct {
+	Total    int64      `json:"total"`
+	Page     int        `json:"page"`
+	PageSize int        `json:"page_size"`
+	Logs     []QueryLog `json:"data"`
+}
+
+// MessageFeedbackPage contains paginated message feedback
+type MessageFeedbackPage struct {
+	Total    int64             `json:"total"`
+	Page     int               `json:"page"`
+	PageSize int               `json:"page_size"`
+	Feedback []MessageFeedback `json:"data"`
+}
+
+// GetKnowledgeBaseAnalytics gets the usage summary of a knowledge base
+func (c *Client) GetKnowledgeBaseAnalytics(ctx context.Context,
+	knowledgeBaseID string, dateRange AnalyticsDateRange,
+) (*KnowledgeBaseAnalytics, error) {
+	path := fmt.Sprintf("/api/v1/knowledge-bases/%s/analytics", knowledgeBaseID)
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil, dateRange.query())
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Success bool                    `json:"success"`
+		Data    *KnowledgeBaseAnalytics `json:"data"`
+	}
+	if err := parseResponse(resp, &response); err != nil {
+		return nil, err
+	}
+	return response.Data, nil
+}
+
+// ListTopHitEntries lists the most hit chunks or FAQ entries of a knowledge base, 0 limit for the server default
+func (c *Client) ListTopHitEntries(ctx context.Context,
+	knowledgeBaseID string, dateRange AnalyticsDateRange, limit int,
+) ([]AnalyticsEntry, error) {
+	path := fmt.Sprintf("/api/v1/knowledge-bases/%s/analytics/top-hits", knowledgeBaseID)
+	query := dateRange.query()
+	if limit > 0 {
+		query.Add("limit", strconv.Itoa(limit))
+	}
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Success bool             `json:"success"`
+		Data    []AnalyticsEntry `json:"data"`
+	}
+	if err := parseResponse(resp, &response); err != nil {
+		return nil, err
+	}
+	return response.Data, nil
+}
+
+// ListNeverHitEntries lists the FAQ entries, or documents of a document knowledge base, never hit in the date range
+func (c *Client) ListNeverHitEntries(ctx context.Context,
+	knowledgeBaseID string, dateRange AnalyticsDateRange, page, pageSize int,
+) (*AnalyticsEntriesPage, error) {
+	path := fmt.Sprintf("/api/v1/knowledge-bases/%s/analytics/never-hit", knowledgeBaseID)
+	query := dateRange.query()
+	addPage(query, page, pageSize)
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Success bool                  `json:"success"`
+		Data    *AnalyticsEntriesPage `json:"data"`
+	}
+	if err := parseResponse(resp, &response); err != nil {
+		return nil, err
+	}
+	if response.Data == nil {
+		return &AnalyticsEntriesPage{}, nil
+	}
+	return response.Data, nil
+}
+
+// ListQueryLogs lists the poorly answered queries of a knowledge base, empty logType for all types
+func (c *Client) ListQueryLogs(ctx context.Context,
+	knowledgeBaseID string, logType string, dateRange AnalyticsDateRange, page, pageSize int,
+) (*QueryLogsPage, error) {
+	path := fmt.Sprintf("/api/v1/knowledge-bases/%s/analytics/queries", knowledgeBaseID)
+	query := dateRange.query()
+	if logType != "" {
+		query.Add("type", logType)
+	}
+	addPage(query, page, pageSize)
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Success bool           `json:"success"`
+		Data    *QueryLogsPage `json:"data"`
+	}
+	if err := parseResponse(resp, &response); err != nil {
+		return nil, err
+	}
+	if response.Data == nil {
+		return &QueryLogsPage{}, nil
+	}
+	return response.Data, nil
+}
+
+// ListMessageFeedback lists the feedback on answers citing a knowledge base, empty rating for all ratings
+func (c *Client) ListMessageFeedback(ctx context.Context,
+	knowledgeBaseID string, rating string, dateRange AnalyticsDateRange, page, pageSize int,
+) (*MessageFeedbackPage, error) {
+	path := fmt.Sprintf("/api/v1/knowledge-bases/%s/analytics/feedback", knowledgeBaseID)
+	query := dateRange.query()
+	if rating != "" {
+		query.Add("rating", rating)
+	}
+	addPage(query, page, pageSize)
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Success bool                 `json:"success"`
+		Data    *MessageFeedbackPage `json:"data"`
+	}
+	if err := parseResponse(resp, &response); err != nil {
+		return nil, err
+	}
+	if response.Data == nil {
+		return &MessageFeedbackPage{}, nil
+	}
+	return response.Data, nil
+}
@@ -26,6 +26,8 @@ type FAQEntry struct {
 	Answers           []string  `json:"answers"`
 	AnswerStrategy    string    `json:"answer_strategy"`
 	IndexMode         string    `json:"index_mode"`
+	ReviewStatus      string    `json:"review_status"` // draft, pending_review, published or archived
+	Reviewer          string    `json:"reviewer,omitempty"`
 	UpdatedAt         time.Time `json:"updated_at"`
 	CreatedAt         time.Time `json:"created_at"`
 	Score             float64   `json:"score,omitempty"`
@@ -34,6 +36,9 @@ type FAQEntry struct {
 	// MatchedQuestion is the actual question text that was matched in FAQ search
 	// Could be the standard question or one of the similar questions
 	MatchedQuestion string `json:"matched_question,omitempty"`
+	// Conflicts are the existing entries with near-identical questions, only set by CreateFAQEntry
+	Conflicts       []FAQConflictMatch  `json:"conflicts,omitempty"`
+	MergeSuggestion *FAQMergeSuggestion `json:"merge_suggestion,omitempty"`
 }
 
 // FAQEntryPayload is used to create or update a FAQ entry.
@@ -49,6 +54,7 @@ type FAQEntryPayload struct {
 	TagName           string   `json:"tag_name,omitempty"`
 	IsEnabled         *bool    `json:"is_enabled,omitempty"`
 	IsRecommended     *bool    `json:"is_recommended,omitempty"`
+	ReviewStatus      string   `json:"review_status,omitempty"` // draft or published, only used on create
 }
 
 // FAQBatchUpsertPayload represents the request body for batch import (append/replace).
@@ -56,8 +62,9 @@ type FAQBatchUpsertPayload struct {
 	Entries     []FAQEntryPayload `json:"entries"`
 	Mode        string            `json:"mode"`
 	KnowledgeID string            `json:"knowledge_id,omitempty"`
-	TaskID      string            `json:"task_id,omitempty"` // Optional, if not provided, a UUID will be generated
-	DryRun      bool              `json:"dry_run,omitempty"` // If true, only validate without importing
+	TaskID      string            `json:"task_id,omitempty"`  // Optional, if not provided, a UUID will be generated
+	DryRun      bool              `json:"dry_run,omitempty"`  // If true, only validate without importing
+	AsDraft     bool              `json:"as_draft,omitempty"` // If true, import entries as drafts
 }
 
 // FAQEntryFieldsUpdate represents the fields that can be updated for a single FAQ entry.
@@ -419,6 +426,9 @@ type FAQImportProgress struct {
 	CreatedAt        int64             `json:"created_at"`
 	UpdatedAt        int64             `json:"updated_at"`
 	DryRun           bool              `json:"dry_run,omitempty"` // Whether this is a dry run validation
+	// Existing entries that valid entries conflict or overlap with, only checked by dry runs
+	Conflicts          []FAQImportConflict `json:"conflicts,omitempty"`
+	ConflictsTruncated bool                `json:"conflicts_truncated,omitempty"`
 
 	// Result fields (populated when Status == "completed")
 	ImportMode     string    `json:"import_mode,omitempty"`
@@ -466,3 +476,344 @@ func (c *Client) UpdateLastFAQImportResultDisplayStatus(ctx context.Context, kno
 	var response faqSimpleResponse
 	return parseResponse(resp, &response)
 }
+
+// FAQMiningRequest configures a FAQ mining run, zero values mean server defaults.
+type FAQMiningRequest struct {
+	Days                   int      `json:"days,omitempty"`                 // Days of chat history to mine, default 7
+	ScoreThreshold         float64  `json:"score_threshold,omitempty"`      // Reference score below which an answer is poorly grounded, default 0.5
+	SimilarityThreshold    float64  `json:"similarity_threshold,omitempty"` // Embedding similarity from which questions are clustered, default 0.85
+	MinQuestionCount       int      `json:"min_question_count,omitempty"`   // Times a question must be asked, default 2
+	MaxCandidates          int      `json:"max_candidates,omitempty"`       // Candidates proposed per run, default 20
+	SourceKnowledgeBaseIDs []string `json:"source_knowledge_base_ids,omitempty"`
+}
+
+// FAQCandidate is a FAQ entry proposed from unanswered chat questions.
+type FAQCandidate struct {
+	ID               string     `json:"id"`
+	KnowledgeBaseID  string     `json:"knowledge_base_id"`
+	StandardQuestion string     `json:"standard_question"`
+	SimilarQuestions []string   `json:"similar_questions"`
+	Answer           string     `json:"answer"`
+	SourceChunkIDs   []string   `json:"source_chunk_ids"`
+	MessageIDs       []string   `json:"message_ids"`
+	QuestionCount    int        `json:"question_count"`
+	FallbackCount    int        `json:"fallback_count"`
+	MaxScore         float64    `json:"max_score"`
+	LastAskedAt      time.Time  `json:"last_asked_at"`
+	Status           string     `json:"status"` // pending, accepted or rejected
+	FAQEntryID       int64      `json:"faq_entry_id"`
+	ReviewedAt       *time.Time `json:"reviewed_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// FAQCandidatesPage contains paginated FAQ candidates.
+type FAQCandidatesPage struct {
+	Total      int64          `json:"total"`
+	Page       int            `json:"page"`
+	PageSize   int            `json:"page_size"`
+	Candidates []FAQCandidate `json:"data"`
+}
+
+// FAQCandidatesResponse wraps the paginated FAQ candidates response.
+type FAQCandidatesResponse struct {
+	Success bool               `json:"success"`
+	Data    *FAQCandidatesPage `json:"data"`
+	Message string             `json:"message,omitempty"`
+	Code    string             `json:"code,omitempty"`
+}
+
+// FAQCandidateAcceptRequest overrides fields of a candidate when accepting it, empty fields keep the candidate values.
+type FAQCandidateAcceptRequest struct {
+	StandardQuestion string   `json:"standard_question,omitempty"`
+	SimilarQuestions []string `json:"similar_questions,omitempty"`
+	Answers          []string `json:"answers,omitempty"`
+	TagID            int64    `json:"tag_id,omitempty"`
+	IsEnabled        *bool    `json:"is_enabled,omitempty"`
+}
+
+// StartFAQMining starts mining FAQ candidates for a FAQ knowledge base from chat history and returns the task ID.
+func (c *Client) StartFAQMining(ctx context.Context,
+	knowledgeBaseID string, payload *FAQMiningRequest,
+) (string, error) {
+	path := fmt.Sprintf("/api/v1/knowledge-bases/%s/faq/mining", knowledgeBaseID)
+	resp, err := c.doRequest(ctx, http.MethodPost, path, payload, nil)
+	if err != nil {
+		return "", err
+	}
+
+	var response FAQUpsertResponse
+	if err := parseResponse(resp, &response); err != nil {
+		return "", err
+	}
+	if response.Data == nil {
+		return "", nil
+	}
+	return response.Data.TaskID, nil
+}
+
+// ListFAQCandidates returns the mined FAQ candidates of a knowledge base.
+// status filters by review status ("pending", "accepted", "rejected", "" for all).
+func (c *Client) ListFAQCandidates(ctx context.Context,
+	knowledgeBaseID string, status string, page, pageSize int,
+) (*FAQCandidatesPage, error) {
+	path := fmt.Sprintf("/api/v1/knowledge-bases/%s/faq/candidates", knowledgeBaseID)
+	query := url.Values{}
+	if status != "" {
+		query.Add("status", status)
+	}
+	if page > 0 {
+		query.Add("page", strconv.Itoa(page))
+	}
+	if pageSize > 0 {
+		query.Add("page_size", strconv.Itoa(pageSize))
+	}
+
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var response FAQCandidatesResponse
+	if err := parseResponse(resp, &response); err != nil {
+		return nil, err
+	}
+	if response.Data == nil {
+		return &FAQCandidatesPage{}, nil
+	}
+	return response.Data, nil
+}
+
+// AcceptFAQCandidate creates a FAQ entry from a pending candidate and returns the entry.
+func (c *Client) AcceptFAQCandidate(ctx context.Context,
+	knowledgeBaseID string, candidateID string, payload *FAQCandidateAcceptRequest,
+) (*FAQEntry, error) {
+	path := fmt.Sprintf("/api/v1/knowledge-bases/%s/faq/candidates/%s/accept", knowledgeBaseID, candidateID)
+	resp, err := c.doRequest(ctx, http.MethodPost, path, payload, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response FAQEntryResponse
+	if err := parseResponse(resp, &response); err != nil {
+		return nil, err
+	}
+	return response.Data, nil
+}
+
+// RejectFAQCandidate dismisses a pending candidate so it is not proposed again.
+func (c *Client) RejectFAQCandidate(ctx context.Context, knowledgeBaseID string, candidateID string) error {
+	path := fmt.Sprintf("/api/v1/knowledge-bases/%s/faq/candidates/%s/reject", knowledgeBaseID, candidateID)
+	resp, err := c.doRequest(ctx, http.MethodPost, path, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	var response faqSimpleResponse
+	return parseResponse(resp, &response)
+}
+
+// FAQReviewRequest applies a review action to a FAQ entry.
+// Action is one of submit, approve, reject, publish, archive or restore.
+type FAQReviewRequest struct {
+	Action     string `json:"action"`
+	ReviewerID string `json:"reviewer_id,omitempty"` // Reviewer to assign on submit
+	Comment    string `json:"comment,omitempty"`
+}
+
+// FAQEntryComment is a review comment or action recorded on a FAQ entry
+type FAQEntryComment struct {
+	ID              string    `json:"id"`
+	KnowledgeBaseID string    `json:"knowledge_base_id"`
+	ChunkID         string    `json:"chunk_id"`
+	EntryID         int64     `json:"entry_id"`
+	UserID          string    `json:"user_id"`
+	Username        string    `json:"username"`
+	Action          string    `json:"action"` // Empty for plain comments
+	ReviewStatus    string    `json:"review_status"`
+	Content         string    `json:"content"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// FAQEntryCommentResponse wraps a FAQ entry comment
+type FAQEntryCommentResponse struct {
+	Success bool             `json:"success"`
+	Data    *FAQEntryComment `json:"data"`
+	Message string           `json:"message,omitempty"`
+	Code    string           `json:"code,omitempty"`
+}
+
+// FAQEntryCommentsResponse wraps the comments of a FAQ entry
+type FAQEntryCommentsResponse struct {
+	Success bool              `json:"success"`
+	Data    []FAQEntryComment `json:"data"`
+	Message string            `json:"message,omitempty"`
+	Code    string            `json:"code,omitempty"`
+}
+
+// ReviewFAQEntry moves a FAQ entry through the review workflow and returns the updated entry.
+// Only published entries are retrievable; approve and reject are limited to the assigned reviewer.
+func (c *Client) ReviewFAQEntry(ctx context.Context,
+	knowledgeBaseID string, entryID int64, payload *FAQReviewRequest,
+) (*FAQEntry, error) {
+	path := fmt.Sprintf("/api/v1/knowledge-bases/%s/faq/entries/%d/review", knowledgeBaseID, entryID)
+	resp, err := c.doRequest(ctx, http.MethodPost, path, payload, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response FAQEntryResponse
+	if err := parseResponse(resp, &response); err != nil {
+		return nil, err
+	}
+	return response.Data, nil
+}
+
+// AssignFAQEntryReviewer assigns the reviewer of a FAQ entry, an empty reviewerID unassigns it.
+func (c *Client) AssignFAQEntryReviewer(ctx context.Context,
+	knowledgeBaseID string, entryID int64, reviewerID string,
+) (*FAQEntry, error) {
+	path := fmt.Sprintf("/api/v1/knowledge-bases/%s/faq/entries/%d/reviewer", knowledgeBaseID, entryID)
+	payload := map[string]string{"reviewer_id": reviewerID}
+	resp, err := c.doRequest(ctx, http.MethodPut, path, payload, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response FAQEntryResponse
+	if err := parseResponse(resp, &response); err != nil {
+		return nil, err
+	}
+	return response.Data, nil
+}
+
+// ListFAQEntryComments returns the review comments and actions of a FAQ entry, oldest first.
+func (c *Client) ListFAQEntryComments(ctx context.Context,
+	knowledgeBaseID string, entryID int64,
+) ([]FAQEntryComment, error) {
+	path := fmt.Sprintf("/api/v1/knowledge-bases/%s/faq/entries/%d/comments", knowledgeBaseID, entryID)
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response FAQEntryCommentsResponse
+	if err := parseResponse(resp, &response); err != nil {
+		return nil, err
+	}
+	return response.Data, nil
+}
+
+// AddFAQEntryComment adds a review comment to a FAQ entry.
+func (c *Client) AddFAQEntryComment(ctx context.Context,
+	knowledgeBaseID string, entryID int64, content string,
+) (*FAQEntryComment, error) {
+	path := fmt.Sprintf("/api/v1/knowledge-bases/%s/faq/entries/%d/comments", knowledgeBaseID, entryID)
+	payload := map[string]string{"content": content}
+	resp, err := c.doRequest(ctx, http.MethodPost, path, payload, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response FAQEntryCommentResponse
+	if err := parseResponse(resp, &response); err != nil {
+		return nil, err
+	}
+	return response.Data, nil
+}
+
+// FAQConflictMatch is an existing FAQ entry with a question near-identical to a question of the checked entry.
+// Type is "conflict" when the answers differ and "overlap" when they are the same.
+type FAQConflictMatch struct {
+	Type             string   `json:"type"`
+	Question         string   `json:"question"`
+	MatchedQuestion  string   `json:"matched_question"`
+	Score            float64  `json:"score"`
+	EntryID          int64    `json:"entry_id"`
+	StandardQuestion string   `json:"standard_question"`
+	Answers          []string `json:"answers"`
+}
+
+// FAQMergeSuggestion proposes adding the questions of one entry to another as similar questions.
+type FAQMergeSuggestion struct {
+	TargetEntryID    int64    `json:"target_entry_id"`
+	SimilarQuestions []string `json:"similar_questions"`
+	RemoveEntryID    int64    `json:"remove_entry_id,omitempty"` // Entry to delete once merged, 0 if none
+}
+
+// FAQImportConflict reports the existing entries an imported entry conflicts or overlaps with.
+type FAQImportConflict struct {
+	Index            int                 `json:"index"` // Entry index in the import batch (0-based)
+	StandardQuestion string              `json:"standard_question"`
+	Matches          []FAQConflictMatch  `json:"matches"`
+	Suggestion       *FAQMergeSuggestion `json:"suggestion,omitempty"`
+}
+
+// FAQConflictGroup reports the entries an existing entry conflicts or overlaps with.
+type FAQConflictGroup struct {
+	EntryID          int64               `json:"entry_id"`
+	StandardQuestion string              `json:"standard_question"`
+	Answers          []string            `json:"answers"`
+	Matches          []FAQConflictMatch  `json:"matches"`
+	Suggestion       *FAQMergeSuggestion `json:"suggestion,omitempty"`
+}
+
+// FAQConflictReport is the latest conflict report of a FAQ knowledge base.
+type FAQConflictReport struct {
+	TaskID          string             `json:"task_id"`
+	KnowledgeBaseID string             `json:"knowledge_base_id"`
+	Status          string             `json:"status"` // processing, completed or failed
+	Threshold       float64            `json:"threshold"`
+	ScannedEntries  int                `json:"scanned_entries"`
+	TotalEntries    int                `json:"total_entries"`
+	Truncated       bool               `json:"truncated,omitempty"`
+	Groups          []FAQConflictGroup `json:"groups"`
+	Error           string             `json:"error,omitempty"`
+	StartedAt       time.Time          `json:"started_at"`
+	FinishedAt      *time.Time         `json:"finished_at,omitempty"`
+}
+
+// FAQConflictReportResponse wraps the FAQ conflict report response.
+type FAQConflictReportResponse struct {
+	Success bool               `json:"success"`
+	Data    *FAQConflictReport `json:"data"`
+	Message string             `json:"message,omitempty"`
+	Code    string             `json:"code,omitempty"`
+}
+
+// StartFAQConflictScan starts scanning a FAQ knowledge base for conflicting entries and returns the task ID.
+// threshold is the vector similarity from which questions count as near-identical, 0 for the server default.
+func (c *Client) StartFAQConflictScan(ctx context.Context,
+	knowledgeBaseID string, threshold float64,
+) (string, error) {
+	path := fmt.Sprintf("/api/v1/knowledge-bases/%s/faq/conflicts/scan", knowledgeBaseID)
+	payload := map[string]float64{"threshold": threshold}
+	resp, err := c.doRequest(ctx, http.MethodPost, path, payload, nil)
+	if err != nil {
+		return "", err
+	}
+
+	var response FAQUpsertResponse
+	if err := parseResponse(resp, &response); err != nil {
+		return "", err
+	}
+	if response.Data == nil {
+		return "", nil
+	}
+	return response.Data.TaskID, nil
+}
+
+// GetFAQConflictReport returns the latest conflict report of a FAQ knowledge base.
+func (c *Client) GetFAQConflictReport(ctx context.Context, knowledgeBaseID string) (*FAQConflictReport, error) {
+	path := fmt.Sprintf("/api/v1/knowledge-bases/%s/faq/conflicts", knowledgeBaseID)
+	resp, err := c.doRequest(ctx, http.MethodGet, path, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response FAQConflictReportResponse
+	if err := parseResponse(resp, &response); err != nil {
+		return nil, err
+	}
+	return response.Data, nil
+}
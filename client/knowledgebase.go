@@ -27,6 +27,7 @@ type KnowledgeBase struct {
 	VLMConfig             VLMConfig             `json:"vlm_config"`
 	StorageConfig         StorageConfig         `json:"cos_config"`
 	ExtractConfig         *ExtractConfig        `json:"extract_config"`
+	MultilingualConfig    *MultilingualConfig   `json:"multilingual_config"`
 	CreatedAt             time.Time             `json:"created_at"`
 	UpdatedAt             time.Time             `json:"updated_at"`
 	// Computed fields (not stored in database)
@@ -41,6 +42,7 @@ type KnowledgeBaseConfig struct {
 	ChunkingConfig        ChunkingConfig        `json:"chunking_config"`
 	ImageProcessingConfig ImageProcessingConfig `json:"image_processing_config"`
 	FAQConfig             *FAQConfig            `json:"faq_config"`
+	MultilingualConfig    *MultilingualConfig   `json:"multilingual_config,omitempty"`
 }
 
 // ChunkingConfig represents document chunking configuration
@@ -56,6 +58,12 @@ type FAQConfig struct {
 	QuestionIndexMode string `json:"question_index_mode"`
 }
 
+// MultilingualConfig represents the cross-lingual retrieval configuration
+type MultilingualConfig struct {
+	Languages      []string `json:"languages"`       // ISO 639-1 codes of the document languages, such as en or ko
+	TranslateQuery bool     `json:"translate_query"` // Translate queries in other languages for keyword retrieval
+}
+
 // ImageProcessingConfig represents image processing configuration
 type ImageProcessingConfig struct {
 	ModelID string `json:"model_id"` // Multimodal model ID
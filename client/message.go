@@ -117,3 +117,77 @@ func (c *Client) DeleteMessage(ctx context.Context, sessionID string, messageID
 
 	return parseResponse(resp, &response)
 }
+
+// SaveWebReferencesRequest selects web references of a message to save as knowledge
+type SaveWebReferencesRequest struct {
+	KnowledgeBaseID string   `json:"knowledge_base_id"` // Target knowledge base ID
+	URLs            []string `json:"urls"`              // URLs of the web references to save
+	TagID           string   `json:"tag_id,omitempty"`  // Tag assigned to the saved knowledge (optional)
+}
+
+// SavedWebReference is the outcome of saving one web reference
+type SavedWebReference struct {
+	URL         string `json:"url"`                    // URL of the web reference
+	KnowledgeID string `json:"knowledge_id,omitempty"` // ID of the created knowledge
+	FullContent bool   `json:"full_content"`           // Whether the full page was fetched
+	Error       string `json:"error,omitempty"`        // Reason the reference was not saved
+}
+
+// SaveWebReferences fetches web references of a message and saves them as knowledge in a knowledge base
+func (c *Client) SaveWebReferences(ctx context.Context,
+	sessionID string, messageID string, request *SaveWebReferencesRequest,
+) ([]SavedWebReference, error) {
+	path := fmt.Sprintf("/api/v1/messages/%s/%s/web-references", sessionID, messageID)
+	resp, err := c.doRequest(ctx, http.MethodPost, path, request, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Success bool                `json:"success"`
+		Data    []SavedWebReference `json:"data"`
+	}
+	if err := parseResponse(resp, &response); err != nil {
+		return nil, err
+	}
+	return response.Data, nil
+}
+
+// MessageFeedback is the rating of an assistant message
+type MessageFeedback struct {
+	ID        string    `json:"id"`
+	SessionID string    `json:"session_id"`
+	MessageID string    `json:"message_id"`
+	UserID    string    `json:"user_id"`
+	Rating    string    `json:"rating"`    // up or down
+	Comment   string    `json:"comment"`   // Comment explaining the rating
+	ChunkIDs  []string  `json:"chunk_ids"` // IDs of the chunks the answer cited
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// MessageFeedbackRequest rates an assistant message
+type MessageFeedbackRequest struct {
+	Rating  string `json:"rating"`            // up or down
+	Comment string `json:"comment,omitempty"` // Comment explaining the rating (optional)
+}
+
+// SubmitMessageFeedback rates an assistant message, replacing an earlier rating
+func (c *Client) SubmitMessageFeedback(ctx context.Context,
+	sessionID string, messageID string, request *MessageFeedbackRequest,
+) (*MessageFeedback, error) {
+	path := fmt.Sprintf("/api/v1/messages/%s/%s/feedback", sessionID, messageID)
+	resp, err := c.doRequest(ctx, http.MethodPut, path, request, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Success bool             `json:"success"`
+		Data    *MessageFeedback `json:"data"`
+	}
+	if err := parseResponse(resp, &response); err != nil {
+		return nil, err
+	}
+	return response.Data, nil
+}
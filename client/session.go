@@ -181,14 +181,15 @@ func (c *Client) GenerateTitle(ctx context.Context, sessionID string, request *G
 
 // KnowledgeQARequest knowledge Q&A request
 type KnowledgeQARequest struct {
-	Query            string   `json:"query"`              // Query text for knowledge base search
-	KnowledgeBaseIDs []string `json:"knowledge_base_ids"` // Selected knowledge base IDs for this request
-	KnowledgeIDs     []string `json:"knowledge_ids"`      // Selected knowledge IDs for this request
-	AgentEnabled     bool     `json:"agent_enabled"`      // Whether agent mode is enabled for this request
-	AgentID          string   `json:"agent_id"`           // Selected custom agent ID for this request
-	WebSearchEnabled bool     `json:"web_search_enabled"` // Whether web search is enabled for this request
-	SummaryModelID   string   `json:"summary_model_id"`   // Optional summary model ID (overrides session default)
-	DisableTitle     bool     `json:"disable_title"`      // Whether to disable auto title generation
+	Query            string   `json:"query"`                     // Query text for knowledge base search
+	KnowledgeBaseIDs []string `json:"knowledge_base_ids"`        // Selected knowledge base IDs for this request
+	KnowledgeIDs     []string `json:"knowledge_ids"`             // Selected knowledge IDs for this request
+	AgentEnabled     bool     `json:"agent_enabled"`             // Whether agent mode is enabled for this request
+	AgentID          string   `json:"agent_id"`                  // Selected custom agent ID for this request
+	WebSearchEnabled bool     `json:"web_search_enabled"`        // Whether web search is enabled for this request
+	SummaryModelID   string   `json:"summary_model_id"`          // Optional summary model ID (overrides session default)
+	DisableTitle     bool     `json:"disable_title"`             // Whether to disable auto title generation
+	TimeoutSeconds   int      `json:"timeout_seconds,omitempty"` // Wait limit for synchronous calls (capped by server config)
 }
 
 // LLMToolCall represents a function/tool call from the LLM
@@ -312,6 +313,54 @@ func (c *Client) KnowledgeQAStream(
 	return nil
 }
 
+// QAUsage usage statistics of a synchronous Q&A call
+type QAUsage struct {
+	TotalSteps int   `json:"total_steps"` // Number of agent steps (agent mode only)
+	DurationMs int64 `json:"duration_ms"` // Time spent generating the answer
+}
+
+// QAResponse synchronous Q&A result
+type QAResponse struct {
+	SessionID           string          `json:"session_id"`            // Session ID
+	AssistantMessageID  string          `json:"assistant_message_id"`  // Assistant message ID
+	RequestID           string          `json:"request_id"`            // Request ID
+	Answer              string          `json:"answer"`                // Complete answer
+	KnowledgeReferences []*SearchResult `json:"knowledge_references"`  // Knowledge references
+	AgentSteps          []AgentStep     `json:"agent_steps,omitempty"` // Agent execution steps (agent mode only)
+	Usage               QAUsage         `json:"usage"`                 // Usage statistics
+}
+
+// QAResponseEnvelope synchronous Q&A response
+type QAResponseEnvelope struct {
+	Success bool       `json:"success"`
+	Data    QAResponse `json:"data"`
+}
+
+// KnowledgeQA knowledge Q&A without streaming, blocks until the complete answer is available.
+// The server waits at most request.TimeoutSeconds (bounded by its own limit); make sure the
+// client timeout (see WithTimeout) is long enough for the answer to be generated.
+func (c *Client) KnowledgeQA(ctx context.Context, sessionID string, request *KnowledgeQARequest) (*QAResponse, error) {
+	if request == nil {
+		return nil, fmt.Errorf("knowledge QA request cannot be nil")
+	}
+	if strings.TrimSpace(request.Query) == "" {
+		return nil, fmt.Errorf("knowledge QA query cannot be empty")
+	}
+
+	path := fmt.Sprintf("/api/v1/knowledge-chat/%s/sync", sessionID)
+	resp, err := c.doRequest(ctx, http.MethodPost, path, request, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response QAResponseEnvelope
+	if err := parseResponse(resp, &response); err != nil {
+		return nil, err
+	}
+
+	return &response.Data, nil
+}
+
 // ContinueStream continues to receive an active stream for a session
 func (c *Client) ContinueStream(
 	ctx context.Context,
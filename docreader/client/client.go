@@ -1,7 +1,10 @@
 package client
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"strconv"
@@ -24,6 +27,10 @@ func getMaxMessageSize() int {
 	return 50 * 1024 * 1024 // default 50MB
 }
 
+// StreamPartSize is the size of the file parts uploaded by ReadFromFileInParts,
+// well below the gRPC message size limit
+const StreamPartSize = 1024 * 1024
+
 // Logger is the default logger used by the client
 var Logger = log.New(os.Stdout, "[DocReader] ", log.LstdFlags|log.Lmicroseconds)
 
@@ -95,6 +102,69 @@ func (c *Client) Log(level string, format string, args ...interface{}) {
 	Logger.Printf("%s: %s", level, fmt.Sprintf(format, args...))
 }
 
+// ReadFromFileInParts uploads the file read from r in parts, so large files are neither limited by the
+// gRPC message size nor held in memory, then calls onChunks with each batch of chunks docreader streams
+// back while parsing. The request carries the file metadata and is sent with the first part.
+func (c *Client) ReadFromFileInParts(ctx context.Context, r io.Reader,
+	req *proto.ReadFromFileStreamRequest, onChunks func([]*proto.Chunk) error,
+) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.ReadFromFileStream(ctx)
+	if err != nil {
+		return err
+	}
+
+	buf := make([]byte, StreamPartSize)
+	parts, size := 0, 0
+	for {
+		n, readErr := io.ReadFull(r, buf)
+		if n > 0 || parts == 0 {
+			req.FileContent = buf[:n]
+			if err := stream.Send(req); err != nil {
+				// The server ended the stream early, its status is returned by Recv
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				return err
+			}
+			// Metadata is only sent with the first part
+			req = &proto.ReadFromFileStreamRequest{}
+			parts++
+			size += n
+		}
+		if errors.Is(readErr, io.EOF) || errors.Is(readErr, io.ErrUnexpectedEOF) {
+			break
+		}
+		if readErr != nil {
+			return fmt.Errorf("read file: %w", readErr)
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		return err
+	}
+	c.Log("DEBUG", "Uploaded %d bytes in %d parts", size, parts)
+
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if resp.Error != "" {
+			return errors.New(resp.Error)
+		}
+		if len(resp.Chunks) > 0 {
+			if err := onChunks(resp.Chunks); err != nil {
+				return err
+			}
+		}
+	}
+}
+
 // GetImagesFromChunk extracts all image information from a Chunk
 func GetImagesFromChunk(chunk *proto.Chunk) []ImageInfo {
 	if chunk == nil || len(chunk.Images) == 0 {
@@ -1,13 +1,20 @@
 package client
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"io"
 	"log"
+	"net"
 	"os"
 	"testing"
 	"time"
 
 	"github.com/Tencent/WeKnora/docreader/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
 )
 
 func init() {
@@ -152,3 +159,78 @@ func truncateString(s string, maxLen int) string {
 	}
 	return s[:maxLen] + "..."
 }
+
+// streamServer echoes the uploaded file back as one chunk per part
+type streamServer struct {
+	proto.UnimplementedDocReaderServer
+	fileName string
+	content  []byte
+}
+
+func (s *streamServer) ReadFromFileStream(stream proto.DocReader_ReadFromFileStreamServer) error {
+	var parts []string
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if req.FileName != "" {
+			s.fileName = req.FileName
+		}
+		s.content = append(s.content, req.FileContent...)
+		parts = append(parts, string(req.FileContent))
+	}
+	for i, part := range parts {
+		chunk := &proto.Chunk{Content: part, Seq: int32(i)}
+		if err := stream.Send(&proto.ReadStreamResponse{Chunks: []*proto.Chunk{chunk}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestReadFromFileInParts(t *testing.T) {
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	fake := &streamServer{}
+	proto.RegisterDocReaderServer(server, fake)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	client := &Client{conn: conn, DocReaderClient: proto.NewDocReaderClient(conn)}
+	defer client.Close()
+
+	content := bytes.Repeat([]byte("x"), 2*StreamPartSize+10)
+	var received []*proto.Chunk
+	err = client.ReadFromFileInParts(context.Background(), bytes.NewReader(content),
+		&proto.ReadFromFileStreamRequest{FileName: "large.pdf", FileType: "pdf"},
+		func(chunks []*proto.Chunk) error {
+			received = append(received, chunks...)
+			return nil
+		},
+	)
+	if err != nil {
+		t.Fatalf("ReadFromFileInParts failed: %v", err)
+	}
+	if fake.fileName != "large.pdf" {
+		t.Errorf("file name = %q, want large.pdf", fake.fileName)
+	}
+	if !bytes.Equal(fake.content, content) {
+		t.Errorf("server received %d bytes, want %d", len(fake.content), len(content))
+	}
+	if len(received) != 3 {
+		t.Errorf("received %d chunks, want 3", len(received))
+	}
+}
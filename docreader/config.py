@@ -51,6 +51,10 @@ class DocReaderConfig:
     grpc_max_workers: int
     grpc_max_file_size_mb: int
     grpc_port: int
+    # Largest file accepted by a streaming read, in bytes, the same limit as file uploads
+    max_file_size: int
+    stream_batch_size: int
+    stream_pdf_pages: int
 
     # Image processing
     image_max_concurrent: int
@@ -111,6 +115,11 @@ def load_config() -> DocReaderConfig:
         * 1024
     )
     grpc_port = _get_int(["DOCREADER_GRPC_PORT", "PORT"], 50051)
+    max_file_size = _get_int(["MAX_FILE_SIZE_MB"], 50) * 1024 * 1024
+    # Number of chunks per response of a streaming read
+    stream_batch_size = _get_int(["DOCREADER_STREAM_BATCH_SIZE"], 20)
+    # Number of PDF pages parsed at a time by a streaming read
+    stream_pdf_pages = _get_int(["DOCREADER_STREAM_PDF_PAGES"], 10)
 
     # Image processing
     image_max_concurrent = _get_int(
@@ -186,6 +195,9 @@ def load_config() -> DocReaderConfig:
         grpc_max_workers=grpc_max_workers,
         grpc_max_file_size_mb=grpc_max_file_size_mb,
         grpc_port=grpc_port,
+        max_file_size=max_file_size,
+        stream_batch_size=stream_batch_size,
+        stream_pdf_pages=stream_pdf_pages,
         image_max_concurrent=image_max_concurrent,
         external_http_proxy=external_http_proxy,
         external_https_proxy=external_https_proxy,
@@ -227,6 +239,9 @@ def dump_config(mask_secrets: bool = True) -> Dict[str, Any]:
         "DOCREADER_GRPC_MAX_WORKERS": cfg.grpc_max_workers,
         "DOCREADER_GRPC_MAX_FILE_SIZE_MB": cfg.grpc_max_file_size_mb,
         "DOCREADER_GRPC_PORT": cfg.grpc_port,
+        "MAX_FILE_SIZE_MB": cfg.max_file_size // (1024 * 1024),
+        "DOCREADER_STREAM_BATCH_SIZE": cfg.stream_batch_size,
+        "DOCREADER_STREAM_PDF_PAGES": cfg.stream_pdf_pages,
         # Image processing
         "DOCREADER_IMAGE_MAX_CONCURRENT": cfg.image_max_concurrent,
         # Proxy
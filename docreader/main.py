@@ -2,6 +2,7 @@ import logging
 import os
 import re
 import sys
+import tempfile
 import traceback
 import uuid
 from concurrent import futures
@@ -23,6 +24,7 @@ from docreader.proto.docreader_pb2 import (
     ReadFromFileRequest,
     ReadFromURLRequest,
     ReadResponse,
+    ReadStreamResponse,
     StorageProvider,
 )
 from docreader.utils.request import init_logging_request_id, request_id_context
@@ -97,9 +99,12 @@ def create_chunking_config(read_config: ReadConfig):
         "app_id": sc.app_id,
         "path_prefix": sc.path_prefix,
     }
+    if read_config.preview:
+        # Previews upload nothing, images are returned inline as data URLs
+        storage_config = {"provider": "base64"}
     logger.info(
         f"Using Storage config: provider={storage_config.get('provider')}, "
-        f"bucket={storage_config['bucket_name']}"
+        f"bucket={storage_config.get('bucket_name')}"
     )
 
     # Extract VLM config
@@ -190,6 +195,67 @@ class DocReaderServicer(docreader_pb2_grpc.DocReaderServicer):
                 context.set_details(str(e))
                 return ReadResponse(error=str(e))
 
+    def ReadFromFileStream(self, request_iterator, context):
+        # Receive the file parts into a temporary file, the first message carries the metadata.
+        # Files over the upload size limit are rejected as soon as they exceed it.
+        first = None
+        size = 0
+        with tempfile.TemporaryFile() as spool:
+            for request in request_iterator:
+                if first is None:
+                    first = request
+                size += len(request.file_content)
+                if size > CONFIG.max_file_size:
+                    error_msg = (
+                        f"File exceeds the maximum size of "
+                        f"{CONFIG.max_file_size // (1024 * 1024)}MB"
+                    )
+                    logger.error(error_msg)
+                    context.set_code(grpc.StatusCode.RESOURCE_EXHAUSTED)
+                    context.set_details(error_msg)
+                    return
+                spool.write(request.file_content)
+            if first is None:
+                context.set_code(grpc.StatusCode.INVALID_ARGUMENT)
+                context.set_details("Empty request stream")
+                return
+            # Parsers take the whole file, it is read into memory once
+            spool.seek(0)
+            content = spool.read()
+
+        # Get or generate request ID
+        request_id = first.request_id or str(uuid.uuid4())
+
+        # Use request ID context
+        with request_id_context(request_id):
+            try:
+                # Get file type
+                file_type = first.file_type or os.path.splitext(first.file_name)[1][1:]
+                logger.info(
+                    f"ReadFromFileStream for file: {first.file_name}, type: {file_type}"
+                )
+                logger.info(f"File content size: {len(content)} bytes")
+
+                # Create chunking config
+                chunking_config = create_chunking_config(first.read_config)
+
+                # Parse file, streaming back the chunks batch by batch
+                logger.info("Starting file stream parsing process")
+                for chunks in self.parser.parse_file_stream(
+                    first.file_name, file_type, content, chunking_config
+                ):
+                    yield ReadStreamResponse(
+                        chunks=[self._convert_chunk_to_proto(chunk) for chunk in chunks]
+                    )
+
+            except Exception as e:
+                error_msg = f"Error reading file stream: {str(e)}"
+                logger.error(error_msg)
+                logger.info(f"Detailed traceback: {traceback.format_exc()}")
+                context.set_code(grpc.StatusCode.INTERNAL)
+                context.set_details(str(e))
+                yield ReadStreamResponse(error=str(e))
+
     def ReadFromURL(self, request: ReadFromURLRequest, context):
         # Get or generate request ID
         request_id = (
@@ -7,7 +7,7 @@ import os
 import re
 import time
 from abc import ABC, abstractmethod
-from typing import Dict, List, Optional, Tuple
+from typing import Dict, Iterator, List, Optional, Tuple
 from urllib.parse import urlparse
 
 import requests
@@ -194,6 +194,21 @@ class BaseParser(ABC):
             where image_map is a dict mapping image URLs to Image objects
         """
 
+    def parse_into_parts(self, content: bytes) -> Iterator[Document]:
+        """Parse document content part by part, in document order
+
+        Parsers that can read a document incrementally (e.g. PDF pages, Excel sheets)
+        override this so that streaming reads return chunks before the whole document
+        is parsed. By default the whole document is a single part.
+
+        Args:
+            content: Document content
+
+        Yields:
+            Parsed parts, chunk positions are relative to each part
+        """
+        yield self.parse_into_text(content)
+
     def perform_ocr(self, image: Image.Image):
         """Execute OCR recognition on the image
 
@@ -402,6 +417,66 @@ class BaseParser(ABC):
         if document.chunks:
             return document
 
+        chunks = self._split_document(document)
+
+        # If multimodal is enabled and file type is supported, process images
+        if self._should_process_images():
+            chunks = self.process_chunks_images(chunks, document.images)
+
+        document.chunks = chunks
+        return document
+
+    def parse_stream(
+        self, content: bytes, batch_size: int = 20
+    ) -> Iterator[List[Chunk]]:
+        """Parse document content, yielding the chunks in batches as they are processed
+
+        The document is parsed part by part, see parse_into_parts. The chunks of each
+        part are yielded once the part is parsed, so that the caller can consume them
+        while the rest of the document is still being parsed. Images of multimodal
+        documents are processed batch by batch before each batch is yielded.
+
+        Args:
+            content: Document content
+            batch_size: Number of chunks per batch
+
+        Yields:
+            Batches of processed chunks, in document order
+        """
+        logger.info(
+            f"Parsing document stream with {self.__class__.__name__}, "
+            f"bytes: {len(content)}"
+        )
+        seq, offset = 0, 0
+        for part in self.parse_into_parts(content):
+            logger.info(
+                f"Extracted {len(part.content)} characters from a part of {self.file_name}"
+            )
+            chunks = part.chunks or self._split_document(part)
+            process_images = not part.chunks and self._should_process_images()
+
+            # Chunk positions are relative to the part, number them across the document
+            remaining = self.max_chunks - seq
+            if len(chunks) > remaining:
+                logger.warning(f"Limiting chunks to maximum {self.max_chunks}")
+                chunks = chunks[:remaining]
+            for chunk in chunks:
+                chunk.seq = seq
+                chunk.start += offset
+                chunk.end += offset
+                seq += 1
+            offset += len(part.content)
+
+            for i in range(0, len(chunks), batch_size):
+                batch = chunks[i : i + batch_size]
+                if process_images:
+                    batch = self.process_chunks_images(batch, part.images)
+                yield batch
+            if seq >= self.max_chunks:
+                return
+
+    def _split_document(self, document: Document) -> List[Chunk]:
+        """Split the document text into chunks, limited to max_chunks"""
         splitter = TextSplitter(
             chunk_size=self.chunk_size,
             chunk_overlap=self.chunk_overlap,
@@ -417,46 +492,43 @@ class BaseParser(ABC):
                 f"Limiting chunks from {len(chunks)} to maximum {self.max_chunks}"
             )
             chunks = chunks[: self.max_chunks]
+        return chunks
 
-        # If multimodal is enabled and file type is supported, process images
-        if self.enable_multimodal:
-            # Get file extension and convert to lowercase
-            file_ext = (
-                os.path.splitext(self.file_name)[1].lower()
-                if self.file_name
-                else (self.file_type.lower() if self.file_type else "")
-            )
+    def _should_process_images(self) -> bool:
+        """Whether images in the chunks are processed: multimodal and a supported file type"""
+        if not self.enable_multimodal:
+            return False
 
-            # Define allowed file types for image processing
-            allowed_types = [
-                # Text files
-                ".pdf",
-                ".md",
-                ".markdown",
-                ".doc",
-                ".docx",
-                # Image files
-                ".jpg",
-                ".jpeg",
-                ".png",
-                ".gif",
-                ".bmp",
-                ".tiff",
-                ".webp",
-            ]
+        # Get file extension and convert to lowercase
+        file_ext = (
+            os.path.splitext(self.file_name)[1].lower()
+            if self.file_name
+            else (self.file_type.lower() if self.file_type else "")
+        )
 
-            if file_ext in allowed_types:
-                logger.info(
-                    f"Processing images in each chunk for file type: {file_ext}"
-                )
-                chunks = self.process_chunks_images(chunks, document.images)
-            else:
-                logger.info(
-                    f"Skipping image processing for unsupported file type: {file_ext}"
-                )
+        # Define allowed file types for image processing
+        allowed_types = [
+            # Text files
+            ".pdf",
+            ".md",
+            ".markdown",
+            ".doc",
+            ".docx",
+            # Image files
+            ".jpg",
+            ".jpeg",
+            ".png",
+            ".gif",
+            ".bmp",
+            ".tiff",
+            ".webp",
+        ]
 
-        document.chunks = chunks
-        return document
+        if file_ext in allowed_types:
+            logger.info(f"Processing images in each chunk for file type: {file_ext}")
+            return True
+        logger.info(f"Skipping image processing for unsupported file type: {file_ext}")
+        return False
 
     def _str_to_chunk(self, text: List[Tuple[int, int, str]]) -> List[Chunk]:
         """Convert string to Chunk object"""
@@ -7,7 +7,7 @@ sheets and handles various Excel formats using pandas.
 """
 import logging
 from io import BytesIO
-from typing import List
+from typing import Iterator, List
 
 import pandas as pd
 
@@ -58,13 +58,43 @@ class ExcelParser(BaseParser):
         """
         chunks: List[Chunk] = []
         text: List[str] = []
-        start, end = 0, 0
+        offset = 0
 
+        # Combine the sheets, shifting the chunk positions past the previous sheets
+        for sheet in self.parse_into_parts(content):
+            for chunk in sheet.chunks:
+                chunks.append(
+                    Chunk(
+                        content=chunk.content,
+                        seq=len(chunks),
+                        start=offset + chunk.start,
+                        end=offset + chunk.end,
+                    )
+                )
+            text.append(sheet.content)
+            offset += len(sheet.content)
+
+        # Combine all text and return as Document
+        return Document(content="".join(text), chunks=chunks)
+
+    def parse_into_parts(self, content: bytes) -> Iterator[Document]:
+        """Parse Excel file bytes sheet by sheet.
+        
+        Args:
+            content: Raw bytes of the Excel file
+            
+        Yields:
+            Document: One per sheet, with one chunk per row, positions relative to the sheet
+        """
         # Load Excel file from bytes into pandas ExcelFile object
         excel_file = pd.ExcelFile(BytesIO(content))
         
         # Process each sheet in the Excel file
         for excel_sheet_name in excel_file.sheet_names:
+            chunks: List[Chunk] = []
+            text: List[str] = []
+            start, end = 0, 0
+
             # Parse the sheet into a DataFrame
             df = excel_file.parse(sheet_name=excel_sheet_name)
             # Remove rows where all values are NaN (completely empty rows)
@@ -93,8 +123,7 @@ class ExcelParser(BaseParser):
                 )
                 start = end
 
-        # Combine all text and return as Document
-        return Document(content="".join(text), chunks=chunks)
+            yield Document(content="".join(text), chunks=chunks)
 
 
 if __name__ == "__main__":
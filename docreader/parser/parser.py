@@ -1,8 +1,8 @@
 import logging
-from typing import Dict, Type
+from typing import Dict, Iterator, List, Type
 
 from docreader.config import CONFIG
-from docreader.models.document import Document
+from docreader.models.document import Chunk, Document
 from docreader.models.read_config import ChunkingConfig
 from docreader.parser.base_parser import BaseParser
 from docreader.parser.csv_parser import CSVParser
@@ -97,12 +97,62 @@ class Parser:
             f"multimodal={config.enable_multimodal}"
         )
 
+        parser = self._create_file_parser(file_name, file_type, config)
+
+        logger.info(f"Starting to parse file content, size: {len(content)} bytes")
+        # Execute the parsing process
+        result = parser.parse(content)
+
+        # Validate parsing results and log warnings if needed
+        if not result.content:
+            logger.warning(f"Parser returned empty content for file: {file_name}")
+        elif not result.chunks:
+            logger.warning(f"Parser returned empty chunks for file: {file_name}")
+        elif result.chunks[0]:
+            # Log first chunk size for debugging
+            logger.info(f"First chunk content length: {len(result.chunks[0].content)}")
+        logger.info(f"Parsed file {file_name}, with {len(result.chunks)} chunks")
+        return result
+
+    def parse_file_stream(
+        self,
+        file_name: str,
+        file_type: str,
+        content: bytes,
+        config: ChunkingConfig,
+    ) -> Iterator[List[Chunk]]:
+        """
+        Parse file content, yielding chunks in batches as they are processed.
+
+        Args:
+            file_name: Name of the file being parsed
+            file_type: Type/extension of the file
+            content: Raw file content as bytes
+            config: Configuration for chunking process
+
+        Yields:
+            Batches of chunks in document order
+        """
+        logger.info(f"Parsing file stream: {file_name} with type: {file_type}")
+        parser = self._create_file_parser(file_name, file_type, config)
+
+        total = 0
+        for batch in parser.parse_stream(content, CONFIG.stream_batch_size):
+            total += len(batch)
+            logger.info(f"Parsed {total} chunks of file {file_name}")
+            yield batch
+        logger.info(f"Parsed file stream {file_name}, with {total} chunks")
+
+    def _create_file_parser(
+        self, file_name: str, file_type: str, config: ChunkingConfig
+    ) -> BaseParser:
+        """Create the parser instance for the file type with the chunking config"""
         # Get appropriate parser class for the file type
         cls = self.get_parser(file_type)
 
         # Create parser instance with configuration
         logger.info(f"Creating parser instance for {file_type} file")
-        parser = cls(
+        return cls(
             file_name=file_name,
             file_type=file_type,
             chunk_size=config.chunk_size,  # Size of each text chunk
@@ -115,21 +165,6 @@ class Parser:
             ocr_backend=CONFIG.ocr_backend,
         )
 
-        logger.info(f"Starting to parse file content, size: {len(content)} bytes")
-        # Execute the parsing process
-        result = parser.parse(content)
-
-        # Validate parsing results and log warnings if needed
-        if not result.content:
-            logger.warning(f"Parser returned empty content for file: {file_name}")
-        elif not result.chunks:
-            logger.warning(f"Parser returned empty chunks for file: {file_name}")
-        elif result.chunks[0]:
-            # Log first chunk size for debugging
-            logger.info(f"First chunk content length: {len(result.chunks[0].content)}")
-        logger.info(f"Parsed file {file_name}, with {len(result.chunks)} chunks")
-        return result
-
     def parse_url(self, url: str, title: str, config: ChunkingConfig) -> Document:
         """
         Parse content from a URL using the WebParser.
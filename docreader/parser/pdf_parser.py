@@ -1,7 +1,17 @@
+import io
+import logging
+from typing import Iterator
+
+from pypdf import PdfReader, PdfWriter
+
+from docreader.config import CONFIG
+from docreader.models.document import Document
 from docreader.parser.chain_parser import FirstParser
 from docreader.parser.markitdown_parser import MarkitdownParser
 from docreader.parser.mineru_parser import MinerUParser
 
+logger = logging.getLogger(__name__)
+
 
 class PDFParser(FirstParser):
     """PDF Parser using chain of responsibility pattern
@@ -14,3 +24,38 @@ class PDFParser(FirstParser):
     """
     # Parser classes to try in order (chain of responsibility pattern)
     _parser_cls = (MinerUParser, MarkitdownParser)
+
+    def parse_into_parts(self, content: bytes) -> Iterator[Document]:
+        """Parse the PDF a few pages at a time, each group of pages is a part.
+
+        Every group of CONFIG.stream_pdf_pages pages is written to a PDF of its own
+        and parsed by the parser chain. A PDF that can not be split is parsed whole.
+
+        Args:
+            content: Raw PDF bytes
+
+        Yields:
+            Document: Parsed group of pages, in page order
+        """
+        try:
+            reader = PdfReader(io.BytesIO(content))
+            page_count = len(reader.pages)
+        except Exception:
+            logger.exception("PDFParser: failed to read the pages, parsing the PDF whole")
+            yield self.parse_into_text(content)
+            return
+
+        pages_per_part = max(CONFIG.stream_pdf_pages, 1)
+        if page_count <= pages_per_part:
+            yield self.parse_into_text(content)
+            return
+
+        for start in range(0, page_count, pages_per_part):
+            end = min(start + pages_per_part, page_count)
+            writer = PdfWriter()
+            for i in range(start, end):
+                writer.add_page(reader.pages[i])
+            buffer = io.BytesIO()
+            writer.write(buffer)
+            logger.info(f"PDFParser: parsing pages {start + 1}-{end} of {page_count}")
+            yield self.parse_into_text(buffer.getvalue())
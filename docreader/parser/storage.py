@@ -397,9 +397,13 @@ class LocalStorage(Storage):
 
 
 class Base64Storage(Storage):
+    """Storage that uploads nothing and returns images inline as data URLs."""
+
     def upload_file(self, file_path: str) -> str:
         logger.info(f"Uploading file to base64 storage: {file_path}")
-        return file_path
+        with open(file_path, "rb") as f:
+            content = f.read()
+        return self.upload_bytes(content, os.path.splitext(file_path)[1] or ".png")
 
     def upload_bytes(self, content: bytes, file_ext: str = ".png") -> str:
         logger.info(f"Uploading file to base64 storage: {len(content)} bytes")
@@ -241,6 +241,7 @@ type ReadConfig struct {
 	EnableMultimodal bool                   `protobuf:"varint,4,opt,name=enable_multimodal,json=enableMultimodal,proto3" json:"enable_multimodal,omitempty"` // 多模态处理
 	StorageConfig    *StorageConfig         `protobuf:"bytes,5,opt,name=storage_config,json=storageConfig,proto3" json:"storage_config,omitempty"`           // 对象存储配置（通用）
 	VlmConfig        *VLMConfig             `protobuf:"bytes,6,opt,name=vlm_config,json=vlmConfig,proto3" json:"vlm_config,omitempty"`                       // VLM 配置
+	Preview          bool                   `protobuf:"varint,7,opt,name=preview,proto3" json:"preview,omitempty"`                                           // Preview: images are returned inline instead of uploaded to object storage
 	unknownFields    protoimpl.UnknownFields
 	sizeCache        protoimpl.SizeCache
 }
@@ -317,6 +318,13 @@ func (x *ReadConfig) GetVlmConfig() *VLMConfig {
 	return nil
 }
 
+func (x *ReadConfig) GetPreview() bool {
+	if x != nil {
+		return x.Preview
+	}
+	return false
+}
+
 // 从文件读取文档请求
 type ReadFromFileRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -394,6 +402,83 @@ func (x *ReadFromFileRequest) GetRequestId() string {
 	return ""
 }
 
+// 分片读取文件请求，首条消息携带文件元数据，每条消息可携带下一段文件内容
+type ReadFromFileStreamRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileContent   []byte                 `protobuf:"bytes,1,opt,name=file_content,json=fileContent,proto3" json:"file_content,omitempty"`
+	FileName      string                 `protobuf:"bytes,2,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	FileType      string                 `protobuf:"bytes,3,opt,name=file_type,json=fileType,proto3" json:"file_type,omitempty"`
+	ReadConfig    *ReadConfig            `protobuf:"bytes,4,opt,name=read_config,json=readConfig,proto3" json:"read_config,omitempty"`
+	RequestId     string                 `protobuf:"bytes,5,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadFromFileStreamRequest) Reset() {
+	*x = ReadFromFileStreamRequest{}
+	mi := &file_docreader_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadFromFileStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadFromFileStreamRequest) ProtoMessage() {}
+
+func (x *ReadFromFileStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_docreader_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadFromFileStreamRequest.ProtoReflect.Descriptor instead.
+func (*ReadFromFileStreamRequest) Descriptor() ([]byte, []int) {
+	return file_docreader_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ReadFromFileStreamRequest) GetFileContent() []byte {
+	if x != nil {
+		return x.FileContent
+	}
+	return nil
+}
+
+func (x *ReadFromFileStreamRequest) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+func (x *ReadFromFileStreamRequest) GetFileType() string {
+	if x != nil {
+		return x.FileType
+	}
+	return ""
+}
+
+func (x *ReadFromFileStreamRequest) GetReadConfig() *ReadConfig {
+	if x != nil {
+		return x.ReadConfig
+	}
+	return nil
+}
+
+func (x *ReadFromFileStreamRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
 // 从URL读取文档请求
 type ReadFromURLRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -407,7 +492,7 @@ type ReadFromURLRequest struct {
 
 func (x *ReadFromURLRequest) Reset() {
 	*x = ReadFromURLRequest{}
-	mi := &file_docreader_proto_msgTypes[4]
+	mi := &file_docreader_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -419,7 +504,7 @@ func (x *ReadFromURLRequest) String() string {
 func (*ReadFromURLRequest) ProtoMessage() {}
 
 func (x *ReadFromURLRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_docreader_proto_msgTypes[4]
+	mi := &file_docreader_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -432,7 +517,7 @@ func (x *ReadFromURLRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReadFromURLRequest.ProtoReflect.Descriptor instead.
 func (*ReadFromURLRequest) Descriptor() ([]byte, []int) {
-	return file_docreader_proto_rawDescGZIP(), []int{4}
+	return file_docreader_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *ReadFromURLRequest) GetUrl() string {
@@ -478,7 +563,7 @@ type Image struct {
 
 func (x *Image) Reset() {
 	*x = Image{}
-	mi := &file_docreader_proto_msgTypes[5]
+	mi := &file_docreader_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -490,7 +575,7 @@ func (x *Image) String() string {
 func (*Image) ProtoMessage() {}
 
 func (x *Image) ProtoReflect() protoreflect.Message {
-	mi := &file_docreader_proto_msgTypes[5]
+	mi := &file_docreader_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -503,7 +588,7 @@ func (x *Image) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Image.ProtoReflect.Descriptor instead.
 func (*Image) Descriptor() ([]byte, []int) {
-	return file_docreader_proto_rawDescGZIP(), []int{5}
+	return file_docreader_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *Image) GetUrl() string {
@@ -561,7 +646,7 @@ type Chunk struct {
 
 func (x *Chunk) Reset() {
 	*x = Chunk{}
-	mi := &file_docreader_proto_msgTypes[6]
+	mi := &file_docreader_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -573,7 +658,7 @@ func (x *Chunk) String() string {
 func (*Chunk) ProtoMessage() {}
 
 func (x *Chunk) ProtoReflect() protoreflect.Message {
-	mi := &file_docreader_proto_msgTypes[6]
+	mi := &file_docreader_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -586,7 +671,7 @@ func (x *Chunk) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Chunk.ProtoReflect.Descriptor instead.
 func (*Chunk) Descriptor() ([]byte, []int) {
-	return file_docreader_proto_rawDescGZIP(), []int{6}
+	return file_docreader_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *Chunk) GetContent() string {
@@ -635,7 +720,7 @@ type ReadResponse struct {
 
 func (x *ReadResponse) Reset() {
 	*x = ReadResponse{}
-	mi := &file_docreader_proto_msgTypes[7]
+	mi := &file_docreader_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -647,7 +732,7 @@ func (x *ReadResponse) String() string {
 func (*ReadResponse) ProtoMessage() {}
 
 func (x *ReadResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_docreader_proto_msgTypes[7]
+	mi := &file_docreader_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -660,7 +745,7 @@ func (x *ReadResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReadResponse.ProtoReflect.Descriptor instead.
 func (*ReadResponse) Descriptor() ([]byte, []int) {
-	return file_docreader_proto_rawDescGZIP(), []int{7}
+	return file_docreader_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *ReadResponse) GetChunks() []*Chunk {
@@ -677,6 +762,59 @@ func (x *ReadResponse) GetError() string {
 	return ""
 }
 
+// 分片读取文档响应，携带自上条响应以来解析出的分块
+type ReadStreamResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Chunks        []*Chunk               `protobuf:"bytes,1,rep,name=chunks,proto3" json:"chunks,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadStreamResponse) Reset() {
+	*x = ReadStreamResponse{}
+	mi := &file_docreader_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadStreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadStreamResponse) ProtoMessage() {}
+
+func (x *ReadStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_docreader_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadStreamResponse.ProtoReflect.Descriptor instead.
+func (*ReadStreamResponse) Descriptor() ([]byte, []int) {
+	return file_docreader_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ReadStreamResponse) GetChunks() []*Chunk {
+	if x != nil {
+		return x.Chunks
+	}
+	return nil
+}
+
+func (x *ReadStreamResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
 var File_docreader_proto protoreflect.FileDescriptor
 
 const file_docreader_proto_rawDesc = "" +
@@ -697,7 +835,7 @@ const file_docreader_proto_rawDesc = "" +
 	"model_name\x18\x01 \x01(\tR\tmodelName\x12\x19\n" +
 	"\bbase_url\x18\x02 \x01(\tR\abaseUrl\x12\x17\n" +
 	"\aapi_key\x18\x03 \x01(\tR\x06apiKey\x12%\n" +
-	"\x0einterface_type\x18\x04 \x01(\tR\rinterfaceType\"\x93\x02\n" +
+	"\x0einterface_type\x18\x04 \x01(\tR\rinterfaceType\"\xad\x02\n" +
 	"\n" +
 	"ReadConfig\x12\x1d\n" +
 	"\n" +
@@ -709,7 +847,8 @@ const file_docreader_proto_rawDesc = "" +
 	"\x11enable_multimodal\x18\x04 \x01(\bR\x10enableMultimodal\x12?\n" +
 	"\x0estorage_config\x18\x05 \x01(\v2\x18.docreader.StorageConfigR\rstorageConfig\x123\n" +
 	"\n" +
-	"vlm_config\x18\x06 \x01(\v2\x14.docreader.VLMConfigR\tvlmConfig\"\xc9\x01\n" +
+	"vlm_config\x18\x06 \x01(\v2\x14.docreader.VLMConfigR\tvlmConfig\x12\x18\n" +
+	"\apreview\x18\a \x01(\bR\apreview\"\xc9\x01\n" +
 	"\x13ReadFromFileRequest\x12!\n" +
 	"\ffile_content\x18\x01 \x01(\fR\vfileContent\x12\x1b\n" +
 	"\tfile_name\x18\x02 \x01(\tR\bfileName\x12\x1b\n" +
@@ -717,6 +856,14 @@ const file_docreader_proto_rawDesc = "" +
 	"\vread_config\x18\x04 \x01(\v2\x15.docreader.ReadConfigR\n" +
 	"readConfig\x12\x1d\n" +
 	"\n" +
+	"request_id\x18\x05 \x01(\tR\trequestId\"\xcf\x01\n" +
+	"\x19ReadFromFileStreamRequest\x12!\n" +
+	"\ffile_content\x18\x01 \x01(\fR\vfileContent\x12\x1b\n" +
+	"\tfile_name\x18\x02 \x01(\tR\bfileName\x12\x1b\n" +
+	"\tfile_type\x18\x03 \x01(\tR\bfileType\x126\n" +
+	"\vread_config\x18\x04 \x01(\v2\x15.docreader.ReadConfigR\n" +
+	"readConfig\x12\x1d\n" +
+	"\n" +
 	"request_id\x18\x05 \x01(\tR\trequestId\"\x93\x01\n" +
 	"\x12ReadFromURLRequest\x12\x10\n" +
 	"\x03url\x18\x01 \x01(\tR\x03url\x12\x14\n" +
@@ -740,14 +887,18 @@ const file_docreader_proto_rawDesc = "" +
 	"\x06images\x18\x05 \x03(\v2\x10.docreader.ImageR\x06images\"N\n" +
 	"\fReadResponse\x12(\n" +
 	"\x06chunks\x18\x01 \x03(\v2\x10.docreader.ChunkR\x06chunks\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"T\n" +
+	"\x12ReadStreamResponse\x12(\n" +
+	"\x06chunks\x18\x01 \x03(\v2\x10.docreader.ChunkR\x06chunks\x12\x14\n" +
 	"\x05error\x18\x02 \x01(\tR\x05error*G\n" +
 	"\x0fStorageProvider\x12 \n" +
 	"\x1cSTORAGE_PROVIDER_UNSPECIFIED\x10\x00\x12\a\n" +
 	"\x03COS\x10\x01\x12\t\n" +
-	"\x05MINIO\x10\x022\x9f\x01\n" +
+	"\x05MINIO\x10\x022\x80\x02\n" +
 	"\tDocReader\x12I\n" +
 	"\fReadFromFile\x12\x1e.docreader.ReadFromFileRequest\x1a\x17.docreader.ReadResponse\"\x00\x12G\n" +
-	"\vReadFromURL\x12\x1d.docreader.ReadFromURLRequest\x1a\x17.docreader.ReadResponse\"\x00B5Z3github.com/Tencent/WeKnora/internal/docreader/protob\x06proto3"
+	"\vReadFromURL\x12\x1d.docreader.ReadFromURLRequest\x1a\x17.docreader.ReadResponse\"\x00\x12_\n" +
+	"\x12ReadFromFileStream\x12$.docreader.ReadFromFileStreamRequest\x1a\x1d.docreader.ReadStreamResponse\"\x00(\x010\x01B5Z3github.com/Tencent/WeKnora/internal/docreader/protob\x06proto3"
 
 var (
 	file_docreader_proto_rawDescOnce sync.Once
@@ -762,35 +913,41 @@ func file_docreader_proto_rawDescGZIP() []byte {
 }
 
 var file_docreader_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_docreader_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_docreader_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
 var file_docreader_proto_goTypes = []any{
-	(StorageProvider)(0),        // 0: docreader.StorageProvider
-	(*StorageConfig)(nil),       // 1: docreader.StorageConfig
-	(*VLMConfig)(nil),           // 2: docreader.VLMConfig
-	(*ReadConfig)(nil),          // 3: docreader.ReadConfig
-	(*ReadFromFileRequest)(nil), // 4: docreader.ReadFromFileRequest
-	(*ReadFromURLRequest)(nil),  // 5: docreader.ReadFromURLRequest
-	(*Image)(nil),               // 6: docreader.Image
-	(*Chunk)(nil),               // 7: docreader.Chunk
-	(*ReadResponse)(nil),        // 8: docreader.ReadResponse
+	(StorageProvider)(0),              // 0: docreader.StorageProvider
+	(*StorageConfig)(nil),             // 1: docreader.StorageConfig
+	(*VLMConfig)(nil),                 // 2: docreader.VLMConfig
+	(*ReadConfig)(nil),                // 3: docreader.ReadConfig
+	(*ReadFromFileRequest)(nil),       // 4: docreader.ReadFromFileRequest
+	(*ReadFromFileStreamRequest)(nil), // 5: docreader.ReadFromFileStreamRequest
+	(*ReadFromURLRequest)(nil),        // 6: docreader.ReadFromURLRequest
+	(*Image)(nil),                     // 7: docreader.Image
+	(*Chunk)(nil),                     // 8: docreader.Chunk
+	(*ReadResponse)(nil),              // 9: docreader.ReadResponse
+	(*ReadStreamResponse)(nil),        // 10: docreader.ReadStreamResponse
 }
 var file_docreader_proto_depIdxs = []int32{
-	0, // 0: docreader.StorageConfig.provider:type_name -> docreader.StorageProvider
-	1, // 1: docreader.ReadConfig.storage_config:type_name -> docreader.StorageConfig
-	2, // 2: docreader.ReadConfig.vlm_config:type_name -> docreader.VLMConfig
-	3, // 3: docreader.ReadFromFileRequest.read_config:type_name -> docreader.ReadConfig
-	3, // 4: docreader.ReadFromURLRequest.read_config:type_name -> docreader.ReadConfig
-	6, // 5: docreader.Chunk.images:type_name -> docreader.Image
-	7, // 6: docreader.ReadResponse.chunks:type_name -> docreader.Chunk
-	4, // 7: docreader.DocReader.ReadFromFile:input_type -> docreader.ReadFromFileRequest
-	5, // 8: docreader.DocReader.ReadFromURL:input_type -> docreader.ReadFromURLRequest
-	8, // 9: docreader.DocReader.ReadFromFile:output_type -> docreader.ReadResponse
-	8, // 10: docreader.DocReader.ReadFromURL:output_type -> docreader.ReadResponse
-	9, // [9:11] is the sub-list for method output_type
-	7, // [7:9] is the sub-list for method input_type
-	7, // [7:7] is the sub-list for extension type_name
-	7, // [7:7] is the sub-list for extension extendee
-	0, // [0:7] is the sub-list for field type_name
+	0,  // 0: docreader.StorageConfig.provider:type_name -> docreader.StorageProvider
+	1,  // 1: docreader.ReadConfig.storage_config:type_name -> docreader.StorageConfig
+	2,  // 2: docreader.ReadConfig.vlm_config:type_name -> docreader.VLMConfig
+	3,  // 3: docreader.ReadFromFileRequest.read_config:type_name -> docreader.ReadConfig
+	3,  // 4: docreader.ReadFromFileStreamRequest.read_config:type_name -> docreader.ReadConfig
+	3,  // 5: docreader.ReadFromURLRequest.read_config:type_name -> docreader.ReadConfig
+	7,  // 6: docreader.Chunk.images:type_name -> docreader.Image
+	8,  // 7: docreader.ReadResponse.chunks:type_name -> docreader.Chunk
+	8,  // 8: docreader.ReadStreamResponse.chunks:type_name -> docreader.Chunk
+	4,  // 9: docreader.DocReader.ReadFromFile:input_type -> docreader.ReadFromFileRequest
+	6,  // 10: docreader.DocReader.ReadFromURL:input_type -> docreader.ReadFromURLRequest
+	5,  // 11: docreader.DocReader.ReadFromFileStream:input_type -> docreader.ReadFromFileStreamRequest
+	9,  // 12: docreader.DocReader.ReadFromFile:output_type -> docreader.ReadResponse
+	9,  // 13: docreader.DocReader.ReadFromURL:output_type -> docreader.ReadResponse
+	10, // 14: docreader.DocReader.ReadFromFileStream:output_type -> docreader.ReadStreamResponse
+	12, // [12:15] is the sub-list for method output_type
+	9,  // [9:12] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
 }
 
 func init() { file_docreader_proto_init() }
@@ -804,7 +961,7 @@ func file_docreader_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_docreader_proto_rawDesc), len(file_docreader_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   8,
+			NumMessages:   10,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
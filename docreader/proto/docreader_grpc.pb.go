@@ -19,8 +19,9 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	DocReader_ReadFromFile_FullMethodName = "/docreader.DocReader/ReadFromFile"
-	DocReader_ReadFromURL_FullMethodName  = "/docreader.DocReader/ReadFromURL"
+	DocReader_ReadFromFile_FullMethodName       = "/docreader.DocReader/ReadFromFile"
+	DocReader_ReadFromURL_FullMethodName        = "/docreader.DocReader/ReadFromURL"
+	DocReader_ReadFromFileStream_FullMethodName = "/docreader.DocReader/ReadFromFileStream"
 )
 
 // DocReaderClient is the client API for DocReader service.
@@ -33,6 +34,8 @@ type DocReaderClient interface {
 	ReadFromFile(ctx context.Context, in *ReadFromFileRequest, opts ...grpc.CallOption) (*ReadResponse, error)
 	// 从URL读取文档
 	ReadFromURL(ctx context.Context, in *ReadFromURLRequest, opts ...grpc.CallOption) (*ReadResponse, error)
+	// 分片上传文件，并在解析过程中流式返回分块
+	ReadFromFileStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ReadFromFileStreamRequest, ReadStreamResponse], error)
 }
 
 type docReaderClient struct {
@@ -63,6 +66,19 @@ func (c *docReaderClient) ReadFromURL(ctx context.Context, in *ReadFromURLReques
 	return out, nil
 }
 
+func (c *docReaderClient) ReadFromFileStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ReadFromFileStreamRequest, ReadStreamResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DocReader_ServiceDesc.Streams[0], DocReader_ReadFromFileStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ReadFromFileStreamRequest, ReadStreamResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DocReader_ReadFromFileStreamClient = grpc.BidiStreamingClient[ReadFromFileStreamRequest, ReadStreamResponse]
+
 // DocReaderServer is the server API for DocReader service.
 // All implementations must embed UnimplementedDocReaderServer
 // for forward compatibility.
@@ -73,6 +89,8 @@ type DocReaderServer interface {
 	ReadFromFile(context.Context, *ReadFromFileRequest) (*ReadResponse, error)
 	// 从URL读取文档
 	ReadFromURL(context.Context, *ReadFromURLRequest) (*ReadResponse, error)
+	// 分片上传文件，并在解析过程中流式返回分块
+	ReadFromFileStream(grpc.BidiStreamingServer[ReadFromFileStreamRequest, ReadStreamResponse]) error
 	mustEmbedUnimplementedDocReaderServer()
 }
 
@@ -89,6 +107,9 @@ func (UnimplementedDocReaderServer) ReadFromFile(context.Context, *ReadFromFileR
 func (UnimplementedDocReaderServer) ReadFromURL(context.Context, *ReadFromURLRequest) (*ReadResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ReadFromURL not implemented")
 }
+func (UnimplementedDocReaderServer) ReadFromFileStream(grpc.BidiStreamingServer[ReadFromFileStreamRequest, ReadStreamResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ReadFromFileStream not implemented")
+}
 func (UnimplementedDocReaderServer) mustEmbedUnimplementedDocReaderServer() {}
 func (UnimplementedDocReaderServer) testEmbeddedByValue()                   {}
 
@@ -146,6 +167,13 @@ func _DocReader_ReadFromURL_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DocReader_ReadFromFileStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DocReaderServer).ReadFromFileStream(&grpc.GenericServerStream[ReadFromFileStreamRequest, ReadStreamResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DocReader_ReadFromFileStreamServer = grpc.BidiStreamingServer[ReadFromFileStreamRequest, ReadStreamResponse]
+
 // DocReader_ServiceDesc is the grpc.ServiceDesc for DocReader service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -162,6 +190,13 @@ var DocReader_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _DocReader_ReadFromURL_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ReadFromFileStream",
+			Handler:       _DocReader_ReadFromFileStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
 	Metadata: "docreader.proto",
 }
@@ -45,6 +45,11 @@ class DocReaderStub(object):
                 request_serializer=docreader__pb2.ReadFromURLRequest.SerializeToString,
                 response_deserializer=docreader__pb2.ReadResponse.FromString,
                 _registered_method=True)
+        self.ReadFromFileStream = channel.stream_stream(
+                '/docreader.DocReader/ReadFromFileStream',
+                request_serializer=docreader__pb2.ReadFromFileStreamRequest.SerializeToString,
+                response_deserializer=docreader__pb2.ReadStreamResponse.FromString,
+                _registered_method=True)
 
 
 class DocReaderServicer(object):
@@ -65,6 +70,13 @@ class DocReaderServicer(object):
         context.set_details('Method not implemented!')
         raise NotImplementedError('Method not implemented!')
 
+    def ReadFromFileStream(self, request_iterator, context):
+        """分片上传文件，并在解析过程中流式返回分块
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
 
 def add_DocReaderServicer_to_server(servicer, server):
     rpc_method_handlers = {
@@ -78,6 +90,11 @@ def add_DocReaderServicer_to_server(servicer, server):
                     request_deserializer=docreader__pb2.ReadFromURLRequest.FromString,
                     response_serializer=docreader__pb2.ReadResponse.SerializeToString,
             ),
+            'ReadFromFileStream': grpc.stream_stream_rpc_method_handler(
+                    servicer.ReadFromFileStream,
+                    request_deserializer=docreader__pb2.ReadFromFileStreamRequest.FromString,
+                    response_serializer=docreader__pb2.ReadStreamResponse.SerializeToString,
+            ),
     }
     generic_handler = grpc.method_handlers_generic_handler(
             'docreader.DocReader', rpc_method_handlers)
@@ -143,3 +160,30 @@ class DocReader(object):
             timeout,
             metadata,
             _registered_method=True)
+
+    @staticmethod
+    def ReadFromFileStream(request_iterator,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.stream_stream(
+            request_iterator,
+            target,
+            '/docreader.DocReader/ReadFromFileStream',
+            docreader__pb2.ReadFromFileStreamRequest.SerializeToString,
+            docreader__pb2.ReadStreamResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)
@@ -18,7 +18,7 @@ export function createKnowledgeBase(data: {
   };
   cos_config?: any;
   extract_config?: any;
-  faq_config?: { index_mode: string; question_index_mode?: string };
+  faq_config?: { index_mode: string; question_index_mode?: string; require_review?: boolean };
 }) {
   return post(`/api/v1/knowledge-bases`, data);
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/Tencent/WeKnora/internal/event"
 	"github.com/Tencent/WeKnora/internal/logger"
 	"github.com/Tencent/WeKnora/internal/models/chat"
+	"github.com/Tencent/WeKnora/internal/searchutil"
 	"github.com/Tencent/WeKnora/internal/types"
 	"github.com/Tencent/WeKnora/internal/types/interfaces"
 	"github.com/google/uuid"
@@ -102,12 +103,7 @@ func (e *AgentEngine) Execute(
 	}
 
 	// Build system prompt using progressive RAG prompt
-	systemPrompt := BuildSystemPrompt(
-		e.knowledgeBasesInfo,
-		e.config.WebSearchEnabled,
-		e.selectedDocs,
-		e.systemPromptTemplate,
-	)
+	systemPrompt := e.buildSystemPrompt()
 	logger.Debugf(ctx, "[Agent] SystemPrompt Length: %d characters", len(systemPrompt))
 	logger.Debugf(ctx, "[Agent] SystemPrompt (stream)\n----\n%s\n----", systemPrompt)
 
@@ -493,6 +489,17 @@ func (e *AgentEngine) executeLoop(
 	return state, nil
 }
 
+// buildSystemPrompt builds the system prompt of the agent, asking for the answer in the language of the query
+func (e *AgentEngine) buildSystemPrompt() string {
+	systemPrompt := BuildSystemPrompt(
+		e.knowledgeBasesInfo,
+		e.config.WebSearchEnabled,
+		e.selectedDocs,
+		e.systemPromptTemplate,
+	)
+	return searchutil.WithLanguage(systemPrompt, e.config.QueryLanguage, searchutil.AnswerLanguageInstruction)
+}
+
 // buildToolsForLLM builds the tools list for LLM function calling
 func (e *AgentEngine) buildToolsForLLM() []chat.Tool {
 	functionDefs := e.toolRegistry.GetFunctionDefinitions()
@@ -779,12 +786,7 @@ func (e *AgentEngine) streamFinalAnswerToEventBus(
 	})
 
 	// Build messages with all context
-	systemPrompt := BuildSystemPrompt(
-		e.knowledgeBasesInfo,
-		e.config.WebSearchEnabled,
-		e.selectedDocs,
-		e.systemPromptTemplate,
-	)
+	systemPrompt := e.buildSystemPrompt()
 
 	messages := []chat.Message{
 		{Role: "system", Content: systemPrompt},
@@ -134,7 +134,7 @@ func (t *GetDocumentInfoTool) Execute(ctx context.Context, args json.RawMessage)
 				ListPagedChunksByKnowledgeID(ctx, tenantID, id, &types.Pagination{
 					Page:     1,
 					PageSize: 1000,
-				}, []types.ChunkType{"text"}, "", "", "", "", "")
+				}, []types.ChunkType{"text"}, "", "", "", "", "", "")
 			if err != nil {
 				mu.Lock()
 				results[id] = &docInfo{
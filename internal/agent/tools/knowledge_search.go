@@ -72,6 +72,8 @@ Avoid:
 - queries (required): 1–5 semantic questions or conceptual statements.
   These should reflect the meaning or topic you want embeddings to capture.
 - knowledge_base_ids (optional): limit the search scope.
+- metadata_filters (optional): restrict results by custom document metadata, only when the user asks for it
+  (e.g. a department, year or product line). All filters must match.
 
 ## Output
 Returns chunks ranked by semantic similarity, reranked when applicable.  
@@ -96,6 +98,24 @@ Results represent conceptual relevance, not literal keyword overlap.`,
       },
       "minItems": 0,
       "maxItems": 10
+    },
+    "metadata_filters": {
+      "type": "array",
+      "description": "Optional: filters on custom document metadata, combined with AND",
+      "items": {
+        "type": "object",
+        "properties": {
+          "key": {"type": "string", "description": "Metadata key"},
+          "op": {"type": "string", "enum": ["eq", "in", "range", "exists"]},
+          "value": {"description": "Value for eq (string, number or boolean)"},
+          "values": {"type": "array", "description": "Values for in"},
+          "gt": {"description": "Lower bound (exclusive) for range"},
+          "gte": {"description": "Lower bound (inclusive) for range"},
+          "lt": {"description": "Upper bound (exclusive) for range"},
+          "lte": {"description": "Upper bound (inclusive) for range"}
+        },
+        "required": ["key", "op"]
+      }
     }
   },
   "required": ["queries"]
@@ -104,8 +124,9 @@ Results represent conceptual relevance, not literal keyword overlap.`,
 
 // KnowledgeSearchInput defines the input parameters for knowledge search tool
 type KnowledgeSearchInput struct {
-	Queries          []string `json:"queries"`
-	KnowledgeBaseIDs []string `json:"knowledge_base_ids,omitempty"`
+	Queries          []string              `json:"queries"`
+	KnowledgeBaseIDs []string              `json:"knowledge_base_ids,omitempty"`
+	MetadataFilters  types.MetadataFilters `json:"metadata_filters,omitempty"`
 }
 
 // searchResultWithMeta wraps search result with metadata about which query matched it
@@ -165,6 +186,15 @@ func (t *KnowledgeSearchTool) Execute(ctx context.Context, args json.RawMessage)
 		}, err
 	}
 
+	metadataFilters, err := input.MetadataFilters.Normalize()
+	if err != nil {
+		logger.Errorf(ctx, "[Tool][KnowledgeSearch] Invalid metadata filters: %v", err)
+		return &types.ToolResult{
+			Success: false,
+			Error:   fmt.Sprintf("Invalid metadata_filters: %v", err),
+		}, err
+	}
+
 	// Log input arguments
 	argsJSON, _ := json.MarshalIndent(input, "", "  ")
 	logger.Debugf(ctx, "[Tool][KnowledgeSearch] Input args:\n%s", string(argsJSON))
@@ -281,12 +311,12 @@ func (t *KnowledgeSearchTool) Execute(ctx context.Context, args json.RawMessage)
 	kbTypeMap := t.getKnowledgeBaseTypes(ctx, kbIDs)
 
 	allResults := t.concurrentSearchByTargets(ctx, queries, searchTargets,
-		topK, vectorThreshold, keywordThreshold, kbTypeMap)
+		topK, vectorThreshold, keywordThreshold, metadataFilters, kbTypeMap)
 	logger.Infof(ctx, "[Tool][KnowledgeSearch] Concurrent search completed: %d raw results", len(allResults))
 
-	// Note: HybridSearch now uses RRF (Reciprocal Rank Fusion) which produces normalized scores
+	// Note: HybridSearch fuses scores with the knowledge base's fusion strategy (RRF by default)
 	// RRF scores are in range [0, ~0.033] (max when rank=1 on both sides: 2/(60+1))
-	// Threshold filtering is already done inside HybridSearch before RRF, so we skip it here
+	// Threshold filtering is already done inside HybridSearch before fusion, so we skip it here
 
 	// Deduplicate before reranking to reduce processing overhead
 	deduplicatedBeforeRerank := t.deduplicateResults(allResults)
@@ -367,8 +397,8 @@ func (t *KnowledgeSearchTool) Execute(ctx context.Context, args json.RawMessage)
 		}
 	}
 
-	// Note: minScore filter is skipped because HybridSearch now uses RRF scores
-	// RRF scores are in range [0, ~0.033], not [0, 1], so old thresholds don't apply
+	// Note: minScore filter is skipped because HybridSearch returns fused scores
+	// e.g. RRF scores are in range [0, ~0.033], not [0, 1], so old thresholds don't apply
 	// Threshold filtering is already done inside HybridSearch before RRF fusion
 
 	// Final deduplication after rerank (in case rerank changed scores/order but duplicates remain)
@@ -438,6 +468,7 @@ func (t *KnowledgeSearchTool) concurrentSearchByTargets(
 	searchTargets types.SearchTargets,
 	topK int,
 	vectorThreshold, keywordThreshold float64,
+	metadataFilters types.MetadataFilters,
 	kbTypeMap map[string]string,
 ) []*searchResultWithMeta {
 	var wg sync.WaitGroup
@@ -457,6 +488,7 @@ func (t *KnowledgeSearchTool) concurrentSearchByTargets(
 					MatchCount:       topK,
 					VectorThreshold:  vectorThreshold,
 					KeywordThreshold: keywordThreshold,
+					MetadataFilters:  metadataFilters,
 				}
 
 				// If target has specific knowledge IDs, add them to search params
@@ -1056,7 +1088,7 @@ func (t *KnowledgeSearchTool) formatOutput(
 				_, total, err := t.chunkService.GetRepository().ListPagedChunksByKnowledgeID(ctx,
 					tenantID, result.KnowledgeID,
 					&types.Pagination{Page: 1, PageSize: 1},
-					[]types.ChunkType{types.ChunkTypeText}, "", "", "", "", "",
+					[]types.ChunkType{types.ChunkTypeText}, "", "", "", "", "", "",
 				)
 				if err != nil {
 					logger.Warnf(
@@ -258,16 +258,29 @@ func (t *WebFetchTool) validateAndResolve(p webFetchParams) (*validatedParams, e
 	if p.Prompt == "" {
 		return nil, fmt.Errorf("prompt is required")
 	}
-	if !strings.HasPrefix(p.URL, "http://") && !strings.HasPrefix(p.URL, "https://") {
+	vp, err := t.resolveURL(p.URL)
+	if err != nil {
+		return nil, err
+	}
+	vp.Prompt = p.Prompt
+	return vp, nil
+}
+
+// resolveURL validates a URL against SSRF and pins its host to a single public IP
+func (t *WebFetchTool) resolveURL(rawURL string) (*validatedParams, error) {
+	if rawURL == "" {
+		return nil, fmt.Errorf("url is required")
+	}
+	if !strings.HasPrefix(rawURL, "http://") && !strings.HasPrefix(rawURL, "https://") {
 		return nil, fmt.Errorf("invalid URL format")
 	}
 
 	// SSRF protection: validate URL is safe (scheme, hostname, and that resolved IPs are not restricted)
-	if safe, reason := utils.IsSSRFSafeURL(p.URL); !safe {
+	if safe, reason := utils.IsSSRFSafeURL(rawURL); !safe {
 		return nil, fmt.Errorf("URL rejected for security reasons: %s", reason)
 	}
 
-	u, err := url.Parse(p.URL)
+	u, err := url.Parse(rawURL)
 	if err != nil {
 		return nil, fmt.Errorf("invalid URL: %w", err)
 	}
@@ -298,8 +311,7 @@ func (t *WebFetchTool) validateAndResolve(p webFetchParams) (*validatedParams, e
 	}
 
 	return &validatedParams{
-		URL:      p.URL,
-		Prompt:   p.Prompt,
+		URL:      rawURL,
 		Host:     hostname,
 		Port:     port,
 		PinnedIP: pinnedIP,
@@ -349,6 +361,25 @@ func (t *WebFetchTool) executeFetch(
 	return output, resultData, summaryErr
 }
 
+// FetchPage fetches a web page with the same SSRF protection as the tool and returns its content
+// as Markdown text without LLM processing, so callers can store the full page
+func (t *WebFetchTool) FetchPage(ctx context.Context, rawURL string) (string, error) {
+	vp, err := t.resolveURL(t.normalizeGitHubURL(strings.TrimSpace(rawURL)))
+	if err != nil {
+		return "", err
+	}
+	htmlContent, method, err := t.fetchHTMLContent(ctx, vp)
+	if err != nil {
+		return "", err
+	}
+	textContent := t.convertHTMLToText(htmlContent)
+	if runes := []rune(textContent); len(runes) > webFetchMaxChars {
+		textContent = string(runes[:webFetchMaxChars])
+	}
+	logger.Infof(ctx, "[Tool][WebFetch] Fetched page url=%s method=%s length=%d", vp.URL, method, len(textContent))
+	return textContent, nil
+}
+
 // normalizeGitHubURL normalizes a GitHub URL
 func (t *WebFetchTool) normalizeGitHubURL(source string) string {
 	if strings.Contains(source, "github.com") && strings.Contains(source, "/blob/") {
@@ -40,6 +40,7 @@ var webSearchTool = BaseTool{
 
 **Parameters**:
 - query (required): Search query string
+- bypass_cache (optional): Skip cached results of recent identical searches, only for breaking news or fast-changing information
 
 **Returns**: Web search results with title, URL, snippet, and content (up to %d results)
 
@@ -70,7 +71,8 @@ var webSearchTool = BaseTool{
 
 // WebSearchInput defines the input parameters for web search tool
 type WebSearchInput struct {
-	Query string `json:"query" jsonschema:"Search query string"`
+	Query       string `json:"query"                  jsonschema:"Search query string"`
+	BypassCache bool   `json:"bypass_cache,omitempty" jsonschema:"Skip cached results of recent identical searches, for breaking news or fast-changing information"`
 }
 
 // WebSearchTool performs web searches and returns results
@@ -82,6 +84,8 @@ type WebSearchTool struct {
 	webSearchStateService interfaces.WebSearchStateService
 	sessionID             string
 	maxResults            int
+	allowlist             []string
+	trustWeights          map[string]float64
 }
 
 // NewWebSearchTool creates a new web search tool
@@ -92,6 +96,8 @@ func NewWebSearchTool(
 	webSearchStateService interfaces.WebSearchStateService,
 	sessionID string,
 	maxResults int,
+	allowlist []string,
+	trustWeights map[string]float64,
 ) *WebSearchTool {
 	tool := webSearchTool
 	tool.description = fmt.Sprintf(tool.description, maxResults, maxResults)
@@ -104,6 +110,8 @@ func NewWebSearchTool(
 		webSearchStateService: webSearchStateService,
 		sessionID:             sessionID,
 		maxResults:            maxResults,
+		allowlist:             allowlist,
+		trustWeights:          trustWeights,
 	}
 }
 
@@ -158,9 +166,10 @@ func (t *WebSearchTool) Execute(ctx context.Context, args json.RawMessage) (*typ
 		}, fmt.Errorf("web search is not configured for tenant %d", tenantID)
 	}
 
-	// Create a copy of web search config with maxResults from agent config
-	searchConfig := *tenant.WebSearchConfig
+	// Create a copy of web search config with maxResults, allowlist and trust weights from agent config
+	searchConfig := tenant.WebSearchConfig.WithOverrides(t.allowlist, t.trustWeights)
 	searchConfig.MaxResults = t.maxResults
+	searchConfig.BypassCache = searchConfig.BypassCache || input.BypassCache
 
 	// Perform web search
 	logger.Infof(
@@ -169,7 +178,7 @@ func (t *WebSearchTool) Execute(ctx context.Context, args json.RawMessage) (*typ
 		searchConfig.Provider,
 		searchConfig.MaxResults,
 	)
-	webResults, err := t.webSearchService.Search(ctx, &searchConfig, query)
+	webResults, err := t.webSearchService.Search(ctx, searchConfig, query)
 	if err != nil {
 		logger.Errorf(ctx, "[Tool][WebSearch] Web search failed: %v", err)
 		return &types.ToolResult{
@@ -255,6 +264,9 @@ func (t *WebSearchTool) Execute(ctx context.Context, args json.RawMessage) (*typ
 		if result.PublishedAt != nil {
 			resultData["published_at"] = result.PublishedAt.Format(time.RFC3339)
 		}
+		if result.CachedAt != nil {
+			resultData["cached_at"] = result.CachedAt.Format(time.RFC3339)
+		}
 		formattedResults = append(formattedResults, resultData)
 	}
 
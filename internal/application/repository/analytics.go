@@ -0,0 +1,306 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// analyticsRepository stores chunk hit counters, query logs and message feedback
+type analyticsRepository struct {
+	db *gorm.DB
+}
+
+// NewAnalyticsRepository creates a new analytics repository
+func NewAnalyticsRepository(db *gorm.DB) interfaces.AnalyticsRepository {
+	return &analyticsRepository{db: db}
+}
+
+// IncrementHitStats adds the hit counts to the daily counters of the chunks
+func (r *analyticsRepository) IncrementHitStats(ctx context.Context, stats []*types.KnowledgeHitStat) error {
+	if len(stats) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "chunk_id"}, {Name: "stat_date"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"hit_count": gorm.Expr("knowledge_hit_stats.hit_count + EXCLUDED.hit_count"),
+		}),
+	}).Create(stats).Error
+}
+
+// CreateQueryLogs stores query logs
+func (r *analyticsRepository) CreateQueryLogs(ctx context.Context, logs []*types.QueryLog) error {
+	if len(logs) == 0 {
+		return nil
+	}
+	for _, log := range logs {
+		if log.ID == "" {
+			log.ID = uuid.New().String()
+		}
+	}
+	return r.db.WithContext(ctx).Create(logs).Error
+}
+
+// SaveMessageFeedback stores the feedback of a message, replacing an earlier one, with the chunks it cites
+func (r *analyticsRepository) SaveMessageFeedback(ctx context.Context,
+	feedback *types.MessageFeedback, chunks []*types.MessageFeedbackChunk,
+) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing types.MessageFeedback
+		err := tx.Where("tenant_id = ? AND message_id = ?", feedback.TenantID, feedback.MessageID).
+			First(&existing).Error
+		switch {
+		case err == nil:
+			feedback.ID = existing.ID
+			feedback.CreatedAt = existing.CreatedAt
+			if err := tx.Where("feedback_id = ?", existing.ID).
+				Delete(&types.MessageFeedbackChunk{}).Error; err != nil {
+				return err
+			}
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			feedback.ID = uuid.New().String()
+			feedback.CreatedAt = time.Now()
+		default:
+			return err
+		}
+		feedback.UpdatedAt = time.Now()
+		if err := tx.Save(feedback).Error; err != nil {
+			return err
+		}
+		if len(chunks) == 0 {
+			return nil
+		}
+		for _, chunk := range chunks {
+			chunk.FeedbackID = feedback.ID
+			chunk.Rating = feedback.Rating
+			chunk.UpdatedAt = feedback.UpdatedAt
+		}
+		return tx.Create(chunks).Error
+	})
+}
+
+// GetKnowledgeBaseAnalytics counts the hits, logged queries and feedback of a knowledge base
+func (r *analyticsRepository) GetKnowledgeBaseAnalytics(ctx context.Context,
+	tenantID uint64, kbID string, dateRange types.AnalyticsDateRange,
+) (*types.KnowledgeBaseAnalytics, error) {
+	db := r.db.WithContext(ctx)
+	result := &types.KnowledgeBaseAnalytics{KnowledgeBaseID: kbID, DateRange: dateRange}
+
+	var hits struct {
+		TotalHits int64
+		HitChunks int64
+	}
+	if err := db.Model(&types.KnowledgeHitStat{}).
+		Select("COALESCE(SUM(hit_count), 0) AS total_hits, COUNT(DISTINCT chunk_id) AS hit_chunks").
+		Where("tenant_id = ? AND knowledge_base_id = ? AND stat_date BETWEEN ? AND ?",
+			tenantID, kbID, dateRange.StartDate, dateRange.EndDate).
+		Scan(&hits).Error; err != nil {
+		return nil, err
+	}
+	result.TotalHits, result.HitChunks = hits.TotalHits, hits.HitChunks
+
+	var queries []struct {
+		Type  types.QueryLogType
+		Count int64
+	}
+	if err := db.Model(&types.QueryLog{}).
+		Select("type, COUNT(*) AS count").
+		Where("tenant_id = ? AND knowledge_base_id = ? AND created_at >= ? AND created_at < ?",
+			tenantID, kbID, dateRange.StartDate, dateRange.EndExclusive()).
+		Group("type").Scan(&queries).Error; err != nil {
+		return nil, err
+	}
+	for _, q := range queries {
+		switch q.Type {
+		case types.QueryLogTypeZeroResult:
+			result.ZeroResultQueries = q.Count
+		case types.QueryLogTypeLowConfidence:
+			result.LowConfidenceQueries = q.Count
+		}
+	}
+
+	var ratings []struct {
+		Rating types.MessageFeedbackRating
+		Count  int64
+	}
+	if err := db.Model(&types.MessageFeedbackChunk{}).
+		Select("rating, COUNT(DISTINCT feedback_id) AS count").
+		Where("tenant_id = ? AND knowledge_base_id = ? AND updated_at >= ? AND updated_at < ?",
+			tenantID, kbID, dateRange.StartDate, dateRange.EndExclusive()).
+		Group("rating").Scan(&ratings).Error; err != nil {
+		return nil, err
+	}
+	for _, rating := range ratings {
+		switch rating.Rating {
+		case types.MessageFeedbackRatingUp:
+			result.UpFeedback = rating.Count
+		case types.MessageFeedbackRatingDown:
+			result.DownFeedback = rating.Count
+		}
+	}
+	return result, nil
+}
+
+// ListTopHitChunks lists the most hit chunks of a knowledge base with their hit counts
+func (r *analyticsRepository) ListTopHitChunks(ctx context.Context,
+	tenantID uint64, kbID string, dateRange types.AnalyticsDateRange, limit int,
+) ([]*types.AnalyticsEntry, error) {
+	var entries []*types.AnalyticsEntry
+	if err := r.db.WithContext(ctx).Model(&types.KnowledgeHitStat{}).
+		Select("chunk_id, knowledge_id, chunk_type, SUM(hit_count) AS hit_count, MAX(stat_date) AS last_hit_date").
+		Where("tenant_id = ? AND knowledge_base_id = ? AND stat_date BETWEEN ? AND ?",
+			tenantID, kbID, dateRange.StartDate, dateRange.EndDate).
+		Group("chunk_id, knowledge_id, chunk_type").
+		Order("hit_count DESC, chunk_id").
+		Limit(limit).
+		Scan(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// CountFeedbackByChunk counts the ratings of answers citing each chunk
+func (r *analyticsRepository) CountFeedbackByChunk(ctx context.Context,
+	tenantID uint64, chunkIDs []string, dateRange types.AnalyticsDateRange,
+) (map[string]interfaces.FeedbackCount, error) {
+	counts := make(map[string]interfaces.FeedbackCount, len(chunkIDs))
+	if len(chunkIDs) == 0 {
+		return counts, nil
+	}
+	var rows []struct {
+		ChunkID string
+		Rating  types.MessageFeedbackRating
+		Count   int64
+	}
+	if err := r.db.WithContext(ctx).Model(&types.MessageFeedbackChunk{}).
+		Select("chunk_id, rating, COUNT(*) AS count").
+		Where("tenant_id = ? AND chunk_id IN ? AND updated_at >= ? AND updated_at < ?",
+			tenantID, chunkIDs, dateRange.StartDate, dateRange.EndExclusive()).
+		Group("chunk_id, rating").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		count := counts[row.ChunkID]
+		switch row.Rating {
+		case types.MessageFeedbackRatingUp:
+			count.Up = row.Count
+		case types.MessageFeedbackRatingDown:
+			count.Down = row.Count
+		}
+		counts[row.ChunkID] = count
+	}
+	return counts, nil
+}
+
+// ListNeverHitFAQEntries lists the enabled, published FAQ entries of a knowledge base without hits
+func (r *analyticsRepository) ListNeverHitFAQEntries(ctx context.Context,
+	tenantID uint64, kbID string, dateRange types.AnalyticsDateRange, page *types.Pagination,
+) ([]*types.AnalyticsEntry, int64, error) {
+	query := r.db.WithContext(ctx).Table("chunks AS c").
+		Where("c.tenant_id = ? AND c.knowledge_base_id = ? AND c.chunk_type = ? AND c.is_enabled = ? AND c.deleted_at IS NULL",
+			tenantID, kbID, types.ChunkTypeFAQ, true).
+		Where("COALESCE(c.metadata->>'review_status', '') IN ?",
+			[]string{"", string(types.FAQReviewStatusPublished)}).
+		Where("NOT EXISTS (SELECT 1 FROM knowledge_hit_stats h WHERE h.chunk_id = c.id AND h.stat_date BETWEEN ? AND ?)",
+			dateRange.StartDate, dateRange.EndDate)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var entries []*types.AnalyticsEntry
+	if err := query.
+		Select("c.id AS chunk_id, c.seq_id AS entry_id, c.chunk_type, c.knowledge_id, " +
+			"c.metadata->>'standard_question' AS title").
+		Order("c.seq_id").
+		Offset(page.Offset()).Limit(page.Limit()).
+		Scan(&entries).Error; err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+// ListNeverHitKnowledge lists the parsed documents of a knowledge base none of whose chunks were hit
+func (r *analyticsRepository) ListNeverHitKnowledge(ctx context.Context,
+	tenantID uint64, kbID string, dateRange types.AnalyticsDateRange, page *types.Pagination,
+) ([]*types.AnalyticsEntry, int64, error) {
+	query := r.db.WithContext(ctx).Table("knowledges AS k").
+		Where("k.tenant_id = ? AND k.knowledge_base_id = ? AND k.parse_status = ? AND k.deleted_at IS NULL",
+			tenantID, kbID, types.ParseStatusCompleted).
+		Where("NOT EXISTS (SELECT 1 FROM knowledge_hit_stats h WHERE h.knowledge_id = k.id AND h.stat_date BETWEEN ? AND ?)",
+			dateRange.StartDate, dateRange.EndDate)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var entries []*types.AnalyticsEntry
+	if err := query.
+		Select("k.id AS knowledge_id, k.title AS knowledge_title, k.title AS title").
+		Order("k.created_at").
+		Offset(page.Offset()).Limit(page.Limit()).
+		Scan(&entries).Error; err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+// ListQueryLogs lists the query logs of a knowledge base, newest first, all types when logType is empty
+func (r *analyticsRepository) ListQueryLogs(ctx context.Context,
+	tenantID uint64, kbID string, logType types.QueryLogType,
+	dateRange types.AnalyticsDateRange, page *types.Pagination,
+) ([]*types.QueryLog, int64, error) {
+	query := r.db.WithContext(ctx).Model(&types.QueryLog{}).
+		Where("tenant_id = ? AND knowledge_base_id = ? AND created_at >= ? AND created_at < ?",
+			tenantID, kbID, dateRange.StartDate, dateRange.EndExclusive())
+	if logType != "" {
+		query = query.Where("type = ?", logType)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var logs []*types.QueryLog
+	if err := query.Order("created_at DESC").
+		Offset(page.Offset()).Limit(page.Limit()).
+		Find(&logs).Error; err != nil {
+		return nil, 0, err
+	}
+	return logs, total, nil
+}
+
+// ListMessageFeedback lists the feedback on answers citing chunks of a knowledge base, newest first,
+// all ratings when rating is empty
+func (r *analyticsRepository) ListMessageFeedback(ctx context.Context,
+	tenantID uint64, kbID string, rating types.MessageFeedbackRating,
+	dateRange types.AnalyticsDateRange, page *types.Pagination,
+) ([]*types.MessageFeedback, int64, error) {
+	query := r.db.WithContext(ctx).Model(&types.MessageFeedback{}).
+		Where("tenant_id = ? AND updated_at >= ? AND updated_at < ?",
+			tenantID, dateRange.StartDate, dateRange.EndExclusive()).
+		Where("id IN (SELECT feedback_id FROM message_feedback_chunks WHERE tenant_id = ? AND knowledge_base_id = ?)",
+			tenantID, kbID)
+	if rating != "" {
+		query = query.Where("rating = ?", rating)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var feedback []*types.MessageFeedback
+	if err := query.Order("updated_at DESC").
+		Offset(page.Offset()).Limit(page.Limit()).
+		Find(&feedback).Error; err != nil {
+		return nil, 0, err
+	}
+	return feedback, total, nil
+}
@@ -111,6 +111,7 @@ func (r *chunkRepository) ListPagedChunksByKnowledgeID(
 	searchField string,
 	sortOrder string,
 	knowledgeType string,
+	reviewStatus string,
 ) ([]*types.Chunk, int64, error) {
 	var chunks []*types.Chunk
 	var total int64
@@ -118,10 +119,25 @@ func (r *chunkRepository) ListPagedChunksByKnowledgeID(
 
 	baseFilter := func(db *gorm.DB) *gorm.DB {
 		db = db.Where("tenant_id = ? AND knowledge_id = ? AND chunk_type IN (?) AND status in (?)",
-			tenantID, knowledgeID, chunkType, []int{int(types.ChunkStatusIndexed), int(types.ChunkStatusDefault)})
+			tenantID, knowledgeID, chunkType, []int{
+				int(types.ChunkStatusIndexed), int(types.ChunkStatusDefault), int(types.ChunkStatusIndexFailed),
+				int(types.ChunkStatusUnpublished),
+			})
 		if tagID != "" {
 			db = db.Where("tag_id = ?", tagID)
 		}
+		if reviewStatus != "" {
+			// Chunks without a review status, including FAQ entries created before reviews, are published
+			statuses := []string{reviewStatus}
+			if types.FAQReviewStatus(reviewStatus) == types.FAQReviewStatusPublished {
+				statuses = append(statuses, "")
+			}
+			if db.Dialector.Name() == "postgres" {
+				db = db.Where("COALESCE(metadata->>'review_status', '') IN (?)", statuses)
+			} else {
+				db = db.Where("COALESCE(metadata->>'$.review_status', '') IN (?)", statuses)
+			}
+		}
 		if keyword != "" {
 			like := "%" + keyword + "%"
 
@@ -479,7 +495,7 @@ func (r *chunkRepository) ListAllFAQChunksByKnowledgeID(
 }
 
 // ListAllFAQChunksWithMetadataByKnowledgeBaseID lists all FAQ chunks for a knowledge base ID
-// Returns ID and Metadata fields for duplicate question checking
+// Returns ID, SeqID and Metadata fields for duplicate question and conflict checking
 // Uses batch query to handle large datasets
 func (r *chunkRepository) ListAllFAQChunksWithMetadataByKnowledgeBaseID(
 	ctx context.Context,
@@ -493,9 +509,10 @@ func (r *chunkRepository) ListAllFAQChunksWithMetadataByKnowledgeBaseID(
 	for {
 		var batchChunks []*types.Chunk
 		if err := r.db.WithContext(ctx).
-			Select("id, metadata").
-			Where("tenant_id = ? AND knowledge_base_id = ? AND chunk_type = ? AND status = ?",
-				tenantID, kbID, types.ChunkTypeFAQ, types.ChunkStatusIndexed).
+			Select("id, seq_id, metadata").
+			Where("tenant_id = ? AND knowledge_base_id = ? AND chunk_type = ? AND status IN (?)",
+				tenantID, kbID, types.ChunkTypeFAQ,
+				[]int{int(types.ChunkStatusIndexed), int(types.ChunkStatusUnpublished)}).
 			Offset(offset).
 			Limit(batchSize).
 			Find(&batchChunks).Error; err != nil {
@@ -535,8 +552,9 @@ func (r *chunkRepository) ListAllFAQChunksForExport(
 		var batchChunks []*types.Chunk
 		if err := r.db.WithContext(ctx).
 			Select("id, metadata, tag_id, is_enabled, flags").
-			Where("tenant_id = ? AND knowledge_id = ? AND chunk_type = ? AND status = ?",
-				tenantID, knowledgeID, types.ChunkTypeFAQ, types.ChunkStatusIndexed).
+			Where("tenant_id = ? AND knowledge_id = ? AND chunk_type = ? AND status IN (?)",
+				tenantID, knowledgeID, types.ChunkTypeFAQ,
+				[]int{int(types.ChunkStatusIndexed), int(types.ChunkStatusUnpublished)}).
 			Order("created_at ASC").
 			Offset(offset).
 			Limit(batchSize).
@@ -767,3 +785,13 @@ func (r *chunkRepository) FAQChunkDiff(
 
 	return chunksToAdd, chunksToDelete, nil
 }
+
+// ShiftChunkIndex shifts the chunk_index of the text and child chunks of a knowledge from fromIndex on by delta
+func (r *chunkRepository) ShiftChunkIndex(ctx context.Context,
+	tenantID uint64, knowledgeID string, fromIndex int, delta int,
+) error {
+	return r.db.WithContext(ctx).Model(&types.Chunk{}).
+		Where("tenant_id = ? AND knowledge_id = ? AND chunk_index >= ? AND chunk_type IN ?",
+			tenantID, knowledgeID, fromIndex, []types.ChunkType{types.ChunkTypeText, types.ChunkTypeChild}).
+		Update("chunk_index", gorm.Expr("chunk_index + ?", delta)).Error
+}
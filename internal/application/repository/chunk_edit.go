@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Tencent/WeKnora/internal/common"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// chunkEditRepository stores the history of manual chunk edits
+type chunkEditRepository struct {
+	db *gorm.DB
+}
+
+// NewChunkEditRepository creates a new chunk edit repository
+func NewChunkEditRepository(db *gorm.DB) interfaces.ChunkEditRepository {
+	return &chunkEditRepository{db: db}
+}
+
+// CreateChunkEdit records a manual chunk edit
+func (r *chunkEditRepository) CreateChunkEdit(ctx context.Context, edit *types.ChunkEdit) error {
+	if edit.ID == "" {
+		edit.ID = uuid.New().String()
+	}
+	return r.db.WithContext(ctx).Create(edit).Error
+}
+
+// ApplyChunkEdit saves the chunk changes of a manual edit and records the edit in one transaction.
+// edit may be nil for changes that are not kept in the history.
+func (r *chunkEditRepository) ApplyChunkEdit(ctx context.Context,
+	tenantID uint64, knowledgeID string, edit *types.ChunkEdit, changes *types.ChunkEditChanges,
+) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(changes.DeletedIDs) > 0 {
+			if err := tx.Where("tenant_id = ? AND id IN ?", tenantID, changes.DeletedIDs).
+				Delete(&types.Chunk{}).Error; err != nil {
+				return err
+			}
+		}
+		if changes.ShiftBy != 0 {
+			if err := tx.Model(&types.Chunk{}).
+				Where("tenant_id = ? AND knowledge_id = ? AND chunk_index >= ? AND chunk_type IN ?",
+					tenantID, knowledgeID, changes.ShiftFrom, []types.ChunkType{types.ChunkTypeText, types.ChunkTypeChild}).
+				Update("chunk_index", gorm.Expr("chunk_index + ?", changes.ShiftBy)).Error; err != nil {
+				return err
+			}
+		}
+		for _, chunk := range changes.Updated {
+			chunk.Content = common.CleanInvalidUTF8(chunk.Content)
+			if err := tx.Save(chunk).Error; err != nil {
+				return err
+			}
+		}
+		if len(changes.Created) > 0 {
+			for _, chunk := range changes.Created {
+				chunk.Content = common.CleanInvalidUTF8(chunk.Content)
+			}
+			if err := tx.Select("*").CreateInBatches(changes.Created, 100).Error; err != nil {
+				return err
+			}
+		}
+		for chunkID, preChunkID := range changes.Relinks {
+			if err := tx.Model(&types.Chunk{}).Where("tenant_id = ? AND id = ?", tenantID, chunkID).
+				Update("pre_chunk_id", preChunkID).Error; err != nil {
+				return err
+			}
+		}
+		if edit == nil {
+			return nil
+		}
+		if edit.ID == "" {
+			edit.ID = uuid.New().String()
+		}
+		return tx.Create(edit).Error
+	})
+}
+
+// ListChunkEdits lists the edits of a knowledge in the order they were made
+func (r *chunkEditRepository) ListChunkEdits(ctx context.Context,
+	tenantID uint64, knowledgeID string,
+) ([]*types.ChunkEdit, error) {
+	var edits []*types.ChunkEdit
+	if err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND knowledge_id = ?", tenantID, knowledgeID).
+		Order("created_at ASC").
+		Find(&edits).Error; err != nil {
+		return nil, err
+	}
+	return edits, nil
+}
+
+// ListChunkEditsByChunkID lists the edits that produced, split or merged a chunk, oldest first
+func (r *chunkEditRepository) ListChunkEditsByChunkID(ctx context.Context,
+	tenantID uint64, chunkID string,
+) ([]*types.ChunkEdit, error) {
+	var edits []*types.ChunkEdit
+	if err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND (chunk_id = ? OR related_chunk_id = ?)", tenantID, chunkID, chunkID).
+		Order("created_at ASC").
+		Find(&edits).Error; err != nil {
+		return nil, err
+	}
+	return edits, nil
+}
+
+// UpdateChunkEdits saves the chunks and orphaned flags of edits after they were replayed
+func (r *chunkEditRepository) UpdateChunkEdits(ctx context.Context, edits []*types.ChunkEdit) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, edit := range edits {
+			if err := tx.Model(&types.ChunkEdit{}).Where("id = ?", edit.ID).Updates(map[string]interface{}{
+				"chunk_id":         edit.ChunkID,
+				"related_chunk_id": edit.RelatedChunkID,
+				"orphaned":         edit.Orphaned,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteChunkEdits deletes the edit history of the given knowledge
+func (r *chunkEditRepository) DeleteChunkEdits(ctx context.Context, tenantID uint64, knowledgeIDs []string) error {
+	if len(knowledgeIDs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Where("tenant_id = ? AND knowledge_id IN ?", tenantID, knowledgeIDs).
+		Delete(&types.ChunkEdit{}).Error
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// faqCandidateRepository stores the FAQ candidates mined from chat history
+type faqCandidateRepository struct {
+	db *gorm.DB
+}
+
+// NewFAQCandidateRepository creates a new FAQ candidate repository
+func NewFAQCandidateRepository(db *gorm.DB) interfaces.FAQCandidateRepository {
+	return &faqCandidateRepository{db: db}
+}
+
+// CreateFAQCandidates stores new candidates
+func (r *faqCandidateRepository) CreateFAQCandidates(ctx context.Context, candidates []*types.FAQCandidate) error {
+	if len(candidates) == 0 {
+		return nil
+	}
+	for _, candidate := range candidates {
+		if candidate.ID == "" {
+			candidate.ID = uuid.New().String()
+		}
+	}
+	return r.db.WithContext(ctx).Create(candidates).Error
+}
+
+// GetFAQCandidate gets a candidate of a knowledge base
+func (r *faqCandidateRepository) GetFAQCandidate(ctx context.Context,
+	tenantID uint64, kbID string, id string,
+) (*types.FAQCandidate, error) {
+	var candidate types.FAQCandidate
+	if err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND knowledge_base_id = ? AND id = ?", tenantID, kbID, id).
+		First(&candidate).Error; err != nil {
+		return nil, err
+	}
+	return &candidate, nil
+}
+
+// ListFAQCandidates lists the candidates of a knowledge base, newest first, all statuses when status is empty
+func (r *faqCandidateRepository) ListFAQCandidates(ctx context.Context,
+	tenantID uint64, kbID string, status types.FAQCandidateStatus, page *types.Pagination,
+) ([]*types.FAQCandidate, int64, error) {
+	query := r.db.WithContext(ctx).Model(&types.FAQCandidate{}).
+		Where("tenant_id = ? AND knowledge_base_id = ?", tenantID, kbID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var candidates []*types.FAQCandidate
+	if err := query.Order("question_count DESC, created_at DESC").
+		Offset(page.Offset()).Limit(page.Limit()).
+		Find(&candidates).Error; err != nil {
+		return nil, 0, err
+	}
+	return candidates, total, nil
+}
+
+// ListFAQCandidateQuestions lists the standard and similar questions of every candidate of a knowledge base
+func (r *faqCandidateRepository) ListFAQCandidateQuestions(ctx context.Context,
+	tenantID uint64, kbID string,
+) ([]string, error) {
+	var candidates []*types.FAQCandidate
+	if err := r.db.WithContext(ctx).
+		Select("standard_question", "similar_questions").
+		Where("tenant_id = ? AND knowledge_base_id = ?", tenantID, kbID).
+		Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+	questions := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		questions = append(questions, candidate.StandardQuestion)
+		questions = append(questions, candidate.SimilarQuestions...)
+	}
+	return questions, nil
+}
+
+// UpdateFAQCandidateReview records the review of a candidate
+func (r *faqCandidateRepository) UpdateFAQCandidateReview(ctx context.Context, candidate *types.FAQCandidate) error {
+	return r.db.WithContext(ctx).Model(&types.FAQCandidate{}).
+		Where("tenant_id = ? AND id = ?", candidate.TenantID, candidate.ID).
+		Updates(map[string]interface{}{
+			"status":       candidate.Status,
+			"faq_entry_id": candidate.FAQEntryID,
+			"reviewed_at":  candidate.ReviewedAt,
+			"updated_at":   time.Now(),
+		}).Error
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// faqEntryCommentRepository stores the review comments of FAQ entries
+type faqEntryCommentRepository struct {
+	db *gorm.DB
+}
+
+// NewFAQEntryCommentRepository creates a new FAQ entry comment repository
+func NewFAQEntryCommentRepository(db *gorm.DB) interfaces.FAQEntryCommentRepository {
+	return &faqEntryCommentRepository{db: db}
+}
+
+// CreateFAQEntryComment stores a comment
+func (r *faqEntryCommentRepository) CreateFAQEntryComment(ctx context.Context, comment *types.FAQEntryComment) error {
+	if comment.ID == "" {
+		comment.ID = uuid.New().String()
+	}
+	return r.db.WithContext(ctx).Create(comment).Error
+}
+
+// ListFAQEntryComments lists the comments of a FAQ entry, oldest first
+func (r *faqEntryCommentRepository) ListFAQEntryComments(ctx context.Context,
+	tenantID uint64, chunkID string,
+) ([]*types.FAQEntryComment, error) {
+	var comments []*types.FAQEntryComment
+	if err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND chunk_id = ?", tenantID, chunkID).
+		Order("created_at ASC").
+		Find(&comments).Error; err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
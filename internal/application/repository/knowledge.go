@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"strings"
+	"time"
 
 	"github.com/Tencent/WeKnora/internal/types"
 	"github.com/Tencent/WeKnora/internal/types/interfaces"
@@ -414,3 +415,43 @@ func (r *knowledgeRepository) ListIDsByTagID(
 		Pluck("id", &ids).Error
 	return ids, err
 }
+
+// ListKnowledgeToExpire lists enabled, processed knowledge of all tenants whose validity period
+// does not include now
+func (r *knowledgeRepository) ListKnowledgeToExpire(ctx context.Context, now time.Time) ([]*types.Knowledge, error) {
+	var knowledgeList []*types.Knowledge
+	err := r.db.WithContext(ctx).
+		Where("enable_status = ? AND parse_status = ?", types.KnowledgeEnableStatusEnabled, types.ParseStatusCompleted).
+		Where("((valid_until IS NOT NULL AND valid_until <= ?) OR (valid_from IS NOT NULL AND valid_from > ?))", now, now).
+		Find(&knowledgeList).Error
+	return knowledgeList, err
+}
+
+// ListKnowledgeToRestore lists expired knowledge of all tenants whose validity period includes now
+func (r *knowledgeRepository) ListKnowledgeToRestore(ctx context.Context, now time.Time) ([]*types.Knowledge, error) {
+	var knowledgeList []*types.Knowledge
+	err := r.db.WithContext(ctx).
+		Where("enable_status = ?", types.KnowledgeEnableStatusExpired).
+		Where("(valid_until IS NULL OR valid_until > ?)", now).
+		Where("(valid_from IS NULL OR valid_from <= ?)", now).
+		Find(&knowledgeList).Error
+	return knowledgeList, err
+}
+
+// ListExpiringKnowledge lists knowledge of a knowledge base that expires or is due for review
+// before the given time, soonest first
+func (r *knowledgeRepository) ListExpiringKnowledge(
+	ctx context.Context,
+	tenantID uint64,
+	kbID string,
+	before time.Time,
+) ([]*types.Knowledge, error) {
+	var knowledgeList []*types.Knowledge
+	err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND knowledge_base_id = ?", tenantID, kbID).
+		Where("((valid_until IS NOT NULL AND valid_until <= ?) OR (review_by IS NOT NULL AND review_by <= ?))",
+			before, before).
+		Order("LEAST(COALESCE(valid_until, 'infinity'), COALESCE(review_by, 'infinity')) ASC").
+		Find(&knowledgeList).Error
+	return knowledgeList, err
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// knowledgeStageRepository stores knowledge processing stages
+type knowledgeStageRepository struct {
+	db *gorm.DB
+}
+
+// NewKnowledgeStageRepository creates a new knowledge stage repository
+func NewKnowledgeStageRepository(db *gorm.DB) interfaces.KnowledgeStageRepository {
+	return &knowledgeStageRepository{db: db}
+}
+
+// stageConflict identifies the record of a stage
+var stageConflict = []clause.Column{{Name: "knowledge_id"}, {Name: "stage"}}
+
+// StartStage marks a stage running and counts an attempt, creating its record if needed
+func (r *knowledgeStageRepository) StartStage(ctx context.Context, stage *types.KnowledgeStage) error {
+	now := time.Now()
+	stage.ID = uuid.New().String()
+	stage.Status = types.StageStatusRunning
+	stage.Attempts = 1
+	stage.StartedAt = &now
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: stageConflict,
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"status":        types.StageStatusRunning,
+			"attempts":      gorm.Expr("knowledge_processing_stages.attempts + 1"),
+			"error_message": "",
+			"total_items":   stage.TotalItems,
+			"done_items":    0,
+			"started_at":    now,
+			"finished_at":   nil,
+			"duration_ms":   0,
+			"updated_at":    now,
+		}),
+	}).Create(stage).Error
+}
+
+// FinishStage records the outcome of the running attempt of a stage
+func (r *knowledgeStageRepository) FinishStage(ctx context.Context,
+	knowledgeID string, stage types.ProcessingStage, status string, errorMessage string,
+) error {
+	now := time.Now()
+	return r.db.WithContext(ctx).Model(&types.KnowledgeStage{}).
+		Where("knowledge_id = ? AND stage = ?", knowledgeID, stage).
+		Updates(map[string]interface{}{
+			"status":        status,
+			"error_message": errorMessage,
+			"finished_at":   now,
+			"duration_ms":   gorm.Expr("COALESCE((EXTRACT(EPOCH FROM (?::timestamptz - started_at)) * 1000)::bigint, 0)", now),
+			"updated_at":    now,
+		}).Error
+}
+
+// MarkStagePending records a stage queued to run, without counting an attempt
+func (r *knowledgeStageRepository) MarkStagePending(ctx context.Context, stage *types.KnowledgeStage) error {
+	now := time.Now()
+	stage.ID = uuid.New().String()
+	stage.Status = types.StageStatusPending
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: stageConflict,
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"status":        types.StageStatusPending,
+			"error_message": "",
+			"updated_at":    now,
+		}),
+	}).Create(stage).Error
+}
+
+// CompleteStageItem counts a finished work item of a running stage, completing the stage with its last item
+func (r *knowledgeStageRepository) CompleteStageItem(ctx context.Context,
+	knowledgeID string, stage types.ProcessingStage,
+) error {
+	now := time.Now()
+	if err := r.db.WithContext(ctx).Model(&types.KnowledgeStage{}).
+		Where("knowledge_id = ? AND stage = ? AND status = ?", knowledgeID, stage, types.StageStatusRunning).
+		Updates(map[string]interface{}{
+			"done_items": gorm.Expr("done_items + 1"),
+			"updated_at": now,
+		}).Error; err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Model(&types.KnowledgeStage{}).
+		Where("knowledge_id = ? AND stage = ? AND status = ? AND done_items >= total_items",
+			knowledgeID, stage, types.StageStatusRunning).
+		Updates(map[string]interface{}{
+			"status":      types.StageStatusCompleted,
+			"finished_at": now,
+			"duration_ms": gorm.Expr("COALESCE((EXTRACT(EPOCH FROM (?::timestamptz - started_at)) * 1000)::bigint, 0)", now),
+		}).Error
+}
+
+// ListStages lists the stages of a knowledge
+func (r *knowledgeStageRepository) ListStages(ctx context.Context,
+	tenantID uint64, knowledgeID string,
+) ([]*types.KnowledgeStage, error) {
+	var stages []*types.KnowledgeStage
+	if err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND knowledge_id = ?", tenantID, knowledgeID).
+		Order("created_at ASC").
+		Find(&stages).Error; err != nil {
+		return nil, err
+	}
+	return stages, nil
+}
+
+// ListFailedStages lists the failed stages of the knowledge in a knowledge base
+func (r *knowledgeStageRepository) ListFailedStages(ctx context.Context,
+	tenantID uint64, kbID string,
+) ([]*types.KnowledgeStage, error) {
+	var stages []*types.KnowledgeStage
+	if err := r.db.WithContext(ctx).
+		Where("tenant_id = ? AND knowledge_base_id = ? AND status = ?", tenantID, kbID, types.StageStatusFailed).
+		Order("updated_at DESC").
+		Find(&stages).Error; err != nil {
+		return nil, err
+	}
+	return stages, nil
+}
+
+// DeleteStages deletes the stages of the given knowledge
+func (r *knowledgeStageRepository) DeleteStages(ctx context.Context, tenantID uint64, knowledgeIDs []string) error {
+	if len(knowledgeIDs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Where("tenant_id = ? AND knowledge_id IN ?", tenantID, knowledgeIDs).
+		Delete(&types.KnowledgeStage{}).Error
+}
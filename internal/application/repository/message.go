@@ -151,3 +151,25 @@ func (r *messageRepository) GetMessageByRequestID(
 
 	return &message, nil
 }
+
+// ListMessagesForFAQMining lists the questions of a tenant asked since a time, each with the
+// fallback flag and references of its answer. Questions and answers are paired by request ID.
+func (r *messageRepository) ListMessagesForFAQMining(
+	ctx context.Context, tenantID uint64, since time.Time, limit int,
+) ([]*types.FAQMiningMessage, error) {
+	var messages []*types.FAQMiningMessage
+	if err := r.db.WithContext(ctx).Table("messages AS a").
+		Select("u.id AS message_id, u.content AS question, a.is_fallback, "+
+			"a.knowledge_references, u.created_at").
+		Joins("JOIN messages AS u ON u.session_id = a.session_id AND u.request_id = a.request_id "+
+			"AND u.role = 'user' AND u.deleted_at IS NULL").
+		Joins("JOIN sessions AS s ON s.id = a.session_id AND s.deleted_at IS NULL").
+		Where("s.tenant_id = ? AND a.role = 'assistant' AND a.is_completed AND a.deleted_at IS NULL "+
+			"AND a.created_at >= ?", tenantID, since).
+		Order("a.created_at DESC").
+		Limit(limit).
+		Scan(&messages).Error; err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
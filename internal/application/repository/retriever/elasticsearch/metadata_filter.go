@@ -0,0 +1,91 @@
+package elasticsearch
+
+import (
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+// IndexMapping is the mapping new indices are created with. Date detection is disabled and
+// metadata strings are mapped as text with a keyword sub-field, so a metadata value that looks
+// like a date stays a string and keeps the keyword sub-field the filters match on.
+const IndexMapping = `{
+  "mappings": {
+    "date_detection": false,
+    "dynamic_templates": [
+      {
+        "metadata_strings": {
+          "path_match": "metadata.*",
+          "match_mapping_type": "string",
+          "mapping": {
+            "type": "text",
+            "fields": {"keyword": {"type": "keyword", "ignore_above": 256}}
+          }
+        }
+      }
+    ]
+  }
+}`
+
+// BuildMetadataFilterQueries converts metadata filters into Elasticsearch query clauses for a bool must list.
+// Metadata strings are mapped by IndexMapping, so they are matched on the keyword sub-field while
+// numbers and booleans are matched on the field itself. Filters must be validated before calling.
+func BuildMetadataFilterQueries(filters types.MetadataFilters) []map[string]interface{} {
+	queries := make([]map[string]interface{}, 0, len(filters))
+	for i := range filters {
+		filter := &filters[i]
+		field := "metadata." + filter.Key
+		switch filter.Op {
+		case types.MetadataFilterOpEq:
+			queries = append(queries, map[string]interface{}{
+				"term": map[string]interface{}{
+					metadataValueField(field, filter.Value): filter.Value,
+				},
+			})
+		case types.MetadataFilterOpIn:
+			// Group values by target field, mixed types match any of the groups
+			grouped := make(map[string][]interface{})
+			for _, value := range filter.Values {
+				valueField := metadataValueField(field, value)
+				grouped[valueField] = append(grouped[valueField], value)
+			}
+			should := make([]map[string]interface{}, 0, len(grouped))
+			for valueField, values := range grouped {
+				should = append(should, map[string]interface{}{
+					"terms": map[string]interface{}{valueField: values},
+				})
+			}
+			queries = append(queries, map[string]interface{}{
+				"bool": map[string]interface{}{
+					"should":               should,
+					"minimum_should_match": 1,
+				},
+			})
+		case types.MetadataFilterOpExists:
+			queries = append(queries, map[string]interface{}{
+				"exists": map[string]interface{}{"field": field},
+			})
+		case types.MetadataFilterOpRange:
+			rangeField := field
+			if !filter.IsNumericRange() {
+				rangeField = field + ".keyword"
+			}
+			bounds := make(map[string]interface{})
+			for name, value := range map[string]any{"gt": filter.Gt, "gte": filter.Gte, "lt": filter.Lt, "lte": filter.Lte} {
+				if value != nil {
+					bounds[name] = value
+				}
+			}
+			queries = append(queries, map[string]interface{}{
+				"range": map[string]interface{}{rangeField: bounds},
+			})
+		}
+	}
+	return queries
+}
+
+// metadataValueField returns the field to match a metadata value against
+func metadataValueField(field string, value any) string {
+	if _, ok := value.(string); ok {
+		return field + ".keyword"
+	}
+	return field
+}
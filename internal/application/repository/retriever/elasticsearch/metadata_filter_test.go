@@ -0,0 +1,86 @@
+package elasticsearch
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+func TestBuildMetadataFilterQueries(t *testing.T) {
+	tests := []struct {
+		name     string
+		filters  types.MetadataFilters
+		expected []map[string]interface{}
+	}{
+		{
+			name:    "eq string",
+			filters: types.MetadataFilters{{Key: "department", Op: types.MetadataFilterOpEq, Value: "hr"}},
+			expected: []map[string]interface{}{
+				{"term": map[string]interface{}{"metadata.department.keyword": "hr"}},
+			},
+		},
+		{
+			name:    "eq number",
+			filters: types.MetadataFilters{{Key: "year", Op: types.MetadataFilterOpEq, Value: 2024.0}},
+			expected: []map[string]interface{}{
+				{"term": map[string]interface{}{"metadata.year": 2024.0}},
+			},
+		},
+		{
+			name: "date-valued key",
+			filters: types.MetadataFilters{
+				{Key: "published_at", Op: types.MetadataFilterOpEq, Value: "2024-05-01"},
+				{Key: "published_at", Op: types.MetadataFilterOpRange, Gte: "2024-01-01"},
+			},
+			expected: []map[string]interface{}{
+				{"term": map[string]interface{}{"metadata.published_at.keyword": "2024-05-01"}},
+				{"range": map[string]interface{}{
+					"metadata.published_at.keyword": map[string]interface{}{"gte": "2024-01-01"},
+				}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := BuildMetadataFilterQueries(tt.filters)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("BuildMetadataFilterQueries() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIndexMappingKeepsDateStringsAsKeywords(t *testing.T) {
+	var body struct {
+		Mappings struct {
+			DateDetection    *bool `json:"date_detection"`
+			DynamicTemplates []map[string]struct {
+				PathMatch        string `json:"path_match"`
+				MatchMappingType string `json:"match_mapping_type"`
+				Mapping          struct {
+					Fields map[string]struct {
+						Type string `json:"type"`
+					} `json:"fields"`
+				} `json:"mapping"`
+			} `json:"dynamic_templates"`
+		} `json:"mappings"`
+	}
+	if err := json.Unmarshal([]byte(IndexMapping), &body); err != nil {
+		t.Fatalf("IndexMapping is not valid JSON: %v", err)
+	}
+	if body.Mappings.DateDetection == nil || *body.Mappings.DateDetection {
+		t.Error("date_detection must be disabled so date-like metadata strings keep the keyword sub-field")
+	}
+	for _, template := range body.Mappings.DynamicTemplates {
+		for _, tmpl := range template {
+			if tmpl.PathMatch == "metadata.*" && tmpl.MatchMappingType == "string" &&
+				tmpl.Mapping.Fields["keyword"].Type == "keyword" {
+				return
+			}
+		}
+	}
+	t.Error("IndexMapping has no metadata string template with a keyword sub-field")
+}
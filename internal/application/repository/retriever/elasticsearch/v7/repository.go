@@ -44,9 +44,42 @@ func NewElasticsearchEngineRepository(client *elasticsearch.Client,
 
 	log.Infof("[ElasticsearchV7] Using index: %s", indexName)
 	res := &elasticsearchRepository{client: client, index: indexName}
+	if err := res.createIndexIfNotExists(context.Background()); err != nil {
+		log.Errorf("[ElasticsearchV7] Failed to create index: %v", err)
+	}
 	return res
 }
 
+// createIndexIfNotExists creates the index with the metadata mapping if it does not exist yet
+func (e *elasticsearchRepository) createIndexIfNotExists(ctx context.Context) error {
+	log := logger.GetLogger(ctx)
+
+	existsResp, err := e.client.Indices.Exists([]string{e.index}, e.client.Indices.Exists.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to check if index exists: %w", err)
+	}
+	existsResp.Body.Close()
+	if existsResp.StatusCode == 200 {
+		log.Debugf("[ElasticsearchV7] Index already exists: %s", e.index)
+		return nil
+	}
+
+	log.Infof("[ElasticsearchV7] Creating index: %s", e.index)
+	resp, err := e.client.Indices.Create(
+		e.index,
+		e.client.Indices.Create.WithBody(strings.NewReader(elasticsearchRetriever.IndexMapping)),
+		e.client.Indices.Create.WithContext(ctx),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create index: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.IsError() {
+		return fmt.Errorf("failed to create index: %s", resp.String())
+	}
+	return nil
+}
+
 func (e *elasticsearchRepository) EngineType() typesLocal.RetrieverEngineType {
 	return typesLocal.ElasticsearchRetrieverEngineType
 }
@@ -387,6 +420,10 @@ func (e *elasticsearchRepository) getBaseConds(params typesLocal.RetrieveParams)
 			},
 		})
 	}
+	// Filter by custom knowledge metadata if specified
+	if len(params.MetadataFilters) > 0 {
+		must = append(must, elasticsearchRetriever.BuildMetadataFilterQueries(params.MetadataFilters)...)
+	}
 
 	// Build MUST_NOT conditions (negative filters)
 	mustNot := make([]map[string]interface{}, 0)
@@ -1101,6 +1138,9 @@ func (e *elasticsearchRepository) processSingleHit(ctx context.Context,
 		Content:         content,
 		SourceType:      typesLocal.SourceType(sourceType),
 	}
+	if metadata, ok := sourceObj["metadata"].(map[string]interface{}); ok {
+		indexInfo.Metadata = metadata
+	}
 
 	return indexInfo, embedding, nil
 }
@@ -1296,3 +1336,60 @@ func (e *elasticsearchRepository) BatchUpdateChunkTagID(
 	log.Infof("[ElasticsearchV7] Successfully batch updated chunk tag ID")
 	return nil
 }
+
+// BatchUpdateKnowledgeMetadata replaces the custom metadata of all indices of the given knowledge
+func (e *elasticsearchRepository) BatchUpdateKnowledgeMetadata(
+	ctx context.Context,
+	knowledgeMetadataMap map[string]map[string]any,
+) error {
+	log := logger.GetLogger(ctx)
+	if len(knowledgeMetadataMap) == 0 {
+		log.Warnf("[ElasticsearchV7] Knowledge metadata map is empty, skipping update")
+		return nil
+	}
+
+	log.Infof("[ElasticsearchV7] Batch updating knowledge metadata, count: %d", len(knowledgeMetadataMap))
+
+	for knowledgeID, metadata := range knowledgeMetadataMap {
+		if metadata == nil {
+			metadata = map[string]any{}
+		}
+		query := map[string]interface{}{
+			"query": map[string]interface{}{
+				"term": map[string]interface{}{
+					"knowledge_id.keyword": knowledgeID,
+				},
+			},
+			"script": map[string]interface{}{
+				"source": "ctx._source.metadata = params.metadata",
+				"lang":   "painless",
+				"params": map[string]interface{}{
+					"metadata": metadata,
+				},
+			},
+		}
+		queryJSON, _ := json.Marshal(query)
+		res, err := esapi.UpdateByQueryRequest{
+			Index: []string{e.index},
+			Body:  strings.NewReader(string(queryJSON)),
+		}.Do(ctx, e.client)
+		if err != nil {
+			log.Errorf("[ElasticsearchV7] Failed to update metadata of knowledge %s: %v", knowledgeID, err)
+			return err
+		}
+		defer res.Body.Close()
+		if res.IsError() {
+			var e map[string]interface{}
+			if err := json.NewDecoder(res.Body).Decode(&e); err != nil {
+				log.Errorf("[ElasticsearchV7] Error parsing the response body: %v", err)
+			} else {
+				log.Errorf("[ElasticsearchV7] Error updating knowledge metadata: %v", e["error"])
+			}
+			return fmt.Errorf("elasticsearch update_by_query failed with status: %d", res.StatusCode)
+		}
+		log.Infof("[ElasticsearchV7] Updated metadata of knowledge %s", knowledgeID)
+	}
+
+	log.Infof("[ElasticsearchV7] Successfully batch updated knowledge metadata")
+	return nil
+}
@@ -237,7 +237,8 @@ func (e *elasticsearchRepository) DeleteByKnowledgeIDList(ctx context.Context,
 // getBaseConds creates the base query conditions for retrieval operations
 // Returns a slice of Query objects with must and must_not conditions
 // KnowledgeBaseIDs and KnowledgeIDs use AND logic (search specific documents within knowledge bases)
-func (e *elasticsearchRepository) getBaseConds(params typesLocal.RetrieveParams) []types.Query {
+// An error is returned when the metadata filters cannot be converted, so retrieval never runs unfiltered
+func (e *elasticsearchRepository) getBaseConds(params typesLocal.RetrieveParams) ([]types.Query, error) {
 	must := []types.Query{}
 
 	// KnowledgeBaseIDs and KnowledgeIDs use AND logic
@@ -266,6 +267,14 @@ func (e *elasticsearchRepository) getBaseConds(params typesLocal.RetrieveParams)
 			},
 		}})
 	}
+	// Filter by custom knowledge metadata if specified
+	if len(params.MetadataFilters) > 0 {
+		metadataQueries, err := metadataFilterQueries(params.MetadataFilters)
+		if err != nil {
+			return nil, err
+		}
+		must = append(must, metadataQueries...)
+	}
 
 	mustNot := make([]types.Query, 0)
 	// Exclude disabled chunks (is_enabled = false)
@@ -283,7 +292,25 @@ func (e *elasticsearchRepository) getBaseConds(params typesLocal.RetrieveParams)
 			TermsQuery: map[string]types.TermsQueryField{"chunk_id.keyword": params.ExcludeChunkIDs},
 		}})
 	}
-	return []types.Query{{Bool: &types.BoolQuery{Must: must, MustNot: mustNot}}}
+	return []types.Query{{Bool: &types.BoolQuery{Must: must, MustNot: mustNot}}}, nil
+}
+
+// metadataFilterQueries converts the shared metadata filter clauses into typed queries
+func metadataFilterQueries(filters typesLocal.MetadataFilters) ([]types.Query, error) {
+	clauses := elasticsearchRetriever.BuildMetadataFilterQueries(filters)
+	queries := make([]types.Query, 0, len(clauses))
+	for _, clause := range clauses {
+		clauseJSON, err := json.Marshal(clause)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal metadata filter: %w", err)
+		}
+		var query types.Query
+		if err := json.Unmarshal(clauseJSON, &query); err != nil {
+			return nil, fmt.Errorf("failed to convert metadata filter: %w", err)
+		}
+		queries = append(queries, query)
+	}
+	return queries, nil
 }
 
 // createIndexIfNotExists checks if the specified index exists and creates it if not
@@ -306,7 +333,7 @@ func (e *elasticsearchRepository) createIndexIfNotExists(ctx context.Context) er
 
 	// Create index if it doesn't exist
 	log.Infof("[Elasticsearch] Creating index: %s", e.index)
-	_, err = e.client.Indices.Create(e.index).Do(ctx)
+	_, err = e.client.Indices.Create(e.index).Raw(strings.NewReader(elasticsearchRetriever.IndexMapping)).Do(ctx)
 	if err != nil {
 		log.Errorf("[Elasticsearch] Failed to create index: %v", err)
 		return err
@@ -346,7 +373,11 @@ func (e *elasticsearchRepository) VectorRetrieve(ctx context.Context,
 	log.Infof("[Elasticsearch] Vector retrieval: dim=%d, topK=%d, threshold=%.4f",
 		len(params.Embedding), params.TopK, params.Threshold)
 
-	filter := e.getBaseConds(params)
+	filter, err := e.getBaseConds(params)
+	if err != nil {
+		log.Errorf("[Elasticsearch] Failed to build vector retrieval filter: %v", err)
+		return nil, err
+	}
 
 	// Build script scoring query with cosine similarity
 	queryVectorJSON, err := json.Marshal(params.Embedding)
@@ -417,7 +448,11 @@ func (e *elasticsearchRepository) KeywordsRetrieve(ctx context.Context,
 	log := logger.GetLogger(ctx)
 	log.Infof("[Elasticsearch] Performing keywords retrieval with query: %s, topK: %d", params.Query, params.TopK)
 
-	filter := e.getBaseConds(params)
+	filter, err := e.getBaseConds(params)
+	if err != nil {
+		log.Errorf("[Elasticsearch] Failed to build keyword retrieval filter: %v", err)
+		return nil, err
+	}
 	// Build must conditions for content matching
 	must := []types.Query{
 		{Match: map[string]types.MatchQuery{"content": {Query: params.Query}}},
@@ -490,7 +525,10 @@ func (e *elasticsearchRepository) CopyIndices(ctx context.Context,
 	}
 
 	// Build base query conditions
-	filter := e.getBaseConds(params)
+	filter, err := e.getBaseConds(params)
+	if err != nil {
+		return err
+	}
 
 	// Set batch processing parameters
 	batchSize := 500
@@ -575,6 +613,7 @@ func (e *elasticsearchRepository) CopyIndices(ctx context.Context,
 				ChunkID:         targetChunkID,
 				KnowledgeID:     targetKnowledgeID,
 				KnowledgeBaseID: targetKnowledgeBaseID,
+				Metadata:        sourceDoc.Metadata,
 			}
 
 			indexInfoList = append(indexInfoList, indexInfo)
@@ -742,3 +781,49 @@ func (e *elasticsearchRepository) BatchUpdateChunkTagID(
 	log.Infof("[Elasticsearch] Successfully batch updated chunk tag ID")
 	return nil
 }
+
+// BatchUpdateKnowledgeMetadata replaces the custom metadata of all indices of the given knowledge
+func (e *elasticsearchRepository) BatchUpdateKnowledgeMetadata(
+	ctx context.Context,
+	knowledgeMetadataMap map[string]map[string]any,
+) error {
+	log := logger.GetLogger(ctx)
+	if len(knowledgeMetadataMap) == 0 {
+		log.Warnf("[Elasticsearch] Knowledge metadata map is empty, skipping update")
+		return nil
+	}
+
+	log.Infof("[Elasticsearch] Batch updating knowledge metadata, count: %d", len(knowledgeMetadataMap))
+
+	for knowledgeID, metadata := range knowledgeMetadataMap {
+		if metadata == nil {
+			metadata = map[string]any{}
+		}
+		metadataJSON, err := json.Marshal(metadata)
+		if err != nil {
+			log.Errorf("[Elasticsearch] Failed to marshal metadata of knowledge %s: %v", knowledgeID, err)
+			return err
+		}
+		query := types.NewQuery()
+		query.Term = map[string]types.TermQuery{
+			"knowledge_id.keyword": {Value: knowledgeID},
+		}
+		source := "ctx._source.metadata = params.metadata"
+		lang := scriptlanguage.Painless
+		script := types.Script{
+			Source: &source,
+			Lang:   &lang,
+			Params: map[string]json.RawMessage{
+				"metadata": metadataJSON,
+			},
+		}
+		if _, err := e.client.UpdateByQuery(e.index).Query(query).Script(&script).Do(ctx); err != nil {
+			log.Errorf("[Elasticsearch] Failed to update metadata of knowledge %s: %v", knowledgeID, err)
+			return err
+		}
+		log.Infof("[Elasticsearch] Updated metadata of knowledge %s", knowledgeID)
+	}
+
+	log.Infof("[Elasticsearch] Successfully batch updated knowledge metadata")
+	return nil
+}
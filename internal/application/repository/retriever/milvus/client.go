@@ -0,0 +1,386 @@
+package milvus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// maxIDLength is the max length of the id and knowledge related varchar fields
+	maxIDLength = 128
+	// maxContentLength is the max byte length of the content field, the Milvus varchar limit
+	maxContentLength = 65535
+	// defaultTimeout is the default timeout of a Milvus RESTful API request
+	defaultTimeout = 30 * time.Second
+)
+
+// milvusClient is the subset of Milvus operations used by the repository.
+// It is implemented by restClient on top of the Milvus RESTful API, and by an in-memory fake in tests.
+type milvusClient interface {
+	// HasCollection reports whether the collection exists
+	HasCollection(ctx context.Context, collection string) (bool, error)
+	// CreateCollection creates and loads a collection for vectors of the given dimension
+	CreateCollection(ctx context.Context, collection string, dimension int) error
+	// ListCollections lists all collection names
+	ListCollections(ctx context.Context) ([]string, error)
+	// Upsert inserts rows, replacing rows with the same ID
+	Upsert(ctx context.Context, collection string, rows []*MilvusVectorEmbedding) error
+	// Delete removes the rows matching the filter
+	Delete(ctx context.Context, collection string, filter *milvusFilter) error
+	// Search returns the topK rows most similar to the vector by cosine similarity, best first
+	Search(ctx context.Context, collection string, vector []float32,
+		filter *milvusFilter, topK int) ([]*MilvusVectorEmbeddingWithScore, error)
+	// Query returns up to limit rows matching the filter with an ID greater than afterID, ordered by ID
+	Query(ctx context.Context, collection string, filter *milvusFilter,
+		afterID string, limit int, withVector bool) ([]*MilvusVectorEmbedding, error)
+}
+
+// ClientConfig configures the connection to Milvus
+type ClientConfig struct {
+	// Address is the Milvus endpoint, e.g. http://localhost:19530
+	Address string
+	// Token is "username:password" or an API key, empty when authentication is disabled
+	Token string
+	// DBName is the database name, empty for the default database
+	DBName string
+	// Timeout is the timeout of each request, defaults to 30s
+	Timeout time.Duration
+}
+
+// restClient implements milvusClient with the Milvus RESTful API v2.
+// The RESTful API is used instead of the Go SDK, which pulls in the Milvus server dependencies.
+type restClient struct {
+	baseURL    string
+	token      string
+	dbName     string
+	httpClient *http.Client
+}
+
+// newRESTClient creates a Milvus RESTful API client
+func newRESTClient(cfg ClientConfig) *restClient {
+	address := strings.TrimRight(cfg.Address, "/")
+	if !strings.HasPrefix(address, "http://") && !strings.HasPrefix(address, "https://") {
+		address = "http://" + address
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	return &restClient{
+		baseURL:    address,
+		token:      cfg.Token,
+		dbName:     cfg.DBName,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// restResponse is the envelope of every Milvus RESTful API response
+type restResponse struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// restRow is a row as sent to and returned by the RESTful API
+type restRow struct {
+	ID              string          `json:"id"`
+	Content         string          `json:"content"`
+	SourceID        string          `json:"source_id"`
+	SourceType      int             `json:"source_type"`
+	ChunkID         string          `json:"chunk_id"`
+	KnowledgeID     string          `json:"knowledge_id"`
+	KnowledgeBaseID string          `json:"knowledge_base_id"`
+	TagID           string          `json:"tag_id"`
+	IsEnabled       bool            `json:"is_enabled"`
+	Metadata        json.RawMessage `json:"metadata"`
+	Embedding       []float32       `json:"embedding,omitempty"`
+	Distance        float64         `json:"distance,omitempty"`
+}
+
+// scalarFields are the output fields of search and query besides the vector
+var scalarFields = []string{
+	fieldID, fieldContent, fieldSourceID, fieldSourceType, fieldChunkID, fieldKnowledgeID,
+	fieldKnowledgeBaseID, fieldTagID, fieldIsEnabled, fieldMetadata,
+}
+
+// post calls a RESTful API endpoint and decodes the data of the response into out
+func (c *restClient) post(ctx context.Context, path string, body map[string]any, out any) error {
+	if c.dbName != "" {
+		body["dbName"] = c.dbName
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("milvus %s returned status %d: %s", path, resp.StatusCode, string(respBody))
+	}
+
+	var result restResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	if result.Code != 0 {
+		return fmt.Errorf("milvus %s failed with code %d: %s", path, result.Code, result.Message)
+	}
+	if out != nil && len(result.Data) > 0 {
+		if err := json.Unmarshal(result.Data, out); err != nil {
+			return fmt.Errorf("failed to decode response data: %w", err)
+		}
+	}
+	return nil
+}
+
+func (c *restClient) HasCollection(ctx context.Context, collection string) (bool, error) {
+	var data struct {
+		Has bool `json:"has"`
+	}
+	err := c.post(ctx, "/v2/vectordb/collections/has", map[string]any{"collectionName": collection}, &data)
+	return data.Has, err
+}
+
+func (c *restClient) CreateCollection(ctx context.Context, collection string, dimension int) error {
+	varchar := func(name string, maxLength int) map[string]any {
+		return map[string]any{
+			"fieldName":         name,
+			"dataType":          "VarChar",
+			"elementTypeParams": map[string]any{"max_length": maxLength},
+		}
+	}
+	primaryKey := varchar(fieldID, maxIDLength)
+	primaryKey["isPrimary"] = true
+
+	fields := []map[string]any{
+		primaryKey,
+		varchar(fieldContent, maxContentLength),
+		varchar(fieldSourceID, maxIDLength*2),
+		{"fieldName": fieldSourceType, "dataType": "Int64"},
+		varchar(fieldChunkID, maxIDLength),
+		varchar(fieldKnowledgeID, maxIDLength),
+		varchar(fieldKnowledgeBaseID, maxIDLength),
+		varchar(fieldTagID, maxIDLength),
+		{"fieldName": fieldIsEnabled, "dataType": "Bool"},
+		{"fieldName": fieldMetadata, "dataType": "JSON"},
+		{
+			"fieldName":         fieldEmbedding,
+			"dataType":          "FloatVector",
+			"elementTypeParams": map[string]any{"dim": dimension},
+		},
+	}
+
+	indexParams := []map[string]any{{
+		"fieldName":  fieldEmbedding,
+		"indexName":  fieldEmbedding,
+		"metricType": "COSINE",
+		"params":     map[string]any{"index_type": "AUTOINDEX"},
+	}}
+	// Inverted indexes speed up the filters used by retrieval, deletion and batch updates
+	for _, field := range []string{fieldChunkID, fieldKnowledgeID, fieldKnowledgeBaseID, fieldSourceID} {
+		indexParams = append(indexParams, map[string]any{
+			"fieldName": field,
+			"indexName": field,
+			"params":    map[string]any{"index_type": "INVERTED"},
+		})
+	}
+
+	// Collections created with index params are loaded automatically
+	return c.post(ctx, "/v2/vectordb/collections/create", map[string]any{
+		"collectionName": collection,
+		"schema": map[string]any{
+			"autoId":             false,
+			"enableDynamicField": false,
+			"fields":             fields,
+		},
+		"indexParams": indexParams,
+	}, nil)
+}
+
+func (c *restClient) ListCollections(ctx context.Context) ([]string, error) {
+	var collections []string
+	err := c.post(ctx, "/v2/vectordb/collections/list", map[string]any{}, &collections)
+	return collections, err
+}
+
+func (c *restClient) Upsert(ctx context.Context, collection string, rows []*MilvusVectorEmbedding) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	data := make([]restRow, 0, len(rows))
+	for _, row := range rows {
+		metadata := row.Metadata
+		if metadata == nil {
+			// The JSON field is not nullable
+			metadata = map[string]any{}
+		}
+		metadataJSON, err := json.Marshal(metadata)
+		if err != nil {
+			return fmt.Errorf("failed to marshal metadata of chunk %s: %w", row.ChunkID, err)
+		}
+		data = append(data, restRow{
+			ID:              row.ID,
+			Content:         row.Content,
+			SourceID:        row.SourceID,
+			SourceType:      row.SourceType,
+			ChunkID:         row.ChunkID,
+			KnowledgeID:     row.KnowledgeID,
+			KnowledgeBaseID: row.KnowledgeBaseID,
+			TagID:           row.TagID,
+			IsEnabled:       row.IsEnabled,
+			Metadata:        metadataJSON,
+			Embedding:       row.Embedding,
+		})
+	}
+	return c.post(ctx, "/v2/vectordb/entities/upsert", map[string]any{
+		"collectionName": collection,
+		"data":           data,
+	}, nil)
+}
+
+func (c *restClient) Delete(ctx context.Context, collection string, filter *milvusFilter) error {
+	return c.post(ctx, "/v2/vectordb/entities/delete", map[string]any{
+		"collectionName": collection,
+		"filter":         filter.Expr(),
+	}, nil)
+}
+
+func (c *restClient) Search(ctx context.Context, collection string, vector []float32,
+	filter *milvusFilter, topK int,
+) ([]*MilvusVectorEmbeddingWithScore, error) {
+	body := map[string]any{
+		"collectionName": collection,
+		"data":           [][]float32{vector},
+		"annsField":      fieldEmbedding,
+		"limit":          topK,
+		"outputFields":   scalarFields,
+	}
+	if expr := filter.Expr(); expr != "" {
+		body["filter"] = expr
+	}
+
+	var data []restRow
+	if err := c.post(ctx, "/v2/vectordb/entities/search", body, &data); err != nil {
+		return nil, err
+	}
+
+	results := make([]*MilvusVectorEmbeddingWithScore, 0, len(data))
+	for _, row := range data {
+		embedding, err := row.toEmbedding()
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, &MilvusVectorEmbeddingWithScore{
+			MilvusVectorEmbedding: *embedding,
+			Score:                 row.Distance,
+		})
+	}
+	return results, nil
+}
+
+func (c *restClient) Query(ctx context.Context, collection string, filter *milvusFilter,
+	afterID string, limit int, withVector bool,
+) ([]*MilvusVectorEmbedding, error) {
+	expr := filter.Expr()
+	if afterID != "" {
+		// Query results are ordered by primary key, so the last ID is a stable pagination cursor
+		cursor := fmt.Sprintf("%s > %s", fieldID, quoteString(afterID))
+		if expr == "" {
+			expr = cursor
+		} else {
+			expr = fmt.Sprintf("(%s) and %s", expr, cursor)
+		}
+	}
+	outputFields := scalarFields
+	if withVector {
+		outputFields = append(append([]string{}, scalarFields...), fieldEmbedding)
+	}
+
+	var data []restRow
+	err := c.post(ctx, "/v2/vectordb/entities/query", map[string]any{
+		"collectionName": collection,
+		"filter":         expr,
+		"limit":          limit,
+		"outputFields":   outputFields,
+	}, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]*MilvusVectorEmbedding, 0, len(data))
+	for _, row := range data {
+		embedding, err := row.toEmbedding()
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, embedding)
+	}
+	return rows, nil
+}
+
+// toEmbedding converts a RESTful API row to the repository row
+func (r *restRow) toEmbedding() (*MilvusVectorEmbedding, error) {
+	metadata, err := decodeMetadata(r.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode metadata of chunk %s: %w", r.ChunkID, err)
+	}
+	return &MilvusVectorEmbedding{
+		ID:              r.ID,
+		Content:         r.Content,
+		SourceID:        r.SourceID,
+		SourceType:      r.SourceType,
+		ChunkID:         r.ChunkID,
+		KnowledgeID:     r.KnowledgeID,
+		KnowledgeBaseID: r.KnowledgeBaseID,
+		TagID:           r.TagID,
+		Embedding:       r.Embedding,
+		IsEnabled:       r.IsEnabled,
+		Metadata:        metadata,
+	}, nil
+}
+
+// decodeMetadata decodes a JSON field, which the RESTful API returns either as an object or as a JSON string
+func decodeMetadata(raw json.RawMessage) (map[string]any, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	if raw[0] == '"' {
+		var encoded string
+		if err := json.Unmarshal(raw, &encoded); err != nil {
+			return nil, err
+		}
+		raw = json.RawMessage(encoded)
+	}
+	var metadata map[string]any
+	if err := json.Unmarshal(raw, &metadata); err != nil {
+		return nil, err
+	}
+	if len(metadata) == 0 {
+		return nil, nil
+	}
+	return metadata, nil
+}
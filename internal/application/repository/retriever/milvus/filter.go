@@ -0,0 +1,123 @@
+package milvus
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+// termCondition matches rows whose string field is (or with Not, is not) one of Values
+type termCondition struct {
+	Field  string
+	Values []string
+	Not    bool
+}
+
+// milvusFilter is a conjunction of conditions on the scalar fields of a collection.
+// It is kept structured rather than as a raw expression so that the client can be faked in tests.
+type milvusFilter struct {
+	// EnabledOnly matches only enabled rows
+	EnabledOnly bool
+	// Terms are string field membership conditions
+	Terms []termCondition
+	// MetadataFilters are conditions on the custom knowledge metadata JSON field
+	MetadataFilters types.MetadataFilters
+}
+
+// in adds a membership condition, empty values are ignored
+func (f *milvusFilter) in(field string, values ...string) *milvusFilter {
+	if len(values) > 0 {
+		f.Terms = append(f.Terms, termCondition{Field: field, Values: values})
+	}
+	return f
+}
+
+// notIn adds an exclusion condition, empty values are ignored
+func (f *milvusFilter) notIn(field string, values ...string) *milvusFilter {
+	if len(values) > 0 {
+		f.Terms = append(f.Terms, termCondition{Field: field, Values: values, Not: true})
+	}
+	return f
+}
+
+// Expr renders the filter as a Milvus boolean expression
+func (f *milvusFilter) Expr() string {
+	var parts []string
+	if f.EnabledOnly {
+		parts = append(parts, fmt.Sprintf("%s == true", fieldIsEnabled))
+	}
+	for _, term := range f.Terms {
+		op := "in"
+		if term.Not {
+			op = "not in"
+		}
+		parts = append(parts, fmt.Sprintf("%s %s %s", term.Field, op, quoteStringList(term.Values)))
+	}
+	parts = append(parts, buildMetadataExprs(f.MetadataFilters)...)
+	return strings.Join(parts, " and ")
+}
+
+// buildMetadataExprs converts metadata filters into Milvus JSON field expressions.
+// Filters must have been normalized by MetadataFilters.Normalize.
+func buildMetadataExprs(filters types.MetadataFilters) []string {
+	exprs := make([]string, 0, len(filters))
+	for _, filter := range filters {
+		// Keys are restricted to [A-Za-z0-9_-], so they can be quoted as is
+		field := fmt.Sprintf("%s[\"%s\"]", fieldMetadata, filter.Key)
+		switch filter.Op {
+		case types.MetadataFilterOpEq:
+			exprs = append(exprs, fmt.Sprintf("%s == %s", field, formatLiteral(filter.Value)))
+		case types.MetadataFilterOpIn:
+			// Values may mix types, so use an OR of equalities instead of a typed list
+			conditions := make([]string, 0, len(filter.Values))
+			for _, value := range filter.Values {
+				conditions = append(conditions, fmt.Sprintf("%s == %s", field, formatLiteral(value)))
+			}
+			exprs = append(exprs, "("+strings.Join(conditions, " or ")+")")
+		case types.MetadataFilterOpExists:
+			exprs = append(exprs, fmt.Sprintf("exists %s", field))
+		case types.MetadataFilterOpRange:
+			bounds := []struct {
+				op    string
+				value any
+			}{{">", filter.Gt}, {">=", filter.Gte}, {"<", filter.Lt}, {"<=", filter.Lte}}
+			for _, bound := range bounds {
+				if bound.value != nil {
+					exprs = append(exprs, fmt.Sprintf("%s %s %s", field, bound.op, formatLiteral(bound.value)))
+				}
+			}
+		}
+	}
+	return exprs
+}
+
+// formatLiteral renders a normalized metadata value as a Milvus literal
+func formatLiteral(value any) string {
+	switch v := value.(type) {
+	case string:
+		return quoteString(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	return quoteString(fmt.Sprint(value))
+}
+
+// quoteString renders a double-quoted Milvus string literal
+func quoteString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+// quoteStringList renders a list of Milvus string literals
+func quoteStringList(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		quoted = append(quoted, quoteString(v))
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
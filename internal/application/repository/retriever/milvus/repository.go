@@ -0,0 +1,702 @@
+package milvus
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+	"github.com/google/uuid"
+)
+
+const (
+	envMilvusCollection   = "MILVUS_COLLECTION"
+	defaultCollectionName = "weknora_embeddings"
+	fieldID               = "id"
+	fieldContent          = "content"
+	fieldSourceID         = "source_id"
+	fieldSourceType       = "source_type"
+	fieldChunkID          = "chunk_id"
+	fieldKnowledgeID      = "knowledge_id"
+	fieldKnowledgeBaseID  = "knowledge_base_id"
+	fieldTagID            = "tag_id"
+	fieldEmbedding        = "embedding"
+	fieldIsEnabled        = "is_enabled"
+	fieldMetadata         = "metadata"
+
+	// batchSize is the page size used when reading rows back for updates and copies
+	batchSize = 500
+)
+
+// NewMilvusRetrieveEngineRepository creates and initializes a new Milvus repository
+func NewMilvusRetrieveEngineRepository(cfg ClientConfig) interfaces.RetrieveEngineRepository {
+	log := logger.GetLogger(context.Background())
+	log.Infof("[Milvus] Initializing Milvus retriever engine repository, address: %s", cfg.Address)
+
+	collectionBaseName := os.Getenv(envMilvusCollection)
+	if collectionBaseName == "" {
+		log.Warn("[Milvus] MILVUS_COLLECTION environment variable not set, using default collection name")
+		collectionBaseName = defaultCollectionName
+	}
+
+	res := newMilvusRepository(newRESTClient(cfg), collectionBaseName)
+
+	log.Info("[Milvus] Successfully initialized repository")
+	return res
+}
+
+// newMilvusRepository creates a repository on top of any milvusClient implementation
+func newMilvusRepository(client milvusClient, collectionBaseName string) *milvusRepository {
+	return &milvusRepository{
+		client:             client,
+		collectionBaseName: collectionBaseName,
+	}
+}
+
+// getCollectionName returns the collection name for a specific dimension
+func (m *milvusRepository) getCollectionName(dimension int) string {
+	return fmt.Sprintf("%s_%d", m.collectionBaseName, dimension)
+}
+
+// isOwnCollection reports whether the collection was created by this repository
+func (m *milvusRepository) isOwnCollection(collectionName string) bool {
+	return strings.HasPrefix(collectionName, m.collectionBaseName+"_")
+}
+
+// listCollections returns the collections of all dimensions created by this repository
+func (m *milvusRepository) listCollections(ctx context.Context) ([]string, error) {
+	collections, err := m.client.ListCollections(ctx)
+	if err != nil {
+		logger.GetLogger(ctx).Errorf("[Milvus] Failed to list collections: %v", err)
+		return nil, fmt.Errorf("failed to list collections: %w", err)
+	}
+	own := make([]string, 0, len(collections))
+	for _, collectionName := range collections {
+		if m.isOwnCollection(collectionName) {
+			own = append(own, collectionName)
+		}
+	}
+	return own, nil
+}
+
+// ensureCollection ensures the collection exists for the given dimension
+func (m *milvusRepository) ensureCollection(ctx context.Context, dimension int) error {
+	collectionName := m.getCollectionName(dimension)
+
+	// Check cache first
+	if _, ok := m.initializedCollections.Load(dimension); ok {
+		return nil
+	}
+
+	log := logger.GetLogger(ctx)
+
+	// Check if collection exists
+	exists, err := m.client.HasCollection(ctx, collectionName)
+	if err != nil {
+		log.Errorf("[Milvus] Failed to check collection existence: %v", err)
+		return fmt.Errorf("failed to check collection existence: %w", err)
+	}
+
+	if !exists {
+		log.Infof("[Milvus] Creating collection %s with dimension %d", collectionName, dimension)
+		if err := m.client.CreateCollection(ctx, collectionName, dimension); err != nil {
+			log.Errorf("[Milvus] Failed to create collection: %v", err)
+			return fmt.Errorf("failed to create collection: %w", err)
+		}
+		log.Infof("[Milvus] Successfully created collection %s", collectionName)
+	}
+
+	// Mark as initialized
+	m.initializedCollections.Store(dimension, true)
+	return nil
+}
+
+// collectionExists reports whether the collection of the dimension exists, used to skip
+// operations on dimensions that were never written
+func (m *milvusRepository) collectionExists(ctx context.Context, dimension int) (bool, error) {
+	if _, ok := m.initializedCollections.Load(dimension); ok {
+		return true, nil
+	}
+	exists, err := m.client.HasCollection(ctx, m.getCollectionName(dimension))
+	if err != nil {
+		logger.GetLogger(ctx).Errorf("[Milvus] Failed to check collection existence: %v", err)
+		return false, fmt.Errorf("failed to check collection existence: %w", err)
+	}
+	return exists, nil
+}
+
+func (m *milvusRepository) EngineType() types.RetrieverEngineType {
+	return types.MilvusRetrieverEngineType
+}
+
+func (m *milvusRepository) Support() []types.RetrieverType {
+	return []types.RetrieverType{types.VectorRetrieverType}
+}
+
+// EstimateStorageSize calculates the estimated storage size for a list of indices
+func (m *milvusRepository) EstimateStorageSize(ctx context.Context,
+	indexInfoList []*types.IndexInfo, params map[string]any,
+) int64 {
+	var totalStorageSize int64
+	for _, embedding := range indexInfoList {
+		embeddingDB := toMilvusVectorEmbedding(embedding, params)
+		totalStorageSize += m.calculateStorageSize(embeddingDB)
+	}
+	logger.GetLogger(ctx).Infof(
+		"[Milvus] Storage size for %d indices: %d bytes", len(indexInfoList), totalStorageSize,
+	)
+	return totalStorageSize
+}
+
+// Save stores a single row in Milvus
+func (m *milvusRepository) Save(ctx context.Context,
+	embedding *types.IndexInfo,
+	additionalParams map[string]any,
+) error {
+	log := logger.GetLogger(ctx)
+	log.Debugf("[Milvus] Saving index for chunk ID: %s", embedding.ChunkID)
+
+	embeddingDB := toMilvusVectorEmbedding(embedding, additionalParams)
+	if len(embeddingDB.Embedding) == 0 {
+		err := fmt.Errorf("empty embedding vector for chunk ID: %s", embedding.ChunkID)
+		log.Errorf("[Milvus] %v", err)
+		return err
+	}
+
+	dimension := len(embeddingDB.Embedding)
+	if err := m.ensureCollection(ctx, dimension); err != nil {
+		return err
+	}
+
+	embeddingDB.ID = uuid.New().String()
+	if err := m.client.Upsert(ctx, m.getCollectionName(dimension), []*MilvusVectorEmbedding{embeddingDB}); err != nil {
+		log.Errorf("[Milvus] Failed to save index: %v", err)
+		return err
+	}
+
+	log.Infof("[Milvus] Successfully saved index for chunk ID: %s, row ID: %s", embedding.ChunkID, embeddingDB.ID)
+	return nil
+}
+
+// BatchSave stores multiple rows in Milvus, grouped into one collection per dimension
+func (m *milvusRepository) BatchSave(ctx context.Context,
+	embeddingList []*types.IndexInfo, additionalParams map[string]any,
+) error {
+	log := logger.GetLogger(ctx)
+	if len(embeddingList) == 0 {
+		log.Warn("[Milvus] Empty list provided to BatchSave, skipping")
+		return nil
+	}
+
+	log.Infof("[Milvus] Batch saving %d indices", len(embeddingList))
+
+	// Group rows by dimension
+	rowsByDimension := make(map[int][]*MilvusVectorEmbedding)
+
+	for _, embedding := range embeddingList {
+		embeddingDB := toMilvusVectorEmbedding(embedding, additionalParams)
+		if len(embeddingDB.Embedding) == 0 {
+			log.Warnf("[Milvus] Skipping empty embedding for chunk ID: %s", embedding.ChunkID)
+			continue
+		}
+
+		dimension := len(embeddingDB.Embedding)
+		embeddingDB.ID = uuid.New().String()
+		rowsByDimension[dimension] = append(rowsByDimension[dimension], embeddingDB)
+		log.Debugf("[Milvus] Added chunk ID %s to batch request (dimension: %d)", embedding.ChunkID, dimension)
+	}
+
+	if len(rowsByDimension) == 0 {
+		log.Warn("[Milvus] No valid rows to save after filtering")
+		return nil
+	}
+
+	// Save rows to each dimension-specific collection
+	totalSaved := 0
+	for dimension, rows := range rowsByDimension {
+		if err := m.ensureCollection(ctx, dimension); err != nil {
+			return err
+		}
+
+		collectionName := m.getCollectionName(dimension)
+		if err := m.client.Upsert(ctx, collectionName, rows); err != nil {
+			log.Errorf("[Milvus] Failed to execute batch operation for dimension %d: %v", dimension, err)
+			return fmt.Errorf("failed to batch save (dimension %d): %w", dimension, err)
+		}
+		totalSaved += len(rows)
+		log.Infof("[Milvus] Saved %d rows to collection %s", len(rows), collectionName)
+	}
+
+	log.Infof("[Milvus] Successfully batch saved %d indices", totalSaved)
+	return nil
+}
+
+// deleteBy removes rows of the dimension's collection whose field matches any of the values
+func (m *milvusRepository) deleteBy(ctx context.Context, field string, values []string, dimension int) error {
+	log := logger.GetLogger(ctx)
+	if len(values) == 0 {
+		log.Warnf("[Milvus] Empty %s list provided for deletion, skipping", field)
+		return nil
+	}
+
+	exists, err := m.collectionExists(ctx, dimension)
+	if err != nil {
+		return err
+	}
+	collectionName := m.getCollectionName(dimension)
+	if !exists {
+		log.Warnf("[Milvus] Collection %s does not exist, nothing to delete", collectionName)
+		return nil
+	}
+
+	log.Infof("[Milvus] Deleting indices by %s from %s, count: %d", field, collectionName, len(values))
+	filter := (&milvusFilter{}).in(field, values...)
+	if err := m.client.Delete(ctx, collectionName, filter); err != nil {
+		log.Errorf("[Milvus] Failed to delete by %s: %v", field, err)
+		return fmt.Errorf("failed to delete by %s: %w", field, err)
+	}
+
+	log.Infof("[Milvus] Successfully deleted documents by %s", field)
+	return nil
+}
+
+// DeleteByChunkIDList removes rows from the collection based on chunk IDs
+func (m *milvusRepository) DeleteByChunkIDList(ctx context.Context,
+	chunkIDList []string, dimension int, knowledgeType string,
+) error {
+	return m.deleteBy(ctx, fieldChunkID, chunkIDList, dimension)
+}
+
+// DeleteByKnowledgeIDList removes rows from the collection based on knowledge IDs
+func (m *milvusRepository) DeleteByKnowledgeIDList(ctx context.Context,
+	knowledgeIDList []string, dimension int, knowledgeType string,
+) error {
+	return m.deleteBy(ctx, fieldKnowledgeID, knowledgeIDList, dimension)
+}
+
+// DeleteBySourceIDList removes rows from the collection based on source IDs
+func (m *milvusRepository) DeleteBySourceIDList(ctx context.Context,
+	sourceIDList []string, dimension int, knowledgeType string,
+) error {
+	return m.deleteBy(ctx, fieldSourceID, sourceIDList, dimension)
+}
+
+// updateRows rewrites the rows matching the filter in all collections.
+// Milvus has no partial update, so rows are read back with their vectors, changed and upserted.
+func (m *milvusRepository) updateRows(ctx context.Context,
+	filter *milvusFilter, update func(row *MilvusVectorEmbedding),
+) error {
+	log := logger.GetLogger(ctx)
+	collections, err := m.listCollections(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, collectionName := range collections {
+		afterID := ""
+		for {
+			rows, err := m.client.Query(ctx, collectionName, filter, afterID, batchSize, true)
+			if err != nil {
+				log.Errorf("[Milvus] Failed to query rows to update in %s: %v", collectionName, err)
+				return fmt.Errorf("failed to query rows to update in %s: %w", collectionName, err)
+			}
+			if len(rows) == 0 {
+				break
+			}
+			for _, row := range rows {
+				update(row)
+			}
+			if err := m.client.Upsert(ctx, collectionName, rows); err != nil {
+				log.Errorf("[Milvus] Failed to upsert updated rows in %s: %v", collectionName, err)
+				return fmt.Errorf("failed to upsert updated rows in %s: %w", collectionName, err)
+			}
+			if len(rows) < batchSize {
+				break
+			}
+			afterID = rows[len(rows)-1].ID
+		}
+	}
+	return nil
+}
+
+// BatchUpdateChunkEnabledStatus updates the enabled status of chunks in batch
+// This method operates on all collections since dimension is not provided
+func (m *milvusRepository) BatchUpdateChunkEnabledStatus(ctx context.Context, chunkStatusMap map[string]bool) error {
+	log := logger.GetLogger(ctx)
+	if len(chunkStatusMap) == 0 {
+		log.Warn("[Milvus] Empty chunk status map provided, skipping")
+		return nil
+	}
+
+	log.Infof("[Milvus] Batch updating chunk enabled status, count: %d", len(chunkStatusMap))
+
+	// Group chunks by enabled status for batch updates
+	statusGroups := make(map[bool][]string)
+	for chunkID, enabled := range chunkStatusMap {
+		statusGroups[enabled] = append(statusGroups[enabled], chunkID)
+	}
+
+	for enabled, chunkIDs := range statusGroups {
+		err := m.updateRows(ctx, (&milvusFilter{}).in(fieldChunkID, chunkIDs...), func(row *MilvusVectorEmbedding) {
+			row.IsEnabled = enabled
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Infof("[Milvus] Batch update chunk enabled status completed")
+	return nil
+}
+
+// BatchUpdateChunkTagID updates the tag ID of chunks in batch
+func (m *milvusRepository) BatchUpdateChunkTagID(ctx context.Context, chunkTagMap map[string]string) error {
+	log := logger.GetLogger(ctx)
+	if len(chunkTagMap) == 0 {
+		log.Warn("[Milvus] Empty chunk tag map provided, skipping")
+		return nil
+	}
+
+	log.Infof("[Milvus] Batch updating chunk tag ID, count: %d", len(chunkTagMap))
+
+	// Group chunks by tag ID for batch updates
+	tagGroups := make(map[string][]string)
+	for chunkID, tagID := range chunkTagMap {
+		tagGroups[tagID] = append(tagGroups[tagID], chunkID)
+	}
+
+	for tagID, chunkIDs := range tagGroups {
+		err := m.updateRows(ctx, (&milvusFilter{}).in(fieldChunkID, chunkIDs...), func(row *MilvusVectorEmbedding) {
+			row.TagID = tagID
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Infof("[Milvus] Batch update chunk tag ID completed")
+	return nil
+}
+
+// BatchUpdateKnowledgeMetadata replaces the custom metadata of all indices of the given knowledge
+func (m *milvusRepository) BatchUpdateKnowledgeMetadata(
+	ctx context.Context, knowledgeMetadataMap map[string]map[string]any,
+) error {
+	log := logger.GetLogger(ctx)
+	if len(knowledgeMetadataMap) == 0 {
+		log.Warn("[Milvus] Empty knowledge metadata map provided, skipping")
+		return nil
+	}
+
+	log.Infof("[Milvus] Batch updating knowledge metadata, count: %d", len(knowledgeMetadataMap))
+
+	for knowledgeID, metadata := range knowledgeMetadataMap {
+		err := m.updateRows(ctx, (&milvusFilter{}).in(fieldKnowledgeID, knowledgeID), func(row *MilvusVectorEmbedding) {
+			row.Metadata = metadata
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Infof("[Milvus] Batch update knowledge metadata completed")
+	return nil
+}
+
+// getBaseFilter builds the filter shared by all retrievals
+func (m *milvusRepository) getBaseFilter(params types.RetrieveParams) *milvusFilter {
+	// Only retrieve enabled chunks
+	filter := &milvusFilter{EnabledOnly: true}
+
+	// KnowledgeBaseIDs and KnowledgeIDs use AND logic
+	// - If only KnowledgeBaseIDs: search entire knowledge bases
+	// - If only KnowledgeIDs: search specific documents
+	// - If both: search specific documents within the knowledge bases (AND)
+	filter.in(fieldKnowledgeBaseID, params.KnowledgeBaseIDs...)
+	filter.in(fieldKnowledgeID, params.KnowledgeIDs...)
+	// Filter by tag IDs if specified
+	filter.in(fieldTagID, params.TagIDs...)
+	filter.notIn(fieldKnowledgeID, params.ExcludeKnowledgeIDs...)
+	filter.notIn(fieldChunkID, params.ExcludeChunkIDs...)
+
+	// Filter by custom knowledge metadata if specified
+	filter.MetadataFilters = params.MetadataFilters
+	return filter
+}
+
+// Retrieve dispatches the retrieval operation to the appropriate method based on retriever type
+func (m *milvusRepository) Retrieve(ctx context.Context,
+	params types.RetrieveParams,
+) ([]*types.RetrieveResult, error) {
+	log := logger.GetLogger(ctx)
+	log.Debugf("[Milvus] Processing retrieval request of type: %s", params.RetrieverType)
+
+	switch params.RetrieverType {
+	case types.VectorRetrieverType:
+		return m.VectorRetrieve(ctx, params)
+	}
+
+	err := fmt.Errorf("invalid retriever type: %v", params.RetrieverType)
+	log.Errorf("[Milvus] %v", err)
+	return nil, err
+}
+
+// VectorRetrieve performs vector similarity search
+func (m *milvusRepository) VectorRetrieve(ctx context.Context,
+	params types.RetrieveParams,
+) ([]*types.RetrieveResult, error) {
+	log := logger.GetLogger(ctx)
+	dimension := len(params.Embedding)
+	log.Infof("[Milvus] Vector retrieval: dim=%d, topK=%d, threshold=%.4f",
+		dimension, params.TopK, params.Threshold)
+
+	// Get collection name based on embedding dimension
+	collectionName := m.getCollectionName(dimension)
+
+	exists, err := m.collectionExists(ctx, dimension)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check collection: %w", err)
+	}
+	if !exists {
+		log.Warnf("[Milvus] Collection %s does not exist, returning empty results", collectionName)
+		return buildRetrieveResult(nil, types.VectorRetrieverType), nil
+	}
+
+	hits, err := m.client.Search(ctx, collectionName, params.Embedding, m.getBaseFilter(params), params.TopK)
+	if err != nil {
+		log.Errorf("[Milvus] Vector search failed: %v", err)
+		return nil, fmt.Errorf("%s: %w", collectionName, err)
+	}
+
+	var results []*types.IndexWithScore
+	for _, hit := range hits {
+		// Milvus has no score threshold for plain searches, COSINE scores are higher for closer vectors
+		if hit.Score < params.Threshold {
+			continue
+		}
+		results = append(results, fromMilvusVectorEmbedding(hit, types.MatchTypeEmbedding))
+	}
+
+	if len(results) == 0 {
+		log.Warnf("[Milvus] No vector matches found that meet threshold %.4f", params.Threshold)
+	} else {
+		log.Infof("[Milvus] Vector retrieval found %d results", len(results))
+		log.Debugf("[Milvus] Top result score: %.4f", results[0].Score)
+	}
+
+	return buildRetrieveResult(results, types.VectorRetrieverType), nil
+}
+
+// CopyIndices copies index data from source knowledge base to target knowledge base
+func (m *milvusRepository) CopyIndices(ctx context.Context,
+	sourceKnowledgeBaseID string,
+	sourceToTargetKBIDMap map[string]string,
+	sourceToTargetChunkIDMap map[string]string,
+	targetKnowledgeBaseID string,
+	dimension int,
+	knowledgeType string,
+) error {
+	log := logger.GetLogger(ctx)
+	log.Infof(
+		"[Milvus] Copying indices from source knowledge base %s to target knowledge base %s, count: %d, dimension: %d",
+		sourceKnowledgeBaseID, targetKnowledgeBaseID, len(sourceToTargetChunkIDMap), dimension,
+	)
+
+	if len(sourceToTargetChunkIDMap) == 0 {
+		log.Warn("[Milvus] Empty mapping, skipping copy")
+		return nil
+	}
+
+	collectionName := m.getCollectionName(dimension)
+
+	// Ensure target collection exists
+	if err := m.ensureCollection(ctx, dimension); err != nil {
+		return err
+	}
+
+	filter := (&milvusFilter{}).in(fieldKnowledgeBaseID, sourceKnowledgeBaseID)
+	afterID := ""
+	totalCopied := 0
+
+	for {
+		sourceRows, err := m.client.Query(ctx, collectionName, filter, afterID, batchSize, true)
+		if err != nil {
+			log.Errorf("[Milvus] Failed to query source rows: %v", err)
+			return err
+		}
+
+		rowsCount := len(sourceRows)
+		if rowsCount == 0 {
+			break
+		}
+
+		log.Infof("[Milvus] Found %d source rows in batch", rowsCount)
+
+		targetRows := make([]*MilvusVectorEmbedding, 0, rowsCount)
+		for _, sourceRow := range sourceRows {
+			targetChunkID, ok := sourceToTargetChunkIDMap[sourceRow.ChunkID]
+			if !ok {
+				log.Warnf("[Milvus] Source chunk %s not found in target mapping, skipping", sourceRow.ChunkID)
+				continue
+			}
+
+			targetKnowledgeID, ok := sourceToTargetKBIDMap[sourceRow.KnowledgeID]
+			if !ok {
+				log.Warnf("[Milvus] Source knowledge %s not found in target mapping, skipping", sourceRow.KnowledgeID)
+				continue
+			}
+
+			if len(sourceRow.Embedding) == 0 {
+				log.Warnf("[Milvus] No vector found for source row with chunk %s, skipping", sourceRow.ChunkID)
+				continue
+			}
+
+			// Handle SourceID transformation for generated questions
+			// Generated questions have SourceID format: {chunkID}-{questionID}
+			// Regular chunks have SourceID == ChunkID
+			var targetSourceID string
+			if sourceRow.SourceID == sourceRow.ChunkID {
+				// Regular chunk, use targetChunkID as SourceID
+				targetSourceID = targetChunkID
+			} else if strings.HasPrefix(sourceRow.SourceID, sourceRow.ChunkID+"-") {
+				// This is a generated question, preserve the questionID part
+				questionID := strings.TrimPrefix(sourceRow.SourceID, sourceRow.ChunkID+"-")
+				targetSourceID = fmt.Sprintf("%s-%s", targetChunkID, questionID)
+			} else {
+				// For other complex scenarios, generate new unique SourceID
+				targetSourceID = uuid.New().String()
+			}
+
+			targetRows = append(targetRows, &MilvusVectorEmbedding{
+				ID:              uuid.New().String(),
+				Content:         sourceRow.Content,
+				SourceID:        targetSourceID,
+				SourceType:      sourceRow.SourceType,
+				ChunkID:         targetChunkID,
+				KnowledgeID:     targetKnowledgeID,
+				KnowledgeBaseID: targetKnowledgeBaseID,
+				TagID:           sourceRow.TagID,
+				Embedding:       sourceRow.Embedding,
+				IsEnabled:       true,
+				Metadata:        sourceRow.Metadata,
+			})
+		}
+
+		if len(targetRows) > 0 {
+			if err := m.client.Upsert(ctx, collectionName, targetRows); err != nil {
+				log.Errorf("[Milvus] Failed to batch upsert target rows: %v", err)
+				return err
+			}
+
+			totalCopied += len(targetRows)
+			log.Infof("[Milvus] Successfully copied batch, batch size: %d, total copied: %d",
+				len(targetRows), totalCopied)
+		}
+
+		if rowsCount < batchSize {
+			break
+		}
+		afterID = sourceRows[rowsCount-1].ID
+	}
+
+	log.Infof("[Milvus] Index copy completed, total copied: %d", totalCopied)
+	return nil
+}
+
+func buildRetrieveResult(results []*types.IndexWithScore, retrieverType types.RetrieverType) []*types.RetrieveResult {
+	return []*types.RetrieveResult{
+		{
+			Results:             results,
+			RetrieverEngineType: types.MilvusRetrieverEngineType,
+			RetrieverType:       retrieverType,
+			Error:               nil,
+		},
+	}
+}
+
+// calculateStorageSize estimates the storage of a row: scalar fields, the raw vector and its HNSW graph
+func (m *milvusRepository) calculateStorageSize(embedding *MilvusVectorEmbedding) int64 {
+	// Scalar fields
+	scalarSizeBytes := int64(0)
+	scalarSizeBytes += 36                                    // id string
+	scalarSizeBytes += int64(len(embedding.Content))         // content string
+	scalarSizeBytes += int64(len(embedding.SourceID))        // source_id string
+	scalarSizeBytes += int64(len(embedding.ChunkID))         // chunk_id string
+	scalarSizeBytes += int64(len(embedding.KnowledgeID))     // knowledge_id string
+	scalarSizeBytes += int64(len(embedding.KnowledgeBaseID)) // knowledge_base_id string
+	scalarSizeBytes += int64(len(embedding.TagID))           // tag_id string
+	scalarSizeBytes += 8 + 1                                 // source_type int64, is_enabled bool
+
+	// Vector storage and index
+	var vectorSizeBytes int64 = 0
+	var hnswIndexBytes int64 = 0
+	if embedding.Embedding != nil {
+		dimensions := int64(len(embedding.Embedding))
+		vectorSizeBytes = dimensions * 4
+
+		// HNSW graph: M × 2 neighbor links of 8 bytes per row, AUTOINDEX uses M=16 on standalone deployments
+		const hnswM = 16
+		hnswIndexBytes = hnswM * 2 * 8
+	}
+
+	return scalarSizeBytes + vectorSizeBytes + hnswIndexBytes
+}
+
+// toMilvusVectorEmbedding converts IndexInfo to a Milvus row
+func toMilvusVectorEmbedding(embedding *types.IndexInfo, additionalParams map[string]interface{}) *MilvusVectorEmbedding {
+	vector := &MilvusVectorEmbedding{
+		Content:         truncateContent(embedding.Content),
+		SourceID:        embedding.SourceID,
+		SourceType:      int(embedding.SourceType),
+		ChunkID:         embedding.ChunkID,
+		KnowledgeID:     embedding.KnowledgeID,
+		KnowledgeBaseID: embedding.KnowledgeBaseID,
+		TagID:           embedding.TagID,
+		IsEnabled:       true, // Default to enabled
+		Metadata:        embedding.Metadata,
+	}
+	if additionalParams != nil && slices.Contains(slices.Collect(maps.Keys(additionalParams)), fieldEmbedding) {
+		if embeddingMap, ok := additionalParams[fieldEmbedding].(map[string][]float32); ok {
+			vector.Embedding = embeddingMap[embedding.SourceID]
+		}
+	}
+	return vector
+}
+
+// fromMilvusVectorEmbedding converts a Milvus row to IndexWithScore domain model
+func fromMilvusVectorEmbedding(embedding *MilvusVectorEmbeddingWithScore,
+	matchType types.MatchType,
+) *types.IndexWithScore {
+	return &types.IndexWithScore{
+		ID:              embedding.ID,
+		SourceID:        embedding.SourceID,
+		SourceType:      types.SourceType(embedding.SourceType),
+		ChunkID:         embedding.ChunkID,
+		KnowledgeID:     embedding.KnowledgeID,
+		KnowledgeBaseID: embedding.KnowledgeBaseID,
+		TagID:           embedding.TagID,
+		Content:         embedding.Content,
+		Score:           embedding.Score,
+		MatchType:       matchType,
+		IsEnabled:       embedding.IsEnabled,
+	}
+}
+
+// truncateContent cuts content to the Milvus varchar limit without splitting a UTF-8 character.
+// Only the matched content returned by retrieval is affected, chunks are read from the database.
+func truncateContent(content string) string {
+	if len(content) <= maxContentLength {
+		return content
+	}
+	cut := maxContentLength
+	for cut > 0 && !utf8.RuneStart(content[cut]) {
+		cut--
+	}
+	return content[:cut]
+}
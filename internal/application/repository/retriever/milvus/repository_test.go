@@ -0,0 +1,392 @@
+package milvus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+// fakeClient is an in-memory milvusClient evaluating filters the way Milvus does
+type fakeClient struct {
+	collections map[string]map[string]*MilvusVectorEmbedding
+	upsertErr   error
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{collections: make(map[string]map[string]*MilvusVectorEmbedding)}
+}
+
+func (f *fakeClient) HasCollection(ctx context.Context, collection string) (bool, error) {
+	_, ok := f.collections[collection]
+	return ok, nil
+}
+
+func (f *fakeClient) CreateCollection(ctx context.Context, collection string, dimension int) error {
+	f.collections[collection] = make(map[string]*MilvusVectorEmbedding)
+	return nil
+}
+
+func (f *fakeClient) ListCollections(ctx context.Context) ([]string, error) {
+	var names []string
+	for name := range f.collections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (f *fakeClient) Upsert(ctx context.Context, collection string, rows []*MilvusVectorEmbedding) error {
+	if f.upsertErr != nil {
+		return f.upsertErr
+	}
+	rowsByID, ok := f.collections[collection]
+	if !ok {
+		return fmt.Errorf("collection %s not found", collection)
+	}
+	for _, row := range rows {
+		copied := *row
+		rowsByID[row.ID] = &copied
+	}
+	return nil
+}
+
+func (f *fakeClient) Delete(ctx context.Context, collection string, filter *milvusFilter) error {
+	for id, row := range f.collections[collection] {
+		if matches(row, filter) {
+			delete(f.collections[collection], id)
+		}
+	}
+	return nil
+}
+
+func (f *fakeClient) Search(ctx context.Context, collection string, vector []float32,
+	filter *milvusFilter, topK int,
+) ([]*MilvusVectorEmbeddingWithScore, error) {
+	var results []*MilvusVectorEmbeddingWithScore
+	for _, row := range f.collections[collection] {
+		if matches(row, filter) {
+			results = append(results, &MilvusVectorEmbeddingWithScore{
+				MilvusVectorEmbedding: *row,
+				Score:                 cosine(vector, row.Embedding),
+			})
+		}
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	if len(results) > topK {
+		results = results[:topK]
+	}
+	return results, nil
+}
+
+func (f *fakeClient) Query(ctx context.Context, collection string, filter *milvusFilter,
+	afterID string, limit int, withVector bool,
+) ([]*MilvusVectorEmbedding, error) {
+	var rows []*MilvusVectorEmbedding
+	for _, row := range f.collections[collection] {
+		if row.ID > afterID && matches(row, filter) {
+			copied := *row
+			if !withVector {
+				copied.Embedding = nil
+			}
+			rows = append(rows, &copied)
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].ID < rows[j].ID })
+	if len(rows) > limit {
+		rows = rows[:limit]
+	}
+	return rows, nil
+}
+
+// matches evaluates a filter against a row
+func matches(row *MilvusVectorEmbedding, filter *milvusFilter) bool {
+	if filter.EnabledOnly && !row.IsEnabled {
+		return false
+	}
+	fields := map[string]string{
+		fieldChunkID: row.ChunkID, fieldKnowledgeID: row.KnowledgeID, fieldKnowledgeBaseID: row.KnowledgeBaseID,
+		fieldSourceID: row.SourceID, fieldTagID: row.TagID,
+	}
+	for _, term := range filter.Terms {
+		if slices.Contains(term.Values, fields[term.Field]) == term.Not {
+			return false
+		}
+	}
+	for _, mf := range filter.MetadataFilters {
+		value, exists := row.Metadata[mf.Key]
+		switch mf.Op {
+		case types.MetadataFilterOpEq:
+			if !exists || value != mf.Value {
+				return false
+			}
+		case types.MetadataFilterOpIn:
+			if !exists || !slices.Contains(mf.Values, value) {
+				return false
+			}
+		case types.MetadataFilterOpExists:
+			if !exists {
+				return false
+			}
+		case types.MetadataFilterOpRange:
+			number, ok := value.(float64)
+			if !ok ||
+				(mf.Gt != nil && number <= mf.Gt.(float64)) || (mf.Gte != nil && number < mf.Gte.(float64)) ||
+				(mf.Lt != nil && number >= mf.Lt.(float64)) || (mf.Lte != nil && number > mf.Lte.(float64)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func cosine(a, b []float32) float64 {
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// seedRepository saves chunk c1 (with a generated question), c2 and c3 in kb1, and a 3-dimension chunk c4
+func seedRepository(t *testing.T) (*milvusRepository, *fakeClient) {
+	t.Helper()
+	client := newFakeClient()
+	repo := newMilvusRepository(client, "test")
+	indices := []*types.IndexInfo{
+		{SourceID: "c1", ChunkID: "c1", KnowledgeID: "k1", KnowledgeBaseID: "kb1",
+			Content: "first", Metadata: map[string]any{"year": 2024.0}},
+		{SourceID: "c1-q1", ChunkID: "c1", KnowledgeID: "k1", KnowledgeBaseID: "kb1",
+			Content: "question", Metadata: map[string]any{"year": 2024.0}},
+		{SourceID: "c2", ChunkID: "c2", KnowledgeID: "k2", KnowledgeBaseID: "kb1", TagID: "tag1", Content: "second"},
+		{SourceID: "c3", ChunkID: "c3", KnowledgeID: "k2", KnowledgeBaseID: "kb1", Content: "third"},
+		{SourceID: "c4", ChunkID: "c4", KnowledgeID: "k3", KnowledgeBaseID: "kb2", Content: "other dimension"},
+	}
+	params := map[string]any{"embedding": map[string][]float32{
+		"c1": {1, 0}, "c1-q1": {0.9, 0.1}, "c2": {0.6, 0.8}, "c3": {0, 1}, "c4": {1, 0, 0},
+	}}
+	if err := repo.BatchSave(context.Background(), indices, params); err != nil {
+		t.Fatalf("BatchSave: %v", err)
+	}
+	return repo, client
+}
+
+func retrieveChunkIDs(t *testing.T, repo *milvusRepository, params types.RetrieveParams) []string {
+	t.Helper()
+	params.RetrieverType = types.VectorRetrieverType
+	results, err := repo.Retrieve(context.Background(), params)
+	if err != nil {
+		t.Fatalf("Retrieve: %v", err)
+	}
+	var chunkIDs []string
+	for _, r := range results[0].Results {
+		chunkIDs = append(chunkIDs, r.SourceID)
+	}
+	return chunkIDs
+}
+
+func TestBatchSavePartitionsByDimension(t *testing.T) {
+	_, client := seedRepository(t)
+	if got := len(client.collections["test_2"]); got != 4 {
+		t.Errorf("test_2 has %d rows, want 4", got)
+	}
+	if got := len(client.collections["test_3"]); got != 1 {
+		t.Errorf("test_3 has %d rows, want 1", got)
+	}
+}
+
+func TestVectorRetrieve(t *testing.T) {
+	repo, _ := seedRepository(t)
+	tests := []struct {
+		name   string
+		params types.RetrieveParams
+		want   []string
+	}{
+		{
+			name:   "threshold and knowledge base",
+			params: types.RetrieveParams{Embedding: []float32{1, 0}, TopK: 10, Threshold: 0.5, KnowledgeBaseIDs: []string{"kb1"}},
+			want:   []string{"c1", "c1-q1", "c2"},
+		},
+		{
+			name:   "tag",
+			params: types.RetrieveParams{Embedding: []float32{1, 0}, TopK: 10, TagIDs: []string{"tag1"}},
+			want:   []string{"c2"},
+		},
+		{
+			name: "metadata and excluded chunks",
+			params: types.RetrieveParams{
+				Embedding: []float32{1, 0}, TopK: 10, ExcludeChunkIDs: []string{"c2"},
+				MetadataFilters: types.MetadataFilters{{Key: "year", Op: types.MetadataFilterOpRange, Gte: 2020.0}},
+			},
+			want: []string{"c1", "c1-q1"},
+		},
+		{
+			name:   "missing dimension",
+			params: types.RetrieveParams{Embedding: []float32{1, 0, 0, 0}, TopK: 10},
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retrieveChunkIDs(t, repo, tt.params); !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := repo.Retrieve(context.Background(), types.RetrieveParams{RetrieverType: types.KeywordsRetrieverType}); err == nil {
+		t.Error("keyword retrieval should not be supported")
+	}
+}
+
+func TestBatchUpdates(t *testing.T) {
+	repo, _ := seedRepository(t)
+	ctx := context.Background()
+	if err := repo.BatchUpdateChunkEnabledStatus(ctx, map[string]bool{"c1": false}); err != nil {
+		t.Fatalf("BatchUpdateChunkEnabledStatus: %v", err)
+	}
+	if err := repo.BatchUpdateChunkTagID(ctx, map[string]string{"c3": "tag1", "c2": ""}); err != nil {
+		t.Fatalf("BatchUpdateChunkTagID: %v", err)
+	}
+
+	got := retrieveChunkIDs(t, repo, types.RetrieveParams{Embedding: []float32{1, 0}, TopK: 10})
+	if want := []string{"c2", "c3"}; !slices.Equal(got, want) {
+		t.Errorf("after disabling c1 got %v, want %v", got, want)
+	}
+	got = retrieveChunkIDs(t, repo, types.RetrieveParams{Embedding: []float32{1, 0}, TopK: 10, TagIDs: []string{"tag1"}})
+	if want := []string{"c3"}; !slices.Equal(got, want) {
+		t.Errorf("after retagging got %v, want %v", got, want)
+	}
+}
+
+func TestBatchUpdatesReturnWriteErrors(t *testing.T) {
+	repo, client := seedRepository(t)
+	ctx := context.Background()
+	client.upsertErr = fmt.Errorf("rejected")
+	if err := repo.BatchUpdateChunkEnabledStatus(ctx, map[string]bool{"c1": false}); err == nil {
+		t.Error("BatchUpdateChunkEnabledStatus should fail when the upsert is rejected")
+	}
+	if err := repo.BatchUpdateChunkTagID(ctx, map[string]string{"c3": "tag1"}); err == nil {
+		t.Error("BatchUpdateChunkTagID should fail when the upsert is rejected")
+	}
+	if err := repo.BatchUpdateKnowledgeMetadata(ctx, map[string]map[string]any{"k1": {"year": 2025.0}}); err == nil {
+		t.Error("BatchUpdateKnowledgeMetadata should fail when the upsert is rejected")
+	}
+}
+
+func TestCopyIndices(t *testing.T) {
+	repo, client := seedRepository(t)
+	ctx := context.Background()
+	if err := repo.BatchUpdateChunkEnabledStatus(ctx, map[string]bool{"c2": false}); err != nil {
+		t.Fatalf("BatchUpdateChunkEnabledStatus: %v", err)
+	}
+	err := repo.CopyIndices(ctx, "kb1",
+		map[string]string{"k1": "nk1", "k2": "nk2"},
+		map[string]string{"c1": "n1", "c2": "n2"},
+		"kb9", 2, "manual",
+	)
+	if err != nil {
+		t.Fatalf("CopyIndices: %v", err)
+	}
+
+	copied := map[string]*MilvusVectorEmbedding{}
+	for _, row := range client.collections["test_2"] {
+		if row.KnowledgeBaseID == "kb9" {
+			copied[row.SourceID] = row
+		}
+	}
+	if len(copied) != 3 {
+		t.Fatalf("copied %d rows, want 3", len(copied))
+	}
+	if row := copied["n1-q1"]; row == nil || row.ChunkID != "n1" || row.Metadata["year"] != 2024.0 {
+		t.Errorf("generated question not copied correctly: %+v", row)
+	}
+	if row := copied["n2"]; row == nil || !row.IsEnabled || row.TagID != "tag1" || row.KnowledgeID != "nk2" {
+		t.Errorf("chunk not copied correctly: %+v", row)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	repo, client := seedRepository(t)
+	ctx := context.Background()
+	if err := repo.DeleteByChunkIDList(ctx, []string{"c1"}, 2, ""); err != nil {
+		t.Fatalf("DeleteByChunkIDList: %v", err)
+	}
+	if err := repo.DeleteByKnowledgeIDList(ctx, []string{"k3"}, 3, ""); err != nil {
+		t.Fatalf("DeleteByKnowledgeIDList: %v", err)
+	}
+	if err := repo.DeleteBySourceIDList(ctx, []string{"c3"}, 8, ""); err != nil {
+		t.Fatalf("deleting from a missing collection should be a no-op: %v", err)
+	}
+	if got := len(client.collections["test_2"]); got != 2 {
+		t.Errorf("test_2 has %d rows, want 2", got)
+	}
+	if got := len(client.collections["test_3"]); got != 0 {
+		t.Errorf("test_3 has %d rows, want 0", got)
+	}
+}
+
+func TestFilterExpr(t *testing.T) {
+	filter := (&milvusFilter{EnabledOnly: true}).
+		in(fieldKnowledgeBaseID, "kb1", `kb"2`).
+		in(fieldTagID).
+		notIn(fieldChunkID, "c1")
+	filter.MetadataFilters = types.MetadataFilters{
+		{Key: "lang", Op: types.MetadataFilterOpIn, Values: []any{"en", 1.5}},
+		{Key: "author", Op: types.MetadataFilterOpExists},
+		{Key: "date", Op: types.MetadataFilterOpRange, Gt: "2024-01-01", Lte: "2024-12-31"},
+	}
+	want := `is_enabled == true and knowledge_base_id in ["kb1", "kb\"2"] and chunk_id not in ["c1"]` +
+		` and (metadata["lang"] == "en" or metadata["lang"] == 1.5) and exists metadata["author"]` +
+		` and metadata["date"] > "2024-01-01" and metadata["date"] <= "2024-12-31"`
+	if got := filter.Expr(); got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestRESTClientQuery(t *testing.T) {
+	var request map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/vectordb/entities/query" || r.Header.Get("Authorization") != "Bearer root:secret" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		_, _ = w.Write([]byte(`{"code":0,"data":[{"id":"b","chunk_id":"c1","is_enabled":true,"metadata":"{\"year\":2024}"}]}`))
+	}))
+	defer server.Close()
+
+	client := newRESTClient(ClientConfig{Address: server.URL, Token: "root:secret", DBName: "db"})
+	rows, err := client.Query(context.Background(), "test_2",
+		(&milvusFilter{}).in(fieldChunkID, "c1"), "a", 10, false)
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(rows) != 1 || rows[0].ID != "b" || rows[0].Metadata["year"] != 2024.0 {
+		t.Errorf("unexpected rows: %+v", rows)
+	}
+	if request["filter"] != `(chunk_id in ["c1"]) and id > "a"` || request["dbName"] != "db" {
+		t.Errorf("unexpected request: %v", request)
+	}
+}
+
+func TestRESTClientError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"code":1100,"message":"collection not found"}`))
+	}))
+	defer server.Close()
+
+	client := newRESTClient(ClientConfig{Address: server.URL})
+	if _, err := client.HasCollection(context.Background(), "test_2"); err == nil {
+		t.Error("expected an error for a nonzero response code")
+	}
+}
@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+// buildMetadataFilterConditions converts metadata filters into SQL conditions on the jsonb metadata column.
+// placeholder appends a bind variable and returns its placeholder, so the same conditions can be used
+// with both gorm ("?") and raw ("$n") queries. Filters must be validated before calling.
+func buildMetadataFilterConditions(filters types.MetadataFilters, placeholder func(v any) string) []string {
+	conditions := make([]string, 0, len(filters))
+	for i := range filters {
+		filter := &filters[i]
+		switch filter.Op {
+		case types.MetadataFilterOpEq:
+			conditions = append(conditions, metadataContainsCondition(filter.Key, filter.Value, placeholder))
+		case types.MetadataFilterOpIn:
+			parts := make([]string, len(filter.Values))
+			for j, value := range filter.Values {
+				parts[j] = metadataContainsCondition(filter.Key, value, placeholder)
+			}
+			conditions = append(conditions, "("+strings.Join(parts, " OR ")+")")
+		case types.MetadataFilterOpExists:
+			conditions = append(conditions, fmt.Sprintf("jsonb_exists(metadata, %s)", placeholder(filter.Key)))
+		case types.MetadataFilterOpRange:
+			conditions = append(conditions, metadataRangeCondition(filter, placeholder))
+		}
+	}
+	return conditions
+}
+
+// metadataContainsCondition matches a single key/value pair using the GIN-indexed containment operator
+func metadataContainsCondition(key string, value any, placeholder func(v any) string) string {
+	doc, _ := json.Marshal(map[string]any{key: value})
+	return fmt.Sprintf("metadata @> %s::jsonb", placeholder(string(doc)))
+}
+
+// metadataRangeCondition compares numbers numerically and strings lexically
+func metadataRangeCondition(filter *types.MetadataFilter, placeholder func(v any) string) string {
+	// The field expression is rebuilt for every bound, since "?" placeholders cannot be reused
+	field := func() string {
+		if filter.IsNumericRange() {
+			return fmt.Sprintf(
+				"(CASE WHEN jsonb_typeof(metadata -> %s) = 'number' THEN (metadata ->> %s)::numeric END)",
+				placeholder(filter.Key), placeholder(filter.Key),
+			)
+		}
+		return fmt.Sprintf("(metadata ->> %s)", placeholder(filter.Key))
+	}
+
+	bounds := []struct {
+		op    string
+		value any
+	}{
+		{">", filter.Gt},
+		{">=", filter.Gte},
+		{"<", filter.Lt},
+		{"<=", filter.Lte},
+	}
+	parts := make([]string, 0, len(bounds))
+	for _, bound := range bounds {
+		if bound.value == nil {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s %s %s", field(), bound.op, placeholder(bound.value)))
+	}
+	return "(" + strings.Join(parts, " AND ") + ")"
+}
@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+func TestBuildMetadataFilterConditions(t *testing.T) {
+	tests := []struct {
+		name         string
+		filters      types.MetadataFilters
+		expectedSQL  []string
+		expectedVars []any
+	}{
+		{
+			name:         "eq",
+			filters:      types.MetadataFilters{{Key: "department", Op: types.MetadataFilterOpEq, Value: "hr"}},
+			expectedSQL:  []string{"metadata @> $1::jsonb"},
+			expectedVars: []any{`{"department":"hr"}`},
+		},
+		{
+			name:    "in",
+			filters: types.MetadataFilters{{Key: "year", Op: types.MetadataFilterOpIn, Values: []any{2024, 2025}}},
+			expectedSQL: []string{
+				"(metadata @> $1::jsonb OR metadata @> $2::jsonb)",
+			},
+			expectedVars: []any{`{"year":2024}`, `{"year":2025}`},
+		},
+		{
+			name:         "exists",
+			filters:      types.MetadataFilters{{Key: "author", Op: types.MetadataFilterOpExists}},
+			expectedSQL:  []string{"jsonb_exists(metadata, $1)"},
+			expectedVars: []any{"author"},
+		},
+		{
+			name:    "numeric range",
+			filters: types.MetadataFilters{{Key: "version", Op: types.MetadataFilterOpRange, Gte: 2, Lt: 5}},
+			expectedSQL: []string{
+				"((CASE WHEN jsonb_typeof(metadata -> $1) = 'number' THEN (metadata ->> $2)::numeric END) >= $3 AND " +
+					"(CASE WHEN jsonb_typeof(metadata -> $4) = 'number' THEN (metadata ->> $5)::numeric END) < $6)",
+			},
+			expectedVars: []any{"version", "version", 2.0, "version", "version", 5.0},
+		},
+		{
+			name: "string range and eq combined",
+			filters: types.MetadataFilters{
+				{Key: "published_at", Op: types.MetadataFilterOpRange, Gt: "2024-01-01"},
+				{Key: "public", Op: types.MetadataFilterOpEq, Value: true},
+			},
+			expectedSQL: []string{
+				"((metadata ->> $1) > $2)",
+				"metadata @> $3::jsonb",
+			},
+			expectedVars: []any{"published_at", "2024-01-01", `{"public":true}`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters, err := tt.filters.Normalize()
+			if err != nil {
+				t.Fatalf("Normalize() error = %v", err)
+			}
+			var vars []any
+			placeholder := func(v any) string {
+				vars = append(vars, v)
+				return fmt.Sprintf("$%d", len(vars))
+			}
+			sql := buildMetadataFilterConditions(filters, placeholder)
+			if !reflect.DeepEqual(sql, tt.expectedSQL) {
+				t.Errorf("conditions = %v, want %v", sql, tt.expectedSQL)
+			}
+			if !reflect.DeepEqual(vars, tt.expectedVars) {
+				t.Errorf("vars = %v, want %v", vars, tt.expectedVars)
+			}
+		})
+	}
+}
+
+func TestMetadataFiltersNormalize(t *testing.T) {
+	invalid := []types.MetadataFilters{
+		{{Key: "bad key", Op: types.MetadataFilterOpExists}},
+		{{Key: "k", Op: "like", Value: "x"}},
+		{{Key: "k", Op: types.MetadataFilterOpEq}},
+		{{Key: "k", Op: types.MetadataFilterOpIn}},
+		{{Key: "k", Op: types.MetadataFilterOpRange}},
+		{{Key: "k", Op: types.MetadataFilterOpRange, Gt: 1, Lt: "z"}},
+	}
+	for i, filters := range invalid {
+		if _, err := filters.Normalize(); err == nil {
+			t.Errorf("case %d: expected validation error for %+v", i, filters)
+		}
+	}
+}
@@ -193,6 +193,17 @@ func (g *pgRepository) KeywordsRetrieve(ctx context.Context,
 			Values: common.ToInterfaceSlice(params.TagIDs),
 		})
 	}
+	// Filter by custom knowledge metadata if specified
+	if len(params.MetadataFilters) > 0 {
+		logger.GetLogger(ctx).Debugf("[Postgres] Filtering by metadata: %+v", params.MetadataFilters)
+		var vars []interface{}
+		placeholder := func(v any) string {
+			vars = append(vars, v)
+			return "?"
+		}
+		conditions := buildMetadataFilterConditions(params.MetadataFilters, placeholder)
+		conds = append(conds, clause.Expr{SQL: strings.Join(conditions, " AND "), Vars: vars})
+	}
 	conds = append(conds, clause.Expr{
 		SQL:  "id @@@ paradedb.match(field => 'content', value => ?, distance => 1)",
 		Vars: []interface{}{params.Query},
@@ -318,6 +329,16 @@ func (g *pgRepository) VectorRetrieve(ctx context.Context,
 			strings.Join(placeholders, ", ")))
 	}
 
+	// Filter by custom knowledge metadata if specified
+	if len(params.MetadataFilters) > 0 {
+		logger.GetLogger(ctx).Debugf("[Postgres] Filtering vector search by metadata: %+v", params.MetadataFilters)
+		placeholder := func(v any) string {
+			allVars = append(allVars, v)
+			return fmt.Sprintf("$%d", len(allVars))
+		}
+		whereParts = append(whereParts, buildMetadataFilterConditions(params.MetadataFilters, placeholder)...)
+	}
+
 	// is_enabled filter
 	whereParts = append(whereParts, fmt.Sprintf("(is_enabled IS NULL OR is_enabled = $%d)", len(allVars)+1))
 	allVars = append(allVars, true)
@@ -501,6 +522,7 @@ func (g *pgRepository) CopyIndices(ctx context.Context,
 				KnowledgeBaseID: targetKnowledgeBaseID, // Update to target knowledge base ID
 				Dimension:       sourceVector.Dimension,
 				Embedding:       sourceVector.Embedding, // Copy the vector embedding directly, avoid recalculation
+				Metadata:        sourceVector.Metadata,
 			}
 
 			targetVectors = append(targetVectors, targetVector)
@@ -617,3 +639,30 @@ func (g *pgRepository) BatchUpdateChunkTagID(ctx context.Context, chunkTagMap ma
 	logger.GetLogger(ctx).Infof("[Postgres] Successfully batch updated chunk tag ID")
 	return nil
 }
+
+// BatchUpdateKnowledgeMetadata replaces the custom metadata of all indices of the given knowledge
+func (g *pgRepository) BatchUpdateKnowledgeMetadata(
+	ctx context.Context, knowledgeMetadataMap map[string]map[string]any,
+) error {
+	if len(knowledgeMetadataMap) == 0 {
+		logger.GetLogger(ctx).Warnf("[Postgres] Knowledge metadata map is empty, skipping update")
+		return nil
+	}
+
+	logger.GetLogger(ctx).Infof("[Postgres] Batch updating knowledge metadata, count: %d", len(knowledgeMetadataMap))
+
+	for knowledgeID, metadata := range knowledgeMetadataMap {
+		result := g.db.WithContext(ctx).Model(&pgVector{}).
+			Where("knowledge_id = ?", knowledgeID).
+			Update("metadata", pgMetadata(metadata))
+		if result.Error != nil {
+			logger.GetLogger(ctx).Errorf("[Postgres] Failed to update metadata of knowledge %s: %v", knowledgeID, result.Error)
+			return result.Error
+		}
+		logger.GetLogger(ctx).
+			Infof("[Postgres] Updated metadata of knowledge %s, rows affected: %d", knowledgeID, result.RowsAffected)
+	}
+
+	logger.GetLogger(ctx).Infof("[Postgres] Successfully batch updated knowledge metadata")
+	return nil
+}
@@ -1,6 +1,9 @@
 package postgres
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"maps"
 	"slices"
 	"strconv"
@@ -26,6 +29,7 @@ type pgVector struct {
 	Dimension       int                 `json:"dimension"         gorm:"column:dimension;not null"`
 	Embedding       pgvector.HalfVector `json:"embedding"         gorm:"column:embedding;not null"`
 	IsEnabled       bool                `json:"is_enabled"        gorm:"column:is_enabled;default:true;index"`
+	Metadata        pgMetadata          `json:"metadata"          gorm:"column:metadata;type:jsonb"`
 }
 
 // pgVectorWithScore extends pgVector with similarity score field
@@ -43,9 +47,38 @@ type pgVectorWithScore struct {
 	Dimension       int                 `json:"dimension"         gorm:"column:dimension;not null"`
 	Embedding       pgvector.HalfVector `json:"embedding"         gorm:"column:embedding;not null"`
 	IsEnabled       bool                `json:"is_enabled"        gorm:"column:is_enabled;default:true;index"`
+	Metadata        pgMetadata          `json:"metadata"          gorm:"column:metadata;type:jsonb"`
 	Score           float64             `json:"score"             gorm:"column:score"`
 }
 
+// pgMetadata stores the custom knowledge metadata in the jsonb metadata column
+type pgMetadata map[string]any
+
+// Value implements the driver.Valuer interface, empty metadata is stored as NULL
+func (m pgMetadata) Value() (driver.Value, error) {
+	if len(m) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(map[string]any(m))
+}
+
+// Scan implements the sql.Scanner interface, NULL is scanned as empty metadata
+func (m *pgMetadata) Scan(value any) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*m = nil
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported metadata type %T", value)
+	}
+	return json.Unmarshal(data, m)
+}
+
 // TableName specifies the database table name for pgVectorWithScore
 func (pgVectorWithScore) TableName() string {
 	return "embeddings"
@@ -67,6 +100,7 @@ func toDBVectorEmbedding(indexInfo *types.IndexInfo, additionalParams map[string
 		TagID:           indexInfo.TagID,
 		Content:         common.CleanInvalidUTF8(indexInfo.Content),
 		IsEnabled:       true, // Default to enabled
+		Metadata:        pgMetadata(indexInfo.Metadata),
 	}
 	// Add embedding data if available in additionalParams
 	if additionalParams != nil && slices.Contains(slices.Collect(maps.Keys(additionalParams)), "embedding") {
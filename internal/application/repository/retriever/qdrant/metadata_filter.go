@@ -0,0 +1,99 @@
+package qdrant
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/qdrant/go-client/qdrant"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// metadataDateLayouts are the accepted layouts for string range bounds, Qdrant only supports datetime ranges on strings
+var metadataDateLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"}
+
+// buildMetadataConditions converts metadata filters into Qdrant conditions on the nested metadata payload.
+// Filters must be validated before calling.
+func buildMetadataConditions(filters types.MetadataFilters) ([]*qdrant.Condition, error) {
+	conditions := make([]*qdrant.Condition, 0, len(filters))
+	for i := range filters {
+		filter := &filters[i]
+		field := fieldMetadata + "." + filter.Key
+		switch filter.Op {
+		case types.MetadataFilterOpEq:
+			conditions = append(conditions, metadataValueCondition(field, filter.Value))
+		case types.MetadataFilterOpIn:
+			should := make([]*qdrant.Condition, len(filter.Values))
+			for j, value := range filter.Values {
+				should[j] = metadataValueCondition(field, value)
+			}
+			conditions = append(conditions, qdrant.NewFilterAsCondition(&qdrant.Filter{Should: should}))
+		case types.MetadataFilterOpExists:
+			conditions = append(conditions, qdrant.NewFilterAsCondition(&qdrant.Filter{
+				MustNot: []*qdrant.Condition{qdrant.NewIsEmpty(field)},
+			}))
+		case types.MetadataFilterOpRange:
+			if filter.IsNumericRange() {
+				conditions = append(conditions, qdrant.NewRange(field, &qdrant.Range{
+					Gt:  metadataFloatBound(filter.Gt),
+					Gte: metadataFloatBound(filter.Gte),
+					Lt:  metadataFloatBound(filter.Lt),
+					Lte: metadataFloatBound(filter.Lte),
+				}))
+				continue
+			}
+			dateRange := &qdrant.DatetimeRange{}
+			var err error
+			if dateRange.Gt, err = metadataDateBound(filter.Gt); err != nil {
+				return nil, err
+			}
+			if dateRange.Gte, err = metadataDateBound(filter.Gte); err != nil {
+				return nil, err
+			}
+			if dateRange.Lt, err = metadataDateBound(filter.Lt); err != nil {
+				return nil, err
+			}
+			if dateRange.Lte, err = metadataDateBound(filter.Lte); err != nil {
+				return nil, err
+			}
+			conditions = append(conditions, qdrant.NewDatetimeRange(field, dateRange))
+		}
+	}
+	return conditions, nil
+}
+
+// metadataValueCondition matches a single metadata value by its type
+func metadataValueCondition(field string, value any) *qdrant.Condition {
+	switch v := value.(type) {
+	case string:
+		return qdrant.NewMatchKeyword(field, v)
+	case bool:
+		return qdrant.NewMatchBool(field, v)
+	case float64:
+		// Match numbers by range so that both integer and float payloads are matched
+		return qdrant.NewRange(field, &qdrant.Range{Gte: &v, Lte: &v})
+	}
+	return qdrant.NewMatchKeyword(field, fmt.Sprintf("%v", value))
+}
+
+// metadataFloatBound converts a numeric range bound
+func metadataFloatBound(value any) *float64 {
+	if v, ok := value.(float64); ok {
+		return &v
+	}
+	return nil
+}
+
+// metadataDateBound parses a string range bound as a datetime
+func metadataDateBound(value any) (*timestamppb.Timestamp, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, nil
+	}
+	for _, layout := range metadataDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return timestamppb.New(t), nil
+		}
+	}
+	return nil, fmt.Errorf("qdrant only supports numeric or datetime metadata ranges, got %q", s)
+}
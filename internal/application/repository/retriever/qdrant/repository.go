@@ -10,6 +10,7 @@ import (
 	"unicode/utf8"
 
 	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/searchutil"
 	"github.com/Tencent/WeKnora/internal/types"
 	"github.com/Tencent/WeKnora/internal/types/interfaces"
 	"github.com/google/uuid"
@@ -28,6 +29,7 @@ const (
 	fieldTagID            = "tag_id"
 	fieldEmbedding        = "embedding"
 	fieldIsEnabled        = "is_enabled"
+	fieldMetadata         = "metadata"
 )
 
 // NewQdrantRetrieveEngineRepository creates and initializes a new Qdrant repository
@@ -479,7 +481,58 @@ func (q *qdrantRepository) BatchUpdateChunkTagID(ctx context.Context, chunkTagMa
 	return nil
 }
 
-func (q *qdrantRepository) getBaseFilter(params types.RetrieveParams) *qdrant.Filter {
+// BatchUpdateKnowledgeMetadata replaces the custom metadata of all indices of the given knowledge
+func (q *qdrantRepository) BatchUpdateKnowledgeMetadata(
+	ctx context.Context, knowledgeMetadataMap map[string]map[string]any,
+) error {
+	log := logger.GetLogger(ctx)
+	if len(knowledgeMetadataMap) == 0 {
+		log.Warn("[Qdrant] Empty knowledge metadata map provided, skipping")
+		return nil
+	}
+
+	log.Infof("[Qdrant] Batch updating knowledge metadata, count: %d", len(knowledgeMetadataMap))
+
+	// Get all collections that match our base name pattern
+	collections, err := q.client.ListCollections(ctx)
+	if err != nil {
+		log.Errorf("[Qdrant] Failed to list collections: %v", err)
+		return fmt.Errorf("failed to list collections: %w", err)
+	}
+
+	// Update in all matching collections
+	for _, collectionName := range collections {
+		// Only process collections that start with our base name
+		if len(collectionName) <= len(q.collectionBaseName) ||
+			collectionName[:len(q.collectionBaseName)] != q.collectionBaseName {
+			continue
+		}
+
+		for knowledgeID, metadata := range knowledgeMetadataMap {
+			if metadata == nil {
+				metadata = map[string]any{}
+			}
+			// SetPayload merges top-level keys, so the nested metadata object is replaced as a whole
+			_, err := q.client.SetPayload(ctx, &qdrant.SetPayloadPoints{
+				CollectionName: collectionName,
+				Payload:        qdrant.NewValueMap(map[string]any{fieldMetadata: metadata}),
+				PointsSelector: qdrant.NewPointsSelectorFilter(&qdrant.Filter{
+					Must: []*qdrant.Condition{
+						qdrant.NewMatch(fieldKnowledgeID, knowledgeID),
+					},
+				}),
+			})
+			if err != nil {
+				log.Warnf("[Qdrant] Failed to update metadata of knowledge %s in %s: %v", knowledgeID, collectionName, err)
+			}
+		}
+	}
+
+	log.Infof("[Qdrant] Batch update knowledge metadata completed")
+	return nil
+}
+
+func (q *qdrantRepository) getBaseFilter(params types.RetrieveParams) (*qdrant.Filter, error) {
 	must := make([]*qdrant.Condition, 0)
 	mustNot := make([]*qdrant.Condition, 0)
 
@@ -501,6 +554,15 @@ func (q *qdrantRepository) getBaseFilter(params types.RetrieveParams) *qdrant.Fi
 		must = append(must, qdrant.NewMatchKeywords(fieldTagID, params.TagIDs...))
 	}
 
+	// Filter by custom knowledge metadata if specified
+	if len(params.MetadataFilters) > 0 {
+		metadataConditions, err := buildMetadataConditions(params.MetadataFilters)
+		if err != nil {
+			return nil, err
+		}
+		must = append(must, metadataConditions...)
+	}
+
 	if len(params.ExcludeKnowledgeIDs) > 0 {
 		mustNot = append(mustNot, qdrant.NewMatchKeywords(fieldKnowledgeID, params.ExcludeKnowledgeIDs...))
 	}
@@ -514,7 +576,7 @@ func (q *qdrantRepository) getBaseFilter(params types.RetrieveParams) *qdrant.Fi
 		MustNot: mustNot,
 	}
 
-	return filter
+	return filter, nil
 }
 
 // Retrieve dispatches the retrieval operation to the appropriate method based on retriever type
@@ -559,7 +621,11 @@ func (q *qdrantRepository) VectorRetrieve(ctx context.Context,
 		return buildRetrieveResult(nil, types.VectorRetrieverType), nil
 	}
 
-	filter := q.getBaseFilter(params)
+	filter, err := q.getBaseFilter(params)
+	if err != nil {
+		log.Errorf("[Qdrant] Invalid retrieval filter: %v", err)
+		return nil, err
+	}
 
 	limit := uint64(params.TopK)
 	scoreThreshold := float32(params.Threshold)
@@ -641,7 +707,11 @@ func (q *qdrantRepository) KeywordsRetrieve(ctx context.Context,
 			continue
 		}
 
-		filter := q.getBaseFilter(params)
+		filter, err := q.getBaseFilter(params)
+		if err != nil {
+			log.Errorf("[Qdrant] Invalid retrieval filter: %v", err)
+			return nil, err
+		}
 
 		// Build should conditions for each token (OR logic)
 		// This allows matching documents that contain any of the query tokens
@@ -806,6 +876,9 @@ func (q *qdrantRepository) CopyIndices(ctx context.Context,
 				fieldKnowledgeBaseID: targetKnowledgeBaseID,
 				fieldIsEnabled:       true,
 			})
+			if metadata, ok := payload[fieldMetadata]; ok {
+				newPayload[fieldMetadata] = metadata
+			}
 
 			var vectors *qdrant.Vectors
 			if vectorOutput := sourcePoint.Vectors.GetVector(); vectorOutput != nil {
@@ -867,6 +940,9 @@ func createPayload(embedding *QdrantVectorEmbedding) map[string]*qdrant.Value {
 		fieldTagID:           embedding.TagID,
 		fieldIsEnabled:       embedding.IsEnabled,
 	}
+	if len(embedding.Metadata) > 0 {
+		payload[fieldMetadata] = embedding.Metadata
+	}
 	return qdrant.NewValueMap(payload)
 }
 
@@ -924,6 +1000,7 @@ func toQdrantVectorEmbedding(embedding *types.IndexInfo, additionalParams map[st
 		KnowledgeBaseID: embedding.KnowledgeBaseID,
 		TagID:           embedding.TagID,
 		IsEnabled:       true, // Default to enabled
+		Metadata:        embedding.Metadata,
 	}
 	if additionalParams != nil && slices.Contains(slices.Collect(maps.Keys(additionalParams)), fieldEmbedding) {
 		if embeddingMap, ok := additionalParams[fieldEmbedding].(map[string][]float32); ok {
@@ -953,13 +1030,19 @@ func fromQdrantVectorEmbedding(id string,
 }
 
 // tokenizeQuery splits a query string into tokens for OR-based full-text search.
-// It uses jieba for professional Chinese word segmentation.
+// It uses jieba for professional Chinese word segmentation, and a Korean-aware
+// tokenizer stripping particles for queries with Korean.
 func tokenizeQuery(query string) []string {
 	query = strings.TrimSpace(query)
 	if query == "" {
 		return nil
 	}
 
+	// jieba does not split Korean words from their particles
+	if searchutil.ContainsHangul(query) {
+		return searchutil.TokenizeKorean(query)
+	}
+
 	// Use jieba for segmentation (search mode for better recall)
 	words := types.Jieba.CutForSearch(query, true)
 
@@ -294,6 +294,8 @@ func (s *agentService) registerTools(
 				s.webSearchStateService,
 				sessionID,
 				config.WebSearchMaxResults,
+				config.WebSearchAllowlist,
+				config.WebSearchTrustWeights,
 			)
 			logger.Infof(ctx, "Registered web_search tool for session: %s, maxResults: %d", sessionID, config.WebSearchMaxResults)
 
@@ -375,7 +377,7 @@ func (s *agentService) getKnowledgeBaseInfos(ctx context.Context, kbIDs []string
 			pageResult, err := s.knowledgeService.ListFAQEntries(ctx, kbID, &types.Pagination{
 				Page:     1,
 				PageSize: 10,
-			}, 0, "", "", "")
+			}, 0, "", "", "", types.FAQReviewStatusPublished)
 			if err == nil && pageResult != nil {
 				docCount = int(pageResult.Total)
 				if entries, ok := pageResult.Data.([]*types.FAQEntry); ok {
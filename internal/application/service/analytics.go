@@ -0,0 +1,424 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	werrors "github.com/Tencent/WeKnora/internal/errors"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+)
+
+const (
+	// defaultTopHitLimit is the number of top hit entries listed when no limit is given
+	defaultTopHitLimit = 20
+	// maxTopHitLimit is the largest number of top hit entries listed at once
+	maxTopHitLimit = 100
+	// analyticsTitleRunes is the length of the chunk beginning used as title of document chunks
+	analyticsTitleRunes = 80
+)
+
+// analyticsService records how knowledge is used in chats and reports it per knowledge base
+type analyticsService struct {
+	analyticsRepo  interfaces.AnalyticsRepository
+	chunkRepo      interfaces.ChunkRepository
+	knowledgeRepo  interfaces.KnowledgeRepository
+	kbService      interfaces.KnowledgeBaseService
+	messageService interfaces.MessageService
+}
+
+// NewAnalyticsService creates a new analytics service
+func NewAnalyticsService(
+	analyticsRepo interfaces.AnalyticsRepository,
+	chunkRepo interfaces.ChunkRepository,
+	knowledgeRepo interfaces.KnowledgeRepository,
+	kbService interfaces.KnowledgeBaseService,
+	messageService interfaces.MessageService,
+) interfaces.AnalyticsService {
+	return &analyticsService{
+		analyticsRepo:  analyticsRepo,
+		chunkRepo:      chunkRepo,
+		knowledgeRepo:  knowledgeRepo,
+		kbService:      kbService,
+		messageService: messageService,
+	}
+}
+
+// retrievedChunkScores returns the best score of each knowledge chunk of the results in result
+// order, web search results are skipped
+func retrievedChunkScores(results []*types.SearchResult) ([]string, map[string]float64) {
+	ids := make([]string, 0, len(results))
+	scores := make(map[string]float64, len(results))
+	for _, result := range results {
+		if result == nil || result.ID == "" || result.MatchType == types.MatchTypeWebSearch {
+			continue
+		}
+		score, seen := scores[result.ID]
+		if !seen {
+			ids = append(ids, result.ID)
+		}
+		if !seen || result.Score > score {
+			scores[result.ID] = result.Score
+		}
+	}
+	return ids, scores
+}
+
+// confidenceScore returns the best relevance score of the results returned to the chat, and false
+// when no result has a score on the scale of LowConfidenceQueryScore. Reranked scores are used when
+// a rerank model ran, otherwise the vector similarity, since fused hybrid scores such as RRF and
+// keyword scores are on other scales.
+func confidenceScore(chatManage *types.ChatManage) (float64, bool) {
+	if chatManage.RerankModelID != "" && len(chatManage.RerankResult) > 0 {
+		_, scores := retrievedChunkScores(chatManage.RerankResult)
+		best, ok := 0.0, false
+		for _, score := range scores {
+			best, ok = max(best, score), true
+		}
+		return best, ok
+	}
+
+	best, ok := 0.0, false
+	for _, result := range chatManage.MergeResult {
+		if result == nil || result.ID == "" {
+			continue
+		}
+		switch {
+		case result.ScoreDetails != nil:
+			if result.ScoreDetails.VectorRank > 0 {
+				best, ok = max(best, result.ScoreDetails.VectorScore), true
+			}
+		case result.MatchType == types.MatchTypeEmbedding:
+			best, ok = max(best, result.Score), true
+		}
+	}
+	return best, ok
+}
+
+// searchedKnowledgeBaseIDs returns the knowledge bases a chat query searched
+func searchedKnowledgeBaseIDs(chatManage *types.ChatManage) []string {
+	seen := make(map[string]bool)
+	var ids []string
+	add := func(id string) {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for _, target := range chatManage.SearchTargets {
+		if target != nil {
+			add(target.KnowledgeBaseID)
+		}
+	}
+	for _, id := range chatManage.KnowledgeBaseIDs {
+		add(id)
+	}
+	return ids
+}
+
+// analyticsContext returns a background context keeping the tenant and request of ctx,
+// so that recording outlives the chat request
+func analyticsContext(ctx context.Context, tenantID uint64) context.Context {
+	bgCtx := context.WithValue(context.Background(), types.TenantIDContextKey, tenantID)
+	if requestID := ctx.Value(types.RequestIDContextKey); requestID != nil {
+		bgCtx = context.WithValue(bgCtx, types.RequestIDContextKey, requestID)
+	}
+	return bgCtx
+}
+
+// chatTenantID returns the tenant of a chat
+func chatTenantID(ctx context.Context, chatManage *types.ChatManage) uint64 {
+	if chatManage.TenantID != 0 {
+		return chatManage.TenantID
+	}
+	tenantID, _ := ctx.Value(types.TenantIDContextKey).(uint64)
+	return tenantID
+}
+
+// newQueryLogs builds one query log per searched knowledge base
+func newQueryLogs(tenantID uint64, chatManage *types.ChatManage, kbIDs []string,
+	logType types.QueryLogType, resultCount int, maxScore float64,
+) []*types.QueryLog {
+	logs := make([]*types.QueryLog, 0, len(kbIDs))
+	for _, kbID := range kbIDs {
+		logs = append(logs, &types.QueryLog{
+			TenantID:        tenantID,
+			KnowledgeBaseID: kbID,
+			SessionID:       chatManage.SessionID,
+			MessageID:       chatManage.MessageID,
+			Query:           chatManage.Query,
+			RewriteQuery:    chatManage.RewriteQuery,
+			Type:            logType,
+			ResultCount:     resultCount,
+			MaxScore:        maxScore,
+			CreatedAt:       time.Now(),
+		})
+	}
+	return logs
+}
+
+// RecordRetrieval counts the hits of the results returned to the chat and logs the query
+// when their best score is low
+func (s *analyticsService) RecordRetrieval(ctx context.Context, chatManage *types.ChatManage) {
+	chunkIDs, _ := retrievedChunkScores(chatManage.MergeResult)
+	if len(chunkIDs) == 0 {
+		return
+	}
+	tenantID := chatTenantID(ctx, chatManage)
+	var logs []*types.QueryLog
+	if maxScore, ok := confidenceScore(chatManage); ok && maxScore < types.LowConfidenceQueryScore {
+		logs = newQueryLogs(tenantID, chatManage, searchedKnowledgeBaseIDs(chatManage),
+			types.QueryLogTypeLowConfidence, len(chunkIDs), maxScore)
+	}
+	statDate := time.Now().UTC().Truncate(24 * time.Hour)
+
+	go func() {
+		bgCtx := analyticsContext(ctx, tenantID)
+		chunks, err := s.chunkRepo.ListChunksByID(bgCtx, tenantID, chunkIDs)
+		if err != nil {
+			logger.Warnf(bgCtx, "Failed to load hit chunks: %v", err)
+			return
+		}
+		stats := make([]*types.KnowledgeHitStat, 0, len(chunks))
+		for _, chunk := range chunks {
+			stats = append(stats, &types.KnowledgeHitStat{
+				TenantID:        tenantID,
+				KnowledgeBaseID: chunk.KnowledgeBaseID,
+				KnowledgeID:     chunk.KnowledgeID,
+				ChunkID:         chunk.ID,
+				ChunkType:       string(chunk.ChunkType),
+				StatDate:        statDate,
+				HitCount:        1,
+			})
+		}
+		if err := s.analyticsRepo.IncrementHitStats(bgCtx, stats); err != nil {
+			logger.Warnf(bgCtx, "Failed to record chunk hits: %v", err)
+		}
+		if err := s.analyticsRepo.CreateQueryLogs(bgCtx, logs); err != nil {
+			logger.Warnf(bgCtx, "Failed to log low confidence query: %v", err)
+		}
+	}()
+}
+
+// RecordZeroResult logs a chat query whose retrieval returned nothing
+func (s *analyticsService) RecordZeroResult(ctx context.Context, chatManage *types.ChatManage) {
+	tenantID := chatTenantID(ctx, chatManage)
+	logs := newQueryLogs(tenantID, chatManage, searchedKnowledgeBaseIDs(chatManage),
+		types.QueryLogTypeZeroResult, 0, 0)
+	if len(logs) == 0 {
+		return
+	}
+	go func() {
+		bgCtx := analyticsContext(ctx, tenantID)
+		if err := s.analyticsRepo.CreateQueryLogs(bgCtx, logs); err != nil {
+			logger.Warnf(bgCtx, "Failed to log zero result query: %v", err)
+		}
+	}()
+}
+
+// SubmitMessageFeedback rates an assistant message, linking the rating to the chunks the answer cited
+func (s *analyticsService) SubmitMessageFeedback(ctx context.Context,
+	sessionID string, messageID string, req *types.MessageFeedbackRequest,
+) (*types.MessageFeedback, error) {
+	if req == nil || !req.Rating.IsValid() {
+		return nil, werrors.NewBadRequestError("rating 必须是 up 或 down")
+	}
+	message, err := s.messageService.GetMessage(ctx, sessionID, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if message == nil {
+		return nil, werrors.NewNotFoundError("消息不存在")
+	}
+	if message.Role != "assistant" {
+		return nil, werrors.NewBadRequestError("只能评价助手的回复")
+	}
+
+	tenantID := ctx.Value(types.TenantIDContextKey).(uint64)
+	chunkIDs, _ := retrievedChunkScores(message.KnowledgeReferences)
+	feedback := &types.MessageFeedback{
+		TenantID:  tenantID,
+		SessionID: sessionID,
+		MessageID: messageID,
+		Rating:    req.Rating,
+		Comment:   strings.TrimSpace(req.Comment),
+		ChunkIDs:  chunkIDs,
+	}
+	if user, ok := ctx.Value(types.UserContextKey).(*types.User); ok && user != nil {
+		feedback.UserID = user.ID
+	}
+
+	var feedbackChunks []*types.MessageFeedbackChunk
+	if len(chunkIDs) > 0 {
+		chunks, err := s.chunkRepo.ListChunksByID(ctx, tenantID, chunkIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, chunk := range chunks {
+			feedbackChunks = append(feedbackChunks, &types.MessageFeedbackChunk{
+				ChunkID:         chunk.ID,
+				TenantID:        tenantID,
+				KnowledgeBaseID: chunk.KnowledgeBaseID,
+				KnowledgeID:     chunk.KnowledgeID,
+			})
+		}
+	}
+	if err := s.analyticsRepo.SaveMessageFeedback(ctx, feedback, feedbackChunks); err != nil {
+		return nil, err
+	}
+	logger.Infof(ctx, "Message %s rated %s, %d cited chunks", messageID, req.Rating, len(feedbackChunks))
+	return feedback, nil
+}
+
+// getKnowledgeBase gets a knowledge base of the tenant
+func (s *analyticsService) getKnowledgeBase(ctx context.Context, kbID string) (*types.KnowledgeBase, error) {
+	kb, err := s.kbService.GetKnowledgeBaseByID(ctx, kbID)
+	if err != nil {
+		return nil, werrors.NewNotFoundError("知识库不存在")
+	}
+	if kb.TenantID != ctx.Value(types.TenantIDContextKey).(uint64) {
+		return nil, werrors.NewForbiddenError("无权访问该知识库")
+	}
+	return kb, nil
+}
+
+// GetKnowledgeBaseAnalytics summarizes the usage of a knowledge base in a date range
+func (s *analyticsService) GetKnowledgeBaseAnalytics(ctx context.Context,
+	kbID string, dateRange types.AnalyticsDateRange,
+) (*types.KnowledgeBaseAnalytics, error) {
+	kb, err := s.getKnowledgeBase(ctx, kbID)
+	if err != nil {
+		return nil, err
+	}
+	return s.analyticsRepo.GetKnowledgeBaseAnalytics(ctx, kb.TenantID, kb.ID, dateRange)
+}
+
+// ListTopHitEntries lists the most returned chunks or FAQ entries of a knowledge base
+func (s *analyticsService) ListTopHitEntries(ctx context.Context,
+	kbID string, dateRange types.AnalyticsDateRange, limit int,
+) ([]*types.AnalyticsEntry, error) {
+	kb, err := s.getKnowledgeBase(ctx, kbID)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultTopHitLimit
+	}
+	limit = min(limit, maxTopHitLimit)
+
+	entries, err := s.analyticsRepo.ListTopHitChunks(ctx, kb.TenantID, kb.ID, dateRange, limit)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return []*types.AnalyticsEntry{}, nil
+	}
+	chunkIDs := make([]string, 0, len(entries))
+	knowledgeIDs := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		chunkIDs = append(chunkIDs, entry.ChunkID)
+		knowledgeIDs = append(knowledgeIDs, entry.KnowledgeID)
+	}
+
+	// Titles are looked up best effort, deleted chunks and documents keep their counts
+	chunkByID := make(map[string]*types.Chunk, len(chunkIDs))
+	if chunks, err := s.chunkRepo.ListChunksByID(ctx, kb.TenantID, chunkIDs); err == nil {
+		for _, chunk := range chunks {
+			chunkByID[chunk.ID] = chunk
+		}
+	} else {
+		logger.Warnf(ctx, "Failed to load top hit chunks: %v", err)
+	}
+	titleByKnowledge := make(map[string]string, len(knowledgeIDs))
+	if knowledgeList, err := s.knowledgeRepo.GetKnowledgeBatch(ctx, kb.TenantID, knowledgeIDs); err == nil {
+		for _, knowledge := range knowledgeList {
+			titleByKnowledge[knowledge.ID] = knowledge.Title
+		}
+	} else {
+		logger.Warnf(ctx, "Failed to load top hit knowledge: %v", err)
+	}
+	feedback, err := s.analyticsRepo.CountFeedbackByChunk(ctx, kb.TenantID, chunkIDs, dateRange)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		entry.KnowledgeTitle = titleByKnowledge[entry.KnowledgeID]
+		if chunk := chunkByID[entry.ChunkID]; chunk != nil {
+			entry.Title = analyticsChunkTitle(chunk)
+			if chunk.ChunkType == types.ChunkTypeFAQ {
+				entry.EntryID = chunk.SeqID
+			}
+		}
+		count := feedback[entry.ChunkID]
+		entry.UpFeedback, entry.DownFeedback = count.Up, count.Down
+	}
+	return entries, nil
+}
+
+// analyticsChunkTitle returns the standard question of a FAQ chunk or the beginning of other chunks
+func analyticsChunkTitle(chunk *types.Chunk) string {
+	if chunk.ChunkType == types.ChunkTypeFAQ {
+		if meta, err := chunk.FAQMetadata(); err == nil && meta != nil {
+			return meta.StandardQuestion
+		}
+	}
+	content := []rune(strings.TrimSpace(chunk.Content))
+	if len(content) > analyticsTitleRunes {
+		return string(content[:analyticsTitleRunes]) + "..."
+	}
+	return string(content)
+}
+
+// ListNeverHitEntries lists the FAQ entries, or documents of a document knowledge base, never returned
+func (s *analyticsService) ListNeverHitEntries(ctx context.Context,
+	kbID string, dateRange types.AnalyticsDateRange, page *types.Pagination,
+) (*types.PageResult, error) {
+	kb, err := s.getKnowledgeBase(ctx, kbID)
+	if err != nil {
+		return nil, err
+	}
+	var entries []*types.AnalyticsEntry
+	var total int64
+	if kb.Type == types.KnowledgeBaseTypeFAQ {
+		entries, total, err = s.analyticsRepo.ListNeverHitFAQEntries(ctx, kb.TenantID, kb.ID, dateRange, page)
+	} else {
+		entries, total, err = s.analyticsRepo.ListNeverHitKnowledge(ctx, kb.TenantID, kb.ID, dateRange, page)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return types.NewPageResult(total, page, entries), nil
+}
+
+// ListQueryLogs lists the logged chat queries of a knowledge base, newest first
+func (s *analyticsService) ListQueryLogs(ctx context.Context,
+	kbID string, logType types.QueryLogType, dateRange types.AnalyticsDateRange, page *types.Pagination,
+) (*types.PageResult, error) {
+	kb, err := s.getKnowledgeBase(ctx, kbID)
+	if err != nil {
+		return nil, err
+	}
+	logs, total, err := s.analyticsRepo.ListQueryLogs(ctx, kb.TenantID, kb.ID, logType, dateRange, page)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewPageResult(total, page, logs), nil
+}
+
+// ListMessageFeedback lists the feedback on answers citing a knowledge base, newest first
+func (s *analyticsService) ListMessageFeedback(ctx context.Context,
+	kbID string, rating types.MessageFeedbackRating, dateRange types.AnalyticsDateRange, page *types.Pagination,
+) (*types.PageResult, error) {
+	kb, err := s.getKnowledgeBase(ctx, kbID)
+	if err != nil {
+		return nil, err
+	}
+	feedback, total, err := s.analyticsRepo.ListMessageFeedback(ctx, kb.TenantID, kb.ID, rating, dateRange, page)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewPageResult(total, page, feedback), nil
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+func TestRetrievedChunkScores(t *testing.T) {
+	results := []*types.SearchResult{
+		{ID: "c1", Score: 0.4},
+		{ID: "w1", Score: 0.9, MatchType: types.MatchTypeWebSearch},
+		{ID: "c2", Score: 0.3},
+		{ID: "c1", Score: 0.6},
+		nil,
+		{ID: "", Score: 1},
+	}
+	ids, scores := retrievedChunkScores(results)
+	if got := strings.Join(ids, ","); got != "c1,c2" {
+		t.Errorf("ids = %q, want knowledge chunks once in result order", got)
+	}
+	if scores["c1"] != 0.6 || scores["c2"] != 0.3 {
+		t.Errorf("scores = %v, want the best score of each chunk", scores)
+	}
+}
+
+func TestSearchedKnowledgeBaseIDs(t *testing.T) {
+	tests := []struct {
+		name       string
+		chatManage *types.ChatManage
+		want       string
+	}{
+		{"none", &types.ChatManage{}, ""},
+		{
+			"targets first, then ids",
+			&types.ChatManage{
+				KnowledgeBaseIDs: []string{"kb2", "kb3"},
+				SearchTargets: types.SearchTargets{
+					{Type: types.SearchTargetTypeKnowledge, KnowledgeBaseID: "kb1"},
+					{Type: types.SearchTargetTypeKnowledgeBase, KnowledgeBaseID: "kb2"},
+				},
+			},
+			"kb1,kb2,kb3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.Join(searchedKnowledgeBaseIDs(tt.chatManage), ","); got != tt.want {
+				t.Errorf("searchedKnowledgeBaseIDs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfidenceScore(t *testing.T) {
+	// Without a rerank model the merged scores are fused scores, e.g. RRF with k=60 peaks near 0.033
+	hybrid := []*types.SearchResult{
+		{ID: "c1", Score: 0.033, ScoreDetails: &types.ScoreDetails{
+			FusionStrategy: types.FusionStrategyRRF, VectorRank: 1, VectorScore: 0.82, KeywordRank: 2, KeywordScore: 7.5,
+		}},
+		{ID: "c2", Score: 0.016, ScoreDetails: &types.ScoreDetails{
+			FusionStrategy: types.FusionStrategyRRF, KeywordRank: 1, KeywordScore: 9.1,
+		}},
+	}
+	tests := []struct {
+		name       string
+		chatManage *types.ChatManage
+		want       float64
+		wantOK     bool
+	}{
+		{"hybrid without rerank uses vector score", &types.ChatManage{MergeResult: hybrid}, 0.82, true},
+		{
+			"keyword only has no comparable score",
+			&types.ChatManage{MergeResult: hybrid[1:]},
+			0, false,
+		},
+		{
+			"vector only uses the score",
+			&types.ChatManage{MergeResult: []*types.SearchResult{
+				{ID: "c1", Score: 0.31, MatchType: types.MatchTypeEmbedding},
+				{ID: "c2", Score: 0.9, MatchType: types.MatchTypeNearByChunk},
+			}},
+			0.31, true,
+		},
+		{
+			"reranked scores",
+			&types.ChatManage{
+				RerankModelID: "rerank",
+				RerankResult:  []*types.SearchResult{{ID: "c1", Score: 0.42}, {ID: "c2", Score: 0.12}},
+				MergeResult:   hybrid,
+			},
+			0.42, true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := confidenceScore(tt.chatManage)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("confidenceScore() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
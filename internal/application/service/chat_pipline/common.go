@@ -8,6 +8,7 @@ import (
 	"github.com/Tencent/WeKnora/internal/common"
 	"github.com/Tencent/WeKnora/internal/logger"
 	"github.com/Tencent/WeKnora/internal/models/chat"
+	"github.com/Tencent/WeKnora/internal/searchutil"
 	"github.com/Tencent/WeKnora/internal/types"
 	"github.com/Tencent/WeKnora/internal/types/interfaces"
 )
@@ -56,6 +57,8 @@ func prepareChatModel(ctx context.Context, modelService interfaces.ModelService,
 func prepareMessagesWithHistory(chatManage *types.ChatManage) []chat.Message {
 	// Replace placeholders in system prompt
 	systemPrompt := renderSystemPromptPlaceholders(chatManage.SummaryConfig.Prompt)
+	// Answer in the language of the user regardless of the language of the retrieved information
+	systemPrompt = searchutil.WithLanguage(systemPrompt, chatManage.QueryLanguage, searchutil.AnswerLanguageInstruction)
 	
 	chatMessages := []chat.Message{
 		{Role: "system", Content: systemPrompt},
@@ -9,15 +9,20 @@ import (
 	"time"
 
 	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
 	"github.com/Tencent/WeKnora/internal/utils"
 )
 
 // PluginIntoChatMessage handles the transformation of search results into chat messages
-type PluginIntoChatMessage struct{}
+type PluginIntoChatMessage struct {
+	analyticsService interfaces.AnalyticsService // Records the hits of the results used in the answer
+}
 
 // NewPluginIntoChatMessage creates and registers a new PluginIntoChatMessage instance
-func NewPluginIntoChatMessage(eventManager *EventManager) *PluginIntoChatMessage {
-	res := &PluginIntoChatMessage{}
+func NewPluginIntoChatMessage(eventManager *EventManager,
+	analyticsService interfaces.AnalyticsService,
+) *PluginIntoChatMessage {
+	res := &PluginIntoChatMessage{analyticsService: analyticsService}
 	eventManager.Register(res)
 	return res
 }
@@ -129,6 +134,11 @@ func (p *PluginIntoChatMessage) OnEvent(ctx context.Context,
 		"user_content_len": len(chatManage.UserContent),
 		"faq_priority":     chatManage.FAQPriorityEnabled,
 	})
+
+	// Count the results used in the answer for knowledge base analytics
+	if p.analyticsService != nil {
+		p.analyticsService.RecordRetrieval(ctx, chatManage)
+	}
 	return next()
 }
 
@@ -0,0 +1,121 @@
+package chatpipline
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/Tencent/WeKnora/internal/models/chat"
+	"github.com/Tencent/WeKnora/internal/searchutil"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+)
+
+const (
+	// rewriteLanguageInstruction is appended to the system prompt of the query rewrite
+	rewriteLanguageInstruction = "- Write the rewritten question in {{language}}, the language of the user question"
+	// translateQueryPrompt is the system prompt translating a query for keyword retrieval
+	translateQueryPrompt = "You translate search queries. Translate the user's query into {{language}}. " +
+		"Keep product names, codes and numbers unchanged. Only output the translated query, without explanation."
+)
+
+// translateQuery translates a query into a language with the chat model
+func translateQuery(ctx context.Context, chatModel chat.Chat, query string, language string) (string, error) {
+	thinking := false
+	response, err := chatModel.Chat(ctx, []chat.Message{
+		{Role: "system", Content: searchutil.WithLanguage(translateQueryPrompt, language, "")},
+		{Role: "user", Content: query},
+	}, &chat.ChatOptions{
+		Temperature:         0.1,
+		MaxCompletionTokens: 100,
+		Thinking:            &thinking,
+	})
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(reg.ReplaceAllString(response.Content, "")), nil
+}
+
+// translateQueryForTargets translates the rewritten query into the languages of the searched document
+// knowledge bases that translate queries. It returns the translated queries by knowledge base ID and
+// records them in chatManage.QueryTranslations.
+func translateQueryForTargets(ctx context.Context, chatManage *types.ChatManage,
+	knowledgeBaseService interfaces.KnowledgeBaseService, modelService interfaces.ModelService,
+) map[string][]string {
+	query := strings.TrimSpace(chatManage.RewriteQuery)
+	if query == "" || chatManage.QueryLanguage == "" || modelService == nil {
+		return nil
+	}
+
+	// Languages each knowledge base needs, keyword retrieval is not used for FAQ knowledge bases
+	kbLanguages := make(map[string][]string)
+	var languages []string
+	for _, target := range chatManage.SearchTargets {
+		if _, done := kbLanguages[target.KnowledgeBaseID]; done {
+			continue
+		}
+		kb, err := knowledgeBaseService.GetKnowledgeBaseByID(ctx, target.KnowledgeBaseID)
+		if err != nil || kb.Type == types.KnowledgeBaseTypeFAQ {
+			kbLanguages[target.KnowledgeBaseID] = nil
+			continue
+		}
+		kbLanguages[target.KnowledgeBaseID] = kb.MultilingualConfig.TranslationLanguages(chatManage.QueryLanguage)
+		for _, language := range kbLanguages[target.KnowledgeBaseID] {
+			if !slices.Contains(languages, language) {
+				languages = append(languages, language)
+			}
+		}
+	}
+	if len(languages) == 0 {
+		return nil
+	}
+
+	chatModel, err := modelService.GetChatModel(ctx, chatManage.ChatModelID)
+	if err != nil {
+		pipelineWarn(ctx, "Search", "translate_model", map[string]interface{}{
+			"chat_model_id": chatManage.ChatModelID,
+			"error":         err.Error(),
+		})
+		return nil
+	}
+
+	// Each language is translated once for all knowledge bases
+	translations := make(map[string]string, len(languages))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, language := range languages {
+		wg.Add(1)
+		go func(language string) {
+			defer wg.Done()
+			translated, err := translateQuery(ctx, chatModel, query, language)
+			if err != nil || translated == "" {
+				pipelineWarn(ctx, "Search", "translate_error", map[string]interface{}{
+					"language": language,
+					"error":    err,
+				})
+				return
+			}
+			mu.Lock()
+			translations[language] = translated
+			mu.Unlock()
+		}(language)
+	}
+	wg.Wait()
+
+	chatManage.QueryTranslations = translations
+	pipelineInfo(ctx, "Search", "query_translated", map[string]interface{}{
+		"query_language": chatManage.QueryLanguage,
+		"translations":   translations,
+	})
+
+	queries := make(map[string][]string, len(kbLanguages))
+	for kbID, kbLangs := range kbLanguages {
+		for _, language := range kbLangs {
+			if translated, ok := translations[language]; ok {
+				queries[kbID] = append(queries[kbID], translated)
+			}
+		}
+	}
+	return queries
+}
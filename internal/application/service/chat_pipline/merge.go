@@ -278,7 +278,11 @@ func (p *PluginMerge) populateFAQAnswers(
 			}
 			continue
 		}
-		content := buildFAQAnswerContent(meta)
+		var pref types.FAQAnswerPreference
+		if chatManage != nil {
+			pref = chatManage.FAQAnswerPreference
+		}
+		content := buildFAQAnswerContent(meta, pref)
 		if content == "" {
 			continue
 		}
@@ -299,15 +303,16 @@ func (p *PluginMerge) populateFAQAnswers(
 	return results
 }
 
-// buildFAQAnswerContent builds the content of a FAQ answer
-func buildFAQAnswerContent(meta *types.FAQChunkMetadata) string {
+// buildFAQAnswerContent builds the content of a FAQ answer with the answer variant of the preference
+func buildFAQAnswerContent(meta *types.FAQChunkMetadata, pref types.FAQAnswerPreference) string {
 	if meta == nil {
 		return ""
 	}
 
 	question := strings.TrimSpace(meta.StandardQuestion)
-	answers := make([]string, 0, len(meta.Answers))
-	for _, ans := range meta.Answers {
+	variantAnswers := meta.AnswersFor(pref)
+	answers := make([]string, 0, len(variantAnswers))
+	for _, ans := range variantAnswers {
 		if trimmed := strings.TrimSpace(ans); trimmed != "" {
 			answers = append(answers, trimmed)
 		}
@@ -376,6 +381,10 @@ func (p *PluginMerge) expandShortContextWithNeighbors(
 		if r.ChunkType != string(types.ChunkTypeText) {
 			continue
 		}
+		// Parents swapped in for matched child chunks already carry their whole section
+		if r.MatchType == types.MatchTypeSmallToBig {
+			continue
+		}
 		if runeLen(r.Content) >= minLen {
 			continue
 		}
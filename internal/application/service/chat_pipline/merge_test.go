@@ -0,0 +1,40 @@
+package chatpipline
+
+import (
+	"testing"
+
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+func TestBuildFAQAnswerContentVariants(t *testing.T) {
+	meta := &types.FAQChunkMetadata{
+		StandardQuestion: "How to reset password?",
+		Answers:          []string{"default"},
+		AnswerVariants: []types.FAQAnswerVariant{
+			{Locale: "en", Answers: []string{"english"}},
+			{Locale: "en-US", Channel: "ivr", Answers: []string{"us ivr"}},
+			{Channel: "web", Answers: []string{"web"}},
+		},
+	}
+
+	tests := []struct {
+		name string
+		pref types.FAQAnswerPreference
+		want string
+	}{
+		{"no preference uses defaults", types.FAQAnswerPreference{}, "default"},
+		{"language matches regional locale", types.FAQAnswerPreference{Locale: "en-GB"}, "english"},
+		{"exact locale and channel", types.FAQAnswerPreference{Locale: "en_us", Channel: "IVR"}, "us ivr"},
+		{"locale ranks above channel", types.FAQAnswerPreference{Locale: "en-US", Channel: "web"}, "english"},
+		{"channel only", types.FAQAnswerPreference{Locale: "ko-KR", Channel: "web"}, "web"},
+		{"no match falls back", types.FAQAnswerPreference{Locale: "ko-KR"}, "default"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := "Q: How to reset password?\nAnswer:\n- " + tt.want
+			if got := buildFAQAnswerContent(meta, tt.pref); got != want {
+				t.Errorf("buildFAQAnswerContent() = %q, want %q", got, want)
+			}
+		})
+	}
+}
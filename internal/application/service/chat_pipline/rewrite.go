@@ -12,6 +12,7 @@ import (
 
 	"github.com/Tencent/WeKnora/internal/config"
 	"github.com/Tencent/WeKnora/internal/models/chat"
+	"github.com/Tencent/WeKnora/internal/searchutil"
 	"github.com/Tencent/WeKnora/internal/types"
 	"github.com/Tencent/WeKnora/internal/types/interfaces"
 )
@@ -164,6 +165,10 @@ func (p *PluginRewrite) OnEvent(ctx context.Context,
 	systemContent = strings.ReplaceAll(systemContent, "{{current_time}}", currentTime)
 	systemContent = strings.ReplaceAll(systemContent, "{{yesterday}}", yesterday)
 
+	// Keep the rewritten query in the language of the user, the default prompt has English examples
+	userContent = searchutil.WithLanguage(userContent, chatManage.QueryLanguage, "")
+	systemContent = searchutil.WithLanguage(systemContent, chatManage.QueryLanguage, rewriteLanguageInstruction)
+
 	rewriteModel, err := p.modelService.GetChatModel(ctx, chatManage.ChatModelID)
 	if err != nil {
 		pipelineError(ctx, "Rewrite", "get_model", map[string]interface{}{
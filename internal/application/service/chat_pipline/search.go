@@ -25,6 +25,7 @@ type PluginSearch struct {
 	tenantService         interfaces.TenantService
 	sessionService        interfaces.SessionService
 	webSearchStateService interfaces.WebSearchStateService
+	modelService          interfaces.ModelService
 }
 
 func NewPluginSearch(eventManager *EventManager,
@@ -36,6 +37,7 @@ func NewPluginSearch(eventManager *EventManager,
 	tenantService interfaces.TenantService,
 	sessionService interfaces.SessionService,
 	webSearchStateService interfaces.WebSearchStateService,
+	modelService interfaces.ModelService,
 ) *PluginSearch {
 	res := &PluginSearch{
 		knowledgeBaseService:  knowledgeBaseService,
@@ -46,6 +48,7 @@ func NewPluginSearch(eventManager *EventManager,
 		tenantService:         tenantService,
 		sessionService:        sessionService,
 		webSearchStateService: webSearchStateService,
+		modelService:          modelService,
 	}
 	eventManager.Register(res)
 	return res
@@ -84,6 +87,9 @@ func (p *PluginSearch) OnEvent(ctx context.Context,
 		"vector_threshold":  chatManage.VectorThreshold,
 		"keyword_threshold": chatManage.KeywordThreshold,
 	})
+	// Translate the query for knowledge bases in other languages before searching
+	translatedQueries := translateQueryForTargets(ctx, chatManage, p.knowledgeBaseService, p.modelService)
+
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 	allResults := make([]*types.SearchResult, 0)
@@ -92,7 +98,7 @@ func (p *PluginSearch) OnEvent(ctx context.Context,
 	// Goroutine 1: Knowledge base search using SearchTargets
 	go func() {
 		defer wg.Done()
-		kbResults := p.searchByTargets(ctx, chatManage)
+		kbResults := p.searchByTargets(ctx, chatManage, translatedQueries)
 		if len(kbResults) > 0 {
 			mu.Lock()
 			allResults = append(allResults, kbResults...)
@@ -169,6 +175,7 @@ func (p *PluginSearch) OnEvent(ctx context.Context,
 							MatchCount:           expTopK,
 							DisableVectorMatch:   true,
 							DisableKeywordsMatch: false,
+							MetadataFilters:      chatManage.MetadataFilters,
 						}
 						// Apply knowledge ID filter if this is a partial KB search
 						if t.Type == types.SearchTargetTypeKnowledge {
@@ -311,9 +318,12 @@ func buildContentSignature(content string) string {
 
 // searchByTargets performs KB searches using pre-computed SearchTargets
 // This is the main search method that uses the unified search targets
+// translatedQueries holds the query translated into the languages of each knowledge base,
+// which are searched by keywords in addition to the query
 func (p *PluginSearch) searchByTargets(
 	ctx context.Context,
 	chatManage *types.ChatManage,
+	translatedQueries map[string][]string,
 ) []*types.SearchResult {
 	if len(chatManage.SearchTargets) == 0 {
 		return nil
@@ -334,7 +344,8 @@ func (p *PluginSearch) searchByTargets(
 			searchKnowledgeIDs := t.KnowledgeIDs
 
 			// Try direct loading for specific knowledge targets
-			if t.Type == types.SearchTargetTypeKnowledge {
+			// Direct loading ignores metadata filters, so it is skipped when filters are present
+			if t.Type == types.SearchTargetTypeKnowledge && len(chatManage.MetadataFilters) == 0 {
 				directResults, skippedIDs := p.tryDirectChunkLoading(ctx, chatManage.TenantID, t.KnowledgeIDs)
 
 				if len(directResults) > 0 {
@@ -368,6 +379,7 @@ func (p *PluginSearch) searchByTargets(
 				VectorThreshold:  chatManage.VectorThreshold,
 				KeywordThreshold: chatManage.KeywordThreshold,
 				MatchCount:       chatManage.EmbeddingTopK,
+				MetadataFilters:  chatManage.MetadataFilters,
 			}
 			// Apply knowledge ID filter if this is a partial KB search
 			if t.Type == types.SearchTargetTypeKnowledge {
@@ -391,6 +403,30 @@ func (p *PluginSearch) searchByTargets(
 			mu.Lock()
 			results = append(results, res...)
 			mu.Unlock()
+
+			// Keyword search with the translated queries, vector search is already cross-lingual
+			for _, translated := range translatedQueries[t.KnowledgeBaseID] {
+				paramsTr := params
+				paramsTr.QueryText = translated
+				paramsTr.DisableVectorMatch = true
+				trRes, err := p.knowledgeBaseService.HybridSearch(ctx, t.KnowledgeBaseID, paramsTr)
+				if err != nil {
+					pipelineWarn(ctx, "Search", "translated_search_error", map[string]interface{}{
+						"kb_id": t.KnowledgeBaseID,
+						"query": translated,
+						"error": err.Error(),
+					})
+					continue
+				}
+				pipelineInfo(ctx, "Search", "translated_hits", map[string]interface{}{
+					"kb_id": t.KnowledgeBaseID,
+					"query": translated,
+					"hits":  len(trRes),
+				})
+				mu.Lock()
+				results = append(results, trRes...)
+				mu.Unlock()
+			}
 		}(target)
 	}
 
@@ -502,11 +538,12 @@ func (p *PluginSearch) searchWebIfEnabled(ctx context.Context, chatManage *types
 		return nil
 	}
 
+	searchConfig := tenant.WebSearchConfig.WithOverrides(chatManage.WebSearchAllowlist, chatManage.WebSearchTrustWeights)
 	pipelineInfo(ctx, "Search", "web_request", map[string]interface{}{
 		"tenant_id": chatManage.TenantID,
-		"provider":  tenant.WebSearchConfig.Provider,
+		"provider":  searchConfig.Provider,
 	})
-	webResults, err := p.webSearchService.Search(ctx, tenant.WebSearchConfig, chatManage.RewriteQuery)
+	webResults, err := p.webSearchService.Search(ctx, searchConfig, chatManage.RewriteQuery)
 	if err != nil {
 		pipelineWarn(ctx, "Search", "web_search_error", map[string]interface{}{
 			"tenant_id": chatManage.TenantID,
@@ -620,6 +657,10 @@ var stopwords = map[string]struct{}{
 var questionWords = regexp.MustCompile(`^(什么是|什么|如何|怎么|怎样|为什么|为何|哪个|哪些|谁|何时|何地|请问|请告诉我|帮我|我想知道|我想了解)`)
 
 func extractKeywords(text string) []string {
+	// Korean words carry particles and have their own question words
+	if searchutil.ContainsHangul(text) {
+		return searchutil.TokenizeKorean(text)
+	}
 	words := tokenize(text)
 	keywords := make([]string, 0, len(words))
 	for _, w := range words {
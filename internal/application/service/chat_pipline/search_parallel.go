@@ -44,6 +44,7 @@ func NewPluginSearchParallel(
 	graphRepository interfaces.RetrieveGraphRepository,
 	chunkRepository interfaces.ChunkRepository,
 	knowledgeRepository interfaces.KnowledgeRepository,
+	modelService interfaces.ModelService,
 ) *PluginSearchParallel {
 	// Create internal plugins without registering them
 	searchPlugin := &PluginSearch{
@@ -55,6 +56,7 @@ func NewPluginSearchParallel(
 		tenantService:         tenantService,
 		sessionService:        sessionService,
 		webSearchStateService: webSearchStateService,
+		modelService:          modelService,
 	}
 
 	searchEntityPlugin := &PluginSearchEntity{
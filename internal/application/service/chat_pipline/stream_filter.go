@@ -126,8 +126,9 @@ func (p *PluginStreamFilter) filterEventsWithPrefix(
 			Type:      types.EventType(event.EventAgentFinalAnswer),
 			SessionID: chatManage.SessionID,
 			Data: event.AgentFinalAnswerData{
-				Content: chatManage.FallbackResponse,
-				Done:    true,
+				Content:    chatManage.FallbackResponse,
+				Done:       true,
+				IsFallback: true,
 			},
 		})
 	}
@@ -6,6 +6,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/Tencent/WeKnora/internal/application/service/retriever"
 	"github.com/Tencent/WeKnora/internal/logger"
@@ -17,10 +18,12 @@ import (
 // It provides operations for managing document chunks in the knowledge base
 // Chunks are segments of documents that have been processed and prepared for indexing
 type chunkService struct {
-	chunkRepository interfaces.ChunkRepository // Repository for chunk data persistence
-	kbRepository    interfaces.KnowledgeBaseRepository
-	modelService    interfaces.ModelService
-	retrieveEngine  interfaces.RetrieveEngineRegistry
+	chunkRepository     interfaces.ChunkRepository // Repository for chunk data persistence
+	chunkEditRepository interfaces.ChunkEditRepository
+	kbRepository        interfaces.KnowledgeBaseRepository
+	knowledgeRepository interfaces.KnowledgeRepository
+	modelService        interfaces.ModelService
+	retrieveEngine      interfaces.RetrieveEngineRegistry
 }
 
 // NewChunkService creates a new chunk service
@@ -32,15 +35,19 @@ type chunkService struct {
 //   - interfaces.ChunkService: Initialized chunk service implementation
 func NewChunkService(
 	chunkRepository interfaces.ChunkRepository,
+	chunkEditRepository interfaces.ChunkEditRepository,
 	kbRepository interfaces.KnowledgeBaseRepository,
+	knowledgeRepository interfaces.KnowledgeRepository,
 	modelService interfaces.ModelService,
 	retrieveEngine interfaces.RetrieveEngineRegistry,
 ) interfaces.ChunkService {
 	return &chunkService{
-		chunkRepository: chunkRepository,
-		kbRepository:    kbRepository,
-		modelService:    modelService,
-		retrieveEngine:  retrieveEngine,
+		chunkRepository:     chunkRepository,
+		chunkEditRepository: chunkEditRepository,
+		kbRepository:        kbRepository,
+		knowledgeRepository: knowledgeRepository,
+		modelService:        modelService,
+		retrieveEngine:      retrieveEngine,
 	}
 }
 
@@ -154,6 +161,7 @@ func (s *chunkService) ListPagedChunksByKnowledgeID(ctx context.Context,
 		"",
 		"",
 		"",
+		"",
 	)
 	if err != nil {
 		logger.ErrorWithFields(ctx, err, map[string]interface{}{
@@ -214,7 +222,37 @@ func (s *chunkService) UpdateChunks(ctx context.Context, chunks []*types.Chunk)
 	return nil
 }
 
-// DeleteChunk deletes a chunk by ID
+// UpdateChunkEnabledStatus enables or disables the index entries of a chunk and of its child chunks
+// and sets chunk.IsEnabled, the chunk itself is saved by the caller afterwards
+func (s *chunkService) UpdateChunkEnabledStatus(ctx context.Context, chunk *types.Chunk, isEnabled bool) error {
+	if chunk.IsEnabled == isEnabled {
+		return nil
+	}
+	tenantID := ctx.Value(types.TenantIDContextKey).(uint64)
+	knowledge, err := s.knowledgeRepository.GetKnowledgeByID(ctx, tenantID, chunk.KnowledgeID)
+	if err != nil {
+		return fmt.Errorf("failed to get knowledge: %w", err)
+	}
+	children, err := s.chunkRepository.ListChunkByParentID(ctx, tenantID, chunk.ID)
+	if err != nil {
+		return err
+	}
+	tenantInfo := ctx.Value(types.TenantInfoContextKey).(*types.Tenant)
+	retrieveEngine, err := retriever.NewCompositeRetrieveEngine(s.retrieveEngine, tenantInfo.GetEffectiveEngines())
+	if err != nil {
+		return fmt.Errorf("failed to create retrieve engine: %w", err)
+	}
+
+	chunk.IsEnabled = isEnabled
+	chunkStatusMap := chunkEnabledIndexStatus(knowledge, chunk, children, time.Now())
+	if err := retrieveEngine.BatchUpdateChunkEnabledStatus(ctx, chunkStatusMap); err != nil {
+		chunk.IsEnabled = !isEnabled
+		return err
+	}
+	logger.Infof(ctx, "Chunk %s enabled status set to %v, %d index entries updated", chunk.ID, isEnabled, len(chunkStatusMap))
+	return nil
+}
+
 // This method removes a specific chunk from the repository
 // Parameters:
 //   - ctx: Context with authentication and request information
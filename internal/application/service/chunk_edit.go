@@ -0,0 +1,586 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Tencent/WeKnora/internal/application/service/retriever"
+	werrors "github.com/Tencent/WeKnora/internal/errors"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/models/embedding"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/google/uuid"
+)
+
+// minMergeOverlap is the shortest overlap between adjacent chunks that merging removes,
+// shorter matches are more likely a coincidence than chunk overlap
+const minMergeOverlap = 16
+
+// EditChunk replaces the content of a chunk, records the edit and re-embeds the chunk.
+// Sending the content of a chunk whose re-indexing failed indexes it again.
+func (s *chunkService) EditChunk(ctx context.Context, chunk *types.Chunk, content string) error {
+	if chunk.ChunkType == types.ChunkTypeFAQ {
+		return werrors.NewBadRequestError("FAQ entries are edited through the FAQ API")
+	}
+	if strings.TrimSpace(content) == "" {
+		return werrors.NewBadRequestError("chunk content cannot be empty")
+	}
+
+	if content == chunk.Content && chunk.Status != int(types.ChunkStatusIndexFailed) {
+		return nil
+	}
+
+	previous := chunk.Content
+	var edit *types.ChunkEdit
+	if chunk.ChunkType == types.ChunkTypeText && content != previous {
+		edit = newChunkEdit(chunk, types.ChunkEditOperationEdit, []string{previous}, []string{content}, "")
+	}
+	chunk.Content = content
+	chunk.UpdatedAt = time.Now()
+	if err := s.applyChunkEdit(ctx, []*types.Chunk{chunk}, nil, nil, edit, &types.ChunkEditChanges{
+		Updated: []*types.Chunk{chunk},
+	}); err != nil {
+		// The stored chunk is unchanged unless the edit was saved and only its indexing failed
+		if chunk.Status != int(types.ChunkStatusIndexFailed) {
+			chunk.Content = previous
+		}
+		return err
+	}
+
+	logger.Infof(ctx, "Edited chunk %s of knowledge %s", chunk.ID, chunk.KnowledgeID)
+	return nil
+}
+
+// SplitChunk splits a text chunk in two at a character offset and embeds both parts
+func (s *chunkService) SplitChunk(ctx context.Context, chunk *types.Chunk, offset int) ([]*types.Chunk, error) {
+	if chunk.ChunkType != types.ChunkTypeText {
+		return nil, werrors.NewBadRequestError("only text chunks can be split")
+	}
+	runes := []rune(chunk.Content)
+	if offset <= 0 || offset >= len(runes) {
+		return nil, werrors.NewBadRequestError(fmt.Sprintf("offset must be between 1 and %d", len(runes)-1))
+	}
+	first, second := string(runes[:offset]), string(runes[offset:])
+	if strings.TrimSpace(first) == "" || strings.TrimSpace(second) == "" {
+		return nil, werrors.NewBadRequestError("both parts of a split chunk must have content")
+	}
+
+	before := chunk.Content
+	// The generated questions were asked about the whole chunk, they are dropped with their index entries
+	droppedSourceIDs := generatedQuestionSourceIDs(chunk)
+	if len(droppedSourceIDs) > 0 {
+		meta, _ := chunk.DocumentMetadata()
+		meta.GeneratedQuestions = nil
+		if err := chunk.SetDocumentMetadata(meta); err != nil {
+			return nil, err
+		}
+	}
+
+	parts, _ := replaceChunks([]*types.Chunk{chunk}, []string{first, second})
+	changes := &types.ChunkEditChanges{
+		// Make room for the second part right after the chunk
+		ShiftFrom: chunk.ChunkIndex + 1,
+		ShiftBy:   1,
+		Updated:   parts[:1],
+		Created:   parts[1:],
+		Relinks:   relinkNextChunk(parts[1]),
+	}
+	edit := newChunkEdit(parts[0], types.ChunkEditOperationSplit, []string{before}, []string{first, second}, parts[1].ID)
+	if err := s.applyChunkEdit(ctx, parts, nil, droppedSourceIDs, edit, changes); err != nil {
+		return nil, err
+	}
+
+	logger.Infof(ctx, "Split chunk %s at offset %d into %s", chunk.ID, offset, parts[1].ID)
+	return parts, nil
+}
+
+// MergeChunks merges a text chunk with an adjacent one, the next one by default, and embeds the result
+func (s *chunkService) MergeChunks(ctx context.Context, chunk *types.Chunk, otherChunkID string) (*types.Chunk, error) {
+	if otherChunkID == "" {
+		otherChunkID = chunk.NextChunkID
+	}
+	if otherChunkID == "" {
+		return nil, werrors.NewBadRequestError("chunk has no next chunk to merge with")
+	}
+	if otherChunkID != chunk.NextChunkID && otherChunkID != chunk.PreChunkID {
+		return nil, werrors.NewBadRequestError("only adjacent chunks can be merged")
+	}
+	if chunk.ChunkType != types.ChunkTypeText {
+		return nil, werrors.NewBadRequestError("only text chunks can be merged")
+	}
+
+	tenantID := ctx.Value(types.TenantIDContextKey).(uint64)
+	other, err := s.chunkRepository.GetChunkByID(ctx, tenantID, otherChunkID)
+	if err != nil {
+		return nil, err
+	}
+	if other.ChunkType != types.ChunkTypeText || other.KnowledgeID != chunk.KnowledgeID {
+		return nil, werrors.NewBadRequestError("only adjacent chunks can be merged")
+	}
+
+	first, second := chunk, other
+	if otherChunkID == chunk.PreChunkID {
+		first, second = other, chunk
+	}
+	before := []string{first.Content, second.Content}
+	content := mergeChunkContents(first.Content, second.Content)
+
+	replacements, removed := replaceChunks([]*types.Chunk{first, second}, []string{content})
+	merged := replacements[0]
+	images, err := s.moveImageChunks(ctx, removed, merged.ID)
+	if err != nil {
+		return nil, err
+	}
+	droppedSourceIDs := make([]string, 0)
+	for _, removedChunk := range removed {
+		droppedSourceIDs = append(droppedSourceIDs, generatedQuestionSourceIDs(removedChunk)...)
+	}
+	changes := &types.ChunkEditChanges{
+		Updated:    append([]*types.Chunk{merged}, images...),
+		DeletedIDs: []string{second.ID},
+		Relinks:    relinkNextChunk(merged),
+	}
+	edit := newChunkEdit(merged, types.ChunkEditOperationMerge, before, []string{content}, second.ID)
+	if err := s.applyChunkEdit(ctx, []*types.Chunk{merged}, removed, droppedSourceIDs, edit, changes); err != nil {
+		return nil, err
+	}
+
+	logger.Infof(ctx, "Merged chunk %s into %s", second.ID, merged.ID)
+	return merged, nil
+}
+
+// ListChunkEdits lists the edit history of a chunk
+func (s *chunkService) ListChunkEdits(ctx context.Context, chunkID string) ([]*types.ChunkEdit, error) {
+	tenantID := ctx.Value(types.TenantIDContextKey).(uint64)
+	return s.chunkEditRepository.ListChunkEditsByChunkID(ctx, tenantID, chunkID)
+}
+
+// DeleteChunkEdits discards the manual edits of the given knowledge. The current chunks are kept,
+// the next re-ingestion produces the parsed chunks.
+func (s *chunkService) DeleteChunkEdits(ctx context.Context, knowledgeIDs []string) error {
+	tenantID := ctx.Value(types.TenantIDContextKey).(uint64)
+	return s.chunkEditRepository.DeleteChunkEdits(ctx, tenantID, knowledgeIDs)
+}
+
+// ReplayChunkEdits applies the manual edits of a knowledge to its freshly parsed text chunks
+func (s *chunkService) ReplayChunkEdits(ctx context.Context,
+	knowledgeID string, chunks []*types.Chunk,
+) ([]*types.Chunk, map[string]string) {
+	tenantID := ctx.Value(types.TenantIDContextKey).(uint64)
+	edits, err := s.chunkEditRepository.ListChunkEdits(ctx, tenantID, knowledgeID)
+	if err != nil {
+		logger.Warnf(ctx, "Failed to list chunk edits of knowledge %s, keeping parsed chunks: %v", knowledgeID, err)
+		return chunks, nil
+	}
+	if len(edits) == 0 {
+		return chunks, nil
+	}
+
+	chunks, merged := replayChunkEdits(edits, chunks)
+	orphaned := 0
+	for _, edit := range edits {
+		if edit.Orphaned {
+			orphaned++
+		}
+	}
+	logger.Infof(ctx, "Replayed %d manual chunk edits of knowledge %s, %d no longer match the document",
+		len(edits)-orphaned, knowledgeID, orphaned)
+	if err := s.chunkEditRepository.UpdateChunkEdits(ctx, edits); err != nil {
+		logger.Warnf(ctx, "Failed to update replayed chunk edits of knowledge %s: %v", knowledgeID, err)
+	}
+	return chunks, merged
+}
+
+// newChunkEdit builds the history entry of an edit to the chunks of a knowledge
+func newChunkEdit(chunk *types.Chunk,
+	operation types.ChunkEditOperation, before, after []string, relatedChunkID string,
+) *types.ChunkEdit {
+	return &types.ChunkEdit{
+		TenantID:        chunk.TenantID,
+		KnowledgeBaseID: chunk.KnowledgeBaseID,
+		KnowledgeID:     chunk.KnowledgeID,
+		ChunkID:         chunk.ID,
+		RelatedChunkID:  relatedChunkID,
+		Operation:       operation,
+		ContentsBefore:  before,
+		ContentsAfter:   after,
+		CreatedAt:       time.Now(),
+	}
+}
+
+// relinkNextChunk points the chunk after a split or merged chunk back at it
+func relinkNextChunk(chunk *types.Chunk) map[string]string {
+	if chunk.NextChunkID == "" {
+		return nil
+	}
+	return map[string]string{chunk.NextChunkID: chunk.ID}
+}
+
+// generatedQuestionSourceIDs returns the index source IDs of the generated questions of a chunk
+func generatedQuestionSourceIDs(chunk *types.Chunk) []string {
+	meta, err := chunk.DocumentMetadata()
+	if err != nil || meta == nil {
+		return nil
+	}
+	sourceIDs := make([]string, 0, len(meta.GeneratedQuestions))
+	for _, question := range meta.GeneratedQuestions {
+		sourceIDs = append(sourceIDs, fmt.Sprintf("%s-%s", chunk.ID, question.ID))
+	}
+	return sourceIDs
+}
+
+// moveImageChunks returns the image OCR and caption chunks of merged chunks, moved to the chunk they were merged into
+func (s *chunkService) moveImageChunks(ctx context.Context,
+	removed []*types.Chunk, parentID string,
+) ([]*types.Chunk, error) {
+	tenantID := ctx.Value(types.TenantIDContextKey).(uint64)
+	var images []*types.Chunk
+	for _, chunk := range removed {
+		related, err := s.chunkRepository.ListChunkByParentID(ctx, tenantID, chunk.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, image := range related {
+			if image.ChunkType != types.ChunkTypeImageOCR && image.ChunkType != types.ChunkTypeImageCaption {
+				continue
+			}
+			image.ParentChunkID = parentID
+			images = append(images, image)
+		}
+	}
+	return images, nil
+}
+
+// applyChunkEdit saves an edit of chunks and replaces their index entries with entries for their current
+// content. Child chunks are rebuilt when the knowledge base uses parent-child chunking. The new entries are
+// embedded before anything is written, so a failing embedding model leaves the chunks and the index as they
+// were. The chunk rows, the rebuilt child chunks and the edit history are then saved in one transaction.
+// Index backends add an entry again instead of overwriting it, so the entries kept under the same source
+// ID are deleted right before the new ones are saved, and the entries of chunks that no longer exist only
+// once the new entries are saved. When that fails the edited chunks are marked ChunkStatusIndexFailed.
+func (s *chunkService) applyChunkEdit(ctx context.Context, edited []*types.Chunk, removed []*types.Chunk,
+	droppedSourceIDs []string, edit *types.ChunkEdit, changes *types.ChunkEditChanges,
+) error {
+	tenantID := ctx.Value(types.TenantIDContextKey).(uint64)
+	kb, err := s.kbRepository.GetKnowledgeBaseByID(ctx, edited[0].KnowledgeBaseID)
+	if err != nil {
+		return fmt.Errorf("failed to get knowledge base: %w", err)
+	}
+	knowledge, err := s.knowledgeRepository.GetKnowledgeByID(ctx, tenantID, edited[0].KnowledgeID)
+	if err != nil {
+		return fmt.Errorf("failed to get knowledge: %w", err)
+	}
+	embeddingModel, err := s.modelService.GetEmbeddingModel(ctx, kb.EmbeddingModelID)
+	if err != nil {
+		return fmt.Errorf("failed to get embedding model: %w", err)
+	}
+	tenantInfo := ctx.Value(types.TenantInfoContextKey).(*types.Tenant)
+	retrieveEngine, err := retriever.NewCompositeRetrieveEngine(s.retrieveEngine, tenantInfo.GetEffectiveEngines())
+	if err != nil {
+		return fmt.Errorf("failed to create retrieve engine: %w", err)
+	}
+
+	// Entries of the edited chunks are replaced, those of removed chunks, children and dropped questions go
+	replacedSourceIDs := make([]string, 0, len(edited))
+	for _, chunk := range edited {
+		replacedSourceIDs = append(replacedSourceIDs, chunk.ID)
+	}
+	staleSourceIDs := slices.Clone(droppedSourceIDs)
+	for _, chunk := range removed {
+		staleSourceIDs = append(staleSourceIDs, chunk.ID)
+	}
+	for _, chunk := range slices.Concat(edited, removed) {
+		children, err := s.chunkRepository.ListChunkByParentID(ctx, tenantID, chunk.ID)
+		if err != nil {
+			return err
+		}
+		for _, child := range children {
+			if child.ChunkType == types.ChunkTypeChild {
+				staleSourceIDs = append(staleSourceIDs, child.ID)
+				changes.DeletedIDs = append(changes.DeletedIDs, child.ID)
+			}
+		}
+	}
+
+	indexed := edited
+	if kb.ChunkingConfig.EnableParentChild {
+		textChunks := make([]*types.Chunk, 0, len(edited))
+		indexed = make([]*types.Chunk, 0, len(edited))
+		for _, chunk := range edited {
+			if chunk.ChunkType == types.ChunkTypeText {
+				textChunks = append(textChunks, chunk)
+			} else {
+				indexed = append(indexed, chunk)
+			}
+		}
+		children, indexedTextChunks := buildChildChunks(kb.ChunkingConfig, embeddingTokenizer(ctx, s.modelService, kb), textChunks)
+		changes.Created = append(changes.Created, children...)
+		indexed = append(indexed, indexedTextChunks...)
+	}
+
+	indexInfoList := make([]*types.IndexInfo, 0, len(indexed))
+	contents := make([]string, 0, len(indexed))
+	for _, chunk := range indexed {
+		indexInfoList = append(indexInfoList, &types.IndexInfo{
+			Content:         chunk.Content,
+			SourceID:        chunk.ID,
+			SourceType:      types.ChunkSourceType,
+			ChunkID:         chunk.ID,
+			KnowledgeID:     chunk.KnowledgeID,
+			KnowledgeBaseID: chunk.KnowledgeBaseID,
+			Metadata:        knowledge.GetCustomMetadata(),
+		})
+		contents = append(contents, chunk.Content)
+	}
+	var embedder embedding.Embedder = embeddingModel
+	if retrieveEngine.SupportRetriever(types.VectorRetrieverType) {
+		vectors, err := embeddingModel.BatchEmbedWithPool(ctx, embeddingModel, contents)
+		if err != nil {
+			return fmt.Errorf("failed to re-embed edited chunks: %w", err)
+		}
+		precomputed := embedding.NewPrecomputedEmbedder(embeddingModel)
+		precomputed.Add(contents, vectors)
+		embedder = precomputed
+	}
+
+	for _, chunk := range edited {
+		chunk.Status = int(types.ChunkStatusIndexed)
+	}
+	if err := s.chunkEditRepository.ApplyChunkEdit(ctx, tenantID, knowledge.ID, edit, changes); err != nil {
+		return fmt.Errorf("failed to save edited chunks: %w", err)
+	}
+
+	if err := s.swapIndexEntries(ctx, retrieveEngine, embedder, kb, knowledge, edited, indexed,
+		indexInfoList, replacedSourceIDs, staleSourceIDs); err != nil {
+		for _, chunk := range edited {
+			chunk.Status = int(types.ChunkStatusIndexFailed)
+		}
+		if updateErr := s.chunkRepository.UpdateChunks(ctx, edited); updateErr != nil {
+			logger.Errorf(ctx, "Failed to mark edited chunks as failed to index: %v", updateErr)
+		}
+		return fmt.Errorf("edit saved but the chunks failed to index, send the content again to retry: %w", err)
+	}
+	return nil
+}
+
+// swapIndexEntries replaces the index entries of edited chunks with the new entries, see applyChunkEdit
+func (s *chunkService) swapIndexEntries(ctx context.Context,
+	retrieveEngine *retriever.CompositeRetrieveEngine, embedder embedding.Embedder,
+	kb *types.KnowledgeBase, knowledge *types.Knowledge, edited []*types.Chunk, indexed []*types.Chunk,
+	indexInfoList []*types.IndexInfo, replacedSourceIDs []string, staleSourceIDs []string,
+) error {
+	dimensions := embedder.GetDimensions()
+	if err := retrieveEngine.DeleteBySourceIDList(ctx, replacedSourceIDs, dimensions, kb.Type); err != nil {
+		return fmt.Errorf("failed to delete replaced index entries: %w", err)
+	}
+	if err := retrieveEngine.BatchIndex(ctx, embedder, indexInfoList); err != nil {
+		return fmt.Errorf("failed to index edited chunks: %w", err)
+	}
+	if len(staleSourceIDs) > 0 {
+		if err := retrieveEngine.DeleteBySourceIDList(ctx, staleSourceIDs, dimensions, kb.Type); err != nil {
+			logger.Warnf(ctx, "Failed to delete stale index entries of edited chunks: %v", err)
+		}
+	}
+	// Indexing adds entries enabled, entries that are out of retrieval are disabled again
+	chunkStatusMap := disabledIndexStatus(knowledge, edited, indexed, time.Now())
+	if len(chunkStatusMap) == 0 {
+		return nil
+	}
+	return retrieveEngine.BatchUpdateChunkEnabledStatus(ctx, chunkStatusMap)
+}
+
+// disabledIndexStatus returns the indexed chunks that must stay out of retrieval, mapped to false: all
+// of them when the knowledge is disabled or expired, otherwise the disabled chunks and the children of
+// disabled edited chunks
+func disabledIndexStatus(knowledge *types.Knowledge, edited []*types.Chunk, indexed []*types.Chunk,
+	now time.Time,
+) map[string]bool {
+	knowledgeDisabled := !knowledgeInRetrieval(knowledge, now)
+	disabledParents := make(map[string]bool)
+	for _, chunk := range edited {
+		if !chunk.IsEnabled {
+			disabledParents[chunk.ID] = true
+		}
+	}
+	chunkStatusMap := make(map[string]bool)
+	for _, chunk := range indexed {
+		if knowledgeDisabled || !chunk.IsEnabled || disabledParents[chunk.ParentChunkID] {
+			chunkStatusMap[chunk.ID] = false
+		}
+	}
+	return chunkStatusMap
+}
+
+// replaceChunks replaces consecutive text chunks with chunks holding the given contents. The first chunk
+// is kept and updated, further contents become new chunks after it and the other replaced chunks are
+// returned as removed. The document range of the chunks is spread over the new ones by length.
+func replaceChunks(run []*types.Chunk, contents []string) (replacements []*types.Chunk, removed []*types.Chunk) {
+	kept, last := run[0], run[len(run)-1]
+	start, end, nextChunkID := kept.StartAt, last.EndAt, last.NextChunkID
+	removed = run[1:]
+	if len(removed) > 0 {
+		kept.ImageInfo = mergeImageInfo(run)
+	}
+
+	now := time.Now()
+	pos := start
+	for i, content := range contents {
+		chunk := kept
+		if i > 0 {
+			chunk = &types.Chunk{
+				ID:              uuid.New().String(),
+				TenantID:        kept.TenantID,
+				KnowledgeID:     kept.KnowledgeID,
+				KnowledgeBaseID: kept.KnowledgeBaseID,
+				ChunkIndex:      kept.ChunkIndex + i,
+				IsEnabled:       kept.IsEnabled,
+				Flags:           kept.Flags,
+				ChunkType:       types.ChunkTypeText,
+				CreatedAt:       now,
+			}
+			replacements[i-1].NextChunkID = chunk.ID
+			chunk.PreChunkID = replacements[i-1].ID
+		}
+		chunk.Content = content
+		chunk.StartAt = pos
+		pos = min(pos+utf8.RuneCountInString(content), end)
+		if i == len(contents)-1 {
+			pos = end
+		}
+		chunk.EndAt = pos
+		chunk.UpdatedAt = now
+		replacements = append(replacements, chunk)
+	}
+	replacements[len(replacements)-1].NextChunkID = nextChunkID
+	return replacements, removed
+}
+
+// mergeImageInfo concatenates the images of chunks
+func mergeImageInfo(chunks []*types.Chunk) string {
+	var images []types.ImageInfo
+	for _, chunk := range chunks {
+		var chunkImages []types.ImageInfo
+		if chunk.ImageInfo != "" && json.Unmarshal([]byte(chunk.ImageInfo), &chunkImages) == nil {
+			images = append(images, chunkImages...)
+		}
+	}
+	if len(images) == 0 {
+		return ""
+	}
+	data, _ := json.Marshal(images)
+	return string(data)
+}
+
+// mergeChunkContents joins adjacent chunks, dropping the overlap the chunker repeated at the start of the second
+func mergeChunkContents(first, second string) string {
+	for k := min(len(first), len(second)); k >= minMergeOverlap; k-- {
+		if strings.HasSuffix(first, second[:k]) {
+			return first + second[k:]
+		}
+	}
+	return first + "\n" + second
+}
+
+// replayChunkEdits applies edits in order to text chunks in document order. An edit applies where
+// consecutive chunks match its contents before. Edits whose result is already in the chunks, as when
+// stored chunks are indexed again, are kept, the others are marked orphaned. Returns the resulting chunks
+// and, for each chunk removed by a merge, the chunk it was merged into.
+func replayChunkEdits(edits []*types.ChunkEdit, chunks []*types.Chunk) ([]*types.Chunk, map[string]string) {
+	merged := make(map[string]string)
+	resolved := make([]bool, len(edits))
+	for i, edit := range edits {
+		at := findChunkRun(chunks, edit.ContentsBefore)
+		if at < 0 || len(edit.ContentsAfter) == 0 {
+			continue
+		}
+		run := chunks[at : at+len(edit.ContentsBefore)]
+		rest := chunks[at+len(run):]
+		replacements, removed := replaceChunks(run, edit.ContentsAfter)
+		if extra := len(replacements) - len(run); extra > 0 {
+			for _, chunk := range rest {
+				chunk.ChunkIndex += extra
+			}
+		}
+		for _, chunk := range removed {
+			for from, to := range merged {
+				if to == chunk.ID {
+					merged[from] = replacements[0].ID
+				}
+			}
+			merged[chunk.ID] = replacements[0].ID
+		}
+		chunks = slices.Concat(chunks[:at], replacements, rest)
+
+		edit.ChunkID = replacements[0].ID
+		switch {
+		case len(replacements) > 1:
+			edit.RelatedChunkID = replacements[1].ID
+		case len(removed) > 0:
+			edit.RelatedChunkID = removed[0].ID
+		}
+		resolved[i] = true
+	}
+
+	// Latest first, so that an edit followed by other edits of its result resolves through them
+	for i := len(edits) - 1; i >= 0; i-- {
+		if resolved[i] {
+			if survivor, ok := merged[edits[i].ChunkID]; ok {
+				edits[i].ChunkID = survivor
+			}
+			edits[i].Orphaned = false
+			continue
+		}
+		edit := edits[i]
+		if at := findChunkRun(chunks, edit.ContentsAfter); at >= 0 {
+			edit.ChunkID = chunks[at].ID
+			if len(edit.ContentsAfter) > 1 {
+				edit.RelatedChunkID = chunks[at+1].ID
+			}
+			resolved[i] = true
+		} else {
+			for j := i + 1; j < len(edits) && !resolved[i]; j++ {
+				if resolved[j] && !edits[j].Orphaned && sharesContent(edits[j].ContentsBefore, edit.ContentsAfter) {
+					edit.ChunkID = edits[j].ChunkID
+					resolved[i] = true
+				}
+			}
+		}
+		edit.Orphaned = !resolved[i]
+	}
+	return chunks, merged
+}
+
+// findChunkRun returns the position of the consecutive chunks with the given contents, or -1
+func findChunkRun(chunks []*types.Chunk, contents []string) int {
+	if len(contents) == 0 {
+		return -1
+	}
+	for at := 0; at+len(contents) <= len(chunks); at++ {
+		match := true
+		for k, content := range contents {
+			if chunks[at+k].Content != content {
+				match = false
+				break
+			}
+		}
+		if match {
+			return at
+		}
+	}
+	return -1
+}
+
+// sharesContent reports whether two content lists have a content in common
+func sharesContent(a, b []string) bool {
+	for _, content := range a {
+		if slices.Contains(b, content) {
+			return true
+		}
+	}
+	return false
+}
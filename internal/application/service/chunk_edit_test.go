@@ -0,0 +1,185 @@
+package service
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+func TestReplayChunkEdits(t *testing.T) {
+	newEdits := func() []*types.ChunkEdit {
+		return []*types.ChunkEdit{
+			{Operation: types.ChunkEditOperationEdit, ContentsBefore: []string{"b"}, ContentsAfter: []string{"B"}},
+			{Operation: types.ChunkEditOperationSplit, ContentsBefore: []string{"c"}, ContentsAfter: []string{"c1", "c2"}},
+			{Operation: types.ChunkEditOperationMerge, ContentsBefore: []string{"a", "B"}, ContentsAfter: []string{"aB"}},
+		}
+	}
+	newChunks := func(contents ...string) []*types.Chunk {
+		chunks := make([]*types.Chunk, 0, len(contents))
+		for i, content := range contents {
+			chunks = append(chunks, &types.Chunk{
+				ID: "chunk-" + content, Content: content, ChunkIndex: i, ChunkType: types.ChunkTypeText,
+			})
+		}
+		return chunks
+	}
+
+	tests := []struct {
+		name         string
+		chunks       []*types.Chunk
+		wantContents []string
+		wantMerged   map[string]string
+		wantOrphaned []bool
+	}{
+		{
+			name:         "edits applied to parsed chunks",
+			chunks:       newChunks("a", "b", "c", "d"),
+			wantContents: []string{"aB", "c1", "c2", "d"},
+			wantMerged:   map[string]string{"chunk-b": "chunk-a"},
+			wantOrphaned: []bool{false, false, false},
+		},
+		{
+			name:         "edits already in stored chunks",
+			chunks:       newChunks("aB", "c1", "c2", "d"),
+			wantContents: []string{"aB", "c1", "c2", "d"},
+			wantMerged:   map[string]string{},
+			wantOrphaned: []bool{false, false, false},
+		},
+		{
+			name:         "document changed",
+			chunks:       newChunks("a", "x", "c"),
+			wantContents: []string{"a", "x", "c1", "c2"},
+			wantMerged:   map[string]string{},
+			wantOrphaned: []bool{true, false, true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edits := newEdits()
+			chunks, merged := replayChunkEdits(edits, tt.chunks)
+
+			contents := make([]string, 0, len(chunks))
+			for i, chunk := range chunks {
+				contents = append(contents, chunk.Content)
+				if i > 0 && chunk.ChunkIndex <= chunks[i-1].ChunkIndex {
+					t.Errorf("chunk %q index %d not after %d", chunk.Content, chunk.ChunkIndex, chunks[i-1].ChunkIndex)
+				}
+			}
+			if !slices.Equal(contents, tt.wantContents) {
+				t.Errorf("contents = %v, want %v", contents, tt.wantContents)
+			}
+			if len(merged) != len(tt.wantMerged) {
+				t.Errorf("merged = %v, want %v", merged, tt.wantMerged)
+			}
+			for from, to := range tt.wantMerged {
+				if merged[from] != to {
+					t.Errorf("merged[%s] = %s, want %s", from, merged[from], to)
+				}
+			}
+			for i, edit := range edits {
+				if edit.Orphaned != tt.wantOrphaned[i] {
+					t.Errorf("edit %d orphaned = %v, want %v", i, edit.Orphaned, tt.wantOrphaned[i])
+				}
+				if !edit.Orphaned && !slices.ContainsFunc(chunks, func(c *types.Chunk) bool { return c.ID == edit.ChunkID }) {
+					t.Errorf("edit %d points at missing chunk %q", i, edit.ChunkID)
+				}
+			}
+		})
+	}
+}
+
+func TestReplaceChunks(t *testing.T) {
+	chunk := &types.Chunk{
+		ID: "a", Content: "abcdefghij", ChunkIndex: 3, StartAt: 10, EndAt: 20,
+		ChunkType: types.ChunkTypeText, NextChunkID: "next",
+	}
+	parts, removed := replaceChunks([]*types.Chunk{chunk}, []string{"abcd", "efghij"})
+	if len(parts) != 2 || len(removed) != 0 {
+		t.Fatalf("got %d parts and %d removed, want 2 and 0", len(parts), len(removed))
+	}
+	first, second := parts[0], parts[1]
+	if first.ID != "a" || first.StartAt != 10 || first.EndAt != 14 || first.NextChunkID != second.ID {
+		t.Errorf("first part = %+v", first)
+	}
+	if second.ChunkIndex != 4 || second.StartAt != 14 || second.EndAt != 20 ||
+		second.PreChunkID != "a" || second.NextChunkID != "next" {
+		t.Errorf("second part = %+v", second)
+	}
+}
+
+func TestMergeChunkContents(t *testing.T) {
+	overlap := strings.Repeat("o", minMergeOverlap)
+	tests := []struct {
+		name   string
+		first  string
+		second string
+		want   string
+	}{
+		{"overlap dropped", "first " + overlap, overlap + " second", "first " + overlap + " second"},
+		{"short match kept", "first oo", "oo second", "first oo\noo second"},
+		{"no overlap", "first", "second", "first\nsecond"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeChunkContents(tt.first, tt.second); got != tt.want {
+				t.Errorf("mergeChunkContents() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDisabledIndexStatus(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-time.Hour)
+	enabled := &types.Chunk{ID: "a", IsEnabled: true}
+	disabled := &types.Chunk{ID: "b", IsEnabled: false}
+	child := &types.Chunk{ID: "b-1", IsEnabled: true, ParentChunkID: "b"}
+	tests := []struct {
+		name      string
+		knowledge *types.Knowledge
+		indexed   []*types.Chunk
+		want      []string
+	}{
+		{"enabled knowledge", &types.Knowledge{EnableStatus: types.KnowledgeEnableStatusEnabled},
+			[]*types.Chunk{enabled, disabled}, []string{"b"}},
+		{"children of disabled chunk", &types.Knowledge{}, []*types.Chunk{enabled, child}, []string{"b-1"}},
+		{"disabled knowledge", &types.Knowledge{EnableStatus: types.KnowledgeEnableStatusDisabled},
+			[]*types.Chunk{enabled, disabled}, []string{"a", "b"}},
+		{"expired knowledge", &types.Knowledge{EnableStatus: types.KnowledgeEnableStatusEnabled, ValidUntil: &past},
+			[]*types.Chunk{enabled}, []string{"a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := disabledIndexStatus(tt.knowledge, []*types.Chunk{enabled, disabled}, tt.indexed, now)
+			var ids []string
+			for id, status := range got {
+				if status {
+					t.Errorf("chunk %s mapped to enabled", id)
+				}
+				ids = append(ids, id)
+			}
+			slices.Sort(ids)
+			if !slices.Equal(ids, tt.want) {
+				t.Errorf("disabled chunks = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeneratedQuestionSourceIDs(t *testing.T) {
+	chunk := &types.Chunk{ID: "c1"}
+	if ids := generatedQuestionSourceIDs(chunk); len(ids) != 0 {
+		t.Errorf("chunk without metadata: got %v", ids)
+	}
+	if err := chunk.SetDocumentMetadata(&types.DocumentChunkMetadata{
+		GeneratedQuestions: []types.GeneratedQuestion{{ID: "q1"}, {ID: "q2"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if ids := generatedQuestionSourceIDs(chunk); !slices.Equal(ids, []string{"c1-q1", "c1-q2"}) {
+		t.Errorf("got %v, want the question source IDs", ids)
+	}
+}
@@ -19,6 +19,7 @@ var (
 	ErrCannotModifyBuiltin = errors.New("cannot modify built-in agent basic info")
 	ErrCannotDeleteBuiltin = errors.New("cannot delete built-in agent")
 	ErrAgentNameRequired   = errors.New("agent name is required")
+	ErrInvalidTrustWeights = errors.New("web search trust weights must be non-negative")
 )
 
 // customAgentService implements the CustomAgentService interface
@@ -39,6 +40,9 @@ func (s *customAgentService) CreateAgent(ctx context.Context, agent *types.Custo
 	if strings.TrimSpace(agent.Name) == "" {
 		return nil, ErrAgentNameRequired
 	}
+	if types.ValidateWebSearchTrustWeights(agent.Config.WebSearchTrustWeights) != nil {
+		return nil, ErrInvalidTrustWeights
+	}
 
 	// Generate UUID and set creation timestamps
 	if agent.ID == "" {
@@ -193,6 +197,10 @@ func (s *customAgentService) UpdateAgent(ctx context.Context, agent *types.Custo
 		return nil, ErrInvalidTenantID
 	}
 
+	if types.ValidateWebSearchTrustWeights(agent.Config.WebSearchTrustWeights) != nil {
+		return nil, ErrInvalidTrustWeights
+	}
+
 	// Handle built-in agents specially using registry
 	if types.IsBuiltinAgentID(agent.ID) {
 		return s.updateBuiltinAgent(ctx, agent, tenantID)
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Tencent/WeKnora/docreader/proto"
+	"github.com/Tencent/WeKnora/internal/chunker"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/models/embedding"
+	"github.com/Tencent/WeKnora/internal/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultDocReaderStreamThresholdMB is the file size from which documents are uploaded to docreader in parts
+const defaultDocReaderStreamThresholdMB = 20
+
+// docReaderStreamThreshold returns the file size in bytes from which documents are uploaded to docreader
+// in parts, set by DOCREADER_STREAM_THRESHOLD_MB. Zero disables streaming.
+func docReaderStreamThreshold() int64 {
+	thresholdMB := defaultDocReaderStreamThresholdMB
+	if value := os.Getenv("DOCREADER_STREAM_THRESHOLD_MB"); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil && parsed >= 0 {
+			thresholdMB = parsed
+		}
+	}
+	return int64(thresholdMB) * 1024 * 1024
+}
+
+// useDocReaderStream reports whether a file is uploaded to docreader in parts instead of in one message.
+// Text and Markdown are read whole, they may be chunked natively.
+func useDocReaderStream(fileType string, fileSize int64) bool {
+	threshold := docReaderStreamThreshold()
+	return threshold > 0 && fileSize >= threshold && !chunker.IsNativeFileType(fileType)
+}
+
+// newReadConfig builds the docreader read config of a knowledge base
+func newReadConfig(kb *types.KnowledgeBase, enableMultimodal bool, vlmConfig *proto.VLMConfig) *proto.ReadConfig {
+	return &proto.ReadConfig{
+		ChunkSize:        int32(kb.ChunkingConfig.ChunkSize),
+		ChunkOverlap:     int32(kb.ChunkingConfig.ChunkOverlap),
+		Separators:       kb.ChunkingConfig.Separators,
+		EnableMultimodal: enableMultimodal,
+		StorageConfig: &proto.StorageConfig{
+			Provider:        proto.StorageProvider(proto.StorageProvider_value[strings.ToUpper(kb.StorageConfig.Provider)]),
+			Region:          kb.StorageConfig.Region,
+			BucketName:      kb.StorageConfig.BucketName,
+			AccessKeyId:     kb.StorageConfig.SecretID,
+			SecretAccessKey: kb.StorageConfig.SecretKey,
+			AppId:           kb.StorageConfig.AppID,
+			PathPrefix:      kb.StorageConfig.PathPrefix,
+		},
+		VlmConfig: vlmConfig,
+	}
+}
+
+// isDocReaderStreamUnsupported reports whether docreader is an older version without the streaming read
+func isDocReaderStreamUnsupported(err error) bool {
+	return status.Code(err) == codes.Unimplemented
+}
+
+// readFromDocReaderStream uploads the file to docreader in parts and collects the chunks streamed back.
+// While docreader is still parsing, each received batch is embedded in the background. The returned
+// embedder holds these vectors, indexing the chunks with it only embeds the chunks that were missed.
+// It is nil when the chunks are not embedded ahead, see startEmbeddingWarmup.
+func (s *knowledgeService) readFromDocReaderStream(ctx context.Context,
+	kb *types.KnowledgeBase, r io.Reader, req *proto.ReadFromFileStreamRequest,
+) ([]*proto.Chunk, *embedding.PrecomputedEmbedder, error) {
+	var chunks []*proto.Chunk
+	warmup := s.startEmbeddingWarmup(ctx, kb)
+
+	err := s.docReaderClient.ReadFromFileInParts(ctx, r, req, func(batch []*proto.Chunk) error {
+		chunks = append(chunks, batch...)
+		warmup.add(batch)
+		return nil
+	})
+	precomputed := warmup.wait()
+	if err != nil {
+		return nil, nil, err
+	}
+	logger.Infof(ctx, "Received %d chunks from docreader stream", len(chunks))
+	return chunks, precomputed, nil
+}
+
+// embeddingWarmup embeds chunk batches in the background, keeping their vectors for indexing.
+// A nil warmup ignores the batches.
+type embeddingWarmup struct {
+	embedder *embedding.PrecomputedEmbedder
+	batches  chan []*proto.Chunk
+	done     chan struct{}
+}
+
+// startEmbeddingWarmup starts embedding chunks ahead of indexing. Returns nil when it is pointless:
+// with parent-child chunking, where the chunks themselves are not embedded, or with a chunking
+// strategy other than characters, where the docreader chunks are split again.
+func (s *knowledgeService) startEmbeddingWarmup(ctx context.Context, kb *types.KnowledgeBase) *embeddingWarmup {
+	if kb.ChunkingConfig.EnableParentChild || kb.ChunkingConfig.GetStrategy() != types.ChunkingStrategyCharacter {
+		return nil
+	}
+	embeddingModel, err := s.modelService.GetEmbeddingModel(ctx, kb.EmbeddingModelID)
+	if err != nil {
+		logger.Warnf(ctx, "Skipping embedding warmup, failed to get embedding model: %v", err)
+		return nil
+	}
+
+	w := &embeddingWarmup{
+		embedder: embedding.NewPrecomputedEmbedder(embeddingModel),
+		batches:  make(chan []*proto.Chunk, 16),
+		done:     make(chan struct{}),
+	}
+	go func() {
+		defer close(w.done)
+		for batch := range w.batches {
+			warmupEmbeddings(ctx, w.embedder, embeddingModel, batch)
+		}
+	}()
+	return w
+}
+
+// add queues a batch of chunks to embed
+func (w *embeddingWarmup) add(batch []*proto.Chunk) {
+	if w != nil {
+		w.batches <- batch
+	}
+}
+
+// wait returns the embedder holding the vectors once every queued batch is embedded
+func (w *embeddingWarmup) wait() *embedding.PrecomputedEmbedder {
+	if w == nil {
+		return nil
+	}
+	close(w.batches)
+	<-w.done
+	return w.embedder
+}
+
+// warmupEmbeddings embeds the chunk contents and adds their vectors to the precomputed embedder.
+// Failures only leave the chunks to be embedded when they are indexed.
+func warmupEmbeddings(ctx context.Context, precomputed *embedding.PrecomputedEmbedder,
+	embeddingModel embedding.Embedder, batch []*proto.Chunk,
+) {
+	texts := make([]string, 0, len(batch))
+	for _, chunk := range batch {
+		if chunk.Content != "" {
+			texts = append(texts, chunk.Content)
+		}
+	}
+	if len(texts) == 0 {
+		return
+	}
+	vectors, err := embeddingModel.BatchEmbedWithPool(ctx, embeddingModel, texts)
+	if err != nil {
+		logger.Warnf(ctx, "Embedding warmup failed for %d chunks: %v", len(texts), err)
+		return
+	}
+	precomputed.Add(texts, vectors)
+}
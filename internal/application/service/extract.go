@@ -76,6 +76,11 @@ Please output in the following format (one paragraph per column):
 - If enumeration value ranges can be inferred from sample data, summarize them (e.g., status field contains pending/in-progress/completed statuses)`
 )
 
+// isGraphExtractionAvailable reports whether the graph database chunk extraction writes to is enabled
+func isGraphExtractionAvailable() bool {
+	return strings.ToLower(os.Getenv("NEO4J_ENABLE")) == "true"
+}
+
 // NewChunkExtractTask creates a new chunk extract task
 func NewChunkExtractTask(
 	ctx context.Context,
@@ -84,7 +89,7 @@ func NewChunkExtractTask(
 	chunkID string,
 	modelID string,
 ) error {
-	if strings.ToLower(os.Getenv("NEO4J_ENABLE")) != "true" {
+	if !isGraphExtractionAvailable() {
 		logger.Warn(ctx, "NEO4J is not enabled, skip chunk extract task")
 		return nil
 	}
@@ -142,6 +147,7 @@ type ChunkExtractService struct {
 	knowledgeBaseRepo interfaces.KnowledgeBaseRepository
 	chunkRepo         interfaces.ChunkRepository
 	graphEngine       interfaces.RetrieveGraphRepository
+	stageRepo         interfaces.KnowledgeStageRepository
 }
 
 // NewChunkExtractService creates a new chunk extract service
@@ -151,6 +157,7 @@ func NewChunkExtractService(
 	knowledgeBaseRepo interfaces.KnowledgeBaseRepository,
 	chunkRepo interfaces.ChunkRepository,
 	graphEngine interfaces.RetrieveGraphRepository,
+	stageRepo interfaces.KnowledgeStageRepository,
 ) interfaces.TaskHandler {
 	// generator := chatpipline.NewQAPromptGenerator(chatpipline.NewFormater(), config.ExtractManager.ExtractGraph)
 	// ctx := context.Background()
@@ -162,11 +169,12 @@ func NewChunkExtractService(
 		knowledgeBaseRepo: knowledgeBaseRepo,
 		chunkRepo:         chunkRepo,
 		graphEngine:       graphEngine,
+		stageRepo:         stageRepo,
 	}
 }
 
 // Handle handles the chunk extraction task
-func (s *ChunkExtractService) Handle(ctx context.Context, t *asynq.Task) error {
+func (s *ChunkExtractService) Handle(ctx context.Context, t *asynq.Task) (err error) {
 	var p types.ExtractChunkPayload
 	if err := json.Unmarshal(t.Payload(), &p); err != nil {
 		logger.Errorf(ctx, "failed to unmarshal task payload: %v", err)
@@ -181,6 +189,8 @@ func (s *ChunkExtractService) Handle(ctx context.Context, t *asynq.Task) error {
 		logger.Errorf(ctx, "failed to get chunk: %v", err)
 		return err
 	}
+	knowledgeID := chunk.KnowledgeID
+	defer func() { s.recordStage(ctx, knowledgeID, err) }()
 	kb, err := s.knowledgeBaseRepo.GetKnowledgeBaseByID(ctx, chunk.KnowledgeBaseID)
 	if err != nil {
 		logger.Errorf(ctx, "failed to get knowledge base: %v", err)
@@ -233,6 +243,25 @@ func (s *ChunkExtractService) Handle(ctx context.Context, t *asynq.Task) error {
 	return nil
 }
 
+// recordStage counts the chunk towards the graph extraction stage of its knowledge.
+// The stage fails once a chunk fails its last attempt.
+func (s *ChunkExtractService) recordStage(ctx context.Context, knowledgeID string, err error) {
+	if err == nil {
+		err = s.stageRepo.CompleteStageItem(ctx, knowledgeID, types.ProcessingStageGraphExtraction)
+	} else {
+		retryCount, _ := asynq.GetRetryCount(ctx)
+		maxRetry, _ := asynq.GetMaxRetry(ctx)
+		if retryCount < maxRetry {
+			return
+		}
+		err = s.stageRepo.FinishStage(ctx, knowledgeID, types.ProcessingStageGraphExtraction,
+			types.StageStatusFailed, err.Error())
+	}
+	if err != nil {
+		logger.Warnf(ctx, "Failed to record graph extraction of knowledge %s: %v", knowledgeID, err)
+	}
+}
+
 // DataTableExtractPayload represents the table extract task payload
 type DataTableSummaryPayload struct {
 	TenantID       uint64 `json:"tenant_id"`
@@ -0,0 +1,420 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	werrors "github.com/Tencent/WeKnora/internal/errors"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+	secutils "github.com/Tencent/WeKnora/internal/utils"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	faqConflictReportKeyPrefix = "faq_conflict_report:"
+	faqConflictReportTTL       = 24 * time.Hour
+	// faqConflictMatchCount is the number of existing entries searched per question
+	faqConflictMatchCount = 5
+	// faqConflictProgressInterval is the number of entries scanned between two report saves
+	faqConflictProgressInterval = 20
+)
+
+// getFAQConflictReportKey returns the Redis key for storing the conflict report of a knowledge base
+func getFAQConflictReportKey(kbID string) string {
+	return faqConflictReportKeyPrefix + kbID
+}
+
+// faqEntryQuestions returns the standard question followed by the similar questions
+func faqEntryQuestions(standard string, similar []string) []string {
+	questions := make([]string, 0, len(similar)+1)
+	for _, q := range append([]string{standard}, similar...) {
+		if q = strings.TrimSpace(q); q != "" {
+			questions = append(questions, q)
+		}
+	}
+	return questions
+}
+
+// sameFAQAnswers reports whether two entries give the same answers, ignoring order,
+// case and surrounding whitespace
+func sameFAQAnswers(a, b []string) bool {
+	normalize := func(answers []string) map[string]struct{} {
+		set := make(map[string]struct{}, len(answers))
+		for _, answer := range answers {
+			if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "" {
+				set[answer] = struct{}{}
+			}
+		}
+		return set
+	}
+	setA, setB := normalize(a), normalize(b)
+	if len(setA) != len(setB) {
+		return false
+	}
+	for answer := range setA {
+		if _, ok := setB[answer]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// suggestFAQMerge proposes keeping the best matching entry and adding the questions of the
+// checked entry to it. removeEntryID is the checked entry when it already exists.
+func suggestFAQMerge(questions []string, matches []types.FAQConflictMatch,
+	removeEntryID int64,
+) *types.FAQMergeSuggestion {
+	if len(matches) == 0 {
+		return nil
+	}
+	target := matches[0]
+	existing := map[string]struct{}{strings.TrimSpace(target.StandardQuestion): {}}
+	for _, match := range matches {
+		if match.EntryID == target.EntryID {
+			existing[strings.TrimSpace(match.MatchedQuestion)] = struct{}{}
+		}
+	}
+	similar := make([]string, 0, len(questions))
+	for _, q := range questions {
+		if _, ok := existing[q]; ok {
+			continue
+		}
+		existing[q] = struct{}{}
+		similar = append(similar, q)
+	}
+	return &types.FAQMergeSuggestion{
+		TargetEntryID:    target.EntryID,
+		SimilarQuestions: similar,
+		RemoveEntryID:    removeEntryID,
+	}
+}
+
+// findFAQConflicts searches the FAQ vector index for existing entries with questions
+// near-identical to the given ones, keeping the best match per entry, best first.
+// excludeEntryID skips the checked entry itself when it already exists.
+func (s *knowledgeService) findFAQConflicts(ctx context.Context, kbID string,
+	questions []string, answers []string, excludeEntryID int64, threshold float64,
+) ([]types.FAQConflictMatch, error) {
+	if threshold <= 0 {
+		threshold = types.DefaultFAQConflictThreshold
+	}
+	best := make(map[int64]types.FAQConflictMatch)
+	for _, question := range questions {
+		entries, err := s.SearchFAQEntries(ctx, kbID, &types.FAQSearchRequest{
+			QueryText:       question,
+			VectorThreshold: threshold,
+			MatchCount:      faqConflictMatchCount,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			if entry.ID == excludeEntryID || entry.Score < threshold {
+				continue
+			}
+			if prev, ok := best[entry.ID]; ok && prev.Score >= entry.Score {
+				continue
+			}
+			matchType := types.FAQConflictTypeConflict
+			if sameFAQAnswers(answers, entry.Answers) {
+				matchType = types.FAQConflictTypeOverlap
+			}
+			matched := entry.MatchedQuestion
+			if matched == "" {
+				matched = entry.StandardQuestion
+			}
+			best[entry.ID] = types.FAQConflictMatch{
+				Type:             matchType,
+				Question:         question,
+				MatchedQuestion:  matched,
+				Score:            entry.Score,
+				EntryID:          entry.ID,
+				StandardQuestion: entry.StandardQuestion,
+				Answers:          entry.Answers,
+			}
+		}
+	}
+
+	matches := make([]types.FAQConflictMatch, 0, len(best))
+	for _, match := range best {
+		matches = append(matches, match)
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].EntryID < matches[j].EntryID
+	})
+	return matches, nil
+}
+
+// checkFAQImportConflicts checks the valid entries of an import dry run against the
+// existing entries and records the conflicts in the progress
+func (s *knowledgeService) checkFAQImportConflicts(ctx context.Context,
+	payload *types.FAQImportPayload, validEntryIndices []int, progress *types.FAQImportProgress,
+) {
+	// 替换模式会删除现有条目，无需检测
+	if payload.Mode == types.FAQBatchModeReplace || len(validEntryIndices) == 0 {
+		return
+	}
+	progress.Message = "正在检测与现有条目的冲突..."
+	if err := s.saveFAQImportProgress(ctx, progress); err != nil {
+		logger.Warnf(ctx, "Failed to update FAQ import progress: %v", err)
+	}
+
+	checked := 0
+	for _, idx := range validEntryIndices {
+		entry := payload.Entries[idx]
+		questions := faqEntryQuestions(entry.StandardQuestion, entry.SimilarQuestions)
+		if checked+len(questions) > types.MaxFAQConflictImportQuestions {
+			progress.ConflictsTruncated = true
+			break
+		}
+		checked += len(questions)
+
+		matches, err := s.findFAQConflicts(ctx, payload.KBID, questions, entry.Answers, 0,
+			types.DefaultFAQConflictThreshold)
+		if err != nil {
+			// 冲突检测仅作提示，失败时不影响校验结果
+			logger.Warnf(ctx, "Failed to check FAQ import conflicts: %v", err)
+			return
+		}
+		if len(matches) == 0 {
+			continue
+		}
+		progress.Conflicts = append(progress.Conflicts, types.FAQImportConflict{
+			Index:            idx,
+			StandardQuestion: strings.TrimSpace(entry.StandardQuestion),
+			Matches:          matches,
+			Suggestion:       suggestFAQMerge(questions, matches, 0),
+		})
+	}
+	logger.Infof(ctx, "FAQ import conflict check: %d entries with conflicts, truncated=%v",
+		len(progress.Conflicts), progress.ConflictsTruncated)
+}
+
+// StartFAQConflictScan starts scanning a FAQ knowledge base for conflicting entries
+func (s *knowledgeService) StartFAQConflictScan(ctx context.Context,
+	kbID string, req *types.FAQConflictScanRequest,
+) (string, error) {
+	if req == nil {
+		req = &types.FAQConflictScanRequest{}
+	}
+	if err := req.Validate(); err != nil {
+		return "", werrors.NewBadRequestError(err.Error())
+	}
+	kb, err := s.validateFAQKnowledgeBase(ctx, kbID)
+	if err != nil {
+		return "", err
+	}
+	tenantID := ctx.Value(types.TenantIDContextKey).(uint64)
+
+	if report, err := s.loadFAQConflictReport(ctx, kb.ID); err == nil && report != nil &&
+		report.Status == types.FAQConflictScanStatusProcessing {
+		return "", werrors.NewBadRequestError("该知识库正在进行冲突扫描，请稍后再试")
+	}
+
+	threshold := req.Threshold
+	if threshold <= 0 {
+		threshold = types.DefaultFAQConflictThreshold
+	}
+	taskID := secutils.GenerateTaskID("faq_conflict", tenantID, kb.ID)
+	report := &types.FAQConflictReport{
+		TaskID:          taskID,
+		KnowledgeBaseID: kb.ID,
+		Status:          types.FAQConflictScanStatusProcessing,
+		Threshold:       threshold,
+		Groups:          make([]types.FAQConflictGroup, 0),
+		StartedAt:       time.Now(),
+	}
+	if err := s.saveFAQConflictReport(ctx, report); err != nil {
+		return "", err
+	}
+
+	payloadBytes, err := json.Marshal(types.FAQConflictScanPayload{
+		TenantID:        tenantID,
+		KnowledgeBaseID: kb.ID,
+		TaskID:          taskID,
+		Threshold:       threshold,
+	})
+	if err != nil {
+		return "", err
+	}
+	if _, err := s.task.Enqueue(asynq.NewTask(types.TypeFAQConflictScan, payloadBytes,
+		asynq.Queue("low"), asynq.MaxRetry(0))); err != nil {
+		logger.Errorf(ctx, "Failed to enqueue FAQ conflict scan task: %v", err)
+		_ = s.redisClient.Del(ctx, getFAQConflictReportKey(kb.ID)).Err()
+		return "", fmt.Errorf("failed to enqueue task: %w", err)
+	}
+	logger.Infof(ctx, "FAQ conflict scan task enqueued: %s, kb: %s", taskID, kb.ID)
+	return taskID, nil
+}
+
+// ProcessFAQConflictScan handles the FAQ conflict scan task
+func (s *knowledgeService) ProcessFAQConflictScan(ctx context.Context, t *asynq.Task) error {
+	var payload types.FAQConflictScanPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		logger.Errorf(ctx, "Failed to unmarshal FAQ conflict scan payload: %v", err)
+		return nil // Don't retry on unmarshal error
+	}
+
+	ctx = logger.WithRequestID(ctx, uuid.New().String())
+	ctx = logger.WithField(ctx, "faq_conflict_scan", payload.TaskID)
+	ctx = context.WithValue(ctx, types.TenantIDContextKey, payload.TenantID)
+	tenantInfo, err := s.tenantRepo.GetTenantByID(ctx, payload.TenantID)
+	if err != nil {
+		logger.Errorf(ctx, "Failed to get tenant info: %v", err)
+		return fmt.Errorf("failed to get tenant info: %w", err)
+	}
+	ctx = context.WithValue(ctx, types.TenantInfoContextKey, tenantInfo)
+
+	report := &types.FAQConflictReport{
+		TaskID:          payload.TaskID,
+		KnowledgeBaseID: payload.KnowledgeBaseID,
+		Status:          types.FAQConflictScanStatusProcessing,
+		Threshold:       payload.Threshold,
+		Groups:          make([]types.FAQConflictGroup, 0),
+		StartedAt:       time.Now(),
+	}
+	if existing, err := s.loadFAQConflictReport(ctx, payload.KnowledgeBaseID); err == nil && existing != nil &&
+		existing.TaskID == payload.TaskID {
+		report.StartedAt = existing.StartedAt
+	}
+
+	if err := s.scanFAQConflicts(ctx, &payload, report); err != nil {
+		logger.Errorf(ctx, "FAQ conflict scan failed: %v", err)
+		report.Status = types.FAQConflictScanStatusFailed
+		report.Error = err.Error()
+	} else {
+		report.Status = types.FAQConflictScanStatusCompleted
+	}
+	now := time.Now()
+	report.FinishedAt = &now
+	if err := s.saveFAQConflictReport(ctx, report); err != nil {
+		logger.Warnf(ctx, "Failed to save FAQ conflict report: %v", err)
+	}
+	logger.Infof(ctx, "FAQ conflict scan %s: scanned %d/%d entries, %d groups",
+		report.Status, report.ScannedEntries, report.TotalEntries, len(report.Groups))
+	return nil
+}
+
+// scanFAQConflicts checks every entry that is not archived against the other entries,
+// reporting each pair of entries once
+func (s *knowledgeService) scanFAQConflicts(ctx context.Context,
+	payload *types.FAQConflictScanPayload, report *types.FAQConflictReport,
+) error {
+	kb, err := s.validateFAQKnowledgeBase(ctx, payload.KnowledgeBaseID)
+	if err != nil {
+		return err
+	}
+	chunks, err := s.chunkRepo.ListAllFAQChunksWithMetadataByKnowledgeBaseID(ctx, payload.TenantID, kb.ID)
+	if err != nil {
+		return err
+	}
+
+	type scanEntry struct {
+		id   int64
+		meta *types.FAQChunkMetadata
+	}
+	entries := make([]scanEntry, 0, len(chunks))
+	for _, chunk := range chunks {
+		meta, err := chunk.FAQMetadata()
+		if err != nil || meta == nil || meta.ReviewStatus == types.FAQReviewStatusArchived {
+			continue
+		}
+		entries = append(entries, scanEntry{id: chunk.SeqID, meta: meta})
+	}
+	report.TotalEntries = len(entries)
+
+	seenPairs := make(map[[2]int64]struct{})
+	checked := 0
+	for i, entry := range entries {
+		questions := faqEntryQuestions(entry.meta.StandardQuestion, entry.meta.SimilarQuestions)
+		if checked+len(questions) > types.MaxFAQConflictScanQuestions {
+			report.Truncated = true
+			break
+		}
+		checked += len(questions)
+
+		matches, err := s.findFAQConflicts(ctx, kb.ID, questions, entry.meta.Answers, entry.id,
+			payload.Threshold)
+		if err != nil {
+			return err
+		}
+		kept := make([]types.FAQConflictMatch, 0, len(matches))
+		for _, match := range matches {
+			pair := [2]int64{min(entry.id, match.EntryID), max(entry.id, match.EntryID)}
+			if _, ok := seenPairs[pair]; ok {
+				continue
+			}
+			seenPairs[pair] = struct{}{}
+			kept = append(kept, match)
+		}
+		if len(kept) > 0 {
+			report.Groups = append(report.Groups, types.FAQConflictGroup{
+				EntryID:          entry.id,
+				StandardQuestion: entry.meta.StandardQuestion,
+				Answers:          entry.meta.Answers,
+				Matches:          kept,
+				Suggestion:       suggestFAQMerge(questions, kept, entry.id),
+			})
+		}
+
+		report.ScannedEntries = i + 1
+		if report.ScannedEntries%faqConflictProgressInterval == 0 {
+			if err := s.saveFAQConflictReport(ctx, report); err != nil {
+				logger.Warnf(ctx, "Failed to save FAQ conflict scan progress: %v", err)
+			}
+		}
+	}
+	return nil
+}
+
+// GetFAQConflictReport returns the latest conflict report of a FAQ knowledge base
+func (s *knowledgeService) GetFAQConflictReport(ctx context.Context, kbID string) (*types.FAQConflictReport, error) {
+	kb, err := s.validateFAQKnowledgeBase(ctx, kbID)
+	if err != nil {
+		return nil, err
+	}
+	report, err := s.loadFAQConflictReport(ctx, kb.ID)
+	if err != nil {
+		return nil, err
+	}
+	if report == nil {
+		return nil, werrors.NewNotFoundError("该知识库暂无冲突报告，请先发起扫描")
+	}
+	return report, nil
+}
+
+// saveFAQConflictReport saves the conflict report of a knowledge base to Redis
+func (s *knowledgeService) saveFAQConflictReport(ctx context.Context, report *types.FAQConflictReport) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to marshal FAQ conflict report: %w", err)
+	}
+	return s.redisClient.Set(ctx, getFAQConflictReportKey(report.KnowledgeBaseID), data, faqConflictReportTTL).Err()
+}
+
+// loadFAQConflictReport loads the conflict report of a knowledge base from Redis, nil if none
+func (s *knowledgeService) loadFAQConflictReport(ctx context.Context, kbID string) (*types.FAQConflictReport, error) {
+	data, err := s.redisClient.Get(ctx, getFAQConflictReportKey(kbID)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get FAQ conflict report from Redis: %w", err)
+	}
+	var report types.FAQConflictReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal FAQ conflict report: %w", err)
+	}
+	return &report, nil
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+func TestSameFAQAnswers(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []string
+		want bool
+	}{
+		{"same order", []string{"a", "b"}, []string{"a", "b"}, true},
+		{"different order and case", []string{"Reset it ", "b"}, []string{"B", "reset it"}, true},
+		{"different answer", []string{"a"}, []string{"b"}, false},
+		{"extra answer", []string{"a"}, []string{"a", "b"}, false},
+		{"blank answers ignored", []string{"a", " "}, []string{"a"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sameFAQAnswers(tt.a, tt.b); got != tt.want {
+				t.Errorf("sameFAQAnswers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggestFAQMerge(t *testing.T) {
+	if suggestFAQMerge([]string{"q"}, nil, 0) != nil {
+		t.Errorf("suggestFAQMerge() without matches should return nil")
+	}
+
+	matches := []types.FAQConflictMatch{
+		{EntryID: 7, StandardQuestion: "How do I reset my password?", MatchedQuestion: "Forgot password"},
+		{EntryID: 9, StandardQuestion: "Change password"},
+	}
+	questions := faqEntryQuestions(" How do I reset my password? ",
+		[]string{"Forgot password", "Password reset", "", "Change password"})
+
+	suggestion := suggestFAQMerge(questions, matches, 3)
+	if suggestion.TargetEntryID != 7 || suggestion.RemoveEntryID != 3 {
+		t.Errorf("suggestion = %+v, want target 7 and remove 3", suggestion)
+	}
+	if got := strings.Join(suggestion.SimilarQuestions, ","); got != "Password reset,Change password" {
+		t.Errorf("suggested questions = %q, want the questions the target lacks", got)
+	}
+}
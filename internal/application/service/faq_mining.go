@@ -0,0 +1,699 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Tencent/WeKnora/internal/chunker"
+	"github.com/Tencent/WeKnora/internal/config"
+	werrors "github.com/Tencent/WeKnora/internal/errors"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/models/chat"
+	"github.com/Tencent/WeKnora/internal/models/embedding"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+	"github.com/hibiken/asynq"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxFAQMiningMessages bounds the number of questions read from the chat history in one run
+	maxFAQMiningMessages = 5000
+	// maxFAQMiningQuestionLength skips long messages, which are rarely reusable FAQ questions
+	maxFAQMiningQuestionLength = 200
+	// maxFAQCandidateSimilarQuestions bounds the similar questions of a candidate
+	maxFAQCandidateSimilarQuestions = 10
+	// maxFAQCandidateMessageIDs bounds the message IDs recorded on a candidate
+	maxFAQCandidateMessageIDs = 50
+	// maxFAQMiningSourceKnowledgeBases bounds the knowledge bases searched to draft an answer
+	maxFAQMiningSourceKnowledgeBases = 10
+	// faqMiningContextChunks is the number of chunks an answer is drafted from
+	faqMiningContextChunks = 5
+	// faqMiningEmbedBatchSize is the number of questions embedded per request
+	faqMiningEmbedBatchSize = 64
+	// faqMiningNoAnswer is the reply of the model when the chunks do not answer the question
+	faqMiningNoAnswer = "NO_ANSWER"
+	// faqMiningSweepUniqueTTL keeps a scheduled run of a knowledge base from being queued twice
+	faqMiningSweepUniqueTTL = 24 * time.Hour
+)
+
+const defaultFAQAnswerDraftPrompt = `你是一名知识库编辑，负责为用户常问的问题撰写FAQ答案。
+
+## 要求
+1. 只能依据【参考资料】作答，不要编造参考资料中没有的信息
+2. 答案简洁、准确，可以直接回复给用户
+3. 如果参考资料不足以回答问题，只输出 ` + faqMiningNoAnswer + `
+
+## 用户问题
+{{question}}
+
+## 参考资料
+{{contexts}}
+
+请直接输出答案：`
+
+// faqMiningService implements the FAQMiningService interface
+type faqMiningService struct {
+	config           *config.Config
+	candidateRepo    interfaces.FAQCandidateRepository
+	messageRepo      interfaces.MessageRepository
+	tenantRepo       interfaces.TenantRepository
+	kbRepo           interfaces.KnowledgeBaseRepository
+	kbService        interfaces.KnowledgeBaseService
+	knowledgeService interfaces.KnowledgeService
+	modelService     interfaces.ModelService
+	task             *asynq.Client
+}
+
+// NewFAQMiningService creates a service mining FAQ candidates from chat history
+func NewFAQMiningService(
+	config *config.Config,
+	candidateRepo interfaces.FAQCandidateRepository,
+	messageRepo interfaces.MessageRepository,
+	tenantRepo interfaces.TenantRepository,
+	kbRepo interfaces.KnowledgeBaseRepository,
+	kbService interfaces.KnowledgeBaseService,
+	knowledgeService interfaces.KnowledgeService,
+	modelService interfaces.ModelService,
+	task *asynq.Client,
+) interfaces.FAQMiningService {
+	return &faqMiningService{
+		config:           config,
+		candidateRepo:    candidateRepo,
+		messageRepo:      messageRepo,
+		tenantRepo:       tenantRepo,
+		kbRepo:           kbRepo,
+		kbService:        kbService,
+		knowledgeService: knowledgeService,
+		modelService:     modelService,
+		task:             task,
+	}
+}
+
+// StartFAQMining enqueues a mining run proposing candidates for a FAQ knowledge base
+func (s *faqMiningService) StartFAQMining(ctx context.Context,
+	kbID string, req *types.FAQMiningRequest,
+) (string, error) {
+	if err := req.Validate(); err != nil {
+		return "", werrors.NewValidationError(err.Error())
+	}
+	kb, err := s.faqKnowledgeBase(ctx, kbID)
+	if err != nil {
+		return "", err
+	}
+
+	return s.enqueueFAQMining(ctx, kb, req)
+}
+
+// enqueueFAQMining enqueues a mining run of a FAQ knowledge base
+func (s *faqMiningService) enqueueFAQMining(ctx context.Context,
+	kb *types.KnowledgeBase, req *types.FAQMiningRequest, opts ...asynq.Option,
+) (string, error) {
+	payload := types.FAQMiningPayload{
+		TenantID:        kb.TenantID,
+		KnowledgeBaseID: kb.ID,
+		Request:         *req,
+	}
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	opts = append([]asynq.Option{asynq.Queue("low")}, opts...)
+	info, err := s.task.Enqueue(asynq.NewTask(types.TypeFAQMining, payloadBytes, opts...))
+	if err != nil {
+		logger.Errorf(ctx, "Failed to enqueue FAQ mining task: %v", err)
+		return "", err
+	}
+	logger.Infof(ctx, "Enqueued FAQ mining task: id=%s knowledge_base_id=%s", info.ID, kb.ID)
+	return info.ID, nil
+}
+
+// ProcessFAQMiningSweep handles the scheduled mining sweep, enqueueing a mining run with the
+// auto mining settings of each FAQ knowledge base that opted in. A run still queued from an
+// earlier sweep is not enqueued again.
+func (s *faqMiningService) ProcessFAQMiningSweep(ctx context.Context, t *asynq.Task) error {
+	kbs, err := s.kbRepo.ListKnowledgeBases(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list knowledge bases: %w", err)
+	}
+	enqueued := 0
+	for _, kb := range kbs {
+		if kb.Type != types.KnowledgeBaseTypeFAQ || kb.FAQConfig == nil || kb.FAQConfig.AutoMining == nil {
+			continue
+		}
+		if _, err := s.enqueueFAQMining(ctx, kb, kb.FAQConfig.AutoMining,
+			asynq.Unique(faqMiningSweepUniqueTTL)); err == nil {
+			enqueued++
+		}
+	}
+	logger.Infof(ctx, "FAQ mining sweep: enqueued %d knowledge bases", enqueued)
+	return nil
+}
+
+// ProcessFAQMining handles the FAQ mining task. Questions that got the fallback answer or were
+// answered from poorly matching references are clustered by embedding similarity, and each
+// cluster asked often enough becomes a pending candidate with an answer drafted from the
+// chunks of the source knowledge bases.
+func (s *faqMiningService) ProcessFAQMining(ctx context.Context, t *asynq.Task) error {
+	var payload types.FAQMiningPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		logger.Errorf(ctx, "Failed to unmarshal FAQ mining payload: %v", err)
+		return nil // Don't retry on unmarshal error
+	}
+	req := payload.Request.WithDefaults()
+
+	ctx = context.WithValue(ctx, types.TenantIDContextKey, payload.TenantID)
+	tenantInfo, err := s.tenantRepo.GetTenantByID(ctx, payload.TenantID)
+	if err != nil {
+		logger.Errorf(ctx, "Failed to get tenant info: %v", err)
+		return fmt.Errorf("failed to get tenant info: %w", err)
+	}
+	ctx = context.WithValue(ctx, types.TenantInfoContextKey, tenantInfo)
+
+	kb, err := s.kbService.GetKnowledgeBaseByID(ctx, payload.KnowledgeBaseID)
+	if err != nil {
+		logger.Errorf(ctx, "Failed to get knowledge base: %v", err)
+		return nil
+	}
+
+	since := time.Now().AddDate(0, 0, -req.Days)
+	messages, err := s.messageRepo.ListMessagesForFAQMining(ctx, payload.TenantID, since, maxFAQMiningMessages)
+	if err != nil {
+		logger.Errorf(ctx, "Failed to list messages for FAQ mining: %v", err)
+		return err
+	}
+	questions := selectMiningQuestions(messages, req.ScoreThreshold)
+	logger.Infof(ctx, "FAQ mining for knowledge base %s: %d messages, %d unanswered questions",
+		kb.ID, len(messages), len(questions))
+	if len(questions) == 0 {
+		return nil
+	}
+
+	// Questions of earlier candidates, accepted or rejected ones included, are not proposed again
+	known, err := s.candidateRepo.ListFAQCandidateQuestions(ctx, payload.TenantID, kb.ID)
+	if err != nil {
+		logger.Errorf(ctx, "Failed to list FAQ candidate questions: %v", err)
+		return err
+	}
+	knownSet := make(map[string]bool, len(known))
+	for _, q := range known {
+		knownSet[normalizeMiningQuestion(q)] = true
+	}
+
+	vectors := s.embedMiningQuestions(ctx, kb.EmbeddingModelID, questions)
+	clusters := clusterMiningQuestions(questions, vectors, req.SimilarityThreshold)
+
+	sources := s.miningSourceKnowledgeBases(ctx, req.SourceKnowledgeBaseIDs)
+	chatModel := s.miningChatModel(ctx, kb, sources)
+
+	candidates := make([]*types.FAQCandidate, 0, req.MaxCandidates)
+	for _, cluster := range clusters {
+		if len(candidates) >= req.MaxCandidates {
+			break
+		}
+		candidate := buildFAQCandidate(cluster)
+		if candidate.QuestionCount < req.MinQuestionCount || clusterIsKnown(cluster, knownSet) {
+			continue
+		}
+		if s.coveredByFAQ(ctx, kb.ID, cluster) {
+			continue
+		}
+		candidate.TenantID = payload.TenantID
+		candidate.KnowledgeBaseID = kb.ID
+		if chatModel != nil {
+			candidate.Answer, candidate.SourceChunkIDs = s.draftFAQAnswer(ctx, chatModel, sources,
+				candidate.StandardQuestion)
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	if err := s.candidateRepo.CreateFAQCandidates(ctx, candidates); err != nil {
+		logger.Errorf(ctx, "Failed to save FAQ candidates: %v", err)
+		return err
+	}
+	logger.Infof(ctx, "FAQ mining for knowledge base %s completed, clusters: %d, candidates: %d",
+		kb.ID, len(clusters), len(candidates))
+	return nil
+}
+
+// ListFAQCandidates lists the candidates of a FAQ knowledge base
+func (s *faqMiningService) ListFAQCandidates(ctx context.Context,
+	kbID string, status types.FAQCandidateStatus, page *types.Pagination,
+) (*types.PageResult, error) {
+	kb, err := s.faqKnowledgeBase(ctx, kbID)
+	if err != nil {
+		return nil, err
+	}
+	candidates, total, err := s.candidateRepo.ListFAQCandidates(ctx, kb.TenantID, kb.ID, status, page)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewPageResult(total, page, candidates), nil
+}
+
+// AcceptFAQCandidate creates a FAQ entry from a pending candidate through the regular FAQ
+// entry creation, so duplicate checks and indexing apply as for entries added by hand
+func (s *faqMiningService) AcceptFAQCandidate(ctx context.Context,
+	kbID string, candidateID string, req *types.FAQCandidateAcceptRequest,
+) (*types.FAQEntry, error) {
+	candidate, err := s.pendingFAQCandidate(ctx, kbID, candidateID)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := &types.FAQEntryPayload{
+		StandardQuestion: candidate.StandardQuestion,
+		SimilarQuestions: candidate.SimilarQuestions,
+		TagID:            req.TagID,
+		IsEnabled:        req.IsEnabled,
+	}
+	if strings.TrimSpace(req.StandardQuestion) != "" {
+		payload.StandardQuestion = req.StandardQuestion
+	}
+	if req.SimilarQuestions != nil {
+		payload.SimilarQuestions = req.SimilarQuestions
+	}
+	if len(req.Answers) > 0 {
+		payload.Answers = req.Answers
+	} else if candidate.Answer != "" {
+		payload.Answers = []string{candidate.Answer}
+	}
+	if len(payload.Answers) == 0 {
+		return nil, werrors.NewBadRequestError("候选问题没有草拟答案，请填写答案")
+	}
+
+	entry, err := s.knowledgeService.CreateFAQEntry(ctx, candidate.KnowledgeBaseID, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	candidate.Status = types.FAQCandidateStatusAccepted
+	candidate.FAQEntryID = entry.ID
+	candidate.ReviewedAt = &now
+	if err := s.candidateRepo.UpdateFAQCandidateReview(ctx, candidate); err != nil {
+		logger.Errorf(ctx, "Failed to mark FAQ candidate %s accepted: %v", candidate.ID, err)
+		return nil, err
+	}
+	logger.Infof(ctx, "Accepted FAQ candidate %s as entry %d", candidate.ID, entry.ID)
+	return entry, nil
+}
+
+// RejectFAQCandidate dismisses a pending candidate, its questions are not proposed again
+func (s *faqMiningService) RejectFAQCandidate(ctx context.Context, kbID string, candidateID string) error {
+	candidate, err := s.pendingFAQCandidate(ctx, kbID, candidateID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	candidate.Status = types.FAQCandidateStatusRejected
+	candidate.ReviewedAt = &now
+	return s.candidateRepo.UpdateFAQCandidateReview(ctx, candidate)
+}
+
+// faqKnowledgeBase gets a FAQ knowledge base of the current tenant
+func (s *faqMiningService) faqKnowledgeBase(ctx context.Context, kbID string) (*types.KnowledgeBase, error) {
+	kb, err := s.kbService.GetKnowledgeBaseByID(ctx, kbID)
+	if err != nil {
+		return nil, err
+	}
+	if kb.TenantID != ctx.Value(types.TenantIDContextKey).(uint64) {
+		return nil, werrors.NewForbiddenError("无权访问该知识库")
+	}
+	if kb.Type != types.KnowledgeBaseTypeFAQ {
+		return nil, werrors.NewBadRequestError("仅支持FAQ知识库")
+	}
+	return kb, nil
+}
+
+// pendingFAQCandidate gets a candidate of a FAQ knowledge base that was not reviewed yet
+func (s *faqMiningService) pendingFAQCandidate(ctx context.Context,
+	kbID string, candidateID string,
+) (*types.FAQCandidate, error) {
+	kb, err := s.faqKnowledgeBase(ctx, kbID)
+	if err != nil {
+		return nil, err
+	}
+	candidate, err := s.candidateRepo.GetFAQCandidate(ctx, kb.TenantID, kb.ID, candidateID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, werrors.NewNotFoundError("候选问题不存在")
+		}
+		return nil, err
+	}
+	if candidate.Status != types.FAQCandidateStatusPending {
+		return nil, werrors.NewBadRequestError("候选问题已审核")
+	}
+	return candidate, nil
+}
+
+// embedMiningQuestions embeds the questions with the embedding model of the FAQ knowledge
+// base. Nil is returned when embedding fails, questions are then only grouped when equal.
+func (s *faqMiningService) embedMiningQuestions(ctx context.Context,
+	modelID string, questions []*miningQuestion,
+) [][]float32 {
+	embedder, err := s.modelService.GetEmbeddingModel(ctx, modelID)
+	if err != nil {
+		logger.Warnf(ctx, "Failed to get embedding model for FAQ mining, clustering equal questions only: %v", err)
+		return nil
+	}
+	vectors, err := embedMiningTexts(ctx, embedder, questions)
+	if err != nil {
+		logger.Warnf(ctx, "Failed to embed questions for FAQ mining, clustering equal questions only: %v", err)
+		return nil
+	}
+	return vectors
+}
+
+// embedMiningTexts embeds the questions in batches
+func embedMiningTexts(ctx context.Context, embedder embedding.Embedder, questions []*miningQuestion) ([][]float32, error) {
+	vectors := make([][]float32, 0, len(questions))
+	for start := 0; start < len(questions); start += faqMiningEmbedBatchSize {
+		end := min(start+faqMiningEmbedBatchSize, len(questions))
+		texts := make([]string, 0, end-start)
+		for _, q := range questions[start:end] {
+			texts = append(texts, q.Text)
+		}
+		batch, err := embedder.BatchEmbed(ctx, texts)
+		if err != nil {
+			return nil, err
+		}
+		if len(batch) != len(texts) {
+			return nil, fmt.Errorf("embedding returned %d vectors for %d texts", len(batch), len(texts))
+		}
+		vectors = append(vectors, batch...)
+	}
+	return vectors, nil
+}
+
+// miningSourceKnowledgeBases returns the document knowledge bases answers are drafted from
+func (s *faqMiningService) miningSourceKnowledgeBases(ctx context.Context, ids []string) []*types.KnowledgeBase {
+	var kbs []*types.KnowledgeBase
+	if len(ids) > 0 {
+		for _, id := range ids {
+			kb, err := s.kbService.GetKnowledgeBaseByID(ctx, id)
+			if err != nil {
+				logger.Warnf(ctx, "Skipping FAQ mining source knowledge base %s: %v", id, err)
+				continue
+			}
+			kbs = append(kbs, kb)
+		}
+	} else {
+		all, err := s.kbService.ListKnowledgeBases(ctx)
+		if err != nil {
+			logger.Warnf(ctx, "Failed to list knowledge bases for FAQ mining: %v", err)
+			return nil
+		}
+		kbs = all
+	}
+
+	tenantID := ctx.Value(types.TenantIDContextKey).(uint64)
+	sources := make([]*types.KnowledgeBase, 0, len(kbs))
+	for _, kb := range kbs {
+		if kb.TenantID != tenantID || kb.IsTemporary || kb.Type == types.KnowledgeBaseTypeFAQ {
+			continue
+		}
+		sources = append(sources, kb)
+		if len(sources) >= maxFAQMiningSourceKnowledgeBases {
+			break
+		}
+	}
+	return sources
+}
+
+// miningChatModel returns the model drafting answers: the summary model of the FAQ knowledge
+// base, or of the first source knowledge base that has one
+func (s *faqMiningService) miningChatModel(ctx context.Context,
+	kb *types.KnowledgeBase, sources []*types.KnowledgeBase,
+) chat.Chat {
+	modelID := kb.SummaryModelID
+	for _, source := range sources {
+		if modelID != "" {
+			break
+		}
+		modelID = source.SummaryModelID
+	}
+	if modelID == "" || len(sources) == 0 {
+		logger.Warnf(ctx, "No chat model or source knowledge base for FAQ mining, answers are not drafted")
+		return nil
+	}
+	chatModel, err := s.modelService.GetChatModel(ctx, modelID)
+	if err != nil {
+		logger.Warnf(ctx, "Failed to get chat model for FAQ mining, answers are not drafted: %v", err)
+		return nil
+	}
+	return chatModel
+}
+
+// coveredByFAQ reports whether a question of the cluster already is a question of a FAQ entry
+func (s *faqMiningService) coveredByFAQ(ctx context.Context, kbID string, cluster []*miningQuestion) bool {
+	entries, err := s.knowledgeService.SearchFAQEntries(ctx, kbID, &types.FAQSearchRequest{
+		QueryText:       cluster[0].Text,
+		VectorThreshold: s.config.Conversation.VectorThreshold,
+		MatchCount:      5,
+	})
+	if err != nil {
+		logger.Warnf(ctx, "Failed to search FAQ entries for mined question: %v", err)
+		return false
+	}
+	members := make(map[string]bool, len(cluster))
+	for _, q := range cluster {
+		members[q.Key] = true
+	}
+	for _, entry := range entries {
+		if members[normalizeMiningQuestion(entry.StandardQuestion)] {
+			return true
+		}
+		for _, similar := range entry.SimilarQuestions {
+			if members[normalizeMiningQuestion(similar)] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// draftFAQAnswer drafts an answer from the best matching chunks of the source knowledge bases.
+// No answer is returned when no chunk matches or the model finds the chunks insufficient.
+func (s *faqMiningService) draftFAQAnswer(ctx context.Context,
+	chatModel chat.Chat, sources []*types.KnowledgeBase, question string,
+) (string, types.StringArray) {
+	var results []*types.SearchResult
+	for _, kb := range sources {
+		kbResults, err := s.kbService.HybridSearch(ctx, kb.ID, types.SearchParams{
+			QueryText:        question,
+			VectorThreshold:  s.config.Conversation.VectorThreshold,
+			KeywordThreshold: s.config.Conversation.KeywordThreshold,
+			MatchCount:       faqMiningContextChunks,
+		})
+		if err != nil {
+			logger.Warnf(ctx, "Failed to search knowledge base %s for FAQ answer: %v", kb.ID, err)
+			continue
+		}
+		results = append(results, kbResults...)
+	}
+	if len(results) == 0 {
+		return "", nil
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	if len(results) > faqMiningContextChunks {
+		results = results[:faqMiningContextChunks]
+	}
+
+	var contexts strings.Builder
+	chunkIDs := make(types.StringArray, 0, len(results))
+	for i, result := range results {
+		contexts.WriteString(fmt.Sprintf("[%d] %s\n%s\n\n", i+1, result.KnowledgeTitle, result.Content))
+		chunkIDs = append(chunkIDs, result.ID)
+	}
+	prompt := strings.ReplaceAll(defaultFAQAnswerDraftPrompt, "{{question}}", question)
+	prompt = strings.ReplaceAll(prompt, "{{contexts}}", contexts.String())
+
+	thinking := false
+	response, err := chatModel.Chat(ctx, []chat.Message{
+		{Role: "user", Content: prompt},
+	}, &chat.ChatOptions{
+		Temperature: 0.3,
+		MaxTokens:   1024,
+		Thinking:    &thinking,
+	})
+	if err != nil {
+		logger.Warnf(ctx, "Failed to draft FAQ answer: %v", err)
+		return "", nil
+	}
+	answer := strings.TrimSpace(response.Content)
+	if answer == "" || strings.Contains(answer, faqMiningNoAnswer) {
+		return "", nil
+	}
+	return answer, chunkIDs
+}
+
+// miningQuestion is a distinct user question that went unanswered, with how often it was asked
+type miningQuestion struct {
+	// Text of the most recent asking
+	Text string
+	// Key is the normalized text, equal questions share it
+	Key           string
+	MessageIDs    []string
+	Count         int
+	FallbackCount int
+	// MaxScore is the best reference score of the answers that were not fallbacks
+	MaxScore    float64
+	LastAskedAt time.Time
+}
+
+// selectMiningQuestions keeps the questions that got the fallback answer or whose best
+// reference scored below the threshold, grouping equal questions. Answers without references
+// that were not fallbacks, such as pure chat or agent answers, are skipped.
+func selectMiningQuestions(messages []*types.FAQMiningMessage, scoreThreshold float64) []*miningQuestion {
+	byKey := make(map[string]*miningQuestion)
+	var questions []*miningQuestion
+	for _, message := range messages {
+		text := strings.TrimSpace(message.Question)
+		count := utf8.RuneCountInString(text)
+		if count < 2 || count > maxFAQMiningQuestionLength {
+			continue
+		}
+		score, hasReferences := miningAnswerScore(message.KnowledgeReferences)
+		if !message.IsFallback && (!hasReferences || score >= scoreThreshold) {
+			continue
+		}
+
+		key := normalizeMiningQuestion(text)
+		q, ok := byKey[key]
+		if !ok {
+			q = &miningQuestion{Text: text, Key: key}
+			byKey[key] = q
+			questions = append(questions, q)
+		}
+		q.Count++
+		if len(q.MessageIDs) < maxFAQCandidateMessageIDs {
+			q.MessageIDs = append(q.MessageIDs, message.MessageID)
+		}
+		if message.IsFallback {
+			q.FallbackCount++
+		} else if score > q.MaxScore {
+			q.MaxScore = score
+		}
+		if message.CreatedAt.After(q.LastAskedAt) {
+			q.Text, q.LastAskedAt = text, message.CreatedAt
+		}
+	}
+	return questions
+}
+
+// miningAnswerScore returns the best score of the knowledge references of an answer,
+// web search results excluded, and whether there was any such reference
+func miningAnswerScore(refs types.References) (float64, bool) {
+	best, found := 0.0, false
+	for _, ref := range refs {
+		if ref == nil || ref.MatchType == types.MatchTypeWebSearch {
+			continue
+		}
+		if !found || ref.Score > best {
+			best, found = ref.Score, true
+		}
+	}
+	return best, found
+}
+
+// clusterMiningQuestions groups questions whose embeddings are at least threshold similar.
+// Questions are taken most asked first and join the first cluster whose leading question is
+// similar enough, so each cluster is led by its most asked question. Clusters are returned
+// most asked first. Without vectors every question is its own cluster.
+func clusterMiningQuestions(questions []*miningQuestion, vectors [][]float32, threshold float64) [][]*miningQuestion {
+	order := make([]int, len(questions))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		qa, qb := questions[order[a]], questions[order[b]]
+		if qa.Count != qb.Count {
+			return qa.Count > qb.Count
+		}
+		return qa.LastAskedAt.After(qb.LastAskedAt)
+	})
+
+	var clusters [][]*miningQuestion
+	var leaders []int
+	for _, i := range order {
+		joined := false
+		if len(vectors) == len(questions) {
+			for c, leader := range leaders {
+				if chunker.CosineSimilarity(vectors[i], vectors[leader]) >= threshold {
+					clusters[c] = append(clusters[c], questions[i])
+					joined = true
+					break
+				}
+			}
+		}
+		if !joined {
+			clusters = append(clusters, []*miningQuestion{questions[i]})
+			leaders = append(leaders, i)
+		}
+	}
+
+	sort.SliceStable(clusters, func(a, b int) bool {
+		return miningClusterCount(clusters[a]) > miningClusterCount(clusters[b])
+	})
+	return clusters
+}
+
+// miningClusterCount returns how often the questions of a cluster were asked
+func miningClusterCount(cluster []*miningQuestion) int {
+	count := 0
+	for _, q := range cluster {
+		count += q.Count
+	}
+	return count
+}
+
+// buildFAQCandidate builds a pending candidate from a cluster, the leading question is the standard question
+func buildFAQCandidate(cluster []*miningQuestion) *types.FAQCandidate {
+	candidate := &types.FAQCandidate{
+		StandardQuestion: cluster[0].Text,
+		SimilarQuestions: make(types.StringArray, 0),
+		MessageIDs:       make(types.StringArray, 0),
+		Status:           types.FAQCandidateStatusPending,
+	}
+	for i, q := range cluster {
+		if i > 0 && len(candidate.SimilarQuestions) < maxFAQCandidateSimilarQuestions {
+			candidate.SimilarQuestions = append(candidate.SimilarQuestions, q.Text)
+		}
+		for _, id := range q.MessageIDs {
+			if len(candidate.MessageIDs) < maxFAQCandidateMessageIDs {
+				candidate.MessageIDs = append(candidate.MessageIDs, id)
+			}
+		}
+		candidate.QuestionCount += q.Count
+		candidate.FallbackCount += q.FallbackCount
+		candidate.MaxScore = math.Max(candidate.MaxScore, q.MaxScore)
+		if q.LastAskedAt.After(candidate.LastAskedAt) {
+			candidate.LastAskedAt = q.LastAskedAt
+		}
+	}
+	return candidate
+}
+
+// clusterIsKnown reports whether a question of the cluster belongs to an earlier candidate
+func clusterIsKnown(cluster []*miningQuestion, known map[string]bool) bool {
+	for _, q := range cluster {
+		if known[q.Key] {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeMiningQuestion normalizes a question for equality: case, spacing and trailing punctuation are ignored
+func normalizeMiningQuestion(question string) string {
+	normalized := strings.ToLower(strings.Join(strings.Fields(question), " "))
+	return strings.TrimRight(normalized, "?？!！.。~～ ")
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+func TestSelectMiningQuestions(t *testing.T) {
+	now := time.Now()
+	refs := func(scores ...float64) types.References {
+		out := make(types.References, 0, len(scores))
+		for _, score := range scores {
+			out = append(out, &types.SearchResult{Score: score, MatchType: types.MatchTypeEmbedding})
+		}
+		return out
+	}
+	messages := []*types.FAQMiningMessage{
+		{MessageID: "m1", Question: "How do I reset my password?", IsFallback: true, CreatedAt: now},
+		{MessageID: "m2", Question: "how do i reset my password", IsFallback: true, CreatedAt: now.Add(-time.Hour)},
+		{MessageID: "m3", Question: "Where is the invoice?", KnowledgeReferences: refs(0.2, 0.3), CreatedAt: now},
+		{MessageID: "m4", Question: "What is WeKnora?", KnowledgeReferences: refs(0.9), CreatedAt: now},
+		{MessageID: "m5", Question: "Tell me a joke", CreatedAt: now},
+		{
+			MessageID: "m6", Question: "Latest news?", CreatedAt: now,
+			KnowledgeReferences: types.References{{Score: 0.1, MatchType: types.MatchTypeWebSearch}},
+		},
+	}
+
+	questions := selectMiningQuestions(messages, 0.5)
+	if len(questions) != 2 {
+		t.Fatalf("selectMiningQuestions() returned %d questions, want 2", len(questions))
+	}
+	reset := questions[0]
+	if reset.Count != 2 || reset.FallbackCount != 2 || reset.Text != "How do I reset my password?" {
+		t.Errorf("reset question = %+v, want 2 fallbacks with the latest text", reset)
+	}
+	if invoice := questions[1]; invoice.Count != 1 || invoice.FallbackCount != 0 || invoice.MaxScore != 0.3 {
+		t.Errorf("invoice question = %+v, want one low-score asking with max score 0.3", invoice)
+	}
+}
+
+func TestClusterMiningQuestions(t *testing.T) {
+	questions := []*miningQuestion{
+		{Text: "reset password", Key: "reset password", Count: 1, MessageIDs: []string{"a"}},
+		{Text: "forgot password", Key: "forgot password", Count: 3, MessageIDs: []string{"b"}, FallbackCount: 3},
+		{Text: "invoice", Key: "invoice", Count: 2, MessageIDs: []string{"c"}, MaxScore: 0.4},
+	}
+	vectors := [][]float32{{1, 0.1}, {1, 0}, {0, 1}}
+
+	clusters := clusterMiningQuestions(questions, vectors, 0.9)
+	if len(clusters) != 2 {
+		t.Fatalf("clusterMiningQuestions() returned %d clusters, want 2", len(clusters))
+	}
+	candidate := buildFAQCandidate(clusters[0])
+	if candidate.StandardQuestion != "forgot password" ||
+		strings.Join(candidate.SimilarQuestions, ",") != "reset password" {
+		t.Errorf("candidate questions = %q %v, want the most asked question first", candidate.StandardQuestion,
+			candidate.SimilarQuestions)
+	}
+	if candidate.QuestionCount != 4 || candidate.FallbackCount != 3 || len(candidate.MessageIDs) != 2 {
+		t.Errorf("candidate = %+v, want 4 askings, 3 fallbacks and 2 messages", candidate)
+	}
+
+	if unclustered := clusterMiningQuestions(questions, nil, 0.9); len(unclustered) != 3 {
+		t.Errorf("clusterMiningQuestions() without vectors returned %d clusters, want 3", len(unclustered))
+	}
+}
@@ -0,0 +1,320 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	werrors "github.com/Tencent/WeKnora/internal/errors"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+// isValidNewFAQReviewStatus reports whether a new FAQ entry may be created with the status
+func isValidNewFAQReviewStatus(status types.FAQReviewStatus) bool {
+	switch status {
+	case "", types.FAQReviewStatusDraft, types.FAQReviewStatusPublished:
+		return true
+	}
+	return false
+}
+
+// newFAQReviewStatus returns the review status of a new FAQ entry, entries of knowledge
+// bases requiring review always start as drafts
+func newFAQReviewStatus(kb *types.KnowledgeBase, requested types.FAQReviewStatus) types.FAQReviewStatus {
+	if kb.FAQConfig != nil && kb.FAQConfig.RequireReview {
+		return types.FAQReviewStatusDraft
+	}
+	return requested.OrPublished()
+}
+
+// faqChunkStatus returns the chunk status of an FAQ entry, only published entries are in the index
+func faqChunkStatus(chunk *types.Chunk) int {
+	if meta, err := chunk.FAQMetadata(); err == nil && meta != nil && !meta.IsPublished() {
+		return int(types.ChunkStatusUnpublished)
+	}
+	return int(types.ChunkStatusIndexed)
+}
+
+// nextFAQReviewStatus returns the status a review action moves an entry to
+func nextFAQReviewStatus(current types.FAQReviewStatus, action types.FAQReviewAction,
+	requireReview bool,
+) (types.FAQReviewStatus, error) {
+	current = current.OrPublished()
+	var from []types.FAQReviewStatus
+	var next types.FAQReviewStatus
+	switch action {
+	case types.FAQReviewActionSubmit:
+		from, next = []types.FAQReviewStatus{types.FAQReviewStatusDraft}, types.FAQReviewStatusPendingReview
+	case types.FAQReviewActionApprove:
+		from, next = []types.FAQReviewStatus{types.FAQReviewStatusPendingReview}, types.FAQReviewStatusPublished
+	case types.FAQReviewActionReject:
+		from, next = []types.FAQReviewStatus{types.FAQReviewStatusPendingReview}, types.FAQReviewStatusDraft
+	case types.FAQReviewActionPublish:
+		if requireReview {
+			return "", werrors.NewBadRequestError("该知识库要求审核，请提交审核人审批后发布")
+		}
+		from, next = []types.FAQReviewStatus{types.FAQReviewStatusDraft}, types.FAQReviewStatusPublished
+	case types.FAQReviewActionArchive:
+		from = []types.FAQReviewStatus{
+			types.FAQReviewStatusDraft, types.FAQReviewStatusPendingReview, types.FAQReviewStatusPublished,
+		}
+		next = types.FAQReviewStatusArchived
+	case types.FAQReviewActionRestore:
+		from, next = []types.FAQReviewStatus{types.FAQReviewStatusArchived}, types.FAQReviewStatusDraft
+	default:
+		return "", werrors.NewBadRequestError(
+			"action 必须是 submit、approve、reject、publish、archive 或 restore")
+	}
+	for _, status := range from {
+		if status == current {
+			return next, nil
+		}
+	}
+	return "", werrors.NewBadRequestError(fmt.Sprintf("条目当前状态为 %s，无法执行 %s", current, action))
+}
+
+// checkFAQReviewActor checks that a user may take a review action on an entry: the reviewer of a
+// submitted entry is someone else, only the reviewer approves or rejects, and never an entry they
+// last edited or submitted themselves
+func checkFAQReviewActor(action types.FAQReviewAction, meta *types.FAQChunkMetadata, userID string) error {
+	switch action {
+	case types.FAQReviewActionSubmit:
+		return checkFAQReviewer(meta.Reviewer, userID)
+	case types.FAQReviewActionApprove, types.FAQReviewActionReject:
+		if userID == "" || userID != meta.Reviewer {
+			return werrors.NewForbiddenError("仅指定的审核人可以审核该条目")
+		}
+		if action == types.FAQReviewActionApprove && (userID == meta.LastEditor || userID == meta.Submitter) {
+			return werrors.NewForbiddenError("不能审批自己编辑或提交的条目")
+		}
+	}
+	return nil
+}
+
+// checkFAQReviewer rejects assigning the caller as the reviewer of a FAQ entry, so that
+// nobody approves their own wording
+func checkFAQReviewer(reviewerID, callerID string) error {
+	if callerID != "" && reviewerID == callerID {
+		return werrors.NewBadRequestError("不能指定自己为审核人")
+	}
+	return nil
+}
+
+// contextUserID returns the ID of the user of the context, empty when there is none
+func contextUserID(ctx context.Context) string {
+	if user, ok := ctx.Value(types.UserContextKey).(*types.User); ok && user != nil {
+		return user.ID
+	}
+	return ""
+}
+
+// getFAQEntryChunk gets the chunk and metadata of a FAQ entry of the knowledge base
+func (s *knowledgeService) getFAQEntryChunk(ctx context.Context,
+	kb *types.KnowledgeBase, entrySeqID int64,
+) (*types.Chunk, *types.FAQChunkMetadata, error) {
+	if entrySeqID <= 0 {
+		return nil, nil, werrors.NewBadRequestError("条目ID不能为空")
+	}
+	tenantID := ctx.Value(types.TenantIDContextKey).(uint64)
+	chunk, err := s.chunkRepo.GetChunkBySeqID(ctx, tenantID, entrySeqID)
+	if err != nil || chunk.KnowledgeBaseID != kb.ID || chunk.ChunkType != types.ChunkTypeFAQ {
+		return nil, nil, werrors.NewNotFoundError("FAQ条目不存在")
+	}
+	meta, err := chunk.FAQMetadata()
+	if err != nil || meta == nil {
+		return nil, nil, werrors.NewBadRequestError("获取 FAQ 元数据失败")
+	}
+	return chunk, meta, nil
+}
+
+// ReviewFAQEntry moves a FAQ entry through the review workflow. Publishing indexes the
+// entry for retrieval, leaving the published status removes its vectors.
+func (s *knowledgeService) ReviewFAQEntry(ctx context.Context,
+	kbID string, entrySeqID int64, req *types.FAQReviewRequest,
+) (*types.FAQEntry, error) {
+	if req == nil {
+		return nil, werrors.NewBadRequestError("请求体不能为空")
+	}
+	kb, err := s.validateFAQKnowledgeBase(ctx, kbID)
+	if err != nil {
+		return nil, err
+	}
+	kb.EnsureDefaults()
+	tenantID := ctx.Value(types.TenantIDContextKey).(uint64)
+
+	chunk, meta, err := s.getFAQEntryChunk(ctx, kb, entrySeqID)
+	if err != nil {
+		return nil, err
+	}
+	next, err := nextFAQReviewStatus(meta.ReviewStatus, req.Action, kb.FAQConfig.RequireReview)
+	if err != nil {
+		return nil, err
+	}
+
+	userID := contextUserID(ctx)
+	if req.Action == types.FAQReviewActionSubmit {
+		if reviewerID := strings.TrimSpace(req.ReviewerID); reviewerID != "" {
+			meta.Reviewer = reviewerID
+		}
+		if meta.Reviewer == "" {
+			return nil, werrors.NewBadRequestError("提交审核前需要指定审核人")
+		}
+		meta.Submitter = userID
+	}
+	if err := checkFAQReviewActor(req.Action, meta, userID); err != nil {
+		return nil, err
+	}
+
+	wasPublished := meta.IsPublished()
+	meta.ReviewStatus = next
+	if err := chunk.SetFAQMetadata(meta); err != nil {
+		return nil, err
+	}
+	chunk.UpdatedAt = time.Now()
+
+	// 先同步检索索引再保存状态，索引失败时条目保持原状态
+	if wasPublished != meta.IsPublished() {
+		faqKnowledge, err := s.repo.GetKnowledgeByID(ctx, tenantID, chunk.KnowledgeID)
+		if err != nil {
+			return nil, err
+		}
+		if meta.IsPublished() {
+			embeddingModel, err := s.modelService.GetEmbeddingModel(ctx, kb.EmbeddingModelID)
+			if err != nil {
+				return nil, err
+			}
+			if err := s.indexFAQChunks(ctx, kb, faqKnowledge, []*types.Chunk{chunk}, embeddingModel,
+				true, true); err != nil {
+				return nil, fmt.Errorf("failed to index FAQ entry: %w", err)
+			}
+		} else if err := s.deleteFAQChunkVectors(ctx, kb, faqKnowledge, []*types.Chunk{chunk}); err != nil {
+			return nil, fmt.Errorf("failed to delete FAQ entry vectors: %w", err)
+		}
+	}
+	chunk.Status = faqChunkStatus(chunk)
+	if err := s.chunkService.UpdateChunk(ctx, chunk); err != nil {
+		return nil, err
+	}
+
+	// 审核操作连同意见一起记录，作为条目的审核记录
+	if err := s.faqCommentRepo.CreateFAQEntryComment(ctx,
+		newFAQEntryComment(ctx, chunk, req.Action, next, strings.TrimSpace(req.Comment))); err != nil {
+		logger.Warnf(ctx, "Failed to record FAQ review action: %v", err)
+	}
+	logger.Infof(ctx, "FAQ entry %d review action %s, status %s", entrySeqID, req.Action, next)
+
+	return s.faqEntryWithTag(ctx, chunk, kb)
+}
+
+// AssignFAQEntryReviewer assigns the user who approves the wording of a FAQ entry
+func (s *knowledgeService) AssignFAQEntryReviewer(ctx context.Context,
+	kbID string, entrySeqID int64, reviewerID string,
+) (*types.FAQEntry, error) {
+	kb, err := s.validateFAQKnowledgeBase(ctx, kbID)
+	if err != nil {
+		return nil, err
+	}
+	kb.EnsureDefaults()
+
+	chunk, meta, err := s.getFAQEntryChunk(ctx, kb, entrySeqID)
+	if err != nil {
+		return nil, err
+	}
+	meta.Reviewer = strings.TrimSpace(reviewerID)
+	if meta.Reviewer == "" && meta.ReviewStatus == types.FAQReviewStatusPendingReview {
+		return nil, werrors.NewBadRequestError("待审核的条目必须有审核人")
+	}
+	if meta.Reviewer != "" {
+		if err := checkFAQReviewer(meta.Reviewer, contextUserID(ctx)); err != nil {
+			return nil, err
+		}
+	}
+	if err := chunk.SetFAQMetadata(meta); err != nil {
+		return nil, err
+	}
+	chunk.UpdatedAt = time.Now()
+	if err := s.chunkService.UpdateChunk(ctx, chunk); err != nil {
+		return nil, err
+	}
+	return s.faqEntryWithTag(ctx, chunk, kb)
+}
+
+// AddFAQEntryComment adds a review comment to a FAQ entry
+func (s *knowledgeService) AddFAQEntryComment(ctx context.Context,
+	kbID string, entrySeqID int64, content string,
+) (*types.FAQEntryComment, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil, werrors.NewBadRequestError("评论内容不能为空")
+	}
+	kb, err := s.validateFAQKnowledgeBase(ctx, kbID)
+	if err != nil {
+		return nil, err
+	}
+	chunk, meta, err := s.getFAQEntryChunk(ctx, kb, entrySeqID)
+	if err != nil {
+		return nil, err
+	}
+	comment := newFAQEntryComment(ctx, chunk, "", meta.ReviewStatus.OrPublished(), content)
+	if err := s.faqCommentRepo.CreateFAQEntryComment(ctx, comment); err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// ListFAQEntryComments lists the review comments and actions of a FAQ entry, oldest first
+func (s *knowledgeService) ListFAQEntryComments(ctx context.Context,
+	kbID string, entrySeqID int64,
+) ([]*types.FAQEntryComment, error) {
+	kb, err := s.validateFAQKnowledgeBase(ctx, kbID)
+	if err != nil {
+		return nil, err
+	}
+	chunk, _, err := s.getFAQEntryChunk(ctx, kb, entrySeqID)
+	if err != nil {
+		return nil, err
+	}
+	return s.faqCommentRepo.ListFAQEntryComments(ctx, chunk.TenantID, chunk.ID)
+}
+
+// faqEntryWithTag converts a FAQ chunk to an entry with its tag
+func (s *knowledgeService) faqEntryWithTag(ctx context.Context,
+	chunk *types.Chunk, kb *types.KnowledgeBase,
+) (*types.FAQEntry, error) {
+	tagSeqIDMap := make(map[string]int64)
+	var tagName string
+	if chunk.TagID != "" {
+		if tag, err := s.tagRepo.GetByID(ctx, chunk.TenantID, chunk.TagID); err == nil && tag != nil {
+			tagSeqIDMap[tag.ID] = tag.SeqID
+			tagName = tag.Name
+		}
+	}
+	entry, err := s.chunkToFAQEntry(chunk, kb, tagSeqIDMap)
+	if err != nil {
+		return nil, err
+	}
+	entry.TagName = tagName
+	return entry, nil
+}
+
+// newFAQEntryComment builds a comment on a FAQ entry by the user of the context
+func newFAQEntryComment(ctx context.Context, chunk *types.Chunk,
+	action types.FAQReviewAction, status types.FAQReviewStatus, content string,
+) *types.FAQEntryComment {
+	comment := &types.FAQEntryComment{
+		TenantID:        chunk.TenantID,
+		KnowledgeBaseID: chunk.KnowledgeBaseID,
+		ChunkID:         chunk.ID,
+		EntryID:         chunk.SeqID,
+		Action:          action,
+		ReviewStatus:    status,
+		Content:         content,
+	}
+	if user, ok := ctx.Value(types.UserContextKey).(*types.User); ok && user != nil {
+		comment.UserID = user.ID
+		comment.Username = user.Username
+	}
+	return comment
+}
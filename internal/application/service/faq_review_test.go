@@ -0,0 +1,128 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+func TestNextFAQReviewStatus(t *testing.T) {
+	tests := []struct {
+		name          string
+		current       types.FAQReviewStatus
+		action        types.FAQReviewAction
+		requireReview bool
+		want          types.FAQReviewStatus
+		wantErr       bool
+	}{
+		{"submit draft", types.FAQReviewStatusDraft, types.FAQReviewActionSubmit, true, types.FAQReviewStatusPendingReview, false},
+		{"approve pending", types.FAQReviewStatusPendingReview, types.FAQReviewActionApprove, true, types.FAQReviewStatusPublished, false},
+		{"reject pending", types.FAQReviewStatusPendingReview, types.FAQReviewActionReject, true, types.FAQReviewStatusDraft, false},
+		{"approve draft", types.FAQReviewStatusDraft, types.FAQReviewActionApprove, false, "", true},
+		{"publish draft", types.FAQReviewStatusDraft, types.FAQReviewActionPublish, false, types.FAQReviewStatusPublished, false},
+		{"publish requires review", types.FAQReviewStatusDraft, types.FAQReviewActionPublish, true, "", true},
+		{"archive legacy entry", "", types.FAQReviewActionArchive, false, types.FAQReviewStatusArchived, false},
+		{"submit legacy entry", "", types.FAQReviewActionSubmit, false, "", true},
+		{"restore archived", types.FAQReviewStatusArchived, types.FAQReviewActionRestore, true, types.FAQReviewStatusDraft, false},
+		{"unknown action", types.FAQReviewStatusDraft, "delete", false, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := nextFAQReviewStatus(tt.current, tt.action, tt.requireReview)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("nextFAQReviewStatus() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("nextFAQReviewStatus() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckFAQReviewActor(t *testing.T) {
+	meta := &types.FAQChunkMetadata{Reviewer: "reviewer", Submitter: "author", LastEditor: "editor"}
+	tests := []struct {
+		name    string
+		action  types.FAQReviewAction
+		meta    *types.FAQChunkMetadata
+		userID  string
+		wantErr bool
+	}{
+		{"submit to another reviewer", types.FAQReviewActionSubmit, meta, "author", false},
+		{"submit to oneself", types.FAQReviewActionSubmit, meta, "reviewer", true},
+		{"approve by reviewer", types.FAQReviewActionApprove, meta, "reviewer", false},
+		{"approve by another user", types.FAQReviewActionApprove, meta, "author", true},
+		{"approve without user", types.FAQReviewActionApprove, meta, "", true},
+		{
+			"approve own edit", types.FAQReviewActionApprove,
+			&types.FAQChunkMetadata{Reviewer: "editor", Submitter: "author", LastEditor: "editor"}, "editor", true,
+		},
+		{
+			"approve own submission", types.FAQReviewActionApprove,
+			&types.FAQChunkMetadata{Reviewer: "author", Submitter: "author"}, "author", true,
+		},
+		{
+			"reject own edit", types.FAQReviewActionReject,
+			&types.FAQChunkMetadata{Reviewer: "editor", LastEditor: "editor"}, "editor", false,
+		},
+		{"archive", types.FAQReviewActionArchive, meta, "anyone", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkFAQReviewActor(tt.action, tt.meta, tt.userID); (err != nil) != tt.wantErr {
+				t.Errorf("checkFAQReviewActor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFilterPublishedFAQChunks(t *testing.T) {
+	chunk := func(id string, status types.FAQReviewStatus) *types.Chunk {
+		c := &types.Chunk{ID: id}
+		if err := c.SetFAQMetadata(&types.FAQChunkMetadata{
+			StandardQuestion: id, Answers: []string{"a"}, ReviewStatus: status,
+		}); err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	chunks := []*types.Chunk{
+		chunk("legacy", ""),
+		chunk("draft", types.FAQReviewStatusDraft),
+		chunk("published", types.FAQReviewStatusPublished),
+		chunk("pending", types.FAQReviewStatusPendingReview),
+		chunk("archived", types.FAQReviewStatusArchived),
+	}
+
+	published := filterPublishedFAQChunks(chunks)
+	if len(published) != 2 || published[0].ID != "legacy" || published[1].ID != "published" {
+		t.Errorf("filterPublishedFAQChunks() kept %d chunks, want legacy and published", len(published))
+	}
+}
+
+func TestFAQChunkStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		status types.FAQReviewStatus
+		want   types.ChunkStatus
+	}{
+		{"published", types.FAQReviewStatusPublished, types.ChunkStatusIndexed},
+		{"legacy entry", "", types.ChunkStatusIndexed},
+		{"draft", types.FAQReviewStatusDraft, types.ChunkStatusUnpublished},
+		{"pending review", types.FAQReviewStatusPendingReview, types.ChunkStatusUnpublished},
+		{"archived", types.FAQReviewStatusArchived, types.ChunkStatusUnpublished},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunk := &types.Chunk{ChunkType: types.ChunkTypeFAQ}
+			if err := chunk.SetFAQMetadata(&types.FAQChunkMetadata{
+				StandardQuestion: "q", Answers: []string{"a"}, ReviewStatus: tt.status,
+			}); err != nil {
+				t.Fatal(err)
+			}
+			if got := faqChunkStatus(chunk); got != int(tt.want) {
+				t.Errorf("faqChunkStatus() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/Tencent/WeKnora/docreader/client"
 	"github.com/Tencent/WeKnora/docreader/proto"
 	"github.com/Tencent/WeKnora/internal/application/service/retriever"
+	"github.com/Tencent/WeKnora/internal/chunker"
 	"github.com/Tencent/WeKnora/internal/config"
 	werrors "github.com/Tencent/WeKnora/internal/errors"
 	"github.com/Tencent/WeKnora/internal/logger"
@@ -65,6 +66,8 @@ type knowledgeService struct {
 	chunkRepo       interfaces.ChunkRepository
 	tagRepo         interfaces.KnowledgeTagRepository
 	tagService      interfaces.KnowledgeTagService
+	stageRepo       interfaces.KnowledgeStageRepository
+	faqCommentRepo  interfaces.FAQEntryCommentRepository
 	fileSvc         interfaces.FileService
 	modelService    interfaces.ModelService
 	task            *asynq.Client
@@ -89,6 +92,8 @@ func NewKnowledgeService(
 	chunkRepo interfaces.ChunkRepository,
 	tagRepo interfaces.KnowledgeTagRepository,
 	tagService interfaces.KnowledgeTagService,
+	stageRepo interfaces.KnowledgeStageRepository,
+	faqCommentRepo interfaces.FAQEntryCommentRepository,
 	fileSvc interfaces.FileService,
 	modelService interfaces.ModelService,
 	task *asynq.Client,
@@ -106,6 +111,8 @@ func NewKnowledgeService(
 		chunkRepo:       chunkRepo,
 		tagRepo:         tagRepo,
 		tagService:      tagService,
+		stageRepo:       stageRepo,
+		faqCommentRepo:  faqCommentRepo,
 		fileSvc:         fileSvc,
 		modelService:    modelService,
 		task:            task,
@@ -143,6 +150,7 @@ func (s *knowledgeService) isKnowledgeDeleting(ctx context.Context, tenantID uin
 // CreateKnowledgeFromFile creates a knowledge entry from an uploaded file
 func (s *knowledgeService) CreateKnowledgeFromFile(ctx context.Context,
 	kbID string, file *multipart.FileHeader, metadata map[string]string, enableMultimodel *bool, customFileName string, tagID string,
+	customMetadata map[string]any,
 ) (*types.Knowledge, error) {
 	logger.Info(ctx, "Start creating knowledge from file")
 
@@ -248,6 +256,11 @@ func (s *knowledgeService) CreateKnowledgeFromFile(ctx context.Context,
 		}
 		metadataJSON = types.JSON(metadataBytes)
 	}
+	customMetadataJSON, err := buildCustomMetadata(customMetadata)
+	if err != nil {
+		logger.Errorf(ctx, "Invalid custom metadata: %v", err)
+		return nil, err
+	}
 
 	// 验证文件名安全性
 	safeFilename, isValid := secutils.ValidateInput(fileName)
@@ -274,6 +287,7 @@ func (s *knowledgeService) CreateKnowledgeFromFile(ctx context.Context,
 		UpdatedAt:        time.Now(),
 		EmbeddingModelID: kb.EmbeddingModelID,
 		Metadata:         metadataJSON,
+		CustomMetadata:   customMetadataJSON,
 	}
 	// Save knowledge record to database
 	logger.Info(ctx, "Saving knowledge record to database")
@@ -361,11 +375,17 @@ func (s *knowledgeService) CreateKnowledgeFromFile(ctx context.Context,
 // CreateKnowledgeFromURL creates a knowledge entry from a URL source
 // tagID is optional - when provided, the knowledge will be assigned to the specified tag/category.
 func (s *knowledgeService) CreateKnowledgeFromURL(ctx context.Context,
-	kbID string, url string, enableMultimodel *bool, title string, tagID string,
+	kbID string, url string, enableMultimodel *bool, title string, tagID string, customMetadata map[string]any,
 ) (*types.Knowledge, error) {
 	logger.Info(ctx, "Start creating knowledge from URL")
 	logger.Infof(ctx, "Knowledge base ID: %s, URL: %s", kbID, url)
 
+	customMetadataJSON, err := buildCustomMetadata(customMetadata)
+	if err != nil {
+		logger.Errorf(ctx, "Invalid custom metadata: %v", err)
+		return nil, err
+	}
+
 	// Get knowledge base configuration
 	logger.Info(ctx, "Getting knowledge base configuration")
 	kb, err := s.kbService.GetKnowledgeBaseByID(ctx, kbID)
@@ -435,6 +455,7 @@ func (s *knowledgeService) CreateKnowledgeFromURL(ctx context.Context,
 		UpdatedAt:        time.Now(),
 		EmbeddingModelID: kb.EmbeddingModelID,
 		TagID:            tagID, // 设置分类ID，用于知识分类管理
+		CustomMetadata:   customMetadataJSON,
 	}
 
 	// Save knowledge record
@@ -536,6 +557,11 @@ func (s *knowledgeService) CreateKnowledgeFromManual(ctx context.Context,
 		return nil, werrors.NewValidationError("状态仅支持 draft 或 publish")
 	}
 
+	customMetadataJSON, err := buildCustomMetadata(payload.CustomMetadata)
+	if err != nil {
+		return nil, err
+	}
+
 	kb, err := s.kbService.GetKnowledgeBaseByID(ctx, kbID)
 	if err != nil {
 		logger.Errorf(ctx, "Failed to get knowledge base: %v", err)
@@ -551,6 +577,10 @@ func (s *knowledgeService) CreateKnowledgeFromManual(ctx context.Context,
 
 	fileName := ensureManualFileName(title)
 	meta := types.NewManualKnowledgeMetadata(cleanContent, status, 1)
+	source := types.KnowledgeTypeManual
+	if payload.Source != "" {
+		source = payload.Source
+	}
 
 	knowledge := &types.Knowledge{
 		TenantID:         tenantID,
@@ -558,7 +588,7 @@ func (s *knowledgeService) CreateKnowledgeFromManual(ctx context.Context,
 		Type:             types.KnowledgeTypeManual,
 		Title:            title,
 		Description:      "",
-		Source:           types.KnowledgeTypeManual,
+		Source:           source,
 		ParseStatus:      types.ManualKnowledgeStatusDraft,
 		EnableStatus:     "disabled",
 		CreatedAt:        now,
@@ -567,6 +597,7 @@ func (s *knowledgeService) CreateKnowledgeFromManual(ctx context.Context,
 		FileName:         fileName,
 		FileType:         types.KnowledgeTypeManual,
 		TagID:            payload.TagID, // 设置分类ID，用于知识分类管理
+		CustomMetadata:   customMetadataJSON,
 	}
 	if err := knowledge.SetManualMetadata(meta); err != nil {
 		logger.Errorf(ctx, "Failed to set manual metadata: %v", err)
@@ -811,6 +842,24 @@ func (s *knowledgeService) DeleteKnowledge(ctx context.Context, id string) error
 		return nil
 	})
 
+	// Delete the processing stages
+	wg.Go(func() error {
+		if err := s.stageRepo.DeleteStages(ctx, knowledge.TenantID, []string{knowledge.ID}); err != nil {
+			logger.GetLogger(ctx).WithField("error", err).Errorf("DeleteKnowledge delete processing stages failed")
+			return err
+		}
+		return nil
+	})
+
+	// Delete the manual chunk edits
+	wg.Go(func() error {
+		if err := s.chunkService.DeleteChunkEdits(ctx, []string{knowledge.ID}); err != nil {
+			logger.GetLogger(ctx).WithField("error", err).Errorf("DeleteKnowledge delete chunk edits failed")
+			return err
+		}
+		return nil
+	})
+
 	if err = wg.Wait(); err != nil {
 		return err
 	}
@@ -923,6 +972,24 @@ func (s *knowledgeService) DeleteKnowledgeList(ctx context.Context, ids []string
 		return nil
 	})
 
+	// Delete the processing stages
+	wg.Go(func() error {
+		if err := s.stageRepo.DeleteStages(ctx, tenantInfo.ID, ids); err != nil {
+			logger.GetLogger(ctx).WithField("error", err).Errorf("DeleteKnowledge delete processing stages failed")
+			return err
+		}
+		return nil
+	})
+
+	// Delete the manual chunk edits
+	wg.Go(func() error {
+		if err := s.chunkService.DeleteChunkEdits(ctx, ids); err != nil {
+			logger.GetLogger(ctx).WithField("error", err).Errorf("DeleteKnowledge delete chunk edits failed")
+			return err
+		}
+		return nil
+	})
+
 	if err = wg.Wait(); err != nil {
 		return err
 	}
@@ -958,6 +1025,10 @@ func (s *knowledgeService) cloneKnowledge(
 		FilePath:         src.FilePath,
 		StorageSize:      src.StorageSize,
 		Metadata:         src.Metadata,
+		ValidFrom:        src.ValidFrom,
+		ValidUntil:       src.ValidUntil,
+		ReviewBy:         src.ReviewBy,
+		ContentSignature: src.ContentSignature,
 	}
 	defer func() {
 		if err != nil {
@@ -1026,6 +1097,8 @@ func (s *knowledgeService) processDocumentFromPassage(ctx context.Context,
 type ProcessChunksOptions struct {
 	EnableQuestionGeneration bool
 	QuestionCount            int
+	// Embedder holds the vectors of chunks embedded while docreader was parsing, indexing embeds only the rest
+	Embedder *embedding.PrecomputedEmbedder
 }
 
 // processChunks processes chunks and creates embeddings for knowledge content
@@ -1056,11 +1129,14 @@ func (s *knowledgeService) processChunks(ctx context.Context,
 		return
 	}
 
+	stage := s.startStage(ctx, knowledge.TenantID, knowledge.KnowledgeBaseID, knowledge.ID, types.ProcessingStageIndex, 0)
+
 	// Get embedding model for vectorization
 	embeddingModel, err := s.modelService.GetEmbeddingModel(ctx, kb.EmbeddingModelID)
 	if err != nil {
 		logger.GetLogger(ctx).WithField("error", err).Errorf("processChunks get embedding model failed")
 		span.RecordError(err)
+		stage.finish(ctx, err)
 		return
 	}
 
@@ -1274,6 +1350,26 @@ func (s *knowledgeService) processChunks(ctx context.Context,
 		}
 	}
 
+	// 重放用户对分块的手动编辑（修改、拆分、合并），使其在重新解析后保留
+	textChunks, mergedChunks := s.chunkService.ReplayChunkEdits(ctx, knowledge.ID, textChunks)
+	if len(mergedChunks) > 0 {
+		for _, chunk := range insertChunks {
+			if parentID, ok := mergedChunks[chunk.ParentChunkID]; ok {
+				chunk.ParentChunkID = parentID
+			}
+		}
+	}
+	replayedChunks := make([]*types.Chunk, 0, len(insertChunks))
+	for _, chunk := range insertChunks {
+		if chunk.ChunkType != types.ChunkTypeText {
+			replayedChunks = append(replayedChunks, chunk)
+		}
+	}
+	insertChunks = append(replayedChunks, textChunks...)
+	sort.SliceStable(insertChunks, func(i, j int) bool {
+		return insertChunks[i].ChunkIndex < insertChunks[j].ChunkIndex
+	})
+
 	// 设置文本Chunk之间的前后关系
 	for i, chunk := range textChunks {
 		if i > 0 {
@@ -1284,9 +1380,36 @@ func (s *knowledgeService) processChunks(ctx context.Context,
 		}
 	}
 
+	// 近似重复检测：与知识库中已有文档比较内容签名，阻止模式下终止处理
+	if err := s.detectDuplicate(ctx, kb, knowledge, textChunks); err != nil {
+		knowledge.ParseStatus = types.ParseStatusFailed
+		knowledge.ErrorMessage = err.Error()
+		knowledge.UpdatedAt = time.Now()
+		s.repo.UpdateKnowledge(ctx, knowledge)
+		span.RecordError(err)
+		stage.finish(ctx, err)
+		return
+	}
+
+	// 父子分块：文本Chunk作为父块存储，仅索引从中切分出的小子块
+	indexedChunks := insertChunks
+	if kb.ChunkingConfig.EnableParentChild {
+		childChunks, indexedTextChunks := buildChildChunks(kb.ChunkingConfig, embeddingTokenizer(ctx, s.modelService, kb), textChunks)
+		indexedChunks = make([]*types.Chunk, 0, len(insertChunks)+len(childChunks))
+		for _, chunk := range insertChunks {
+			if chunk.ChunkType != types.ChunkTypeText {
+				indexedChunks = append(indexedChunks, chunk)
+			}
+		}
+		indexedChunks = append(indexedChunks, indexedTextChunks...)
+		insertChunks = append(insertChunks, childChunks...)
+		logger.GetLogger(ctx).Infof("Created %d child chunks from %d parent chunks", len(childChunks), len(textChunks))
+	}
+
 	// Create index information for each chunk (without generated questions for now)
-	indexInfoList := make([]*types.IndexInfo, 0, len(insertChunks))
-	for _, chunk := range insertChunks {
+	indexInfoList := make([]*types.IndexInfo, 0, len(indexedChunks))
+	customMetadata := knowledge.GetCustomMetadata()
+	for _, chunk := range indexedChunks {
 		// Add original chunk content to index
 		indexInfoList = append(indexInfoList, &types.IndexInfo{
 			Content:         chunk.Content,
@@ -1295,6 +1418,7 @@ func (s *knowledgeService) processChunks(ctx context.Context,
 			ChunkID:         chunk.ID,
 			KnowledgeID:     knowledge.ID,
 			KnowledgeBaseID: knowledge.KnowledgeBaseID,
+			Metadata:        customMetadata,
 		})
 	}
 
@@ -1312,6 +1436,7 @@ func (s *knowledgeService) processChunks(ctx context.Context,
 			knowledge.UpdatedAt = time.Now()
 			s.repo.UpdateKnowledge(ctx, knowledge)
 			span.RecordError(err)
+			stage.finish(ctx, err)
 			return
 		}
 		// Check if there's enough storage quota available
@@ -1321,6 +1446,7 @@ func (s *knowledgeService) processChunks(ctx context.Context,
 			knowledge.UpdatedAt = time.Now()
 			s.repo.UpdateKnowledge(ctx, knowledge)
 			span.RecordError(errors.New("storage quota exceeded"))
+			stage.finish(ctx, errors.New("storage quota exceeded"))
 			return
 		}
 	}
@@ -1340,6 +1466,7 @@ func (s *knowledgeService) processChunks(ctx context.Context,
 		knowledge.UpdatedAt = time.Now()
 		s.repo.UpdateKnowledge(ctx, knowledge)
 		span.RecordError(err)
+		stage.finish(ctx, err)
 		return
 	}
 
@@ -1355,38 +1482,33 @@ func (s *knowledgeService) processChunks(ctx context.Context,
 	}
 
 	span.AddEvent("batch index")
-	err = retrieveEngine.BatchIndex(ctx, embeddingModel, indexInfoList)
+	var indexEmbedder embedding.Embedder = embeddingModel
+	if options.Embedder != nil {
+		indexEmbedder = options.Embedder
+	}
+	err = retrieveEngine.BatchIndex(ctx, indexEmbedder, indexInfoList)
 	if err != nil {
 		knowledge.ParseStatus = types.ParseStatusFailed
 		knowledge.ErrorMessage = err.Error()
 		knowledge.UpdatedAt = time.Now()
 		s.repo.UpdateKnowledge(ctx, knowledge)
 
-		// delete failed chunks
-		if err := s.chunkService.DeleteChunksByKnowledgeID(ctx, knowledge.ID); err != nil {
-			logger.Errorf(ctx, "Delete chunks failed: %v", err)
-		}
-
-		// delete index
+		// delete the partial index, keeping the chunks so that only the index stage needs a retry
 		if err := retrieveEngine.DeleteByKnowledgeIDList(
 			ctx, []string{knowledge.ID}, embeddingModel.GetDimensions(), kb.Type,
 		); err != nil {
 			logger.Errorf(ctx, "Delete index failed: %v", err)
 		}
 		span.RecordError(err)
+		stage.finish(ctx, err)
 		return
 	}
 	logger.GetLogger(ctx).Infof("processChunks batch index successfully, with %d index", len(indexInfoList))
+	stage.finish(ctx, nil)
 
 	logger.Infof(ctx, "processChunks create relationship rag task")
-	if kb.ExtractConfig != nil && kb.ExtractConfig.Enabled {
-		for _, chunk := range textChunks {
-			err := NewChunkExtractTask(ctx, s.task, chunk.TenantID, chunk.ID, kb.SummaryModelID)
-			if err != nil {
-				logger.GetLogger(ctx).WithField("error", err).Errorf("processChunks create chunk extract task failed")
-				span.RecordError(err)
-			}
-		}
+	if err := s.enqueueGraphExtraction(ctx, kb, knowledge, textChunks); err != nil {
+		span.RecordError(err)
 	}
 
 	// Final check before marking as completed - if deleted during processing, don't update status
@@ -1422,6 +1544,13 @@ func (s *knowledgeService) processChunks(ctx context.Context,
 		logger.GetLogger(ctx).WithField("error", err).Errorf("processChunks update knowledge failed")
 	}
 
+	// 不在有效期内的知识处理完成后立即在检索中禁用
+	if !knowledge.IsValidAt(now) {
+		if err := s.setKnowledgeEnableStatus(ctx, knowledge, types.KnowledgeEnableStatusExpired); err != nil {
+			logger.GetLogger(ctx).WithField("error", err).Errorf("processChunks disable expired knowledge failed")
+		}
+	}
+
 	// Enqueue question generation task if enabled (async, non-blocking)
 	if options.EnableQuestionGeneration && len(textChunks) > 0 {
 		questionCount := options.QuestionCount
@@ -1554,7 +1683,7 @@ func (s *knowledgeService) getSummary(ctx context.Context,
 // enqueueQuestionGenerationTask enqueues an async task for question generation
 func (s *knowledgeService) enqueueQuestionGenerationTask(ctx context.Context,
 	kbID, knowledgeID string, questionCount int,
-) {
+) error {
 	tenantID := ctx.Value(types.TenantIDContextKey).(uint64)
 	payload := types.QuestionGenerationPayload{
 		TenantID:        tenantID,
@@ -1566,22 +1695,24 @@ func (s *knowledgeService) enqueueQuestionGenerationTask(ctx context.Context,
 	payloadBytes, err := json.Marshal(payload)
 	if err != nil {
 		logger.Errorf(ctx, "Failed to marshal question generation payload: %v", err)
-		return
+		return err
 	}
 
 	task := asynq.NewTask(types.TypeQuestionGeneration, payloadBytes, asynq.Queue("low"), asynq.MaxRetry(3))
 	info, err := s.task.Enqueue(task)
 	if err != nil {
 		logger.Errorf(ctx, "Failed to enqueue question generation task: %v", err)
-		return
+		return err
 	}
+	s.markStagePending(ctx, tenantID, kbID, knowledgeID, types.ProcessingStageQuestionGeneration)
 	logger.Infof(ctx, "Enqueued question generation task: %s for knowledge: %s", info.ID, knowledgeID)
+	return nil
 }
 
 // enqueueSummaryGenerationTask enqueues an async task for summary generation
 func (s *knowledgeService) enqueueSummaryGenerationTask(ctx context.Context,
 	kbID, knowledgeID string,
-) {
+) error {
 	tenantID := ctx.Value(types.TenantIDContextKey).(uint64)
 	payload := types.SummaryGenerationPayload{
 		TenantID:        tenantID,
@@ -1592,26 +1723,32 @@ func (s *knowledgeService) enqueueSummaryGenerationTask(ctx context.Context,
 	payloadBytes, err := json.Marshal(payload)
 	if err != nil {
 		logger.Errorf(ctx, "Failed to marshal summary generation payload: %v", err)
-		return
+		return err
 	}
 
 	task := asynq.NewTask(types.TypeSummaryGeneration, payloadBytes, asynq.Queue("low"), asynq.MaxRetry(3))
 	info, err := s.task.Enqueue(task)
 	if err != nil {
 		logger.Errorf(ctx, "Failed to enqueue summary generation task: %v", err)
-		return
+		return err
 	}
+	s.markStagePending(ctx, tenantID, kbID, knowledgeID, types.ProcessingStageSummaryGeneration)
 	logger.Infof(ctx, "Enqueued summary generation task: %s for knowledge: %s", info.ID, knowledgeID)
+	return nil
 }
 
 // ProcessSummaryGeneration handles async summary generation task
-func (s *knowledgeService) ProcessSummaryGeneration(ctx context.Context, t *asynq.Task) error {
+func (s *knowledgeService) ProcessSummaryGeneration(ctx context.Context, t *asynq.Task) (err error) {
 	var payload types.SummaryGenerationPayload
 	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
 		logger.Errorf(ctx, "Failed to unmarshal summary generation payload: %v", err)
 		return nil // Don't retry on unmarshal error
 	}
 
+	stage := s.startStage(ctx, payload.TenantID, payload.KnowledgeBaseID, payload.KnowledgeID,
+		types.ProcessingStageSummaryGeneration, 0)
+	defer func() { stage.finish(ctx, err) }()
+
 	logger.Infof(ctx, "Processing summary generation for knowledge: %s", payload.KnowledgeID)
 
 	// Set tenant context
@@ -1621,6 +1758,7 @@ func (s *knowledgeService) ProcessSummaryGeneration(ctx context.Context, t *asyn
 	kb, err := s.kbService.GetKnowledgeBaseByID(ctx, payload.KnowledgeBaseID)
 	if err != nil {
 		logger.Errorf(ctx, "Failed to get knowledge base: %v", err)
+		stage.fail(err)
 		return nil
 	}
 
@@ -1628,6 +1766,7 @@ func (s *knowledgeService) ProcessSummaryGeneration(ctx context.Context, t *asyn
 	knowledge, err := s.repo.GetKnowledgeByID(ctx, payload.TenantID, payload.KnowledgeID)
 	if err != nil {
 		logger.Errorf(ctx, "Failed to get knowledge: %v", err)
+		stage.fail(err)
 		return nil
 	}
 
@@ -1651,6 +1790,7 @@ func (s *knowledgeService) ProcessSummaryGeneration(ctx context.Context, t *asyn
 	chunks, err := s.chunkService.ListChunksByKnowledgeID(ctx, payload.KnowledgeID)
 	if err != nil {
 		logger.Errorf(ctx, "Failed to get chunks: %v", err)
+		stage.fail(err)
 		markSummaryFailed()
 		return nil
 	}
@@ -1766,12 +1906,16 @@ func (s *knowledgeService) ProcessSummaryGeneration(ctx context.Context, t *asyn
 			ChunkID:         summaryChunk.ID,
 			KnowledgeID:     knowledge.ID,
 			KnowledgeBaseID: knowledge.KnowledgeBaseID,
+			Metadata:        knowledge.GetCustomMetadata(),
 		}}
 
 		if err := retrieveEngine.BatchIndex(ctx, embeddingModel, indexInfo); err != nil {
 			logger.Errorf(ctx, "Failed to index summary chunk: %v", err)
 			return fmt.Errorf("failed to index summary chunk: %w", err)
 		}
+		if err := disableExpiredIndex(ctx, retrieveEngine, knowledge, []string{summaryChunk.ID}); err != nil {
+			logger.Warnf(ctx, "Failed to disable summary chunk of expired knowledge: %v", err)
+		}
 
 		logger.Infof(ctx, "Successfully created and indexed summary chunk for knowledge: %s", payload.KnowledgeID)
 	}
@@ -1781,7 +1925,7 @@ func (s *knowledgeService) ProcessSummaryGeneration(ctx context.Context, t *asyn
 }
 
 // ProcessQuestionGeneration handles async question generation task
-func (s *knowledgeService) ProcessQuestionGeneration(ctx context.Context, t *asynq.Task) error {
+func (s *knowledgeService) ProcessQuestionGeneration(ctx context.Context, t *asynq.Task) (err error) {
 	ctx, span := tracing.ContextWithSpan(ctx, "knowledgeService.ProcessQuestionGeneration")
 	defer span.End()
 
@@ -1791,6 +1935,10 @@ func (s *knowledgeService) ProcessQuestionGeneration(ctx context.Context, t *asy
 		return nil // Don't retry on unmarshal error
 	}
 
+	stage := s.startStage(ctx, payload.TenantID, payload.KnowledgeBaseID, payload.KnowledgeID,
+		types.ProcessingStageQuestionGeneration, 0)
+	defer func() { stage.finish(ctx, err) }()
+
 	logger.Infof(ctx, "Processing question generation for knowledge: %s", payload.KnowledgeID)
 
 	// Set tenant context
@@ -1800,6 +1948,7 @@ func (s *knowledgeService) ProcessQuestionGeneration(ctx context.Context, t *asy
 	kb, err := s.kbService.GetKnowledgeBaseByID(ctx, payload.KnowledgeBaseID)
 	if err != nil {
 		logger.Errorf(ctx, "Failed to get knowledge base: %v", err)
+		stage.fail(err)
 		return nil
 	}
 
@@ -1807,6 +1956,7 @@ func (s *knowledgeService) ProcessQuestionGeneration(ctx context.Context, t *asy
 	knowledge, err := s.repo.GetKnowledgeByID(ctx, payload.TenantID, payload.KnowledgeID)
 	if err != nil {
 		logger.Errorf(ctx, "Failed to get knowledge: %v", err)
+		stage.fail(err)
 		return nil
 	}
 
@@ -1814,6 +1964,7 @@ func (s *knowledgeService) ProcessQuestionGeneration(ctx context.Context, t *asy
 	chunks, err := s.chunkService.ListChunksByKnowledgeID(ctx, payload.KnowledgeID)
 	if err != nil {
 		logger.Errorf(ctx, "Failed to get chunks: %v", err)
+		stage.fail(err)
 		return nil
 	}
 
@@ -1933,6 +2084,7 @@ func (s *knowledgeService) ProcessQuestionGeneration(ctx context.Context, t *asy
 				ChunkID:         chunk.ID,
 				KnowledgeID:     knowledge.ID,
 				KnowledgeBaseID: knowledge.KnowledgeBaseID,
+				Metadata:        knowledge.GetCustomMetadata(),
 			})
 		}
 		logger.Debugf(ctx, "Generated %d questions for chunk %s", len(questions), chunk.ID)
@@ -1944,6 +2096,13 @@ func (s *knowledgeService) ProcessQuestionGeneration(ctx context.Context, t *asy
 			logger.Errorf(ctx, "Failed to index generated questions: %v", err)
 			return fmt.Errorf("failed to index questions: %w", err)
 		}
+		chunkIDs := make([]string, 0, len(indexInfoList))
+		for _, info := range indexInfoList {
+			chunkIDs = append(chunkIDs, info.ChunkID)
+		}
+		if err := disableExpiredIndex(ctx, retrieveEngine, knowledge, chunkIDs); err != nil {
+			logger.Warnf(ctx, "Failed to disable generated questions of expired knowledge: %v", err)
+		}
 		logger.Infof(ctx, "Successfully indexed %d generated questions for knowledge: %s", len(indexInfoList), payload.KnowledgeID)
 	}
 
@@ -2075,16 +2234,59 @@ func (s *knowledgeService) UpdateKnowledge(ctx context.Context, knowledge *types
 	if knowledge.Title != "" {
 		record.Title = knowledge.Title
 	}
+	// Custom metadata is replaced as a whole, an empty object or null clears it
+	metadataChanged := len(knowledge.CustomMetadata) > 0
+	if metadataChanged {
+		customMetadata, err := knowledge.CustomMetadata.Map()
+		if err != nil {
+			return werrors.NewValidationError("custom_metadata must be a JSON object")
+		}
+		record.CustomMetadata, err = buildCustomMetadata(customMetadata)
+		if err != nil {
+			return err
+		}
+	}
 
 	// Update knowledge record in the repository
 	if err := s.repo.UpdateKnowledge(ctx, record); err != nil {
 		logger.Errorf(ctx, "Failed to update knowledge: %v", err)
 		return err
 	}
+
+	// Propagate custom metadata to the index so that metadata filters see the change
+	if metadataChanged {
+		tenantInfo := ctx.Value(types.TenantInfoContextKey).(*types.Tenant)
+		retrieveEngine, err := retriever.NewCompositeRetrieveEngine(s.retrieveEngine, tenantInfo.GetEffectiveEngines())
+		if err != nil {
+			logger.Errorf(ctx, "Failed to init retrieve engine: %v", err)
+			return err
+		}
+		if err := retrieveEngine.BatchUpdateKnowledgeMetadata(ctx, map[string]map[string]any{
+			record.ID: record.GetCustomMetadata(),
+		}); err != nil {
+			logger.Errorf(ctx, "Failed to update knowledge metadata in index: %v", err)
+			return err
+		}
+	}
 	logger.Infof(ctx, "Knowledge updated successfully, ID: %s", knowledge.ID)
 	return nil
 }
 
+// buildCustomMetadata validates custom knowledge metadata and converts it to JSON, empty metadata is stored as NULL
+func buildCustomMetadata(metadata map[string]any) (types.JSON, error) {
+	if len(metadata) == 0 {
+		return nil, nil
+	}
+	if err := types.ValidateCustomMetadata(metadata); err != nil {
+		return nil, werrors.NewValidationError(err.Error())
+	}
+	metadataBytes, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+	return types.JSON(metadataBytes), nil
+}
+
 // UpdateManualKnowledge updates manual Markdown knowledge content.
 func (s *knowledgeService) UpdateManualKnowledge(ctx context.Context,
 	knowledgeID string, payload *types.ManualKnowledgePayload,
@@ -2152,7 +2354,9 @@ func (s *knowledgeService) UpdateManualKnowledge(ctx context.Context,
 	existing.FileName = ensureManualFileName(existing.Title)
 	existing.FileType = types.KnowledgeTypeManual
 	existing.Type = types.KnowledgeTypeManual
-	existing.Source = types.KnowledgeTypeManual
+	if existing.Source == "" {
+		existing.Source = types.KnowledgeTypeManual
+	}
 	existing.EnableStatus = "disabled"
 	existing.UpdatedAt = time.Now()
 
@@ -2283,7 +2487,7 @@ func (s *knowledgeService) CloneKnowledgeBase(ctx context.Context, srcID, dstID
 		g.Go(func() error {
 			err := s.DeleteKnowledgeList(gctx, ids)
 			if err != nil {
-				logger.Errorf(gctx, "delete partial knowledge %v: %w", ids, err)
+				logger.Errorf(gctx, "delete partial knowledge %v: %v", ids, err)
 				return err
 			}
 			return nil
@@ -2302,12 +2506,12 @@ func (s *knowledgeService) CloneKnowledgeBase(ctx context.Context, srcID, dstID
 		g.Go(func() error {
 			srcKn, err := s.repo.GetKnowledgeByID(gctx, srcKB.TenantID, knowledge)
 			if err != nil {
-				logger.Errorf(gctx, "get knowledge %s: %w", knowledge, err)
+				logger.Errorf(gctx, "get knowledge %s: %v", knowledge, err)
 				return err
 			}
 			err = s.cloneKnowledge(gctx, srcKn, dstKB)
 			if err != nil {
-				logger.Errorf(gctx, "clone knowledge %s: %w", knowledge, err)
+				logger.Errorf(gctx, "clone knowledge %s: %v", knowledge, err)
 				return err
 			}
 			return nil
@@ -2332,6 +2536,21 @@ func (s *knowledgeService) updateChunkVector(ctx context.Context, kbID string, c
 		return err
 	}
 
+	// Load custom metadata of the knowledge so that re-indexed chunks keep it
+	tenantInfo := ctx.Value(types.TenantInfoContextKey).(*types.Tenant)
+	knowledgeIDs := make([]string, 0, len(chunks))
+	for _, chunk := range chunks {
+		knowledgeIDs = append(knowledgeIDs, chunk.KnowledgeID)
+	}
+	knowledgeList, err := s.repo.GetKnowledgeBatch(ctx, tenantInfo.ID, slices.Compact(slices.Sorted(slices.Values(knowledgeIDs))))
+	if err != nil {
+		return err
+	}
+	customMetadata := make(map[string]map[string]any, len(knowledgeList))
+	for _, knowledge := range knowledgeList {
+		customMetadata[knowledge.ID] = knowledge.GetCustomMetadata()
+	}
+
 	// Initialize composite retrieve engine from tenant configuration
 	indexInfo := make([]*types.IndexInfo, 0, len(chunks))
 	ids := make([]string, 0, len(chunks))
@@ -2347,11 +2566,11 @@ func (s *knowledgeService) updateChunkVector(ctx context.Context, kbID string, c
 			ChunkID:         chunk.ID,
 			KnowledgeID:     chunk.KnowledgeID,
 			KnowledgeBaseID: chunk.KnowledgeBaseID,
+			Metadata:        customMetadata[chunk.KnowledgeID],
 		})
 		ids = append(ids, chunk.ID)
 	}
 
-	tenantInfo := ctx.Value(types.TenantInfoContextKey).(*types.Tenant)
 	retrieveEngine, err := retriever.NewCompositeRetrieveEngine(s.retrieveEngine, tenantInfo.GetEffectiveEngines())
 	if err != nil {
 		return err
@@ -2573,6 +2792,7 @@ func (s *knowledgeService) CloneChunk(ctx context.Context, src, dst *types.Knowl
 			"",
 			"",
 			"",
+			"",
 		)
 		chunkPage++
 		if err != nil {
@@ -2668,6 +2888,7 @@ func (s *knowledgeService) CloneChunk(ctx context.Context, src, dst *types.Knowl
 // ListFAQEntries lists FAQ entries under a FAQ knowledge base.
 func (s *knowledgeService) ListFAQEntries(ctx context.Context,
 	kbID string, page *types.Pagination, tagSeqID int64, keyword string, searchField string, sortOrder string,
+	reviewStatus types.FAQReviewStatus,
 ) (*types.PageResult, error) {
 	if page == nil {
 		page = &types.Pagination{}
@@ -2699,6 +2920,7 @@ func (s *knowledgeService) ListFAQEntries(ctx context.Context,
 	chunkType := []types.ChunkType{types.ChunkTypeFAQ}
 	chunks, total, err := s.chunkRepo.ListPagedChunksByKnowledgeID(
 		ctx, tenantID, faqKnowledge.ID, page, chunkType, tagID, keyword, searchField, sortOrder, types.KnowledgeTypeFAQ,
+		string(reviewStatus),
 	)
 	if err != nil {
 		return nil, err
@@ -2839,6 +3061,8 @@ func (s *knowledgeService) UpsertFAQEntries(ctx context.Context,
 		KnowledgeID: knowledgeID,
 		Mode:        payload.Mode,
 		DryRun:      payload.DryRun,
+		AsDraft:     payload.AsDraft,
+		UserID:      contextUserID(ctx),
 		EnqueuedAt:  enqueuedAt,
 	}
 
@@ -2939,7 +3163,7 @@ func (s *knowledgeService) generateFailedEntriesCSV(ctx context.Context,
 	buf.WriteString("\xEF\xBB\xBF")
 
 	// 写入表头
-	buf.WriteString("错误原因,分类(必填),问题(必填),相似问题(选填-多个用##分隔),反例问题(选填-多个用##分隔),机器人回答(必填-多个用##分隔),是否全部回复(选填-默认FALSE),是否停用(选填-默认FALSE)\n")
+	buf.WriteString("错误原因,分类(必填),问题(必填),相似问题(选填-多个用##分隔),反例问题(选填-多个用##分隔),机器人回答(必填-多个用##分隔),是否全部回复(选填-默认FALSE),是否停用(选填-默认FALSE),多语言渠道回答(选填-每行一个变体 [语言|渠道] 回答1##回答2)\n")
 
 	// 写入数据行
 	for _, entry := range failedEntries {
@@ -2967,9 +3191,10 @@ func (s *knowledgeService) generateFailedEntriesCSV(ctx context.Context,
 		if entry.IsDisabled {
 			isDisabled = "true"
 		}
+		answerVariants := csvEscape(types.FormatFAQAnswerVariants(entry.AnswerVariants))
 
-		buf.WriteString(fmt.Sprintf("%s,%s,%s,%s,%s,%s,%s,%s\n",
-			reason, tagName, standardQ, similarQs, negativeQs, answers, answerAll, isDisabled))
+		buf.WriteString(fmt.Sprintf("%s,%s,%s,%s,%s,%s,%s,%s,%s\n",
+			reason, tagName, standardQ, similarQs, negativeQs, answers, answerAll, isDisabled, answerVariants))
 	}
 
 	// 上传 CSV 文件到临时存储（会自动过期）
@@ -3067,6 +3292,7 @@ func buildFAQFailedEntry(idx int, reason string, entry *types.FAQEntryPayload) t
 		SimilarQuestions:  entry.SimilarQuestions,
 		NegativeQuestions: entry.NegativeQuestions,
 		Answers:           entry.Answers,
+		AnswerVariants:    entry.AnswerVariants,
 		AnswerAll:         answerAll,
 		IsDisabled:        isDisabled,
 	}
@@ -3290,6 +3516,9 @@ func validateFAQEntryPayloadBasic(entry *types.FAQEntryPayload) error {
 	if !hasValidAnswer {
 		return fmt.Errorf("答案不能全为空")
 	}
+	if !isValidNewFAQReviewStatus(entry.ReviewStatus) {
+		return fmt.Errorf("审核状态仅支持 draft 或 published")
+	}
 	return nil
 }
 
@@ -3643,6 +3872,13 @@ func (s *knowledgeService) executeFAQImport(ctx context.Context, taskID string,
 				})
 				return fmt.Errorf("failed to sanitize entry at index %d: %w", i+idx, err)
 			}
+			if payload.AsDraft {
+				meta.ReviewStatus = types.FAQReviewStatusDraft
+			}
+			meta.ReviewStatus = newFAQReviewStatus(kb, meta.ReviewStatus)
+			if payload.Editor != "" {
+				meta.LastEditor = payload.Editor
+			}
 
 			// 解析 TagID
 			tagID, err := s.resolveTagID(ctx, kbID, &entry)
@@ -3717,10 +3953,10 @@ func (s *knowledgeService) executeFAQImport(ctx context.Context, taskID string,
 			indexDuration,
 		)
 
-		// 更新chunks的Status为已索引
+		// 更新chunks的Status，未发布的条目没有写入索引
 		chunksToUpdate := make([]*types.Chunk, 0, len(chunks))
 		for _, chunk := range chunks {
-			chunk.Status = int(types.ChunkStatusIndexed) // indexed
+			chunk.Status = faqChunkStatus(chunk)
 			chunksToUpdate = append(chunksToUpdate, chunk)
 		}
 		if err := s.chunkService.UpdateChunks(ctx, chunksToUpdate); err != nil {
@@ -3812,6 +4048,8 @@ func (s *knowledgeService) CreateFAQEntry(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	meta.ReviewStatus = newFAQReviewStatus(kb, meta.ReviewStatus)
+	meta.LastEditor = contextUserID(ctx)
 
 	// 解析 TagID
 	tagID, err := s.resolveTagID(ctx, kbID, payload)
@@ -3824,6 +4062,14 @@ func (s *knowledgeService) CreateFAQEntry(ctx context.Context,
 		return nil, err
 	}
 
+	// 检测语义相近但答案不同的现有条目，仅作提示，不阻止创建
+	questions := faqEntryQuestions(meta.StandardQuestion, meta.SimilarQuestions)
+	conflicts, err := s.findFAQConflicts(ctx, kb.ID, questions, meta.Answers, 0, types.DefaultFAQConflictThreshold)
+	if err != nil {
+		logger.Warnf(ctx, "Failed to check FAQ entry conflicts: %v", err)
+		conflicts = nil
+	}
+
 	// 确保FAQ Knowledge存在
 	faqKnowledge, err := s.ensureFAQKnowledge(ctx, tenantID, kb)
 	if err != nil {
@@ -3879,15 +4125,15 @@ func (s *knowledgeService) CreateFAQEntry(ctx context.Context,
 		return nil, fmt.Errorf("failed to create chunk: %w", err)
 	}
 
-	// 索引chunk
+	// 索引chunk（未发布的条目不会写入向量）
 	if err := s.indexFAQChunks(ctx, kb, faqKnowledge, []*types.Chunk{chunk}, embeddingModel, true, false); err != nil {
 		// 如果索引失败，删除已创建的chunk
 		_ = s.chunkService.DeleteChunk(ctx, chunk.ID)
 		return nil, fmt.Errorf("failed to index chunk: %w", err)
 	}
 
-	// 更新chunk状态为已索引
-	chunk.Status = int(types.ChunkStatusIndexed)
+	// 更新chunk状态，未发布的条目标记为未发布
+	chunk.Status = faqChunkStatus(chunk)
 	if err := s.chunkService.UpdateChunk(ctx, chunk); err != nil {
 		return nil, fmt.Errorf("failed to update chunk status: %w", err)
 	}
@@ -3915,6 +4161,11 @@ func (s *knowledgeService) CreateFAQEntry(ctx context.Context,
 		}
 	}
 
+	if len(conflicts) > 0 {
+		entry.Conflicts = conflicts
+		entry.MergeSuggestion = suggestFAQMerge(questions, conflicts, 0)
+	}
+
 	return entry, nil
 }
 
@@ -4018,8 +4269,28 @@ func (s *knowledgeService) UpdateFAQEntry(ctx context.Context,
 	if kb.FAQConfig != nil && kb.FAQConfig.QuestionIndexMode != "" {
 		questionIndexMode = kb.FAQConfig.QuestionIndexMode
 	}
+	// 审核状态只能通过审核流程变更，更新时沿用原有状态
+	meta.ReviewStatus = ""
+	wasPublished := true
 	if existing, err := chunk.FAQMetadata(); err == nil && existing != nil {
 		meta.Version = existing.Version + 1
+		// 未提供答案变体时保留原有变体
+		if payload.AnswerVariants == nil {
+			meta.AnswerVariants = existing.AnswerVariants
+		}
+		meta.ReviewStatus = existing.ReviewStatus
+		meta.Reviewer = existing.Reviewer
+		meta.Submitter = existing.Submitter
+		meta.LastEditor = existing.LastEditor
+		wasPublished = existing.IsPublished()
+		// 要求审核的知识库中，已发布或待审核条目的措辞变更后需重新审核
+		if kb.FAQConfig != nil && kb.FAQConfig.RequireReview &&
+			types.CalculateFAQContentHash(meta) != types.CalculateFAQContentHash(existing) {
+			switch existing.ReviewStatus.OrPublished() {
+			case types.FAQReviewStatusPublished, types.FAQReviewStatusPendingReview:
+				meta.ReviewStatus = types.FAQReviewStatusDraft
+			}
+		}
 		// 保存旧的内容用于增量比较
 		if questionIndexMode == types.FAQQuestionIndexModeSeparate {
 			oldSimilarQuestions = existing.SimilarQuestions
@@ -4027,6 +4298,9 @@ func (s *knowledgeService) UpdateFAQEntry(ctx context.Context,
 			oldAnswers = existing.Answers
 		}
 	}
+	if editor := contextUserID(ctx); editor != "" {
+		meta.LastEditor = editor
+	}
 	if err := chunk.SetFAQMetadata(meta); err != nil {
 		return nil, err
 	}
@@ -4059,6 +4333,7 @@ func (s *knowledgeService) UpdateFAQEntry(ctx context.Context,
 			chunk.Flags = chunk.Flags.ClearFlag(types.ChunkFlagRecommended)
 		}
 	}
+	chunk.Status = faqChunkStatus(chunk)
 	chunk.UpdatedAt = time.Now()
 	if err := s.chunkService.UpdateChunk(ctx, chunk); err != nil {
 		return nil, err
@@ -4079,7 +4354,14 @@ func (s *knowledgeService) UpdateFAQEntry(ctx context.Context,
 	}
 
 	// 增量索引优化：只对变化的内容进行索引操作
-	if questionIndexMode == types.FAQQuestionIndexModeSeparate && len(oldSimilarQuestions) > 0 {
+	if !meta.IsPublished() {
+		// 未发布的条目不参与检索，从已发布状态退回草稿时删除其向量
+		if wasPublished {
+			if err := s.deleteFAQChunkVectors(ctx, kb, faqKnowledge, []*types.Chunk{chunk}); err != nil {
+				return nil, err
+			}
+		}
+	} else if questionIndexMode == types.FAQQuestionIndexModeSeparate && len(oldSimilarQuestions) > 0 {
 		// 分别索引模式下的增量更新
 		if err := s.incrementalIndexFAQEntry(ctx, kb, faqKnowledge, chunk, embeddingModel,
 			oldStandardQuestion, oldSimilarQuestions, oldAnswers, meta); err != nil {
@@ -4219,6 +4501,9 @@ func (s *knowledgeService) AddSimilarQuestions(ctx context.Context,
 	oldSimilarQuestions := meta.SimilarQuestions
 	meta.SimilarQuestions = append(meta.SimilarQuestions, newQuestions...)
 	meta.Version++
+	if editor := contextUserID(ctx); editor != "" {
+		meta.LastEditor = editor
+	}
 
 	if err := chunk.SetFAQMetadata(meta); err != nil {
 		return nil, err
@@ -4252,7 +4537,8 @@ func (s *knowledgeService) AddSimilarQuestions(ctx context.Context,
 		questionIndexMode = kb.FAQConfig.QuestionIndexMode
 	}
 
-	if questionIndexMode == types.FAQQuestionIndexModeSeparate {
+	// Unpublished entries are skipped by indexFAQChunks and indexed when they are published
+	if questionIndexMode == types.FAQQuestionIndexModeSeparate && meta.IsPublished() {
 		// Only index the new similar questions
 		if err := s.incrementalIndexFAQEntry(ctx, kb, faqKnowledge, chunk, embeddingModel,
 			meta.StandardQuestion, oldSimilarQuestions, meta.Answers, meta); err != nil {
@@ -5010,6 +5296,15 @@ func (s *knowledgeService) SearchFAQEntries(ctx context.Context,
 			entry.MatchedQuestion = matchedContent
 		}
 
+		// Return the answer variant for the requested locale and channel
+		if variant := types.SelectFAQAnswerVariant(entry.AnswerVariants, types.FAQAnswerPreference{
+			Locale: req.Locale, Channel: req.Channel,
+		}); variant != nil {
+			entry.Answers = variant.Answers
+			entry.AnswerLocale = variant.Locale
+			entry.AnswerChannel = variant.Channel
+		}
+
 		entries = append(entries, entry)
 	}
 
@@ -5165,10 +5460,10 @@ func (s *knowledgeService) DeleteFAQEntries(ctx context.Context,
 }
 
 // ExportFAQEntries exports all FAQ entries for a knowledge base as CSV data.
-// The CSV format matches the import example format with 8 columns:
+// The CSV format matches the import example format with 9 columns:
 // 分类(必填), 问题(必填), 相似问题(选填-多个用##分隔), 反例问题(选填-多个用##分隔),
 // 机器人回答(必填-多个用##分隔), 是否全部回复(选填-默认FALSE), 是否停用(选填-默认FALSE),
-// 是否禁止被推荐(选填-默认False 可被推荐)
+// 是否禁止被推荐(选填-默认False 可被推荐), 多语言渠道回答(选填-每行一个变体 [语言|渠道] 回答1##回答2)
 func (s *knowledgeService) ExportFAQEntries(ctx context.Context, kbID string) ([]byte, error) {
 	kb, err := s.validateFAQKnowledgeBase(ctx, kbID)
 	if err != nil {
@@ -5232,6 +5527,7 @@ func (s *knowledgeService) buildFAQCSV(chunks []*types.Chunk, tagMap map[string]
 		"是否全部回复(选填-默认FALSE)",
 		"是否停用(选填-默认FALSE)",
 		"是否禁止被推荐(选填-默认False 可被推荐)",
+		"多语言渠道回答(选填-每行一个变体 [语言|渠道] 回答1##回答2)",
 	}
 	buf.WriteString(strings.Join(headers, ","))
 	buf.WriteString("\n")
@@ -5261,6 +5557,7 @@ func (s *knowledgeService) buildFAQCSV(chunks []*types.Chunk, tagMap map[string]
 			boolToCSV(meta.AnswerStrategy == types.AnswerStrategyAll),
 			boolToCSV(!chunk.IsEnabled),                                 // 是否停用：取反
 			boolToCSV(!chunk.Flags.HasFlag(types.ChunkFlagRecommended)), // 是否禁止被推荐：取反
+			escapeCSVField(types.FormatFAQAnswerVariants(meta.AnswerVariants)),
 		}
 		buf.WriteString(strings.Join(row, ","))
 		buf.WriteString("\n")
@@ -5494,7 +5791,10 @@ func (s *knowledgeService) chunkToFAQEntry(chunk *types.Chunk, kb *types.Knowled
 		NegativeQuestions: meta.NegativeQuestions,
 		Answers:           meta.Answers,
 		AnswerStrategy:    answerStrategy,
+		AnswerVariants:    meta.AnswerVariants,
 		IndexMode:         kb.FAQConfig.IndexMode,
+		ReviewStatus:      meta.ReviewStatus.OrPublished(),
+		Reviewer:          meta.Reviewer,
 		UpdatedAt:         chunk.UpdatedAt,
 		CreatedAt:         chunk.CreatedAt,
 		ChunkType:         chunk.ChunkType,
@@ -5648,6 +5948,14 @@ func sanitizeFAQEntryPayload(payload *types.FAQEntryPayload) (*types.FAQChunkMet
 		AnswerStrategy:    answerStrategy,
 		Version:           1,
 		Source:            "faq",
+		AnswerVariants:    payload.AnswerVariants,
+		ReviewStatus:      payload.ReviewStatus,
+	}
+	if err := types.ValidateFAQAnswerVariants(payload.AnswerVariants); err != nil {
+		return nil, werrors.NewBadRequestError(err.Error())
+	}
+	if !isValidNewFAQReviewStatus(payload.ReviewStatus) {
+		return nil, werrors.NewBadRequestError("review_status 仅支持 draft 或 published")
 	}
 	meta.Normalize()
 	if meta.StandardQuestion == "" {
@@ -5928,6 +6236,8 @@ func (s *knowledgeService) indexFAQChunks(ctx context.Context,
 	chunks []*types.Chunk, embeddingModel embedding.Embedder,
 	adjustStorage bool, needDelete bool,
 ) error {
+	// 仅索引已发布的条目，草稿、待审核和已归档的条目不参与检索
+	chunks = filterPublishedFAQChunks(chunks)
 	if len(chunks) == 0 {
 		return nil
 	}
@@ -6031,6 +6341,19 @@ func (s *knowledgeService) indexFAQChunks(ctx context.Context,
 	return err
 }
 
+// filterPublishedFAQChunks keeps the chunks of published FAQ entries, chunks whose
+// metadata cannot be parsed are kept so they are indexed as before
+func filterPublishedFAQChunks(chunks []*types.Chunk) []*types.Chunk {
+	published := make([]*types.Chunk, 0, len(chunks))
+	for _, chunk := range chunks {
+		if meta, err := chunk.FAQMetadata(); err == nil && meta != nil && !meta.IsPublished() {
+			continue
+		}
+		published = append(published, chunk)
+	}
+	return published
+}
+
 func (s *knowledgeService) deleteFAQChunkVectors(ctx context.Context,
 	kb *types.KnowledgeBase, knowledge *types.Knowledge, chunks []*types.Chunk,
 ) error {
@@ -6097,8 +6420,9 @@ func (s *knowledgeService) triggerManualProcessing(ctx context.Context,
 	if clean == "" {
 		return
 	}
+	stage := s.startStage(ctx, knowledge.TenantID, knowledge.KnowledgeBaseID, knowledge.ID, types.ProcessingStageParse, 0)
 
-	// 使用 docreader 按照 MD 格式处理，并使用知识库配置的分隔符
+	// 按照 MD 格式处理，并使用知识库配置的分隔符
 	contentBytes := []byte(clean)
 	fileName := ensureManualFileName(knowledge.Title)
 	fileType := "md"
@@ -6116,6 +6440,7 @@ func (s *knowledgeService) triggerManualProcessing(ctx context.Context,
 			knowledge.ErrorMessage = cfgErr.Error()
 			knowledge.UpdatedAt = time.Now()
 			s.repo.UpdateKnowledge(ctx, knowledge)
+			stage.finish(ctx, cfgErr)
 			return
 		}
 		if cfg == nil {
@@ -6125,48 +6450,64 @@ func (s *knowledgeService) triggerManualProcessing(ctx context.Context,
 		vlmConfig = cfg
 	}
 
-	// 调用 docreader 解析 markdown 内容
-	resp, err := s.docReaderClient.ReadFromFile(ctx, &proto.ReadFromFileRequest{
-		FileContent: contentBytes,
-		FileName:    fileName,
-		FileType:    fileType,
-		ReadConfig: &proto.ReadConfig{
-			ChunkSize:        int32(kb.ChunkingConfig.ChunkSize),
-			ChunkOverlap:     int32(kb.ChunkingConfig.ChunkOverlap),
-			Separators:       kb.ChunkingConfig.Separators,
-			EnableMultimodal: enableMultimodel,
-			StorageConfig: &proto.StorageConfig{
-				Provider: proto.StorageProvider(
-					proto.StorageProvider_value[strings.ToUpper(kb.StorageConfig.Provider)],
-				),
-				Region:          kb.StorageConfig.Region,
-				BucketName:      kb.StorageConfig.BucketName,
-				AccessKeyId:     kb.StorageConfig.SecretID,
-				SecretAccessKey: kb.StorageConfig.SecretKey,
-				AppId:           kb.StorageConfig.AppID,
-				PathPrefix:      kb.StorageConfig.PathPrefix,
+	// 纯文本 Markdown 直接在 Go 侧分块，需要提取图片时才调用 docreader
+	var chunks []*proto.Chunk
+	if canChunkNatively(fileType, contentBytes, enableMultimodel) {
+		chunks = s.chunkNatively(ctx, kb, clean, fileType)
+	} else {
+		// 调用 docreader 解析 markdown 内容
+		resp, err := s.docReaderClient.ReadFromFile(ctx, &proto.ReadFromFileRequest{
+			FileContent: contentBytes,
+			FileName:    fileName,
+			FileType:    fileType,
+			ReadConfig: &proto.ReadConfig{
+				ChunkSize:        int32(kb.ChunkingConfig.ChunkSize),
+				ChunkOverlap:     int32(kb.ChunkingConfig.ChunkOverlap),
+				Separators:       kb.ChunkingConfig.Separators,
+				EnableMultimodal: enableMultimodel,
+				StorageConfig: &proto.StorageConfig{
+					Provider: proto.StorageProvider(
+						proto.StorageProvider_value[strings.ToUpper(kb.StorageConfig.Provider)],
+					),
+					Region:          kb.StorageConfig.Region,
+					BucketName:      kb.StorageConfig.BucketName,
+					AccessKeyId:     kb.StorageConfig.SecretID,
+					SecretAccessKey: kb.StorageConfig.SecretKey,
+					AppId:           kb.StorageConfig.AppID,
+					PathPrefix:      kb.StorageConfig.PathPrefix,
+				},
+				VlmConfig: vlmConfig,
 			},
-			VlmConfig: vlmConfig,
-		},
-		RequestId: ctx.Value(types.RequestIDContextKey).(string),
-	})
-	if err != nil {
-		logger.GetLogger(ctx).WithField("knowledge_id", knowledge.ID).
-			WithField("error", err).Errorf("triggerManualProcessing read file failed")
-		knowledge.ParseStatus = "failed"
-		knowledge.ErrorMessage = err.Error()
-		knowledge.UpdatedAt = time.Now()
-		s.repo.UpdateKnowledge(ctx, knowledge)
-		return
+			RequestId: ctx.Value(types.RequestIDContextKey).(string),
+		})
+		switch {
+		case err != nil && isDocReaderUnavailable(err):
+			logger.GetLogger(ctx).WithField("knowledge_id", knowledge.ID).
+				WithField("error", err).Warn("triggerManualProcessing docreader unavailable, falling back to native chunking")
+			chunks = s.chunkNatively(ctx, kb, clean, fileType)
+		case err != nil:
+			logger.GetLogger(ctx).WithField("knowledge_id", knowledge.ID).
+				WithField("error", err).Errorf("triggerManualProcessing read file failed")
+			knowledge.ParseStatus = "failed"
+			knowledge.ErrorMessage = err.Error()
+			knowledge.UpdatedAt = time.Now()
+			s.repo.UpdateKnowledge(ctx, knowledge)
+			stage.finish(ctx, err)
+			return
+		default:
+			chunks = s.rechunkDocReaderChunks(ctx, kb, resp.Chunks, fileType)
+		}
 	}
 
+	stage.finish(ctx, nil)
+
 	if sync {
-		s.processChunks(ctx, kb, knowledge, resp.Chunks)
+		s.processChunks(ctx, kb, knowledge, chunks)
 		return
 	}
 
 	newCtx := logger.CloneContext(ctx)
-	go s.processChunks(newCtx, kb, knowledge, resp.Chunks)
+	go s.processChunks(newCtx, kb, knowledge, chunks)
 }
 
 func (s *knowledgeService) cleanupKnowledgeResources(ctx context.Context, knowledge *types.Knowledge) error {
@@ -6360,6 +6701,7 @@ func (s *knowledgeService) ProcessDocument(ctx context.Context, t *asynq.Task) e
 		logger.Errorf(ctx, "failed to update knowledge status to processing: %v", err)
 		return nil
 	}
+	stage := s.startStage(ctx, knowledge.TenantID, knowledge.KnowledgeBaseID, knowledge.ID, types.ProcessingStageParse, 0)
 
 	// 构建VLM配置（如果需要）
 	var vlmConfig *proto.VLMConfig
@@ -6383,6 +6725,7 @@ func (s *knowledgeService) ProcessDocument(ctx context.Context, t *asynq.Task) e
 		knowledge.ErrorMessage = ErrImageNotParse.Error()
 		knowledge.UpdatedAt = time.Now()
 		s.repo.UpdateKnowledge(ctx, knowledge)
+		stage.finish(ctx, ErrImageNotParse)
 		return nil
 	}
 
@@ -6396,6 +6739,7 @@ func (s *knowledgeService) ProcessDocument(ctx context.Context, t *asynq.Task) e
 			knowledge.ErrorMessage = "URL is not allowed for security reasons"
 			knowledge.UpdatedAt = time.Now()
 			s.repo.UpdateKnowledge(ctx, knowledge)
+			stage.finish(ctx, errors.New(knowledge.ErrorMessage))
 			return nil
 		}
 
@@ -6429,10 +6773,11 @@ func (s *knowledgeService) ProcessDocument(ctx context.Context, t *asynq.Task) e
 				knowledge.ErrorMessage = err.Error()
 				knowledge.UpdatedAt = time.Now()
 				s.repo.UpdateKnowledge(ctx, knowledge)
+				stage.finish(ctx, err)
 			}
 			return fmt.Errorf("failed to read from URL: %w", err)
 		}
-		chunks = urlResp.Chunks
+		chunks = s.rechunkDocReaderChunks(ctx, kb, urlResp.Chunks, "html")
 	} else if len(payload.Passages) > 0 {
 		// 文本段落导入
 		chunks := make([]*proto.Chunk, 0, len(payload.Passages))
@@ -6452,6 +6797,7 @@ func (s *knowledgeService) ProcessDocument(ctx context.Context, t *asynq.Task) e
 			chunks = append(chunks, chunk)
 		}
 		// 直接处理chunks，不需要调用docReader
+		stage.finish(ctx, nil)
 		s.processChunks(ctx, kb, knowledge, chunks)
 		return nil
 	} else {
@@ -6466,10 +6812,55 @@ func (s *knowledgeService) ProcessDocument(ctx context.Context, t *asynq.Task) e
 				knowledge.ErrorMessage = err.Error()
 				knowledge.UpdatedAt = time.Now()
 				s.repo.UpdateKnowledge(ctx, knowledge)
+				stage.finish(ctx, err)
 			}
 			return fmt.Errorf("failed to get file: %w", err)
 		}
-		defer fileReader.Close()
+		defer func() { fileReader.Close() }()
+
+		// 大文件分片上传到 docreader 并流式接收分块，无需整体读入内存，也不受 gRPC 消息大小限制
+		if useDocReaderStream(payload.FileType, knowledge.FileSize) {
+			var precomputed *embedding.PrecomputedEmbedder
+			chunks, precomputed, err = s.readFromDocReaderStream(ctx, kb, fileReader, &proto.ReadFromFileStreamRequest{
+				FileName:   payload.FileName,
+				FileType:   payload.FileType,
+				ReadConfig: newReadConfig(kb, payload.EnableMultimodel, vlmConfig),
+				RequestId:  payload.RequestId,
+			})
+			switch {
+			case err == nil:
+				stage.finish(ctx, nil)
+				chunks = s.rechunkDocReaderChunks(ctx, kb, chunks, payload.FileType)
+				s.processChunks(ctx, kb, knowledge, chunks, ProcessChunksOptions{
+					EnableQuestionGeneration: payload.EnableQuestionGeneration,
+					QuestionCount:            payload.QuestionCount,
+					Embedder:                 precomputed,
+				})
+				return nil
+			case isDocReaderStreamUnsupported(err):
+				// 旧版 docreader 不支持分片读取，重新打开文件整体发送
+				logger.GetLogger(ctx).WithField("knowledge_id", knowledge.ID).
+					Warn("processDocument docreader does not support streaming, sending the file in one request")
+				fileReader.Close()
+				if fileReader, err = s.fileSvc.GetFile(ctx, payload.FilePath); err != nil {
+					if isLastRetry {
+						stage.finish(ctx, err)
+					}
+					return fmt.Errorf("failed to get file: %w", err)
+				}
+			default:
+				logger.GetLogger(ctx).WithField("knowledge_id", knowledge.ID).
+					WithField("error", err).Errorf("processDocument read file stream failed")
+				if isLastRetry {
+					knowledge.ParseStatus = "failed"
+					knowledge.ErrorMessage = err.Error()
+					knowledge.UpdatedAt = time.Now()
+					s.repo.UpdateKnowledge(ctx, knowledge)
+					stage.finish(ctx, err)
+				}
+				return fmt.Errorf("failed to read file from docreader: %w", err)
+			}
+		}
 
 		// 读取文件内容
 		contentBytes, err := io.ReadAll(fileReader)
@@ -6480,49 +6871,48 @@ func (s *knowledgeService) ProcessDocument(ctx context.Context, t *asynq.Task) e
 				knowledge.ErrorMessage = err.Error()
 				knowledge.UpdatedAt = time.Now()
 				s.repo.UpdateKnowledge(ctx, knowledge)
+				stage.finish(ctx, err)
 			}
 			return fmt.Errorf("failed to read file: %w", err)
 		}
 
-		// 调用docReader处理文件
-		fileResp, err := s.docReaderClient.ReadFromFile(ctx, &proto.ReadFromFileRequest{
-			FileContent: contentBytes,
-			FileName:    payload.FileName,
-			FileType:    payload.FileType,
-			ReadConfig: &proto.ReadConfig{
-				ChunkSize:        int32(kb.ChunkingConfig.ChunkSize),
-				ChunkOverlap:     int32(kb.ChunkingConfig.ChunkOverlap),
-				Separators:       kb.ChunkingConfig.Separators,
-				EnableMultimodal: payload.EnableMultimodel,
-				StorageConfig: &proto.StorageConfig{
-					Provider:        proto.StorageProvider(proto.StorageProvider_value[strings.ToUpper(kb.StorageConfig.Provider)]),
-					Region:          kb.StorageConfig.Region,
-					BucketName:      kb.StorageConfig.BucketName,
-					AccessKeyId:     kb.StorageConfig.SecretID,
-					SecretAccessKey: kb.StorageConfig.SecretKey,
-					AppId:           kb.StorageConfig.AppID,
-					PathPrefix:      kb.StorageConfig.PathPrefix,
-				},
-				VlmConfig: vlmConfig,
-			},
-			RequestId: payload.RequestId,
-		})
-		if err != nil {
-			logger.GetLogger(ctx).WithField("knowledge_id", knowledge.ID).
-				WithField("error", err).Errorf("processDocument read file failed")
-			// 如果是最后一次重试，更新状态为失败
-			if isLastRetry {
-				knowledge.ParseStatus = "failed"
-				knowledge.ErrorMessage = err.Error()
-				knowledge.UpdatedAt = time.Now()
-				s.repo.UpdateKnowledge(ctx, knowledge)
+		if canChunkNatively(payload.FileType, contentBytes, payload.EnableMultimodel) {
+			// 纯文本和 Markdown 直接在 Go 侧分块，无需调用 docReader
+			chunks = s.chunkNatively(ctx, kb, string(contentBytes), payload.FileType)
+		} else {
+			// 调用docReader处理文件
+			fileResp, err := s.docReaderClient.ReadFromFile(ctx, &proto.ReadFromFileRequest{
+				FileContent: contentBytes,
+				FileName:    payload.FileName,
+				FileType:    payload.FileType,
+				ReadConfig:  newReadConfig(kb, payload.EnableMultimodel, vlmConfig),
+				RequestId:   payload.RequestId,
+			})
+			switch {
+			case err != nil && isDocReaderUnavailable(err) && chunker.IsNativeFileType(payload.FileType):
+				logger.GetLogger(ctx).WithField("knowledge_id", knowledge.ID).
+					WithField("error", err).Warn("processDocument docreader unavailable, falling back to native chunking")
+				chunks = s.chunkNatively(ctx, kb, string(contentBytes), payload.FileType)
+			case err != nil:
+				logger.GetLogger(ctx).WithField("knowledge_id", knowledge.ID).
+					WithField("error", err).Errorf("processDocument read file failed")
+				// 如果是最后一次重试，更新状态为失败
+				if isLastRetry {
+					knowledge.ParseStatus = "failed"
+					knowledge.ErrorMessage = err.Error()
+					knowledge.UpdatedAt = time.Now()
+					s.repo.UpdateKnowledge(ctx, knowledge)
+					stage.finish(ctx, err)
+				}
+				return fmt.Errorf("failed to read file from docreader: %w", err)
+			default:
+				chunks = s.rechunkDocReaderChunks(ctx, kb, fileResp.Chunks, payload.FileType)
 			}
-			return fmt.Errorf("failed to read file from docreader: %w", err)
 		}
-		chunks = fileResp.Chunks
 	}
 
 	// 处理chunks（这会更新状态为completed）
+	stage.finish(ctx, nil)
 	s.processChunks(ctx, kb, knowledge, chunks, ProcessChunksOptions{
 		EnableQuestionGeneration: payload.EnableQuestionGeneration,
 		QuestionCount:            payload.QuestionCount,
@@ -6635,6 +7025,7 @@ func (s *knowledgeService) ProcessFAQImport(ctx context.Context, t *asynq.Task)
 
 	// Dry run 模式：验证完成后直接返回结果
 	if payload.DryRun {
+		s.checkFAQImportConflicts(ctx, &payload, validEntryIndices, progress)
 		return s.finalizeFAQValidation(ctx, &payload, progress, originalTotalEntries)
 	}
 
@@ -6753,6 +7144,8 @@ func (s *knowledgeService) ProcessFAQImport(ctx context.Context, t *asynq.Task)
 	faqPayload := &types.FAQBatchUpsertPayload{
 		Entries: entriesToImport,
 		Mode:    importMode,
+		AsDraft: payload.AsDraft,
+		Editor:  payload.UserID,
 	}
 
 	// 执行FAQ导入（传入已处理的偏移量，用于进度计算）
@@ -7299,9 +7692,9 @@ func (s *knowledgeService) cloneFAQKnowledgeBase(
 			return err
 		}
 
-		// Update chunk status to indexed
+		// Update chunk status, unpublished entries were not indexed
 		for _, chunk := range newChunks {
-			chunk.Status = int(types.ChunkStatusIndexed)
+			chunk.Status = faqChunkStatus(chunk)
 		}
 		if err := s.chunkService.UpdateChunks(ctx, newChunks); err != nil {
 			logger.Warnf(ctx, "Failed to update FAQ chunks status: %v", err)
@@ -0,0 +1,380 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	werrors "github.com/Tencent/WeKnora/internal/errors"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+const (
+	// minHashSize is the number of hash functions of a MinHash signature
+	minHashSize = 128
+	// minHashShingleSize is the number of characters of a shingle. Character shingles work for
+	// languages without whitespace between words, like Chinese.
+	minHashShingleSize = 5
+	// minHashBandRows is the number of signature rows per LSH band used to find candidate pairs
+	minHashBandRows = 4
+)
+
+// minHashSeeds are the seeds of the MinHash hash functions
+var minHashSeeds = func() []uint64 {
+	seeds := make([]uint64, minHashSize)
+	for i := range seeds {
+		seeds[i] = splitMix64(uint64(i) + 1)
+	}
+	return seeds
+}()
+
+// splitMix64 is the SplitMix64 finalizer, used to derive independent hashes from one shingle hash
+func splitMix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+// minHashSignature computes the MinHash signature of the character shingles of a text. Case,
+// whitespace and punctuation are ignored. Returns nil for text without letters or digits.
+func minHashSignature(text string) types.MinHashSignature {
+	runes := make([]rune, 0, len(text))
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			runes = append(runes, unicode.ToLower(r))
+		}
+	}
+	if len(runes) == 0 {
+		return nil
+	}
+
+	signature := make(types.MinHashSignature, minHashSize)
+	for i := range signature {
+		signature[i] = math.MaxUint32
+	}
+	seen := make(map[uint64]struct{})
+	for start := 0; start == 0 || start+minHashShingleSize <= len(runes); start++ {
+		// FNV-1a over the runes of the shingle
+		h := uint64(14695981039346656037)
+		for _, r := range runes[start:min(start+minHashShingleSize, len(runes))] {
+			h ^= uint64(r)
+			h *= 1099511628211
+		}
+		if _, ok := seen[h]; ok {
+			continue
+		}
+		seen[h] = struct{}{}
+		for i, seed := range minHashSeeds {
+			if v := uint32(splitMix64(h^seed) >> 32); v < signature[i] {
+				signature[i] = v
+			}
+		}
+	}
+	return signature
+}
+
+// chunksSignature computes the MinHash signature of the text of a knowledge from its text chunks
+func chunksSignature(textChunks []*types.Chunk) types.MinHashSignature {
+	contents := make([]string, 0, len(textChunks))
+	for _, chunk := range textChunks {
+		contents = append(contents, chunk.Content)
+	}
+	return minHashSignature(strings.Join(contents, "\n"))
+}
+
+// detectDuplicate computes the content signature of a knowledge being processed and compares it with
+// the other knowledge of its knowledge base. In warn mode the most similar near-duplicate is recorded
+// on the knowledge, in block mode an error naming it is returned. Only the first ingestion is blocked:
+// reindexing or retrying knowledge that was processed before just records the near-duplicate.
+func (s *knowledgeService) detectDuplicate(ctx context.Context,
+	kb *types.KnowledgeBase, knowledge *types.Knowledge, textChunks []*types.Chunk,
+) error {
+	knowledge.DuplicateOf = ""
+	knowledge.DuplicateSimilarity = 0
+	if kb.Type == types.KnowledgeBaseTypeFAQ {
+		return nil
+	}
+	knowledge.ContentSignature = chunksSignature(textChunks)
+	cfg := kb.DuplicateDetectionConfig.WithDefaults()
+	if cfg.Mode == types.DuplicateDetectionOff || len(knowledge.ContentSignature) == 0 {
+		return nil
+	}
+
+	knowledgeList, err := s.repo.ListKnowledgeByKnowledgeBaseID(ctx, knowledge.TenantID, knowledge.KnowledgeBaseID)
+	if err != nil {
+		logger.Warnf(ctx, "Failed to list knowledge for duplicate detection, skipping: %v", err)
+		return nil
+	}
+	duplicate, similarity := findDuplicateOf(knowledge, knowledgeList, cfg.Threshold)
+	if duplicate == nil {
+		return nil
+	}
+	knowledge.DuplicateOf = duplicate.ID
+	knowledge.DuplicateSimilarity = similarity
+	logger.Infof(ctx, "Knowledge %s is a near-duplicate of %s, similarity %.2f",
+		knowledge.ID, duplicate.ID, knowledge.DuplicateSimilarity)
+
+	if cfg.Mode == types.DuplicateDetectionBlock && knowledge.ProcessedAt == nil {
+		name := duplicate.Title
+		if name == "" {
+			name = duplicate.FileName
+		}
+		return fmt.Errorf("与已有文档「%s」内容重复（相似度 %.0f%%）", name, knowledge.DuplicateSimilarity*100)
+	}
+	return nil
+}
+
+// findDuplicateOf returns the processed knowledge most similar to a knowledge from the threshold on.
+// The knowledge itself and the knowledge already accepted as its near-duplicates are skipped, so that
+// reprocessing an original does not find its own copies.
+func findDuplicateOf(knowledge *types.Knowledge, knowledgeList []*types.Knowledge,
+	threshold float64,
+) (*types.Knowledge, float64) {
+	var duplicate *types.Knowledge
+	best := 0.0
+	for _, other := range knowledgeList {
+		if other.ID == knowledge.ID || other.DuplicateOf == knowledge.ID ||
+			other.ParseStatus != types.ParseStatusCompleted {
+			continue
+		}
+		similarity := knowledge.ContentSignature.Similarity(other.ContentSignature)
+		if similarity >= threshold && similarity > best {
+			duplicate = other
+			best = similarity
+		}
+	}
+	return duplicate, best
+}
+
+// GetDuplicateReport groups the processed knowledge of a knowledge base into clusters of near-duplicates.
+// Signatures of knowledge processed before duplicate detection are computed from the stored chunks.
+func (s *knowledgeService) GetDuplicateReport(ctx context.Context,
+	kbID string, threshold float64,
+) (*types.DuplicateReport, error) {
+	if threshold < 0 || threshold > 1 {
+		return nil, werrors.NewBadRequestError("threshold must be between 0 and 1")
+	}
+	kb, err := s.kbService.GetKnowledgeBaseByID(ctx, kbID)
+	if err != nil {
+		return nil, err
+	}
+	if threshold == 0 {
+		threshold = kb.DuplicateDetectionConfig.WithDefaults().Threshold
+	}
+
+	tenantID := ctx.Value(types.TenantIDContextKey).(uint64)
+	knowledgeList, err := s.repo.ListKnowledgeByKnowledgeBaseID(ctx, tenantID, kbID)
+	if err != nil {
+		return nil, err
+	}
+	if kb.Type != types.KnowledgeBaseTypeFAQ {
+		s.backfillContentSignatures(ctx, knowledgeList)
+	}
+
+	return &types.DuplicateReport{
+		Threshold: threshold,
+		Clusters:  findDuplicateClusters(knowledgeList, threshold),
+	}, nil
+}
+
+// backfillContentSignatures computes and stores the missing signatures of processed knowledge
+func (s *knowledgeService) backfillContentSignatures(ctx context.Context, knowledgeList []*types.Knowledge) {
+	backfilled := 0
+	for _, knowledge := range knowledgeList {
+		if knowledge.ParseStatus != types.ParseStatusCompleted || len(knowledge.ContentSignature) > 0 {
+			continue
+		}
+		chunks, err := s.chunkService.ListChunksByKnowledgeID(ctx, knowledge.ID)
+		if err != nil {
+			logger.Warnf(ctx, "Failed to list chunks of knowledge %s for its signature: %v", knowledge.ID, err)
+			continue
+		}
+		signature := chunksSignature(textChunksOf(chunks))
+		if len(signature) == 0 {
+			continue
+		}
+		if err := s.repo.UpdateKnowledgeColumn(ctx, knowledge.ID, "content_signature", signature); err != nil {
+			logger.Warnf(ctx, "Failed to store signature of knowledge %s: %v", knowledge.ID, err)
+		}
+		knowledge.ContentSignature = signature
+		backfilled++
+	}
+	if backfilled > 0 {
+		logger.Infof(ctx, "Computed content signatures of %d knowledge", backfilled)
+	}
+}
+
+// DisableDuplicateKnowledge disables redundant copies in retrieval. Without knowledge IDs every
+// document of a duplicate cluster but the kept one is disabled. Returns the IDs of the disabled knowledge.
+func (s *knowledgeService) DisableDuplicateKnowledge(ctx context.Context,
+	kbID string, req *types.DuplicateDisableRequest,
+) ([]string, error) {
+	report, err := s.GetDuplicateReport(ctx, kbID, req.Threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	clustered := make(map[string]bool)
+	var targets []string
+	for _, cluster := range report.Clusters {
+		for _, item := range cluster.Knowledge {
+			clustered[item.ID] = true
+			if len(req.KnowledgeIDs) == 0 && item.ID != cluster.KeepID {
+				targets = append(targets, item.ID)
+			}
+		}
+	}
+	if len(req.KnowledgeIDs) > 0 {
+		for _, id := range req.KnowledgeIDs {
+			if !clustered[id] {
+				return nil, werrors.NewBadRequestError(fmt.Sprintf("knowledge %s is not in a duplicate cluster", id))
+			}
+		}
+		targets = req.KnowledgeIDs
+	}
+
+	tenantID := ctx.Value(types.TenantIDContextKey).(uint64)
+	knowledgeList, err := s.repo.GetKnowledgeBatch(ctx, tenantID, targets)
+	if err != nil {
+		return nil, err
+	}
+	disabled := make([]string, 0, len(knowledgeList))
+	for _, knowledge := range knowledgeList {
+		if knowledge.EnableStatus == types.KnowledgeEnableStatusDisabled {
+			continue
+		}
+		if err := s.setKnowledgeEnableStatus(ctx, knowledge, types.KnowledgeEnableStatusDisabled); err != nil {
+			return disabled, err
+		}
+		disabled = append(disabled, knowledge.ID)
+	}
+	logger.Infof(ctx, "Disabled %d duplicate knowledge in knowledge base %s", len(disabled), kbID)
+	return disabled, nil
+}
+
+// UpdateKnowledgeEnabled enables or disables a processed knowledge in retrieval. Knowledge enabled
+// outside its validity period stays disabled in retrieval as expired.
+func (s *knowledgeService) UpdateKnowledgeEnabled(ctx context.Context,
+	knowledgeID string, enabled bool,
+) (*types.Knowledge, error) {
+	tenantID := ctx.Value(types.TenantIDContextKey).(uint64)
+	knowledge, err := s.repo.GetKnowledgeByID(ctx, tenantID, knowledgeID)
+	if err != nil {
+		return nil, err
+	}
+	if knowledge.ParseStatus != types.ParseStatusCompleted {
+		return nil, werrors.NewBadRequestError("knowledge has not been processed yet")
+	}
+
+	status := types.KnowledgeEnableStatusDisabled
+	if enabled {
+		status = types.KnowledgeEnableStatusEnabled
+		if !knowledge.IsValidAt(time.Now()) {
+			status = types.KnowledgeEnableStatusExpired
+		}
+	}
+	if status == knowledge.EnableStatus {
+		return knowledge, nil
+	}
+	if err := s.setKnowledgeEnableStatus(ctx, knowledge, status); err != nil {
+		return nil, err
+	}
+	return knowledge, nil
+}
+
+// findDuplicateClusters groups processed knowledge whose signatures are at least threshold similar.
+// Candidate pairs are found with LSH banding over the signatures and verified, so pairs just above
+// a low threshold may be missed. The most recently created knowledge of a cluster is kept.
+func findDuplicateClusters(knowledgeList []*types.Knowledge, threshold float64) []*types.DuplicateCluster {
+	items := make([]*types.Knowledge, 0, len(knowledgeList))
+	for _, knowledge := range knowledgeList {
+		if knowledge.ParseStatus == types.ParseStatusCompleted && len(knowledge.ContentSignature) == minHashSize {
+			items = append(items, knowledge)
+		}
+	}
+
+	parent := make([]int, len(items))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	type bandKey struct {
+		band int
+		hash uint64
+	}
+	buckets := make(map[bandKey][]int)
+	for i, item := range items {
+		for band := 0; band*minHashBandRows < minHashSize; band++ {
+			h := uint64(14695981039346656037)
+			for _, v := range item.ContentSignature[band*minHashBandRows : (band+1)*minHashBandRows] {
+				h ^= uint64(v)
+				h *= 1099511628211
+			}
+			key := bandKey{band: band, hash: h}
+			buckets[key] = append(buckets[key], i)
+		}
+	}
+	checked := make(map[[2]int]bool)
+	for _, bucket := range buckets {
+		for a := 0; a < len(bucket); a++ {
+			for b := a + 1; b < len(bucket); b++ {
+				pair := [2]int{bucket[a], bucket[b]}
+				if checked[pair] {
+					continue
+				}
+				checked[pair] = true
+				if items[pair[0]].ContentSignature.Similarity(items[pair[1]].ContentSignature) >= threshold {
+					parent[find(pair[0])] = find(pair[1])
+				}
+			}
+		}
+	}
+
+	groups := make(map[int][]*types.Knowledge)
+	for i, item := range items {
+		root := find(i)
+		groups[root] = append(groups[root], item)
+	}
+	clusters := make([]*types.DuplicateCluster, 0)
+	for _, group := range groups {
+		if len(group) < 2 {
+			continue
+		}
+		sort.SliceStable(group, func(i, j int) bool {
+			return group[i].CreatedAt.After(group[j].CreatedAt)
+		})
+		keep := group[0]
+		cluster := &types.DuplicateCluster{KeepID: keep.ID}
+		for _, knowledge := range group {
+			cluster.Knowledge = append(cluster.Knowledge, &types.DuplicateKnowledge{
+				ID:           knowledge.ID,
+				Title:        knowledge.Title,
+				FileName:     knowledge.FileName,
+				EnableStatus: knowledge.EnableStatus,
+				CreatedAt:    knowledge.CreatedAt,
+				Similarity:   keep.ContentSignature.Similarity(knowledge.ContentSignature),
+			})
+		}
+		clusters = append(clusters, cluster)
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		if len(clusters[i].Knowledge) != len(clusters[j].Knowledge) {
+			return len(clusters[i].Knowledge) > len(clusters[j].Knowledge)
+		}
+		return clusters[i].Knowledge[0].CreatedAt.After(clusters[j].Knowledge[0].CreatedAt)
+	})
+	return clusters
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+const duplicateTestText = `WeKnora is a document understanding and retrieval framework. It parses documents
+into chunks, embeds them and retrieves the most relevant passages for a question. Hybrid search
+combines keyword and vector retrieval, and a reranker orders the candidates before the answer is
+generated. Knowledge bases can be shared between tenants and organized with tags.`
+
+func TestMinHashSimilarity(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    string
+		minSim  float64
+		maxSim  float64
+		wantNil bool
+	}{
+		{"identical", duplicateTestText, duplicateTestText, 1, 1, false},
+		{"case and whitespace", duplicateTestText, strings.ToUpper(strings.Join(strings.Fields(duplicateTestText), "  ")), 1, 1, false},
+		{"small edit", duplicateTestText, strings.Replace(duplicateTestText, "organized with tags", "grouped by tags", 1), 0.8, 1, false},
+		{"unrelated", duplicateTestText, "企业知识库支持上传文档、网页与手动录入的内容，并提供问答、摘要和知识图谱等功能。", 0, 0.1, false},
+		{"no letters", "   ,.;  ", duplicateTestText, 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := minHashSignature(tt.a)
+			if tt.wantNil {
+				if a != nil {
+					t.Fatalf("minHashSignature() = %v, want nil", a)
+				}
+				return
+			}
+			sim := a.Similarity(minHashSignature(tt.b))
+			if sim < tt.minSim || sim > tt.maxSim {
+				t.Errorf("Similarity() = %v, want in [%v, %v]", sim, tt.minSim, tt.maxSim)
+			}
+		})
+	}
+}
+
+func TestFindDuplicateClusters(t *testing.T) {
+	now := time.Now()
+	knowledge := func(id, text string, age int, status string) *types.Knowledge {
+		return &types.Knowledge{
+			ID:               id,
+			ParseStatus:      status,
+			CreatedAt:        now.Add(-time.Duration(age) * time.Hour),
+			ContentSignature: minHashSignature(text),
+		}
+	}
+	edited := strings.Replace(duplicateTestText, "organized with tags", "grouped by tags", 1)
+	other := "企业知识库支持上传文档、网页与手动录入的内容，并提供问答、摘要和知识图谱等功能。"
+
+	clusters := findDuplicateClusters([]*types.Knowledge{
+		knowledge("v1", duplicateTestText, 3, types.ParseStatusCompleted),
+		knowledge("v2", edited, 1, types.ParseStatusCompleted),
+		knowledge("v3", duplicateTestText, 2, types.ParseStatusCompleted),
+		knowledge("pending", duplicateTestText, 0, types.ParseStatusProcessing),
+		knowledge("other", other, 0, types.ParseStatusCompleted),
+	}, 0.8)
+
+	if len(clusters) != 1 {
+		t.Fatalf("got %d clusters, want 1", len(clusters))
+	}
+	cluster := clusters[0]
+	if cluster.KeepID != "v2" {
+		t.Errorf("KeepID = %s, want newest v2", cluster.KeepID)
+	}
+	var ids []string
+	for _, item := range cluster.Knowledge {
+		ids = append(ids, item.ID)
+	}
+	if got := strings.Join(ids, ","); got != "v2,v3,v1" {
+		t.Errorf("cluster knowledge = %s, want v2,v3,v1", got)
+	}
+	if cluster.Knowledge[0].Similarity != 1 {
+		t.Errorf("kept similarity = %v, want 1", cluster.Knowledge[0].Similarity)
+	}
+}
+
+func TestFindDuplicateOf(t *testing.T) {
+	signature := minHashSignature(duplicateTestText)
+	original := &types.Knowledge{ID: "original", ParseStatus: types.ParseStatusCompleted, ContentSignature: signature}
+	copied := &types.Knowledge{
+		ID: "copy", ParseStatus: types.ParseStatusCompleted, ContentSignature: signature, DuplicateOf: "original",
+	}
+	failed := &types.Knowledge{ID: "failed", ParseStatus: types.ParseStatusFailed, ContentSignature: signature}
+	list := []*types.Knowledge{original, copied, failed}
+
+	// Reindexing the original skips itself and the copy accepted as its duplicate
+	if duplicate, _ := findDuplicateOf(original, list, 0.8); duplicate != nil {
+		t.Errorf("original: got duplicate %s, want none", duplicate.ID)
+	}
+	// Reindexing the copy still finds the original
+	if duplicate, similarity := findDuplicateOf(copied, list, 0.8); duplicate != original || similarity != 1 {
+		t.Errorf("copy: got %v with similarity %v, want original with 1", duplicate, similarity)
+	}
+}
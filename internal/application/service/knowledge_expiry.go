@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/application/service/retriever"
+	werrors "github.com/Tencent/WeKnora/internal/errors"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/hibiken/asynq"
+)
+
+// defaultExpiringDays is the default window for listing knowledge that expires or is due for review
+const defaultExpiringDays = 30
+
+// UpdateKnowledgeValidity sets the validity period and review date of a knowledge, and disables or
+// enables it in retrieval right away when this moves it out of or into its validity period
+func (s *knowledgeService) UpdateKnowledgeValidity(ctx context.Context,
+	knowledgeID string, req *types.KnowledgeValidityRequest,
+) (*types.Knowledge, error) {
+	if err := req.Validate(); err != nil {
+		return nil, werrors.NewBadRequestError(err.Error())
+	}
+	tenantID := ctx.Value(types.TenantIDContextKey).(uint64)
+	knowledge, err := s.repo.GetKnowledgeByID(ctx, tenantID, knowledgeID)
+	if err != nil {
+		return nil, err
+	}
+
+	knowledge.ValidFrom = req.ValidFrom
+	knowledge.ValidUntil = req.ValidUntil
+	knowledge.ReviewBy = req.ReviewBy
+	knowledge.UpdatedAt = time.Now()
+	if err := s.repo.UpdateKnowledge(ctx, knowledge); err != nil {
+		return nil, err
+	}
+
+	valid := knowledge.IsValidAt(time.Now())
+	switch {
+	case !valid && knowledge.EnableStatus == types.KnowledgeEnableStatusEnabled &&
+		knowledge.ParseStatus == types.ParseStatusCompleted:
+		err = s.setKnowledgeEnableStatus(ctx, knowledge, types.KnowledgeEnableStatusExpired)
+	case valid && knowledge.EnableStatus == types.KnowledgeEnableStatusExpired:
+		err = s.setKnowledgeEnableStatus(ctx, knowledge, types.KnowledgeEnableStatusEnabled)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return knowledge, nil
+}
+
+// ListExpiringKnowledge lists the knowledge of a knowledge base that expires or is due for review
+// within the given number of days, including knowledge already expired or overdue
+func (s *knowledgeService) ListExpiringKnowledge(ctx context.Context,
+	kbID string, days int,
+) ([]*types.Knowledge, error) {
+	if days <= 0 {
+		days = defaultExpiringDays
+	}
+	tenantID := ctx.Value(types.TenantIDContextKey).(uint64)
+	before := time.Now().AddDate(0, 0, days)
+	return s.repo.ListExpiringKnowledge(ctx, tenantID, kbID, before)
+}
+
+// ProcessKnowledgeExpirySweep handles the scheduled expiry sweep. Knowledge outside its validity
+// period is disabled in all retrieval engines, expired knowledge that is valid again is enabled.
+func (s *knowledgeService) ProcessKnowledgeExpirySweep(ctx context.Context, t *asynq.Task) error {
+	now := time.Now()
+	toExpire, err := s.repo.ListKnowledgeToExpire(ctx, now)
+	if err != nil {
+		return fmt.Errorf("failed to list knowledge to expire: %w", err)
+	}
+	toRestore, err := s.repo.ListKnowledgeToRestore(ctx, now)
+	if err != nil {
+		return fmt.Errorf("failed to list knowledge to restore: %w", err)
+	}
+	if len(toExpire) == 0 && len(toRestore) == 0 {
+		return nil
+	}
+	logger.Infof(ctx, "Knowledge expiry sweep: %d to expire, %d to restore", len(toExpire), len(toRestore))
+
+	tenants := make(map[uint64]*types.Tenant)
+	sweep := func(knowledge *types.Knowledge, status string) {
+		tenantInfo, ok := tenants[knowledge.TenantID]
+		if !ok {
+			var err error
+			tenantInfo, err = s.tenantRepo.GetTenantByID(ctx, knowledge.TenantID)
+			if err != nil {
+				logger.Errorf(ctx, "Failed to get tenant %d: %v", knowledge.TenantID, err)
+			}
+			tenants[knowledge.TenantID] = tenantInfo
+		}
+		if tenantInfo == nil {
+			return
+		}
+		tenantCtx := context.WithValue(ctx, types.TenantIDContextKey, knowledge.TenantID)
+		tenantCtx = context.WithValue(tenantCtx, types.TenantInfoContextKey, tenantInfo)
+		if err := s.setKnowledgeEnableStatus(tenantCtx, knowledge, status); err != nil {
+			logger.Errorf(ctx, "Failed to update expiry of knowledge %s: %v", knowledge.ID, err)
+		}
+	}
+	for _, knowledge := range toExpire {
+		sweep(knowledge, types.KnowledgeEnableStatusExpired)
+	}
+	for _, knowledge := range toRestore {
+		sweep(knowledge, types.KnowledgeEnableStatusEnabled)
+	}
+	return nil
+}
+
+// setKnowledgeEnableStatus records the enable status of a knowledge and applies it to all retrieval
+// engines: an expired or disabled knowledge has all its chunks disabled, an enabled one gets the
+// chunks that are enabled back
+func (s *knowledgeService) setKnowledgeEnableStatus(ctx context.Context, knowledge *types.Knowledge, status string) error {
+	chunks, err := s.chunkService.ListChunksByKnowledgeID(ctx, knowledge.ID)
+	if err != nil {
+		return err
+	}
+	enabled := status == types.KnowledgeEnableStatusEnabled
+	// Child chunks of a disabled parent chunk stay out of retrieval
+	disabledParents := make(map[string]bool)
+	for _, chunk := range chunks {
+		if !chunk.IsEnabled {
+			disabledParents[chunk.ID] = true
+		}
+	}
+	chunkStatusMap := make(map[string]bool, len(chunks))
+	for _, chunk := range chunks {
+		chunkStatusMap[chunk.ID] = enabled && chunk.IsEnabled &&
+			!(chunk.ChunkType == types.ChunkTypeChild && disabledParents[chunk.ParentChunkID])
+	}
+
+	tenantInfo := ctx.Value(types.TenantInfoContextKey).(*types.Tenant)
+	retrieveEngine, err := retriever.NewCompositeRetrieveEngine(s.retrieveEngine, tenantInfo.GetEffectiveEngines())
+	if err != nil {
+		return err
+	}
+	if err := retrieveEngine.BatchUpdateChunkEnabledStatus(ctx, chunkStatusMap); err != nil {
+		return err
+	}
+
+	if err := s.repo.UpdateKnowledgeColumn(ctx, knowledge.ID, "enable_status", status); err != nil {
+		return err
+	}
+	knowledge.EnableStatus = status
+	logger.Infof(ctx, "Knowledge %s %s in retrieval, %d chunks updated", knowledge.ID, status, len(chunkStatusMap))
+	return nil
+}
+
+// disableExpiredIndex disables newly indexed chunks of knowledge outside its validity period,
+// since indexing always adds entries enabled
+func disableExpiredIndex(ctx context.Context,
+	retrieveEngine *retriever.CompositeRetrieveEngine, knowledge *types.Knowledge, chunkIDs []string,
+) error {
+	if knowledge.IsValidAt(time.Now()) || len(chunkIDs) == 0 {
+		return nil
+	}
+	chunkStatusMap := make(map[string]bool, len(chunkIDs))
+	for _, id := range chunkIDs {
+		chunkStatusMap[id] = false
+	}
+	return retrieveEngine.BatchUpdateChunkEnabledStatus(ctx, chunkStatusMap)
+}
+
+// applyExpiryDecay down-weights the results of knowledge close to expiry and sorts them again by score
+func (s *knowledgeBaseService) applyExpiryDecay(ctx context.Context,
+	cfg *types.ExpiryDecayConfig, results []*types.IndexWithScore,
+) []*types.IndexWithScore {
+	if !cfg.Enabled() || len(results) == 0 {
+		return results
+	}
+	knowledgeIDs := make([]string, 0, len(results))
+	seen := make(map[string]bool)
+	for _, result := range results {
+		if !seen[result.KnowledgeID] {
+			seen[result.KnowledgeID] = true
+			knowledgeIDs = append(knowledgeIDs, result.KnowledgeID)
+		}
+	}
+	tenantID := ctx.Value(types.TenantIDContextKey).(uint64)
+	knowledgeList, err := s.kgRepo.GetKnowledgeBatch(ctx, tenantID, knowledgeIDs)
+	if err != nil {
+		logger.Warnf(ctx, "Failed to load knowledge for expiry decay, keeping scores: %v", err)
+		return results
+	}
+	validUntil := make(map[string]*time.Time, len(knowledgeList))
+	for _, knowledge := range knowledgeList {
+		validUntil[knowledge.ID] = knowledge.ValidUntil
+	}
+
+	now := time.Now()
+	decayed := 0
+	for _, result := range results {
+		if weight := cfg.Weight(validUntil[result.KnowledgeID], now); weight < 1 {
+			result.Score *= weight
+			decayed++
+		}
+	}
+	if decayed > 0 {
+		sort.SliceStable(results, func(i, j int) bool {
+			return results[i].Score > results[j].Score
+		})
+		logger.Infof(ctx, "Down-weighted %d results of knowledge close to expiry", decayed)
+	}
+	return results
+}
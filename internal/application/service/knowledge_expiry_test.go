@@ -0,0 +1,66 @@
+package service
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+func TestKnowledgeIsValidAt(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	before, after := now.Add(-time.Hour), now.Add(time.Hour)
+
+	tests := []struct {
+		name       string
+		validFrom  *time.Time
+		validUntil *time.Time
+		want       bool
+	}{
+		{"no validity period", nil, nil, true},
+		{"within period", &before, &after, true},
+		{"not yet valid", &after, nil, false},
+		{"expired", nil, &before, false},
+		{"expires exactly now", nil, &now, false},
+		{"valid from exactly now", &now, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			knowledge := &types.Knowledge{ValidFrom: tt.validFrom, ValidUntil: tt.validUntil}
+			if got := knowledge.IsValidAt(now); got != tt.want {
+				t.Errorf("IsValidAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpiryDecayWeight(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	in := func(days float64) *time.Time {
+		t := now.Add(time.Duration(days * float64(24*time.Hour)))
+		return &t
+	}
+	cfg := &types.ExpiryDecayConfig{WindowDays: 10}
+
+	tests := []struct {
+		name       string
+		cfg        *types.ExpiryDecayConfig
+		validUntil *time.Time
+		want       float64
+	}{
+		{"disabled", nil, in(1), 1},
+		{"no expiry", cfg, nil, 1},
+		{"outside window", cfg, in(20), 1},
+		{"middle of window", cfg, in(5), 0.75},
+		{"expired", cfg, in(-1), 0.5},
+		{"custom min weight", &types.ExpiryDecayConfig{WindowDays: 10, MinWeight: 0.2}, in(0), 0.2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.Weight(tt.validUntil, now); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Weight() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
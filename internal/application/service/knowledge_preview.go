@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"strconv"
+
+	"github.com/Tencent/WeKnora/docreader/proto"
+	"github.com/Tencent/WeKnora/internal/chunker"
+	werrors "github.com/Tencent/WeKnora/internal/errors"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+// MaxChunkPreviewConfigs is the number of chunking configs one preview can compare side by side
+const MaxChunkPreviewConfigs = 2
+
+// Parsers reported by chunk previews
+const (
+	chunkPreviewParserNative    = "native"
+	chunkPreviewParserDocReader = "docreader"
+)
+
+// PreviewChunks splits an uploaded file with each chunking config the way ingestion would, without
+// creating knowledge, chunks or embeddings. Without configs the knowledge base config is previewed.
+// Images extracted by docreader are returned inline as data URLs and never uploaded to object storage.
+func (s *knowledgeService) PreviewChunks(ctx context.Context, kbID string, file *multipart.FileHeader,
+	configs []types.ChunkingConfig, enableMultimodel bool,
+) ([]*types.ChunkPreview, error) {
+	kb, err := s.kbService.GetKnowledgeBaseByID(ctx, kbID)
+	if err != nil {
+		logger.Errorf(ctx, "Failed to get knowledge base: %v", err)
+		return nil, err
+	}
+
+	if !isValidFileType(file.Filename) {
+		return nil, werrors.NewBadRequestError(ErrInvalidFileType.Error())
+	}
+	fileType := getFileType(file.Filename)
+	if IsImageType(fileType) && !enableMultimodel {
+		return nil, werrors.NewBadRequestError(ErrImageNotParse.Error())
+	}
+
+	if len(configs) == 0 {
+		configs = []types.ChunkingConfig{kb.ChunkingConfig}
+	}
+	if len(configs) > MaxChunkPreviewConfigs {
+		return nil, werrors.NewBadRequestError(fmt.Sprintf("at most %d chunking configs can be compared", MaxChunkPreviewConfigs))
+	}
+	for _, config := range configs {
+		if err := validatePreviewChunkingConfig(config); err != nil {
+			return nil, werrors.NewBadRequestError(err.Error())
+		}
+	}
+
+	reader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	var vlmConfig *proto.VLMConfig
+	if enableMultimodel {
+		if vlmConfig, err = s.getVLMProtoConfig(ctx, kb); err != nil {
+			logger.Warnf(ctx, "Failed to build VLM config for chunk preview: %v", err)
+		}
+	}
+	requestID, _ := ctx.Value(types.RequestIDContextKey).(string)
+	tokenizer := embeddingTokenizer(ctx, s.modelService, kb)
+
+	previews := make([]*types.ChunkPreview, 0, len(configs))
+	for _, config := range configs {
+		candidate := *kb
+		candidate.ChunkingConfig = config
+
+		var chunks []*proto.Chunk
+		parser := chunkPreviewParserNative
+		if canChunkNatively(fileType, content, enableMultimodel) {
+			chunks = s.chunkNatively(ctx, &candidate, string(content), fileType)
+		} else {
+			parser = chunkPreviewParserDocReader
+			readConfig := newReadConfig(&candidate, enableMultimodel, vlmConfig)
+			readConfig.Preview = true
+			resp, err := s.docReaderClient.ReadFromFile(ctx, &proto.ReadFromFileRequest{
+				FileContent: content,
+				FileName:    file.Filename,
+				FileType:    fileType,
+				ReadConfig:  readConfig,
+				RequestId:   requestID,
+			})
+			if err != nil {
+				logger.Errorf(ctx, "Failed to read file from docreader for chunk preview: %v", err)
+				return nil, fmt.Errorf("failed to read file from docreader: %w", err)
+			}
+			chunks = s.rechunkDocReaderChunks(ctx, &candidate, resp.Chunks, fileType)
+		}
+		previews = append(previews, newChunkPreview(config, parser, chunks, tokenizer))
+	}
+
+	logger.Infof(ctx, "Previewed chunking of %s with %d configs", file.Filename, len(previews))
+	return previews, nil
+}
+
+// validatePreviewChunkingConfig checks a candidate chunking config, which unlike a saved one has no defaults
+func validatePreviewChunkingConfig(config types.ChunkingConfig) error {
+	if config.ChunkSize <= 0 {
+		return fmt.Errorf("chunk_size must be positive")
+	}
+	if config.ChunkOverlap < 0 || config.ChunkOverlap >= config.ChunkSize {
+		return fmt.Errorf("chunk_overlap must be in [0, chunk_size)")
+	}
+	return config.Validate()
+}
+
+// newChunkPreview summarizes the chunks of one config, splitting them into children
+// the way parent-child indexing would. Tokens are counted with the tokenizer, estimated when it is nil.
+func newChunkPreview(config types.ChunkingConfig, parser string, chunks []*proto.Chunk,
+	tokenizer chunker.TokenCounter,
+) *types.ChunkPreview {
+	preview := &types.ChunkPreview{
+		ChunkingConfig: config,
+		Parser:         parser,
+		Chunks:         make([]*types.ChunkPreviewItem, 0, len(chunks)),
+	}
+
+	parents := make([]*types.Chunk, 0, len(chunks))
+	for i, chunk := range chunks {
+		item := &types.ChunkPreviewItem{
+			Seq:        int(chunk.Seq),
+			Content:    chunk.Content,
+			StartAt:    int(chunk.Start),
+			EndAt:      int(chunk.End),
+			TokenCount: tokenizer.Count(chunk.Content),
+		}
+		for _, img := range chunk.Images {
+			item.Images = append(item.Images, types.ImageInfo{
+				URL:         img.Url,
+				OriginalURL: img.OriginalUrl,
+				StartPos:    int(img.Start),
+				EndPos:      int(img.End),
+				OCRText:     img.OcrText,
+				Caption:     img.Caption,
+			})
+		}
+		preview.Chunks = append(preview.Chunks, item)
+		preview.ImageCount += len(item.Images)
+
+		preview.TotalTokens += item.TokenCount
+		if i == 0 || item.TokenCount < preview.MinTokens {
+			preview.MinTokens = item.TokenCount
+		}
+		preview.MaxTokens = max(preview.MaxTokens, item.TokenCount)
+
+		parents = append(parents, &types.Chunk{
+			ID:      strconv.Itoa(i),
+			Content: chunk.Content,
+			StartAt: item.StartAt,
+			EndAt:   item.EndAt,
+		})
+	}
+	preview.ChunkCount = len(preview.Chunks)
+	if preview.ChunkCount > 0 {
+		preview.AvgTokens = preview.TotalTokens / preview.ChunkCount
+	}
+
+	if config.EnableParentChild {
+		children, _ := buildChildChunks(config, tokenizer, parents)
+		for _, child := range children {
+			i, _ := strconv.Atoi(child.ParentChunkID)
+			parent := preview.Chunks[i]
+			parent.Children = append(parent.Children, &types.ChunkPreviewItem{
+				Seq:        parent.Seq,
+				Content:    child.Content,
+				StartAt:    child.StartAt,
+				EndAt:      child.EndAt,
+				TokenCount: tokenizer.Count(child.Content),
+			})
+		}
+		preview.ChildCount = len(children)
+	}
+	return preview
+}
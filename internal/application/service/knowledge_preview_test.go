@@ -0,0 +1,61 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Tencent/WeKnora/docreader/proto"
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+func TestNewChunkPreview(t *testing.T) {
+	chunks := []*proto.Chunk{
+		{Content: "hello world", Seq: 0, Start: 0, End: 11},
+		{Content: strings.Repeat("step one of the install.\n", 12), Seq: 1, Start: 11, End: 311,
+			Images: []*proto.Image{{Url: "https://example.com/a.png", Caption: "diagram"}}},
+	}
+
+	preview := newChunkPreview(types.ChunkingConfig{ChunkSize: 512}, chunkPreviewParserNative, chunks, nil)
+	if preview.ChunkCount != 2 || preview.ImageCount != 1 || preview.ChildCount != 0 {
+		t.Fatalf("got %d chunks, %d images, %d children", preview.ChunkCount, preview.ImageCount, preview.ChildCount)
+	}
+	if preview.MinTokens != 4 || preview.TotalTokens != preview.MinTokens+preview.MaxTokens {
+		t.Errorf("token stats min=%d max=%d total=%d", preview.MinTokens, preview.MaxTokens, preview.TotalTokens)
+	}
+	if preview.Chunks[1].Images[0].Caption != "diagram" {
+		t.Errorf("image not carried over: %+v", preview.Chunks[1].Images)
+	}
+
+	config := types.ChunkingConfig{ChunkSize: 512, Separators: []string{"\n"}, EnableParentChild: true}
+	preview = newChunkPreview(config, chunkPreviewParserNative, chunks, nil)
+	if len(preview.Chunks[0].Children) != 0 {
+		t.Errorf("short chunk should have no children")
+	}
+	children := preview.Chunks[1].Children
+	if len(children) < 2 || preview.ChildCount != len(children) {
+		t.Fatalf("got %d children, child count %d", len(children), preview.ChildCount)
+	}
+	if children[0].StartAt != 11 || children[0].Seq != 1 {
+		t.Errorf("child offsets should be relative to the document, got start %d", children[0].StartAt)
+	}
+}
+
+func TestValidatePreviewChunkingConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  types.ChunkingConfig
+		wantErr bool
+	}{
+		{"valid", types.ChunkingConfig{ChunkSize: 500, ChunkOverlap: 50}, false},
+		{"missing size", types.ChunkingConfig{ChunkOverlap: 50}, true},
+		{"overlap too large", types.ChunkingConfig{ChunkSize: 100, ChunkOverlap: 100}, true},
+		{"unknown strategy", types.ChunkingConfig{ChunkSize: 500, Strategy: "paragraph"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validatePreviewChunkingConfig(tt.config); (err != nil) != tt.wantErr {
+				t.Errorf("got error %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
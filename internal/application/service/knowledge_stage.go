@@ -0,0 +1,464 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Tencent/WeKnora/docreader/proto"
+	werrors "github.com/Tencent/WeKnora/internal/errors"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/hibiken/asynq"
+)
+
+// stageRun is an attempt of a knowledge processing stage. Recording it is best effort:
+// a failure to record never fails the stage itself.
+type stageRun struct {
+	repo        stageRecorder
+	knowledgeID string
+	stage       types.ProcessingStage
+	err         error
+}
+
+// stageRecorder is the part of the stage repository a stage run writes to
+type stageRecorder interface {
+	FinishStage(ctx context.Context, knowledgeID string, stage types.ProcessingStage, status string, errorMessage string) error
+}
+
+// startStage records a new attempt of a stage. totalItems is the number of tasks the stage fans out to.
+func (s *knowledgeService) startStage(ctx context.Context, tenantID uint64, kbID, knowledgeID string,
+	stage types.ProcessingStage, totalItems int,
+) *stageRun {
+	if err := s.stageRepo.StartStage(ctx, &types.KnowledgeStage{
+		TenantID:        tenantID,
+		KnowledgeBaseID: kbID,
+		KnowledgeID:     knowledgeID,
+		Stage:           stage,
+		TotalItems:      totalItems,
+	}); err != nil {
+		logger.Warnf(ctx, "Failed to record start of stage %s for knowledge %s: %v", stage, knowledgeID, err)
+	}
+	return &stageRun{repo: s.stageRepo, knowledgeID: knowledgeID, stage: stage}
+}
+
+// fail records the error of an attempt that ends without returning it, e.g. to avoid a task retry
+func (r *stageRun) fail(err error) {
+	r.err = err
+}
+
+// finish records the outcome of the attempt: failed with err, or with the error passed to fail, completed otherwise
+func (r *stageRun) finish(ctx context.Context, err error) {
+	if err == nil {
+		err = r.err
+	}
+	status, message := types.StageStatusCompleted, ""
+	if err != nil {
+		status, message = types.StageStatusFailed, err.Error()
+	}
+	if recordErr := r.repo.FinishStage(ctx, r.knowledgeID, r.stage, status, message); recordErr != nil {
+		logger.Warnf(ctx, "Failed to record end of stage %s for knowledge %s: %v", r.stage, r.knowledgeID, recordErr)
+	}
+}
+
+// markStagePending records a stage queued to run
+func (s *knowledgeService) markStagePending(ctx context.Context, tenantID uint64, kbID, knowledgeID string,
+	stage types.ProcessingStage,
+) {
+	if err := s.stageRepo.MarkStagePending(ctx, &types.KnowledgeStage{
+		TenantID:        tenantID,
+		KnowledgeBaseID: kbID,
+		KnowledgeID:     knowledgeID,
+		Stage:           stage,
+	}); err != nil {
+		logger.Warnf(ctx, "Failed to record stage %s as pending for knowledge %s: %v", stage, knowledgeID, err)
+	}
+}
+
+// ListKnowledgeStages lists the processing stages of a knowledge in pipeline order
+func (s *knowledgeService) ListKnowledgeStages(ctx context.Context, knowledgeID string) ([]*types.KnowledgeStage, error) {
+	tenantID := ctx.Value(types.TenantIDContextKey).(uint64)
+	if _, err := s.repo.GetKnowledgeByID(ctx, tenantID, knowledgeID); err != nil {
+		return nil, err
+	}
+	stages, err := s.stageRepo.ListStages(ctx, tenantID, knowledgeID)
+	if err != nil {
+		return nil, err
+	}
+	sortStages(stages)
+	return stages, nil
+}
+
+// sortStages orders stages the way the pipeline runs them
+func sortStages(stages []*types.KnowledgeStage) {
+	order := make(map[types.ProcessingStage]int, len(types.ProcessingStages))
+	for i, stage := range types.ProcessingStages {
+		order[stage] = i
+	}
+	sort.SliceStable(stages, func(i, j int) bool {
+		return order[stages[i].Stage] < order[stages[j].Stage]
+	})
+}
+
+// ListFailedKnowledge lists the knowledge of a knowledge base with failed processing stages
+func (s *knowledgeService) ListFailedKnowledge(ctx context.Context, kbID string) ([]*types.FailedKnowledge, error) {
+	tenantID := ctx.Value(types.TenantIDContextKey).(uint64)
+	stages, err := s.stageRepo.ListFailedStages(ctx, tenantID, kbID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(stages))
+	stagesByKnowledge := make(map[string][]*types.KnowledgeStage)
+	for _, stage := range stages {
+		if _, ok := stagesByKnowledge[stage.KnowledgeID]; !ok {
+			ids = append(ids, stage.KnowledgeID)
+		}
+		stagesByKnowledge[stage.KnowledgeID] = append(stagesByKnowledge[stage.KnowledgeID], stage)
+	}
+	if len(ids) == 0 {
+		return []*types.FailedKnowledge{}, nil
+	}
+
+	knowledgeList, err := s.repo.GetKnowledgeBatch(ctx, tenantID, ids)
+	if err != nil {
+		return nil, err
+	}
+	knowledgeByID := make(map[string]*types.Knowledge, len(knowledgeList))
+	for _, knowledge := range knowledgeList {
+		knowledgeByID[knowledge.ID] = knowledge
+	}
+
+	// Most recently failed first, skipping deleted knowledge
+	result := make([]*types.FailedKnowledge, 0, len(ids))
+	for _, id := range ids {
+		knowledge, ok := knowledgeByID[id]
+		if !ok {
+			continue
+		}
+		sortStages(stagesByKnowledge[id])
+		result = append(result, &types.FailedKnowledge{Knowledge: knowledge, Stages: stagesByKnowledge[id]})
+	}
+	return result, nil
+}
+
+// RetryKnowledgeStage re-runs a failed stage of a knowledge without re-running the stages before it
+func (s *knowledgeService) RetryKnowledgeStage(ctx context.Context, knowledgeID string, stage types.ProcessingStage) error {
+	if !stage.IsValid() {
+		return werrors.NewBadRequestError(fmt.Sprintf("unknown processing stage %q", stage))
+	}
+	tenantID := ctx.Value(types.TenantIDContextKey).(uint64)
+	knowledge, err := s.repo.GetKnowledgeByID(ctx, tenantID, knowledgeID)
+	if err != nil {
+		return err
+	}
+	stages, err := s.stageRepo.ListStages(ctx, tenantID, knowledgeID)
+	if err != nil {
+		return err
+	}
+	if !isStageFailed(knowledge, stages, stage) {
+		return werrors.NewBadRequestError(fmt.Sprintf("stage %s of knowledge %s has not failed", stage, knowledgeID))
+	}
+	kb, err := s.kbService.GetKnowledgeBaseByID(ctx, knowledge.KnowledgeBaseID)
+	if err != nil {
+		return err
+	}
+	return s.retryStage(ctx, kb, knowledge, stage)
+}
+
+// RetryFailedKnowledge retries the failed stages of the knowledge in a knowledge base
+func (s *knowledgeService) RetryFailedKnowledge(ctx context.Context,
+	kbID string, req *types.KnowledgeRetryRequest,
+) (*types.KnowledgeRetryResult, error) {
+	if req.Stage != "" && !req.Stage.IsValid() {
+		return nil, werrors.NewBadRequestError(fmt.Sprintf("unknown processing stage %q", req.Stage))
+	}
+	kb, err := s.kbService.GetKnowledgeBaseByID(ctx, kbID)
+	if err != nil {
+		return nil, err
+	}
+	failed, err := s.ListFailedKnowledge(ctx, kbID)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make(map[string]bool, len(req.KnowledgeIDs))
+	for _, id := range req.KnowledgeIDs {
+		selected[id] = true
+	}
+
+	result := &types.KnowledgeRetryResult{}
+	for _, item := range failed {
+		if len(selected) > 0 && !selected[item.Knowledge.ID] {
+			continue
+		}
+		// Retrying the earliest failed stage re-runs what depends on it
+		for _, stage := range item.Stages {
+			if req.Stage != "" && stage.Stage != req.Stage {
+				continue
+			}
+			if err := s.retryStage(ctx, kb, item.Knowledge, stage.Stage); err != nil {
+				if result.Errors == nil {
+					result.Errors = make(map[string]string)
+				}
+				result.Errors[item.Knowledge.ID] = err.Error()
+			} else {
+				result.Retried++
+			}
+			break
+		}
+	}
+	logger.Infof(ctx, "Retried %d failed stages in knowledge base %s", result.Retried, kbID)
+	return result, nil
+}
+
+// isStageFailed reports whether the stage failed in its last attempt. Knowledge that failed to parse
+// before stages were recorded only has its parse status.
+func isStageFailed(knowledge *types.Knowledge, stages []*types.KnowledgeStage, stage types.ProcessingStage) bool {
+	for _, record := range stages {
+		if record.Stage == stage {
+			return record.Status == types.StageStatusFailed
+		}
+	}
+	return stage == types.ProcessingStageParse && knowledge.ParseStatus == types.ParseStatusFailed
+}
+
+// retryStage enqueues the task that runs a stage again
+func (s *knowledgeService) retryStage(ctx context.Context,
+	kb *types.KnowledgeBase, knowledge *types.Knowledge, stage types.ProcessingStage,
+) error {
+	logger.Infof(ctx, "Retrying stage %s of knowledge %s", stage, knowledge.ID)
+	switch stage {
+	case types.ProcessingStageParse:
+		return s.retryParse(ctx, kb, knowledge)
+	case types.ProcessingStageIndex:
+		return s.enqueueReindexTask(ctx, kb, knowledge)
+	case types.ProcessingStageQuestionGeneration:
+		return s.enqueueQuestionGenerationTask(ctx, knowledge.KnowledgeBaseID, knowledge.ID, questionCountOf(kb))
+	case types.ProcessingStageSummaryGeneration:
+		return s.enqueueSummaryGenerationTask(ctx, knowledge.KnowledgeBaseID, knowledge.ID)
+	case types.ProcessingStageGraphExtraction:
+		chunks, err := s.chunkService.ListChunksByKnowledgeID(ctx, knowledge.ID)
+		if err != nil {
+			return err
+		}
+		// Chunks that were extracted before the failure would otherwise be added to the graph twice
+		namespace := types.NameSpace{KnowledgeBase: knowledge.KnowledgeBaseID, Knowledge: knowledge.ID}
+		if err := s.graphEngine.DelGraph(ctx, []types.NameSpace{namespace}); err != nil {
+			return err
+		}
+		return s.enqueueGraphExtraction(ctx, kb, knowledge, textChunksOf(chunks))
+	}
+	return werrors.NewBadRequestError(fmt.Sprintf("unknown processing stage %q", stage))
+}
+
+// questionCountOf returns the number of questions generated per chunk in a knowledge base
+func questionCountOf(kb *types.KnowledgeBase) int {
+	if kb.QuestionGenerationConfig != nil && kb.QuestionGenerationConfig.QuestionCount > 0 {
+		return min(kb.QuestionGenerationConfig.QuestionCount, 10)
+	}
+	return 3
+}
+
+// textChunksOf returns the text chunks in chunk order
+func textChunksOf(chunks []*types.Chunk) []*types.Chunk {
+	textChunks := make([]*types.Chunk, 0, len(chunks))
+	for _, chunk := range chunks {
+		if chunk.ChunkType == types.ChunkTypeText {
+			textChunks = append(textChunks, chunk)
+		}
+	}
+	sort.Slice(textChunks, func(i, j int) bool {
+		return textChunks[i].ChunkIndex < textChunks[j].ChunkIndex
+	})
+	return textChunks
+}
+
+// retryParse processes the knowledge again from its source, like a new upload
+func (s *knowledgeService) retryParse(ctx context.Context, kb *types.KnowledgeBase, knowledge *types.Knowledge) error {
+	if knowledge.IsManual() {
+		meta, err := knowledge.ManualMetadata()
+		if err != nil || meta == nil || strings.TrimSpace(meta.Content) == "" {
+			return werrors.NewBadRequestError("manual knowledge has no content to parse")
+		}
+		knowledge.ParseStatus = "processing"
+		knowledge.ErrorMessage = ""
+		knowledge.UpdatedAt = time.Now()
+		if err := s.repo.UpdateKnowledge(ctx, knowledge); err != nil {
+			return err
+		}
+		s.triggerManualProcessing(ctx, kb, knowledge, meta.Content, false)
+		return nil
+	}
+
+	requestID, _ := ctx.Value(types.RequestIDContextKey).(string)
+	payload := types.DocumentProcessPayload{
+		RequestId:                requestID,
+		TenantID:                 knowledge.TenantID,
+		KnowledgeID:              knowledge.ID,
+		KnowledgeBaseID:          knowledge.KnowledgeBaseID,
+		EnableMultimodel:         kb.IsMultimodalEnabled(),
+		EnableQuestionGeneration: kb.QuestionGenerationConfig != nil && kb.QuestionGenerationConfig.Enabled,
+		QuestionCount:            questionCountOf(kb),
+	}
+	switch {
+	case knowledge.FilePath != "":
+		payload.FilePath = knowledge.FilePath
+		payload.FileName = knowledge.FileName
+		payload.FileType = knowledge.FileType
+	case knowledge.Type == "url" && knowledge.Source != "":
+		payload.URL = knowledge.Source
+	default:
+		// Passages are not stored, their chunks can only be indexed again
+		return werrors.NewBadRequestError(fmt.Sprintf("%s knowledge cannot be parsed again, retry the index stage", knowledge.Type))
+	}
+
+	knowledge.ParseStatus = "pending"
+	knowledge.ErrorMessage = ""
+	knowledge.UpdatedAt = time.Now()
+	if err := s.repo.UpdateKnowledge(ctx, knowledge); err != nil {
+		return err
+	}
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	info, err := s.task.Enqueue(asynq.NewTask(types.TypeDocumentProcess, payloadBytes, asynq.Queue("default")))
+	if err != nil {
+		logger.Errorf(ctx, "Failed to enqueue document process task: %v", err)
+		return err
+	}
+	s.markStagePending(ctx, knowledge.TenantID, knowledge.KnowledgeBaseID, knowledge.ID, types.ProcessingStageParse)
+	logger.Infof(ctx, "Enqueued document process task: %s for knowledge: %s", info.ID, knowledge.ID)
+	return nil
+}
+
+// enqueueReindexTask enqueues indexing the stored chunks of a knowledge again
+func (s *knowledgeService) enqueueReindexTask(ctx context.Context, kb *types.KnowledgeBase, knowledge *types.Knowledge) error {
+	payload := types.KnowledgeReindexPayload{
+		TenantID:                 knowledge.TenantID,
+		KnowledgeBaseID:          knowledge.KnowledgeBaseID,
+		KnowledgeID:              knowledge.ID,
+		EnableQuestionGeneration: kb.QuestionGenerationConfig != nil && kb.QuestionGenerationConfig.Enabled,
+		QuestionCount:            questionCountOf(kb),
+	}
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	info, err := s.task.Enqueue(asynq.NewTask(types.TypeKnowledgeReindex, payloadBytes,
+		asynq.Queue("default"), asynq.MaxRetry(3)))
+	if err != nil {
+		logger.Errorf(ctx, "Failed to enqueue knowledge reindex task: %v", err)
+		return err
+	}
+	s.markStagePending(ctx, knowledge.TenantID, knowledge.KnowledgeBaseID, knowledge.ID, types.ProcessingStageIndex)
+	logger.Infof(ctx, "Enqueued knowledge reindex task: %s for knowledge: %s", info.ID, knowledge.ID)
+	return nil
+}
+
+// ProcessKnowledgeReindex handles Asynq tasks indexing the stored chunks of a knowledge again, without re-parsing
+func (s *knowledgeService) ProcessKnowledgeReindex(ctx context.Context, t *asynq.Task) error {
+	var payload types.KnowledgeReindexPayload
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		logger.Errorf(ctx, "Failed to unmarshal knowledge reindex payload: %v", err)
+		return nil
+	}
+	ctx = logger.WithField(ctx, "knowledge_reindex", payload.KnowledgeID)
+	ctx = context.WithValue(ctx, types.TenantIDContextKey, payload.TenantID)
+
+	tenantInfo, err := s.tenantRepo.GetTenantByID(ctx, payload.TenantID)
+	if err != nil {
+		logger.Errorf(ctx, "Failed to get tenant: %v", err)
+		return nil
+	}
+	ctx = context.WithValue(ctx, types.TenantInfoContextKey, tenantInfo)
+
+	knowledge, err := s.repo.GetKnowledgeByID(ctx, payload.TenantID, payload.KnowledgeID)
+	if err != nil || knowledge == nil || knowledge.ParseStatus == types.ParseStatusDeleting {
+		logger.Warnf(ctx, "Knowledge %s is gone or being deleted, skipping reindex", payload.KnowledgeID)
+		return nil
+	}
+	kb, err := s.kbService.GetKnowledgeBaseByID(ctx, payload.KnowledgeBaseID)
+	if err != nil {
+		logger.Errorf(ctx, "Failed to get knowledge base: %v", err)
+		return nil
+	}
+
+	chunks, err := s.chunkService.ListChunksByKnowledgeID(ctx, knowledge.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list chunks: %w", err)
+	}
+	textChunks := textChunksOf(chunks)
+	if len(textChunks) == 0 {
+		err := werrors.NewBadRequestError("no chunks stored to index, retry the parse stage")
+		s.startStage(ctx, knowledge.TenantID, knowledge.KnowledgeBaseID, knowledge.ID, types.ProcessingStageIndex, 0).
+			finish(ctx, err)
+		return nil
+	}
+
+	knowledge.ParseStatus = "processing"
+	knowledge.ErrorMessage = ""
+	knowledge.UpdatedAt = time.Now()
+	if err := s.repo.UpdateKnowledge(ctx, knowledge); err != nil {
+		return fmt.Errorf("failed to update knowledge: %w", err)
+	}
+
+	// processChunks replaces the stored chunks, so rebuild the parsed chunks they came from
+	s.processChunks(ctx, kb, knowledge, protoChunksOf(textChunks), ProcessChunksOptions{
+		EnableQuestionGeneration: payload.EnableQuestionGeneration,
+		QuestionCount:            payload.QuestionCount,
+	})
+	return nil
+}
+
+// protoChunksOf converts stored text chunks back into parsed chunks with their images
+func protoChunksOf(textChunks []*types.Chunk) []*proto.Chunk {
+	result := make([]*proto.Chunk, 0, len(textChunks))
+	for _, chunk := range textChunks {
+		parsed := &proto.Chunk{
+			Content: chunk.Content,
+			Seq:     int32(chunk.ChunkIndex),
+			Start:   int32(chunk.StartAt),
+			End:     int32(chunk.EndAt),
+		}
+		var images []types.ImageInfo
+		if strings.TrimSpace(chunk.ImageInfo) != "" && json.Unmarshal([]byte(chunk.ImageInfo), &images) == nil {
+			for _, img := range images {
+				parsed.Images = append(parsed.Images, &proto.Image{
+					Url:         img.URL,
+					OriginalUrl: img.OriginalURL,
+					Start:       int32(img.StartPos),
+					End:         int32(img.EndPos),
+					OcrText:     img.OCRText,
+					Caption:     img.Caption,
+				})
+			}
+		}
+		result = append(result, parsed)
+	}
+	return result
+}
+
+// enqueueGraphExtraction enqueues a graph extraction task per text chunk, tracked as one stage
+func (s *knowledgeService) enqueueGraphExtraction(ctx context.Context,
+	kb *types.KnowledgeBase, knowledge *types.Knowledge, textChunks []*types.Chunk,
+) error {
+	if kb.ExtractConfig == nil || !kb.ExtractConfig.Enabled || !isGraphExtractionAvailable() || len(textChunks) == 0 {
+		return nil
+	}
+	stage := s.startStage(ctx, knowledge.TenantID, knowledge.KnowledgeBaseID, knowledge.ID,
+		types.ProcessingStageGraphExtraction, len(textChunks))
+	for _, chunk := range textChunks {
+		if err := NewChunkExtractTask(ctx, s.task, chunk.TenantID, chunk.ID, kb.SummaryModelID); err != nil {
+			logger.GetLogger(ctx).WithField("error", err).Errorf("enqueue chunk extract task failed")
+			stage.finish(ctx, err)
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+func TestIsStageFailed(t *testing.T) {
+	stages := []*types.KnowledgeStage{
+		{Stage: types.ProcessingStageParse, Status: types.StageStatusCompleted},
+		{Stage: types.ProcessingStageIndex, Status: types.StageStatusFailed},
+	}
+	failedParse := &types.Knowledge{ParseStatus: types.ParseStatusFailed}
+	completed := &types.Knowledge{ParseStatus: types.ParseStatusCompleted}
+
+	tests := []struct {
+		name      string
+		knowledge *types.Knowledge
+		stages    []*types.KnowledgeStage
+		stage     types.ProcessingStage
+		want      bool
+	}{
+		{"failed stage", completed, stages, types.ProcessingStageIndex, true},
+		{"completed stage", completed, stages, types.ProcessingStageParse, false},
+		{"stage never ran", completed, stages, types.ProcessingStageSummaryGeneration, false},
+		{"recorded stage wins over parse status", failedParse, stages, types.ProcessingStageParse, false},
+		{"parse failed before stages were recorded", failedParse, nil, types.ProcessingStageParse, true},
+		{"only parse falls back to parse status", failedParse, nil, types.ProcessingStageIndex, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isStageFailed(tt.knowledge, tt.stages, tt.stage); got != tt.want {
+				t.Errorf("isStageFailed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProtoChunksOf(t *testing.T) {
+	chunks := textChunksOf([]*types.Chunk{
+		{ChunkType: types.ChunkTypeText, ChunkIndex: 1, Content: "second", StartAt: 6, EndAt: 12,
+			ImageInfo: `[{"url":"https://example.com/a.png","caption":"diagram","start_pos":8,"end_pos":10}]`},
+		{ChunkType: types.ChunkTypeImageCaption, ChunkIndex: 2, Content: "diagram"},
+		{ChunkType: types.ChunkTypeText, ChunkIndex: 0, Content: "first", StartAt: 0, EndAt: 6},
+	})
+
+	parsed := protoChunksOf(chunks)
+	if len(parsed) != 2 {
+		t.Fatalf("got %d chunks, want the 2 text chunks", len(parsed))
+	}
+	if parsed[0].Content != "first" || parsed[1].Seq != 1 || parsed[1].Start != 6 || parsed[1].End != 12 {
+		t.Errorf("chunks not rebuilt in order: %+v", parsed)
+	}
+	if len(parsed[1].Images) != 1 || parsed[1].Images[0].Caption != "diagram" || parsed[1].Images[0].Start != 8 {
+		t.Errorf("images not rebuilt: %+v", parsed[1].Images)
+	}
+}
@@ -9,8 +9,10 @@ import (
 	"time"
 
 	"github.com/Tencent/WeKnora/internal/application/service/retriever"
+	werrors "github.com/Tencent/WeKnora/internal/errors"
 	"github.com/Tencent/WeKnora/internal/logger"
 	"github.com/Tencent/WeKnora/internal/models/embedding"
+	"github.com/Tencent/WeKnora/internal/searchutil"
 	"github.com/Tencent/WeKnora/internal/types"
 	"github.com/Tencent/WeKnora/internal/types/interfaces"
 	"github.com/google/uuid"
@@ -79,6 +81,24 @@ func (s *knowledgeBaseService) CreateKnowledgeBase(ctx context.Context,
 	kb.TenantID = ctx.Value(types.TenantIDContextKey).(uint64)
 	kb.UpdatedAt = time.Now()
 	kb.EnsureDefaults()
+	if err := kb.FAQConfig.Validate(); err != nil {
+		return nil, werrors.NewBadRequestError(err.Error())
+	}
+	if err := kb.FusionConfig.Validate(); err != nil {
+		return nil, werrors.NewBadRequestError(err.Error())
+	}
+	if err := kb.ExpiryDecayConfig.Validate(); err != nil {
+		return nil, werrors.NewBadRequestError(err.Error())
+	}
+	if err := kb.DuplicateDetectionConfig.Validate(); err != nil {
+		return nil, werrors.NewBadRequestError(err.Error())
+	}
+	if err := kb.MultilingualConfig.Validate(); err != nil {
+		return nil, werrors.NewBadRequestError(err.Error())
+	}
+	if err := kb.ChunkingConfig.Validate(); err != nil {
+		return nil, werrors.NewBadRequestError(err.Error())
+	}
 
 	logger.Infof(ctx, "Creating knowledge base, ID: %s, tenant ID: %d, name: %s", kb.ID, kb.TenantID, kb.Name)
 
@@ -192,6 +212,10 @@ func (s *knowledgeBaseService) UpdateKnowledgeBase(ctx context.Context,
 		return nil, err
 	}
 
+	if err := config.ChunkingConfig.Validate(); err != nil {
+		return nil, werrors.NewBadRequestError(err.Error())
+	}
+
 	// Update the knowledge base properties
 	kb.Name = name
 	kb.Description = description
@@ -199,8 +223,39 @@ func (s *knowledgeBaseService) UpdateKnowledgeBase(ctx context.Context,
 	kb.ImageProcessingConfig = config.ImageProcessingConfig
 	// Update FAQ config if provided
 	if config.FAQConfig != nil {
+		if err := config.FAQConfig.Validate(); err != nil {
+			return nil, werrors.NewBadRequestError(err.Error())
+		}
 		kb.FAQConfig = config.FAQConfig
 	}
+	// Update fusion config if provided
+	if config.FusionConfig != nil {
+		if err := config.FusionConfig.Validate(); err != nil {
+			return nil, werrors.NewBadRequestError(err.Error())
+		}
+		kb.FusionConfig = config.FusionConfig
+	}
+	// Update expiry decay config if provided
+	if config.ExpiryDecayConfig != nil {
+		if err := config.ExpiryDecayConfig.Validate(); err != nil {
+			return nil, werrors.NewBadRequestError(err.Error())
+		}
+		kb.ExpiryDecayConfig = config.ExpiryDecayConfig
+	}
+	// Update duplicate detection config if provided
+	if config.DuplicateDetectionConfig != nil {
+		if err := config.DuplicateDetectionConfig.Validate(); err != nil {
+			return nil, werrors.NewBadRequestError(err.Error())
+		}
+		kb.DuplicateDetectionConfig = config.DuplicateDetectionConfig
+	}
+	// Update multilingual config if provided
+	if config.MultilingualConfig != nil {
+		if err := config.MultilingualConfig.Validate(); err != nil {
+			return nil, werrors.NewBadRequestError(err.Error())
+		}
+		kb.MultilingualConfig = config.MultilingualConfig
+	}
 	kb.UpdatedAt = time.Now()
 	kb.EnsureDefaults()
 
@@ -490,6 +545,13 @@ func (s *knowledgeBaseService) HybridSearch(ctx context.Context,
 ) ([]*types.SearchResult, error) {
 	logger.Infof(ctx, "Hybrid search parameters, knowledge base ID: %s, query text: %s", id, params.QueryText)
 
+	metadataFilters, err := params.MetadataFilters.Normalize()
+	if err != nil {
+		logger.Errorf(ctx, "Invalid metadata filters: %v", err)
+		return nil, werrors.NewBadRequestError(err.Error())
+	}
+	params.MetadataFilters = metadataFilters
+
 	tenantInfo := ctx.Value(types.TenantInfoContextKey).(*types.Tenant)
 
 	// Create a composite retrieval engine with tenant's configured retrievers
@@ -511,6 +573,17 @@ func (s *knowledgeBaseService) HybridSearch(ctx context.Context,
 		return nil, err
 	}
 
+	// The request fusion config overrides the knowledge base one
+	fusionConfig := kb.FusionConfig.WithDefaults()
+	if params.Fusion != nil {
+		if err := params.Fusion.Validate(); err != nil {
+			logger.Errorf(ctx, "Invalid fusion config: %v", err)
+			return nil, werrors.NewBadRequestError(err.Error())
+		}
+		fusionConfig = params.Fusion.WithDefaults()
+	}
+	logger.Infof(ctx, "Fusion config: %+v", fusionConfig)
+
 	matchCount := params.MatchCount * 3
 
 	// Add vector retrieval params if supported
@@ -543,6 +616,7 @@ func (s *knowledgeBaseService) HybridSearch(ctx context.Context,
 			RetrieverType:    types.VectorRetrieverType,
 			KnowledgeIDs:     params.KnowledgeIDs,
 			TagIDs:           params.TagIDs,
+			MetadataFilters:  params.MetadataFilters,
 		}
 
 		// For FAQ knowledge base, use FAQ index
@@ -559,13 +633,14 @@ func (s *knowledgeBaseService) HybridSearch(ctx context.Context,
 		kb.Type != types.KnowledgeBaseTypeFAQ {
 		logger.Info(ctx, "Keyword retrieval supported, preparing keyword retrieval parameters")
 		retrieveParams = append(retrieveParams, types.RetrieveParams{
-			Query:            params.QueryText,
+			Query:            searchutil.PrepareKeywordQuery(params.QueryText),
 			KnowledgeBaseIDs: []string{id},
 			TopK:             matchCount,
 			Threshold:        params.KeywordThreshold,
 			RetrieverType:    types.KeywordsRetrieverType,
 			KnowledgeIDs:     params.KnowledgeIDs,
 			TagIDs:           params.TagIDs,
+			MetadataFilters:  params.MetadataFilters,
 		})
 		logger.Info(ctx, "Keyword retrieval parameters setup completed")
 	}
@@ -577,7 +652,7 @@ func (s *knowledgeBaseService) HybridSearch(ctx context.Context,
 
 	// Execute retrieval using the configured engines
 	logger.Infof(ctx, "Starting retrieval, parameter count: %d", len(retrieveParams))
-	retrieveResults, err := retrieveEngine.Retrieve(ctx, retrieveParams)
+	retrieveResults, err := s.retrieveForFusion(ctx, retrieveEngine, retrieveParams, fusionConfig)
 	if err != nil {
 		logger.ErrorWithFields(ctx, err, map[string]interface{}{
 			"knowledge_base_id": id,
@@ -589,7 +664,7 @@ func (s *knowledgeBaseService) HybridSearch(ctx context.Context,
 	// Collect all results from different retrievers and deduplicate by chunk ID
 	logger.Infof(ctx, "Processing retrieval results")
 
-	// Separate results by retriever type for RRF fusion
+	// Separate results by retriever type for fusion
 	var vectorResults []*types.IndexWithScore
 	var keywordResults []*types.IndexWithScore
 	for _, retrieveResult := range retrieveResults {
@@ -612,111 +687,22 @@ func (s *knowledgeBaseService) HybridSearch(ctx context.Context,
 	}
 	logger.Infof(ctx, "Result count before fusion: vector=%d, keyword=%d", len(vectorResults), len(keywordResults))
 
-	var deduplicatedChunks []*types.IndexWithScore
-
-	// If only vector results (no keyword results), keep original embedding scores
-	// This is important for FAQ search which only uses vector retrieval
-	if len(keywordResults) == 0 {
-		logger.Info(ctx, "Only vector results, keeping original embedding scores")
-		chunkInfoMap := make(map[string]*types.IndexWithScore)
-		for _, r := range vectorResults {
-			// Keep the highest score for each chunk (FAQ may have multiple similar questions)
-			if existing, exists := chunkInfoMap[r.ChunkID]; !exists || r.Score > existing.Score {
-				chunkInfoMap[r.ChunkID] = r
-			}
-		}
-		deduplicatedChunks = make([]*types.IndexWithScore, 0, len(chunkInfoMap))
-		for _, info := range chunkInfoMap {
-			deduplicatedChunks = append(deduplicatedChunks, info)
-		}
-		slices.SortFunc(deduplicatedChunks, func(a, b *types.IndexWithScore) int {
-			if a.Score > b.Score {
-				return -1
-			} else if a.Score < b.Score {
-				return 1
-			}
-			return 0
-		})
-		logger.Infof(ctx, "Result count after deduplication: %d", len(deduplicatedChunks))
-	} else {
-		// Use RRF (Reciprocal Rank Fusion) to merge results from multiple retrievers
-		// RRF score = sum(1 / (k + rank)) for each retriever where the chunk appears
-		// k=60 is a common choice that works well in practice
-		const rrfK = 60
-
-		// Build rank maps for each retriever (already sorted by score from retriever)
-		vectorRanks := make(map[string]int)
-		for i, r := range vectorResults {
-			if _, exists := vectorRanks[r.ChunkID]; !exists {
-				vectorRanks[r.ChunkID] = i + 1 // 1-indexed rank
-			}
-		}
-		keywordRanks := make(map[string]int)
-		for i, r := range keywordResults {
-			if _, exists := keywordRanks[r.ChunkID]; !exists {
-				keywordRanks[r.ChunkID] = i + 1 // 1-indexed rank
-			}
-		}
-
-		// Collect all unique chunks and compute RRF scores
-		// Keep the highest score for each chunk from each retriever
-		chunkInfoMap := make(map[string]*types.IndexWithScore)
-		rrfScores := make(map[string]float64)
-
-		// Process vector results - keep highest score per chunk
-		for _, r := range vectorResults {
-			if existing, exists := chunkInfoMap[r.ChunkID]; !exists || r.Score > existing.Score {
-				chunkInfoMap[r.ChunkID] = r
-			}
-		}
-		// Process keyword results - only add if not already from vector
-		for _, r := range keywordResults {
-			if _, exists := chunkInfoMap[r.ChunkID]; !exists {
-				chunkInfoMap[r.ChunkID] = r
-			}
-		}
+	deduplicatedChunks := fuseHybridResults(vectorResults, keywordResults, fusionConfig)
+	logger.Infof(ctx, "Result count after %s fusion: %d", fusionConfig.Strategy, len(deduplicatedChunks))
 
-		// Compute RRF scores
-		for chunkID := range chunkInfoMap {
-			rrfScore := 0.0
-			if rank, ok := vectorRanks[chunkID]; ok {
-				rrfScore += 1.0 / float64(rrfK+rank)
-			}
-			if rank, ok := keywordRanks[chunkID]; ok {
-				rrfScore += 1.0 / float64(rrfK+rank)
-			}
-			rrfScores[chunkID] = rrfScore
-		}
-
-		// Convert to slice and sort by RRF score
-		deduplicatedChunks = make([]*types.IndexWithScore, 0, len(chunkInfoMap))
-		for chunkID, info := range chunkInfoMap {
-			// Store RRF score in the Score field for downstream processing
-			info.Score = rrfScores[chunkID]
-			deduplicatedChunks = append(deduplicatedChunks, info)
-		}
-		slices.SortFunc(deduplicatedChunks, func(a, b *types.IndexWithScore) int {
-			if a.Score > b.Score {
-				return -1
-			} else if a.Score < b.Score {
-				return 1
-			}
-			return 0
-		})
-
-		logger.Infof(ctx, "Result count after RRF fusion: %d", len(deduplicatedChunks))
-
-		// Log top results after RRF fusion for debugging
-		for i, chunk := range deduplicatedChunks {
-			if i < 15 {
-				vRank, vOk := vectorRanks[chunk.ChunkID]
-				kRank, kOk := keywordRanks[chunk.ChunkID]
-				logger.Debugf(ctx, "RRF rank %d: chunk_id=%s, rrf_score=%.6f, vector_rank=%v(%v), keyword_rank=%v(%v)",
-					i, chunk.ChunkID, chunk.Score, vRank, vOk, kRank, kOk)
-			}
+	// Log top results after fusion for debugging
+	for i, chunk := range deduplicatedChunks {
+		if i >= 15 {
+			break
 		}
+		logger.Debugf(ctx, "Fusion rank %d: chunk_id=%s, score=%.6f, vector_rank=%d(%.4f), keyword_rank=%d(%.4f)",
+			i, chunk.ChunkID, chunk.Score,
+			chunk.ScoreDetails.VectorRank, chunk.ScoreDetails.VectorScore,
+			chunk.ScoreDetails.KeywordRank, chunk.ScoreDetails.KeywordScore)
 	}
 
+	deduplicatedChunks = s.applyExpiryDecay(ctx, kb.ExpiryDecayConfig, deduplicatedChunks)
+
 	kb.EnsureDefaults()
 
 	// Check if we need iterative retrieval for FAQ with separate indexing
@@ -747,6 +733,51 @@ func (s *knowledgeBaseService) HybridSearch(ctx context.Context,
 	return s.processSearchResults(ctx, deduplicatedChunks)
 }
 
+// retrieveForFusion executes the retrieval params. With the vector fallback strategy keyword
+// retrieval only runs when vector retrieval returns fewer unique chunks than configured.
+func (s *knowledgeBaseService) retrieveForFusion(ctx context.Context,
+	retrieveEngine *retriever.CompositeRetrieveEngine,
+	retrieveParams []types.RetrieveParams,
+	fusionConfig types.FusionConfig,
+) ([]*types.RetrieveResult, error) {
+	if fusionConfig.Strategy != types.FusionStrategyVectorFallback {
+		return retrieveEngine.Retrieve(ctx, retrieveParams)
+	}
+
+	var vectorParams, keywordParams []types.RetrieveParams
+	for _, param := range retrieveParams {
+		if param.RetrieverType == types.VectorRetrieverType {
+			vectorParams = append(vectorParams, param)
+		} else {
+			keywordParams = append(keywordParams, param)
+		}
+	}
+	if len(vectorParams) == 0 || len(keywordParams) == 0 {
+		return retrieveEngine.Retrieve(ctx, retrieveParams)
+	}
+
+	retrieveResults, err := retrieveEngine.Retrieve(ctx, vectorParams)
+	if err != nil {
+		return nil, err
+	}
+	vectorChunks := make(map[string]struct{})
+	for _, retrieveResult := range retrieveResults {
+		for _, r := range retrieveResult.Results {
+			vectorChunks[r.ChunkID] = struct{}{}
+		}
+	}
+	if len(vectorChunks) >= fusionConfig.FallbackMinResults {
+		return retrieveResults, nil
+	}
+
+	logger.Infof(ctx, "Vector retrieval returned %d chunks, falling back to keyword retrieval", len(vectorChunks))
+	keywordResults, err := retrieveEngine.Retrieve(ctx, keywordParams)
+	if err != nil {
+		return nil, err
+	}
+	return append(retrieveResults, keywordResults...), nil
+}
+
 // iterativeRetrieveWithDeduplication performs iterative retrieval until enough unique chunks are found
 // This is used for FAQ knowledge bases with separate indexing mode
 // Negative question filtering is applied after each iteration with chunk data caching
@@ -999,6 +1030,7 @@ func (s *knowledgeBaseService) processSearchResults(ctx context.Context,
 	chunkScores := make(map[string]float64)
 	chunkMatchTypes := make(map[string]types.MatchType)
 	chunkMatchedContents := make(map[string]string)
+	chunkScoreDetails := make(map[string]*types.ScoreDetails)
 	processedKnowledgeIDs := make(map[string]bool)
 
 	// Collect all knowledge and chunk IDs
@@ -1012,6 +1044,7 @@ func (s *knowledgeBaseService) processSearchResults(ctx context.Context,
 		chunkScores[chunk.ChunkID] = chunk.Score
 		chunkMatchTypes[chunk.ChunkID] = chunk.MatchType
 		chunkMatchedContents[chunk.ChunkID] = chunk.Content
+		chunkScoreDetails[chunk.ChunkID] = chunk.ScoreDetails
 	}
 
 	// Batch fetch knowledge data
@@ -1043,6 +1076,22 @@ func (s *knowledgeBaseService) processSearchResults(ctx context.Context,
 		chunkMap[chunk.ID] = chunk
 		processedChunkIDs[chunk.ID] = true
 
+		// Small-to-big: a matched child is replaced by its parent, scored by its best matched child
+		if chunk.ChunkType == types.ChunkTypeChild {
+			parentID := chunk.ParentChunkID
+			if !processedChunkIDs[parentID] {
+				additionalChunkIDs = append(additionalChunkIDs, parentID)
+				processedChunkIDs[parentID] = true
+			}
+			if parentScore, ok := chunkScores[parentID]; !ok || chunkScores[chunk.ID] > parentScore {
+				chunkScores[parentID] = chunkScores[chunk.ID]
+				chunkMatchTypes[parentID] = types.MatchTypeSmallToBig
+				chunkMatchedContents[parentID] = chunk.Content
+				chunkScoreDetails[parentID] = chunkScoreDetails[chunk.ID]
+			}
+			continue
+		}
+
 		// Collect parent chunks
 		if chunk.ParentChunkID != "" && !processedChunkIDs[chunk.ParentChunkID] {
 			additionalChunkIDs = append(additionalChunkIDs, chunk.ParentChunkID)
@@ -1101,6 +1150,14 @@ func (s *knowledgeBaseService) processSearchResults(ctx context.Context,
 			logger.Debugf(ctx, "Chunk not found in chunkMap: %s", inputChunk.ChunkID)
 			continue
 		}
+		if chunk.ChunkType == types.ChunkTypeChild {
+			parent, ok := chunkMap[chunk.ParentChunkID]
+			if !ok {
+				logger.Debugf(ctx, "Parent chunk not found for child chunk: %s", chunk.ID)
+				continue
+			}
+			chunk = parent
+		}
 		if !s.isValidTextChunk(chunk) {
 			logger.Debugf(ctx, "Chunk is not valid text chunk: %s, type: %s", chunk.ID, chunk.ChunkType)
 			continue
@@ -1113,7 +1170,9 @@ func (s *knowledgeBaseService) processSearchResults(ctx context.Context,
 		if knowledge, ok := knowledgeMap[chunk.KnowledgeID]; ok {
 			matchType := chunkMatchTypes[chunk.ID]
 			matchedContent := chunkMatchedContents[chunk.ID]
-			searchResults = append(searchResults, s.buildSearchResult(chunk, knowledge, score, matchType, matchedContent))
+			searchResult := s.buildSearchResult(chunk, knowledge, score, matchType, matchedContent)
+			searchResult.ScoreDetails = chunkScoreDetails[chunk.ID]
+			searchResults = append(searchResults, searchResult)
 			addedChunkIDs[chunk.ID] = true
 		} else {
 			logger.Warnf(ctx, "Knowledge not found for chunk: %s, knowledge_id: %s", chunk.ID, chunk.KnowledgeID)
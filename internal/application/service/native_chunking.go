@@ -0,0 +1,200 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Tencent/WeKnora/docreader/proto"
+	"github.com/Tencent/WeKnora/internal/chunker"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// canChunkNatively reports whether a document can be split in Go instead of docreader.
+// Only plain text and Markdown qualify, and not when images must be extracted: multimodal
+// processing and base64 embedded images need docreader.
+func canChunkNatively(fileType string, content []byte, enableMultimodal bool) bool {
+	return chunker.IsNativeFileType(fileType) && !enableMultimodal && utf8.Valid(content) &&
+		!bytes.Contains(content, []byte("data:image/"))
+}
+
+// chunkNatively splits plain text or Markdown with the knowledge base chunking config and strategy.
+// The semantic strategy falls back to sentence chunking when the sentences cannot be embedded.
+func (s *knowledgeService) chunkNatively(ctx context.Context,
+	kb *types.KnowledgeBase, content string, fileType string,
+) []*proto.Chunk {
+	cfg := chunkerConfig(kb.ChunkingConfig, kb.ChunkingConfig.ChunkSize, kb.ChunkingConfig.ChunkOverlap,
+		embeddingTokenizer(ctx, s.modelService, kb))
+	cfg.Markdown = chunker.IsMarkdownFileType(fileType)
+
+	var chunks []chunker.Chunk
+	if kb.ChunkingConfig.GetStrategy() == types.ChunkingStrategySemantic {
+		var err error
+		chunks, err = chunker.SplitSemantic(ctx, content, cfg, kb.ChunkingConfig.SemanticThreshold,
+			func(ctx context.Context, texts []string) ([][]float32, error) {
+				embeddingModel, err := s.modelService.GetEmbeddingModel(ctx, kb.EmbeddingModelID)
+				if err != nil {
+					return nil, err
+				}
+				return embeddingModel.BatchEmbedWithPool(ctx, embeddingModel, texts)
+			})
+		if err != nil {
+			logger.Warnf(ctx, "Semantic chunking failed, falling back to sentence chunking: %v", err)
+			chunks = chunker.Split(content, cfg)
+		}
+	} else {
+		chunks = chunker.Split(content, cfg)
+	}
+
+	result := make([]*proto.Chunk, 0, len(chunks))
+	for _, chunk := range chunks {
+		result = append(result, &proto.Chunk{
+			Content: chunk.Content,
+			Seq:     int32(chunk.Seq),
+			Start:   int32(chunk.Start),
+			End:     int32(chunk.End),
+		})
+	}
+	logger.Infof(ctx, "Split %s content natively into %d chunks, strategy: %s", fileType, len(result), kb.ChunkingConfig.GetStrategy())
+	return result
+}
+
+// rechunkDocReaderChunks splits a document parsed by docreader again with the chunking strategy of the
+// knowledge base, since docreader only splits by characters. The parsed text is restored from the chunk
+// offsets and split in Go as Markdown, which docreader converts documents to. Images move to the chunk
+// holding their position. Rows of CSV and Excel files are kept as parsed.
+func (s *knowledgeService) rechunkDocReaderChunks(ctx context.Context,
+	kb *types.KnowledgeBase, chunks []*proto.Chunk, fileType string,
+) []*proto.Chunk {
+	if kb.ChunkingConfig.GetStrategy() == types.ChunkingStrategyCharacter || len(chunks) == 0 {
+		return chunks
+	}
+	switch strings.ToLower(strings.TrimPrefix(fileType, ".")) {
+	case "csv", "xls", "xlsx":
+		return chunks
+	}
+
+	text, images := restoreDocReaderText(chunks)
+	if strings.TrimSpace(text) == "" {
+		return chunks
+	}
+	result := s.chunkNatively(ctx, kb, text, "md")
+	if len(result) == 0 {
+		return chunks
+	}
+	for _, image := range images {
+		attachImage(result, image)
+	}
+	logger.Infof(ctx, "Split %d docreader chunks of %s again into %d chunks, strategy: %s",
+		len(chunks), fileType, len(result), kb.ChunkingConfig.GetStrategy())
+	return result
+}
+
+// positionedImage is an image at a character offset of the restored document text
+type positionedImage struct {
+	image    *proto.Image
+	position int
+}
+
+// restoreDocReaderText rebuilds the parsed document text from overlapping docreader chunks, like
+// TextSplitter.restore_text in docreader: taking chunks by end offset, each adds the text after the
+// end of the previous one. Headings docreader prepends to a chunk are at its start and so left out.
+// The images of the chunks are returned once each, at their offset in the restored text.
+func restoreDocReaderText(chunks []*proto.Chunk) (string, []positionedImage) {
+	sorted := slices.Clone(chunks)
+	slices.SortStableFunc(sorted, func(a, b *proto.Chunk) int {
+		if a.End != b.End {
+			return int(a.End - b.End)
+		}
+		return int(a.Start - b.Start)
+	})
+
+	var text []rune
+	var images []positionedImage
+	seenImages := make(map[string]bool)
+	lastEnd := 0
+	for _, chunk := range sorted {
+		runes := []rune(chunk.Content)
+		added := min(int(chunk.End)-lastEnd, len(runes))
+		if added <= 0 {
+			continue
+		}
+		// Offset in the restored text of the first rune of the chunk content
+		base := len(text) - (len(runes) - added)
+		text = append(text, runes[len(runes)-added:]...)
+		lastEnd = int(chunk.End)
+
+		for _, image := range chunk.Images {
+			key := image.OriginalUrl + "|" + image.Url
+			if seenImages[key] {
+				continue
+			}
+			seenImages[key] = true
+			images = append(images, positionedImage{image: image, position: max(0, base+int(image.Start))})
+		}
+	}
+	return string(text), images
+}
+
+// attachImage adds an image to the first chunk covering its position, or to the last chunk,
+// moving its offsets into the chunk content
+func attachImage(chunks []*proto.Chunk, image positionedImage) {
+	target := chunks[len(chunks)-1]
+	for _, chunk := range chunks {
+		if image.position >= int(chunk.Start) && image.position < int(chunk.End) {
+			target = chunk
+			break
+		}
+	}
+	// A heading prepended to the chunk shifts its content after the offsets
+	prefix := utf8.RuneCountInString(target.Content) - int(target.End-target.Start)
+	start := max(0, image.position-int(target.Start)+max(0, prefix))
+	image.image.End = int32(start) + image.image.End - image.image.Start
+	image.image.Start = int32(start)
+	target.Images = append(target.Images, image.image)
+}
+
+// chunkerConfig converts the chunking config to a splitter config of the given size and overlap.
+// The token strategy counts tokens with the tokenizer, estimating them when it is nil.
+func chunkerConfig(config types.ChunkingConfig, size, overlap int, tokenizer chunker.TokenCounter) chunker.Config {
+	strategy := config.GetStrategy()
+	return chunker.Config{
+		ChunkSize:    size,
+		ChunkOverlap: overlap,
+		Separators:   config.Separators,
+		CountTokens:  strategy == types.ChunkingStrategyToken,
+		Tokenizer:    tokenizer,
+		Sentences:    strategy == types.ChunkingStrategySentence || strategy == types.ChunkingStrategySemantic,
+	}
+}
+
+// embeddingTokenizer returns the tokenizer of the knowledge base embedding model,
+// nil when the model has no known tokenizer and tokens are estimated
+func embeddingTokenizer(ctx context.Context,
+	modelService interfaces.ModelService, kb *types.KnowledgeBase,
+) chunker.TokenCounter {
+	if kb.EmbeddingModelID == "" {
+		return nil
+	}
+	model, err := modelService.GetModelByID(ctx, kb.EmbeddingModelID)
+	if err != nil || model == nil {
+		logger.Warnf(ctx, "Failed to get embedding model for token counting, estimating tokens: %v", err)
+		return nil
+	}
+	return chunker.ModelTokenCounter(model.Name)
+}
+
+// isDocReaderUnavailable reports whether a docreader call failed because the service could not be reached
+func isDocReaderUnavailable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Tencent/WeKnora/docreader/proto"
+)
+
+func TestRestoreDocReaderText(t *testing.T) {
+	image := &proto.Image{Url: "img", Start: 6, End: 7}
+	chunks := []*proto.Chunk{
+		// The second chunk overlaps the first and starts with a prepended heading
+		{Seq: 1, Content: "# T\nefghij", Start: 4, End: 10, Images: []*proto.Image{image}},
+		{Seq: 0, Content: "abcdef", Start: 0, End: 6},
+		{Seq: 2, Content: "ijkl", Start: 8, End: 12, Images: []*proto.Image{{Url: "img", Start: 0, End: 1}}},
+	}
+	text, images := restoreDocReaderText(chunks)
+	if text != "abcdefghijkl" {
+		t.Errorf("restored text = %q, want %q", text, "abcdefghijkl")
+	}
+	if len(images) != 1 || images[0].position != 6 {
+		t.Fatalf("images = %+v, want the image once at offset 6", images)
+	}
+
+	split := []*proto.Chunk{
+		{Content: "abcde", Start: 0, End: 5},
+		{Content: "# T\nfghij", Start: 5, End: 10},
+	}
+	attachImage(split, images[0])
+	if len(split[1].Images) != 1 || split[1].Images[0].Start != 5 || split[1].Images[0].End != 6 {
+		t.Errorf("image attached as %+v, want at content offset 5 of the second chunk", split[1].Images)
+	}
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"time"
+	"unicode/utf8"
+
+	"github.com/Tencent/WeKnora/internal/chunker"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/google/uuid"
+)
+
+// buildChildChunks splits each parent text chunk into child chunks for small-to-big retrieval.
+// Parents that fit in a single child are returned as indexed themselves and get no children.
+func buildChildChunks(config types.ChunkingConfig, tokenizer chunker.TokenCounter,
+	parents []*types.Chunk,
+) (children []*types.Chunk, indexed []*types.Chunk) {
+	childConfig := chunkerConfig(config, config.GetChildChunkSize(), config.GetChildChunkOverlap(), tokenizer)
+	for _, parent := range parents {
+		splits := chunker.Split(parent.Content, childConfig)
+		if len(splits) <= 1 {
+			indexed = append(indexed, parent)
+			continue
+		}
+		// A heading prepended to the parent content shifts it after the document offsets
+		prefix := 0
+		if parent.EndAt > parent.StartAt {
+			prefix = max(0, utf8.RuneCountInString(parent.Content)-(parent.EndAt-parent.StartAt))
+		}
+		for _, split := range splits {
+			child := &types.Chunk{
+				ID:              uuid.New().String(),
+				TenantID:        parent.TenantID,
+				KnowledgeID:     parent.KnowledgeID,
+				KnowledgeBaseID: parent.KnowledgeBaseID,
+				Content:         split.Content,
+				ChunkIndex:      parent.ChunkIndex,
+				IsEnabled:       true,
+				CreatedAt:       time.Now(),
+				UpdatedAt:       time.Now(),
+				StartAt:         parent.StartAt + max(0, split.Start-prefix),
+				EndAt:           parent.StartAt + max(0, split.End-prefix),
+				ChunkType:       types.ChunkTypeChild,
+				ParentChunkID:   parent.ID,
+			}
+			children = append(children, child)
+			indexed = append(indexed, child)
+		}
+	}
+	return children, indexed
+}
+
+// knowledgeInRetrieval reports whether the chunks of a knowledge may be retrieved, that is the
+// knowledge is neither disabled nor outside its validity period
+func knowledgeInRetrieval(knowledge *types.Knowledge, now time.Time) bool {
+	return knowledge.IsValidAt(now) &&
+		(knowledge.EnableStatus == "" || knowledge.EnableStatus == types.KnowledgeEnableStatusEnabled)
+}
+
+// chunkEnabledIndexStatus returns the enabled status of the index entries of a chunk and of its child
+// chunks, which follow their parent. Entries of a knowledge out of retrieval stay disabled.
+func chunkEnabledIndexStatus(knowledge *types.Knowledge, chunk *types.Chunk, children []*types.Chunk,
+	now time.Time,
+) map[string]bool {
+	enabled := chunk.IsEnabled && knowledgeInRetrieval(knowledge, now)
+	chunkStatusMap := map[string]bool{chunk.ID: enabled}
+	for _, child := range children {
+		if child.ChunkType == types.ChunkTypeChild {
+			chunkStatusMap[child.ID] = enabled && child.IsEnabled
+		}
+	}
+	return chunkStatusMap
+}
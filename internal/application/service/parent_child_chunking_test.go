@@ -0,0 +1,104 @@
+package service
+
+import (
+	"maps"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+func TestBuildChildChunks(t *testing.T) {
+	long := &types.Chunk{
+		ID:         "parent-1",
+		Content:    strings.Repeat("step one of the install.\n", 12),
+		ChunkIndex: 3,
+		StartAt:    100,
+		ChunkType:  types.ChunkTypeText,
+	}
+	short := &types.Chunk{ID: "parent-2", Content: "short section", ChunkIndex: 4, ChunkType: types.ChunkTypeText}
+	config := types.ChunkingConfig{ChunkSize: 512, Separators: []string{"\n"}, EnableParentChild: true}
+
+	children, indexed := buildChildChunks(config, nil, []*types.Chunk{long, short})
+	if len(children) < 2 {
+		t.Fatalf("got %d children, want the long parent split", len(children))
+	}
+	if len(indexed) != len(children)+1 || indexed[len(indexed)-1] != short {
+		t.Errorf("short parent should be indexed itself, indexed %d chunks", len(indexed))
+	}
+
+	parentRunes := []rune(long.Content)
+	for i, child := range children {
+		if child.ParentChunkID != long.ID || child.ChunkType != types.ChunkTypeChild || child.ChunkIndex != long.ChunkIndex {
+			t.Errorf("child %d not linked to its parent: %+v", i, child)
+		}
+		if n := utf8.RuneCountInString(child.Content); n > config.GetChildChunkSize() {
+			t.Errorf("child %d has %d characters", i, n)
+		}
+		if child.Content != string(parentRunes[child.StartAt-long.StartAt:child.EndAt-long.StartAt]) {
+			t.Errorf("child %d content does not match its offsets", i)
+		}
+	}
+}
+
+func TestBuildChildChunksHeadingPrefix(t *testing.T) {
+	body := strings.Repeat("step one of the install.\n", 12)
+	parent := &types.Chunk{
+		ID:        "parent-1",
+		Content:   "# Install\n" + body,
+		StartAt:   100,
+		EndAt:     100 + utf8.RuneCountInString(body),
+		ChunkType: types.ChunkTypeText,
+	}
+	document := strings.Repeat("x", 100) + body
+	config := types.ChunkingConfig{ChunkSize: 512, Separators: []string{"\n"}, EnableParentChild: true}
+
+	children, _ := buildChildChunks(config, nil, []*types.Chunk{parent})
+	if len(children) < 2 {
+		t.Fatalf("got %d children, want the parent split", len(children))
+	}
+	documentRunes := []rune(document)
+	last := children[len(children)-1]
+	if last.EndAt != parent.EndAt {
+		t.Errorf("last child ends at %d, want the parent end %d", last.EndAt, parent.EndAt)
+	}
+	if !strings.HasSuffix(last.Content, string(documentRunes[last.StartAt:last.EndAt])) {
+		t.Errorf("last child offsets [%d, %d) do not cover its content in the document", last.StartAt, last.EndAt)
+	}
+}
+
+func TestChunkEnabledIndexStatus(t *testing.T) {
+	now := time.Now()
+	parent := &types.Chunk{ID: "parent", IsEnabled: true}
+	children := []*types.Chunk{
+		{ID: "child-1", IsEnabled: true, ChunkType: types.ChunkTypeChild, ParentChunkID: "parent"},
+		{ID: "child-2", IsEnabled: false, ChunkType: types.ChunkTypeChild, ParentChunkID: "parent"},
+		{ID: "image", IsEnabled: true, ChunkType: types.ChunkTypeImageOCR, ParentChunkID: "parent"},
+	}
+	expired := now.Add(-time.Hour)
+
+	tests := []struct {
+		name      string
+		knowledge *types.Knowledge
+		enabled   bool
+		want      map[string]bool
+	}{
+		{"enable", &types.Knowledge{}, true, map[string]bool{"parent": true, "child-1": true, "child-2": false}},
+		{"disable", &types.Knowledge{}, false, map[string]bool{"parent": false, "child-1": false, "child-2": false}},
+		{
+			"enable in expired knowledge", &types.Knowledge{ValidUntil: &expired}, true,
+			map[string]bool{"parent": false, "child-1": false, "child-2": false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent.IsEnabled = tt.enabled
+			got := chunkEnabledIndexStatus(tt.knowledge, parent, children, now)
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("chunkEnabledIndexStatus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
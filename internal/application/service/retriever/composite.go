@@ -128,6 +128,19 @@ func (c *CompositeRetrieveEngine) BatchUpdateChunkTagID(
 	})
 }
 
+// BatchUpdateKnowledgeMetadata replaces the custom metadata of knowledge indices in batch
+func (c *CompositeRetrieveEngine) BatchUpdateKnowledgeMetadata(
+	ctx context.Context,
+	knowledgeMetadataMap map[string]map[string]any,
+) error {
+	return c.concurrentExecWithError(ctx, func(ctx context.Context, engineInfo *engineInfo) error {
+		if err := engineInfo.retrieveEngine.BatchUpdateKnowledgeMetadata(ctx, knowledgeMetadataMap); err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
 // concurrentRetrieve is a helper function for concurrent processing of retrieval parameters
 // and collecting results
 func concurrentRetrieve(
@@ -283,3 +283,11 @@ func (v *KeywordsVectorHybridRetrieveEngineService) BatchUpdateChunkTagID(
 ) error {
 	return v.indexRepository.BatchUpdateChunkTagID(ctx, chunkTagMap)
 }
+
+// BatchUpdateKnowledgeMetadata replaces the custom metadata of knowledge indices in batch
+func (v *KeywordsVectorHybridRetrieveEngineService) BatchUpdateKnowledgeMetadata(
+	ctx context.Context,
+	knowledgeMetadataMap map[string]map[string]any,
+) error {
+	return v.indexRepository.BatchUpdateKnowledgeMetadata(ctx, knowledgeMetadataMap)
+}
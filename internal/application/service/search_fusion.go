@@ -0,0 +1,183 @@
+package service
+
+import (
+	"sort"
+
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+// retrieverHits holds the rank and best score of each chunk within one retriever's results
+type retrieverHits struct {
+	ranks  map[string]int
+	scores map[string]float64
+}
+
+// collectRetrieverHits builds rank and score maps from results already sorted by the retriever.
+// A chunk may appear several times (e.g. FAQ similar questions), its first rank and highest score are kept.
+func collectRetrieverHits(results []*types.IndexWithScore) retrieverHits {
+	hits := retrieverHits{
+		ranks:  make(map[string]int, len(results)),
+		scores: make(map[string]float64, len(results)),
+	}
+	for i, r := range results {
+		if _, exists := hits.ranks[r.ChunkID]; !exists {
+			hits.ranks[r.ChunkID] = i + 1 // 1-indexed rank
+			hits.scores[r.ChunkID] = r.Score
+		} else if r.Score > hits.scores[r.ChunkID] {
+			hits.scores[r.ChunkID] = r.Score
+		}
+	}
+	return hits
+}
+
+// normalize min-max normalizes the scores into [0, 1], all scores become 1 when they are equal
+func (h retrieverHits) normalize() map[string]float64 {
+	normalized := make(map[string]float64, len(h.scores))
+	if len(h.scores) == 0 {
+		return normalized
+	}
+	first := true
+	var minScore, maxScore float64
+	for _, score := range h.scores {
+		if first || score < minScore {
+			minScore = score
+		}
+		if first || score > maxScore {
+			maxScore = score
+		}
+		first = false
+	}
+	for chunkID, score := range h.scores {
+		if maxScore == minScore {
+			normalized[chunkID] = 1
+		} else {
+			normalized[chunkID] = (score - minScore) / (maxScore - minScore)
+		}
+	}
+	return normalized
+}
+
+// uniqueChunks returns one entry per chunk in input order, keeping the entry with the highest score
+func uniqueChunks(results []*types.IndexWithScore) []*types.IndexWithScore {
+	indexes := make(map[string]int, len(results))
+	chunks := make([]*types.IndexWithScore, 0, len(results))
+	for _, r := range results {
+		if i, exists := indexes[r.ChunkID]; exists {
+			if r.Score > chunks[i].Score {
+				chunks[i] = r
+			}
+			continue
+		}
+		indexes[r.ChunkID] = len(chunks)
+		chunks = append(chunks, r)
+	}
+	return chunks
+}
+
+// sortByScoreDesc sorts chunks by score in descending order, keeping input order for ties
+func sortByScoreDesc(chunks []*types.IndexWithScore) {
+	sort.SliceStable(chunks, func(i, j int) bool {
+		return chunks[i].Score > chunks[j].Score
+	})
+}
+
+// fuseHybridResults merges vector and keyword retrieval results into one list of unique chunks
+// sorted by the fused score, which is written to Score. The per-retriever ranks and raw scores
+// are recorded in ScoreDetails.
+//
+// When there are no keyword results the original vector scores are kept whatever the strategy,
+// FAQ search relies on them being comparable to the vector threshold.
+func fuseHybridResults(vectorResults, keywordResults []*types.IndexWithScore,
+	cfg types.FusionConfig,
+) []*types.IndexWithScore {
+	vectorHits := collectRetrieverHits(vectorResults)
+	keywordHits := collectRetrieverHits(keywordResults)
+
+	vectorChunks := uniqueChunks(vectorResults)
+	keywordChunks := uniqueChunks(keywordResults)
+
+	// Vector entries win over keyword entries of the same chunk since they carry the matched content
+	var chunks []*types.IndexWithScore
+	switch {
+	case len(keywordResults) == 0:
+		chunks = vectorChunks
+		sortByScoreDesc(chunks)
+	case cfg.Strategy == types.FusionStrategyVectorFallback:
+		// Keyword results are only used when vector retrieval found too few chunks, and are ranked
+		// after all vector results since the two scores are not comparable
+		sortByScoreDesc(vectorChunks)
+		chunks = vectorChunks
+		if len(vectorChunks) < cfg.FallbackMinResults {
+			sortByScoreDesc(keywordChunks)
+			var fallback []*types.IndexWithScore
+			for _, r := range keywordChunks {
+				if _, exists := vectorHits.ranks[r.ChunkID]; !exists {
+					fallback = append(fallback, r)
+				}
+			}
+			assignFallbackScores(fallback, vectorChunks)
+			chunks = append(chunks, fallback...)
+		}
+	default:
+		chunks = vectorChunks
+		for _, r := range keywordChunks {
+			if _, exists := vectorHits.ranks[r.ChunkID]; !exists {
+				chunks = append(chunks, r)
+			}
+		}
+		var vectorNormalized, keywordNormalized map[string]float64
+		if cfg.Strategy == types.FusionStrategyWeighted {
+			vectorNormalized = vectorHits.normalize()
+			keywordNormalized = keywordHits.normalize()
+		}
+		for _, chunk := range chunks {
+			score := 0.0
+			if cfg.Strategy == types.FusionStrategyWeighted {
+				score = cfg.VectorWeight*vectorNormalized[chunk.ChunkID] +
+					cfg.KeywordWeight*keywordNormalized[chunk.ChunkID]
+			} else {
+				// RRF score = sum(1 / (k + rank)) for each retriever where the chunk appears
+				if rank, ok := vectorHits.ranks[chunk.ChunkID]; ok {
+					score += 1.0 / float64(cfg.RRFK+rank)
+				}
+				if rank, ok := keywordHits.ranks[chunk.ChunkID]; ok {
+					score += 1.0 / float64(cfg.RRFK+rank)
+				}
+			}
+			chunk.Score = score
+		}
+		sortByScoreDesc(chunks)
+	}
+
+	for _, chunk := range chunks {
+		chunk.ScoreDetails = newScoreDetails(chunk.ChunkID, cfg.Strategy, vectorHits, keywordHits)
+	}
+	return chunks
+}
+
+// assignFallbackScores replaces the keyword scores of fallback chunks with scores by rank strictly
+// below the lowest vector score, so that they stay behind the vector results when later steps sort
+// or threshold on Score. The keyword scores are kept in ScoreDetails.
+func assignFallbackScores(fallback, vectorChunks []*types.IndexWithScore) {
+	floor := 1.0
+	if len(vectorChunks) > 0 {
+		floor = vectorChunks[len(vectorChunks)-1].Score
+	}
+	gap := max(floor, 0.01) / float64(len(fallback)+1)
+	for i, chunk := range fallback {
+		chunk.Score = floor - float64(i+1)*gap
+	}
+}
+
+// newScoreDetails builds the per-retriever score details of a chunk
+func newScoreDetails(chunkID string, strategy types.FusionStrategy,
+	vectorHits, keywordHits retrieverHits,
+) *types.ScoreDetails {
+	return &types.ScoreDetails{
+		FusionStrategy: strategy,
+		VectorRank:     vectorHits.ranks[chunkID],
+		VectorScore:    vectorHits.scores[chunkID],
+		KeywordRank:    keywordHits.ranks[chunkID],
+		KeywordScore:   keywordHits.scores[chunkID],
+	}
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"math"
+	"testing"
+
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+func hits(scores ...any) []*types.IndexWithScore {
+	var results []*types.IndexWithScore
+	for i := 0; i < len(scores); i += 2 {
+		results = append(results, &types.IndexWithScore{
+			ChunkID: scores[i].(string),
+			Score:   scores[i+1].(float64),
+		})
+	}
+	return results
+}
+
+func TestFuseHybridResults(t *testing.T) {
+	tests := []struct {
+		name       string
+		vector     []*types.IndexWithScore
+		keyword    []*types.IndexWithScore
+		config     *types.FusionConfig
+		wantOrder  []string
+		wantScores []float64
+	}{
+		{
+			name:       "vector only keeps original scores",
+			vector:     hits("a", 0.6, "b", 0.9, "a", 0.8),
+			config:     nil,
+			wantOrder:  []string{"b", "a"},
+			wantScores: []float64{0.9, 0.8},
+		},
+		{
+			name:       "rrf with default k",
+			vector:     hits("a", 0.9, "b", 0.8),
+			keyword:    hits("b", 12.0, "c", 3.0),
+			config:     nil,
+			wantOrder:  []string{"b", "a", "c"},
+			wantScores: []float64{1.0/62 + 1.0/61, 1.0 / 61, 1.0 / 62},
+		},
+		{
+			name:       "rrf with custom k",
+			vector:     hits("a", 0.9),
+			keyword:    hits("c", 3.0),
+			config:     &types.FusionConfig{Strategy: types.FusionStrategyRRF, RRFK: 10},
+			wantOrder:  []string{"a", "c"},
+			wantScores: []float64{1.0 / 11, 1.0 / 11},
+		},
+		{
+			name:    "weighted normalizes each retriever",
+			vector:  hits("a", 0.9, "b", 0.7, "c", 0.5),
+			keyword: hits("c", 20.0, "b", 10.0),
+			config: &types.FusionConfig{
+				Strategy: types.FusionStrategyWeighted, VectorWeight: 0.5, KeywordWeight: 0.5,
+			},
+			wantOrder:  []string{"a", "c", "b"},
+			wantScores: []float64{0.5, 0.5, 0.25},
+		},
+		{
+			name:       "vector fallback ignores keyword when vector is enough",
+			vector:     hits("a", 0.9),
+			keyword:    hits("c", 20.0),
+			config:     &types.FusionConfig{Strategy: types.FusionStrategyVectorFallback},
+			wantOrder:  []string{"a"},
+			wantScores: []float64{0.9},
+		},
+		{
+			name:    "vector fallback appends keyword results after vector",
+			vector:  hits("a", 0.4),
+			keyword: hits("c", 2.0, "a", 5.0, "d", 8.0),
+			config: &types.FusionConfig{
+				Strategy: types.FusionStrategyVectorFallback, FallbackMinResults: 2,
+			},
+			wantOrder:  []string{"a", "d", "c"},
+			wantScores: []float64{0.4, 0.4 * 2 / 3, 0.4 / 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := fuseHybridResults(tt.vector, tt.keyword, tt.config.WithDefaults())
+			if len(got) != len(tt.wantOrder) {
+				t.Fatalf("got %d results, want %d", len(got), len(tt.wantOrder))
+			}
+			for i, r := range got {
+				if r.ChunkID != tt.wantOrder[i] {
+					t.Errorf("result %d: got chunk %s, want %s", i, r.ChunkID, tt.wantOrder[i])
+				}
+				if math.Abs(r.Score-tt.wantScores[i]) > 1e-9 {
+					t.Errorf("result %d: got score %v, want %v", i, r.Score, tt.wantScores[i])
+				}
+				if r.ScoreDetails == nil {
+					t.Fatalf("result %d: missing score details", i)
+				}
+			}
+		})
+	}
+}
+
+func TestFuseHybridResultsVectorFallbackResort(t *testing.T) {
+	cfg := types.FusionConfig{Strategy: types.FusionStrategyVectorFallback, FallbackMinResults: 3}
+	got := fuseHybridResults(hits("a", 0.82, "b", 0.55), hits("d", 8.0, "c", 2.5), cfg.WithDefaults())
+
+	// Later steps such as expiry decay and rerank sort by Score again
+	sortByScoreDesc(got)
+	want := []string{"a", "b", "d", "c"}
+	for i, r := range got {
+		if r.ChunkID != want[i] {
+			t.Fatalf("after re-sorting result %d is %s, want order %v", i, r.ChunkID, want)
+		}
+	}
+	if got[2].Score >= 0.55 || got[2].ScoreDetails.KeywordScore != 8.0 {
+		t.Errorf("keyword fallback chunk scored %v with details %+v", got[2].Score, got[2].ScoreDetails)
+	}
+}
+
+func TestFuseHybridResultsScoreDetails(t *testing.T) {
+	got := fuseHybridResults(hits("a", 0.9, "b", 0.8), hits("b", 12.0), (*types.FusionConfig)(nil).WithDefaults())
+	details := map[string]types.ScoreDetails{}
+	for _, r := range got {
+		details[r.ChunkID] = *r.ScoreDetails
+	}
+	want := map[string]types.ScoreDetails{
+		"a": {FusionStrategy: types.FusionStrategyRRF, VectorRank: 1, VectorScore: 0.9},
+		"b": {FusionStrategy: types.FusionStrategyRRF, VectorRank: 2, VectorScore: 0.8, KeywordRank: 1, KeywordScore: 12.0},
+	}
+	for chunkID, w := range want {
+		if details[chunkID] != w {
+			t.Errorf("chunk %s: got %+v, want %+v", chunkID, details[chunkID], w)
+		}
+	}
+}
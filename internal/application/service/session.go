@@ -15,6 +15,7 @@ import (
 	"github.com/Tencent/WeKnora/internal/logger"
 	"github.com/Tencent/WeKnora/internal/models/chat"
 	"github.com/Tencent/WeKnora/internal/models/rerank"
+	"github.com/Tencent/WeKnora/internal/searchutil"
 	"github.com/Tencent/WeKnora/internal/tracing"
 	"github.com/Tencent/WeKnora/internal/types"
 	"github.com/Tencent/WeKnora/internal/types/interfaces"
@@ -42,6 +43,7 @@ type sessionService struct {
 	knowledgeService     interfaces.KnowledgeService      // Service for knowledge operations
 	chunkService         interfaces.ChunkService          // Service for chunk operations
 	webSearchStateRepo   interfaces.WebSearchStateService // Service for web search state
+	analyticsService     interfaces.AnalyticsService      // Service for knowledge base analytics
 }
 
 // NewSessionService creates a new session service instance with all required dependencies
@@ -57,6 +59,7 @@ func NewSessionService(cfg *config.Config,
 	agentService interfaces.AgentService,
 	sessionStorage llmcontext.ContextStorage,
 	webSearchStateRepo interfaces.WebSearchStateService,
+	analyticsService interfaces.AnalyticsService,
 ) interfaces.SessionService {
 	return &sessionService{
 		cfg:                  cfg,
@@ -71,6 +74,7 @@ func NewSessionService(cfg *config.Config,
 		agentService:         agentService,
 		sessionStorage:       sessionStorage,
 		webSearchStateRepo:   webSearchStateRepo,
+		analyticsService:     analyticsService,
 	}
 }
 
@@ -405,6 +409,7 @@ func (s *sessionService) KnowledgeQA(
 	assistantMessageID string,
 	summaryModelID string,
 	webSearchEnabled bool,
+	answerPreference types.FAQAnswerPreference,
 	eventBus *event.EventBus,
 	customAgent *types.CustomAgent,
 ) error {
@@ -591,6 +596,7 @@ func (s *sessionService) KnowledgeQA(
 	chatManage := &types.ChatManage{
 		Query:                query,
 		RewriteQuery:         query,
+		QueryLanguage:        searchutil.DetectLanguage(query),
 		SessionID:            session.ID,
 		MessageID:            assistantMessageID, // NEW: For event emission in pipeline
 		KnowledgeBaseIDs:     knowledgeBaseIDs,   // Multi-KB support
@@ -619,6 +625,11 @@ func (s *sessionService) KnowledgeQA(
 		FAQPriorityEnabled:       faqPriorityEnabled,
 		FAQDirectAnswerThreshold: faqDirectAnswerThreshold,
 		FAQScoreBoost:            faqScoreBoost,
+		FAQAnswerPreference:      answerPreference,
+	}
+	if customAgent != nil {
+		chatManage.WebSearchAllowlist = customAgent.Config.WebSearchAllowlist
+		chatManage.WebSearchTrustWeights = customAgent.Config.WebSearchTrustWeights
 	}
 
 	// Determine pipeline based on knowledge bases availability and web search setting
@@ -942,6 +953,7 @@ func (s *sessionService) KnowledgeQAByEvent(ctx context.Context,
 				eventType,
 				chatManage.FallbackStrategy,
 			)
+			s.analyticsService.RecordZeroResult(ctx, chatManage)
 			s.handleFallbackResponse(ctx, chatManage)
 			return nil
 		}
@@ -965,8 +977,9 @@ func (s *sessionService) KnowledgeQAByEvent(ctx context.Context,
 // SearchKnowledge performs knowledge base search without LLM summarization
 // knowledgeBaseIDs: list of knowledge base IDs to search (supports multi-KB)
 // knowledgeIDs: list of specific knowledge (file) IDs to search
+// metadataFilters: optional filters on the custom metadata of knowledge
 func (s *sessionService) SearchKnowledge(ctx context.Context,
-	knowledgeBaseIDs []string, knowledgeIDs []string, query string,
+	knowledgeBaseIDs []string, knowledgeIDs []string, query string, metadataFilters types.MetadataFilters,
 ) ([]*types.SearchResult, error) {
 	logger.Info(ctx, "Start knowledge base search without LLM summary")
 	logger.Infof(ctx, "Knowledge base search parameters, knowledge base IDs: %v, knowledge IDs: %v, query: %s",
@@ -996,6 +1009,7 @@ func (s *sessionService) SearchKnowledge(ctx context.Context,
 		RewriteQuery:     query,
 		KnowledgeBaseIDs: knowledgeBaseIDs,
 		KnowledgeIDs:     knowledgeIDs,
+		MetadataFilters:  metadataFilters,
 		SearchTargets:    searchTargets,
 		VectorThreshold:  s.cfg.Conversation.VectorThreshold,  // Use default configuration
 		KeywordThreshold: s.cfg.Conversation.KeywordThreshold, // Use default configuration
@@ -1122,12 +1136,15 @@ func (s *sessionService) AgentQA(
 		Temperature:                 customAgent.Config.Temperature,
 		WebSearchEnabled:            customAgent.Config.WebSearchEnabled,
 		WebSearchMaxResults:         customAgent.Config.WebSearchMaxResults,
+		WebSearchAllowlist:          customAgent.Config.WebSearchAllowlist,
+		WebSearchTrustWeights:       customAgent.Config.WebSearchTrustWeights,
 		MultiTurnEnabled:            customAgent.Config.MultiTurnEnabled,
 		HistoryTurns:                customAgent.Config.HistoryTurns,
 		MCPSelectionMode:            customAgent.Config.MCPSelectionMode,
 		MCPServices:                 customAgent.Config.MCPServices,
 		Thinking:                    customAgent.Config.Thinking,
 		RetrieveKBOnlyWhenMentioned: customAgent.Config.RetrieveKBOnlyWhenMentioned,
+		QueryLanguage:               searchutil.DetectLanguage(query),
 	}
 
 	// Resolve knowledge bases: request-level @ mentions take priority over agent config
@@ -1466,8 +1483,9 @@ func (s *sessionService) consumeFallbackStream(
 				Type:      types.EventType(event.EventAgentFinalAnswer),
 				SessionID: chatManage.SessionID,
 				Data: event.AgentFinalAnswerData{
-					Content: response.Content,
-					Done:    response.Done,
+					Content:    response.Content,
+					Done:       response.Done,
+					IsFallback: true,
 				},
 			}); err != nil {
 				logger.Errorf(ctx, "Failed to emit fallback answer chunk event: %v", err)
@@ -1502,8 +1520,9 @@ func (s *sessionService) emitFallbackAnswer(ctx context.Context, chatManage *typ
 		Type:      types.EventType(event.EventAgentFinalAnswer),
 		SessionID: chatManage.SessionID,
 		Data: event.AgentFinalAnswerData{
-			Content: content,
-			Done:    true,
+			Content:    content,
+			Done:       true,
+			IsFallback: true,
 		},
 	}); err != nil {
 		logger.Errorf(ctx, "Failed to emit fallback answer event: %v", err)
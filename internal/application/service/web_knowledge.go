@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/agent/tools"
+	werrors "github.com/Tencent/WeKnora/internal/errors"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+)
+
+const (
+	// maxSavedWebReferences bounds the number of web references saved in one request
+	maxSavedWebReferences = 10
+	// maxWebKnowledgeTitleLength bounds the title of knowledge saved from a web page
+	maxWebKnowledgeTitleLength = 200
+)
+
+// webPageFetcher fetches the content of a web page as Markdown text
+type webPageFetcher interface {
+	FetchPage(ctx context.Context, rawURL string) (string, error)
+}
+
+// webKnowledgeService implements the WebKnowledgeService interface
+type webKnowledgeService struct {
+	messageService   interfaces.MessageService
+	kbService        interfaces.KnowledgeBaseService
+	knowledgeService interfaces.KnowledgeService
+	fetcher          webPageFetcher
+}
+
+// NewWebKnowledgeService creates a service saving web references as knowledge.
+// Pages are fetched with the web_fetch tool, so the same SSRF protection applies.
+func NewWebKnowledgeService(
+	messageService interfaces.MessageService,
+	kbService interfaces.KnowledgeBaseService,
+	knowledgeService interfaces.KnowledgeService,
+) interfaces.WebKnowledgeService {
+	return &webKnowledgeService{
+		messageService:   messageService,
+		kbService:        kbService,
+		knowledgeService: knowledgeService,
+		fetcher:          tools.NewWebFetchTool(nil),
+	}
+}
+
+// webReference is a web search result referenced by a message
+type webReference struct {
+	URL         string
+	Title       string
+	Content     string
+	PublishedAt string
+}
+
+// SaveWebReferences fetches the selected web references of a message and saves each page as
+// published manual knowledge, recording the source URL and fetch time. References whose page
+// cannot be fetched are saved with the content of the search result instead.
+func (s *webKnowledgeService) SaveWebReferences(ctx context.Context,
+	sessionID string, messageID string, req *types.SaveWebReferencesRequest,
+) ([]*types.SavedWebReference, error) {
+	if len(req.URLs) > maxSavedWebReferences {
+		return nil, werrors.NewValidationError(fmt.Sprintf("最多同时保存%d个网页", maxSavedWebReferences))
+	}
+	message, err := s.messageService.GetMessage(ctx, sessionID, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if message == nil {
+		return nil, werrors.NewNotFoundError("消息不存在")
+	}
+
+	kb, err := s.kbService.GetKnowledgeBaseByID(ctx, req.KnowledgeBaseID)
+	if err != nil {
+		return nil, err
+	}
+	if kb.TenantID != ctx.Value(types.TenantIDContextKey).(uint64) {
+		return nil, werrors.NewForbiddenError("无权访问该知识库")
+	}
+	if kb.IsTemporary || kb.Type == types.KnowledgeBaseTypeFAQ {
+		return nil, werrors.NewBadRequestError("只能保存到文档知识库")
+	}
+
+	refs := messageWebReferences(message)
+	results := make([]*types.SavedWebReference, 0, len(req.URLs))
+	selected := make([]*webReference, 0, len(req.URLs))
+	seen := make(map[string]bool, len(req.URLs))
+	for _, rawURL := range req.URLs {
+		u := strings.TrimSpace(rawURL)
+		if u == "" || seen[u] {
+			continue
+		}
+		seen[u] = true
+		results = append(results, &types.SavedWebReference{URL: u})
+		selected = append(selected, refs[u])
+	}
+
+	// Fetch pages concurrently, like the web_fetch tool does for its items
+	contents := make([]string, len(selected))
+	var wg sync.WaitGroup
+	for i, ref := range selected {
+		if ref == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, ref *webReference) {
+			defer wg.Done()
+			content, err := s.fetcher.FetchPage(ctx, ref.URL)
+			if err != nil {
+				logger.Warnf(ctx, "[WebKnowledge] Failed to fetch %s, saving reference content: %v", ref.URL, err)
+				return
+			}
+			contents[i] = strings.TrimSpace(content)
+		}(i, ref)
+	}
+	wg.Wait()
+
+	fetchedAt := time.Now()
+	for i, result := range results {
+		ref := selected[i]
+		if ref == nil {
+			result.Error = "not a web reference of the message"
+			continue
+		}
+		content := contents[i]
+		result.FullContent = content != ""
+		if content == "" {
+			content = strings.TrimSpace(ref.Content)
+		}
+		if content == "" {
+			result.Error = "no content could be fetched"
+			continue
+		}
+		knowledge, err := s.knowledgeService.CreateKnowledgeFromManual(ctx, kb.ID,
+			webKnowledgePayload(ref, content, fetchedAt, req.TagID))
+		if err != nil {
+			logger.Errorf(ctx, "[WebKnowledge] Failed to save %s: %v", ref.URL, err)
+			result.Error = err.Error()
+			continue
+		}
+		result.KnowledgeID = knowledge.ID
+	}
+
+	logger.Infof(ctx, "[WebKnowledge] Saved web references of message %s into knowledge base %s, requested: %d",
+		messageID, kb.ID, len(results))
+	return results, nil
+}
+
+// webKnowledgePayload builds the manual knowledge of a web page. The source URL and fetch time
+// are kept in the content, so they are indexed with the chunks, and in the custom metadata.
+func webKnowledgePayload(ref *webReference, content string, fetchedAt time.Time, tagID string,
+) *types.ManualKnowledgePayload {
+	title := strings.TrimSpace(ref.Title)
+	if title == "" {
+		if u, err := url.Parse(ref.URL); err == nil && u.Host != "" {
+			title = u.Host + u.Path
+		} else {
+			title = ref.URL
+		}
+	}
+	if runes := []rune(title); len(runes) > maxWebKnowledgeTitleLength {
+		title = string(runes[:maxWebKnowledgeTitleLength])
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("# %s\n\n", title))
+	builder.WriteString(fmt.Sprintf("> Source: %s\n", ref.URL))
+	if ref.PublishedAt != "" {
+		builder.WriteString(fmt.Sprintf("> Published: %s\n", ref.PublishedAt))
+	}
+	builder.WriteString(fmt.Sprintf("> Fetched: %s\n\n", fetchedAt.Format(time.RFC3339)))
+	builder.WriteString(content)
+
+	metadata := map[string]any{
+		"source_url": ref.URL,
+		"fetched_at": fetchedAt.Format(time.RFC3339),
+	}
+	if ref.PublishedAt != "" {
+		metadata["published_at"] = ref.PublishedAt
+	}
+	return &types.ManualKnowledgePayload{
+		Title:          title,
+		Content:        builder.String(),
+		Status:         types.ManualKnowledgeStatusPublish,
+		TagID:          tagID,
+		CustomMetadata: metadata,
+		Source:         ref.URL,
+	}
+}
+
+// messageWebReferences collects the web search results referenced by a message, keyed by URL.
+// Results come from the knowledge references and, in agent mode, from web_search tool calls.
+func messageWebReferences(message *types.Message) map[string]*webReference {
+	refs := make(map[string]*webReference)
+	for _, ref := range message.KnowledgeReferences {
+		if ref == nil || ref.MatchType != types.MatchTypeWebSearch || ref.Metadata["url"] == "" {
+			continue
+		}
+		if _, ok := refs[ref.Metadata["url"]]; !ok {
+			refs[ref.Metadata["url"]] = &webReference{
+				URL:         ref.Metadata["url"],
+				Title:       ref.KnowledgeTitle,
+				Content:     ref.Content,
+				PublishedAt: ref.Metadata["published_at"],
+			}
+		}
+	}
+
+	for _, step := range message.AgentSteps {
+		for _, call := range step.ToolCalls {
+			if call.Name != tools.ToolWebSearch || call.Result == nil || call.Result.Data["results"] == nil {
+				continue
+			}
+			// Results are maps in memory and decoded JSON once loaded, so round-trip them through JSON
+			raw, err := json.Marshal(call.Result.Data["results"])
+			if err != nil {
+				continue
+			}
+			var results []struct {
+				Title       string `json:"title"`
+				URL         string `json:"url"`
+				Snippet     string `json:"snippet"`
+				Content     string `json:"content"`
+				PublishedAt string `json:"published_at"`
+			}
+			if err := json.Unmarshal(raw, &results); err != nil {
+				continue
+			}
+			for _, result := range results {
+				if _, ok := refs[result.URL]; ok || result.URL == "" {
+					continue
+				}
+				content := result.Content
+				if content == "" {
+					content = result.Snippet
+				}
+				refs[result.URL] = &webReference{
+					URL:         result.URL,
+					Title:       result.Title,
+					Content:     content,
+					PublishedAt: result.PublishedAt,
+				}
+			}
+		}
+	}
+	return refs
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/agent/tools"
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+func TestMessageWebReferences(t *testing.T) {
+	message := &types.Message{
+		KnowledgeReferences: types.References{
+			{
+				KnowledgeTitle: "Guide",
+				Content:        "guide content",
+				MatchType:      types.MatchTypeWebSearch,
+				Metadata:       map[string]string{"url": "https://example.com/guide", "published_at": "2024-01-02T00:00:00Z"},
+			},
+			{KnowledgeID: "k1", Content: "kb chunk", Metadata: map[string]string{"url": "https://kb.local"}},
+		},
+		AgentSteps: types.AgentSteps{{
+			ToolCalls: []types.ToolCall{{
+				Name: tools.ToolWebSearch,
+				Result: &types.ToolResult{Data: map[string]interface{}{
+					"results": []interface{}{
+						map[string]interface{}{"title": "Dup", "url": "https://example.com/guide", "content": "other"},
+						map[string]interface{}{"title": "News", "url": "https://news.example.org/1", "snippet": "snippet"},
+					},
+				}},
+			}},
+		}},
+	}
+
+	refs := messageWebReferences(message)
+	if len(refs) != 2 {
+		t.Fatalf("got %d references, want 2", len(refs))
+	}
+	if ref := refs["https://example.com/guide"]; ref == nil || ref.Title != "Guide" || ref.PublishedAt == "" {
+		t.Errorf("guide reference = %+v, want the knowledge reference", ref)
+	}
+	if ref := refs["https://news.example.org/1"]; ref == nil || ref.Content != "snippet" {
+		t.Errorf("agent reference = %+v, want snippet content", ref)
+	}
+	if _, ok := refs["https://kb.local"]; ok {
+		t.Error("knowledge base chunk taken as web reference")
+	}
+}
+
+func TestWebKnowledgePayload(t *testing.T) {
+	fetchedAt := time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC)
+	payload := webKnowledgePayload(&webReference{URL: "https://example.com/docs/a"}, "page", fetchedAt, "tag")
+
+	if payload.Title != "example.com/docs/a" {
+		t.Errorf("Title = %s, want example.com/docs/a", payload.Title)
+	}
+	if payload.Source != "https://example.com/docs/a" || payload.Status != types.ManualKnowledgeStatusPublish {
+		t.Errorf("Source = %s, Status = %s", payload.Source, payload.Status)
+	}
+	if !strings.Contains(payload.Content, "> Source: https://example.com/docs/a") ||
+		!strings.Contains(payload.Content, "> Fetched: 2025-03-04T05:06:07Z") {
+		t.Errorf("Content misses provenance: %s", payload.Content)
+	}
+	if payload.CustomMetadata["fetched_at"] != "2025-03-04T05:06:07Z" {
+		t.Errorf("CustomMetadata = %v", payload.CustomMetadata)
+	}
+}
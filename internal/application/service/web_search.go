@@ -3,7 +3,9 @@ package service
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -19,6 +21,7 @@ import (
 type WebSearchService struct {
 	providers map[string]interfaces.WebSearchProvider
 	timeout   int
+	cache     web_search.ResultCache
 }
 
 // CompressWithRAG performs RAG-based compression using a temporary, hidden knowledge base.
@@ -210,6 +213,8 @@ func (s *WebSearchService) consolidateReferencesByURL(
 			Content:     merged,
 			Source:      r.Source,
 			PublishedAt: r.PublishedAt,
+			TrustWeight: r.TrustWeight,
+			CachedAt:    r.CachedAt,
 		})
 	}
 	return out
@@ -267,14 +272,33 @@ func (s *WebSearchService) Search(
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	// Perform search
-	results, err := provider.Search(ctx, query, config.MaxResults, config.IncludeDate)
-	if err != nil {
-		return nil, fmt.Errorf("web search failed: %w", err)
+	// Serve identical queries from the cache unless bypassed
+	var results []*types.WebSearchResult
+	cached := false
+	if s.cache != nil && !config.BypassCache {
+		results, cached = s.cache.Get(ctx, config.Provider, query, config.MaxResults, config.IncludeDate)
+	}
+	if cached {
+		logger.Infof(ctx, "Web search cache hit, provider: %s, results: %d", config.Provider, len(results))
+	} else {
+		// Perform search
+		var err error
+		results, err = provider.Search(ctx, query, config.MaxResults, config.IncludeDate)
+		if err != nil {
+			return nil, fmt.Errorf("web search failed: %w", err)
+		}
+		// Empty results are not cached, they are often a transient provider failure
+		if s.cache != nil && len(results) > 0 {
+			s.cache.Set(ctx, config.Provider, query, config.MaxResults, config.IncludeDate, results)
+		}
 	}
 
-	// Apply blacklist filtering
+	// Apply blacklist and allowlist filtering
 	results = s.filterBlacklist(results, config.Blacklist)
+	results = s.filterAllowlist(results, config.Allowlist)
+
+	// Boost or demote results by the trust weights of their domains
+	results = s.applyTrustWeights(results, config.TrustWeights)
 
 	// Apply compression if needed
 	if config.CompressionMethod != "none" && config.CompressionMethod != "" {
@@ -286,7 +310,9 @@ func (s *WebSearchService) Search(
 }
 
 // NewWebSearchService creates a new web search service
-func NewWebSearchService(cfg *config.Config, registry *web_search.Registry) (interfaces.WebSearchService, error) {
+func NewWebSearchService(
+	cfg *config.Config, registry *web_search.Registry, cache web_search.ResultCache,
+) (interfaces.WebSearchService, error) {
 	timeout := 10 // default timeout
 	if cfg.WebSearch != nil && cfg.WebSearch.Timeout > 0 {
 		timeout = cfg.WebSearch.Timeout
@@ -305,6 +331,7 @@ func NewWebSearchService(cfg *config.Config, registry *web_search.Registry) (int
 	return &WebSearchService{
 		providers: providers,
 		timeout:   timeout,
+		cache:     cache,
 	}, nil
 }
 
@@ -323,7 +350,7 @@ func (s *WebSearchService) filterBlacklist(
 		shouldFilter := false
 
 		for _, rule := range blacklist {
-			if s.matchesBlacklistRule(result.URL, rule) {
+			if s.matchesURLRule(result.URL, rule) {
 				shouldFilter = true
 				break
 			}
@@ -337,15 +364,104 @@ func (s *WebSearchService) filterBlacklist(
 	return filtered
 }
 
-// matchesBlacklistRule checks if a URL matches a blacklist rule
-// Supports both pattern matching (e.g., *://*.example.com/*) and regex patterns (e.g., /example\.(net|org)/)
-func (s *WebSearchService) matchesBlacklistRule(url, rule string) bool {
+// filterAllowlist keeps only the results matching an allowlist rule, all results when there are no rules
+func (s *WebSearchService) filterAllowlist(
+	results []*types.WebSearchResult,
+	allowlist []string,
+) []*types.WebSearchResult {
+	if len(allowlist) == 0 {
+		return results
+	}
+
+	filtered := make([]*types.WebSearchResult, 0, len(results))
+	for _, result := range results {
+		for _, rule := range allowlist {
+			if s.matchesURLRule(result.URL, rule) {
+				filtered = append(filtered, result)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// applyTrustWeights sets the trust weight of results from the most specific matching domain, drops
+// results weighted 0 and orders results by their weight times a prior of their original rank
+func (s *WebSearchService) applyTrustWeights(
+	results []*types.WebSearchResult,
+	trustWeights map[string]float64,
+) []*types.WebSearchResult {
+	if len(trustWeights) == 0 || len(results) == 0 {
+		return results
+	}
+
+	weighted := make([]*types.WebSearchResult, 0, len(results))
+	scores := make(map[*types.WebSearchResult]float64, len(results))
+	for i, result := range results {
+		weight := 1.0
+		if w, ok := domainTrustWeight(result.URL, trustWeights); ok {
+			weight = w
+			result.TrustWeight = w
+		}
+		if weight == 0 {
+			continue
+		}
+		// Rank prior decreasing from 1 for the first result, so that a weight of 2 moves a result up about half the list
+		scores[result] = weight * (1 - float64(i)/float64(2*len(results)))
+		weighted = append(weighted, result)
+	}
+	sort.SliceStable(weighted, func(i, j int) bool {
+		return scores[weighted[i]] > scores[weighted[j]]
+	})
+	return weighted
+}
+
+// domainTrustWeight returns the trust weight of the longest domain matching the host of a URL,
+// a domain matches itself and its subdomains
+func domainTrustWeight(rawURL string, trustWeights map[string]float64) (float64, bool) {
+	host := urlHost(rawURL)
+	if host == "" {
+		return 0, false
+	}
+	matched, weight := "", 0.0
+	for domain, w := range trustWeights {
+		domain = strings.ToLower(strings.TrimPrefix(domain, "*."))
+		if matchesDomain(host, domain) && len(domain) > len(matched) {
+			matched, weight = domain, w
+		}
+	}
+	return weight, matched != ""
+}
+
+// urlHost returns the lower-case host of a URL without port
+func urlHost(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// matchesDomain checks if a host is a domain or one of its subdomains
+func matchesDomain(host, domain string) bool {
+	return domain != "" && (host == domain || strings.HasSuffix(host, "."+domain))
+}
+
+// matchesURLRule checks if a URL matches a blacklist or allowlist rule
+// Supports plain domains matching the domain and its subdomains (e.g., example.com),
+// pattern matching (e.g., *://*.example.com/*) and regex patterns (e.g., /example\.(net|org)/)
+func (s *WebSearchService) matchesURLRule(url, rule string) bool {
+	// Plain domain rule
+	if !strings.ContainsAny(rule, "/:*") {
+		return matchesDomain(urlHost(url), strings.ToLower(strings.TrimSpace(rule)))
+	}
+
 	// Check if it's a regex pattern (starts and ends with /)
 	if strings.HasPrefix(rule, "/") && strings.HasSuffix(rule, "/") {
 		pattern := rule[1 : len(rule)-1]
 		matched, err := regexp.MatchString(pattern, url)
 		if err != nil {
-			logger.Warnf(context.Background(), "Invalid regex pattern in blacklist: %s, error: %v", rule, err)
+			logger.Warnf(context.Background(), "Invalid regex pattern in URL rule: %s, error: %v", rule, err)
 			return false
 		}
 		return matched
@@ -356,7 +472,7 @@ func (s *WebSearchService) matchesBlacklistRule(url, rule string) bool {
 	pattern = "^" + pattern + "$"
 	matched, err := regexp.MatchString(pattern, url)
 	if err != nil {
-		logger.Warnf(context.Background(), "Invalid pattern in blacklist: %s, error: %v", rule, err)
+		logger.Warnf(context.Background(), "Invalid pattern in URL rule: %s, error: %v", rule, err)
 		return false
 	}
 	return matched
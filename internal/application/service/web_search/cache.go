@@ -0,0 +1,258 @@
+package web_search
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/redis/go-redis/v9"
+)
+
+// Result cache types
+const (
+	CacheTypeMemory = "memory"
+	CacheTypeRedis  = "redis"
+	CacheTypeNone   = "none"
+)
+
+const (
+	// resultCacheKeyPrefix namespaces the cache entries in Redis
+	resultCacheKeyPrefix = "websearch:cache:"
+	// defaultResultCacheTTL is the lifetime of cached results of providers without their own TTL
+	defaultResultCacheTTL = 10 * time.Minute
+	// maxMemoryCacheEntries bounds the size of the in-memory cache
+	maxMemoryCacheEntries = 1000
+)
+
+// ResultCache caches the raw results of web search providers by provider and normalized query
+type ResultCache interface {
+	// Get returns the cached results of a query, each marked with the time it was cached
+	Get(ctx context.Context, provider, query string, maxResults int, includeDate bool) ([]*types.WebSearchResult, bool)
+	// Set caches the results of a query for the TTL of the provider
+	Set(ctx context.Context, provider, query string, maxResults int, includeDate bool, results []*types.WebSearchResult)
+}
+
+// cachedResults is a cache entry
+type cachedResults struct {
+	Results  []*types.WebSearchResult `json:"results"`
+	CachedAt time.Time                `json:"cached_at"`
+}
+
+// hit returns copies of the cached results marked with the cache time, so callers can modify them
+func (e *cachedResults) hit() []*types.WebSearchResult {
+	results := make([]*types.WebSearchResult, 0, len(e.Results))
+	cachedAt := e.CachedAt
+	for _, result := range e.Results {
+		copied := *result
+		copied.CachedAt = &cachedAt
+		results = append(results, &copied)
+	}
+	return results
+}
+
+// resultCacheTTLs holds the default TTL and the TTLs of individual providers
+type resultCacheTTLs struct {
+	defaultTTL  time.Duration
+	providerTTL map[string]time.Duration
+}
+
+// ttl returns the TTL of a provider, 0 disables caching for it
+func (t resultCacheTTLs) ttl(provider string) time.Duration {
+	if ttl, ok := t.providerTTL[provider]; ok {
+		return ttl
+	}
+	return t.defaultTTL
+}
+
+// NewResultCache creates the web search result cache. WEB_SEARCH_CACHE_TYPE selects memory, redis or
+// none, and defaults to the backend of STREAM_MANAGER_TYPE. WEB_SEARCH_CACHE_TTL sets the lifetime of
+// entries, WEB_SEARCH_CACHE_PROVIDER_TTL overrides it per provider (e.g. "bing=1h,duckduckgo=0").
+func NewResultCache(redisClient *redis.Client) ResultCache {
+	ctx := context.Background()
+	cacheType := strings.ToLower(strings.TrimSpace(os.Getenv("WEB_SEARCH_CACHE_TYPE")))
+	if cacheType == "" {
+		cacheType = CacheTypeMemory
+		if os.Getenv("STREAM_MANAGER_TYPE") == CacheTypeRedis {
+			cacheType = CacheTypeRedis
+		}
+	}
+	ttls, err := parseResultCacheTTLs(os.Getenv("WEB_SEARCH_CACHE_TTL"), os.Getenv("WEB_SEARCH_CACHE_PROVIDER_TTL"))
+	if err != nil {
+		logger.Warnf(ctx, "[WebSearchCache] %v, using default ttl %s", err, defaultResultCacheTTL)
+		ttls = resultCacheTTLs{defaultTTL: defaultResultCacheTTL}
+	}
+
+	switch cacheType {
+	case CacheTypeNone:
+		logger.Infof(ctx, "[WebSearchCache] Web search result cache disabled")
+		return nil
+	case CacheTypeRedis:
+		if redisClient == nil {
+			logger.Warnf(ctx, "[WebSearchCache] Redis client unavailable, using memory cache")
+			break
+		}
+		logger.Infof(ctx, "[WebSearchCache] Redis web search result cache enabled, ttl: %s", ttls.defaultTTL)
+		return &redisResultCache{client: redisClient, ttls: ttls}
+	case CacheTypeMemory:
+	default:
+		logger.Warnf(ctx, "[WebSearchCache] Unknown WEB_SEARCH_CACHE_TYPE %q, using memory cache", cacheType)
+	}
+	logger.Infof(ctx, "[WebSearchCache] Memory web search result cache enabled, ttl: %s", ttls.defaultTTL)
+	return newMemoryResultCache(ttls)
+}
+
+// parseResultCacheTTLs parses the default TTL and the comma-separated provider=duration TTLs
+func parseResultCacheTTLs(defaultTTL, providerTTL string) (resultCacheTTLs, error) {
+	ttls := resultCacheTTLs{defaultTTL: defaultResultCacheTTL, providerTTL: make(map[string]time.Duration)}
+	if defaultTTL = strings.TrimSpace(defaultTTL); defaultTTL != "" {
+		ttl, err := time.ParseDuration(defaultTTL)
+		if err != nil || ttl < 0 {
+			return ttls, fmt.Errorf("invalid WEB_SEARCH_CACHE_TTL %q", defaultTTL)
+		}
+		ttls.defaultTTL = ttl
+	}
+	for _, entry := range strings.Split(providerTTL, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		provider, value, ok := strings.Cut(entry, "=")
+		ttl, err := time.ParseDuration(strings.TrimSpace(value))
+		if !ok || err != nil || ttl < 0 {
+			return ttls, fmt.Errorf("invalid WEB_SEARCH_CACHE_PROVIDER_TTL entry %q", entry)
+		}
+		ttls.providerTTL[strings.TrimSpace(provider)] = ttl
+	}
+	return ttls, nil
+}
+
+// resultCacheKey builds the cache key of a query. Queries differing only in case or whitespace share an entry.
+func resultCacheKey(provider, query string, maxResults int, includeDate bool) string {
+	normalized := strings.Join(strings.Fields(strings.ToLower(query)), " ")
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s|%d|%t|%s", provider, maxResults, includeDate, normalized)))
+	return resultCacheKeyPrefix + provider + ":" + hex.EncodeToString(hash[:16])
+}
+
+// memoryResultCache is a ResultCache kept in process memory
+type memoryResultCache struct {
+	mu      sync.Mutex
+	ttls    resultCacheTTLs
+	entries map[string]*memoryCacheEntry
+}
+
+type memoryCacheEntry struct {
+	cachedResults
+	expiresAt time.Time
+}
+
+func newMemoryResultCache(ttls resultCacheTTLs) *memoryResultCache {
+	return &memoryResultCache{ttls: ttls, entries: make(map[string]*memoryCacheEntry)}
+}
+
+func (c *memoryResultCache) Get(ctx context.Context,
+	provider, query string, maxResults int, includeDate bool,
+) ([]*types.WebSearchResult, bool) {
+	key := resultCacheKey(provider, query, maxResults, includeDate)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.hit(), true
+}
+
+func (c *memoryResultCache) Set(ctx context.Context,
+	provider, query string, maxResults int, includeDate bool, results []*types.WebSearchResult,
+) {
+	ttl := c.ttls.ttl(provider)
+	if ttl <= 0 {
+		return
+	}
+	now := time.Now()
+	entry := &memoryCacheEntry{
+		cachedResults: cachedResults{Results: make([]*types.WebSearchResult, 0, len(results)), CachedAt: now},
+		expiresAt:     now.Add(ttl),
+	}
+	for _, result := range results {
+		copied := *result
+		entry.Results = append(entry.Results, &copied)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= maxMemoryCacheEntries {
+		c.evict(now)
+	}
+	c.entries[resultCacheKey(provider, query, maxResults, includeDate)] = entry
+}
+
+// evict removes expired entries, and the entry expiring first when none has expired
+func (c *memoryResultCache) evict(now time.Time) {
+	var oldestKey string
+	var oldest time.Time
+	for key, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, key)
+			continue
+		}
+		if oldestKey == "" || entry.expiresAt.Before(oldest) {
+			oldestKey, oldest = key, entry.expiresAt
+		}
+	}
+	if len(c.entries) >= maxMemoryCacheEntries {
+		delete(c.entries, oldestKey)
+	}
+}
+
+// redisResultCache is a ResultCache backed by Redis, shared by all instances
+type redisResultCache struct {
+	client *redis.Client
+	ttls   resultCacheTTLs
+}
+
+func (c *redisResultCache) Get(ctx context.Context,
+	provider, query string, maxResults int, includeDate bool,
+) ([]*types.WebSearchResult, bool) {
+	raw, err := c.client.Get(ctx, resultCacheKey(provider, query, maxResults, includeDate)).Bytes()
+	if err != nil {
+		if err != redis.Nil {
+			logger.Warnf(ctx, "[WebSearchCache] Failed to read cache: %v", err)
+		}
+		return nil, false
+	}
+	var entry cachedResults
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		logger.Warnf(ctx, "[WebSearchCache] Ignoring corrupted entry: %v", err)
+		return nil, false
+	}
+	return entry.hit(), true
+}
+
+func (c *redisResultCache) Set(ctx context.Context,
+	provider, query string, maxResults int, includeDate bool, results []*types.WebSearchResult,
+) {
+	ttl := c.ttls.ttl(provider)
+	if ttl <= 0 {
+		return
+	}
+	raw, err := json.Marshal(cachedResults{Results: results, CachedAt: time.Now()})
+	if err != nil {
+		logger.Warnf(ctx, "[WebSearchCache] Failed to encode results: %v", err)
+		return
+	}
+	if err := c.client.Set(ctx, resultCacheKey(provider, query, maxResults, includeDate), raw, ttl).Err(); err != nil {
+		logger.Warnf(ctx, "[WebSearchCache] Failed to write cache: %v", err)
+	}
+}
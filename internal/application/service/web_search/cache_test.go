@@ -0,0 +1,90 @@
+package web_search
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryResultCache(t *testing.T) {
+	ctx := context.Background()
+	cache := newMemoryResultCache(resultCacheTTLs{
+		defaultTTL:  time.Minute,
+		providerTTL: map[string]time.Duration{"nocache": 0, "short": time.Millisecond},
+	})
+	results := []*types.WebSearchResult{{Title: "Result 1", URL: "https://example.com/1"}}
+
+	t.Run("Miss then hit with normalized query", func(t *testing.T) {
+		_, ok := cache.Get(ctx, "bing", "Hello World", 5, false)
+		assert.False(t, ok)
+
+		cache.Set(ctx, "bing", "Hello World", 5, false, results)
+		cached, ok := cache.Get(ctx, "bing", "  hello   world ", 5, false)
+		require.True(t, ok)
+		require.Len(t, cached, 1)
+		assert.Equal(t, "https://example.com/1", cached[0].URL)
+		assert.NotNil(t, cached[0].CachedAt)
+		assert.Nil(t, results[0].CachedAt)
+	})
+
+	t.Run("Key includes provider and options", func(t *testing.T) {
+		_, ok := cache.Get(ctx, "google", "hello world", 5, false)
+		assert.False(t, ok)
+		_, ok = cache.Get(ctx, "bing", "hello world", 10, false)
+		assert.False(t, ok)
+		_, ok = cache.Get(ctx, "bing", "hello world", 5, true)
+		assert.False(t, ok)
+	})
+
+	t.Run("Hits are copies", func(t *testing.T) {
+		cached, ok := cache.Get(ctx, "bing", "hello world", 5, false)
+		require.True(t, ok)
+		cached[0].TrustWeight = 2
+		again, ok := cache.Get(ctx, "bing", "hello world", 5, false)
+		require.True(t, ok)
+		assert.Zero(t, again[0].TrustWeight)
+	})
+
+	t.Run("Provider TTL", func(t *testing.T) {
+		cache.Set(ctx, "nocache", "q", 5, false, results)
+		_, ok := cache.Get(ctx, "nocache", "q", 5, false)
+		assert.False(t, ok)
+
+		cache.Set(ctx, "short", "q", 5, false, results)
+		time.Sleep(5 * time.Millisecond)
+		_, ok = cache.Get(ctx, "short", "q", 5, false)
+		assert.False(t, ok)
+	})
+}
+
+func TestParseResultCacheTTLs(t *testing.T) {
+	testCases := []struct {
+		name        string
+		defaultTTL  string
+		providerTTL string
+		wantDefault time.Duration
+		wantBing    time.Duration
+		wantErr     bool
+	}{
+		{name: "defaults", wantDefault: defaultResultCacheTTL, wantBing: defaultResultCacheTTL},
+		{name: "custom", defaultTTL: "5m", providerTTL: "bing=1h, duckduckgo=0", wantDefault: 5 * time.Minute, wantBing: time.Hour},
+		{name: "invalid default", defaultTTL: "soon", wantErr: true},
+		{name: "invalid provider entry", providerTTL: "bing", wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ttls, err := parseResultCacheTTLs(tc.defaultTTL, tc.providerTTL)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantDefault, ttls.ttl("google"))
+			assert.Equal(t, tc.wantBing, ttls.ttl("bing"))
+		})
+	}
+}
@@ -0,0 +1,274 @@
+package web_search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+)
+
+const defaultJSONAPITimeout = 15 * time.Second
+
+// JSONAPIMapping maps the response of a JSON search API to search results.
+// Paths are JSONPath-style, e.g. "$.data.items" or "meta.links[0].href".
+type JSONAPIMapping struct {
+	// Results is the path of the result array in the response
+	Results string `json:"results"`
+	// Title is the path of the title in a result, defaults to "title"
+	Title string `json:"title"`
+	// URL is the path of the URL in a result, defaults to "url"
+	URL string `json:"url"`
+	// Snippet is the path of the snippet in a result, defaults to "snippet"
+	Snippet string `json:"snippet"`
+	// PublishedAt is the optional path of the publication time in a result
+	PublishedAt string `json:"published_at"`
+}
+
+// JSONAPIProvider implements web search using any search API that answers GET requests with JSON
+type JSONAPIProvider struct {
+	client      *http.Client
+	urlTemplate string
+	headers     map[string]string
+	mapping     JSONAPIMapping
+}
+
+// NewJSONAPIProvider creates a new JSON search API provider from the environment:
+//   - JSON_SEARCH_API_URL: URL template, {query} and {max_results} are replaced by the URL-escaped values
+//   - JSON_SEARCH_API_HEADERS: optional JSON object of request headers
+//   - JSON_SEARCH_API_MAPPING: JSON object of the JSONAPIMapping, "results" is required
+func NewJSONAPIProvider() (interfaces.WebSearchProvider, error) {
+	urlTemplate := os.Getenv("JSON_SEARCH_API_URL")
+	if urlTemplate == "" {
+		return nil, fmt.Errorf("JSON_SEARCH_API_URL is not set")
+	}
+	if !strings.Contains(urlTemplate, "{query}") {
+		return nil, fmt.Errorf("JSON_SEARCH_API_URL must contain {query}")
+	}
+	var headers map[string]string
+	if raw := os.Getenv("JSON_SEARCH_API_HEADERS"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &headers); err != nil {
+			return nil, fmt.Errorf("invalid JSON_SEARCH_API_HEADERS: %w", err)
+		}
+	}
+	var mapping JSONAPIMapping
+	raw := os.Getenv("JSON_SEARCH_API_MAPPING")
+	if raw == "" {
+		return nil, fmt.Errorf("JSON_SEARCH_API_MAPPING is not set")
+	}
+	if err := json.Unmarshal([]byte(raw), &mapping); err != nil {
+		return nil, fmt.Errorf("invalid JSON_SEARCH_API_MAPPING: %w", err)
+	}
+	if mapping.Results == "" {
+		return nil, fmt.Errorf("results path is empty in JSON_SEARCH_API_MAPPING")
+	}
+	if mapping.Title == "" {
+		mapping.Title = "title"
+	}
+	if mapping.URL == "" {
+		mapping.URL = "url"
+	}
+	if mapping.Snippet == "" {
+		mapping.Snippet = "snippet"
+	}
+	return &JSONAPIProvider{
+		client:      &http.Client{Timeout: defaultJSONAPITimeout},
+		urlTemplate: urlTemplate,
+		headers:     headers,
+		mapping:     mapping,
+	}, nil
+}
+
+// JSONAPIProviderInfo returns the provider info for registration
+func JSONAPIProviderInfo() types.WebSearchProviderInfo {
+	return types.WebSearchProviderInfo{
+		ID:             "json_api",
+		Name:           "JSON Search API",
+		Free:           true,
+		RequiresAPIKey: false,
+		Description:    "Generic JSON search API with configurable URL, headers and result mapping",
+	}
+}
+
+// Name returns the provider name
+func (p *JSONAPIProvider) Name() string {
+	return "json_api"
+}
+
+// Search performs a web search using the configured JSON search API
+func (p *JSONAPIProvider) Search(
+	ctx context.Context,
+	query string,
+	maxResults int,
+	includeDate bool,
+) ([]*types.WebSearchResult, error) {
+	if len(query) == 0 {
+		return nil, fmt.Errorf("query is empty")
+	}
+	if maxResults <= 0 {
+		maxResults = 5
+	}
+
+	reqURL := strings.NewReplacer(
+		"{query}", url.QueryEscape(query),
+		"{max_results}", strconv.Itoa(maxResults),
+	).Replace(p.urlTemplate)
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "WeKnora/1.0")
+	for key, value := range p.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to perform request: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("json search API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var respData interface{}
+	if err := json.Unmarshal(body, &respData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	items, ok := lookupJSONPath(respData, p.mapping.Results)
+	if !ok {
+		return nil, fmt.Errorf("results path %s not found in response", p.mapping.Results)
+	}
+	list, ok := items.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("results path %s is not an array", p.mapping.Results)
+	}
+
+	results := make([]*types.WebSearchResult, 0, min(len(list), maxResults))
+	for _, item := range list {
+		if len(results) >= maxResults {
+			break
+		}
+		link := jsonPathString(item, p.mapping.URL)
+		if link == "" {
+			continue
+		}
+		result := &types.WebSearchResult{
+			Title:   jsonPathString(item, p.mapping.Title),
+			URL:     link,
+			Snippet: jsonPathString(item, p.mapping.Snippet),
+			Source:  "json_api",
+		}
+		if p.mapping.PublishedAt != "" {
+			if value, ok := lookupJSONPath(item, p.mapping.PublishedAt); ok {
+				result.PublishedAt = parseResultTime(value)
+			}
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// lookupJSONPath resolves a JSONPath-style path of object keys and array indexes in a decoded
+// JSON value. A leading "$" is optional and a trailing "[*]" selects the whole array.
+func lookupJSONPath(value interface{}, path string) (interface{}, bool) {
+	path = strings.TrimPrefix(strings.TrimSpace(path), "$")
+	path = strings.TrimSuffix(path, "[*]")
+	for _, segment := range strings.Split(path, ".") {
+		if segment == "" {
+			continue
+		}
+		key := segment
+		var indexes []string
+		if i := strings.Index(segment, "["); i >= 0 {
+			key = segment[:i]
+			for _, part := range strings.Split(segment[i+1:], "[") {
+				indexes = append(indexes, strings.TrimSuffix(part, "]"))
+			}
+		}
+		if key != "" {
+			object, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if value, ok = object[key]; !ok {
+				return nil, false
+			}
+		}
+		for _, index := range indexes {
+			array, ok := value.([]interface{})
+			if !ok {
+				return nil, false
+			}
+			i, err := strconv.Atoi(index)
+			if err != nil || i < 0 || i >= len(array) {
+				return nil, false
+			}
+			value = array[i]
+		}
+	}
+	return value, true
+}
+
+// jsonPathString resolves a path to a string, numbers are formatted and other values are empty
+func jsonPathString(value interface{}, path string) string {
+	value, ok := lookupJSONPath(value, path)
+	if !ok {
+		return ""
+	}
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
+
+// resultTimeLayouts are the layouts tried when parsing publication times of results
+var resultTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// parseResultTime parses a publication time of a result, either a date string or a unix
+// timestamp in seconds or milliseconds. Returns nil when it cannot be parsed.
+func parseResultTime(value interface{}) *time.Time {
+	var t time.Time
+	switch v := value.(type) {
+	case string:
+		for _, layout := range resultTimeLayouts {
+			parsed, err := time.Parse(layout, strings.TrimSpace(v))
+			if err == nil {
+				t = parsed
+				break
+			}
+		}
+	case float64:
+		if v > 1e12 {
+			t = time.UnixMilli(int64(v))
+		} else if v > 0 {
+			t = time.Unix(int64(v), 0)
+		}
+	}
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
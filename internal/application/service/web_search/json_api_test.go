@@ -0,0 +1,138 @@
+package web_search
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewJSONAPIProvider(t *testing.T) {
+	testCases := []struct {
+		name    string
+		url     string
+		headers string
+		mapping string
+		wantErr string
+	}{
+		{name: "valid config", url: "http://search/api?q={query}", mapping: `{"results": "items"}`},
+		{name: "missing url", mapping: `{"results": "items"}`, wantErr: "JSON_SEARCH_API_URL is not set"},
+		{name: "url without query", url: "http://search/api", mapping: `{"results": "items"}`, wantErr: "{query}"},
+		{name: "invalid headers", url: "http://search/api?q={query}", headers: "x", mapping: `{"results": "items"}`, wantErr: "JSON_SEARCH_API_HEADERS"},
+		{name: "missing mapping", url: "http://search/api?q={query}", wantErr: "JSON_SEARCH_API_MAPPING is not set"},
+		{name: "missing results path", url: "http://search/api?q={query}", mapping: `{"title": "name"}`, wantErr: "results path is empty"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("JSON_SEARCH_API_URL", tc.url)
+			t.Setenv("JSON_SEARCH_API_HEADERS", tc.headers)
+			t.Setenv("JSON_SEARCH_API_MAPPING", tc.mapping)
+			provider, err := NewJSONAPIProvider()
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "json_api", provider.Name())
+		})
+	}
+}
+
+func TestJSONAPIProvider_Search(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Query().Get("keyword") != "test query" || r.URL.Query().Get("size") != "2" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"data": {
+				"hits": [
+					{"name": "Result 1", "links": [{"href": "https://example.com/1"}], "summary": "Snippet 1", "ts": 1740816000},
+					{"name": "No link", "links": [], "summary": "skipped"},
+					{"name": "Result 2", "links": [{"href": "https://example.com/2"}], "summary": "Snippet 2", "ts": "2025-03-02"},
+					{"name": "Result 3", "links": [{"href": "https://example.com/3"}], "summary": "Snippet 3"}
+				]
+			}
+		}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("JSON_SEARCH_API_URL", server.URL+"/search?keyword={query}&size={max_results}")
+	t.Setenv("JSON_SEARCH_API_HEADERS", `{"Authorization": "Bearer test-token"}`)
+	t.Setenv("JSON_SEARCH_API_MAPPING",
+		`{"results": "$.data.hits[*]", "title": "name", "url": "links[0].href", "snippet": "summary", "published_at": "ts"}`)
+	provider, err := NewJSONAPIProvider()
+	require.NoError(t, err)
+
+	results, err := provider.Search(context.Background(), "test query", 2, false)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "Result 1", results[0].Title)
+	assert.Equal(t, "https://example.com/1", results[0].URL)
+	assert.Equal(t, "Snippet 1", results[0].Snippet)
+	assert.Equal(t, "json_api", results[0].Source)
+	require.NotNil(t, results[0].PublishedAt)
+	assert.Equal(t, time.Unix(1740816000, 0).UTC(), results[0].PublishedAt.UTC())
+	assert.Equal(t, "https://example.com/2", results[1].URL)
+	require.NotNil(t, results[1].PublishedAt)
+	assert.Equal(t, 2, results[1].PublishedAt.Day())
+}
+
+func TestJSONAPIProvider_Search_BadResultsPath(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"hits": {"name": "not an array"}}}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("JSON_SEARCH_API_URL", server.URL+"?q={query}")
+	t.Setenv("JSON_SEARCH_API_MAPPING", `{"results": "data.hits"}`)
+	provider, err := NewJSONAPIProvider()
+	require.NoError(t, err)
+
+	results, err := provider.Search(context.Background(), "test query", 5, false)
+	assert.Error(t, err)
+	assert.Nil(t, results)
+	assert.Contains(t, err.Error(), "is not an array")
+}
+
+func TestLookupJSONPath(t *testing.T) {
+	doc := map[string]interface{}{
+		"data": map[string]interface{}{
+			"items": []interface{}{
+				map[string]interface{}{"title": "first"},
+				[]interface{}{"nested"},
+			},
+		},
+	}
+	testCases := []struct {
+		path   string
+		want   interface{}
+		wantOK bool
+	}{
+		{"$.data.items[0].title", "first", true},
+		{"data.items[1][0]", "nested", true},
+		{"data.items[2]", nil, false},
+		{"data.missing", nil, false},
+		{"data.items.title", nil, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			got, ok := lookupJSONPath(doc, tc.path)
+			assert.Equal(t, tc.wantOK, ok)
+			if tc.wantOK {
+				assert.Equal(t, tc.want, got)
+			}
+		})
+	}
+}
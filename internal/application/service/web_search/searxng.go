@@ -0,0 +1,140 @@
+package web_search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+)
+
+const defaultSearXNGTimeout = 15 * time.Second
+
+// SearXNGProvider implements web search using a self-hosted SearXNG instance.
+// The instance must have the json format enabled in search.formats of its settings.
+type SearXNGProvider struct {
+	client  *http.Client
+	baseURL *url.URL
+}
+
+// NewSearXNGProvider creates a new SearXNG provider. SEARXNG_URL is the URL of the instance,
+// query parameters in it (e.g. engines, language) are sent with every search.
+func NewSearXNGProvider() (interfaces.WebSearchProvider, error) {
+	rawURL := os.Getenv("SEARXNG_URL")
+	if rawURL == "" {
+		return nil, fmt.Errorf("SEARXNG_URL is not set")
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SEARXNG_URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("SEARXNG_URL must be an http or https URL")
+	}
+	if !strings.HasSuffix(u.Path, "/search") {
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/search"
+	}
+	return &SearXNGProvider{
+		client:  &http.Client{Timeout: defaultSearXNGTimeout},
+		baseURL: u,
+	}, nil
+}
+
+// SearXNGProviderInfo returns the provider info for registration
+func SearXNGProviderInfo() types.WebSearchProviderInfo {
+	return types.WebSearchProviderInfo{
+		ID:             "searxng",
+		Name:           "SearXNG",
+		Free:           true,
+		RequiresAPIKey: false,
+		Description:    "Self-hosted SearXNG metasearch engine",
+	}
+}
+
+// Name returns the provider name
+func (p *SearXNGProvider) Name() string {
+	return "searxng"
+}
+
+// Search performs a web search using the SearXNG JSON API
+func (p *SearXNGProvider) Search(
+	ctx context.Context,
+	query string,
+	maxResults int,
+	includeDate bool,
+) ([]*types.WebSearchResult, error) {
+	if len(query) == 0 {
+		return nil, fmt.Errorf("query is empty")
+	}
+	if maxResults <= 0 {
+		maxResults = 5
+	}
+
+	reqURL := *p.baseURL
+	params := reqURL.Query()
+	params.Set("q", query)
+	params.Set("format", "json")
+	reqURL.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "WeKnora/1.0")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to perform request: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		// SearXNG answers 403 when the json format is not enabled
+		return nil, fmt.Errorf("searxng returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var respData searxngSearchResponse
+	if err := json.Unmarshal(body, &respData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	results := make([]*types.WebSearchResult, 0, min(len(respData.Results), maxResults))
+	for _, item := range respData.Results {
+		if len(results) >= maxResults {
+			break
+		}
+		if item.URL == "" {
+			continue
+		}
+		results = append(results, &types.WebSearchResult{
+			Title:       item.Title,
+			URL:         item.URL,
+			Snippet:     item.Content,
+			Source:      "searxng",
+			PublishedAt: parseResultTime(item.PublishedDate),
+		})
+	}
+	return results, nil
+}
+
+// searxngSearchResponse defines the response structure of the SearXNG JSON API
+type searxngSearchResponse struct {
+	Query   string `json:"query"`
+	Results []struct {
+		Title         string      `json:"title"`
+		URL           string      `json:"url"`
+		Content       string      `json:"content"`
+		Engine        string      `json:"engine"`
+		PublishedDate interface{} `json:"publishedDate"`
+	} `json:"results"`
+}
@@ -0,0 +1,101 @@
+package web_search
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSearXNGProvider(t *testing.T) {
+	testCases := []struct {
+		name    string
+		url     string
+		wantErr string
+	}{
+		{name: "valid url", url: "http://searxng:8080"},
+		{name: "missing url", url: "", wantErr: "SEARXNG_URL is not set"},
+		{name: "invalid scheme", url: "ftp://searxng", wantErr: "http or https"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("SEARXNG_URL", tc.url)
+			provider, err := NewSearXNGProvider()
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "searxng", provider.Name())
+		})
+	}
+}
+
+func TestSearXNGProvider_Search(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/searxng/search" || r.URL.Query().Get("format") != "json" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.Query().Get("engines") != "wikipedia" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"query": "` + r.URL.Query().Get("q") + `",
+			"results": [
+				{"title": "Result 1", "url": "https://example.com/1", "content": "Snippet 1",
+				 "engine": "wikipedia", "publishedDate": "2025-03-01T08:00:00"},
+				{"title": "No URL", "url": "", "content": "skipped"},
+				{"title": "Result 2", "url": "https://example.com/2", "content": "Snippet 2", "publishedDate": null},
+				{"title": "Result 3", "url": "https://example.com/3", "content": "Snippet 3"}
+			]
+		}`))
+	}))
+	defer server.Close()
+
+	t.Setenv("SEARXNG_URL", server.URL+"/searxng/?engines=wikipedia")
+	provider, err := NewSearXNGProvider()
+	require.NoError(t, err)
+
+	t.Run("Successful search", func(t *testing.T) {
+		results, err := provider.Search(context.Background(), "test query", 2, false)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.Equal(t, "Result 1", results[0].Title)
+		assert.Equal(t, "https://example.com/1", results[0].URL)
+		assert.Equal(t, "Snippet 1", results[0].Snippet)
+		assert.Equal(t, "searxng", results[0].Source)
+		require.NotNil(t, results[0].PublishedAt)
+		assert.Equal(t, 2025, results[0].PublishedAt.Year())
+		assert.Equal(t, "https://example.com/2", results[1].URL)
+		assert.Nil(t, results[1].PublishedAt)
+	})
+
+	t.Run("Empty query", func(t *testing.T) {
+		results, err := provider.Search(context.Background(), "", 2, false)
+		assert.Error(t, err)
+		assert.Nil(t, results)
+	})
+}
+
+func TestSearXNGProvider_Search_JSONDisabled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	t.Setenv("SEARXNG_URL", server.URL)
+	provider, err := NewSearXNGProvider()
+	require.NoError(t, err)
+
+	results, err := provider.Search(context.Background(), "test query", 5, false)
+	assert.Error(t, err)
+	assert.Nil(t, results)
+	assert.Contains(t, err.Error(), "status 403")
+}
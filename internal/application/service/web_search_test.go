@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Tencent/WeKnora/internal/application/service/web_search"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+)
+
+func webResults(urls ...string) []*types.WebSearchResult {
+	results := make([]*types.WebSearchResult, 0, len(urls))
+	for _, u := range urls {
+		results = append(results, &types.WebSearchResult{URL: u})
+	}
+	return results
+}
+
+func resultURLs(results []*types.WebSearchResult) string {
+	urls := make([]string, 0, len(results))
+	for _, r := range results {
+		urls = append(urls, r.URL)
+	}
+	return strings.Join(urls, ",")
+}
+
+func TestWebSearchFilterAllowlist(t *testing.T) {
+	s := &WebSearchService{}
+	results := webResults(
+		"https://docs.example.com/guide",
+		"https://example.com.evil.net/",
+		"https://vendor.io/kb/1",
+		"https://other.org/",
+	)
+
+	tests := []struct {
+		name      string
+		allowlist []string
+		want      string
+	}{
+		{"no rules keeps all", nil, resultURLs(results)},
+		{"plain domain matches subdomains", []string{"example.com"}, "https://docs.example.com/guide"},
+		{"pattern rule", []string{"*://vendor.io/*"}, "https://vendor.io/kb/1"},
+		{"regex rule", []string{`/\.(org|io)\//`}, "https://vendor.io/kb/1,https://other.org/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resultURLs(s.filterAllowlist(results, tt.allowlist)); got != tt.want {
+				t.Errorf("filterAllowlist() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebSearchApplyTrustWeights(t *testing.T) {
+	s := &WebSearchService{}
+
+	tests := []struct {
+		name    string
+		urls    []string
+		weights map[string]float64
+		want    string
+	}{
+		{
+			"no weights keeps order",
+			[]string{"https://a.com", "https://b.com"},
+			nil,
+			"https://a.com,https://b.com",
+		},
+		{
+			"boost moves trusted domain up",
+			[]string{"https://a.com", "https://b.com", "https://docs.trusted.com"},
+			map[string]float64{"trusted.com": 2},
+			"https://docs.trusted.com,https://a.com,https://b.com",
+		},
+		{
+			"demote and drop",
+			[]string{"https://spam.com", "https://blog.vendor.io", "https://a.com"},
+			map[string]float64{"spam.com": 0, "vendor.io": 0.5},
+			"https://a.com,https://blog.vendor.io",
+		},
+		{
+			"most specific domain wins",
+			[]string{"https://a.com", "https://docs.vendor.io"},
+			map[string]float64{"vendor.io": 0.1, "docs.vendor.io": 3},
+			"https://docs.vendor.io,https://a.com",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resultURLs(s.applyTrustWeights(webResults(tt.urls...), tt.weights)); got != tt.want {
+				t.Errorf("applyTrustWeights() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebSearchConfigWithOverrides(t *testing.T) {
+	cfg := types.WebSearchConfig{
+		Allowlist:    []string{"tenant.com"},
+		TrustWeights: map[string]float64{"a.com": 2, "b.com": 0.5},
+	}
+
+	overridden := cfg.WithOverrides([]string{"agent.com"}, map[string]float64{"b.com": 3})
+	if got := strings.Join(overridden.Allowlist, ","); got != "agent.com" {
+		t.Errorf("Allowlist = %s, want agent.com", got)
+	}
+	if overridden.TrustWeights["a.com"] != 2 || overridden.TrustWeights["b.com"] != 3 {
+		t.Errorf("TrustWeights = %v, want a.com=2 b.com=3", overridden.TrustWeights)
+	}
+	if cfg.TrustWeights["b.com"] != 0.5 {
+		t.Errorf("tenant TrustWeights modified: %v", cfg.TrustWeights)
+	}
+
+	if kept := cfg.WithOverrides(nil, nil); strings.Join(kept.Allowlist, ",") != "tenant.com" {
+		t.Errorf("Allowlist = %v, want tenant.com", kept.Allowlist)
+	}
+}
+
+// countingProvider is a web search provider that counts its searches
+type countingProvider struct {
+	calls int
+}
+
+func (p *countingProvider) Name() string { return "counting" }
+
+func (p *countingProvider) Search(
+	ctx context.Context, query string, maxResults int, includeDate bool,
+) ([]*types.WebSearchResult, error) {
+	p.calls++
+	return webResults("https://example.com/" + query), nil
+}
+
+func TestWebSearchServiceCache(t *testing.T) {
+	t.Setenv("WEB_SEARCH_CACHE_TYPE", web_search.CacheTypeMemory)
+	provider := &countingProvider{}
+	s := &WebSearchService{
+		providers: map[string]interfaces.WebSearchProvider{"counting": provider},
+		timeout:   1,
+		cache:     web_search.NewResultCache(nil),
+	}
+	cfg := &types.WebSearchConfig{Provider: "counting", MaxResults: 5}
+	ctx := context.Background()
+
+	first, err := s.Search(ctx, cfg, "weknora")
+	if err != nil || len(first) != 1 || first[0].CachedAt != nil {
+		t.Fatalf("first search = %v, %v, want one fresh result", first, err)
+	}
+	second, err := s.Search(ctx, cfg, "  WeKnora ")
+	if err != nil || len(second) != 1 || second[0].CachedAt == nil {
+		t.Fatalf("second search = %v, %v, want one cached result", second, err)
+	}
+	if provider.calls != 1 {
+		t.Errorf("provider calls = %d, want 1", provider.calls)
+	}
+
+	bypass := *cfg
+	bypass.BypassCache = true
+	fresh, err := s.Search(ctx, &bypass, "weknora")
+	if err != nil || len(fresh) != 1 || fresh[0].CachedAt != nil {
+		t.Fatalf("bypass search = %v, %v, want one fresh result", fresh, err)
+	}
+	if provider.calls != 2 {
+		t.Errorf("provider calls = %d, want 2", provider.calls)
+	}
+}
@@ -0,0 +1,417 @@
+// Package chunker splits plain text and Markdown into chunks natively in Go,
+// following the behavior of the docreader TextSplitter so both produce compatible chunks.
+package chunker
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultChunkSize is the chunk size used when the config has none, same as docreader
+	DefaultChunkSize = 512
+	// DefaultChunkOverlap is the chunk overlap used when the config has none, same as docreader
+	DefaultChunkOverlap = 50
+)
+
+// DefaultSeparators are the separators used when the config has none, same as docreader
+var DefaultSeparators = []string{"\n\n", "\n", "。"}
+
+// protectedPatterns match content that is kept in one piece when it fits in a chunk
+var protectedPatterns = []*regexp.Regexp{
+	// LaTeX formula enclosed in $$
+	regexp.MustCompile(`\$\$[\s\S]*?\$\$`),
+	// Markdown image ![alt](url)
+	regexp.MustCompile(`!\[.*?\]\(.*?\)`),
+	// Markdown link [text](url)
+	regexp.MustCompile(`\[.*?\]\(.*?\)`),
+	// Markdown table header with its separator line
+	regexp.MustCompile(`[ ]*(?:\|[^|\n]*)+\|[\r\n]+\s*(?:\|\s*:?-{3,}:?\s*)+\|[\r\n]+`),
+	// Markdown table row
+	regexp.MustCompile(`[ ]*(?:\|[^|\n]*)+\|[\r\n]+`),
+	// Code block start with its language
+	regexp.MustCompile("```(?:\\w+)[\\r\\n]+[^\\r\\n]*"),
+}
+
+// headingPattern matches a Markdown ATX heading line
+var headingPattern = regexp.MustCompile(`(?m)^(#{1,6})[ \t]+(.+?)[ \t]*#*[ \t]*$`)
+
+// TokenSafetyPercent is the share of ChunkSize filled when sizes count estimated tokens. The rest is a
+// margin for texts the estimate undercounts, keeping chunks within the token limit of the embedding model.
+// Tokens counted with the tokenizer of the model are exact and fill the whole ChunkSize.
+const TokenSafetyPercent = 85
+
+// Config configures the splitter. Sizes are counted in characters (runes) unless CountTokens is set.
+type Config struct {
+	// ChunkSize is the max size of a chunk
+	ChunkSize int
+	// ChunkOverlap is the max size of the content repeated from the previous chunk
+	ChunkOverlap int
+	// Separators are tried in order to split the text, characters are used as the last resort
+	Separators []string
+	// Markdown prepends the enclosing headings to chunks that start in the middle of a section
+	Markdown bool
+	// CountTokens measures ChunkSize and ChunkOverlap in tokens instead of characters
+	CountTokens bool
+	// Tokenizer counts the tokens when CountTokens is set. Without it tokens are estimated with
+	// EstimateTokens, filling TokenSafetyPercent of ChunkSize
+	Tokenizer TokenCounter
+	// Sentences splits the text into sentences first, so chunks only break between sentences when they fit
+	Sentences bool
+}
+
+// Chunk is a piece of the split text. Start and End are character offsets in the original text,
+// compatible with proto.Chunk. A prepended heading is part of Content but not of the offsets.
+type Chunk struct {
+	Content string
+	Seq     int
+	Start   int
+	End     int
+}
+
+// withDefaults fills the unset fields with the docreader defaults
+func (c Config) withDefaults() Config {
+	if c.ChunkSize <= 0 {
+		c.ChunkSize = DefaultChunkSize
+	}
+	if c.CountTokens && c.Tokenizer == nil {
+		c.ChunkSize = max(1, c.ChunkSize*TokenSafetyPercent/100)
+	}
+	if c.ChunkOverlap < 0 {
+		c.ChunkOverlap = 0
+	}
+	if c.ChunkOverlap == 0 && c.ChunkSize > DefaultChunkOverlap {
+		c.ChunkOverlap = DefaultChunkOverlap
+	}
+	if c.ChunkOverlap > c.ChunkSize {
+		c.ChunkOverlap = c.ChunkSize
+	}
+	if len(c.Separators) == 0 {
+		c.Separators = DefaultSeparators
+	}
+	return c
+}
+
+// Split splits the text into chunks of at most ChunkSize characters with overlap
+func Split(text string, cfg Config) []Chunk {
+	if text == "" {
+		return nil
+	}
+	s := &splitter{cfg: cfg.withDefaults()}
+	return s.merge(s.splits(text), nil)
+}
+
+// IsNativeFileType reports whether the file type is plain text or Markdown that can be split in Go
+func IsNativeFileType(fileType string) bool {
+	switch strings.ToLower(strings.TrimPrefix(fileType, ".")) {
+	case "txt", "text", "md", "markdown":
+		return true
+	}
+	return false
+}
+
+// IsMarkdownFileType reports whether the file type is Markdown
+func IsMarkdownFileType(fileType string) bool {
+	switch strings.ToLower(strings.TrimPrefix(fileType, ".")) {
+	case "md", "markdown":
+		return true
+	}
+	return false
+}
+
+type splitter struct {
+	cfg Config
+}
+
+// size measures a text against ChunkSize and ChunkOverlap
+func (s *splitter) size(text string) int {
+	if s.cfg.CountTokens {
+		return s.cfg.Tokenizer.Count(text)
+	}
+	return length(text)
+}
+
+// splits breaks the text into the splits merged into chunks, with protected matches kept whole
+func (s *splitter) splits(text string) []string {
+	var splits []string
+	if s.cfg.Sentences {
+		for _, sentence := range splitSentences(text) {
+			splits = append(splits, s.split(sentence)...)
+		}
+	} else {
+		splits = s.split(text)
+	}
+	return s.join(splits, s.protected(text))
+}
+
+// piece is a part of a chunk, headings prepended for context have Start == End
+type piece struct {
+	start, end int
+	text       string
+}
+
+// length returns the size of a text in characters
+func length(text string) int {
+	return utf8.RuneCountInString(text)
+}
+
+// split recursively breaks the text into splits no longer than the chunk size.
+// Each separator is kept at the start of the split following it, so joining the splits restores the text.
+func (s *splitter) split(text string) []string {
+	if s.size(text) <= s.cfg.ChunkSize {
+		return []string{text}
+	}
+
+	var splits []string
+	for _, sep := range s.cfg.Separators {
+		if sep == "" {
+			continue
+		}
+		splits = splitKeepSeparator(text, sep)
+		if len(splits) > 1 {
+			break
+		}
+	}
+	if len(splits) <= 1 {
+		splits = splitChars(text)
+	}
+
+	result := make([]string, 0, len(splits))
+	for _, split := range splits {
+		if s.size(split) <= s.cfg.ChunkSize {
+			result = append(result, split)
+		} else {
+			result = append(result, s.split(split)...)
+		}
+	}
+	return result
+}
+
+// splitKeepSeparator splits the text by sep, keeping sep at the start of every part but the first
+func splitKeepSeparator(text, sep string) []string {
+	parts := strings.Split(text, sep)
+	result := make([]string, 0, len(parts))
+	for i, part := range parts {
+		if i > 0 {
+			part = sep + part
+		}
+		if part != "" {
+			result = append(result, part)
+		}
+	}
+	return result
+}
+
+// splitChars splits the text into single characters
+func splitChars(text string) []string {
+	result := make([]string, 0, len(text))
+	for _, r := range text {
+		result = append(result, string(r))
+	}
+	return result
+}
+
+// protectedSpan is a protected match, start is a character offset
+type protectedSpan struct {
+	start int
+	text  string
+}
+
+// protected returns the non-overlapping protected matches that fit in a chunk, ordered by position
+func (s *splitter) protected(text string) []protectedSpan {
+	type match struct{ start, end int }
+	var matches []match
+	for _, pattern := range protectedPatterns {
+		for _, loc := range pattern.FindAllStringIndex(text, -1) {
+			matches = append(matches, match{loc[0], loc[1]})
+		}
+	}
+	// Earlier first, then longer first, so overlapped matches are dropped
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].start != matches[j].start {
+			return matches[i].start < matches[j].start
+		}
+		return matches[i].end > matches[j].end
+	})
+
+	var spans []protectedSpan
+	furthest := -1
+	for _, m := range matches {
+		if m.start >= furthest {
+			matched := text[m.start:m.end]
+			if s.size(matched) < s.cfg.ChunkSize {
+				spans = append(spans, protectedSpan{start: length(text[:m.start]), text: matched})
+			}
+		}
+		if m.end > furthest {
+			furthest = m.end
+		}
+	}
+	return spans
+}
+
+// join re-cuts the splits so that every protected match is a split of its own
+func (s *splitter) join(splits []string, protect []protectedSpan) []string {
+	j := 0
+	point, start := 0, 0
+	var result []string
+	for _, split := range splits {
+		end := start + length(split)
+		runes := []rune(split)
+		// A protected match may extend over several splits
+		cur := runes[min(point-start, len(runes)):]
+
+		for j < len(protect) {
+			pStart, pText := protect[j].start, protect[j].text
+			pEnd := pStart + length(pText)
+			if end <= pStart {
+				break
+			}
+			// Content before the protected match
+			if point < pStart {
+				localEnd := pStart - point
+				result = append(result, string(cur[:localEnd]))
+				cur = cur[localEnd:]
+				point = pStart
+			}
+			result = append(result, pText)
+			j++
+			// Skip the part of the split covered by the protected match
+			if point < pEnd {
+				localStart := min(pEnd-point, len(cur))
+				cur = cur[localStart:]
+				point = pEnd
+			}
+			if len(cur) == 0 {
+				break
+			}
+		}
+
+		if len(cur) > 0 {
+			result = append(result, string(cur))
+			point = end
+		}
+		start = end
+	}
+	return result
+}
+
+// headingTracker tracks the Markdown headings enclosing the current position
+type headingTracker struct {
+	levels   []int
+	headings []string
+}
+
+// context returns the enclosing headings of a split, one per line, excluding the headings
+// at or below the level of a heading the split starts with
+func (h *headingTracker) context(split string) string {
+	maxLevel := 7
+	if loc := headingPattern.FindStringSubmatchIndex(split); loc != nil && strings.TrimSpace(split[:loc[0]]) == "" {
+		maxLevel = loc[3] - loc[2]
+	}
+	var lines []string
+	for i, level := range h.levels {
+		if level < maxLevel {
+			lines = append(lines, h.headings[i])
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// update records the headings of the split
+func (h *headingTracker) update(split string) {
+	for _, m := range headingPattern.FindAllStringSubmatch(split, -1) {
+		level := len(m[1])
+		keep := 0
+		for keep < len(h.levels) && h.levels[keep] < level {
+			keep++
+		}
+		h.levels = append(h.levels[:keep], level)
+		h.headings = append(h.headings[:keep], strings.TrimSpace(m[0]))
+	}
+}
+
+// merge combines the splits into chunks of at most the chunk size, each chunk starting with
+// up to ChunkOverlap of the previous one. A split in breaks starts a new chunk without overlap.
+func (s *splitter) merge(splits []string, breaks map[int]bool) []Chunk {
+	var chunks []Chunk
+	var cur []piece
+	var tracker headingTracker
+	curLen, curStart := 0, 0
+
+	emit := func() {
+		var content strings.Builder
+		for _, p := range cur {
+			content.WriteString(p.text)
+		}
+		chunks = append(chunks, Chunk{
+			Content: content.String(),
+			Seq:     len(chunks),
+			Start:   cur[0].start,
+			End:     cur[len(cur)-1].end,
+		})
+	}
+
+	for i, split := range splits {
+		curEnd := curStart + length(split)
+		splitLen := s.size(split)
+
+		headings := ""
+		if s.cfg.Markdown {
+			headings = tracker.context(split)
+			tracker.update(split)
+		}
+		headingsLen := s.size(headings)
+		if headingsLen > s.cfg.ChunkSize {
+			headings, headingsLen = "", 0
+		}
+
+		if breaks[i] && len(cur) > 0 {
+			emit()
+			cur, curLen = nil, 0
+		}
+
+		// Headings only take room in a new chunk, the current one already has its context
+		if len(cur) == 0 || curLen+splitLen > s.cfg.ChunkSize {
+			if len(cur) > 0 {
+				emit()
+			}
+
+			// Drop pieces from the front until what remains fits in the overlap and leaves room for the split
+			for len(cur) > 0 && (curLen > s.cfg.ChunkOverlap || curLen+splitLen+headingsLen > s.cfg.ChunkSize) {
+				first := cur[0]
+				cur = cur[1:]
+				curLen -= s.size(first.text)
+				// A dropped heading is followed by the content it introduced, drop it as well
+				if len(cur) > 0 && first.start == first.end {
+					curLen -= s.size(cur[0].text)
+					cur = cur[1:]
+				}
+			}
+
+			// Start the new chunk with its enclosing headings
+			if headings != "" && splitLen+headingsLen < s.cfg.ChunkSize && !strings.Contains(split, headings) {
+				nextStart := curStart
+				if len(cur) > 0 {
+					nextStart = cur[0].start
+				}
+				cur = append([]piece{{start: nextStart, end: nextStart, text: headings}}, cur...)
+				curLen += headingsLen
+			}
+		}
+
+		cur = append(cur, piece{start: curStart, end: curEnd, text: split})
+		curLen += splitLen
+		curStart = curEnd
+	}
+
+	if len(cur) > 0 {
+		emit()
+	}
+	return chunks
+}
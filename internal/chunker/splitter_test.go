@@ -0,0 +1,93 @@
+package chunker
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		cfg  Config
+		want []Chunk
+	}{
+		{
+			name: "short text is one chunk",
+			text: "hello world",
+			cfg:  Config{ChunkSize: 20, ChunkOverlap: 5},
+			want: []Chunk{{Content: "hello world", Seq: 0, Start: 0, End: 11}},
+		},
+		{
+			name: "paragraphs with overlap",
+			text: "aaaa\nbbbb\ncccc",
+			cfg:  Config{ChunkSize: 10, ChunkOverlap: 5, Separators: []string{"\n"}},
+			want: []Chunk{
+				{Content: "aaaa\nbbbb", Seq: 0, Start: 0, End: 9},
+				{Content: "\nbbbb\ncccc", Seq: 1, Start: 4, End: 14},
+			},
+		},
+		{
+			name: "offsets count characters",
+			text: "你好世界。再见世界。",
+			cfg:  Config{ChunkSize: 5, ChunkOverlap: 1, Separators: []string{"。"}},
+			want: []Chunk{
+				{Content: "你好世界", Seq: 0, Start: 0, End: 4},
+				{Content: "。再见世界", Seq: 1, Start: 4, End: 9},
+				{Content: "。", Seq: 2, Start: 9, End: 10},
+			},
+		},
+		{
+			name: "markdown headings are prepended",
+			text: "# Guide\n## Install\nstep one\nstep two",
+			cfg:  Config{ChunkSize: 30, ChunkOverlap: 1, Separators: []string{"\n"}, Markdown: true},
+			want: []Chunk{
+				{Content: "# Guide\n## Install\nstep one", Seq: 0, Start: 0, End: 27},
+				{Content: "# Guide\n## Install\n\nstep two", Seq: 1, Start: 27, End: 36},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Split(tt.text, tt.cfg)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d chunks %q, want %d", len(got), got, len(tt.want))
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("chunk %d: got %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSplitInvariants(t *testing.T) {
+	text := strings.Repeat("Some text with a [link](http://example.com/page) inside.\n", 30) +
+		"| a | b |\n| --- | --- |\n| 1 | 2 |\n" +
+		strings.Repeat("更多的中文内容，用于测试。", 40)
+	runes := []rune(text)
+	cfg := Config{ChunkSize: 100, ChunkOverlap: 20}
+
+	chunks := Split(text, cfg)
+	end := 0
+	for i, chunk := range chunks {
+		if n := utf8.RuneCountInString(chunk.Content); n > cfg.ChunkSize {
+			t.Errorf("chunk %d has %d characters", i, n)
+		}
+		if chunk.Content != string(runes[chunk.Start:chunk.End]) {
+			t.Errorf("chunk %d content does not match its offsets", i)
+		}
+		if strings.Contains(chunk.Content, "[link](") && !strings.Contains(chunk.Content, "[link](http://example.com/page)") {
+			t.Errorf("chunk %d splits a protected link: %q", i, chunk.Content)
+		}
+		if chunk.Start > end {
+			t.Errorf("chunk %d leaves a gap at %d", i, end)
+		}
+		end = chunk.End
+	}
+	if end != len(runes) {
+		t.Errorf("chunks end at %d, want %d", end, len(runes))
+	}
+}
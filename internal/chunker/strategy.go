@@ -0,0 +1,172 @@
+package chunker
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// defaultBreakpointPercentile breaks semantic chunks at the most dissimilar tenth of adjacent sentences
+const defaultBreakpointPercentile = 0.9
+
+// sentenceClosers are the quotes and brackets that may close a sentence after its end mark
+const sentenceClosers = `"'”’」』)）`
+
+// EmbedFunc embeds the texts, returning one vector per text
+type EmbedFunc func(ctx context.Context, texts []string) ([][]float32, error)
+
+// EstimateTokens estimates the number of tokens of a text for BPE tokenizers used by embedding models:
+// a Latin word counts a token per four characters, a CJK or Hangul character or a punctuation mark
+// counts one token, whitespace is free. It is the fallback for embedding models without a known
+// tokenizer (see ModelTokenCounter), so token chunk sizes estimated with it keep a margin of TokenSafetyPercent.
+func EstimateTokens(text string) int {
+	tokens, word := 0, 0
+	flush := func() {
+		tokens += (word + 3) / 4
+		word = 0
+	}
+	for _, r := range text {
+		switch {
+		case unicode.IsSpace(r):
+			flush()
+		case unicode.In(r, unicode.Han, unicode.Hangul, unicode.Hiragana, unicode.Katakana):
+			flush()
+			tokens++
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r):
+			word++
+		default:
+			flush()
+			tokens++
+		}
+	}
+	flush()
+	return tokens
+}
+
+// isSentenceEnd reports whether the rune ends a sentence. Full-width marks end a sentence by themselves,
+// ASCII marks only when followed by whitespace, so decimals and abbreviations inside words are kept.
+func isSentenceEnd(r rune) (end bool, needsSpace bool) {
+	switch r {
+	case '。', '！', '？', '…':
+		return true, false
+	case '.', '!', '?':
+		return true, true
+	}
+	return false, false
+}
+
+// splitSentences splits the text after sentence ends and line breaks, keeping the trailing
+// whitespace with the sentence, so joining the sentences restores the text
+func splitSentences(text string) []string {
+	runes := []rune(text)
+	var sentences []string
+	start := 0
+	for i := 0; i < len(runes); i++ {
+		end, needsSpace := isSentenceEnd(runes[i])
+		if runes[i] != '\n' && !end {
+			continue
+		}
+		j := i + 1
+		// Closing quotes and repeated marks belong to the sentence
+		for end && j < len(runes) {
+			if next, _ := isSentenceEnd(runes[j]); !next && !strings.ContainsRune(sentenceClosers, runes[j]) {
+				break
+			}
+			j++
+		}
+		if needsSpace && j < len(runes) && !unicode.IsSpace(runes[j]) {
+			continue
+		}
+		for j < len(runes) && unicode.IsSpace(runes[j]) {
+			j++
+		}
+		sentences = append(sentences, string(runes[start:j]))
+		start = j
+		i = j - 1
+	}
+	if start < len(runes) {
+		sentences = append(sentences, string(runes[start:]))
+	}
+	return sentences
+}
+
+// SplitSemantic splits the text into chunks of whole sentences, starting a new chunk where the
+// embedding similarity of adjacent sentences drops below threshold. A threshold of zero breaks at
+// the most dissimilar tenth of adjacent sentences. Chunks still hold at most ChunkSize.
+func SplitSemantic(ctx context.Context, text string, cfg Config, threshold float64, embed EmbedFunc) ([]Chunk, error) {
+	if text == "" {
+		return nil, nil
+	}
+	cfg.Sentences = true
+	s := &splitter{cfg: cfg.withDefaults()}
+	splits := s.splits(text)
+
+	// Blank splits are not embedded and never start a chunk
+	var texts []string
+	var indexes []int
+	for i, split := range splits {
+		if strings.TrimSpace(split) != "" {
+			texts = append(texts, split)
+			indexes = append(indexes, i)
+		}
+	}
+	if len(texts) < 2 {
+		return s.merge(splits, nil), nil
+	}
+	vectors, err := embed(ctx, texts)
+	if err != nil {
+		return nil, err
+	}
+	if len(vectors) != len(texts) {
+		return nil, fmt.Errorf("got %d embeddings for %d sentences", len(vectors), len(texts))
+	}
+
+	breaks := make(map[int]bool)
+	for j, isBreak := range semanticBreaks(vectors, threshold) {
+		if isBreak {
+			breaks[indexes[j+1]] = true
+		}
+	}
+	return s.merge(splits, breaks), nil
+}
+
+// semanticBreaks reports for each pair of adjacent vectors whether the second starts a new chunk
+func semanticBreaks(vectors [][]float32, threshold float64) []bool {
+	similarities := make([]float64, len(vectors)-1)
+	for i := range similarities {
+		similarities[i] = CosineSimilarity(vectors[i], vectors[i+1])
+	}
+	if threshold <= 0 {
+		sorted := append([]float64(nil), similarities...)
+		sort.Float64s(sorted)
+		threshold = sorted[int(float64(len(sorted)-1)*(1-defaultBreakpointPercentile))]
+		// All pairs equally similar, nothing stands out as a topic change
+		if threshold == sorted[len(sorted)-1] {
+			return make([]bool, len(similarities))
+		}
+		// Break at the pairs at or below the percentile
+		threshold = math.Nextafter(threshold, math.Inf(1))
+	}
+	breaks := make([]bool, len(similarities))
+	for i, similarity := range similarities {
+		breaks[i] = similarity < threshold
+	}
+	return breaks
+}
+
+// CosineSimilarity returns the cosine similarity of two embedding vectors, zero for a zero vector
+func CosineSimilarity(a, b []float32) float64 {
+	var dot, normA, normB float64
+	for i := range min(len(a), len(b)) {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(normA*normB)
+}
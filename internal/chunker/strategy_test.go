@@ -0,0 +1,115 @@
+package chunker
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"", 0},
+		{"hello world", 4},
+		{"안녕하세요", 5},
+		{"你好，世界", 5},
+		{"version 3.14", 5},
+	}
+	for _, tt := range tests {
+		if got := EstimateTokens(tt.text); got != tt.want {
+			t.Errorf("EstimateTokens(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestSplitSentences(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{
+			name: "ascii marks need whitespace",
+			text: "Pi is 3.14. Really?\"Yes\" it is! ok",
+			want: []string{"Pi is 3.14. ", "Really?\"Yes\" it is! ", "ok"},
+		},
+		{
+			name: "korean and line breaks",
+			text: "설치합니다. 다음 단계\n완료했습니다.",
+			want: []string{"설치합니다. ", "다음 단계\n", "완료했습니다."},
+		},
+		{
+			name: "full width marks",
+			text: "第一句。第二句！」第三句",
+			want: []string{"第一句。", "第二句！」", "第三句"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitSentences(tt.text)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitStrategies(t *testing.T) {
+	text := "첫 번째 문장입니다. 두 번째 문장입니다. 세 번째 문장입니다."
+
+	sentences := Split(text, Config{ChunkSize: 25, ChunkOverlap: 1, Sentences: true})
+	for _, chunk := range sentences {
+		if !strings.HasSuffix(strings.TrimSpace(chunk.Content), ".") {
+			t.Errorf("sentence chunk breaks inside a sentence: %q", chunk.Content)
+		}
+	}
+
+	tokens := Split(strings.Repeat("word ", 40), Config{ChunkSize: 10, ChunkOverlap: 1, Separators: []string{" "}, CountTokens: true})
+	for _, chunk := range tokens {
+		if n := EstimateTokens(chunk.Content); n > 10*TokenSafetyPercent/100 {
+			t.Errorf("token chunk has %d tokens: %q", n, chunk.Content)
+		}
+	}
+}
+
+func TestSplitSemantic(t *testing.T) {
+	text := "Install the agent. Configure the agent. Bake the bread. Slice the bread."
+	// Sentences about the agent and about bread point in different directions
+	embed := func(ctx context.Context, texts []string) ([][]float32, error) {
+		vectors := make([][]float32, len(texts))
+		for i, text := range texts {
+			if strings.Contains(text, "agent") {
+				vectors[i] = []float32{1, 0.1}
+			} else {
+				vectors[i] = []float32{0.1, 1}
+			}
+		}
+		return vectors, nil
+	}
+
+	chunks, err := SplitSemantic(context.Background(), text, Config{ChunkSize: 200, ChunkOverlap: 10}, 0.5, embed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Install the agent. Configure the agent. ", "Bake the bread. Slice the bread."}
+	if len(chunks) != len(want) {
+		t.Fatalf("got %d chunks %+v, want %d", len(chunks), chunks, len(want))
+	}
+	for i := range want {
+		if chunks[i].Content != want[i] {
+			t.Errorf("chunk %d: got %q, want %q", i, chunks[i].Content, want[i])
+		}
+	}
+}
+
+func TestSemanticBreaksPercentile(t *testing.T) {
+	vectors := [][]float32{{1, 0}, {1, 0}, {1, 0.1}, {0, 1}, {0, 1}}
+	got := semanticBreaks(vectors, 0)
+	want := []bool{false, false, true, false}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
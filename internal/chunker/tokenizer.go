@@ -0,0 +1,58 @@
+package chunker
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/tiktoken-go/tokenizer"
+)
+
+// TokenCounter counts the tokens of a text with the tokenizer of an embedding model
+type TokenCounter func(text string) int
+
+// modelTokenCounters caches the token counter of each model name, nil when the model has no known tokenizer
+var modelTokenCounters sync.Map
+
+// ModelTokenCounter returns the tokenizer of an embedding model, or nil when none is known. OpenAI
+// models are counted with their tiktoken encoding, provider prefixes such as "openai/" are ignored.
+// The tokenizers of other models (BGE, Qwen, ...) are not available in Go, callers fall back to
+// the EstimateTokens heuristic for them.
+func ModelTokenCounter(modelName string) TokenCounter {
+	name := strings.ToLower(modelName[strings.LastIndex(modelName, "/")+1:])
+	if name == "" {
+		return nil
+	}
+	if counter, ok := modelTokenCounters.Load(name); ok {
+		return counter.(TokenCounter)
+	}
+
+	var counter TokenCounter
+	if codec, err := modelCodec(name); err == nil {
+		counter = func(text string) int {
+			n, err := codec.Count(text)
+			if err != nil {
+				return EstimateTokens(text)
+			}
+			return n
+		}
+	}
+	modelTokenCounters.Store(name, counter)
+	return counter
+}
+
+// modelCodec returns the tiktoken encoding of an OpenAI model. The text-embedding-3 models
+// share cl100k_base with text-embedding-ada-002 but are unknown to the tokenizer package.
+func modelCodec(name string) (tokenizer.Codec, error) {
+	if strings.HasPrefix(name, "text-embedding-") {
+		return tokenizer.Get(tokenizer.Cl100kBase)
+	}
+	return tokenizer.ForModel(tokenizer.Model(name))
+}
+
+// Count counts the tokens of a text, estimating them with EstimateTokens when there is no tokenizer
+func (c TokenCounter) Count(text string) int {
+	if c == nil {
+		return EstimateTokens(text)
+	}
+	return c(text)
+}
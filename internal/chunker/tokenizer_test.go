@@ -0,0 +1,41 @@
+package chunker
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestModelTokenCounter(t *testing.T) {
+	tests := []struct {
+		model string
+		text  string
+		want  int
+	}{
+		{"text-embedding-3-small", "hello world", 2},
+		{"openai/text-embedding-ada-002", "hello world", 2},
+		// Models without a known tokenizer fall back to the estimate
+		{"bge-m3", "hello world", 4},
+		{"", "你好，世界", 5},
+	}
+	for _, tt := range tests {
+		if got := ModelTokenCounter(tt.model).Count(tt.text); got != tt.want {
+			t.Errorf("ModelTokenCounter(%q).Count(%q) = %d, want %d", tt.model, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestSplitWithModelTokenizer(t *testing.T) {
+	tokenizer := ModelTokenCounter("text-embedding-3-small")
+	chunks := Split(strings.Repeat("word ", 40), Config{
+		ChunkSize: 10, ChunkOverlap: 1, Separators: []string{" "}, CountTokens: true, Tokenizer: tokenizer,
+	})
+	for _, chunk := range chunks {
+		// Exact counts fill the whole chunk size, without the margin of the estimate
+		if n := tokenizer.Count(chunk.Content); n > 10 {
+			t.Errorf("token chunk has %d tokens: %q", n, chunk.Content)
+		}
+	}
+	if n := tokenizer.Count(chunks[0].Content); n <= 10*TokenSafetyPercent/100 {
+		t.Errorf("first chunk has %d tokens, want more than the estimated margin", n)
+	}
+}
@@ -62,6 +62,8 @@ type ConversationConfig struct {
 	ExtractRelationshipsPrompt string         `yaml:"extract_relationships_prompt"  json:"extract_relationships_prompt"`
 	// GenerateQuestionsPrompt is used to generate questions for document chunks to improve recall
 	GenerateQuestionsPrompt string `yaml:"generate_questions_prompt" json:"generate_questions_prompt"`
+	// SyncTimeout is the maximum time a non-streaming (synchronous) chat request may wait for the answer
+	SyncTimeout time.Duration `yaml:"sync_timeout" json:"sync_timeout"`
 }
 
 // SummaryConfig 摘要配置
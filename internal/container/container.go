@@ -29,6 +29,7 @@ import (
 	"github.com/Tencent/WeKnora/internal/application/repository"
 	elasticsearchRepoV7 "github.com/Tencent/WeKnora/internal/application/repository/retriever/elasticsearch/v7"
 	elasticsearchRepoV8 "github.com/Tencent/WeKnora/internal/application/repository/retriever/elasticsearch/v8"
+	milvusRepo "github.com/Tencent/WeKnora/internal/application/repository/retriever/milvus"
 	neo4jRepo "github.com/Tencent/WeKnora/internal/application/repository/retriever/neo4j"
 	postgresRepo "github.com/Tencent/WeKnora/internal/application/repository/retriever/postgres"
 	qdrantRepo "github.com/Tencent/WeKnora/internal/application/repository/retriever/qdrant"
@@ -102,9 +103,14 @@ func BuildContainer(container *dig.Container) *dig.Container {
 	must(container.Provide(repository.NewKnowledgeBaseRepository))
 	must(container.Provide(repository.NewKnowledgeRepository))
 	must(container.Provide(repository.NewChunkRepository))
+	must(container.Provide(repository.NewChunkEditRepository))
 	must(container.Provide(repository.NewKnowledgeTagRepository))
+	must(container.Provide(repository.NewKnowledgeStageRepository))
 	must(container.Provide(repository.NewSessionRepository))
 	must(container.Provide(repository.NewMessageRepository))
+	must(container.Provide(repository.NewFAQCandidateRepository))
+	must(container.Provide(repository.NewFAQEntryCommentRepository))
+	must(container.Provide(repository.NewAnalyticsRepository))
 	must(container.Provide(repository.NewModelRepository))
 	must(container.Provide(repository.NewUserRepository))
 	must(container.Provide(repository.NewAuthTokenRepository))
@@ -124,6 +130,7 @@ func BuildContainer(container *dig.Container) *dig.Container {
 	must(container.Provide(service.NewKnowledgeService))
 	must(container.Provide(service.NewChunkService))
 	must(container.Provide(service.NewKnowledgeTagService))
+	must(container.Provide(embedding.NewEmbeddingCache))
 	must(container.Provide(embedding.NewBatchEmbedder))
 	must(container.Provide(service.NewModelService))
 	must(container.Provide(service.NewDatasetService))
@@ -142,7 +149,11 @@ func BuildContainer(container *dig.Container) *dig.Container {
 	logger.Debugf(ctx, "[Container] Registering web search registry and providers...")
 	must(container.Provide(web_search.NewRegistry))
 	must(container.Invoke(registerWebSearchProviders))
+	must(container.Provide(web_search.NewResultCache))
 	must(container.Provide(service.NewWebSearchService))
+	must(container.Provide(service.NewWebKnowledgeService))
+	must(container.Provide(service.NewFAQMiningService))
+	must(container.Provide(service.NewAnalyticsService))
 
 	// Agent service layer (requires event bus, web search service)
 	// SessionService is passed as parameter to CreateAgentEngine method when creating AgentService
@@ -186,6 +197,7 @@ func BuildContainer(container *dig.Container) *dig.Container {
 	must(container.Provide(handler.NewKnowledgeHandler))
 	must(container.Provide(handler.NewChunkHandler))
 	must(container.Provide(handler.NewFAQHandler))
+	must(container.Provide(handler.NewAnalyticsHandler))
 	must(container.Provide(handler.NewTagHandler))
 	must(container.Provide(session.NewHandler))
 	must(container.Provide(handler.NewMessageHandler))
@@ -203,6 +215,8 @@ func BuildContainer(container *dig.Container) *dig.Container {
 	logger.Debugf(ctx, "[Container] Registering router and starting asynq server...")
 	must(container.Provide(router.NewRouter))
 	must(container.Invoke(router.RunAsynqServer))
+	must(container.Provide(router.NewAsynqScheduler))
+	must(container.Invoke(router.RunAsynqScheduler))
 
 	logger.Infof(ctx, "[Container] Container initialization completed successfully")
 	return container
@@ -528,6 +542,36 @@ func initRetrieveEngineRegistry(db *gorm.DB, cfg *config.Config) (interfaces.Ret
 			}
 		}
 	}
+
+	if slices.Contains(retrieveDriver, "milvus") {
+		milvusAddress := os.Getenv("MILVUS_ADDRESS")
+		if milvusAddress == "" {
+			milvusAddress = "localhost:19530"
+		}
+
+		// Authentication token (optional), an API key or username:password
+		milvusToken := os.Getenv("MILVUS_API_KEY")
+		if milvusToken == "" && os.Getenv("MILVUS_USERNAME") != "" {
+			milvusToken = os.Getenv("MILVUS_USERNAME") + ":" + os.Getenv("MILVUS_PASSWORD")
+		}
+
+		log.Infof("Connecting to Milvus at %s", milvusAddress)
+
+		milvusRepository := milvusRepo.NewMilvusRetrieveEngineRepository(milvusRepo.ClientConfig{
+			Address: milvusAddress,
+			Token:   milvusToken,
+			DBName:  os.Getenv("MILVUS_DB_NAME"),
+		})
+		if err := registry.Register(
+			retriever.NewKVHybridRetrieveEngine(
+				milvusRepository, types.MilvusRetrieverEngineType,
+			),
+		); err != nil {
+			log.Errorf("Register milvus retrieve engine failed: %v", err)
+		} else {
+			log.Infof("Register milvus retrieve engine success")
+		}
+	}
 	return registry, nil
 }
 
@@ -667,4 +711,14 @@ func registerWebSearchProviders(registry *web_search.Registry) {
 	registry.Register(web_search.BingProviderInfo(), func() (interfaces.WebSearchProvider, error) {
 		return web_search.NewBingProvider()
 	})
+
+	// Register SearXNG provider
+	registry.Register(web_search.SearXNGProviderInfo(), func() (interfaces.WebSearchProvider, error) {
+		return web_search.NewSearXNGProvider()
+	})
+
+	// Register generic JSON search API provider
+	registry.Register(web_search.JSONAPIProviderInfo(), func() (interfaces.WebSearchProvider, error) {
+		return web_search.NewJSONAPIProvider()
+	})
 }
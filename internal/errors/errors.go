@@ -124,6 +124,15 @@ func NewValidationError(message string) *AppError {
 	}
 }
 
+// NewTimeoutError creates a timeout error
+func NewTimeoutError(message string) *AppError {
+	return &AppError{
+		Code:     ErrTimeout,
+		Message:  message,
+		HTTPCode: http.StatusGatewayTimeout,
+	}
+}
+
 // Tenant related errors
 func NewTenantNotFoundError() *AppError {
 	return &AppError{
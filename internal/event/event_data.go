@@ -185,6 +185,8 @@ type AgentReferencesData struct {
 type AgentFinalAnswerData struct {
 	Content string `json:"content"`
 	Done    bool   `json:"done"`
+	// IsFallback marks the fallback answer given when nothing relevant was found
+	IsFallback bool `json:"is_fallback,omitempty"`
 }
 
 // AgentReflectionData represents agent reflection data
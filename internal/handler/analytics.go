@@ -0,0 +1,256 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tencent/WeKnora/internal/errors"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/Tencent/WeKnora/internal/types/interfaces"
+	secutils "github.com/Tencent/WeKnora/internal/utils"
+)
+
+// AnalyticsHandler handles the usage analytics of knowledge bases
+type AnalyticsHandler struct {
+	analyticsService interfaces.AnalyticsService
+}
+
+// NewAnalyticsHandler creates a new analytics handler
+func NewAnalyticsHandler(analyticsService interfaces.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{analyticsService: analyticsService}
+}
+
+// bindDateRange parses the start_date and end_date query parameters
+func bindDateRange(c *gin.Context) (types.AnalyticsDateRange, bool) {
+	dateRange, err := types.ParseAnalyticsDateRange(c.Query("start_date"), c.Query("end_date"), time.Now())
+	if err != nil {
+		c.Error(errors.NewBadRequestError("日期范围不合法").WithDetails(err.Error()))
+		return dateRange, false
+	}
+	return dateRange, true
+}
+
+// bindPage parses the pagination query parameters
+func bindPage(c *gin.Context) (*types.Pagination, bool) {
+	var page types.Pagination
+	if err := c.ShouldBindQuery(&page); err != nil {
+		logger.Error(c.Request.Context(), "Failed to bind pagination query", err)
+		c.Error(errors.NewBadRequestError("分页参数不合法").WithDetails(err.Error()))
+		return nil, false
+	}
+	return &page, true
+}
+
+// GetSummary godoc
+// @Summary      获取知识库使用统计
+// @Description  统计日期范围内知识库分块被检索命中的次数、无结果和低置信度问题数，以及引用该知识库的回答收到的点赞和点踩数。日期默认为最近30天
+// @Tags         知识库统计
+// @Accept       json
+// @Produce      json
+// @Param        id          path      string  true   "知识库ID"
+// @Param        start_date  query     string  false  "开始日期（YYYY-MM-DD）"
+// @Param        end_date    query     string  false  "结束日期（YYYY-MM-DD），默认今天"
+// @Success      200         {object}  map[string]interface{}  "使用统计"
+// @Failure      400         {object}  errors.AppError         "请求参数错误"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /knowledge-bases/{id}/analytics [get]
+func (h *AnalyticsHandler) GetSummary(c *gin.Context) {
+	ctx := c.Request.Context()
+	dateRange, ok := bindDateRange(c)
+	if !ok {
+		return
+	}
+
+	summary, err := h.analyticsService.GetKnowledgeBaseAnalytics(ctx, secutils.SanitizeForLog(c.Param("id")), dateRange)
+	if err != nil {
+		logger.ErrorWithFields(ctx, err, nil)
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    summary,
+	})
+}
+
+// ListTopHits godoc
+// @Summary      获取命中最多的条目
+// @Description  按命中次数从高到低列出日期范围内被检索命中的FAQ条目或文档分块，附带引用它们的回答收到的评价数
+// @Tags         知识库统计
+// @Accept       json
+// @Produce      json
+// @Param        id          path      string  true   "知识库ID"
+// @Param        start_date  query     string  false  "开始日期（YYYY-MM-DD）"
+// @Param        end_date    query     string  false  "结束日期（YYYY-MM-DD），默认今天"
+// @Param        limit       query     int     false  "返回数量，最大100"  default(20)
+// @Success      200         {object}  map[string]interface{}  "条目列表"
+// @Failure      400         {object}  errors.AppError         "请求参数错误"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /knowledge-bases/{id}/analytics/top-hits [get]
+func (h *AnalyticsHandler) ListTopHits(c *gin.Context) {
+	ctx := c.Request.Context()
+	dateRange, ok := bindDateRange(c)
+	if !ok {
+		return
+	}
+	limit := 0
+	if limitStr := c.Query("limit"); limitStr != "" {
+		var err error
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 {
+			c.Error(errors.NewBadRequestError("limit 必须是正整数"))
+			return
+		}
+	}
+
+	entries, err := h.analyticsService.ListTopHitEntries(ctx, secutils.SanitizeForLog(c.Param("id")), dateRange, limit)
+	if err != nil {
+		logger.ErrorWithFields(ctx, err, nil)
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    entries,
+	})
+}
+
+// ListNeverHit godoc
+// @Summary      获取从未命中的条目
+// @Description  分页列出日期范围内从未被检索命中的条目：FAQ知识库返回已发布且启用的FAQ条目，文档知识库返回已解析的文档
+// @Tags         知识库统计
+// @Accept       json
+// @Produce      json
+// @Param        id          path      string  true   "知识库ID"
+// @Param        start_date  query     string  false  "开始日期（YYYY-MM-DD）"
+// @Param        end_date    query     string  false  "结束日期（YYYY-MM-DD），默认今天"
+// @Param        page        query     int     false  "页码"
+// @Param        page_size   query     int     false  "每页数量"
+// @Success      200         {object}  map[string]interface{}  "条目列表"
+// @Failure      400         {object}  errors.AppError         "请求参数错误"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /knowledge-bases/{id}/analytics/never-hit [get]
+func (h *AnalyticsHandler) ListNeverHit(c *gin.Context) {
+	ctx := c.Request.Context()
+	dateRange, ok := bindDateRange(c)
+	if !ok {
+		return
+	}
+	page, ok := bindPage(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.analyticsService.ListNeverHitEntries(ctx, secutils.SanitizeForLog(c.Param("id")), dateRange, page)
+	if err != nil {
+		logger.ErrorWithFields(ctx, err, nil)
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// ListQueries godoc
+// @Summary      获取未答好的问题
+// @Description  分页列出日期范围内检索无结果（zero_result）或最高分低于阈值（low_confidence）的用户问题，按时间倒序
+// @Tags         知识库统计
+// @Accept       json
+// @Produce      json
+// @Param        id          path      string  true   "知识库ID"
+// @Param        type        query     string  false  "问题类型：zero_result 或 low_confidence，默认全部"
+// @Param        start_date  query     string  false  "开始日期（YYYY-MM-DD）"
+// @Param        end_date    query     string  false  "结束日期（YYYY-MM-DD），默认今天"
+// @Param        page        query     int     false  "页码"
+// @Param        page_size   query     int     false  "每页数量"
+// @Success      200         {object}  map[string]interface{}  "问题列表"
+// @Failure      400         {object}  errors.AppError         "请求参数错误"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /knowledge-bases/{id}/analytics/queries [get]
+func (h *AnalyticsHandler) ListQueries(c *gin.Context) {
+	ctx := c.Request.Context()
+	logType := types.QueryLogType(c.Query("type"))
+	if logType != "" && !logType.IsValid() {
+		c.Error(errors.NewBadRequestError("type 必须是 zero_result 或 low_confidence"))
+		return
+	}
+	dateRange, ok := bindDateRange(c)
+	if !ok {
+		return
+	}
+	page, ok := bindPage(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.analyticsService.ListQueryLogs(ctx, secutils.SanitizeForLog(c.Param("id")), logType, dateRange, page)
+	if err != nil {
+		logger.ErrorWithFields(ctx, err, nil)
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// ListFeedback godoc
+// @Summary      获取回答评价
+// @Description  分页列出日期范围内对引用了该知识库内容的回答的评价，按时间倒序
+// @Tags         知识库统计
+// @Accept       json
+// @Produce      json
+// @Param        id          path      string  true   "知识库ID"
+// @Param        rating      query     string  false  "评价：up 或 down，默认全部"
+// @Param        start_date  query     string  false  "开始日期（YYYY-MM-DD）"
+// @Param        end_date    query     string  false  "结束日期（YYYY-MM-DD），默认今天"
+// @Param        page        query     int     false  "页码"
+// @Param        page_size   query     int     false  "每页数量"
+// @Success      200         {object}  map[string]interface{}  "评价列表"
+// @Failure      400         {object}  errors.AppError         "请求参数错误"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /knowledge-bases/{id}/analytics/feedback [get]
+func (h *AnalyticsHandler) ListFeedback(c *gin.Context) {
+	ctx := c.Request.Context()
+	rating := types.MessageFeedbackRating(c.Query("rating"))
+	if rating != "" && !rating.IsValid() {
+		c.Error(errors.NewBadRequestError("rating 必须是 up 或 down"))
+		return
+	}
+	dateRange, ok := bindDateRange(c)
+	if !ok {
+		return
+	}
+	page, ok := bindPage(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.analyticsService.ListMessageFeedback(ctx, secutils.SanitizeForLog(c.Param("id")), rating, dateRange, page)
+	if err != nil {
+		logger.ErrorWithFields(ctx, err, nil)
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
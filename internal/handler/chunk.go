@@ -227,7 +227,7 @@ func (h *ChunkHandler) validateAndGetChunk(c *gin.Context) (*types.Chunk, string
 
 // UpdateChunk godoc
 // @Summary      更新分块
-// @Description  更新指定分块的内容和属性
+// @Description  更新指定分块的内容和属性。修改内容会记录到分块编辑历史并重新生成向量，重复提交相同内容可重试向量化
 // @Tags         分块管理
 // @Accept       json
 // @Produce      json
@@ -257,12 +257,23 @@ func (h *ChunkHandler) UpdateChunk(c *gin.Context) {
 		return
 	}
 
-	// Update chunk properties
-	if req.Content != "" {
-		chunk.Content = req.Content
+	// Changed content of document chunks is recorded in the edit history and re-embedded,
+	// FAQ chunks keep the plain update. Chunks whose re-indexing failed are indexed again.
+	if req.Content != "" && (req.Content != chunk.Content || chunk.Status == int(types.ChunkStatusIndexFailed)) {
+		if chunk.ChunkType == types.ChunkTypeFAQ {
+			chunk.Content = req.Content
+		} else if err := h.service.EditChunk(ctx, chunk, req.Content); err != nil {
+			handleChunkEditError(c, err)
+			return
+		}
 	}
 
-	chunk.IsEnabled = req.IsEnabled
+	// The index entries follow the enabled status, those of child chunks with their parent
+	if err := h.service.UpdateChunkEnabledStatus(ctx, chunk, req.IsEnabled); err != nil {
+		logger.ErrorWithFields(ctx, err, nil)
+		c.Error(errors.NewInternalServerError(err.Error()))
+		return
+	}
 
 	if err := h.service.UpdateChunk(ctx, chunk); err != nil {
 		logger.ErrorWithFields(ctx, err, nil)
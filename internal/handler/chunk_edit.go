@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Tencent/WeKnora/internal/errors"
+	"github.com/Tencent/WeKnora/internal/logger"
+	secutils "github.com/Tencent/WeKnora/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// SplitChunkRequest defines the request structure for splitting a chunk
+type SplitChunkRequest struct {
+	// Character offset in the chunk content where the second part starts
+	Offset int `json:"offset" binding:"required"`
+}
+
+// MergeChunkRequest defines the request structure for merging chunks
+type MergeChunkRequest struct {
+	// ID of the adjacent chunk to merge with, the next chunk when empty
+	ChunkID string `json:"chunk_id"`
+}
+
+// handleChunkEditError reports a chunk edit error, keeping the status of application errors
+func handleChunkEditError(c *gin.Context, err error) {
+	if appErr, ok := errors.IsAppError(err); ok {
+		c.Error(appErr)
+		return
+	}
+	logger.ErrorWithFields(c.Request.Context(), err, nil)
+	c.Error(errors.NewInternalServerError(err.Error()))
+}
+
+// SplitChunk godoc
+// @Summary      拆分分块
+// @Description  在指定字符位置将文本分块拆分为两个相邻分块，并重新生成向量。拆分会记录到编辑历史，重新解析文档后仍然保留
+// @Tags         分块管理
+// @Accept       json
+// @Produce      json
+// @Param        knowledge_id  path      string             true  "知识ID"
+// @Param        id            path      string             true  "分块ID"
+// @Param        request       body      SplitChunkRequest  true  "拆分请求"
+// @Success      200           {object}  map[string]interface{}  "拆分后的分块"
+// @Failure      400           {object}  errors.AppError         "请求参数错误"
+// @Failure      404           {object}  errors.AppError         "分块不存在"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /chunks/{knowledge_id}/{id}/split [post]
+func (h *ChunkHandler) SplitChunk(c *gin.Context) {
+	ctx := c.Request.Context()
+	logger.Info(ctx, "Start splitting knowledge chunk")
+
+	chunk, _, err := h.validateAndGetChunk(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	var req SplitChunkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Errorf(ctx, "Failed to parse request parameters: %s", secutils.SanitizeForLog(err.Error()))
+		c.Error(errors.NewBadRequestError(err.Error()))
+		return
+	}
+
+	chunks, err := h.service.SplitChunk(ctx, chunk, req.Offset)
+	if err != nil {
+		handleChunkEditError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    chunks,
+	})
+}
+
+// MergeChunks godoc
+// @Summary      合并分块
+// @Description  将文本分块与相邻分块合并，默认合并下一个分块，并重新生成向量。合并会记录到编辑历史，重新解析文档后仍然保留
+// @Tags         分块管理
+// @Accept       json
+// @Produce      json
+// @Param        knowledge_id  path      string             true   "知识ID"
+// @Param        id            path      string             true   "分块ID"
+// @Param        request       body      MergeChunkRequest  false  "合并请求"
+// @Success      200           {object}  map[string]interface{}  "合并后的分块"
+// @Failure      400           {object}  errors.AppError         "请求参数错误或分块不相邻"
+// @Failure      404           {object}  errors.AppError         "分块不存在"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /chunks/{knowledge_id}/{id}/merge [post]
+func (h *ChunkHandler) MergeChunks(c *gin.Context) {
+	ctx := c.Request.Context()
+	logger.Info(ctx, "Start merging knowledge chunks")
+
+	chunk, _, err := h.validateAndGetChunk(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	var req MergeChunkRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logger.Errorf(ctx, "Failed to parse request parameters: %s", secutils.SanitizeForLog(err.Error()))
+			c.Error(errors.NewBadRequestError(err.Error()))
+			return
+		}
+	}
+
+	merged, err := h.service.MergeChunks(ctx, chunk, secutils.SanitizeForLog(req.ChunkID))
+	if err != nil {
+		handleChunkEditError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    merged,
+	})
+}
+
+// ListChunkEdits godoc
+// @Summary      获取分块编辑历史
+// @Description  获取分块的手动编辑历史（修改、拆分、合并），包括拆分产生和合并移除的分块
+// @Tags         分块管理
+// @Accept       json
+// @Produce      json
+// @Param        knowledge_id  path      string  true  "知识ID"
+// @Param        id            path      string  true  "分块ID"
+// @Success      200           {object}  map[string]interface{}  "编辑历史"
+// @Failure      400           {object}  errors.AppError         "请求参数错误"
+// @Failure      404           {object}  errors.AppError         "分块不存在"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /chunks/{knowledge_id}/{id}/history [get]
+func (h *ChunkHandler) ListChunkEdits(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	chunk, _, err := h.validateAndGetChunk(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	edits, err := h.service.ListChunkEdits(ctx, chunk.ID)
+	if err != nil {
+		handleChunkEditError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    edits,
+	})
+}
+
+// DeleteChunkEdits godoc
+// @Summary      放弃分块手动编辑
+// @Description  删除知识下所有分块的编辑历史。当前分块保持不变，下次重新解析文档时将使用解析结果而不再重放手动编辑
+// @Tags         分块管理
+// @Accept       json
+// @Produce      json
+// @Param        knowledge_id  path      string  true  "知识ID"
+// @Success      200           {object}  map[string]interface{}  "删除成功"
+// @Failure      400           {object}  errors.AppError         "请求参数错误"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /chunks/{knowledge_id}/edits [delete]
+func (h *ChunkHandler) DeleteChunkEdits(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	knowledgeID := secutils.SanitizeForLog(c.Param("knowledge_id"))
+	if knowledgeID == "" {
+		logger.Error(ctx, "Knowledge ID is empty")
+		c.Error(errors.NewBadRequestError("Knowledge ID cannot be empty"))
+		return
+	}
+
+	logger.Infof(ctx, "Discarding manual chunk edits, knowledge ID: %s", knowledgeID)
+	if err := h.service.DeleteChunkEdits(ctx, []string{knowledgeID}); err != nil {
+		handleChunkEditError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Chunk edits discarded",
+	})
+}
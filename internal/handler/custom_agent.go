@@ -80,7 +80,7 @@ func (h *CustomAgentHandler) CreateAgent(c *gin.Context) {
 	createdAgent, err := h.service.CreateAgent(ctx, agent)
 	if err != nil {
 		logger.ErrorWithFields(ctx, err, nil)
-		if err == service.ErrAgentNameRequired {
+		if err == service.ErrAgentNameRequired || err == service.ErrInvalidTrustWeights {
 			c.Error(errors.NewBadRequestError(err.Error()))
 			return
 		}
@@ -225,7 +225,7 @@ func (h *CustomAgentHandler) UpdateAgent(c *gin.Context) {
 			c.Error(errors.NewNotFoundError("Agent not found"))
 		case service.ErrCannotModifyBuiltin:
 			c.Error(errors.NewForbiddenError("Cannot modify built-in agent"))
-		case service.ErrAgentNameRequired:
+		case service.ErrAgentNameRequired, service.ErrInvalidTrustWeights:
 			c.Error(errors.NewBadRequestError(err.Error()))
 		default:
 			c.Error(errors.NewInternalServerError(err.Error()))
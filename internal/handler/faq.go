@@ -16,11 +16,15 @@ import (
 // FAQHandler handles FAQ knowledge base operations.
 type FAQHandler struct {
 	knowledgeService interfaces.KnowledgeService
+	faqMiningService interfaces.FAQMiningService
 }
 
 // NewFAQHandler creates a new FAQ handler
-func NewFAQHandler(knowledgeService interfaces.KnowledgeService) *FAQHandler {
-	return &FAQHandler{knowledgeService: knowledgeService}
+func NewFAQHandler(
+	knowledgeService interfaces.KnowledgeService,
+	faqMiningService interfaces.FAQMiningService,
+) *FAQHandler {
+	return &FAQHandler{knowledgeService: knowledgeService, faqMiningService: faqMiningService}
 }
 
 // ListEntries godoc
@@ -36,6 +40,7 @@ func NewFAQHandler(knowledgeService interfaces.KnowledgeService) *FAQHandler {
 // @Param        keyword      query     string  false  "关键词搜索"
 // @Param        search_field query     string  false  "搜索字段: standard_question(标准问题), similar_questions(相似问法), answers(答案), 默认搜索全部"
 // @Param        sort_order   query     string  false  "排序方式: asc(按更新时间正序), 默认按更新时间倒序"
+// @Param        review_status query    string  false  "审核状态: draft(草稿), pending_review(待审核), published(已发布), archived(已归档), 默认全部"
 // @Success      200        {object}  map[string]interface{}  "FAQ列表"
 // @Failure      400        {object}  errors.AppError         "请求参数错误"
 // @Security     Bearer
@@ -63,8 +68,14 @@ func (h *FAQHandler) ListEntries(c *gin.Context) {
 	keyword := secutils.SanitizeForLog(c.Query("keyword"))
 	searchField := secutils.SanitizeForLog(c.Query("search_field"))
 	sortOrder := secutils.SanitizeForLog(c.Query("sort_order"))
+	reviewStatus := types.FAQReviewStatus(c.Query("review_status"))
+	if reviewStatus != "" && !reviewStatus.IsValid() {
+		c.Error(errors.NewBadRequestError("review_status 必须是 draft、pending_review、published 或 archived"))
+		return
+	}
 
-	result, err := h.knowledgeService.ListFAQEntries(ctx, secutils.SanitizeForLog(c.Param("id")), &page, tagSeqID, keyword, searchField, sortOrder)
+	result, err := h.knowledgeService.ListFAQEntries(ctx, secutils.SanitizeForLog(c.Param("id")), &page, tagSeqID, keyword, searchField, sortOrder,
+		reviewStatus)
 	if err != nil {
 		logger.ErrorWithFields(ctx, err, nil)
 		c.Error(err)
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tencent/WeKnora/internal/errors"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+	secutils "github.com/Tencent/WeKnora/internal/utils"
+)
+
+// ScanConflicts godoc
+// @Summary      扫描FAQ冲突
+// @Description  异步扫描知识库中标准问或相似问语义几乎相同的条目，答案不同的记为冲突，答案相同的记为重叠，并给出合并建议。结果通过获取冲突报告接口查看
+// @Tags         FAQ管理
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                        true  "FAQ知识库ID"
+// @Param        request  body      types.FAQConflictScanRequest  false "扫描参数"
+// @Success      200      {object}  map[string]interface{}        "任务ID"
+// @Failure      400      {object}  errors.AppError               "请求参数错误"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /knowledge-bases/{id}/faq/conflicts/scan [post]
+func (h *FAQHandler) ScanConflicts(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req types.FAQConflictScanRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logger.Error(ctx, "Failed to bind FAQ conflict scan request", err)
+			c.Error(errors.NewBadRequestError("请求参数不合法").WithDetails(err.Error()))
+			return
+		}
+	}
+
+	taskID, err := h.knowledgeService.StartFAQConflictScan(ctx, secutils.SanitizeForLog(c.Param("id")), &req)
+	if err != nil {
+		logger.ErrorWithFields(ctx, err, nil)
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"task_id": taskID,
+		},
+	})
+}
+
+// GetConflictReport godoc
+// @Summary      获取FAQ冲突报告
+// @Description  获取知识库最近一次冲突扫描的报告，扫描进行中时返回当前进度和已发现的冲突
+// @Tags         FAQ管理
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string                  true  "FAQ知识库ID"
+// @Success      200  {object}  map[string]interface{}  "冲突报告"
+// @Failure      404  {object}  errors.AppError         "暂无冲突报告"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /knowledge-bases/{id}/faq/conflicts [get]
+func (h *FAQHandler) GetConflictReport(c *gin.Context) {
+	ctx := c.Request.Context()
+	report, err := h.knowledgeService.GetFAQConflictReport(ctx, secutils.SanitizeForLog(c.Param("id")))
+	if err != nil {
+		logger.ErrorWithFields(ctx, err, nil)
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    report,
+	})
+}
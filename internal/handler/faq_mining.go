@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tencent/WeKnora/internal/errors"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+	secutils "github.com/Tencent/WeKnora/internal/utils"
+)
+
+// StartMining godoc
+// @Summary      挖掘FAQ候选
+// @Description  异步分析近期对话中命中兜底回复或检索得分较低的用户问题，按语义聚类后生成FAQ候选（标准问、相似问及基于已有文档草拟的答案），放入待审核队列
+// @Tags         FAQ管理
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                  true  "FAQ知识库ID"
+// @Param        request  body      types.FAQMiningRequest  false "挖掘参数"
+// @Success      200      {object}  map[string]interface{}  "任务ID"
+// @Failure      400      {object}  errors.AppError         "请求参数错误"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /knowledge-bases/{id}/faq/mining [post]
+func (h *FAQHandler) StartMining(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req types.FAQMiningRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logger.Error(ctx, "Failed to bind FAQ mining request", err)
+			c.Error(errors.NewBadRequestError("请求参数不合法").WithDetails(err.Error()))
+			return
+		}
+	}
+
+	taskID, err := h.faqMiningService.StartFAQMining(ctx, secutils.SanitizeForLog(c.Param("id")), &req)
+	if err != nil {
+		logger.ErrorWithFields(ctx, err, nil)
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"task_id": taskID,
+		},
+	})
+}
+
+// ListCandidates godoc
+// @Summary      获取FAQ候选列表
+// @Description  获取从对话中挖掘出的FAQ候选，按提问次数倒序排列
+// @Tags         FAQ管理
+// @Accept       json
+// @Produce      json
+// @Param        id         path      string  true   "FAQ知识库ID"
+// @Param        status     query     string  false  "审核状态: pending(待审核), accepted(已采纳), rejected(已拒绝)，默认全部"
+// @Param        page       query     int     false  "页码"
+// @Param        page_size  query     int     false  "每页数量"
+// @Success      200        {object}  map[string]interface{}  "候选列表"
+// @Failure      400        {object}  errors.AppError         "请求参数错误"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /knowledge-bases/{id}/faq/candidates [get]
+func (h *FAQHandler) ListCandidates(c *gin.Context) {
+	ctx := c.Request.Context()
+	var page types.Pagination
+	if err := c.ShouldBindQuery(&page); err != nil {
+		logger.Error(ctx, "Failed to bind pagination query", err)
+		c.Error(errors.NewBadRequestError("分页参数不合法").WithDetails(err.Error()))
+		return
+	}
+
+	status := types.FAQCandidateStatus(c.Query("status"))
+	switch status {
+	case "", types.FAQCandidateStatusPending, types.FAQCandidateStatusAccepted, types.FAQCandidateStatusRejected:
+	default:
+		c.Error(errors.NewBadRequestError("status 必须是 pending、accepted 或 rejected"))
+		return
+	}
+
+	result, err := h.faqMiningService.ListFAQCandidates(ctx, secutils.SanitizeForLog(c.Param("id")), status, &page)
+	if err != nil {
+		logger.ErrorWithFields(ctx, err, nil)
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// AcceptCandidate godoc
+// @Summary      采纳FAQ候选
+// @Description  将待审核的FAQ候选创建为FAQ条目，请求中填写的字段会覆盖候选内容，未填写的字段沿用候选内容
+// @Tags         FAQ管理
+// @Accept       json
+// @Produce      json
+// @Param        id            path      string                           true   "FAQ知识库ID"
+// @Param        candidate_id  path      string                           true   "候选ID"
+// @Param        request       body      types.FAQCandidateAcceptRequest  false  "编辑后的FAQ内容"
+// @Success      200           {object}  map[string]interface{}           "创建的FAQ条目"
+// @Failure      400           {object}  errors.AppError                  "请求参数错误"
+// @Failure      404           {object}  errors.AppError                  "候选不存在"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /knowledge-bases/{id}/faq/candidates/{candidate_id}/accept [post]
+func (h *FAQHandler) AcceptCandidate(c *gin.Context) {
+	ctx := c.Request.Context()
+	var req types.FAQCandidateAcceptRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logger.Error(ctx, "Failed to bind FAQ candidate accept request", err)
+			c.Error(errors.NewBadRequestError("请求参数不合法").WithDetails(err.Error()))
+			return
+		}
+	}
+
+	entry, err := h.faqMiningService.AcceptFAQCandidate(ctx,
+		secutils.SanitizeForLog(c.Param("id")), secutils.SanitizeForLog(c.Param("candidate_id")), &req)
+	if err != nil {
+		logger.ErrorWithFields(ctx, err, nil)
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    entry,
+	})
+}
+
+// RejectCandidate godoc
+// @Summary      拒绝FAQ候选
+// @Description  拒绝待审核的FAQ候选，其问题不会在后续挖掘中再次出现
+// @Tags         FAQ管理
+// @Accept       json
+// @Produce      json
+// @Param        id            path      string  true  "FAQ知识库ID"
+// @Param        candidate_id  path      string  true  "候选ID"
+// @Success      200           {object}  map[string]interface{}  "拒绝成功"
+// @Failure      404           {object}  errors.AppError         "候选不存在"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /knowledge-bases/{id}/faq/candidates/{candidate_id}/reject [post]
+func (h *FAQHandler) RejectCandidate(c *gin.Context) {
+	ctx := c.Request.Context()
+	if err := h.faqMiningService.RejectFAQCandidate(ctx,
+		secutils.SanitizeForLog(c.Param("id")), secutils.SanitizeForLog(c.Param("candidate_id"))); err != nil {
+		logger.ErrorWithFields(ctx, err, nil)
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+	})
+}
@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Tencent/WeKnora/internal/errors"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+	secutils "github.com/Tencent/WeKnora/internal/utils"
+)
+
+// ReviewEntry godoc
+// @Summary      审核FAQ条目
+// @Description  在审核流程中流转FAQ条目：submit(提交审核)、approve(审核通过并发布)、reject(驳回为草稿)、publish(直接发布，知识库要求审核时不可用)、archive(归档)、restore(恢复为草稿)。仅已发布的条目参与检索，approve和reject仅限指定的审核人
+// @Tags         FAQ管理
+// @Accept       json
+// @Produce      json
+// @Param        id        path      string                  true  "知识库ID"
+// @Param        entry_id  path      int                     true  "FAQ条目ID(seq_id)"
+// @Param        request   body      types.FAQReviewRequest  true  "审核操作"
+// @Success      200       {object}  map[string]interface{}  "更新后的FAQ条目"
+// @Failure      400       {object}  errors.AppError         "请求参数错误或状态不允许该操作"
+// @Failure      403       {object}  errors.AppError         "不是指定的审核人"
+// @Failure      404       {object}  errors.AppError         "条目不存在"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /knowledge-bases/{id}/faq/entries/{entry_id}/review [post]
+func (h *FAQHandler) ReviewEntry(c *gin.Context) {
+	ctx := c.Request.Context()
+	entrySeqID, err := strconv.ParseInt(c.Param("entry_id"), 10, 64)
+	if err != nil {
+		c.Error(errors.NewBadRequestError("entry_id 必须是整数"))
+		return
+	}
+
+	var req types.FAQReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error(ctx, "Failed to bind FAQ review request", err)
+		c.Error(errors.NewBadRequestError("请求参数不合法").WithDetails(err.Error()))
+		return
+	}
+
+	entry, err := h.knowledgeService.ReviewFAQEntry(ctx, secutils.SanitizeForLog(c.Param("id")), entrySeqID, &req)
+	if err != nil {
+		logger.ErrorWithFields(ctx, err, nil)
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    entry,
+	})
+}
+
+// AssignEntryReviewer godoc
+// @Summary      指定FAQ条目审核人
+// @Description  指定审核FAQ条目措辞的用户，reviewer_id为空时取消指定（待审核的条目不可取消）
+// @Tags         FAQ管理
+// @Accept       json
+// @Produce      json
+// @Param        id        path      string                    true  "知识库ID"
+// @Param        entry_id  path      int                       true  "FAQ条目ID(seq_id)"
+// @Param        request   body      types.FAQReviewerRequest  true  "审核人"
+// @Success      200       {object}  map[string]interface{}    "更新后的FAQ条目"
+// @Failure      400       {object}  errors.AppError           "请求参数错误"
+// @Failure      404       {object}  errors.AppError           "条目不存在"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /knowledge-bases/{id}/faq/entries/{entry_id}/reviewer [put]
+func (h *FAQHandler) AssignEntryReviewer(c *gin.Context) {
+	ctx := c.Request.Context()
+	entrySeqID, err := strconv.ParseInt(c.Param("entry_id"), 10, 64)
+	if err != nil {
+		c.Error(errors.NewBadRequestError("entry_id 必须是整数"))
+		return
+	}
+
+	var req types.FAQReviewerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error(ctx, "Failed to bind FAQ reviewer request", err)
+		c.Error(errors.NewBadRequestError("请求参数不合法").WithDetails(err.Error()))
+		return
+	}
+
+	entry, err := h.knowledgeService.AssignFAQEntryReviewer(ctx,
+		secutils.SanitizeForLog(c.Param("id")), entrySeqID, req.ReviewerID)
+	if err != nil {
+		logger.ErrorWithFields(ctx, err, nil)
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    entry,
+	})
+}
+
+// ListEntryComments godoc
+// @Summary      获取FAQ条目审核记录
+// @Description  获取FAQ条目的评论和审核操作记录，按时间正序排列
+// @Tags         FAQ管理
+// @Accept       json
+// @Produce      json
+// @Param        id        path      string  true  "知识库ID"
+// @Param        entry_id  path      int     true  "FAQ条目ID(seq_id)"
+// @Success      200       {object}  map[string]interface{}  "评论列表"
+// @Failure      404       {object}  errors.AppError         "条目不存在"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /knowledge-bases/{id}/faq/entries/{entry_id}/comments [get]
+func (h *FAQHandler) ListEntryComments(c *gin.Context) {
+	ctx := c.Request.Context()
+	entrySeqID, err := strconv.ParseInt(c.Param("entry_id"), 10, 64)
+	if err != nil {
+		c.Error(errors.NewBadRequestError("entry_id 必须是整数"))
+		return
+	}
+
+	comments, err := h.knowledgeService.ListFAQEntryComments(ctx, secutils.SanitizeForLog(c.Param("id")), entrySeqID)
+	if err != nil {
+		logger.ErrorWithFields(ctx, err, nil)
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    comments,
+	})
+}
+
+// AddEntryComment godoc
+// @Summary      评论FAQ条目
+// @Description  为FAQ条目添加审核评论
+// @Tags         FAQ管理
+// @Accept       json
+// @Produce      json
+// @Param        id        path      string                        true  "知识库ID"
+// @Param        entry_id  path      int                           true  "FAQ条目ID(seq_id)"
+// @Param        request   body      types.FAQEntryCommentRequest  true  "评论内容"
+// @Success      200       {object}  map[string]interface{}        "创建的评论"
+// @Failure      400       {object}  errors.AppError               "请求参数错误"
+// @Failure      404       {object}  errors.AppError               "条目不存在"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /knowledge-bases/{id}/faq/entries/{entry_id}/comments [post]
+func (h *FAQHandler) AddEntryComment(c *gin.Context) {
+	ctx := c.Request.Context()
+	entrySeqID, err := strconv.ParseInt(c.Param("entry_id"), 10, 64)
+	if err != nil {
+		c.Error(errors.NewBadRequestError("entry_id 必须是整数"))
+		return
+	}
+
+	var req types.FAQEntryCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error(ctx, "Failed to bind FAQ comment request", err)
+		c.Error(errors.NewBadRequestError("请求参数不合法").WithDetails(err.Error()))
+		return
+	}
+
+	comment, err := h.knowledgeService.AddFAQEntryComment(ctx,
+		secutils.SanitizeForLog(c.Param("id")), entrySeqID, req.Content)
+	if err != nil {
+		logger.ErrorWithFields(ctx, err, nil)
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    comment,
+	})
+}
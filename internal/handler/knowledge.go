@@ -93,6 +93,7 @@ func (h *KnowledgeHandler) handleDuplicateKnowledgeError(c *gin.Context,
 // @Param        file              formData  file    true   "上传的文件"
 // @Param        fileName          formData  string  false  "自定义文件名"
 // @Param        metadata          formData  string  false  "元数据JSON"
+// @Param        custom_metadata   formData  string  false  "自定义元数据JSON对象，用于检索过滤"
 // @Param        enable_multimodel formData  bool    false  "启用多模态处理"
 // @Success      200               {object}  map[string]interface{}  "创建的知识"
 // @Failure      400               {object}  errors.AppError         "请求参数错误"
@@ -152,6 +153,16 @@ func (h *KnowledgeHandler) CreateKnowledgeFromFile(c *gin.Context) {
 		logger.Infof(ctx, "Received file metadata: %s", secutils.SanitizeForLog(fmt.Sprintf("%v", metadata)))
 	}
 
+	// Parse custom metadata used by metadata filters if provided
+	var customMetadata map[string]any
+	if customMetadataStr := c.PostForm("custom_metadata"); customMetadataStr != "" {
+		if err := json.Unmarshal([]byte(customMetadataStr), &customMetadata); err != nil {
+			logger.Error(ctx, "Failed to parse custom metadata", err)
+			c.Error(errors.NewBadRequestError("Invalid custom_metadata format").WithDetails(err.Error()))
+			return
+		}
+	}
+
 	enableMultimodelForm := c.PostForm("enable_multimodel")
 	var enableMultimodel *bool
 	if enableMultimodelForm != "" {
@@ -172,7 +183,9 @@ func (h *KnowledgeHandler) CreateKnowledgeFromFile(c *gin.Context) {
 	}
 
 	// Create knowledge entry from the file
-	knowledge, err := h.kgService.CreateKnowledgeFromFile(ctx, kbID, file, metadata, enableMultimodel, customFileName, tagID)
+	knowledge, err := h.kgService.CreateKnowledgeFromFile(
+		ctx, kbID, file, metadata, enableMultimodel, customFileName, tagID, customMetadata,
+	)
 	// Check for duplicate knowledge error
 	if err != nil {
 		if h.handleDuplicateKnowledgeError(c, err, knowledge, "file") {
@@ -199,6 +212,84 @@ func (h *KnowledgeHandler) CreateKnowledgeFromFile(c *gin.Context) {
 	})
 }
 
+// PreviewKnowledgeChunks godoc
+// @Summary      预览文件分块
+// @Description  使用候选分块配置解析上传的文件并返回分块、图片和Token统计，不创建知识。可同时传入两套配置进行对比
+// @Tags         知识管理
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id                      path      string  true   "知识库ID"
+// @Param        file                    formData  file    true   "上传的文件"
+// @Param        chunking_config         formData  string  false  "分块配置JSON，默认使用知识库配置"
+// @Param        compare_chunking_config formData  string  false  "用于对比的第二套分块配置JSON"
+// @Param        enable_multimodel       formData  bool    false  "启用多模态处理"
+// @Success      200                     {object}  map[string]interface{}  "各配置的分块预览"
+// @Failure      400                     {object}  errors.AppError         "请求参数错误"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /knowledge-bases/{id}/knowledge/preview [post]
+func (h *KnowledgeHandler) PreviewKnowledgeChunks(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	_, kbID, err := h.validateKnowledgeBaseAccess(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		logger.Error(ctx, "File upload failed", err)
+		c.Error(errors.NewBadRequestError("File upload failed").WithDetails(err.Error()))
+		return
+	}
+	if file.Size > secutils.GetMaxFileSize() {
+		c.Error(errors.NewBadRequestError(fmt.Sprintf("file size cannot exceed %dMB", secutils.GetMaxFileSizeMB())))
+		return
+	}
+
+	// Candidate configs in order, none previews the knowledge base config
+	var configs []types.ChunkingConfig
+	for _, field := range []string{"chunking_config", "compare_chunking_config"} {
+		value := c.PostForm(field)
+		if value == "" {
+			continue
+		}
+		var config types.ChunkingConfig
+		if err := json.Unmarshal([]byte(value), &config); err != nil {
+			c.Error(errors.NewBadRequestError(fmt.Sprintf("Invalid %s format", field)).WithDetails(err.Error()))
+			return
+		}
+		configs = append(configs, config)
+	}
+
+	var enableMultimodel bool
+	if value := c.PostForm("enable_multimodel"); value != "" {
+		if enableMultimodel, err = strconv.ParseBool(value); err != nil {
+			c.Error(errors.NewBadRequestError("Invalid enable_multimodel format").WithDetails(err.Error()))
+			return
+		}
+	}
+
+	logger.Infof(ctx, "Previewing chunks, knowledge base ID: %s, filename: %s, configs: %d",
+		kbID, secutils.SanitizeForLog(file.Filename), len(configs))
+	previews, err := h.kgService.PreviewChunks(ctx, kbID, file, configs, enableMultimodel)
+	if err != nil {
+		if appErr, ok := errors.IsAppError(err); ok {
+			c.Error(appErr)
+			return
+		}
+		logger.ErrorWithFields(ctx, err, nil)
+		c.Error(errors.NewInternalServerError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    previews,
+	})
+}
+
 // CreateKnowledgeFromURL godoc
 // @Summary      从URL创建知识
 // @Description  从指定URL抓取内容并创建知识条目
@@ -206,7 +297,7 @@ func (h *KnowledgeHandler) CreateKnowledgeFromFile(c *gin.Context) {
 // @Accept       json
 // @Produce      json
 // @Param        id       path      string  true  "知识库ID"
-// @Param        request  body      object{url=string,enable_multimodel=bool,title=string,tag_id=string}  true  "URL请求"
+// @Param        request  body      object{url=string,enable_multimodel=bool,title=string,tag_id=string,custom_metadata=object}  true  "URL请求"
 // @Success      201      {object}  map[string]interface{}  "创建的知识"
 // @Failure      400      {object}  errors.AppError         "请求参数错误"
 // @Failure      409      {object}  map[string]interface{}  "URL重复"
@@ -226,10 +317,11 @@ func (h *KnowledgeHandler) CreateKnowledgeFromURL(c *gin.Context) {
 
 	// Parse URL from request body
 	var req struct {
-		URL              string `json:"url" binding:"required"`
-		EnableMultimodel *bool  `json:"enable_multimodel"`
-		Title            string `json:"title"`
-		TagID            string `json:"tag_id"`
+		URL              string         `json:"url" binding:"required"`
+		EnableMultimodel *bool          `json:"enable_multimodel"`
+		Title            string         `json:"title"`
+		TagID            string         `json:"tag_id"`
+		CustomMetadata   map[string]any `json:"custom_metadata"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		logger.Error(ctx, "Failed to parse URL request", err)
@@ -246,7 +338,9 @@ func (h *KnowledgeHandler) CreateKnowledgeFromURL(c *gin.Context) {
 	)
 
 	// Create knowledge entry from the URL
-	knowledge, err := h.kgService.CreateKnowledgeFromURL(ctx, kbID, req.URL, req.EnableMultimodel, req.Title, req.TagID)
+	knowledge, err := h.kgService.CreateKnowledgeFromURL(
+		ctx, kbID, req.URL, req.EnableMultimodel, req.Title, req.TagID, req.CustomMetadata,
+	)
 	// Check for duplicate knowledge error
 	if err != nil {
 		if h.handleDuplicateKnowledgeError(c, err, knowledge, "url") {
@@ -610,7 +704,7 @@ func (h *KnowledgeHandler) GetKnowledgeBatch(c *gin.Context) {
 
 // UpdateKnowledge godoc
 // @Summary      更新知识
-// @Description  更新知识条目信息
+// @Description  更新知识条目信息，custom_metadata 会整体替换并同步到检索索引
 // @Tags         知识管理
 // @Accept       json
 // @Produce      json
@@ -638,7 +732,15 @@ func (h *KnowledgeHandler) UpdateKnowledge(c *gin.Context) {
 		return
 	}
 
+	if knowledge.ID == "" {
+		knowledge.ID = c.Param("id")
+	}
+
 	if err := h.kgService.UpdateKnowledge(ctx, &knowledge); err != nil {
+		if appErr, ok := errors.IsAppError(err); ok {
+			c.Error(appErr)
+			return
+		}
 		logger.ErrorWithFields(ctx, err, nil)
 		c.Error(errors.NewInternalServerError(err.Error()))
 		return
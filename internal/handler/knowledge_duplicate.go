@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Tencent/WeKnora/internal/errors"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+	secutils "github.com/Tencent/WeKnora/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// GetDuplicateReport godoc
+// @Summary      获取重复文档报告
+// @Description  基于内容签名（MinHash）将知识库中内容相似度不低于阈值的文档分组，每组保留最新创建的文档
+// @Tags         知识管理
+// @Accept       json
+// @Produce      json
+// @Param        id         path      string   true   "知识库ID"
+// @Param        threshold  query     number   false  "相似度阈值（0-1），默认使用知识库配置"
+// @Success      200        {object}  map[string]interface{}  "重复文档报告"
+// @Failure      400        {object}  errors.AppError         "请求参数错误"
+// @Failure      403        {object}  errors.AppError         "无权访问"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /knowledge-bases/{id}/knowledge/duplicates [get]
+func (h *KnowledgeHandler) GetDuplicateReport(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	_, kbID, err := h.validateKnowledgeBaseAccess(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	threshold, err := strconv.ParseFloat(c.DefaultQuery("threshold", "0"), 64)
+	if err != nil {
+		c.Error(errors.NewBadRequestError("threshold must be a number"))
+		return
+	}
+
+	report, err := h.kgService.GetDuplicateReport(ctx, kbID, threshold)
+	if err != nil {
+		if appErr, ok := errors.IsAppError(err); ok {
+			c.Error(appErr)
+			return
+		}
+		logger.ErrorWithFields(ctx, err, nil)
+		c.Error(errors.NewInternalServerError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    report,
+	})
+}
+
+// DisableDuplicateKnowledge godoc
+// @Summary      禁用重复文档
+// @Description  在检索中禁用重复文档的冗余副本。未指定知识ID时禁用每组中除保留文档外的所有文档
+// @Tags         知识管理
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                         true  "知识库ID"
+// @Param        request  body      types.DuplicateDisableRequest  true  "禁用请求"
+// @Success      200      {object}  map[string]interface{}         "已禁用的知识ID"
+// @Failure      400      {object}  errors.AppError                "请求参数错误"
+// @Failure      403      {object}  errors.AppError                "无权访问"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /knowledge-bases/{id}/knowledge/duplicates/disable [post]
+func (h *KnowledgeHandler) DisableDuplicateKnowledge(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	_, kbID, err := h.validateKnowledgeBaseAccess(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req types.DuplicateDisableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error(ctx, "Failed to parse duplicate disable request", err)
+		c.Error(errors.NewBadRequestError(err.Error()))
+		return
+	}
+
+	disabled, err := h.kgService.DisableDuplicateKnowledge(ctx, kbID, &req)
+	if err != nil {
+		if appErr, ok := errors.IsAppError(err); ok {
+			c.Error(appErr)
+			return
+		}
+		logger.ErrorWithFields(ctx, err, nil)
+		c.Error(errors.NewInternalServerError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    disabled,
+		"total":   len(disabled),
+	})
+}
+
+// UpdateKnowledgeEnabled godoc
+// @Summary      启用或禁用知识
+// @Description  在所有检索引擎中启用或禁用已处理完成的知识。不在有效期内的知识启用后仍保持失效状态
+// @Tags         知识管理
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                        true  "知识ID"
+// @Param        request  body      types.KnowledgeEnableRequest  true  "启用状态"
+// @Success      200      {object}  map[string]interface{}        "更新后的知识"
+// @Failure      400      {object}  errors.AppError               "请求参数错误"
+// @Failure      404      {object}  errors.AppError               "知识不存在"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /knowledge/{id}/enabled [put]
+func (h *KnowledgeHandler) UpdateKnowledgeEnabled(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id := secutils.SanitizeForLog(c.Param("id"))
+	if id == "" {
+		logger.Error(ctx, "Knowledge ID is empty")
+		c.Error(errors.NewBadRequestError("Knowledge ID cannot be empty"))
+		return
+	}
+
+	var req types.KnowledgeEnableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error(ctx, "Failed to parse knowledge enable request", err)
+		c.Error(errors.NewBadRequestError(err.Error()))
+		return
+	}
+
+	knowledge, err := h.kgService.UpdateKnowledgeEnabled(ctx, id, req.Enabled)
+	if err != nil {
+		if appErr, ok := errors.IsAppError(err); ok {
+			c.Error(appErr)
+			return
+		}
+		logger.ErrorWithFields(ctx, err, nil)
+		c.Error(errors.NewInternalServerError(err.Error()))
+		return
+	}
+
+	logger.Infof(ctx, "Knowledge enable status updated, knowledge ID: %s, status: %s", id, knowledge.EnableStatus)
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    knowledge,
+	})
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Tencent/WeKnora/internal/errors"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+	secutils "github.com/Tencent/WeKnora/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// UpdateKnowledgeValidity godoc
+// @Summary      设置知识有效期
+// @Description  设置知识的生效时间、失效时间与复审时间，未提供的字段将被清除。不在有效期内的知识会在所有检索引擎中禁用，恢复有效后自动重新启用
+// @Tags         知识管理
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                          true  "知识ID"
+// @Param        request  body      types.KnowledgeValidityRequest  true  "有效期"
+// @Success      200      {object}  map[string]interface{}          "更新后的知识"
+// @Failure      400      {object}  errors.AppError                 "请求参数错误"
+// @Failure      404      {object}  errors.AppError                 "知识不存在"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /knowledge/{id}/validity [put]
+func (h *KnowledgeHandler) UpdateKnowledgeValidity(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id := secutils.SanitizeForLog(c.Param("id"))
+	if id == "" {
+		logger.Error(ctx, "Knowledge ID is empty")
+		c.Error(errors.NewBadRequestError("Knowledge ID cannot be empty"))
+		return
+	}
+
+	var req types.KnowledgeValidityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error(ctx, "Failed to parse knowledge validity request", err)
+		c.Error(errors.NewBadRequestError(err.Error()))
+		return
+	}
+
+	knowledge, err := h.kgService.UpdateKnowledgeValidity(ctx, id, &req)
+	if err != nil {
+		if appErr, ok := errors.IsAppError(err); ok {
+			c.Error(appErr)
+			return
+		}
+		logger.ErrorWithFields(ctx, err, nil)
+		c.Error(errors.NewInternalServerError(err.Error()))
+		return
+	}
+
+	logger.Infof(ctx, "Knowledge validity updated, knowledge ID: %s", id)
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    knowledge,
+	})
+}
+
+// ListExpiringKnowledge godoc
+// @Summary      获取即将过期的知识
+// @Description  获取知识库下在指定天数内失效或需要复审的知识，包括已失效和已逾期未复审的知识，按时间先后排序
+// @Tags         知识管理
+// @Accept       json
+// @Produce      json
+// @Param        id    path      string  true   "知识库ID"
+// @Param        days  query     int     false  "天数"  default(30)
+// @Success      200   {object}  map[string]interface{}  "知识列表"
+// @Failure      400   {object}  errors.AppError         "请求参数错误"
+// @Failure      403   {object}  errors.AppError         "无权访问"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /knowledge-bases/{id}/knowledge/expiring [get]
+func (h *KnowledgeHandler) ListExpiringKnowledge(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	_, kbID, err := h.validateKnowledgeBaseAccess(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", "0"))
+	if err != nil || days < 0 {
+		c.Error(errors.NewBadRequestError("days must be a non-negative integer"))
+		return
+	}
+
+	knowledgeList, err := h.kgService.ListExpiringKnowledge(ctx, kbID, days)
+	if err != nil {
+		logger.ErrorWithFields(ctx, err, nil)
+		c.Error(errors.NewInternalServerError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    knowledgeList,
+		"total":   len(knowledgeList),
+	})
+}
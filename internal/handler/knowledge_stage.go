@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Tencent/WeKnora/internal/errors"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+	secutils "github.com/Tencent/WeKnora/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// handleStageError reports a stage service error, keeping the status of application errors
+func handleStageError(c *gin.Context, err error) {
+	if appErr, ok := errors.IsAppError(err); ok {
+		c.Error(appErr)
+		return
+	}
+	logger.ErrorWithFields(c.Request.Context(), err, nil)
+	c.Error(errors.NewInternalServerError(err.Error()))
+}
+
+// ListKnowledgeStages godoc
+// @Summary      获取知识处理阶段
+// @Description  获取知识各处理阶段（解析、向量索引、问题生成、摘要生成、图谱抽取）的状态、耗时、错误信息与尝试次数
+// @Tags         知识管理
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "知识ID"
+// @Success      200  {object}  map[string]interface{}  "处理阶段列表"
+// @Failure      400  {object}  errors.AppError         "请求参数错误"
+// @Failure      404  {object}  errors.AppError         "知识不存在"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /knowledge/{id}/stages [get]
+func (h *KnowledgeHandler) ListKnowledgeStages(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id := secutils.SanitizeForLog(c.Param("id"))
+	if id == "" {
+		logger.Error(ctx, "Knowledge ID is empty")
+		c.Error(errors.NewBadRequestError("Knowledge ID cannot be empty"))
+		return
+	}
+
+	stages, err := h.kgService.ListKnowledgeStages(ctx, id)
+	if err != nil {
+		handleStageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    stages,
+	})
+}
+
+// RetryKnowledgeStage godoc
+// @Summary      重试知识处理阶段
+// @Description  仅重新执行知识失败的处理阶段，不重跑之前已完成的阶段
+// @Tags         知识管理
+// @Accept       json
+// @Produce      json
+// @Param        id     path      string  true  "知识ID"
+// @Param        stage  path      string  true  "处理阶段: parse, index, question_generation, summary_generation, graph_extraction"
+// @Success      200    {object}  map[string]interface{}  "重试已提交"
+// @Failure      400    {object}  errors.AppError         "请求参数错误或阶段未失败"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /knowledge/{id}/stages/{stage}/retry [post]
+func (h *KnowledgeHandler) RetryKnowledgeStage(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id := secutils.SanitizeForLog(c.Param("id"))
+	if id == "" {
+		logger.Error(ctx, "Knowledge ID is empty")
+		c.Error(errors.NewBadRequestError("Knowledge ID cannot be empty"))
+		return
+	}
+	stage := types.ProcessingStage(c.Param("stage"))
+
+	logger.Infof(ctx, "Retrying knowledge stage, knowledge ID: %s, stage: %s", id, secutils.SanitizeForLog(string(stage)))
+	if err := h.kgService.RetryKnowledgeStage(ctx, id, stage); err != nil {
+		handleStageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+	})
+}
+
+// ListFailedKnowledge godoc
+// @Summary      获取处理失败的知识
+// @Description  获取知识库下存在失败处理阶段的知识及其阶段详情
+// @Tags         知识管理
+// @Accept       json
+// @Produce      json
+// @Param        id   path      string  true  "知识库ID"
+// @Success      200  {object}  map[string]interface{}  "失败知识列表"
+// @Failure      400  {object}  errors.AppError         "请求参数错误"
+// @Failure      403  {object}  errors.AppError         "无权访问"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /knowledge-bases/{id}/knowledge/failed [get]
+func (h *KnowledgeHandler) ListFailedKnowledge(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	_, kbID, err := h.validateKnowledgeBaseAccess(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	failed, err := h.kgService.ListFailedKnowledge(ctx, kbID)
+	if err != nil {
+		handleStageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    failed,
+		"total":   len(failed),
+	})
+}
+
+// RetryFailedKnowledge godoc
+// @Summary      批量重试失败的知识
+// @Description  重试知识库下失败的处理阶段。可指定知识ID和阶段，不指定时重试全部失败知识的最早失败阶段
+// @Tags         知识管理
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                      true  "知识库ID"
+// @Param        request  body      types.KnowledgeRetryRequest  false  "重试请求"
+// @Success      200      {object}  map[string]interface{}      "重试结果"
+// @Failure      400      {object}  errors.AppError             "请求参数错误"
+// @Failure      403      {object}  errors.AppError             "无权访问"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /knowledge-bases/{id}/knowledge/retry [post]
+func (h *KnowledgeHandler) RetryFailedKnowledge(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	_, kbID, err := h.validateKnowledgeBaseAccess(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req types.KnowledgeRetryRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logger.Error(ctx, "Failed to parse knowledge retry request", err)
+			c.Error(errors.NewBadRequestError("请求参数不合法").WithDetails(err.Error()))
+			return
+		}
+	}
+
+	result, err := h.kgService.RetryFailedKnowledge(ctx, kbID, &req)
+	if err != nil {
+		handleStageError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/Tencent/WeKnora/internal/errors"
 	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
 	"github.com/Tencent/WeKnora/internal/types/interfaces"
 	secutils "github.com/Tencent/WeKnora/internal/utils"
 )
@@ -16,17 +17,27 @@ import (
 // MessageHandler handles HTTP requests related to messages within chat sessions
 // It provides endpoints for loading and managing message history
 type MessageHandler struct {
-	MessageService interfaces.MessageService // Service that implements message business logic
+	MessageService      interfaces.MessageService      // Service that implements message business logic
+	WebKnowledgeService interfaces.WebKnowledgeService // Service that saves web references as knowledge
+	AnalyticsService    interfaces.AnalyticsService    // Service that records answer feedback
 }
 
 // NewMessageHandler creates a new message handler instance with the required service
 // Parameters:
 //   - messageService: Service that implements message business logic
+//   - webKnowledgeService: Service that saves web references as knowledge
+//   - analyticsService: Service that records answer feedback
 //
 // Returns a pointer to a new MessageHandler
-func NewMessageHandler(messageService interfaces.MessageService) *MessageHandler {
+func NewMessageHandler(
+	messageService interfaces.MessageService,
+	webKnowledgeService interfaces.WebKnowledgeService,
+	analyticsService interfaces.AnalyticsService,
+) *MessageHandler {
 	return &MessageHandler{
-		MessageService: messageService,
+		MessageService:      messageService,
+		WebKnowledgeService: webKnowledgeService,
+		AnalyticsService:    analyticsService,
 	}
 }
 
@@ -156,3 +167,99 @@ func (h *MessageHandler) DeleteMessage(c *gin.Context) {
 		"message": "Message deleted successfully",
 	})
 }
+
+// SaveWebReferences godoc
+// @Summary      保存网页引用到知识库
+// @Description  抓取消息中选中的网络搜索引用的完整网页内容，并作为知识保存到指定知识库，保留来源URL与抓取时间
+// @Tags         消息
+// @Accept       json
+// @Produce      json
+// @Param        session_id  path      string                          true  "会话ID"
+// @Param        id          path      string                          true  "消息ID"
+// @Param        request     body      types.SaveWebReferencesRequest  true  "保存请求"
+// @Success      200         {object}  map[string]interface{}          "每个网页的保存结果"
+// @Failure      400         {object}  errors.AppError                 "请求参数错误"
+// @Failure      403         {object}  errors.AppError                 "无权访问该知识库"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /messages/{session_id}/{id}/web-references [post]
+func (h *MessageHandler) SaveWebReferences(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	sessionID := secutils.SanitizeForLog(c.Param("session_id"))
+	messageID := secutils.SanitizeForLog(c.Param("id"))
+
+	var req types.SaveWebReferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error(ctx, "Failed to parse save web references request", err)
+		c.Error(errors.NewBadRequestError(err.Error()))
+		return
+	}
+
+	logger.Infof(ctx, "Saving web references, session ID: %s, message ID: %s, knowledge base ID: %s, count: %d",
+		sessionID, messageID, secutils.SanitizeForLog(req.KnowledgeBaseID), len(req.URLs))
+
+	results, err := h.WebKnowledgeService.SaveWebReferences(ctx, sessionID, messageID, &req)
+	if err != nil {
+		if appErr, ok := errors.IsAppError(err); ok {
+			c.Error(appErr)
+			return
+		}
+		logger.ErrorWithFields(ctx, err, nil)
+		c.Error(errors.NewInternalServerError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    results,
+	})
+}
+
+// SubmitFeedback godoc
+// @Summary      评价助手回复
+// @Description  对助手回复点赞或点踩，可附带评论；每条消息只保留一条评价，重复提交会覆盖。评价会关联到回复引用的知识分块，用于知识库统计
+// @Tags         消息
+// @Accept       json
+// @Produce      json
+// @Param        session_id  path      string                        true  "会话ID"
+// @Param        id          path      string                        true  "消息ID"
+// @Param        request     body      types.MessageFeedbackRequest  true  "评价内容"
+// @Success      200         {object}  map[string]interface{}        "保存的评价"
+// @Failure      400         {object}  errors.AppError               "请求参数错误"
+// @Failure      404         {object}  errors.AppError               "消息不存在"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /messages/{session_id}/{id}/feedback [put]
+func (h *MessageHandler) SubmitFeedback(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	sessionID := secutils.SanitizeForLog(c.Param("session_id"))
+	messageID := secutils.SanitizeForLog(c.Param("id"))
+
+	var req types.MessageFeedbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error(ctx, "Failed to parse message feedback request", err)
+		c.Error(errors.NewBadRequestError(err.Error()))
+		return
+	}
+
+	logger.Infof(ctx, "Submitting message feedback, session ID: %s, message ID: %s, rating: %s",
+		sessionID, messageID, secutils.SanitizeForLog(string(req.Rating)))
+
+	feedback, err := h.AnalyticsService.SubmitMessageFeedback(ctx, sessionID, messageID, &req)
+	if err != nil {
+		if appErr, ok := errors.IsAppError(err); ok {
+			c.Error(appErr)
+			return
+		}
+		logger.ErrorWithFields(ctx, err, nil)
+		c.Error(errors.NewInternalServerError(err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    feedback,
+	})
+}
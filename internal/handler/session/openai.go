@@ -0,0 +1,459 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/event"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+	secutils "github.com/Tencent/WeKnora/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// openAIModelKBPrefix selects a knowledge base instead of a custom agent as the model
+	openAIModelKBPrefix = "kb:"
+	// openAIOwner is reported as owned_by in the model list
+	openAIOwner = "weknora"
+	// openAISessionHeader selects an existing session when session_id is not set in the body
+	openAISessionHeader = "X-Session-ID"
+	// openAIDeltaBufferSize is the number of stream deltas buffered before the pipeline blocks
+	openAIDeltaBufferSize = 256
+	// maxOpenAISessionTitleLength is the maximum length of a session title derived from the first message
+	maxOpenAISessionTitleLength = 50
+)
+
+// writeOpenAIError writes an error in the OpenAI error format
+func writeOpenAIError(c *gin.Context, status int, message, errType, code string) {
+	c.JSON(status, gin.H{
+		"error": gin.H{
+			"message": message,
+			"type":    errType,
+			"code":    code,
+		},
+	})
+}
+
+// ListOpenAIModels godoc
+// @Summary      模型列表（OpenAI兼容）
+// @Description  以OpenAI格式列出可用的模型，包括自定义智能体和知识库（kb:<id>）
+// @Tags         OpenAI兼容
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}  "模型列表"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /v1/models [get]
+func (h *Handler) ListOpenAIModels(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	agents, err := h.customAgentService.ListAgents(ctx)
+	if err != nil {
+		logger.ErrorWithFields(ctx, err, nil)
+		writeOpenAIError(c, http.StatusInternalServerError, err.Error(), "server_error", "")
+		return
+	}
+	kbs, err := h.knowledgebaseService.ListKnowledgeBases(ctx)
+	if err != nil {
+		logger.ErrorWithFields(ctx, err, nil)
+		writeOpenAIError(c, http.StatusInternalServerError, err.Error(), "server_error", "")
+		return
+	}
+
+	models := make([]OpenAIModel, 0, len(agents)+len(kbs))
+	for _, agent := range agents {
+		models = append(models, OpenAIModel{
+			ID:      agent.ID,
+			Object:  "model",
+			Created: agent.CreatedAt.Unix(),
+			OwnedBy: openAIOwner,
+			Name:    agent.Name,
+		})
+	}
+	for _, kb := range kbs {
+		models = append(models, OpenAIModel{
+			ID:      openAIModelKBPrefix + kb.ID,
+			Object:  "model",
+			Created: kb.CreatedAt.Unix(),
+			OwnedBy: openAIOwner,
+			Name:    kb.Name,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"object": "list",
+		"data":   models,
+	})
+}
+
+// ChatCompletions godoc
+// @Summary      对话补全（OpenAI兼容）
+// @Description  OpenAI兼容的对话补全接口，model为自定义智能体ID或kb:<知识库ID>，支持stream流式响应
+// @Tags         OpenAI兼容
+// @Accept       json
+// @Produce      json
+// @Produce      text/event-stream
+// @Param        request  body      OpenAIChatCompletionRequest   true  "对话补全请求"
+// @Success      200      {object}  OpenAIChatCompletionResponse  "对话补全结果"
+// @Failure      400      {object}  map[string]interface{}        "请求参数错误"
+// @Failure      404      {object}  map[string]interface{}        "模型或会话不存在"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /v1/chat/completions [post]
+func (h *Handler) ChatCompletions(c *gin.Context) {
+	ctx := logger.CloneContext(c.Request.Context())
+	logger.Info(ctx, "[ChatCompletions] Start processing request")
+
+	var request OpenAIChatCompletionRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		logger.Error(ctx, "Failed to parse request data", err)
+		writeOpenAIError(c, http.StatusBadRequest, err.Error(), "invalid_request_error", "")
+		return
+	}
+
+	// The last message is the query, everything before it is history
+	last := request.Messages[len(request.Messages)-1]
+	if last.Role != "user" || strings.TrimSpace(string(last.Content)) == "" {
+		writeOpenAIError(c, http.StatusBadRequest,
+			"The last message must be a non-empty user message", "invalid_request_error", "invalid_messages")
+		return
+	}
+
+	// Resolve the model to a custom agent or a knowledge base
+	var customAgent *types.CustomAgent
+	var knowledgeBaseIDs []string
+	if kbID, ok := strings.CutPrefix(request.Model, openAIModelKBPrefix); ok {
+		if _, err := h.knowledgebaseService.GetKnowledgeBaseByID(ctx, kbID); err != nil {
+			logger.Warnf(ctx, "Knowledge base not found for model %s: %v", secutils.SanitizeForLog(request.Model), err)
+			writeOpenAIError(c, http.StatusNotFound,
+				fmt.Sprintf("The model '%s' does not exist", request.Model), "invalid_request_error", "model_not_found")
+			return
+		}
+		knowledgeBaseIDs = []string{kbID}
+	} else {
+		agent, err := h.customAgentService.GetAgentByID(ctx, request.Model)
+		if err != nil {
+			logger.Warnf(ctx, "Custom agent not found for model %s: %v", secutils.SanitizeForLog(request.Model), err)
+			writeOpenAIError(c, http.StatusNotFound,
+				fmt.Sprintf("The model '%s' does not exist", request.Model), "invalid_request_error", "model_not_found")
+			return
+		}
+		customAgent = agent
+	}
+
+	session, err := h.resolveOpenAISession(ctx, c, &request)
+	if err != nil {
+		return
+	}
+
+	logger.Infof(ctx, "[ChatCompletions] model=%s, session_id=%s, stream=%v, messages=%d, user=%s",
+		secutils.SanitizeForLog(request.Model), session.ID, request.Stream, len(request.Messages),
+		secutils.SanitizeForLog(request.User))
+
+	reqCtx := &qaRequestContext{
+		ctx:         ctx,
+		c:           c,
+		sessionID:   session.ID,
+		requestID:   secutils.SanitizeForLog(c.GetString(types.RequestIDContextKey.String())),
+		query:       secutils.SanitizeForLog(string(last.Content)),
+		session:     session,
+		customAgent: customAgent,
+		assistantMessage: &types.Message{
+			SessionID:   session.ID,
+			Role:        "assistant",
+			RequestID:   c.GetString(types.RequestIDContextKey.String()),
+			IsCompleted: false,
+		},
+		knowledgeBaseIDs: knowledgeBaseIDs,
+		webSearchEnabled: request.WebSearchEnabled,
+	}
+
+	// Prepare messages and pick the pipeline
+	start := h.startNormalModeQA
+	if customAgent != nil && customAgent.IsAgentMode() {
+		if err := h.prepareAgentModeQA(reqCtx); err != nil {
+			writeOpenAIError(c, http.StatusInternalServerError, err.Error(), "server_error", "")
+			return
+		}
+		start = h.startAgentModeQA
+	} else if err := h.prepareNormalModeQA(reqCtx); err != nil {
+		writeOpenAIError(c, http.StatusInternalServerError, err.Error(), "server_error", "")
+		return
+	}
+
+	promptTokens := 0
+	for _, message := range request.Messages {
+		promptTokens += estimateTokens(string(message.Content))
+	}
+
+	if request.Stream {
+		h.streamChatCompletion(reqCtx, &request, promptTokens, start)
+		return
+	}
+	h.syncChatCompletion(reqCtx, &request, promptTokens, start)
+}
+
+// resolveOpenAISession returns the session selected by the request, or creates a new one seeded
+// with the prior conversation turns. The error response is written before an error is returned.
+func (h *Handler) resolveOpenAISession(
+	ctx context.Context,
+	c *gin.Context,
+	request *OpenAIChatCompletionRequest,
+) (*types.Session, error) {
+	sessionID := request.SessionID
+	if sessionID == "" {
+		sessionID = c.GetHeader(openAISessionHeader)
+	}
+	if sessionID != "" {
+		session, err := h.sessionService.GetSession(ctx, sessionID)
+		if err != nil {
+			logger.Errorf(ctx, "Failed to get session, session ID: %s, error: %v", secutils.SanitizeForLog(sessionID), err)
+			writeOpenAIError(c, http.StatusNotFound, "Session not found", "invalid_request_error", "session_not_found")
+			return nil, err
+		}
+		return session, nil
+	}
+
+	tenantID, exists := c.Get(types.TenantIDContextKey.String())
+	if !exists {
+		logger.Error(ctx, "Failed to get tenant ID")
+		writeOpenAIError(c, http.StatusUnauthorized, "Unauthorized", "authentication_error", "")
+		return nil, fmt.Errorf("tenant ID not found in context")
+	}
+
+	// Title the session with the first user message instead of generating one with the LLM
+	title := ""
+	for _, message := range request.Messages {
+		if message.Role == "user" {
+			title = truncateRunes(strings.TrimSpace(string(message.Content)), maxOpenAISessionTitleLength)
+			break
+		}
+	}
+
+	session, err := h.sessionService.CreateSession(ctx, &types.Session{
+		TenantID: tenantID.(uint64),
+		Title:    title,
+	})
+	if err != nil {
+		logger.ErrorWithFields(ctx, err, nil)
+		writeOpenAIError(c, http.StatusInternalServerError, err.Error(), "server_error", "")
+		return nil, err
+	}
+	logger.Infof(ctx, "Created session for chat completion, ID: %s", session.ID)
+
+	// Seed the prior turns so that multi-turn pipelines see the conversation history
+	requestID := c.GetString(types.RequestIDContextKey.String())
+	for _, message := range request.Messages[:len(request.Messages)-1] {
+		if message.Role != "user" && message.Role != "assistant" {
+			continue
+		}
+		if _, err := h.messageService.CreateMessage(ctx, &types.Message{
+			SessionID:   session.ID,
+			Role:        message.Role,
+			Content:     string(message.Content),
+			RequestID:   requestID,
+			CreatedAt:   time.Now(),
+			IsCompleted: true,
+		}); err != nil {
+			logger.ErrorWithFields(ctx, err, nil)
+			writeOpenAIError(c, http.StatusInternalServerError, err.Error(), "server_error", "")
+			return nil, err
+		}
+	}
+	return session, nil
+}
+
+// syncChatCompletion waits for the answer and writes a single chat.completion object
+func (h *Handler) syncChatCompletion(
+	reqCtx *qaRequestContext,
+	request *OpenAIChatCompletionRequest,
+	promptTokens int,
+	start func(*qaRequestContext, *sseStreamContext) <-chan struct{},
+) {
+	startTime := time.Now()
+	streamCtx := h.setupEventStream(reqCtx, false)
+	collector := newSyncQACollector(streamCtx.eventBus)
+	serviceDone := start(reqCtx, streamCtx)
+
+	timeout := h.syncQATimeout(0)
+	switch h.waitSyncQA(reqCtx, streamCtx, collector, serviceDone, timeout, startTime) {
+	case syncQATimedOut:
+		writeOpenAIError(reqCtx.c, http.StatusGatewayTimeout,
+			"Timed out waiting for the answer", "timeout", "timeout")
+		return
+	case syncQADisconnected:
+		return
+	case syncQAFailed:
+		writeOpenAIError(reqCtx.c, http.StatusInternalServerError, collector.errorMessage(), "server_error", "")
+		return
+	}
+
+	result := collector.result(reqCtx, startTime)
+	completionTokens := estimateTokens(result.Answer)
+	reqCtx.c.JSON(http.StatusOK, &OpenAIChatCompletionResponse{
+		ID:      "chatcmpl-" + reqCtx.assistantMessage.ID,
+		Object:  "chat.completion",
+		Created: startTime.Unix(),
+		Model:   request.Model,
+		Choices: []OpenAIChoice{{
+			Index:        0,
+			Message:      OpenAIResponseMessage{Role: "assistant", Content: result.Answer},
+			FinishReason: "stop",
+		}},
+		Usage: OpenAIUsage{
+			PromptTokens:     promptTokens,
+			CompletionTokens: completionTokens,
+			TotalTokens:      promptTokens + completionTokens,
+		},
+		SessionID:  reqCtx.sessionID,
+		References: result.KnowledgeReferences,
+	})
+}
+
+// streamChatCompletion streams the answer as chat.completion.chunk server-sent events
+func (h *Handler) streamChatCompletion(
+	reqCtx *qaRequestContext,
+	request *OpenAIChatCompletionRequest,
+	promptTokens int,
+	start func(*qaRequestContext, *sseStreamContext) <-chan struct{},
+) {
+	c := reqCtx.c
+	startTime := time.Now()
+	setSSEHeaders(c)
+
+	streamCtx := h.setupEventStream(reqCtx, false)
+	collector := newSyncQACollector(streamCtx.eventBus)
+
+	chunk := func(delta OpenAIDelta, finishReason *string) *OpenAIChatCompletionChunk {
+		return &OpenAIChatCompletionChunk{
+			ID:      "chatcmpl-" + reqCtx.assistantMessage.ID,
+			Object:  "chat.completion.chunk",
+			Created: startTime.Unix(),
+			Model:   request.Model,
+			Choices: []OpenAIChunkChoice{{Index: 0, Delta: delta, FinishReason: finishReason}},
+		}
+	}
+
+	// Event handlers run on the pipeline goroutine, deltas are handed over to the writer
+	// goroutine which is the only one writing to the response until stop is closed
+	deltas := make(chan OpenAIDelta, openAIDeltaBufferSize)
+	stop := make(chan struct{})
+	sendDelta := func(delta OpenAIDelta) {
+		select {
+		case deltas <- delta:
+		case <-stop:
+		}
+	}
+	streamCtx.eventBus.On(event.EventAgentFinalAnswer, func(ctx context.Context, evt event.Event) error {
+		if data, ok := evt.Data.(event.AgentFinalAnswerData); ok && data.Content != "" {
+			sendDelta(OpenAIDelta{Content: data.Content})
+		}
+		return nil
+	})
+	streamCtx.eventBus.On(event.EventAgentThought, func(ctx context.Context, evt event.Event) error {
+		if data, ok := evt.Data.(event.AgentThoughtData); ok && data.Content != "" {
+			sendDelta(OpenAIDelta{ReasoningContent: data.Content})
+		}
+		return nil
+	})
+
+	writeOpenAIChunk(c, chunk(OpenAIDelta{Role: "assistant"}, nil))
+
+	writerDone := make(chan struct{})
+	go func() {
+		defer close(writerDone)
+		for {
+			select {
+			case delta := <-deltas:
+				writeOpenAIChunk(c, chunk(delta, nil))
+			case <-stop:
+				// Flush everything queued before the run ended
+				for {
+					select {
+					case delta := <-deltas:
+						writeOpenAIChunk(c, chunk(delta, nil))
+					default:
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	serviceDone := start(reqCtx, streamCtx)
+	outcome := h.waitSyncQA(reqCtx, streamCtx, collector, serviceDone, h.syncQATimeout(0), startTime)
+	close(stop)
+	<-writerDone
+
+	switch outcome {
+	case syncQADisconnected:
+		return
+	case syncQATimedOut:
+		writeOpenAIChunk(c, gin.H{"error": gin.H{"message": "Timed out waiting for the answer", "type": "timeout"}})
+		writeOpenAIDone(c)
+		return
+	case syncQAFailed:
+		writeOpenAIChunk(c, gin.H{"error": gin.H{"message": collector.errorMessage(), "type": "server_error"}})
+		writeOpenAIDone(c)
+		return
+	}
+
+	result := collector.result(reqCtx, startTime)
+	finishReason := "stop"
+	final := chunk(OpenAIDelta{}, &finishReason)
+	final.SessionID = reqCtx.sessionID
+	final.References = result.KnowledgeReferences
+	writeOpenAIChunk(c, final)
+
+	if request.StreamOptions != nil && request.StreamOptions.IncludeUsage {
+		usage := chunk(OpenAIDelta{}, nil)
+		usage.Choices = []OpenAIChunkChoice{}
+		completionTokens := estimateTokens(result.Answer)
+		usage.Usage = &OpenAIUsage{
+			PromptTokens:     promptTokens,
+			CompletionTokens: completionTokens,
+			TotalTokens:      promptTokens + completionTokens,
+		}
+		writeOpenAIChunk(c, usage)
+	}
+	writeOpenAIDone(c)
+
+	logger.Infof(reqCtx.ctx, "Chat completion stream finished, session ID: %s, answer length: %d, duration: %dms",
+		reqCtx.sessionID, len(result.Answer), time.Since(startTime).Milliseconds())
+}
+
+// writeOpenAIChunk writes a single data-only server-sent event
+func writeOpenAIChunk(c *gin.Context, payload any) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		logger.Errorf(c.Request.Context(), "Failed to marshal chat completion chunk: %v", err)
+		return
+	}
+	_, _ = fmt.Fprintf(c.Writer, "data: %s\n\n", data)
+	c.Writer.Flush()
+}
+
+// writeOpenAIDone writes the stream terminator
+func writeOpenAIDone(c *gin.Context) {
+	_, _ = c.Writer.WriteString("data: [DONE]\n\n")
+	c.Writer.Flush()
+}
+
+// truncateRunes truncates text to at most maxLen runes
+func truncateRunes(text string, maxLen int) string {
+	runes := []rune(text)
+	if len(runes) <= maxLen {
+		return text
+	}
+	return string(runes[:maxLen])
+}
+
+// estimateTokens roughly estimates the token count of a text (about 4 characters per token),
+// since streaming model providers do not report usage to fill in the OpenAI usage fields
+func estimateTokens(text string) int {
+	return len([]rune(text)) / 4
+}
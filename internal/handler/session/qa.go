@@ -31,6 +31,7 @@ type qaRequestContext struct {
 	summaryModelID   string
 	webSearchEnabled bool
 	mentionedItems   types.MentionedItems
+	answerPreference types.FAQAnswerPreference
 }
 
 // parseQARequest parses and validates a QA request, returns the request context
@@ -106,6 +107,10 @@ func (h *Handler) parseQARequest(c *gin.Context, logPrefix string) (*qaRequestCo
 		summaryModelID:   secutils.SanitizeForLog(request.SummaryModelID),
 		webSearchEnabled: request.WebSearchEnabled,
 		mentionedItems:   convertMentionedItems(request.MentionedItems),
+		answerPreference: types.FAQAnswerPreference{
+			Locale:  secutils.SanitizeForLog(request.Locale),
+			Channel: secutils.SanitizeForLog(request.Channel),
+		},
 	}
 
 	return reqCtx, &request, nil
@@ -124,6 +129,12 @@ func (h *Handler) setupSSEStream(reqCtx *qaRequestContext, generateTitle bool) *
 	// Set SSE headers
 	setSSEHeaders(reqCtx.c)
 
+	return h.setupEventStream(reqCtx, generateTitle)
+}
+
+// setupEventStream creates the EventBus, stream handler and title generation for a QA request.
+// It is shared by the SSE endpoints and the synchronous endpoints.
+func (h *Handler) setupEventStream(reqCtx *qaRequestContext, generateTitle bool) *sseStreamContext {
 	// Write initial agent_query event
 	h.writeAgentQueryEvent(reqCtx.ctx, reqCtx.sessionID, reqCtx.assistantMessage.ID)
 
@@ -212,6 +223,12 @@ func (h *Handler) SearchKnowledge(c *gin.Context) {
 		return
 	}
 
+	if _, err := request.MetadataFilters.Normalize(); err != nil {
+		logger.Error(ctx, "Invalid metadata filters", err)
+		c.Error(errors.NewBadRequestError(err.Error()))
+		return
+	}
+
 	logger.Infof(
 		ctx,
 		"Knowledge search request, knowledge base IDs: %v, knowledge IDs: %v, query: %s",
@@ -221,7 +238,9 @@ func (h *Handler) SearchKnowledge(c *gin.Context) {
 	)
 
 	// Directly call knowledge retrieval service without LLM summarization
-	searchResults, err := h.sessionService.SearchKnowledge(ctx, knowledgeBaseIDs, request.KnowledgeIDs, request.Query)
+	searchResults, err := h.sessionService.SearchKnowledge(
+		ctx, knowledgeBaseIDs, request.KnowledgeIDs, request.Query, request.MetadataFilters,
+	)
 	if err != nil {
 		logger.ErrorWithFields(ctx, err, nil)
 		c.Error(errors.NewInternalServerError(err.Error()))
@@ -302,25 +321,44 @@ func (h *Handler) AgentQA(c *gin.Context) {
 
 // executeNormalModeQA executes the normal (KnowledgeQA) mode
 func (h *Handler) executeNormalModeQA(reqCtx *qaRequestContext, generateTitle bool) {
+	if err := h.prepareNormalModeQA(reqCtx); err != nil {
+		reqCtx.c.Error(errors.NewInternalServerError(err.Error()))
+		return
+	}
+
+	// Setup SSE stream
+	streamCtx := h.setupSSEStream(reqCtx, generateTitle)
+
+	h.startNormalModeQA(reqCtx, streamCtx)
+
+	// Handle SSE events (blocking)
+	shouldWaitForTitle := generateTitle && reqCtx.session.Title == ""
+	h.handleAgentEventsForSSE(reqCtx.ctx, reqCtx.c, reqCtx.sessionID, reqCtx.assistantMessage.ID,
+		reqCtx.requestID, streamCtx.eventBus, shouldWaitForTitle)
+}
+
+// prepareNormalModeQA creates the user and assistant messages for a normal mode request
+func (h *Handler) prepareNormalModeQA(reqCtx *qaRequestContext) error {
 	ctx := reqCtx.ctx
-	sessionID := reqCtx.sessionID
 
 	// Create user message
-	if err := h.createUserMessage(ctx, sessionID, reqCtx.query, reqCtx.requestID, reqCtx.mentionedItems); err != nil {
-		reqCtx.c.Error(errors.NewInternalServerError(err.Error()))
-		return
+	if err := h.createUserMessage(ctx, reqCtx.sessionID, reqCtx.query, reqCtx.requestID, reqCtx.mentionedItems); err != nil {
+		return err
 	}
 
 	// Create assistant message
 	if _, err := h.createAssistantMessage(ctx, reqCtx.assistantMessage); err != nil {
-		reqCtx.c.Error(errors.NewInternalServerError(err.Error()))
-		return
+		return err
 	}
 
 	logger.Infof(ctx, "Using knowledge bases: %v", reqCtx.knowledgeBaseIDs)
+	return nil
+}
 
-	// Setup SSE stream
-	streamCtx := h.setupSSEStream(reqCtx, generateTitle)
+// startNormalModeQA registers the completion handler and runs KnowledgeQA asynchronously.
+// The returned channel is closed once the service call has returned.
+func (h *Handler) startNormalModeQA(reqCtx *qaRequestContext, streamCtx *sseStreamContext) <-chan struct{} {
+	sessionID := reqCtx.sessionID
 
 	// Setup completion handler for normal mode
 	// Note: Thinking content is now embedded in answer stream with <think> tags
@@ -333,6 +371,9 @@ func (h *Handler) executeNormalModeQA(reqCtx *qaRequestContext, generateTitle bo
 			return nil
 		}
 		streamCtx.assistantMessage.Content += data.Content
+		if data.IsFallback {
+			streamCtx.assistantMessage.IsFallback = true
+		}
 		if data.Done {
 			// Prevent duplicate completion handling
 			if completionHandled {
@@ -354,14 +395,19 @@ func (h *Handler) executeNormalModeQA(reqCtx *qaRequestContext, generateTitle bo
 		return nil
 	})
 
+	done := make(chan struct{})
+
 	// Execute KnowledgeQA asynchronously
 	go func() {
+		defer close(done)
 		defer func() {
 			if r := recover(); r != nil {
 				buf := make([]byte, 10240)
 				runtime.Stack(buf, true)
 				logger.ErrorWithFields(streamCtx.asyncCtx,
 					errors.NewInternalServerError(fmt.Sprintf("Knowledge QA service panicked: %v\n%s", r, string(buf))), nil)
+				// Report the failure so that waiting clients do not block until the timeout
+				emitQAPanic(streamCtx, sessionID, "knowledge_qa_execution", r)
 			}
 		}()
 
@@ -374,6 +420,7 @@ func (h *Handler) executeNormalModeQA(reqCtx *qaRequestContext, generateTitle bo
 			reqCtx.assistantMessage.ID,
 			reqCtx.summaryModelID,
 			reqCtx.webSearchEnabled,
+			reqCtx.answerPreference,
 			streamCtx.eventBus,
 			reqCtx.customAgent,
 		)
@@ -391,14 +438,28 @@ func (h *Handler) executeNormalModeQA(reqCtx *qaRequestContext, generateTitle bo
 		}
 	}()
 
-	// Handle SSE events (blocking)
-	shouldWaitForTitle := generateTitle && reqCtx.session.Title == ""
-	h.handleAgentEventsForSSE(ctx, reqCtx.c, sessionID, reqCtx.assistantMessage.ID,
-		reqCtx.requestID, streamCtx.eventBus, shouldWaitForTitle)
+	return done
 }
 
 // executeAgentModeQA executes the agent mode
 func (h *Handler) executeAgentModeQA(reqCtx *qaRequestContext) {
+	if err := h.prepareAgentModeQA(reqCtx); err != nil {
+		reqCtx.c.Error(err)
+		return
+	}
+
+	// Setup SSE stream (agent mode always generates title)
+	streamCtx := h.setupSSEStream(reqCtx, true)
+
+	h.startAgentModeQA(reqCtx, streamCtx)
+
+	// Handle SSE events (blocking)
+	h.handleAgentEventsForSSE(reqCtx.ctx, reqCtx.c, reqCtx.sessionID, reqCtx.assistantMessage.ID,
+		reqCtx.requestID, streamCtx.eventBus, reqCtx.session.Title == "")
+}
+
+// prepareAgentModeQA emits the agent query event and creates the user and assistant messages
+func (h *Handler) prepareAgentModeQA(reqCtx *qaRequestContext) error {
 	ctx := reqCtx.ctx
 	sessionID := reqCtx.sessionID
 
@@ -414,30 +475,34 @@ func (h *Handler) executeAgentModeQA(reqCtx *qaRequestContext) {
 		},
 	}); err != nil {
 		logger.Errorf(ctx, "Failed to emit agent query event: %v", err)
-		return
+		return errors.NewInternalServerError(err.Error())
 	}
 
 	// Create user message
 	if err := h.createUserMessage(ctx, sessionID, reqCtx.query, reqCtx.requestID, reqCtx.mentionedItems); err != nil {
-		reqCtx.c.Error(errors.NewInternalServerError(err.Error()))
-		return
+		return errors.NewInternalServerError(err.Error())
 	}
 
 	// Create assistant message
 	assistantMessagePtr, err := h.createAssistantMessage(ctx, reqCtx.assistantMessage)
 	if err != nil {
-		reqCtx.c.Error(errors.NewInternalServerError(err.Error()))
-		return
+		return errors.NewInternalServerError(err.Error())
 	}
 	reqCtx.assistantMessage = assistantMessagePtr
 
 	logger.Infof(ctx, "Calling agent QA service, session ID: %s", sessionID)
+	return nil
+}
 
-	// Setup SSE stream (agent mode always generates title)
-	streamCtx := h.setupSSEStream(reqCtx, true)
+// startAgentModeQA runs AgentQA asynchronously.
+// The returned channel is closed once the service call has returned and the message is completed.
+func (h *Handler) startAgentModeQA(reqCtx *qaRequestContext, streamCtx *sseStreamContext) <-chan struct{} {
+	sessionID := reqCtx.sessionID
+	done := make(chan struct{})
 
 	// Execute AgentQA asynchronously
 	go func() {
+		defer close(done)
 		defer func() {
 			if r := recover(); r != nil {
 				buf := make([]byte, 1024)
@@ -445,6 +510,7 @@ func (h *Handler) executeAgentModeQA(reqCtx *qaRequestContext) {
 				logger.ErrorWithFields(streamCtx.asyncCtx,
 					errors.NewInternalServerError(fmt.Sprintf("Agent QA service panicked: %v\n%s", r, string(buf))),
 					map[string]interface{}{"session_id": sessionID})
+				emitQAPanic(streamCtx, sessionID, "agent_execution", r)
 			}
 			h.completeAssistantMessage(streamCtx.asyncCtx, streamCtx.assistantMessage)
 			logger.Infof(streamCtx.asyncCtx, "Agent QA service completed for session: %s", sessionID)
@@ -475,9 +541,7 @@ func (h *Handler) executeAgentModeQA(reqCtx *qaRequestContext) {
 		}
 	}()
 
-	// Handle SSE events (blocking)
-	h.handleAgentEventsForSSE(ctx, reqCtx.c, sessionID, reqCtx.assistantMessage.ID,
-		reqCtx.requestID, streamCtx.eventBus, reqCtx.session.Title == "")
+	return done
 }
 
 // completeAssistantMessage marks an assistant message as complete and updates it
@@ -486,3 +550,16 @@ func (h *Handler) completeAssistantMessage(ctx context.Context, assistantMessage
 	assistantMessage.IsCompleted = true
 	_ = h.messageService.UpdateMessage(ctx, assistantMessage)
 }
+
+// emitQAPanic publishes an error event for a QA service call that panicked
+func emitQAPanic(streamCtx *sseStreamContext, sessionID string, stage string, r any) {
+	streamCtx.eventBus.Emit(streamCtx.asyncCtx, event.Event{
+		Type:      event.EventError,
+		SessionID: sessionID,
+		Data: event.ErrorData{
+			Error:     fmt.Sprintf("QA service panicked: %v", r),
+			Stage:     stage,
+			SessionID: sessionID,
+		},
+	})
+}
@@ -0,0 +1,359 @@
+package session
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/errors"
+	"github.com/Tencent/WeKnora/internal/event"
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/gin-gonic/gin"
+)
+
+// defaultSyncQATimeout is used when conversation.sync_timeout is not configured
+const defaultSyncQATimeout = 120 * time.Second
+
+// syncQACollector accumulates the events of a single QA run so that it can be
+// returned to the caller as one JSON document instead of an SSE stream
+type syncQACollector struct {
+	mu         sync.Mutex
+	answer     strings.Builder
+	final      string
+	references []*types.SearchResult
+	agentSteps []types.AgentStep
+	totalSteps int
+	errMessage string
+
+	completed    chan struct{}
+	failed       chan struct{}
+	completeOnce sync.Once
+	failOnce     sync.Once
+}
+
+// newSyncQACollector creates a collector and subscribes it to the request EventBus
+func newSyncQACollector(eventBus *event.EventBus) *syncQACollector {
+	collector := &syncQACollector{
+		completed: make(chan struct{}),
+		failed:    make(chan struct{}),
+	}
+	eventBus.On(event.EventAgentFinalAnswer, collector.handleFinalAnswer)
+	eventBus.On(event.EventAgentReferences, collector.handleReferences)
+	eventBus.On(event.EventAgentComplete, collector.handleComplete)
+	eventBus.On(event.EventError, collector.handleError)
+	return collector
+}
+
+// handleFinalAnswer accumulates answer chunks
+func (s *syncQACollector) handleFinalAnswer(ctx context.Context, evt event.Event) error {
+	data, ok := evt.Data.(event.AgentFinalAnswerData)
+	if !ok {
+		return nil
+	}
+	s.mu.Lock()
+	s.answer.WriteString(data.Content)
+	s.mu.Unlock()
+	return nil
+}
+
+// handleReferences collects knowledge references
+func (s *syncQACollector) handleReferences(ctx context.Context, evt event.Event) error {
+	data, ok := evt.Data.(event.AgentReferencesData)
+	if !ok {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch refs := data.References.(type) {
+	case []*types.SearchResult:
+		s.references = append(s.references, refs...)
+	case types.References:
+		s.references = append(s.references, refs...)
+	}
+	return nil
+}
+
+// handleComplete records the final answer, references and agent steps
+func (s *syncQACollector) handleComplete(ctx context.Context, evt event.Event) error {
+	data, ok := evt.Data.(event.AgentCompleteData)
+	if !ok {
+		return nil
+	}
+	s.mu.Lock()
+	s.final = data.FinalAnswer
+	s.totalSteps = data.TotalSteps
+	if len(data.KnowledgeRefs) > 0 {
+		refs := make([]*types.SearchResult, 0, len(data.KnowledgeRefs))
+		for _, ref := range data.KnowledgeRefs {
+			if sr, ok := ref.(*types.SearchResult); ok {
+				refs = append(refs, sr)
+			}
+		}
+		s.references = refs
+	}
+	if steps, ok := data.AgentSteps.([]types.AgentStep); ok {
+		s.agentSteps = steps
+	}
+	s.mu.Unlock()
+
+	s.completeOnce.Do(func() { close(s.completed) })
+	return nil
+}
+
+// handleError records the first error reported by the pipeline
+func (s *syncQACollector) handleError(ctx context.Context, evt event.Event) error {
+	data, ok := evt.Data.(event.ErrorData)
+	if !ok {
+		return nil
+	}
+	s.mu.Lock()
+	if s.errMessage == "" {
+		s.errMessage = data.Error
+	}
+	s.mu.Unlock()
+
+	s.failOnce.Do(func() { close(s.failed) })
+	return nil
+}
+
+// result builds the response document from the collected events
+func (s *syncQACollector) result(reqCtx *qaRequestContext, startTime time.Time) *QAResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	answer := s.final
+	if answer == "" {
+		answer = s.answer.String()
+	}
+	references := s.references
+	if references == nil {
+		references = make([]*types.SearchResult, 0)
+	}
+
+	return &QAResponse{
+		SessionID:           reqCtx.sessionID,
+		AssistantMessageID:  reqCtx.assistantMessage.ID,
+		RequestID:           reqCtx.requestID,
+		Answer:              answer,
+		KnowledgeReferences: references,
+		AgentSteps:          s.agentSteps,
+		Usage: QAUsage{
+			TotalSteps: s.totalSteps,
+			DurationMs: time.Since(startTime).Milliseconds(),
+		},
+	}
+}
+
+// errorMessage returns the first error reported by the pipeline
+func (s *syncQACollector) errorMessage() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.errMessage
+}
+
+// KnowledgeQASync godoc
+// @Summary      知识问答（同步）
+// @Description  基于知识库的问答（使用LLM总结），阻塞等待并以JSON返回完整答案
+// @Tags         问答
+// @Accept       json
+// @Produce      json
+// @Param        session_id  path      string                   true  "会话ID"
+// @Param        request     body      CreateKnowledgeQARequest true  "问答请求"
+// @Success      200         {object}  QAResponse               "问答结果"
+// @Failure      400         {object}  errors.AppError          "请求参数错误"
+// @Failure      504         {object}  errors.AppError          "等待超时"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /knowledge-chat/{session_id}/sync [post]
+func (h *Handler) KnowledgeQASync(c *gin.Context) {
+	// Parse and validate request
+	reqCtx, request, err := h.parseQARequest(c, "KnowledgeQASync")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.prepareNormalModeQA(reqCtx); err != nil {
+		c.Error(errors.NewInternalServerError(err.Error()))
+		return
+	}
+
+	h.executeSyncQA(reqCtx, request, !request.DisableTitle, h.startNormalModeQA)
+}
+
+// AgentQASync godoc
+// @Summary      Agent问答（同步）
+// @Description  基于Agent的智能问答，阻塞等待并以JSON返回答案、引用和执行步骤
+// @Tags         问答
+// @Accept       json
+// @Produce      json
+// @Param        session_id  path      string                   true  "会话ID"
+// @Param        request     body      CreateKnowledgeQARequest true  "问答请求"
+// @Success      200         {object}  QAResponse               "问答结果"
+// @Failure      400         {object}  errors.AppError          "请求参数错误"
+// @Failure      504         {object}  errors.AppError          "等待超时"
+// @Security     Bearer
+// @Security     ApiKeyAuth
+// @Router       /agent-chat/{session_id}/sync [post]
+func (h *Handler) AgentQASync(c *gin.Context) {
+	// Parse and validate request
+	reqCtx, request, err := h.parseQARequest(c, "AgentQASync")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Determine if agent mode should be enabled
+	// Priority: customAgent.IsAgentMode() > request.AgentEnabled
+	agentModeEnabled := request.AgentEnabled
+	if reqCtx.customAgent != nil {
+		agentModeEnabled = reqCtx.customAgent.IsAgentMode()
+	}
+
+	if !agentModeEnabled {
+		logger.Infof(reqCtx.ctx, "Agent mode disabled, delegating to normal mode for session: %s", reqCtx.sessionID)
+		if err := h.prepareNormalModeQA(reqCtx); err != nil {
+			c.Error(errors.NewInternalServerError(err.Error()))
+			return
+		}
+		h.executeSyncQA(reqCtx, request, false, h.startNormalModeQA)
+		return
+	}
+
+	if err := h.prepareAgentModeQA(reqCtx); err != nil {
+		c.Error(err)
+		return
+	}
+	h.executeSyncQA(reqCtx, request, !request.DisableTitle, h.startAgentModeQA)
+}
+
+// executeSyncQA runs the QA pipeline started by start and blocks until the answer is complete,
+// the pipeline fails, the server-side timeout elapses or the client disconnects
+func (h *Handler) executeSyncQA(
+	reqCtx *qaRequestContext,
+	request *CreateKnowledgeQARequest,
+	generateTitle bool,
+	start func(*qaRequestContext, *sseStreamContext) <-chan struct{},
+) {
+	ctx := reqCtx.ctx
+	startTime := time.Now()
+
+	streamCtx := h.setupEventStream(reqCtx, generateTitle)
+	collector := newSyncQACollector(streamCtx.eventBus)
+	serviceDone := start(reqCtx, streamCtx)
+
+	timeout := h.syncQATimeout(request.TimeoutSeconds)
+	switch h.waitSyncQA(reqCtx, streamCtx, collector, serviceDone, timeout, startTime) {
+	case syncQATimedOut:
+		reqCtx.c.Error(errors.NewTimeoutError("Timed out waiting for the answer").WithDetails(gin.H{
+			"session_id":           reqCtx.sessionID,
+			"assistant_message_id": reqCtx.assistantMessage.ID,
+			"timeout_seconds":      int(timeout.Seconds()),
+		}))
+		return
+	case syncQADisconnected:
+		return
+	case syncQAFailed:
+		reqCtx.c.Error(errors.NewInternalServerError(collector.errorMessage()).WithDetails(gin.H{
+			"session_id":           reqCtx.sessionID,
+			"assistant_message_id": reqCtx.assistantMessage.ID,
+		}))
+		return
+	}
+
+	response := collector.result(reqCtx, startTime)
+	logger.Infof(ctx, "Synchronous QA completed, session ID: %s, answer length: %d, references: %d, duration: %dms",
+		reqCtx.sessionID, len(response.Answer), len(response.KnowledgeReferences), response.Usage.DurationMs)
+	reqCtx.c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    response,
+	})
+}
+
+// syncQAOutcome is the way a synchronous QA run ended
+type syncQAOutcome int
+
+const (
+	syncQACompleted syncQAOutcome = iota
+	syncQAFailed
+	syncQATimedOut
+	syncQADisconnected
+)
+
+// waitSyncQA blocks until the QA run completes, fails, times out or the client disconnects.
+// On timeout or disconnect the run is aborted and the partial answer is persisted.
+func (h *Handler) waitSyncQA(
+	reqCtx *qaRequestContext,
+	streamCtx *sseStreamContext,
+	collector *syncQACollector,
+	serviceDone <-chan struct{},
+	timeout time.Duration,
+	startTime time.Time,
+) syncQAOutcome {
+	ctx := reqCtx.ctx
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	// Normal mode returns from the service before the answer has finished streaming,
+	// so wait for both the service call and a completion (or error) event
+	completedCh, failedCh := collector.completed, collector.failed
+	completed, failed, returned := false, false, false
+	for !(returned && (completed || failed)) {
+		select {
+		case <-completedCh:
+			completed = true
+			completedCh = nil
+		case <-failedCh:
+			failed = true
+			failedCh = nil
+		case <-serviceDone:
+			returned = true
+			serviceDone = nil
+		case <-timer.C:
+			logger.Warnf(ctx, "Synchronous QA timed out after %s, session ID: %s", timeout, reqCtx.sessionID)
+			h.abortSyncQA(streamCtx, collector, reqCtx, startTime)
+			return syncQATimedOut
+		case <-reqCtx.c.Request.Context().Done():
+			logger.Infof(ctx, "Client disconnected, aborting synchronous QA for session: %s", reqCtx.sessionID)
+			h.abortSyncQA(streamCtx, collector, reqCtx, startTime)
+			return syncQADisconnected
+		}
+	}
+
+	if !completed {
+		logger.Errorf(ctx, "Synchronous QA failed, session ID: %s, error: %s", reqCtx.sessionID, collector.errorMessage())
+		return syncQAFailed
+	}
+	return syncQACompleted
+}
+
+// abortSyncQA cancels the running pipeline and persists the partial answer
+func (h *Handler) abortSyncQA(
+	streamCtx *sseStreamContext,
+	collector *syncQACollector,
+	reqCtx *qaRequestContext,
+	startTime time.Time,
+) {
+	streamCtx.cancel()
+	partial := collector.result(reqCtx, startTime)
+	streamCtx.assistantMessage.Content = partial.Answer
+	h.completeAssistantMessage(reqCtx.ctx, streamCtx.assistantMessage)
+}
+
+// syncQATimeout resolves the effective timeout for a synchronous request.
+// A request may shorten the configured timeout but never extend it.
+func (h *Handler) syncQATimeout(requestSeconds int) time.Duration {
+	timeout := defaultSyncQATimeout
+	if h.config != nil && h.config.Conversation != nil && h.config.Conversation.SyncTimeout > 0 {
+		timeout = h.config.Conversation.SyncTimeout
+	}
+	if requestSeconds > 0 {
+		if requested := time.Duration(requestSeconds) * time.Second; requested < timeout {
+			timeout = requested
+		}
+	}
+	return timeout
+}
@@ -1,6 +1,10 @@
 package session
 
 import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
 	"github.com/Tencent/WeKnora/internal/types"
 )
 
@@ -16,7 +20,7 @@ type CreateSessionRequest struct {
 
 // GenerateTitleRequest defines the request structure for generating a session title
 type GenerateTitleRequest struct {
-	Messages []types.Message `json:"messages" binding:"required"` // Messages to use as context for title generation
+	Messages []types.Message `json:"messages" binding:"required,min=1"` // Messages to use as context for title generation
 }
 
 // MentionedItemRequest represents a mentioned item in the request
@@ -38,6 +42,27 @@ type CreateKnowledgeQARequest struct {
 	SummaryModelID   string                 `json:"summary_model_id"`                      // Optional summary model ID for this request (overrides session default)
 	MentionedItems   []MentionedItemRequest `json:"mentioned_items"`                       // @mentioned knowledge bases and files
 	DisableTitle     bool                   `json:"disable_title"`                         // Whether to disable auto title generation
+	TimeoutSeconds   int                    `json:"timeout_seconds"`                       // Optional wait limit for synchronous endpoints (capped by server config)
+	Locale           string                 `json:"locale"`                                // Optional locale selecting FAQ answer variants, e.g. ko-KR
+	Channel          string                 `json:"channel"`                               // Optional channel selecting FAQ answer variants, e.g. web or ivr
+}
+
+// QAResponse is the response of the synchronous (non-streaming) QA endpoints
+type QAResponse struct {
+	SessionID           string            `json:"session_id"`            // Session ID
+	AssistantMessageID  string            `json:"assistant_message_id"`  // ID of the persisted assistant message
+	RequestID           string            `json:"request_id"`            // Request ID
+	Answer              string            `json:"answer"`                // Complete answer text
+	KnowledgeReferences types.References  `json:"knowledge_references"`  // Knowledge references used for the answer
+	AgentSteps          []types.AgentStep `json:"agent_steps,omitempty"` // Agent execution steps (agent mode only)
+	Usage               QAUsage           `json:"usage"`                 // Usage statistics
+}
+
+// QAUsage describes the resources consumed by a synchronous QA request.
+// Token counts are not included since streaming model providers do not report them.
+type QAUsage struct {
+	TotalSteps int   `json:"total_steps"` // Number of agent steps (agent mode only)
+	DurationMs int64 `json:"duration_ms"` // Wall-clock time spent waiting for the answer
 }
 
 // SearchKnowledgeRequest defines the request structure for searching knowledge without LLM summarization
@@ -46,9 +71,135 @@ type SearchKnowledgeRequest struct {
 	KnowledgeBaseID  string   `json:"knowledge_base_id"`                     // Single knowledge base ID (for backward compatibility)
 	KnowledgeBaseIDs []string `json:"knowledge_base_ids"`                    // IDs of knowledge bases to search (multi-KB support)
 	KnowledgeIDs     []string `json:"knowledge_ids"`                         // IDs of specific knowledge (files) to search
+	// Filters on the custom metadata of knowledge, combined with AND
+	MetadataFilters types.MetadataFilters `json:"metadata_filters"`
 }
 
 // StopSessionRequest represents the stop session request
 type StopSessionRequest struct {
 	MessageID string `json:"message_id" binding:"required"`
 }
+
+// OpenAIMessageContent is the content of an OpenAI chat message.
+// It accepts both a plain string and an array of content parts, only text parts are kept.
+type OpenAIMessageContent string
+
+// UnmarshalJSON implements json.Unmarshaler
+func (m *OpenAIMessageContent) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*m = OpenAIMessageContent(text)
+		return nil
+	}
+	var parts []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(data, &parts); err != nil {
+		return fmt.Errorf("content must be a string or an array of content parts: %w", err)
+	}
+	texts := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part.Type == "text" && part.Text != "" {
+			texts = append(texts, part.Text)
+		}
+	}
+	*m = OpenAIMessageContent(strings.Join(texts, "\n"))
+	return nil
+}
+
+// OpenAIChatMessage is a message in an OpenAI chat completion request
+type OpenAIChatMessage struct {
+	Role    string               `json:"role"`    // "system", "user" or "assistant"
+	Content OpenAIMessageContent `json:"content"` // Message text
+}
+
+// OpenAIStreamOptions are the stream options of an OpenAI chat completion request
+type OpenAIStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"` // Whether to send a usage chunk before [DONE]
+}
+
+// OpenAIChatCompletionRequest is an OpenAI compatible chat completion request.
+// Sampling parameters are accepted for compatibility but the agent or knowledge base configuration applies.
+type OpenAIChatCompletionRequest struct {
+	Model         string               `json:"model"    binding:"required"`       // Custom agent ID, or "kb:<knowledge_base_id>"
+	Messages      []OpenAIChatMessage  `json:"messages" binding:"required,min=1"` // Conversation messages, the last one must be from the user
+	Stream        bool                 `json:"stream"`                            // Whether to stream the answer as chat.completion.chunk events
+	StreamOptions *OpenAIStreamOptions `json:"stream_options,omitempty"`          // Stream options
+	Temperature   *float64             `json:"temperature,omitempty"`             // Ignored
+	MaxTokens     *int                 `json:"max_tokens,omitempty"`              // Ignored
+	User          string               `json:"user,omitempty"`                    // End-user identifier (logged only)
+
+	// Extension fields
+	SessionID        string `json:"session_id,omitempty"`         // Existing session to continue, a new one is created when empty
+	WebSearchEnabled bool   `json:"web_search_enabled,omitempty"` // Whether to enable web search (normal mode)
+}
+
+// OpenAIUsage is the token usage of an OpenAI chat completion
+type OpenAIUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// OpenAIResponseMessage is the assistant message of a non-streaming chat completion
+type OpenAIResponseMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// OpenAIChoice is a choice of a non-streaming chat completion
+type OpenAIChoice struct {
+	Index        int                   `json:"index"`
+	Message      OpenAIResponseMessage `json:"message"`
+	FinishReason string                `json:"finish_reason"`
+}
+
+// OpenAIChatCompletionResponse is an OpenAI compatible chat completion response.
+// SessionID and References are WeKnora extension fields.
+type OpenAIChatCompletionResponse struct {
+	ID         string           `json:"id"`
+	Object     string           `json:"object"`
+	Created    int64            `json:"created"`
+	Model      string           `json:"model"`
+	Choices    []OpenAIChoice   `json:"choices"`
+	Usage      OpenAIUsage      `json:"usage"`
+	SessionID  string           `json:"session_id"`
+	References types.References `json:"references"`
+}
+
+// OpenAIDelta is the incremental message content of a streaming chunk
+type OpenAIDelta struct {
+	Role             string `json:"role,omitempty"`
+	Content          string `json:"content,omitempty"`
+	ReasoningContent string `json:"reasoning_content,omitempty"`
+}
+
+// OpenAIChunkChoice is a choice of a streaming chat completion chunk
+type OpenAIChunkChoice struct {
+	Index        int         `json:"index"`
+	Delta        OpenAIDelta `json:"delta"`
+	FinishReason *string     `json:"finish_reason"`
+}
+
+// OpenAIChatCompletionChunk is an OpenAI compatible streaming chunk.
+// SessionID and References are WeKnora extension fields, References is only set on the final chunk.
+type OpenAIChatCompletionChunk struct {
+	ID         string              `json:"id"`
+	Object     string              `json:"object"`
+	Created    int64               `json:"created"`
+	Model      string              `json:"model"`
+	Choices    []OpenAIChunkChoice `json:"choices"`
+	Usage      *OpenAIUsage        `json:"usage,omitempty"`
+	SessionID  string              `json:"session_id,omitempty"`
+	References types.References    `json:"references,omitempty"`
+}
+
+// OpenAIModel is an entry of the OpenAI compatible model list
+type OpenAIModel struct {
+	ID      string `json:"id"`
+	Object  string `json:"object"`
+	Created int64  `json:"created"`
+	OwnedBy string `json:"owned_by"`
+	Name    string `json:"name,omitempty"` // Display name of the agent or knowledge base (extension)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/Tencent/WeKnora/internal/config"
 	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/Tencent/WeKnora/internal/models/embedding"
 	"github.com/Tencent/WeKnora/internal/types"
 	"github.com/gin-gonic/gin"
 	"github.com/minio/minio-go/v7"
@@ -17,15 +18,19 @@ import (
 
 // SystemHandler handles system-related requests
 type SystemHandler struct {
-	cfg         *config.Config
-	neo4jDriver neo4j.Driver
+	cfg            *config.Config
+	neo4jDriver    neo4j.Driver
+	embeddingCache embedding.EmbeddingCache
 }
 
 // NewSystemHandler creates a new system handler
-func NewSystemHandler(cfg *config.Config, neo4jDriver neo4j.Driver) *SystemHandler {
+func NewSystemHandler(cfg *config.Config, neo4jDriver neo4j.Driver,
+	embeddingCache embedding.EmbeddingCache,
+) *SystemHandler {
 	return &SystemHandler{
-		cfg:         cfg,
-		neo4jDriver: neo4jDriver,
+		cfg:            cfg,
+		neo4jDriver:    neo4jDriver,
+		embeddingCache: embeddingCache,
 	}
 }
 
@@ -39,6 +44,8 @@ type GetSystemInfoResponse struct {
 	VectorStoreEngine   string `json:"vector_store_engine,omitempty"`
 	GraphDatabaseEngine string `json:"graph_database_engine,omitempty"`
 	MinioEnabled        bool   `json:"minio_enabled,omitempty"`
+	// EmbeddingCache holds the embedding cache hit and miss counters of this instance
+	EmbeddingCache embedding.EmbeddingCacheStats `json:"embedding_cache"`
 }
 
 // 编译时注入的版本信息
@@ -82,6 +89,9 @@ func (h *SystemHandler) GetSystemInfo(c *gin.Context) {
 		GraphDatabaseEngine: graphDatabaseEngine,
 		MinioEnabled:        minioEnabled,
 	}
+	if h.embeddingCache != nil {
+		response.EmbeddingCache = h.embeddingCache.Stats()
+	}
 
 	logger.Info(ctx, "System info retrieved successfully")
 	c.JSON(200, gin.H{
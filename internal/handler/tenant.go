@@ -657,6 +657,10 @@ func (h *TenantHandler) updateTenantWebSearchConfigInternal(c *gin.Context) {
 		c.Error(errors.NewBadRequestError("max_results must be between 1 and 50"))
 		return
 	}
+	if err := types.ValidateWebSearchTrustWeights(cfg.TrustWeights); err != nil {
+		c.Error(errors.NewBadRequestError(err.Error()))
+		return
+	}
 
 	tenant := ctx.Value(types.TenantInfoContextKey).(*types.Tenant)
 	if tenant == nil {
@@ -38,6 +38,19 @@ func isNoAuthAPI(path string, method string) bool {
 	return false
 }
 
+// 允许以 Bearer Token 传递 API Key 的路由前缀（OpenAI 兼容接口）
+var bearerAPIKeyPrefixes = []string{"/v1/"}
+
+// 检查请求路径是否允许以 Bearer Token 传递 API Key
+func allowsBearerAPIKey(path string) bool {
+	for _, prefix := range bearerAPIKeyPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 // canAccessTenant checks if a user can access a target tenant
 func canAccessTenant(user *types.User, targetTenantID uint64, cfg *config.Config) bool {
 	// 1. 检查功能是否启用
@@ -147,6 +160,10 @@ func Auth(
 
 		// 尝试X-API-Key认证（兼容模式）
 		apiKey := c.GetHeader("X-API-Key")
+		// OpenAI SDK 以 Bearer 方式传递 API Key，仅在 OpenAI 兼容接口上 JWT 校验失败时按租户 API Key 处理
+		if apiKey == "" && strings.HasPrefix(authHeader, "Bearer ") && allowsBearerAPIKey(c.Request.URL.Path) {
+			apiKey = strings.TrimPrefix(authHeader, "Bearer ")
+		}
 		if apiKey != "" {
 			// Get tenant information
 			tenantID, err := tenantService.ExtractTenantIDFromAPIKey(apiKey)
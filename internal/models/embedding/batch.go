@@ -6,21 +6,25 @@ import (
 	"strconv"
 	"sync"
 
+	"github.com/Tencent/WeKnora/internal/logger"
 	"github.com/Tencent/WeKnora/internal/models/utils"
 	"github.com/panjf2000/ants/v2"
 )
 
 type batchEmbedder struct {
-	pool *ants.Pool
+	pool  *ants.Pool
+	cache EmbeddingCache
 }
 
-func NewBatchEmbedder(pool *ants.Pool) EmbedderPooler {
-	return &batchEmbedder{pool: pool}
+// NewBatchEmbedder creates a pooled batch embedder, cache may be nil to disable the embedding cache
+func NewBatchEmbedder(pool *ants.Pool, cache EmbeddingCache) EmbedderPooler {
+	return &batchEmbedder{pool: pool, cache: cache}
 }
 
 type textEmbedding struct {
-	text    string
-	results []float32
+	text     string
+	cacheKey string
+	results  []float32
 }
 
 func (e *batchEmbedder) BatchEmbedWithPool(ctx context.Context, model Embedder, texts []string) ([][]float32, error) {
@@ -40,6 +44,9 @@ func (e *batchEmbedder) BatchEmbedWithPool(ctx context.Context, model Embedder,
 		return &textEmbedding{text: text}
 	})
 
+	// Only texts missing from the cache are sent to the model
+	pending := e.loadCached(ctx, model, textEmbeddings)
+
 	// Function to process each document chunk
 	processChunk := func(texts []*textEmbedding) func() {
 		return func() {
@@ -72,7 +79,7 @@ func (e *batchEmbedder) BatchEmbedWithPool(ctx context.Context, model Embedder,
 	}
 
 	// Submit all tasks to the goroutine pool
-	for _, texts := range utils.ChunkSlice(textEmbeddings, batchSize) {
+	for _, texts := range utils.ChunkSlice(pending, batchSize) {
 		wg.Add(1)
 		err := e.pool.Submit(processChunk(texts))
 		if err != nil {
@@ -88,8 +95,60 @@ func (e *batchEmbedder) BatchEmbedWithPool(ctx context.Context, model Embedder,
 		return nil, firstErr
 	}
 
+	e.storeCached(ctx, pending)
+
 	results := utils.MapSlice(textEmbeddings, func(text *textEmbedding) []float32 {
 		return text.results
 	})
 	return results, nil
 }
+
+// loadCached fills the results of the texts found in the embedding cache and returns the others
+func (e *batchEmbedder) loadCached(ctx context.Context, model Embedder, texts []*textEmbedding) []*textEmbedding {
+	if e.cache == nil || len(texts) == 0 {
+		return texts
+	}
+	keys := make([]string, len(texts))
+	for i, text := range texts {
+		text.cacheKey = embeddingCacheKey(model, text.text)
+		keys[i] = text.cacheKey
+	}
+
+	cached, err := e.cache.Get(ctx, keys)
+	if err != nil {
+		// The cache is an optimization, embed everything when it is unavailable
+		logger.Warnf(ctx, "[EmbeddingCache] Failed to read cache: %v", err)
+		return texts
+	}
+
+	pending := make([]*textEmbedding, 0, len(texts))
+	for i, text := range texts {
+		if i < len(cached) && cached[i] != nil {
+			text.results = cached[i]
+			continue
+		}
+		pending = append(pending, text)
+	}
+	logger.Infof(ctx, "[EmbeddingCache] Model %s: %d cached, %d to embed",
+		model.GetModelName(), len(texts)-len(pending), len(pending))
+	return pending
+}
+
+// storeCached writes the newly embedded texts to the embedding cache
+func (e *batchEmbedder) storeCached(ctx context.Context, texts []*textEmbedding) {
+	if e.cache == nil || len(texts) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(texts))
+	vectors := make([][]float32, 0, len(texts))
+	for _, text := range texts {
+		if text.cacheKey == "" || len(text.results) == 0 {
+			continue
+		}
+		keys = append(keys, text.cacheKey)
+		vectors = append(vectors, text.results)
+	}
+	if err := e.cache.Set(ctx, keys, vectors); err != nil {
+		logger.Warnf(ctx, "[EmbeddingCache] Failed to write cache: %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package embedding
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/panjf2000/ants/v2"
+)
+
+// countingEmbedder embeds a text as [len(text)] and records the texts it was asked to embed
+type countingEmbedder struct {
+	EmbedderPooler
+	mu       sync.Mutex
+	embedded []string
+}
+
+func (e *countingEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	return []float32{float32(len(text))}, nil
+}
+
+func (e *countingEmbedder) BatchEmbed(ctx context.Context, texts []string) ([][]float32, error) {
+	e.mu.Lock()
+	e.embedded = append(e.embedded, texts...)
+	e.mu.Unlock()
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i], _ = e.Embed(ctx, text)
+	}
+	return vectors, nil
+}
+
+func (e *countingEmbedder) GetModelName() string { return "counting" }
+func (e *countingEmbedder) GetDimensions() int   { return 1 }
+func (e *countingEmbedder) GetModelID() string   { return "model-1" }
+
+// memoryCache is an in-memory EmbeddingCache
+type memoryCache struct {
+	mu      sync.Mutex
+	entries map[string][]float32
+}
+
+func (c *memoryCache) Get(ctx context.Context, keys []string) ([][]float32, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	vectors := make([][]float32, len(keys))
+	for i, key := range keys {
+		vectors[i] = c.entries[key]
+	}
+	return vectors, nil
+}
+
+func (c *memoryCache) Set(ctx context.Context, keys []string, vectors [][]float32) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, key := range keys {
+		c.entries[key] = vectors[i]
+	}
+	return nil
+}
+
+func (c *memoryCache) Stats() EmbeddingCacheStats { return EmbeddingCacheStats{Enabled: true} }
+
+func TestBatchEmbedWithPoolUsesCache(t *testing.T) {
+	pool, err := ants.NewPool(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Release()
+
+	embedder := NewBatchEmbedder(pool, &memoryCache{entries: map[string][]float32{}})
+	model := &countingEmbedder{}
+	ctx := context.Background()
+
+	if _, err := embedder.BatchEmbedWithPool(ctx, model, []string{"a", "bb", "ccc"}); err != nil {
+		t.Fatal(err)
+	}
+	model.embedded = nil
+
+	vectors, err := embedder.BatchEmbedWithPool(ctx, model, []string{"a", "dddd", "ccc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(model.embedded, []string{"dddd"}) {
+		t.Errorf("embedded %v, want only the changed text", model.embedded)
+	}
+	for i, want := range []float32{1, 4, 3} {
+		if len(vectors[i]) != 1 || vectors[i][0] != want {
+			t.Errorf("vector %d: got %v, want [%v]", i, vectors[i], want)
+		}
+	}
+}
+
+func TestVectorEncoding(t *testing.T) {
+	vector := []float32{0.5, -1.25, 3e-7}
+	decoded, err := decodeVector(encodeVector(vector))
+	if err != nil || !slices.Equal(decoded, vector) {
+		t.Errorf("got %v (%v), want %v", decoded, err, vector)
+	}
+	if _, err := decodeVector("abc"); err == nil {
+		t.Error("expected an error for a truncated vector")
+	}
+}
@@ -0,0 +1,167 @@
+package embedding
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/Tencent/WeKnora/internal/logger"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// embeddingCacheKeyPrefix namespaces the cache entries in Redis
+	embeddingCacheKeyPrefix = "embedding:cache:"
+	// defaultEmbeddingCacheTTL evicts entries not used for a week
+	defaultEmbeddingCacheTTL = 7 * 24 * time.Hour
+)
+
+// EmbeddingCache stores embedding vectors keyed by embedding model and content hash,
+// so unchanged content is not sent to the embedding provider again
+type EmbeddingCache interface {
+	// Get returns the cached vectors of the keys, nil for the keys that are not cached
+	Get(ctx context.Context, keys []string) ([][]float32, error)
+	// Set caches the vectors of the keys
+	Set(ctx context.Context, keys []string, vectors [][]float32) error
+	// Stats returns the hit and miss counters since startup
+	Stats() EmbeddingCacheStats
+}
+
+// EmbeddingCacheStats holds the embedding cache counters of this process
+type EmbeddingCacheStats struct {
+	Enabled bool    `json:"enabled"`
+	Hits    int64   `json:"hits"`
+	Misses  int64   `json:"misses"`
+	HitRate float64 `json:"hit_rate"`
+}
+
+// embeddingCacheKey builds the cache key of a text for a model.
+// The model name and dimensions are part of the key, so changing the model behind an ID invalidates its entries.
+func embeddingCacheKey(model Embedder, text string) string {
+	modelHash := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%d", model.GetModelID(), model.GetModelName(), model.GetDimensions())))
+	contentHash := sha256.Sum256([]byte(text))
+	return embeddingCacheKeyPrefix + hex.EncodeToString(modelHash[:8]) + ":" + hex.EncodeToString(contentHash[:])
+}
+
+// redisEmbeddingCache is an EmbeddingCache backed by Redis.
+// Entries expire after the TTL without use, each hit extends their lifetime.
+type redisEmbeddingCache struct {
+	client *redis.Client
+	ttl    time.Duration
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// NewEmbeddingCache creates the Redis embedding cache, or returns nil when it is disabled
+// with EMBEDDING_CACHE_ENABLED=false. EMBEDDING_CACHE_TTL sets the idle lifetime of entries.
+func NewEmbeddingCache(client *redis.Client) EmbeddingCache {
+	ctx := context.Background()
+	if enabled := strings.ToLower(strings.TrimSpace(os.Getenv("EMBEDDING_CACHE_ENABLED"))); enabled == "false" ||
+		enabled == "0" || client == nil {
+		logger.Infof(ctx, "[EmbeddingCache] Embedding cache disabled")
+		return nil
+	}
+
+	ttl := defaultEmbeddingCacheTTL
+	if ttlStr := os.Getenv("EMBEDDING_CACHE_TTL"); ttlStr != "" {
+		if parsed, err := time.ParseDuration(ttlStr); err == nil && parsed > 0 {
+			ttl = parsed
+		} else {
+			logger.Warnf(ctx, "[EmbeddingCache] Invalid EMBEDDING_CACHE_TTL %q, using default %s", ttlStr, ttl)
+		}
+	}
+	logger.Infof(ctx, "[EmbeddingCache] Embedding cache enabled, ttl: %s", ttl)
+	return &redisEmbeddingCache{client: client, ttl: ttl}
+}
+
+func (c *redisEmbeddingCache) Get(ctx context.Context, keys []string) ([][]float32, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	values, err := c.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		c.misses.Add(int64(len(keys)))
+		return nil, err
+	}
+
+	vectors := make([][]float32, len(keys))
+	pipe := c.client.Pipeline()
+	hits := 0
+	for i, value := range values {
+		raw, ok := value.(string)
+		if !ok {
+			continue
+		}
+		vector, err := decodeVector(raw)
+		if err != nil {
+			logger.Warnf(ctx, "[EmbeddingCache] Ignoring corrupted entry %s: %v", keys[i], err)
+			continue
+		}
+		vectors[i] = vector
+		pipe.Expire(ctx, keys[i], c.ttl)
+		hits++
+	}
+	if hits > 0 {
+		// Extending the lifetime is best effort, a failure only makes the entry expire earlier
+		if _, err := pipe.Exec(ctx); err != nil {
+			logger.Warnf(ctx, "[EmbeddingCache] Failed to refresh ttl: %v", err)
+		}
+	}
+
+	c.hits.Add(int64(hits))
+	c.misses.Add(int64(len(keys) - hits))
+	return vectors, nil
+}
+
+func (c *redisEmbeddingCache) Set(ctx context.Context, keys []string, vectors [][]float32) error {
+	pipe := c.client.Pipeline()
+	count := 0
+	for i, key := range keys {
+		if i >= len(vectors) || len(vectors[i]) == 0 {
+			continue
+		}
+		pipe.Set(ctx, key, encodeVector(vectors[i]), c.ttl)
+		count++
+	}
+	if count == 0 {
+		return nil
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (c *redisEmbeddingCache) Stats() EmbeddingCacheStats {
+	stats := EmbeddingCacheStats{Enabled: true, Hits: c.hits.Load(), Misses: c.misses.Load()}
+	if total := stats.Hits + stats.Misses; total > 0 {
+		stats.HitRate = float64(stats.Hits) / float64(total)
+	}
+	return stats
+}
+
+// encodeVector encodes a vector as little-endian float32 bytes
+func encodeVector(vector []float32) string {
+	buf := make([]byte, 4*len(vector))
+	for i, v := range vector {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(v))
+	}
+	return string(buf)
+}
+
+// decodeVector decodes a vector encoded by encodeVector
+func decodeVector(raw string) ([]float32, error) {
+	if len(raw) == 0 || len(raw)%4 != 0 {
+		return nil, fmt.Errorf("invalid vector length %d", len(raw))
+	}
+	vector := make([]float32, len(raw)/4)
+	for i := range vector {
+		vector[i] = math.Float32frombits(binary.LittleEndian.Uint32([]byte(raw[4*i : 4*i+4])))
+	}
+	return vector, nil
+}
@@ -0,0 +1,72 @@
+package embedding
+
+import (
+	"context"
+	"sync"
+)
+
+// PrecomputedEmbedder is an Embedder that returns vectors computed ahead of indexing,
+// texts without a vector are embedded by the wrapped model
+type PrecomputedEmbedder struct {
+	Embedder
+	mu      sync.RWMutex
+	vectors map[string][]float32
+}
+
+// NewPrecomputedEmbedder wraps a model, vectors are added with Add before indexing
+func NewPrecomputedEmbedder(model Embedder) *PrecomputedEmbedder {
+	return &PrecomputedEmbedder{Embedder: model, vectors: make(map[string][]float32)}
+}
+
+// Add records the vectors of texts, so that indexing the texts does not embed them again
+func (e *PrecomputedEmbedder) Add(texts []string, vectors [][]float32) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for i, text := range texts {
+		if i < len(vectors) && len(vectors[i]) > 0 {
+			e.vectors[text] = vectors[i]
+		}
+	}
+}
+
+// Embed returns the precomputed vector of a text, or embeds it with the wrapped model
+func (e *PrecomputedEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	e.mu.RLock()
+	vector, ok := e.vectors[text]
+	e.mu.RUnlock()
+	if ok {
+		return vector, nil
+	}
+	return e.Embedder.Embed(ctx, text)
+}
+
+// BatchEmbed returns the precomputed vectors of texts and embeds the others with the wrapped model
+func (e *PrecomputedEmbedder) BatchEmbed(ctx context.Context, texts []string) ([][]float32, error) {
+	results := make([][]float32, len(texts))
+	var missing []string
+	var missingIdx []int
+	e.mu.RLock()
+	for i, text := range texts {
+		if vector, ok := e.vectors[text]; ok {
+			results[i] = vector
+		} else {
+			missing = append(missing, text)
+			missingIdx = append(missingIdx, i)
+		}
+	}
+	e.mu.RUnlock()
+	if len(missing) == 0 {
+		return results, nil
+	}
+
+	vectors, err := e.Embedder.BatchEmbed(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	for i, idx := range missingIdx {
+		if i < len(vectors) {
+			results[idx] = vectors[i]
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,26 @@
+package embedding
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestPrecomputedEmbedder(t *testing.T) {
+	model := &countingEmbedder{}
+	embedder := NewPrecomputedEmbedder(model)
+	embedder.Add([]string{"a", "bb"}, [][]float32{{10}, {20}})
+
+	vectors, err := embedder.BatchEmbed(context.Background(), []string{"a", "ccc", "bb"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(model.embedded, []string{"ccc"}) {
+		t.Errorf("embedded %v, want only the text without a vector", model.embedded)
+	}
+	for i, want := range []float32{10, 3, 20} {
+		if len(vectors[i]) != 1 || vectors[i][0] != want {
+			t.Errorf("vector %d: got %v, want [%v]", i, vectors[i], want)
+		}
+	}
+}
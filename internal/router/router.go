@@ -46,6 +46,7 @@ type RouterParams struct {
 	MCPServiceHandler     *handler.MCPServiceHandler
 	WebSearchHandler      *handler.WebSearchHandler
 	FAQHandler            *handler.FAQHandler
+	AnalyticsHandler      *handler.AnalyticsHandler
 	TagHandler            *handler.TagHandler
 	CustomAgentHandler    *handler.CustomAgentHandler
 }
@@ -58,7 +59,7 @@ func NewRouter(params RouterParams) *gin.Engine {
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-API-Key", "X-Request-ID"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-API-Key", "X-Request-ID", "X-Session-ID"},
 		ExposeHeaders:    []string{"Content-Length", "Access-Control-Allow-Origin"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
@@ -101,6 +102,7 @@ func NewRouter(params RouterParams) *gin.Engine {
 		RegisterKnowledgeTagRoutes(v1, params.TagHandler)
 		RegisterKnowledgeRoutes(v1, params.KnowledgeHandler)
 		RegisterFAQRoutes(v1, params.FAQHandler)
+		RegisterAnalyticsRoutes(v1, params.AnalyticsHandler)
 		RegisterChunkRoutes(v1, params.ChunkHandler)
 		RegisterSessionRoutes(v1, params.SessionHandler)
 		RegisterChatRoutes(v1, params.SessionHandler)
@@ -114,6 +116,9 @@ func NewRouter(params RouterParams) *gin.Engine {
 		RegisterCustomAgentRoutes(v1, params.CustomAgentHandler)
 	}
 
+	// OpenAI compatible routes, mounted at /v1 so that OpenAI SDKs can use the server as base URL
+	RegisterOpenAICompatibleRoutes(r.Group("/v1"), params.SessionHandler)
+
 	return r
 }
 
@@ -134,6 +139,14 @@ func RegisterChunkRoutes(r *gin.RouterGroup, handler *handler.ChunkHandler) {
 		chunks.PUT("/:knowledge_id/:id", handler.UpdateChunk)
 		// Delete single generated question (by question ID)
 		chunks.DELETE("/by-id/:id/questions", handler.DeleteGeneratedQuestion)
+		// Split chunk at an offset
+		chunks.POST("/:knowledge_id/:id/split", handler.SplitChunk)
+		// Merge chunk with an adjacent chunk
+		chunks.POST("/:knowledge_id/:id/merge", handler.MergeChunks)
+		// Get chunk edit history
+		chunks.GET("/:knowledge_id/:id/history", handler.ListChunkEdits)
+		// Discard manual chunk edits of knowledge
+		chunks.DELETE("/:knowledge_id/edits", handler.DeleteChunkEdits)
 	}
 }
 
@@ -148,6 +161,18 @@ func RegisterKnowledgeRoutes(r *gin.RouterGroup, handler *handler.KnowledgeHandl
 		kb.POST("/url", handler.CreateKnowledgeFromURL)
 		// Manual Markdown entry
 		kb.POST("/manual", handler.CreateManualKnowledge)
+		// Preview chunking of a file without creating knowledge
+		kb.POST("/preview", handler.PreviewKnowledgeChunks)
+		// List knowledge with failed processing stages
+		kb.GET("/failed", handler.ListFailedKnowledge)
+		// Retry failed processing stages in batch
+		kb.POST("/retry", handler.RetryFailedKnowledge)
+		// List knowledge that expires or is due for review soon
+		kb.GET("/expiring", handler.ListExpiringKnowledge)
+		// Report clusters of near-duplicate knowledge
+		kb.GET("/duplicates", handler.GetDuplicateReport)
+		// Disable redundant near-duplicate knowledge
+		kb.POST("/duplicates/disable", handler.DisableDuplicateKnowledge)
 		// Get knowledge list under knowledge base
 		kb.GET("", handler.ListKnowledge)
 	}
@@ -167,6 +192,14 @@ func RegisterKnowledgeRoutes(r *gin.RouterGroup, handler *handler.KnowledgeHandl
 		k.PUT("/manual/:id", handler.UpdateManualKnowledge)
 		// Get knowledge file
 		k.GET("/:id/download", handler.DownloadKnowledgeFile)
+		// Get knowledge processing stages
+		k.GET("/:id/stages", handler.ListKnowledgeStages)
+		// Retry a failed processing stage
+		k.POST("/:id/stages/:stage/retry", handler.RetryKnowledgeStage)
+		// Set knowledge validity period and review date
+		k.PUT("/:id/validity", handler.UpdateKnowledgeValidity)
+		// Enable or disable knowledge in retrieval
+		k.PUT("/:id/enabled", handler.UpdateKnowledgeEnabled)
 		// Update image chunk info
 		k.PUT("/image/:id/:chunk_id", handler.UpdateImageInfo)
 		// Batch update knowledge tags
@@ -190,11 +223,24 @@ func RegisterFAQRoutes(r *gin.RouterGroup, handler *handler.FAQHandler) {
 		faq.POST("/entry", handler.CreateEntry)
 		faq.PUT("/entries/:entry_id", handler.UpdateEntry)
 		faq.POST("/entries/:entry_id/similar-questions", handler.AddSimilarQuestions)
+		// FAQ entry review workflow
+		faq.POST("/entries/:entry_id/review", handler.ReviewEntry)
+		faq.PUT("/entries/:entry_id/reviewer", handler.AssignEntryReviewer)
+		faq.GET("/entries/:entry_id/comments", handler.ListEntryComments)
+		faq.POST("/entries/:entry_id/comments", handler.AddEntryComment)
 		// Unified batch update API - supports is_enabled, is_recommended, tag_id
 		faq.PUT("/entries/fields", handler.UpdateEntryFieldsBatch)
 		faq.PUT("/entries/tags", handler.UpdateEntryTagBatch)
 		faq.DELETE("/entries", handler.DeleteEntries)
 		faq.POST("/search", handler.SearchFAQ)
+		// FAQ candidates mined from chat history
+		faq.POST("/mining", handler.StartMining)
+		faq.GET("/candidates", handler.ListCandidates)
+		faq.POST("/candidates/:candidate_id/accept", handler.AcceptCandidate)
+		faq.POST("/candidates/:candidate_id/reject", handler.RejectCandidate)
+		// FAQ conflict detection
+		faq.POST("/conflicts/scan", handler.ScanConflicts)
+		faq.GET("/conflicts", handler.GetConflictReport)
 		// FAQ import result display status
 		faq.PUT("/import/last-result/display", handler.UpdateLastImportResultDisplayStatus)
 	}
@@ -205,6 +251,18 @@ func RegisterFAQRoutes(r *gin.RouterGroup, handler *handler.FAQHandler) {
 	}
 }
 
+// RegisterAnalyticsRoutes registers knowledge base analytics routes
+func RegisterAnalyticsRoutes(r *gin.RouterGroup, handler *handler.AnalyticsHandler) {
+	analytics := r.Group("/knowledge-bases/:id/analytics")
+	{
+		analytics.GET("", handler.GetSummary)
+		analytics.GET("/top-hits", handler.ListTopHits)
+		analytics.GET("/never-hit", handler.ListNeverHit)
+		analytics.GET("/queries", handler.ListQueries)
+		analytics.GET("/feedback", handler.ListFeedback)
+	}
+}
+
 // RegisterKnowledgeBaseRoutes registers knowledge base-related routes
 func RegisterKnowledgeBaseRoutes(r *gin.RouterGroup, handler *handler.KnowledgeBaseHandler) {
 	// Knowledge base route group
@@ -252,6 +310,10 @@ func RegisterMessageRoutes(r *gin.RouterGroup, handler *handler.MessageHandler)
 		messages.GET("/:session_id/load", handler.LoadMessages)
 		// Delete message
 		messages.DELETE("/:session_id/:id", handler.DeleteMessage)
+		// Save web references of a message as knowledge
+		messages.POST("/:session_id/:id/web-references", handler.SaveWebReferences)
+		// Rate an assistant message
+		messages.PUT("/:session_id/:id/feedback", handler.SubmitFeedback)
 	}
 }
 
@@ -276,12 +338,16 @@ func RegisterChatRoutes(r *gin.RouterGroup, handler *session.Handler) {
 	knowledgeChat := r.Group("/knowledge-chat")
 	{
 		knowledgeChat.POST("/:session_id", handler.KnowledgeQA)
+		// Non-streaming variant returning a single JSON document
+		knowledgeChat.POST("/:session_id/sync", handler.KnowledgeQASync)
 	}
 
 	// Agent-based chat
 	agentChat := r.Group("/agent-chat")
 	{
 		agentChat.POST("/:session_id", handler.AgentQA)
+		// Non-streaming variant returning a single JSON document
+		agentChat.POST("/:session_id/sync", handler.AgentQASync)
 	}
 
 	// New knowledge retrieval interface, does not require session_id
@@ -291,6 +357,12 @@ func RegisterChatRoutes(r *gin.RouterGroup, handler *session.Handler) {
 	}
 }
 
+// RegisterOpenAICompatibleRoutes registers OpenAI compatible routes
+func RegisterOpenAICompatibleRoutes(r *gin.RouterGroup, handler *session.Handler) {
+	r.POST("/chat/completions", handler.ChatCompletions)
+	r.GET("/models", handler.ListOpenAIModels)
+}
+
 // RegisterTenantRoutes registers tenant-related routes
 func RegisterTenantRoutes(r *gin.RouterGroup, handler *handler.TenantHandler) {
 	// Add route to get all tenants (requires cross-tenant permission)
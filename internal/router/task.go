@@ -19,6 +19,7 @@ type AsynqTaskParams struct {
 	KnowledgeService     interfaces.KnowledgeService
 	KnowledgeBaseService interfaces.KnowledgeBaseService
 	TagService           interfaces.KnowledgeTagService
+	FAQMiningService     interfaces.FAQMiningService
 	ChunkExtracter       interfaces.TaskHandler `name:"chunkExtracter"`
 	DataTableSummary     interfaces.TaskHandler `name:"dataTableSummary"`
 }
@@ -85,6 +86,9 @@ func RunAsynqServer(params AsynqTaskParams) *asynq.ServeMux {
 	// Register summary generation handler
 	mux.HandleFunc(types.TypeSummaryGeneration, params.KnowledgeService.ProcessSummaryGeneration)
 
+	// Register knowledge re-indexing handler
+	mux.HandleFunc(types.TypeKnowledgeReindex, params.KnowledgeService.ProcessKnowledgeReindex)
+
 	// Register KB clone handler
 	mux.HandleFunc(types.TypeKBClone, params.KnowledgeService.ProcessKBClone)
 
@@ -97,6 +101,18 @@ func RunAsynqServer(params AsynqTaskParams) *asynq.ServeMux {
 	// Register KB delete handler
 	mux.HandleFunc(types.TypeKBDelete, params.KnowledgeBaseService.ProcessKBDelete)
 
+	// Register scheduled knowledge expiry sweep handler
+	mux.HandleFunc(types.TypeKnowledgeExpirySweep, params.KnowledgeService.ProcessKnowledgeExpirySweep)
+
+	// Register FAQ conflict scan handler
+	mux.HandleFunc(types.TypeFAQConflictScan, params.KnowledgeService.ProcessFAQConflictScan)
+
+	// Register FAQ candidate mining handler
+	mux.HandleFunc(types.TypeFAQMining, params.FAQMiningService.ProcessFAQMining)
+
+	// Register scheduled FAQ candidate mining sweep handler
+	mux.HandleFunc(types.TypeFAQMiningSweep, params.FAQMiningService.ProcessFAQMiningSweep)
+
 	go func() {
 		// Start the server
 		if err := params.Server.Run(mux); err != nil {
@@ -105,3 +121,49 @@ func RunAsynqServer(params AsynqTaskParams) *asynq.ServeMux {
 	}()
 	return mux
 }
+
+// NewAsynqScheduler creates the scheduler that enqueues periodic tasks
+func NewAsynqScheduler() *asynq.Scheduler {
+	return asynq.NewScheduler(getAsynqRedisClientOpt(), nil)
+}
+
+// RunAsynqScheduler registers the periodic tasks and starts the scheduler.
+// The knowledge expiry sweep runs hourly unless KNOWLEDGE_EXPIRY_SWEEP_CRON sets another cron spec,
+// the FAQ mining sweep daily unless FAQ_MINING_SWEEP_CRON does.
+func RunAsynqScheduler(scheduler *asynq.Scheduler, resourceCleaner interfaces.ResourceCleaner) error {
+	expirySweepSpec := os.Getenv("KNOWLEDGE_EXPIRY_SWEEP_CRON")
+	if expirySweepSpec == "" {
+		expirySweepSpec = "@every 1h"
+	}
+	// Unique keeps several server instances from running the same sweep concurrently
+	if _, err := scheduler.Register(
+		expirySweepSpec,
+		asynq.NewTask(types.TypeKnowledgeExpirySweep, nil),
+		asynq.Queue("low"),
+		asynq.Unique(time.Hour),
+	); err != nil {
+		return err
+	}
+
+	miningSweepSpec := os.Getenv("FAQ_MINING_SWEEP_CRON")
+	if miningSweepSpec == "" {
+		miningSweepSpec = "@daily"
+	}
+	if _, err := scheduler.Register(
+		miningSweepSpec,
+		asynq.NewTask(types.TypeFAQMiningSweep, nil),
+		asynq.Queue("low"),
+		asynq.Unique(time.Hour),
+	); err != nil {
+		return err
+	}
+
+	if err := scheduler.Start(); err != nil {
+		return err
+	}
+	resourceCleaner.RegisterWithName("AsynqScheduler", func() error {
+		scheduler.Shutdown()
+		return nil
+	})
+	return nil
+}
@@ -2,6 +2,7 @@ package searchutil
 
 import (
 	"fmt"
+	"strconv"
 	"time"
 	"unicode/utf8"
 
@@ -61,6 +62,12 @@ func ConvertWebSearchResults(
 		appendContent(webResult.Snippet)
 		appendContent(webResult.Content)
 
+		// Trusted domains get a higher base score, so the weight carries into rerank
+		score := 0.6
+		if webResult.TrustWeight > 0 {
+			score = ClampFloat(score*webResult.TrustWeight, 0, 1)
+		}
+
 		result := &types.SearchResult{
 			ID:             chunkID,
 			Content:        content,
@@ -70,7 +77,7 @@ func ConvertWebSearchResults(
 			StartAt:        0,
 			EndAt:          utf8.RuneCountInString(content),
 			Seq:            options.seqFunc(i),
-			Score:          0.6,
+			Score:          score,
 			MatchType:      types.MatchTypeWebSearch,
 			SubChunkID:     []string{},
 			Metadata: map[string]string{
@@ -89,6 +96,13 @@ func ConvertWebSearchResults(
 		if webResult.PublishedAt != nil {
 			result.Metadata["published_at"] = webResult.PublishedAt.Format(time.RFC3339)
 		}
+		if webResult.TrustWeight > 0 {
+			result.Metadata["trust_weight"] = strconv.FormatFloat(webResult.TrustWeight, 'f', -1, 64)
+		}
+		if webResult.CachedAt != nil {
+			result.Metadata["cache_hit"] = "true"
+			result.Metadata["cached_at"] = webResult.CachedAt.Format(time.RFC3339)
+		}
 
 		results = append(results, result)
 	}
@@ -0,0 +1,272 @@
+package searchutil
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// AnswerLanguageInstruction is appended to the system prompt of an answer
+	AnswerLanguageInstruction = "## Response Language\n" +
+		"- Answer in {{language}}, the language of the user's question, " +
+		"even when the retrieved information is in another language."
+	// unknownLanguageName stands in for the language name when the query language is unknown
+	unknownLanguageName = "the language of the user's question"
+)
+
+// languageNames maps ISO 639-1 codes to the language names used in prompts
+var languageNames = map[string]string{
+	"ar": "Arabic",
+	"de": "German",
+	"en": "English",
+	"es": "Spanish",
+	"fr": "French",
+	"hi": "Hindi",
+	"id": "Indonesian",
+	"it": "Italian",
+	"ja": "Japanese",
+	"ko": "Korean",
+	"pt": "Portuguese",
+	"ru": "Russian",
+	"th": "Thai",
+	"vi": "Vietnamese",
+	"zh": "Chinese",
+}
+
+// LanguageName returns the English name of a language code, or the code itself when unknown
+func LanguageName(code string) string {
+	code = NormalizeLanguage(code)
+	if name, ok := languageNames[code]; ok {
+		return name
+	}
+	return code
+}
+
+// WithLanguage fills the {{language}} placeholder of a prompt with the name of the query language.
+// Prompts without the placeholder get the instruction appended when the language is known.
+func WithLanguage(prompt string, language string, instruction string) string {
+	name := unknownLanguageName
+	if language != "" {
+		name = LanguageName(language)
+	}
+	if strings.Contains(prompt, "{{language}}") {
+		return strings.ReplaceAll(prompt, "{{language}}", name)
+	}
+	if language == "" || instruction == "" {
+		return prompt
+	}
+	return strings.TrimRight(prompt, "\n") + "\n\n" + strings.ReplaceAll(instruction, "{{language}}", name)
+}
+
+// NormalizeLanguage reduces a language code or locale to its lowercase language part, e.g. "ko-KR" to "ko"
+func NormalizeLanguage(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if i := strings.IndexAny(code, "-_"); i >= 0 {
+		code = code[:i]
+	}
+	return code
+}
+
+// DetectLanguage guesses the language of a text and returns its ISO 639-1 code, or "" when it is
+// unknown. Scripts map to ko (Hangul), ja (Hiragana or Katakana, also with Han), zh (Han only),
+// ru (Cyrillic), ar (Arabic), th (Thai) and hi (Devanagari). Latin script is told apart by
+// detectLatinLanguage, and stays unknown when that is not conclusive.
+func DetectLanguage(text string) string {
+	counts := make(map[string]int)
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Hangul, r):
+			counts["ko"]++
+		case unicode.Is(unicode.Hiragana, r), unicode.Is(unicode.Katakana, r):
+			counts["kana"]++
+		case unicode.Is(unicode.Han, r):
+			counts["zh"]++
+		case unicode.Is(unicode.Cyrillic, r):
+			counts["ru"]++
+		case unicode.Is(unicode.Arabic, r):
+			counts["ar"]++
+		case unicode.Is(unicode.Thai, r):
+			counts["th"]++
+		case unicode.Is(unicode.Devanagari, r):
+			counts["hi"]++
+		case unicode.Is(unicode.Latin, r):
+			counts["en"]++
+		}
+	}
+	// Japanese mixes kana with Han, a few kana are enough to tell it from Chinese
+	if counts["kana"] > 0 {
+		counts["ja"] = counts["kana"] + counts["zh"]
+		delete(counts, "zh")
+	}
+	delete(counts, "kana")
+
+	best, bestCount := "", 0
+	for _, code := range []string{"ko", "ja", "zh", "ru", "ar", "th", "hi"} {
+		if counts[code] > bestCount {
+			best, bestCount = code, counts[code]
+		}
+	}
+	// Latin letters only win when no other script is present in a comparable amount,
+	// so that product names and acronyms in CJK queries do not flip the language
+	if counts["en"] > 0 && counts["en"] > bestCount*3 {
+		return detectLatinLanguage(text)
+	}
+	return best
+}
+
+// latinStopwords are frequent function words of languages written in Latin script
+var latinStopwords = map[string][]string{
+	"en": {"the", "a", "an", "and", "or", "of", "to", "in", "on", "for", "with", "is", "are", "was", "be",
+		"how", "what", "why", "when", "where", "which", "who", "do", "does", "can", "i", "my", "you",
+		"your", "it", "this", "that", "not"},
+	"fr": {"le", "la", "les", "un", "une", "des", "du", "de", "et", "ou", "est", "sont", "pour", "avec",
+		"dans", "sur", "comment", "quoi", "pourquoi", "quand", "où", "qui", "que", "je", "mon", "ma",
+		"mes", "vous", "votre", "ne", "pas", "ce", "cette", "il", "elle"},
+	"es": {"el", "la", "los", "las", "un", "una", "de", "del", "y", "o", "es", "son", "para", "con", "en",
+		"por", "cómo", "qué", "cuándo", "dónde", "quién", "mi", "mis", "tu", "su", "no", "esto", "este",
+		"esta", "se", "puedo"},
+	"de": {"der", "die", "das", "den", "dem", "ein", "eine", "und", "oder", "ist", "sind", "für", "mit",
+		"in", "auf", "wie", "was", "warum", "wann", "wo", "wer", "ich", "mein", "meine", "sie", "ihr",
+		"nicht", "kann", "es", "zu", "von"},
+	"pt": {"o", "a", "os", "as", "um", "uma", "de", "do", "da", "dos", "das", "e", "ou", "é", "são", "para",
+		"com", "em", "no", "na", "como", "que", "quando", "onde", "quem", "meu", "minha", "você", "não",
+		"isso", "posso"},
+	"it": {"il", "lo", "la", "gli", "le", "un", "una", "di", "del", "della", "e", "o", "è", "sono", "per",
+		"con", "in", "su", "come", "cosa", "perché", "quando", "dove", "chi", "mio", "mia", "non", "posso",
+		"questo"},
+	"id": {"yang", "dan", "atau", "di", "ke", "dari", "untuk", "dengan", "ini", "itu", "apa", "bagaimana",
+		"mengapa", "kapan", "mana", "siapa", "saya", "anda", "tidak", "bisa", "cara", "adalah"},
+}
+
+// detectLatinLanguage tells apart languages written in Latin script. Vietnamese is recognized by its
+// letters, other languages by their function words. Short or mixed texts stay unknown, since a wrong
+// guess makes answers come back in the wrong language.
+func detectLatinLanguage(text string) string {
+	vietnamese := 0
+	for _, r := range strings.ToLower(text) {
+		// ă, đ, ơ, ư and the Latin Extended Additional block hold the Vietnamese letters with tone marks
+		if r == 'ă' || r == 'đ' || r == 'ơ' || r == 'ư' || (r >= 0x1EA0 && r <= 0x1EF9) {
+			vietnamese++
+		}
+	}
+	if vietnamese >= 2 {
+		return "vi"
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	hits := make(map[string]int)
+	for language, stopwords := range latinStopwords {
+		seen := make(map[string]bool)
+		for _, word := range words {
+			if !seen[word] && slices.Contains(stopwords, word) {
+				seen[word] = true
+				hits[language]++
+			}
+		}
+	}
+	best, bestHits, secondHits := "", 0, 0
+	for _, language := range []string{"en", "fr", "es", "de", "pt", "it", "id"} {
+		switch {
+		case hits[language] > bestHits:
+			best, bestHits, secondHits = language, hits[language], bestHits
+		case hits[language] > secondHits:
+			secondHits = hits[language]
+		}
+	}
+	if bestHits < 2 || bestHits < secondHits*2 {
+		return ""
+	}
+	return best
+}
+
+// ContainsHangul reports whether the text contains Hangul
+func ContainsHangul(text string) bool {
+	for _, r := range text {
+		if unicode.Is(unicode.Hangul, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// koreanParticles are particles (josa) attached to Korean nouns, longest first
+var koreanParticles = []string{
+	"에서부터", "으로부터", "에게서", "한테서", "으로서", "으로써", "이라고", "이라는", "에서는", "에서도",
+	"으로는", "에게는", "까지는", "부터는", "이라도",
+	"에서", "에게", "한테", "께서", "까지", "부터", "으로", "처럼", "보다", "마다", "이나", "이랑", "라고",
+	"라는", "로서", "로써", "하고", "와는", "과는", "은", "는", "이", "가", "을", "를", "에", "의", "로",
+	"와", "과", "도", "만",
+}
+
+// koreanStopwords are Korean question and function words that carry no keyword
+var koreanStopwords = map[string]struct{}{
+	"무엇": {}, "무엇인가요": {}, "무엇입니까": {}, "뭐": {}, "뭔가요": {}, "뭐예요": {}, "왜": {}, "언제": {},
+	"어디": {}, "어디서": {}, "어디에": {}, "누가": {}, "누구": {}, "어떤": {}, "어떻게": {}, "어느": {},
+	"얼마나": {}, "있나요": {}, "있습니까": {}, "있어요": {}, "없나요": {}, "하나요": {}, "합니까": {},
+	"해요": {}, "하나": {}, "인가요": {}, "입니까": {}, "인지": {}, "되나요": {}, "됩니까": {}, "알려주세요": {},
+	"알려줘": {}, "주세요": {}, "좀": {}, "그리고": {}, "또는": {}, "및": {}, "수": {}, "것": {}, "등": {},
+}
+
+// stripKoreanParticle removes a trailing particle from a Hangul word when a stem of at least
+// two syllables remains
+func stripKoreanParticle(word string) string {
+	for _, particle := range koreanParticles {
+		stem, ok := strings.CutSuffix(word, particle)
+		if ok && utf8.RuneCountInString(stem) >= 2 {
+			return stem
+		}
+	}
+	return word
+}
+
+// TokenizeKorean splits text with Korean into lowercase keyword tokens. Words are split on
+// whitespace and punctuation, question and function words are dropped and particles are
+// stripped from Hangul words. Both a word and its stem are kept, since the trailing syllable
+// of a noun can look like a particle.
+func TokenizeKorean(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	seen := make(map[string]struct{}, len(words))
+	tokens := make([]string, 0, len(words)*2)
+	add := func(token string) {
+		if _, stop := koreanStopwords[token]; stop || token == "" {
+			return
+		}
+		if _, ok := seen[token]; ok {
+			return
+		}
+		seen[token] = struct{}{}
+		tokens = append(tokens, token)
+	}
+	for _, word := range words {
+		if !ContainsHangul(word) {
+			add(word)
+			continue
+		}
+		stem := stripKoreanParticle(word)
+		if _, stop := koreanStopwords[stem]; stop {
+			continue
+		}
+		add(stem)
+		add(word)
+	}
+	return tokens
+}
+
+// PrepareKeywordQuery rewrites a query for keyword retrieval. Queries with Korean are reduced to
+// their keyword tokens since Korean particles keep words from matching, other queries are unchanged.
+func PrepareKeywordQuery(query string) string {
+	if !ContainsHangul(query) {
+		return query
+	}
+	tokens := TokenizeKorean(query)
+	if len(tokens) == 0 {
+		return query
+	}
+	return strings.Join(tokens, " ")
+}
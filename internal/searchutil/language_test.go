@@ -0,0 +1,88 @@
+package searchutil
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"비밀번호를 어떻게 재설정하나요?", "ko"},
+		{"How do I reset my password?", "en"},
+		{"Comment réinitialiser mon mot de passe ?", "fr"},
+		{"¿Cómo puedo cambiar mi contraseña?", "es"},
+		{"Wie kann ich mein Passwort zurücksetzen?", "de"},
+		{"Làm thế nào để đặt lại mật khẩu?", "vi"},
+		{"reset password", ""},
+		{"Password reset", ""},
+		{"如何重置密码？", "zh"},
+		{"パスワードをリセットする方法", "ja"},
+		{"Как сбросить пароль?", "ru"},
+		{"WeKnora 비밀번호 재설정", "ko"},
+		{"iPhone 电池续航", "zh"},
+		{"12345 ?!", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := DetectLanguage(tt.text); got != tt.want {
+				t.Errorf("DetectLanguage(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenizeKorean(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"particles stripped", "비밀번호를 어떻게 재설정하나요?", "비밀번호,비밀번호를,재설정하나요"},
+		{"short stem kept whole", "회의 일정은", "회의,일정,일정은"},
+		{"latin words lowercased", "WeKnora에서 API 키", "weknora,weknora에서,api,키"},
+		{"only stopwords", "무엇 어떻게", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.Join(TokenizeKorean(tt.text), ","); got != tt.want {
+				t.Errorf("TokenizeKorean(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrepareKeywordQuery(t *testing.T) {
+	if got := PrepareKeywordQuery("How do I reset it?"); got != "How do I reset it?" {
+		t.Errorf("PrepareKeywordQuery() changed a query without Korean: %q", got)
+	}
+	if got := PrepareKeywordQuery("요금제를 변경하려면"); got != "요금제 요금제를 변경하려면" {
+		t.Errorf("PrepareKeywordQuery() = %q", got)
+	}
+}
+
+func TestWithLanguage(t *testing.T) {
+	tests := []struct {
+		name        string
+		prompt      string
+		language    string
+		instruction string
+		want        string
+	}{
+		{"placeholder filled", "Reply in {{language}}.", "ko", "", "Reply in Korean."},
+		{"placeholder without language", "Reply in {{language}}.", "", "", "Reply in the language of the user's question."},
+		{"instruction appended", "Be brief.\n", "ja", "- Use {{language}}", "Be brief.\n\n- Use Japanese"},
+		{"unknown language unchanged", "Be brief.", "", "- Use {{language}}", "Be brief."},
+		{"unnamed code used as is", "Be brief.", "sw", "- Use {{language}}", "Be brief.\n\n- Use sw"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WithLanguage(tt.prompt, tt.language, tt.instruction); got != tt.want {
+				t.Errorf("WithLanguage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -18,14 +18,16 @@ type AgentConfig struct {
 	KnowledgeIDs      []string `json:"knowledge_ids"`           // Accessible knowledge IDs (individual documents)
 	SystemPrompt      string   `json:"system_prompt,omitempty"` // Unified system prompt (uses {{web_search_status}} placeholder for dynamic behavior)
 	// Deprecated: Use SystemPrompt instead. Kept for backward compatibility during migration.
-	SystemPromptWebEnabled  string        `json:"system_prompt_web_enabled,omitempty"`  // Deprecated: Custom prompt when web search is enabled
-	SystemPromptWebDisabled string        `json:"system_prompt_web_disabled,omitempty"` // Deprecated: Custom prompt when web search is disabled
-	UseCustomSystemPrompt   bool          `json:"use_custom_system_prompt"`             // Whether to use custom system prompt instead of default
-	WebSearchEnabled        bool          `json:"web_search_enabled"`                   // Whether web search tool is enabled
-	WebSearchMaxResults     int           `json:"web_search_max_results"`               // Maximum number of web search results (default: 5)
-	MultiTurnEnabled        bool          `json:"multi_turn_enabled"`                   // Whether multi-turn conversation is enabled
-	HistoryTurns            int           `json:"history_turns"`                        // Number of history turns to keep in context
-	SearchTargets           SearchTargets `json:"-"`                                    // Pre-computed unified search targets (runtime only)
+	SystemPromptWebEnabled  string             `json:"system_prompt_web_enabled,omitempty"`  // Deprecated: Custom prompt when web search is enabled
+	SystemPromptWebDisabled string             `json:"system_prompt_web_disabled,omitempty"` // Deprecated: Custom prompt when web search is disabled
+	UseCustomSystemPrompt   bool               `json:"use_custom_system_prompt"`             // Whether to use custom system prompt instead of default
+	WebSearchEnabled        bool               `json:"web_search_enabled"`                   // Whether web search tool is enabled
+	WebSearchMaxResults     int                `json:"web_search_max_results"`               // Maximum number of web search results (default: 5)
+	WebSearchAllowlist      []string           `json:"web_search_allowlist,omitempty"`       // Web search allowlist overriding the tenant allowlist
+	WebSearchTrustWeights   map[string]float64 `json:"web_search_trust_weights,omitempty"`   // Web search trust weights merged over the tenant ones
+	MultiTurnEnabled        bool               `json:"multi_turn_enabled"`                   // Whether multi-turn conversation is enabled
+	HistoryTurns            int                `json:"history_turns"`                        // Number of history turns to keep in context
+	SearchTargets           SearchTargets      `json:"-"`                                    // Pre-computed unified search targets (runtime only)
 	// MCP service selection
 	MCPSelectionMode string   `json:"mcp_selection_mode"` // MCP selection mode: "all", "selected", "none"
 	MCPServices      []string `json:"mcp_services"`       // Selected MCP service IDs (when mode is "selected")
@@ -33,6 +35,8 @@ type AgentConfig struct {
 	Thinking *bool `json:"thinking"`
 	// Whether to retrieve knowledge base only when explicitly mentioned with @ (default: false)
 	RetrieveKBOnlyWhenMentioned bool `json:"retrieve_kb_only_when_mentioned"`
+	// QueryLanguage is the ISO 639-1 code of the language detected in the query, the answer is given in it (runtime only)
+	QueryLanguage string `json:"-"`
 }
 
 // SessionAgentConfig represents session-level agent configuration
@@ -0,0 +1,241 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// LowConfidenceQueryScore is the best reranked or vector similarity score of the results below which
+	// a chat query is logged as low confidence
+	LowConfidenceQueryScore = 0.5
+	// DefaultAnalyticsDays is the number of days covered by analytics when no date range is given
+	DefaultAnalyticsDays = 30
+	// MaxAnalyticsDays is the longest date range analytics can cover
+	MaxAnalyticsDays = 366
+	// AnalyticsDateLayout is the layout of the dates of an analytics date range
+	AnalyticsDateLayout = "2006-01-02"
+)
+
+// KnowledgeHitStat counts how often a chunk was returned to the chat on one day
+type KnowledgeHitStat struct {
+	// Tenant ID
+	TenantID uint64 `json:"tenant_id"`
+	// ID of the knowledge base of the chunk
+	KnowledgeBaseID string `json:"knowledge_base_id" gorm:"type:varchar(36)"`
+	// ID of the knowledge of the chunk
+	KnowledgeID string `json:"knowledge_id"      gorm:"type:varchar(36)"`
+	// ID of the chunk
+	ChunkID string `json:"chunk_id"          gorm:"type:varchar(36);primaryKey"`
+	// Type of the chunk, such as text or faq
+	ChunkType string `json:"chunk_type"        gorm:"type:varchar(20)"`
+	// Day the hits were counted on
+	StatDate time.Time `json:"stat_date"         gorm:"type:date;primaryKey"`
+	// Number of times the chunk was returned on the day
+	HitCount int64 `json:"hit_count"`
+}
+
+// TableName returns the table name of KnowledgeHitStat
+func (KnowledgeHitStat) TableName() string {
+	return "knowledge_hit_stats"
+}
+
+// QueryLogType tells why a chat query was logged
+type QueryLogType string
+
+const (
+	// QueryLogTypeZeroResult means retrieval returned nothing and the fallback answer was given
+	QueryLogTypeZeroResult QueryLogType = "zero_result"
+	// QueryLogTypeLowConfidence means the best result scored below LowConfidenceQueryScore
+	QueryLogTypeLowConfidence QueryLogType = "low_confidence"
+)
+
+// IsValid reports whether the query log type is known
+func (t QueryLogType) IsValid() bool {
+	return t == QueryLogTypeZeroResult || t == QueryLogTypeLowConfidence
+}
+
+// QueryLog is a chat query the knowledge base answered poorly, logged once per searched knowledge base
+type QueryLog struct {
+	// Unique identifier of the log
+	ID string `json:"id"                gorm:"type:varchar(36);primaryKey"`
+	// Tenant ID
+	TenantID uint64 `json:"tenant_id"`
+	// ID of the searched knowledge base
+	KnowledgeBaseID string `json:"knowledge_base_id" gorm:"type:varchar(36)"`
+	// ID of the chat session
+	SessionID string `json:"session_id"        gorm:"type:varchar(36)"`
+	// ID of the assistant message answering the query
+	MessageID string `json:"message_id"        gorm:"type:varchar(36)"`
+	// Query asked by the user
+	Query string `json:"query"`
+	// Query after rewriting, as used for retrieval
+	RewriteQuery string `json:"rewrite_query"`
+	// Why the query was logged
+	Type QueryLogType `json:"type"              gorm:"type:varchar(20)"`
+	// Number of results returned to the chat
+	ResultCount int `json:"result_count"`
+	// Best score of the results returned to the chat
+	MaxScore float64 `json:"max_score"`
+	// Creation time of the log
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName returns the table name of QueryLog
+func (QueryLog) TableName() string {
+	return "query_logs"
+}
+
+// MessageFeedbackRating is the rating of an assistant message
+type MessageFeedbackRating string
+
+const (
+	// MessageFeedbackRatingUp means the answer was helpful
+	MessageFeedbackRatingUp MessageFeedbackRating = "up"
+	// MessageFeedbackRatingDown means the answer was not helpful
+	MessageFeedbackRatingDown MessageFeedbackRating = "down"
+)
+
+// IsValid reports whether the rating is up or down
+func (r MessageFeedbackRating) IsValid() bool {
+	return r == MessageFeedbackRatingUp || r == MessageFeedbackRatingDown
+}
+
+// MessageFeedback is the thumbs-up or thumbs-down feedback on an assistant message
+type MessageFeedback struct {
+	// Unique identifier of the feedback
+	ID string `json:"id"         gorm:"type:varchar(36);primaryKey"`
+	// Tenant ID
+	TenantID uint64 `json:"tenant_id"`
+	// ID of the chat session
+	SessionID string `json:"session_id" gorm:"type:varchar(36)"`
+	// ID of the rated assistant message, a message has one feedback
+	MessageID string `json:"message_id" gorm:"type:varchar(36)"`
+	// ID of the user who gave the feedback, empty for API key calls
+	UserID string `json:"user_id"    gorm:"type:varchar(36)"`
+	// Rating of the answer
+	Rating MessageFeedbackRating `json:"rating"     gorm:"type:varchar(8)"`
+	// Optional comment explaining the rating
+	Comment string `json:"comment"`
+	// IDs of the chunks the answer cited
+	ChunkIDs StringArray `json:"chunk_ids"  gorm:"type:jsonb"`
+	// Creation time of the feedback
+	CreatedAt time.Time `json:"created_at"`
+	// Last update time of the feedback
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName returns the table name of MessageFeedback
+func (MessageFeedback) TableName() string {
+	return "message_feedbacks"
+}
+
+// MessageFeedbackChunk links a feedback to a chunk the rated answer cited
+type MessageFeedbackChunk struct {
+	// ID of the feedback
+	FeedbackID string `json:"feedback_id"       gorm:"type:varchar(36);primaryKey"`
+	// ID of the cited chunk
+	ChunkID string `json:"chunk_id"          gorm:"type:varchar(36);primaryKey"`
+	// Tenant ID
+	TenantID uint64 `json:"tenant_id"`
+	// ID of the knowledge base of the chunk
+	KnowledgeBaseID string `json:"knowledge_base_id" gorm:"type:varchar(36)"`
+	// ID of the knowledge of the chunk
+	KnowledgeID string `json:"knowledge_id"      gorm:"type:varchar(36)"`
+	// Rating of the feedback, copied for aggregation
+	Rating MessageFeedbackRating `json:"rating"            gorm:"type:varchar(8)"`
+	// Last update time of the feedback
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName returns the table name of MessageFeedbackChunk
+func (MessageFeedbackChunk) TableName() string {
+	return "message_feedback_chunks"
+}
+
+// MessageFeedbackRequest rates an assistant message
+type MessageFeedbackRequest struct {
+	Rating MessageFeedbackRating `json:"rating"  binding:"required"`
+	// Optional comment explaining the rating
+	Comment string `json:"comment"`
+}
+
+// AnalyticsDateRange is the inclusive range of days analytics cover
+type AnalyticsDateRange struct {
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+}
+
+// ParseAnalyticsDateRange parses the start and end dates of analytics (YYYY-MM-DD). A missing end
+// date means today and a missing start date means DefaultAnalyticsDays days before the end date.
+func ParseAnalyticsDateRange(start, end string, now time.Time) (AnalyticsDateRange, error) {
+	var r AnalyticsDateRange
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	r.EndDate = today
+	if end != "" {
+		d, err := time.Parse(AnalyticsDateLayout, end)
+		if err != nil {
+			return r, fmt.Errorf("end_date must be a date like 2006-01-02")
+		}
+		r.EndDate = d
+	}
+	r.StartDate = r.EndDate.AddDate(0, 0, 1-DefaultAnalyticsDays)
+	if start != "" {
+		d, err := time.Parse(AnalyticsDateLayout, start)
+		if err != nil {
+			return r, fmt.Errorf("start_date must be a date like 2006-01-02")
+		}
+		r.StartDate = d
+	}
+	if r.StartDate.After(r.EndDate) {
+		return r, fmt.Errorf("start_date must not be after end_date")
+	}
+	if r.EndDate.Sub(r.StartDate) >= MaxAnalyticsDays*24*time.Hour {
+		return r, fmt.Errorf("date range must not exceed %d days", MaxAnalyticsDays)
+	}
+	return r, nil
+}
+
+// EndExclusive returns the start of the day after the end date, to compare timestamps with
+func (r AnalyticsDateRange) EndExclusive() time.Time {
+	return r.EndDate.AddDate(0, 0, 1)
+}
+
+// KnowledgeBaseAnalytics summarizes how a knowledge base was used in a date range
+type KnowledgeBaseAnalytics struct {
+	KnowledgeBaseID string             `json:"knowledge_base_id"`
+	DateRange       AnalyticsDateRange `json:"date_range"`
+	// Times chunks of the knowledge base were returned to the chat
+	TotalHits int64 `json:"total_hits"`
+	// Number of distinct chunks returned at least once
+	HitChunks int64 `json:"hit_chunks"`
+	// Chat queries that found nothing in the knowledge base
+	ZeroResultQueries int64 `json:"zero_result_queries"`
+	// Chat queries whose best result scored below LowConfidenceQueryScore
+	LowConfidenceQueries int64 `json:"low_confidence_queries"`
+	// Ratings of answers citing the knowledge base
+	UpFeedback   int64 `json:"up_feedback"`
+	DownFeedback int64 `json:"down_feedback"`
+}
+
+// AnalyticsEntry is a FAQ entry, chunk or document with its usage in a date range
+type AnalyticsEntry struct {
+	// ID of the chunk, empty for documents that were never hit
+	ChunkID string `json:"chunk_id,omitempty"`
+	// ID (seq_id) of the FAQ entry, only set for FAQ chunks
+	EntryID int64 `json:"entry_id,omitempty"`
+	// Type of the chunk, empty for documents that were never hit
+	ChunkType      string `json:"chunk_type,omitempty"`
+	KnowledgeID    string `json:"knowledge_id"`
+	KnowledgeTitle string `json:"knowledge_title,omitempty"`
+	// Standard question of a FAQ entry, or the beginning of a chunk
+	Title string `json:"title"`
+	// Times the entry was returned to the chat
+	HitCount int64 `json:"hit_count"`
+	// Last day the entry was returned to the chat
+	LastHitDate *time.Time `json:"last_hit_date,omitempty"`
+	// Ratings of answers citing the entry
+	UpFeedback   int64 `json:"up_feedback"`
+	DownFeedback int64 `json:"down_feedback"`
+}
@@ -7,9 +7,13 @@ type ChatManage struct {
 	Query        string     `json:"query,omitempty"`         // Original user query
 	RewriteQuery string     `json:"rewrite_query,omitempty"` // Query after rewriting for better retrieval
 	History      []*History `json:"history,omitempty"`       // Chat history for context
+	// QueryLanguage is the ISO 639-1 code of the language detected in the query, the answer is given in it
+	QueryLanguage string `json:"query_language,omitempty"`
 
 	KnowledgeBaseIDs []string `json:"knowledge_base_ids"`      // IDs of knowledge bases to search (multi-KB support)
 	KnowledgeIDs     []string `json:"knowledge_ids,omitempty"` // IDs of specific files to search (optional)
+	// MetadataFilters restricts retrieval by the custom metadata of knowledge (optional)
+	MetadataFilters MetadataFilters `json:"metadata_filters,omitempty"`
 	// SearchTargets is the pre-computed unified search targets
 	// Computed once at request entry point, used throughout the pipeline
 	SearchTargets    SearchTargets `json:"-"`
@@ -45,6 +49,8 @@ type ChatManage struct {
 	GraphResult     *GraphData        `json:"-"` // Graph data from search phase
 	UserContent     string            `json:"-"` // Processed user content
 	ChatResponse    *ChatResponse     `json:"-"` // Final response from chat model
+	// QueryTranslations is the query translated for keyword retrieval, by language
+	QueryTranslations map[string]string `json:"-"`
 
 	// Event system for streaming responses
 	EventBus  EventBusInterface `json:"-"` // EventBus for emitting streaming events
@@ -53,11 +59,16 @@ type ChatManage struct {
 	// Web search configuration (internal use)
 	TenantID         uint64 `json:"-"` // Tenant ID for retrieving web search config
 	WebSearchEnabled bool   `json:"-"` // Whether web search is enabled for this request
+	// Web search allowlist and trust weights of the custom agent, overriding the tenant config
+	WebSearchAllowlist    []string           `json:"-"`
+	WebSearchTrustWeights map[string]float64 `json:"-"`
 
 	// FAQ Strategy Settings
 	FAQPriorityEnabled       bool    `json:"-"` // Whether FAQ priority strategy is enabled
 	FAQDirectAnswerThreshold float64 `json:"-"` // Threshold for direct FAQ answer (similarity > this value)
 	FAQScoreBoost            float64 `json:"-"` // Score multiplier for FAQ results
+	// Locale and channel selecting the FAQ answer variants used in the context
+	FAQAnswerPreference FAQAnswerPreference `json:"-"`
 }
 
 // Clone creates a deep copy of the ChatManage object
@@ -70,6 +81,13 @@ func (c *ChatManage) Clone() *ChatManage {
 	knowledgeIDs := make([]string, len(c.KnowledgeIDs))
 	copy(knowledgeIDs, c.KnowledgeIDs)
 
+	// Deep copy metadata filters slice
+	var metadataFilters MetadataFilters
+	if len(c.MetadataFilters) > 0 {
+		metadataFilters = make(MetadataFilters, len(c.MetadataFilters))
+		copy(metadataFilters, c.MetadataFilters)
+	}
+
 	// Deep copy search targets slice
 	searchTargets := make(SearchTargets, len(c.SearchTargets))
 	for i, t := range c.SearchTargets {
@@ -87,9 +105,11 @@ func (c *ChatManage) Clone() *ChatManage {
 	return &ChatManage{
 		Query:            c.Query,
 		RewriteQuery:     c.RewriteQuery,
+		QueryLanguage:    c.QueryLanguage,
 		SessionID:        c.SessionID,
 		KnowledgeBaseIDs: knowledgeBaseIDs,
 		KnowledgeIDs:     knowledgeIDs,
+		MetadataFilters:  metadataFilters,
 		SearchTargets:    searchTargets,
 		VectorThreshold:  c.VectorThreshold,
 		KeywordThreshold: c.KeywordThreshold,
@@ -127,6 +147,7 @@ func (c *ChatManage) Clone() *ChatManage {
 		FAQPriorityEnabled:       c.FAQPriorityEnabled,
 		FAQDirectAnswerThreshold: c.FAQDirectAnswerThreshold,
 		FAQScoreBoost:            c.FAQScoreBoost,
+		FAQAnswerPreference:      c.FAQAnswerPreference,
 	}
 }
 
@@ -32,6 +32,9 @@ const (
 	ChunkTypeTableSummary ChunkType = "table_summary"
 	// ChunkTypeTableColumn represents a data table column description Chunk
 	ChunkTypeTableColumn ChunkType = "table_column"
+	// ChunkTypeChild represents a small text Chunk split from the text Chunk in ParentChunkID,
+	// indexed for precise matching when parent-child chunking is enabled
+	ChunkTypeChild ChunkType = "child"
 )
 
 // ChunkStatus defines different states of Chunk
@@ -43,6 +46,10 @@ const (
 	ChunkStatusStored ChunkStatus = 1
 	// ChunkStatusIndexed represents an indexed Chunk
 	ChunkStatusIndexed ChunkStatus = 2
+	// ChunkStatusIndexFailed represents a stored Chunk whose edited content could not be indexed
+	ChunkStatusIndexFailed ChunkStatus = 3
+	// ChunkStatusUnpublished represents a stored FAQ entry that is kept out of the index until it is published
+	ChunkStatusUnpublished ChunkStatus = 4
 )
 
 // ChunkFlags defines Chunk flag bits for managing multiple boolean states
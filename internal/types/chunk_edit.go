@@ -0,0 +1,63 @@
+package types
+
+import "time"
+
+// ChunkEditOperation is a manual change made to the chunks of a document
+type ChunkEditOperation string
+
+const (
+	// ChunkEditOperationEdit replaces the content of a chunk
+	ChunkEditOperationEdit ChunkEditOperation = "edit"
+	// ChunkEditOperationSplit splits a chunk in two at an offset
+	ChunkEditOperationSplit ChunkEditOperation = "split"
+	// ChunkEditOperationMerge merges two adjacent chunks
+	ChunkEditOperationMerge ChunkEditOperation = "merge"
+)
+
+// ChunkEdit records a manual change to text chunks. Edits are replayed in order when the
+// knowledge is parsed again, replacing the chunks whose contents match ContentsBefore with
+// ContentsAfter, so that manual changes survive re-ingestion.
+type ChunkEdit struct {
+	// Unique identifier of the edit
+	ID string `json:"id"               gorm:"type:varchar(36);primaryKey"`
+	// Tenant ID
+	TenantID uint64 `json:"tenant_id"`
+	// Knowledge base ID
+	KnowledgeBaseID string `json:"knowledge_base_id" gorm:"type:varchar(36)"`
+	// Knowledge ID
+	KnowledgeID string `json:"knowledge_id"      gorm:"type:varchar(36)"`
+	// ID of the chunk that holds the result of the edit, the first one after a split
+	ChunkID string `json:"chunk_id"          gorm:"type:varchar(36)"`
+	// ID of the chunk created by a split or removed by a merge
+	RelatedChunkID string `json:"related_chunk_id"  gorm:"type:varchar(36)"`
+	// Operation of the edit
+	Operation ChunkEditOperation `json:"operation"         gorm:"type:varchar(16)"`
+	// Contents of the consecutive chunks the edit replaced
+	ContentsBefore StringArray `json:"contents_before"   gorm:"type:jsonb"`
+	// Contents of the chunks the edit produced
+	ContentsAfter StringArray `json:"contents_after"    gorm:"type:jsonb"`
+	// Whether the edit no longer matches the chunks after the document was parsed again
+	Orphaned bool `json:"orphaned"`
+	// Creation time of the edit
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName returns the table name of ChunkEdit
+func (ChunkEdit) TableName() string {
+	return "chunk_edits"
+}
+
+// ChunkEditChanges are the chunk rows a manual edit writes, saved together with the edit
+type ChunkEditChanges struct {
+	// ShiftBy moves the text and child chunks from index ShiftFrom on, to make room for new chunks
+	ShiftFrom int
+	ShiftBy   int
+	// Updated chunks are saved in full
+	Updated []*Chunk
+	// Created chunks are inserted
+	Created []*Chunk
+	// DeletedIDs are the IDs of the deleted chunks
+	DeletedIDs []string
+	// Relinks maps a chunk ID to its new previous chunk ID
+	Relinks map[string]string
+}
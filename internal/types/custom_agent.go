@@ -123,6 +123,10 @@ type CustomAgentConfig struct {
 	WebSearchEnabled bool `yaml:"web_search_enabled" json:"web_search_enabled"`
 	// Maximum web search results
 	WebSearchMaxResults int `yaml:"web_search_max_results" json:"web_search_max_results"`
+	// Web search allowlist rules replacing the tenant allowlist when set
+	WebSearchAllowlist []string `yaml:"web_search_allowlist" json:"web_search_allowlist,omitempty"`
+	// Per-domain web search trust weights merged over the tenant trust weights
+	WebSearchTrustWeights map[string]float64 `yaml:"web_search_trust_weights" json:"web_search_trust_weights,omitempty"`
 
 	// ===== Multi-turn Conversation Settings =====
 	// Whether multi-turn conversation is enabled
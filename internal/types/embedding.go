@@ -23,19 +23,21 @@ const (
 	MatchTypeWebSearch    // Web search match type
 	MatchTypeDirectLoad   // Direct load match type
 	MatchTypeDataAnalysis // Data analysis match type
+	MatchTypeSmallToBig   // Parent chunk returned in place of its matched child chunks
 )
 
 // IndexInfo contains information about indexed content
 type IndexInfo struct {
-	ID              string     // Unique identifier
-	Content         string     // Content text
-	SourceID        string     // ID of the source document
-	SourceType      SourceType // Type of the source
-	ChunkID         string     // ID of the text chunk
-	KnowledgeID     string     // ID of the knowledge
-	KnowledgeBaseID string     // ID of the knowledge base
-	KnowledgeType   string     // Type of the knowledge (e.g., "faq", "manual")
-	TagID           string     // Tag ID for categorization (used for FAQ priority filtering)
-	IsEnabled       bool       // Whether the chunk is enabled for retrieval
-	IsRecommended   bool       // Whether the chunk is recommended
+	ID              string         // Unique identifier
+	Content         string         // Content text
+	SourceID        string         // ID of the source document
+	SourceType      SourceType     // Type of the source
+	ChunkID         string         // ID of the text chunk
+	KnowledgeID     string         // ID of the knowledge
+	KnowledgeBaseID string         // ID of the knowledge base
+	KnowledgeType   string         // Type of the knowledge (e.g., "faq", "manual")
+	TagID           string         // Tag ID for categorization (used for FAQ priority filtering)
+	IsEnabled       bool           // Whether the chunk is enabled for retrieval
+	IsRecommended   bool           // Whether the chunk is recommended
+	Metadata        map[string]any // Custom metadata of the knowledge, used for metadata filtering
 }
@@ -1,16 +1,21 @@
 package types
 
 const (
-	TypeChunkExtract        = "chunk:extract"
-	TypeDocumentProcess     = "document:process"      // Document processing task
-	TypeFAQImport           = "faq:import"            // FAQ import task (includes dry run mode)
-	TypeQuestionGeneration  = "question:generation"   // Question generation task
-	TypeSummaryGeneration   = "summary:generation"    // Summary generation task
-	TypeKBClone             = "kb:clone"              // Knowledge base clone task
-	TypeIndexDelete         = "index:delete"          // Index deletion task
-	TypeKBDelete            = "kb:delete"             // Knowledge base deletion task
-	TypeKnowledgeListDelete = "knowledge:list_delete" // Batch knowledge deletion task
-	TypeDataTableSummary    = "datatable:summary"     // Data table summary task
+	TypeChunkExtract         = "chunk:extract"
+	TypeDocumentProcess      = "document:process"       // Document processing task
+	TypeFAQImport            = "faq:import"             // FAQ import task (includes dry run mode)
+	TypeQuestionGeneration   = "question:generation"    // Question generation task
+	TypeSummaryGeneration    = "summary:generation"     // Summary generation task
+	TypeKBClone              = "kb:clone"               // Knowledge base clone task
+	TypeIndexDelete          = "index:delete"           // Index deletion task
+	TypeKBDelete             = "kb:delete"              // Knowledge base deletion task
+	TypeKnowledgeListDelete  = "knowledge:list_delete"  // Batch knowledge deletion task
+	TypeDataTableSummary     = "datatable:summary"      // Data table summary task
+	TypeKnowledgeReindex     = "knowledge:reindex"      // Knowledge re-indexing task
+	TypeKnowledgeExpirySweep = "knowledge:expiry_sweep" // Scheduled knowledge expiry sweep task
+	TypeFAQMining            = "faq:mining"             // FAQ candidate mining task
+	TypeFAQMiningSweep       = "faq:mining_sweep"       // Scheduled FAQ candidate mining of opted-in knowledge bases
+	TypeFAQConflictScan      = "faq:conflict_scan"      // FAQ conflict scan task
 )
 
 // ExtractChunkPayload represents the extract chunk task payload
@@ -46,8 +51,10 @@ type FAQImportPayload struct {
 	EntriesURL  string            `json:"entries_url,omitempty"`  // Stored in object storage for large data volumes, URL stored here
 	EntryCount  int               `json:"entry_count,omitempty"`  // Total number of entries (required when using EntriesURL)
 	Mode        string            `json:"mode"`
-	DryRun      bool              `json:"dry_run"`     // Dry run mode only validates, does not import
-	EnqueuedAt  int64             `json:"enqueued_at"` // Task enqueue timestamp, used to distinguish different submissions with same TaskID
+	DryRun      bool              `json:"dry_run"`            // Dry run mode only validates, does not import
+	AsDraft     bool              `json:"as_draft,omitempty"` // Import entries as drafts
+	UserID      string            `json:"user_id,omitempty"`  // User who started the import, recorded as the entries' last editor
+	EnqueuedAt  int64             `json:"enqueued_at"`        // Task enqueue timestamp, used to distinguish different submissions with same TaskID
 }
 
 // QuestionGenerationPayload represents the question generation task payload
@@ -65,6 +72,15 @@ type SummaryGenerationPayload struct {
 	KnowledgeID     string `json:"knowledge_id"`
 }
 
+// KnowledgeReindexPayload represents the knowledge re-indexing task payload
+type KnowledgeReindexPayload struct {
+	TenantID                 uint64 `json:"tenant_id"`
+	KnowledgeBaseID          string `json:"knowledge_base_id"`
+	KnowledgeID              string `json:"knowledge_id"`
+	EnableQuestionGeneration bool   `json:"enable_question_generation"`
+	QuestionCount            int    `json:"question_count,omitempty"`
+}
+
 // KBClonePayload represents the knowledge base clone task payload
 type KBClonePayload struct {
 	TenantID uint64 `json:"tenant_id"`
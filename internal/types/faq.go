@@ -4,6 +4,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"sort"
 	"strings"
 	"time"
@@ -18,6 +19,40 @@ type FAQChunkMetadata struct {
 	AnswerStrategy    AnswerStrategy `json:"answer_strategy,omitempty"`
 	Version           int            `json:"version,omitempty"`
 	Source            string         `json:"source,omitempty"`
+	// AnswerVariants are answers for specific locales and channels, Answers are the fallback
+	AnswerVariants []FAQAnswerVariant `json:"answer_variants,omitempty"`
+	// ReviewStatus is the review status of the entry, empty means published
+	ReviewStatus FAQReviewStatus `json:"review_status,omitempty"`
+	// Reviewer is the user ID of the reviewer who approves the wording
+	Reviewer string `json:"reviewer,omitempty"`
+	// Submitter is the user ID of who submitted the entry for review
+	Submitter string `json:"submitter,omitempty"`
+	// LastEditor is the user ID of who last created or changed the entry
+	LastEditor string `json:"last_editor,omitempty"`
+}
+
+// IsPublished reports whether the entry is published and may be indexed for retrieval
+func (m *FAQChunkMetadata) IsPublished() bool {
+	return m != nil && m.ReviewStatus.OrPublished() == FAQReviewStatusPublished
+}
+
+// FAQAnswerVariant holds the answers of a FAQ entry for a locale and channel.
+// An empty locale or channel matches any requested locale or channel.
+type FAQAnswerVariant struct {
+	Locale  string   `json:"locale,omitempty"`  // Locale such as ko-KR or en, matched by language when not exact
+	Channel string   `json:"channel,omitempty"` // Channel such as web or ivr
+	Answers []string `json:"answers"`
+}
+
+// FAQAnswerPreference is the locale and channel a FAQ answer is requested for
+type FAQAnswerPreference struct {
+	Locale  string `json:"locale,omitempty"`
+	Channel string `json:"channel,omitempty"`
+}
+
+// IsEmpty reports whether neither a locale nor a channel is requested
+func (p FAQAnswerPreference) IsEmpty() bool {
+	return strings.TrimSpace(p.Locale) == "" && strings.TrimSpace(p.Channel) == ""
 }
 
 // GeneratedQuestion represents a single AI-generated question
@@ -84,11 +119,141 @@ func (m *FAQChunkMetadata) Normalize() {
 	m.SimilarQuestions = normalizeStrings(m.SimilarQuestions)
 	m.NegativeQuestions = normalizeStrings(m.NegativeQuestions)
 	m.Answers = normalizeStrings(m.Answers)
+	m.AnswerVariants = normalizeFAQAnswerVariants(m.AnswerVariants)
 	if m.Version <= 0 {
 		m.Version = 1
 	}
 }
 
+// AnswersFor returns the answers of the variant best matching the preference, and the
+// default answers when no variant matches. See SelectFAQAnswerVariant for the rules.
+func (m *FAQChunkMetadata) AnswersFor(pref FAQAnswerPreference) []string {
+	if m == nil {
+		return nil
+	}
+	if variant := SelectFAQAnswerVariant(m.AnswerVariants, pref); variant != nil {
+		return variant.Answers
+	}
+	return m.Answers
+}
+
+// SelectFAQAnswerVariant returns the variant best matching the preference, or nil when the
+// default answers should be used. The locale ranks first: an exact locale beats the same
+// language (e.g. "en" for "en-US"), which beats a variant for any locale. The channel breaks
+// ties: an exact channel beats a variant for any channel. Variants for another locale or
+// channel never match, and a variant for any locale and channel never beats the defaults.
+func SelectFAQAnswerVariant(variants []FAQAnswerVariant, pref FAQAnswerPreference) *FAQAnswerVariant {
+	locale := NormalizeFAQLocale(pref.Locale)
+	channel := normalizeFAQChannel(pref.Channel)
+	var best *FAQAnswerVariant
+	bestScore := 0
+	for i := range variants {
+		variant := &variants[i]
+		if len(variant.Answers) == 0 {
+			continue
+		}
+		localeScore, ok := faqLocaleScore(NormalizeFAQLocale(variant.Locale), locale)
+		if !ok {
+			continue
+		}
+		channelScore := 0
+		if variantChannel := normalizeFAQChannel(variant.Channel); variantChannel != "" {
+			if variantChannel != channel {
+				continue
+			}
+			channelScore = 1
+		}
+		if score := localeScore*2 + channelScore; score > bestScore {
+			best, bestScore = variant, score
+		}
+	}
+	return best
+}
+
+// faqLocaleScore scores how well a variant locale matches the requested one:
+// 2 for the same locale, 1 for the same language and 0 for a variant for any locale
+func faqLocaleScore(variantLocale, locale string) (int, bool) {
+	switch {
+	case variantLocale == "":
+		return 0, true
+	case variantLocale == locale:
+		return 2, true
+	case locale != "" && faqLanguage(variantLocale) == faqLanguage(locale):
+		return 1, true
+	default:
+		return 0, false
+	}
+}
+
+// faqLanguage returns the language part of a normalized locale
+func faqLanguage(locale string) string {
+	language, _, _ := strings.Cut(locale, "-")
+	return language
+}
+
+// NormalizeFAQLocale normalizes a locale for matching, e.g. "ko_KR" to "ko-kr"
+func NormalizeFAQLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+func normalizeFAQChannel(channel string) string {
+	return strings.ToLower(strings.TrimSpace(channel))
+}
+
+// normalizeFAQAnswerVariants trims the variants and drops the ones without answers
+func normalizeFAQAnswerVariants(variants []FAQAnswerVariant) []FAQAnswerVariant {
+	if len(variants) == 0 {
+		return nil
+	}
+	normalized := make([]FAQAnswerVariant, 0, len(variants))
+	for _, variant := range variants {
+		variant.Locale = strings.TrimSpace(variant.Locale)
+		variant.Channel = normalizeFAQChannel(variant.Channel)
+		variant.Answers = normalizeStrings(variant.Answers)
+		if len(variant.Answers) > 0 {
+			normalized = append(normalized, variant)
+		}
+	}
+	if len(normalized) == 0 {
+		return nil
+	}
+	return normalized
+}
+
+// FormatFAQAnswerVariants formats answer variants for the FAQ CSV, one variant per line as
+// "[locale|channel] answer1##answer2". The channel part is omitted when empty. The CSV import
+// starts a new variant at each line beginning with a marker, so answers may span lines.
+func FormatFAQAnswerVariants(variants []FAQAnswerVariant) string {
+	lines := make([]string, 0, len(variants))
+	for _, variant := range variants {
+		key := variant.Locale
+		if variant.Channel != "" {
+			key += "|" + variant.Channel
+		}
+		lines = append(lines, "["+key+"] "+strings.Join(variant.Answers, "##"))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ValidateFAQAnswerVariants checks that every variant has a locale or channel and that no
+// two variants share both
+func ValidateFAQAnswerVariants(variants []FAQAnswerVariant) error {
+	seen := make(map[string]struct{}, len(variants))
+	for _, variant := range variants {
+		locale := NormalizeFAQLocale(variant.Locale)
+		channel := normalizeFAQChannel(variant.Channel)
+		if locale == "" && channel == "" {
+			return fmt.Errorf("answer variant needs a locale or channel")
+		}
+		key := locale + "|" + channel
+		if _, ok := seen[key]; ok {
+			return fmt.Errorf("duplicate answer variant for locale %q and channel %q", variant.Locale, variant.Channel)
+		}
+		seen[key] = struct{}{}
+	}
+	return nil
+}
+
 // FAQMetadata parses FAQ metadata in Chunk
 func (c *Chunk) FAQMetadata() (*FAQChunkMetadata, error) {
 	if c == nil || len(c.Metadata) == 0 {
@@ -157,6 +322,11 @@ func CalculateFAQContentHash(meta *FAQChunkMetadata) string {
 	builder.WriteString(strings.Join(negativeQuestions, ","))
 	builder.WriteString("|")
 	builder.WriteString(strings.Join(answers, ","))
+	// Variants are only hashed when present, so hashes of entries without them are unchanged
+	if len(normalized.AnswerVariants) > 0 {
+		builder.WriteString("|")
+		builder.WriteString(FormatFAQAnswerVariants(normalized.AnswerVariants))
+	}
 
 	// Calculate SHA256 hash
 	hash := sha256.Sum256([]byte(builder.String()))
@@ -188,12 +358,24 @@ type FAQEntry struct {
 	NegativeQuestions []string       `json:"negative_questions"`
 	Answers           []string       `json:"answers"`
 	AnswerStrategy    AnswerStrategy `json:"answer_strategy"`
-	IndexMode         FAQIndexMode   `json:"index_mode"`
-	UpdatedAt         time.Time      `json:"updated_at"`
-	CreatedAt         time.Time      `json:"created_at"`
-	Score             float64        `json:"score,omitempty"`
-	MatchType         MatchType      `json:"match_type,omitempty"`
-	ChunkType         ChunkType      `json:"chunk_type"`
+	// AnswerVariants are the answers for specific locales and channels
+	AnswerVariants []FAQAnswerVariant `json:"answer_variants,omitempty"`
+	// AnswerLocale and AnswerChannel identify the variant of Answers returned by a search
+	// for a locale or channel, both are empty when the default answers were returned
+	AnswerLocale  string       `json:"answer_locale,omitempty"`
+	AnswerChannel string       `json:"answer_channel,omitempty"`
+	IndexMode     FAQIndexMode `json:"index_mode"`
+	// ReviewStatus is the review status of the entry, only published entries are retrievable
+	ReviewStatus FAQReviewStatus `json:"review_status"`
+	Reviewer     string          `json:"reviewer,omitempty"`
+	// Conflicts are the existing entries with near-identical questions, only set when creating an entry
+	Conflicts       []FAQConflictMatch  `json:"conflicts,omitempty"`
+	MergeSuggestion *FAQMergeSuggestion `json:"merge_suggestion,omitempty"`
+	UpdatedAt       time.Time           `json:"updated_at"`
+	CreatedAt       time.Time           `json:"created_at"`
+	Score           float64             `json:"score,omitempty"`
+	MatchType       MatchType           `json:"match_type,omitempty"`
+	ChunkType       ChunkType           `json:"chunk_type"`
 	// MatchedQuestion is the actual question text that was matched in FAQ search
 	// Could be the standard question or one of the similar questions
 	MatchedQuestion string `json:"matched_question,omitempty"`
@@ -208,10 +390,16 @@ type FAQEntryPayload struct {
 	NegativeQuestions []string        `json:"negative_questions"`
 	Answers           []string        `json:"answers"              binding:"required"`
 	AnswerStrategy    *AnswerStrategy `json:"answer_strategy,omitempty"`
-	TagID             int64           `json:"tag_id"`
-	TagName           string          `json:"tag_name"`
-	IsEnabled         *bool           `json:"is_enabled,omitempty"`
-	IsRecommended     *bool           `json:"is_recommended,omitempty"`
+	// AnswerVariants are answers for specific locales and channels, Answers are the fallback.
+	// Omitting it keeps the variants of an updated entry, an empty list removes them.
+	AnswerVariants []FAQAnswerVariant `json:"answer_variants,omitempty"`
+	TagID          int64              `json:"tag_id"`
+	TagName        string             `json:"tag_name"`
+	IsEnabled      *bool              `json:"is_enabled,omitempty"`
+	IsRecommended  *bool              `json:"is_recommended,omitempty"`
+	// ReviewStatus of a new entry, draft or published (default). Ignored on update and
+	// forced to draft when the knowledge base requires review.
+	ReviewStatus FAQReviewStatus `json:"review_status,omitempty"`
 }
 
 const (
@@ -224,8 +412,10 @@ type FAQBatchUpsertPayload struct {
 	Entries     []FAQEntryPayload `json:"entries"      binding:"required"`
 	Mode        string            `json:"mode"         binding:"oneof=append replace"`
 	KnowledgeID string            `json:"knowledge_id"`
-	TaskID      string            `json:"task_id"` // Optional, auto-generates UUID if not provided
-	DryRun      bool              `json:"dry_run"` // Only validate, do not actually import
+	TaskID      string            `json:"task_id"`  // Optional, auto-generates UUID if not provided
+	DryRun      bool              `json:"dry_run"`  // Only validate, do not actually import
+	AsDraft     bool              `json:"as_draft"` // Import entries as drafts that are not indexed until published
+	Editor      string            `json:"-"`        // User recorded as the last editor of the imported entries
 }
 
 // FAQFailedEntry represents an entry that failed to import/validate
@@ -237,8 +427,10 @@ type FAQFailedEntry struct {
 	SimilarQuestions  []string `json:"similar_questions,omitempty"`  // Similar questions
 	NegativeQuestions []string `json:"negative_questions,omitempty"` // Negative examples
 	Answers           []string `json:"answers,omitempty"`            // Answers
-	AnswerAll         bool     `json:"answer_all,omitempty"`         // Whether to reply to all
-	IsDisabled        bool     `json:"is_disabled,omitempty"`        // Whether disabled
+	// Answers for specific locales and channels
+	AnswerVariants []FAQAnswerVariant `json:"answer_variants,omitempty"`
+	AnswerAll      bool               `json:"answer_all,omitempty"`  // Whether to reply to all
+	IsDisabled     bool               `json:"is_disabled,omitempty"` // Whether disabled
 }
 
 // FAQSuccessEntry represents simple information about a successfully imported entry
@@ -267,6 +459,9 @@ type FAQSearchRequest struct {
 	FirstPriorityTagIDs  []int64 `json:"first_priority_tag_ids"`  // First priority tag ID list, limits match scope, highest priority
 	SecondPriorityTagIDs []int64 `json:"second_priority_tag_ids"` // Second priority tag ID list, limits match scope, lower priority than first
 	OnlyRecommended      bool    `json:"only_recommended"`        // Whether to return only recommended entries
+	// Locale and Channel select the answer variant returned in Answers (optional)
+	Locale  string `json:"locale,omitempty"`
+	Channel string `json:"channel,omitempty"`
 }
 
 // UntaggedTagName is the default tag name for entries without a tag
@@ -329,6 +524,10 @@ type FAQImportProgress struct {
 	CreatedAt         int64               `json:"created_at"`                    // Task creation timestamp
 	UpdatedAt         int64               `json:"updated_at"`                    // Last update timestamp
 	DryRun            bool                `json:"dry_run,omitempty"`             // Whether it's dry run mode
+	// Existing entries that valid entries conflict or overlap with, only checked in dry run mode
+	Conflicts []FAQImportConflict `json:"conflicts,omitempty"`
+	// ConflictsTruncated is set when only the first MaxFAQConflictImportQuestions questions were checked
+	ConflictsTruncated bool `json:"conflicts_truncated,omitempty"`
 
 	// Result fields (populated when Status == "completed")
 	ImportMode     string    `json:"import_mode,omitempty"`     // Import mode: append or replace
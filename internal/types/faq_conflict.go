@@ -0,0 +1,121 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultFAQConflictThreshold is the default vector similarity from which two questions count as near-identical
+	DefaultFAQConflictThreshold = 0.9
+	// MaxFAQConflictImportQuestions caps the questions checked for conflicts by one import dry run
+	MaxFAQConflictImportQuestions = 1000
+	// MaxFAQConflictScanQuestions caps the questions checked by one knowledge base conflict scan
+	MaxFAQConflictScanQuestions = 5000
+)
+
+// FAQConflictType tells whether near-identical FAQ entries disagree
+type FAQConflictType string
+
+const (
+	// FAQConflictTypeConflict means the entries have near-identical questions but different answers
+	FAQConflictTypeConflict FAQConflictType = "conflict"
+	// FAQConflictTypeOverlap means the entries have near-identical questions and the same answers
+	FAQConflictTypeOverlap FAQConflictType = "overlap"
+)
+
+// FAQConflictMatch is an existing FAQ entry with a question near-identical to a question of the checked entry
+type FAQConflictMatch struct {
+	Type FAQConflictType `json:"type"`
+	// Question of the checked entry
+	Question string `json:"question"`
+	// Question of the existing entry it matched, its standard question or one of its similar questions
+	MatchedQuestion string `json:"matched_question"`
+	// Vector similarity of the two questions
+	Score float64 `json:"score"`
+	// ID (seq_id) of the existing entry
+	EntryID          int64    `json:"entry_id"`
+	StandardQuestion string   `json:"standard_question"`
+	Answers          []string `json:"answers"`
+}
+
+// FAQMergeSuggestion proposes keeping one entry and moving the questions of the other into it
+type FAQMergeSuggestion struct {
+	// ID (seq_id) of the entry to keep
+	TargetEntryID int64 `json:"target_entry_id"`
+	// Questions to add to the kept entry as similar questions
+	SimilarQuestions []string `json:"similar_questions"`
+	// ID (seq_id) of the entry to delete once merged, zero for an entry not created yet
+	RemoveEntryID int64 `json:"remove_entry_id,omitempty"`
+}
+
+// FAQImportConflict reports the existing entries an imported entry conflicts or overlaps with
+type FAQImportConflict struct {
+	// Entry index in batch (0-based)
+	Index            int                 `json:"index"`
+	StandardQuestion string              `json:"standard_question"`
+	Matches          []FAQConflictMatch  `json:"matches"`
+	Suggestion       *FAQMergeSuggestion `json:"suggestion,omitempty"`
+}
+
+// FAQConflictGroup reports the entries an existing entry conflicts or overlaps with
+type FAQConflictGroup struct {
+	// ID (seq_id) of the checked entry
+	EntryID          int64               `json:"entry_id"`
+	StandardQuestion string              `json:"standard_question"`
+	Answers          []string            `json:"answers"`
+	Matches          []FAQConflictMatch  `json:"matches"`
+	Suggestion       *FAQMergeSuggestion `json:"suggestion,omitempty"`
+}
+
+// FAQConflictScanStatus is the status of a knowledge base conflict scan
+type FAQConflictScanStatus string
+
+const (
+	// FAQConflictScanStatusProcessing means the scan is running
+	FAQConflictScanStatusProcessing FAQConflictScanStatus = "processing"
+	// FAQConflictScanStatusCompleted means the report is complete
+	FAQConflictScanStatusCompleted FAQConflictScanStatus = "completed"
+	// FAQConflictScanStatusFailed means the scan stopped on an error
+	FAQConflictScanStatusFailed FAQConflictScanStatus = "failed"
+)
+
+// FAQConflictScanRequest configures a knowledge base conflict scan, zero values mean default
+type FAQConflictScanRequest struct {
+	// Vector similarity from which two questions count as near-identical, defaults to 0.9
+	Threshold float64 `json:"threshold"`
+}
+
+// Validate checks that the scan request is well-formed
+func (r *FAQConflictScanRequest) Validate() error {
+	if r.Threshold < 0 || r.Threshold > 1 {
+		return fmt.Errorf("threshold must be between 0 and 1")
+	}
+	return nil
+}
+
+// FAQConflictScanPayload represents the FAQ conflict scan task payload
+type FAQConflictScanPayload struct {
+	TenantID        uint64  `json:"tenant_id"`
+	KnowledgeBaseID string  `json:"knowledge_base_id"`
+	TaskID          string  `json:"task_id"`
+	Threshold       float64 `json:"threshold"`
+}
+
+// FAQConflictReport is the latest conflict report of a FAQ knowledge base, stored in Redis
+type FAQConflictReport struct {
+	TaskID          string                `json:"task_id"`
+	KnowledgeBaseID string                `json:"knowledge_base_id"`
+	Status          FAQConflictScanStatus `json:"status"`
+	Threshold       float64               `json:"threshold"`
+	// Number of entries scanned so far and in total
+	ScannedEntries int `json:"scanned_entries"`
+	TotalEntries   int `json:"total_entries"`
+	// Truncated is set when the scan stopped at MaxFAQConflictScanQuestions
+	Truncated bool               `json:"truncated,omitempty"`
+	Groups    []FAQConflictGroup `json:"groups"`
+	Error     string             `json:"error,omitempty"`
+	StartedAt time.Time          `json:"started_at"`
+	// FinishedAt is set once the scan completed or failed
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
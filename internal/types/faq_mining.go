@@ -0,0 +1,162 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultFAQMiningDays is the default number of days of chat history mined for FAQ candidates
+	DefaultFAQMiningDays = 7
+	// DefaultFAQMiningScoreThreshold is the default reference score below which an answer counts as poorly grounded
+	DefaultFAQMiningScoreThreshold = 0.5
+	// DefaultFAQMiningSimilarityThreshold is the default embedding similarity from which questions are clustered
+	DefaultFAQMiningSimilarityThreshold = 0.85
+	// DefaultFAQMiningMinQuestionCount is the default number of times a question must be asked to become a candidate
+	DefaultFAQMiningMinQuestionCount = 2
+	// DefaultFAQMiningMaxCandidates is the default number of candidates proposed by one mining run
+	DefaultFAQMiningMaxCandidates = 20
+)
+
+// FAQCandidateStatus is the review status of a mined FAQ candidate
+type FAQCandidateStatus string
+
+const (
+	// FAQCandidateStatusPending waits for an editor to review it
+	FAQCandidateStatusPending FAQCandidateStatus = "pending"
+	// FAQCandidateStatusAccepted was added to the FAQ knowledge base
+	FAQCandidateStatusAccepted FAQCandidateStatus = "accepted"
+	// FAQCandidateStatusRejected was dismissed by an editor and is not proposed again
+	FAQCandidateStatusRejected FAQCandidateStatus = "rejected"
+)
+
+// FAQCandidate is a FAQ entry proposed from user questions that got the fallback answer
+// or were answered from poorly matching knowledge
+type FAQCandidate struct {
+	// Unique identifier of the candidate
+	ID string `json:"id"                 gorm:"type:varchar(36);primaryKey"`
+	// Tenant ID
+	TenantID uint64 `json:"tenant_id"`
+	// ID of the FAQ knowledge base the candidate is proposed for
+	KnowledgeBaseID string `json:"knowledge_base_id"  gorm:"type:varchar(36)"`
+	// Standard question, the most asked phrasing of the cluster
+	StandardQuestion string `json:"standard_question"`
+	// Other phrasings of the question asked by users
+	SimilarQuestions StringArray `json:"similar_questions"  gorm:"type:jsonb"`
+	// Answer drafted by the LLM from existing chunks, empty when no chunk covers the question
+	Answer string `json:"answer"`
+	// IDs of the chunks the answer was drafted from
+	SourceChunkIDs StringArray `json:"source_chunk_ids"   gorm:"type:jsonb"`
+	// IDs of the user messages the questions come from
+	MessageIDs StringArray `json:"message_ids"        gorm:"type:jsonb"`
+	// Number of times the questions were asked
+	QuestionCount int `json:"question_count"`
+	// Number of times the questions got the fallback answer
+	FallbackCount int `json:"fallback_count"`
+	// Best reference score of the answers that were not fallbacks
+	MaxScore float64 `json:"max_score"`
+	// When the questions were last asked
+	LastAskedAt time.Time `json:"last_asked_at"`
+	// Review status
+	Status FAQCandidateStatus `json:"status"             gorm:"type:varchar(16)"`
+	// ID (seq_id) of the FAQ entry created when the candidate was accepted
+	FAQEntryID int64 `json:"faq_entry_id"`
+	// When the candidate was accepted or rejected
+	ReviewedAt *time.Time `json:"reviewed_at"`
+	// Creation time of the candidate
+	CreatedAt time.Time `json:"created_at"`
+	// Last update time of the candidate
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName returns the table name of FAQCandidate
+func (FAQCandidate) TableName() string {
+	return "faq_candidates"
+}
+
+// FAQMiningRequest configures a FAQ mining run, zero values mean default
+type FAQMiningRequest struct {
+	// Number of days of chat history to mine, defaults to 7
+	Days int `json:"days"`
+	// Reference score below which an answer counts as poorly grounded, defaults to 0.5
+	ScoreThreshold float64 `json:"score_threshold"`
+	// Embedding similarity from which questions are clustered, defaults to 0.85
+	SimilarityThreshold float64 `json:"similarity_threshold"`
+	// Number of times a question must be asked to become a candidate, defaults to 2
+	MinQuestionCount int `json:"min_question_count"`
+	// Maximum number of candidates proposed, defaults to 20
+	MaxCandidates int `json:"max_candidates"`
+	// Document knowledge bases the answers are drafted from, all of the tenant when empty
+	SourceKnowledgeBaseIDs []string `json:"source_knowledge_base_ids"`
+}
+
+// Validate checks that the mining request is well-formed
+func (r *FAQMiningRequest) Validate() error {
+	if r.Days < 0 || r.Days > 90 {
+		return fmt.Errorf("days must be between 1 and 90")
+	}
+	if r.ScoreThreshold < 0 || r.ScoreThreshold > 1 {
+		return fmt.Errorf("score_threshold must be between 0 and 1")
+	}
+	if r.SimilarityThreshold < 0 || r.SimilarityThreshold > 1 {
+		return fmt.Errorf("similarity_threshold must be between 0 and 1")
+	}
+	if r.MinQuestionCount < 0 {
+		return fmt.Errorf("min_question_count must not be negative")
+	}
+	if r.MaxCandidates < 0 || r.MaxCandidates > 100 {
+		return fmt.Errorf("max_candidates must be between 1 and 100")
+	}
+	return nil
+}
+
+// WithDefaults returns a copy of the request with zero values replaced by defaults
+func (r FAQMiningRequest) WithDefaults() FAQMiningRequest {
+	if r.Days == 0 {
+		r.Days = DefaultFAQMiningDays
+	}
+	if r.ScoreThreshold == 0 {
+		r.ScoreThreshold = DefaultFAQMiningScoreThreshold
+	}
+	if r.SimilarityThreshold == 0 {
+		r.SimilarityThreshold = DefaultFAQMiningSimilarityThreshold
+	}
+	if r.MinQuestionCount == 0 {
+		r.MinQuestionCount = DefaultFAQMiningMinQuestionCount
+	}
+	if r.MaxCandidates == 0 {
+		r.MaxCandidates = DefaultFAQMiningMaxCandidates
+	}
+	return r
+}
+
+// FAQMiningPayload represents the FAQ mining task payload
+type FAQMiningPayload struct {
+	TenantID        uint64           `json:"tenant_id"`
+	KnowledgeBaseID string           `json:"knowledge_base_id"`
+	Request         FAQMiningRequest `json:"request"`
+}
+
+// FAQMiningMessage is a user question with the answer it got, read from the chat history
+type FAQMiningMessage struct {
+	// ID of the user message
+	MessageID string
+	// Question asked by the user
+	Question string
+	// Whether the answer was the fallback response
+	IsFallback bool
+	// Knowledge references of the answer
+	KnowledgeReferences References
+	// When the question was asked
+	CreatedAt time.Time
+}
+
+// FAQCandidateAcceptRequest accepts a candidate into the FAQ knowledge base. Empty fields
+// keep the values of the candidate, so an editor only sends what they changed.
+type FAQCandidateAcceptRequest struct {
+	StandardQuestion string   `json:"standard_question"`
+	SimilarQuestions []string `json:"similar_questions"`
+	Answers          []string `json:"answers"`
+	TagID            int64    `json:"tag_id"`
+	IsEnabled        *bool    `json:"is_enabled,omitempty"`
+}
@@ -0,0 +1,105 @@
+package types
+
+import "time"
+
+// FAQReviewStatus is the review status of a FAQ entry. Only published entries are
+// indexed for retrieval; an empty status means published, as for entries created
+// before the review workflow existed.
+type FAQReviewStatus string
+
+const (
+	// FAQReviewStatusDraft is being written and is not visible to customers
+	FAQReviewStatusDraft FAQReviewStatus = "draft"
+	// FAQReviewStatusPendingReview waits for the assigned reviewer to approve its wording
+	FAQReviewStatusPendingReview FAQReviewStatus = "pending_review"
+	// FAQReviewStatusPublished is indexed and answered to customers
+	FAQReviewStatusPublished FAQReviewStatus = "published"
+	// FAQReviewStatusArchived was withdrawn and is kept for reference only
+	FAQReviewStatusArchived FAQReviewStatus = "archived"
+)
+
+// IsValid reports whether the status is one of the known review statuses
+func (s FAQReviewStatus) IsValid() bool {
+	switch s {
+	case FAQReviewStatusDraft, FAQReviewStatusPendingReview, FAQReviewStatusPublished, FAQReviewStatusArchived:
+		return true
+	}
+	return false
+}
+
+// OrPublished returns the status, or published when it is empty
+func (s FAQReviewStatus) OrPublished() FAQReviewStatus {
+	if s == "" {
+		return FAQReviewStatusPublished
+	}
+	return s
+}
+
+// FAQReviewAction moves a FAQ entry between review statuses
+type FAQReviewAction string
+
+const (
+	// FAQReviewActionSubmit sends a draft to its reviewer
+	FAQReviewActionSubmit FAQReviewAction = "submit"
+	// FAQReviewActionApprove publishes an entry pending review, only allowed to its reviewer
+	FAQReviewActionApprove FAQReviewAction = "approve"
+	// FAQReviewActionReject sends an entry pending review back to draft, only allowed to its reviewer
+	FAQReviewActionReject FAQReviewAction = "reject"
+	// FAQReviewActionPublish publishes a draft directly, not allowed when the knowledge base requires review
+	FAQReviewActionPublish FAQReviewAction = "publish"
+	// FAQReviewActionArchive withdraws an entry from retrieval
+	FAQReviewActionArchive FAQReviewAction = "archive"
+	// FAQReviewActionRestore brings an archived entry back as a draft
+	FAQReviewActionRestore FAQReviewAction = "restore"
+)
+
+// FAQReviewRequest applies a review action to a FAQ entry
+type FAQReviewRequest struct {
+	Action FAQReviewAction `json:"action"      binding:"required"`
+	// Reviewer to assign, only used by submit and required when the entry has none
+	ReviewerID string `json:"reviewer_id"`
+	// Optional comment recorded with the action, such as the reason of a rejection
+	Comment string `json:"comment"`
+}
+
+// FAQReviewerRequest assigns the reviewer of a FAQ entry
+type FAQReviewerRequest struct {
+	// User ID of the reviewer, empty to unassign
+	ReviewerID string `json:"reviewer_id"`
+}
+
+// FAQEntryCommentRequest adds a comment to a FAQ entry
+type FAQEntryCommentRequest struct {
+	Content string `json:"content" binding:"required"`
+}
+
+// FAQEntryComment is a review comment on a FAQ entry
+type FAQEntryComment struct {
+	// Unique identifier of the comment
+	ID string `json:"id"                gorm:"type:varchar(36);primaryKey"`
+	// Tenant ID
+	TenantID uint64 `json:"tenant_id"`
+	// ID of the FAQ knowledge base
+	KnowledgeBaseID string `json:"knowledge_base_id" gorm:"type:varchar(36)"`
+	// ID of the chunk storing the FAQ entry
+	ChunkID string `json:"chunk_id"          gorm:"type:varchar(36)"`
+	// ID (seq_id) of the FAQ entry
+	EntryID int64 `json:"entry_id"`
+	// ID of the user who wrote the comment, empty for API key calls
+	UserID string `json:"user_id"           gorm:"type:varchar(36)"`
+	// Username of the user who wrote the comment
+	Username string `json:"username"`
+	// Review action the comment was recorded with, empty for plain comments
+	Action FAQReviewAction `json:"action"            gorm:"type:varchar(16)"`
+	// Review status of the entry after the action
+	ReviewStatus FAQReviewStatus `json:"review_status"     gorm:"type:varchar(16)"`
+	// Comment text
+	Content string `json:"content"`
+	// Creation time of the comment
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName returns the table name of FAQEntryComment
+func (FAQEntryComment) TableName() string {
+	return "faq_entry_comments"
+}
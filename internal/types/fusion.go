@@ -0,0 +1,123 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// FusionStrategy represents how vector and keyword retrieval results are merged in hybrid search
+type FusionStrategy string
+
+const (
+	// FusionStrategyRRF merges results with Reciprocal Rank Fusion: score = sum(1 / (k + rank))
+	FusionStrategyRRF FusionStrategy = "rrf"
+	// FusionStrategyWeighted merges results with a weighted sum of min-max normalized retriever scores
+	FusionStrategyWeighted FusionStrategy = "weighted"
+	// FusionStrategyVectorFallback uses vector results only, and falls back to keyword retrieval
+	// when vector retrieval returns too few chunks
+	FusionStrategyVectorFallback FusionStrategy = "vector_fallback"
+)
+
+const (
+	// DefaultRRFK is the default k constant of RRF, 60 is a common choice that works well in practice
+	DefaultRRFK = 60
+	// DefaultFusionVectorWeight is the default weight of normalized vector scores in weighted fusion
+	DefaultFusionVectorWeight = 0.7
+	// DefaultFusionKeywordWeight is the default weight of normalized keyword scores in weighted fusion
+	DefaultFusionKeywordWeight = 0.3
+	// DefaultFallbackMinResults is the default minimum number of vector chunks before keyword fallback
+	DefaultFallbackMinResults = 1
+)
+
+// FusionConfig configures the fusion of hybrid search results.
+// It can be set on a knowledge base and overridden per search request.
+type FusionConfig struct {
+	// Strategy is the fusion strategy, defaults to rrf
+	Strategy FusionStrategy `yaml:"strategy"             json:"strategy"`
+	// RRFK is the k constant of RRF, used by the rrf strategy
+	RRFK int `yaml:"rrf_k"                json:"rrf_k,omitempty"`
+	// VectorWeight is the weight of normalized vector scores, used by the weighted strategy
+	VectorWeight float64 `yaml:"vector_weight"        json:"vector_weight,omitempty"`
+	// KeywordWeight is the weight of normalized keyword scores, used by the weighted strategy
+	KeywordWeight float64 `yaml:"keyword_weight"       json:"keyword_weight,omitempty"`
+	// FallbackMinResults is the number of vector chunks below which keyword results are used,
+	// used by the vector_fallback strategy
+	FallbackMinResults int `yaml:"fallback_min_results" json:"fallback_min_results,omitempty"`
+}
+
+// Value implements driver.Valuer
+func (c FusionConfig) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
+
+// Scan implements sql.Scanner
+func (c *FusionConfig) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(b, c)
+}
+
+// Validate checks that the fusion config is well-formed, zero values are allowed and mean default
+func (c *FusionConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	switch c.Strategy {
+	case "", FusionStrategyRRF, FusionStrategyWeighted, FusionStrategyVectorFallback:
+	default:
+		return fmt.Errorf("unsupported fusion strategy %q", c.Strategy)
+	}
+	if c.RRFK < 0 {
+		return fmt.Errorf("rrf_k must not be negative")
+	}
+	if c.VectorWeight < 0 || c.KeywordWeight < 0 {
+		return fmt.Errorf("fusion weights must not be negative")
+	}
+	if c.FallbackMinResults < 0 {
+		return fmt.Errorf("fallback_min_results must not be negative")
+	}
+	return nil
+}
+
+// WithDefaults returns a copy of the config with zero values replaced by defaults.
+// A nil config yields the default rrf strategy.
+func (c *FusionConfig) WithDefaults() FusionConfig {
+	var cfg FusionConfig
+	if c != nil {
+		cfg = *c
+	}
+	if cfg.Strategy == "" {
+		cfg.Strategy = FusionStrategyRRF
+	}
+	if cfg.RRFK == 0 {
+		cfg.RRFK = DefaultRRFK
+	}
+	if cfg.VectorWeight == 0 && cfg.KeywordWeight == 0 {
+		cfg.VectorWeight = DefaultFusionVectorWeight
+		cfg.KeywordWeight = DefaultFusionKeywordWeight
+	}
+	if cfg.FallbackMinResults == 0 {
+		cfg.FallbackMinResults = DefaultFallbackMinResults
+	}
+	return cfg
+}
+
+// ScoreDetails records how each retriever scored a chunk before fusion, for debugging ranking
+type ScoreDetails struct {
+	// FusionStrategy is the strategy used to compute the final score
+	FusionStrategy FusionStrategy `json:"fusion_strategy"`
+	// VectorRank is the 1-indexed rank in vector retrieval results, 0 if not retrieved by vector
+	VectorRank int `json:"vector_rank,omitempty"`
+	// VectorScore is the raw vector similarity score
+	VectorScore float64 `json:"vector_score,omitempty"`
+	// KeywordRank is the 1-indexed rank in keyword retrieval results, 0 if not retrieved by keyword
+	KeywordRank int `json:"keyword_rank,omitempty"`
+	// KeywordScore is the raw keyword relevance score
+	KeywordScore float64 `json:"keyword_score,omitempty"`
+}
@@ -0,0 +1,72 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+// AnalyticsService records how knowledge is used in chats and reports it per knowledge base
+type AnalyticsService interface {
+	// RecordRetrieval counts the hits of the results returned to the chat and logs the query
+	// when their best score is low. Recording runs in the background.
+	RecordRetrieval(ctx context.Context, chatManage *types.ChatManage)
+	// RecordZeroResult logs a chat query whose retrieval returned nothing. Recording runs in the background.
+	RecordZeroResult(ctx context.Context, chatManage *types.ChatManage)
+	// SubmitMessageFeedback rates an assistant message, replacing an earlier rating
+	SubmitMessageFeedback(ctx context.Context, sessionID string, messageID string,
+		req *types.MessageFeedbackRequest) (*types.MessageFeedback, error)
+	// GetKnowledgeBaseAnalytics summarizes the usage of a knowledge base in a date range
+	GetKnowledgeBaseAnalytics(ctx context.Context, kbID string,
+		dateRange types.AnalyticsDateRange) (*types.KnowledgeBaseAnalytics, error)
+	// ListTopHitEntries lists the most returned chunks or FAQ entries of a knowledge base
+	ListTopHitEntries(ctx context.Context, kbID string, dateRange types.AnalyticsDateRange,
+		limit int) ([]*types.AnalyticsEntry, error)
+	// ListNeverHitEntries lists the FAQ entries, or documents of a document knowledge base, never returned
+	ListNeverHitEntries(ctx context.Context, kbID string, dateRange types.AnalyticsDateRange,
+		page *types.Pagination) (*types.PageResult, error)
+	// ListQueryLogs lists the logged chat queries of a knowledge base, newest first, all types when logType is empty
+	ListQueryLogs(ctx context.Context, kbID string, logType types.QueryLogType,
+		dateRange types.AnalyticsDateRange, page *types.Pagination) (*types.PageResult, error)
+	// ListMessageFeedback lists the feedback on answers citing a knowledge base, newest first
+	ListMessageFeedback(ctx context.Context, kbID string, rating types.MessageFeedbackRating,
+		dateRange types.AnalyticsDateRange, page *types.Pagination) (*types.PageResult, error)
+}
+
+// FeedbackCount is the number of up and down ratings of answers citing a chunk
+type FeedbackCount struct {
+	Up   int64
+	Down int64
+}
+
+// AnalyticsRepository stores chunk hit counters, query logs and message feedback
+type AnalyticsRepository interface {
+	// IncrementHitStats adds the hit counts to the daily counters of the chunks
+	IncrementHitStats(ctx context.Context, stats []*types.KnowledgeHitStat) error
+	// CreateQueryLogs stores query logs
+	CreateQueryLogs(ctx context.Context, logs []*types.QueryLog) error
+	// SaveMessageFeedback stores the feedback of a message, replacing an earlier one, with the chunks it cites
+	SaveMessageFeedback(ctx context.Context, feedback *types.MessageFeedback,
+		chunks []*types.MessageFeedbackChunk) error
+	// GetKnowledgeBaseAnalytics counts the hits, logged queries and feedback of a knowledge base
+	GetKnowledgeBaseAnalytics(ctx context.Context, tenantID uint64, kbID string,
+		dateRange types.AnalyticsDateRange) (*types.KnowledgeBaseAnalytics, error)
+	// ListTopHitChunks lists the most hit chunks of a knowledge base with their hit counts
+	ListTopHitChunks(ctx context.Context, tenantID uint64, kbID string,
+		dateRange types.AnalyticsDateRange, limit int) ([]*types.AnalyticsEntry, error)
+	// CountFeedbackByChunk counts the ratings of answers citing each chunk
+	CountFeedbackByChunk(ctx context.Context, tenantID uint64, chunkIDs []string,
+		dateRange types.AnalyticsDateRange) (map[string]FeedbackCount, error)
+	// ListNeverHitFAQEntries lists the FAQ entries of a knowledge base without hits
+	ListNeverHitFAQEntries(ctx context.Context, tenantID uint64, kbID string,
+		dateRange types.AnalyticsDateRange, page *types.Pagination) ([]*types.AnalyticsEntry, int64, error)
+	// ListNeverHitKnowledge lists the documents of a knowledge base none of whose chunks were hit
+	ListNeverHitKnowledge(ctx context.Context, tenantID uint64, kbID string,
+		dateRange types.AnalyticsDateRange, page *types.Pagination) ([]*types.AnalyticsEntry, int64, error)
+	// ListQueryLogs lists the query logs of a knowledge base, newest first
+	ListQueryLogs(ctx context.Context, tenantID uint64, kbID string, logType types.QueryLogType,
+		dateRange types.AnalyticsDateRange, page *types.Pagination) ([]*types.QueryLog, int64, error)
+	// ListMessageFeedback lists the feedback on answers citing chunks of a knowledge base, newest first
+	ListMessageFeedback(ctx context.Context, tenantID uint64, kbID string, rating types.MessageFeedbackRating,
+		dateRange types.AnalyticsDateRange, page *types.Pagination) ([]*types.MessageFeedback, int64, error)
+}
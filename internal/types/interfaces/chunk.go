@@ -27,6 +27,7 @@ type ChunkRepository interface {
 	//   - Document (manual): sorts by chunk_index, keyword searches content only
 	// sortOrder: "asc" for ascending, default is descending
 	// searchField: specifies which field to search in (only applicable for FAQ type)
+	// reviewStatus: filters FAQ chunks by review status, chunks without one count as published
 	ListPagedChunksByKnowledgeID(
 		ctx context.Context,
 		tenantID uint64,
@@ -38,6 +39,7 @@ type ChunkRepository interface {
 		searchField string,
 		sortOrder string,
 		knowledgeType string,
+		reviewStatus string,
 	) ([]*types.Chunk, int64, error)
 	ListChunkByParentID(ctx context.Context, tenantID uint64, parentID string) ([]*types.Chunk, error)
 	// UpdateChunk updates a chunk
@@ -63,7 +65,7 @@ type ChunkRepository interface {
 	// only ID and ContentHash fields for efficiency
 	ListAllFAQChunksByKnowledgeID(ctx context.Context, tenantID uint64, knowledgeID string) ([]*types.Chunk, error)
 	// ListAllFAQChunksWithMetadataByKnowledgeBaseID lists all FAQ chunks for a knowledge base ID
-	// returns ID and Metadata fields for duplicate question checking
+	// returns ID, SeqID and Metadata fields for duplicate question and conflict checking
 	ListAllFAQChunksWithMetadataByKnowledgeBaseID(ctx context.Context, tenantID uint64, kbID string) ([]*types.Chunk, error)
 	// ListAllFAQChunksForExport lists all FAQ chunks for export with full metadata, tag_id, is_enabled, and flags
 	ListAllFAQChunksForExport(ctx context.Context, tenantID uint64, knowledgeID string) ([]*types.Chunk, error)
@@ -78,6 +80,26 @@ type ChunkRepository interface {
 	// FAQChunkDiff compares FAQ chunks between two knowledge bases and returns the differences.
 	// Returns: chunksToAdd (content_hash in src but not in dst), chunksToDelete (content_hash in dst but not in src)
 	FAQChunkDiff(ctx context.Context, srcTenantID uint64, srcKBID string, dstTenantID uint64, dstKBID string) (chunksToAdd []string, chunksToDelete []string, err error)
+	// ShiftChunkIndex shifts the chunk_index of the text and child chunks of a knowledge from fromIndex on by delta,
+	// making room for chunks inserted by a split
+	ShiftChunkIndex(ctx context.Context, tenantID uint64, knowledgeID string, fromIndex int, delta int) error
+}
+
+// ChunkEditRepository stores the history of manual chunk edits
+type ChunkEditRepository interface {
+	// CreateChunkEdit records a manual chunk edit
+	CreateChunkEdit(ctx context.Context, edit *types.ChunkEdit) error
+	// ApplyChunkEdit saves the chunk changes of a manual edit and records the edit in one transaction
+	ApplyChunkEdit(ctx context.Context,
+		tenantID uint64, knowledgeID string, edit *types.ChunkEdit, changes *types.ChunkEditChanges) error
+	// ListChunkEdits lists the edits of a knowledge in the order they were made
+	ListChunkEdits(ctx context.Context, tenantID uint64, knowledgeID string) ([]*types.ChunkEdit, error)
+	// ListChunkEditsByChunkID lists the edits that produced, split or merged a chunk, oldest first
+	ListChunkEditsByChunkID(ctx context.Context, tenantID uint64, chunkID string) ([]*types.ChunkEdit, error)
+	// UpdateChunkEdits saves the chunks and orphaned flags of edits after they were replayed
+	UpdateChunkEdits(ctx context.Context, edits []*types.ChunkEdit) error
+	// DeleteChunkEdits deletes the edit history of the given knowledge
+	DeleteChunkEdits(ctx context.Context, tenantID uint64, knowledgeIDs []string) error
 }
 
 // ChunkService defines the interface for chunk service operations
@@ -114,4 +136,20 @@ type ChunkService interface {
 	// DeleteGeneratedQuestion deletes a single generated question from a chunk by question ID
 	// This updates the chunk metadata and removes the corresponding vector index
 	DeleteGeneratedQuestion(ctx context.Context, chunkID string, questionID string) error
+	// UpdateChunkEnabledStatus enables or disables the index entries of a chunk and its child chunks
+	UpdateChunkEnabledStatus(ctx context.Context, chunk *types.Chunk, isEnabled bool) error
+	// EditChunk replaces the content of a chunk, records the edit and re-embeds the chunk.
+	// The content is embedded before the edit is saved, unchanged content is left as is unless indexing it failed.
+	EditChunk(ctx context.Context, chunk *types.Chunk, content string) error
+	// SplitChunk splits a text chunk in two at a character offset and embeds both parts
+	SplitChunk(ctx context.Context, chunk *types.Chunk, offset int) ([]*types.Chunk, error)
+	// MergeChunks merges a text chunk with an adjacent one and embeds the result
+	MergeChunks(ctx context.Context, chunk *types.Chunk, otherChunkID string) (*types.Chunk, error)
+	// ListChunkEdits lists the edit history of a chunk
+	ListChunkEdits(ctx context.Context, chunkID string) ([]*types.ChunkEdit, error)
+	// DeleteChunkEdits discards the manual edits of the given knowledge, so that re-ingestion keeps the parsed chunks
+	DeleteChunkEdits(ctx context.Context, knowledgeIDs []string) error
+	// ReplayChunkEdits applies the manual edits of a knowledge to its freshly parsed text chunks.
+	// Returns the resulting text chunks and, for each chunk removed by a merge, the chunk it was merged into.
+	ReplayChunkEdits(ctx context.Context, knowledgeID string, chunks []*types.Chunk) ([]*types.Chunk, map[string]string)
 }
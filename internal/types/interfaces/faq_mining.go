@@ -0,0 +1,41 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/Tencent/WeKnora/internal/types"
+	"github.com/hibiken/asynq"
+)
+
+// FAQMiningService proposes FAQ entries from unanswered chat questions and manages their review queue
+type FAQMiningService interface {
+	// StartFAQMining enqueues a mining run proposing candidates for a FAQ knowledge base and returns the task ID
+	StartFAQMining(ctx context.Context, kbID string, req *types.FAQMiningRequest) (string, error)
+	// ProcessFAQMining handles the Asynq FAQ mining task
+	ProcessFAQMining(ctx context.Context, t *asynq.Task) error
+	// ProcessFAQMiningSweep handles the scheduled Asynq task enqueueing a mining run for each opted-in knowledge base
+	ProcessFAQMiningSweep(ctx context.Context, t *asynq.Task) error
+	// ListFAQCandidates lists the candidates of a FAQ knowledge base, optionally by status
+	ListFAQCandidates(ctx context.Context, kbID string, status types.FAQCandidateStatus,
+		page *types.Pagination) (*types.PageResult, error)
+	// AcceptFAQCandidate creates a FAQ entry from a pending candidate
+	AcceptFAQCandidate(ctx context.Context, kbID string, candidateID string,
+		req *types.FAQCandidateAcceptRequest) (*types.FAQEntry, error)
+	// RejectFAQCandidate dismisses a pending candidate
+	RejectFAQCandidate(ctx context.Context, kbID string, candidateID string) error
+}
+
+// FAQCandidateRepository stores the FAQ candidates mined from chat history
+type FAQCandidateRepository interface {
+	// CreateFAQCandidates stores new candidates
+	CreateFAQCandidates(ctx context.Context, candidates []*types.FAQCandidate) error
+	// GetFAQCandidate gets a candidate of a knowledge base
+	GetFAQCandidate(ctx context.Context, tenantID uint64, kbID string, id string) (*types.FAQCandidate, error)
+	// ListFAQCandidates lists the candidates of a knowledge base, newest first, all statuses when status is empty
+	ListFAQCandidates(ctx context.Context, tenantID uint64, kbID string, status types.FAQCandidateStatus,
+		page *types.Pagination) ([]*types.FAQCandidate, int64, error)
+	// ListFAQCandidateQuestions lists the standard and similar questions of every candidate of a knowledge base
+	ListFAQCandidateQuestions(ctx context.Context, tenantID uint64, kbID string) ([]string, error)
+	// UpdateFAQCandidateReview records the review of a candidate
+	UpdateFAQCandidateReview(ctx context.Context, candidate *types.FAQCandidate) error
+}
@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/Tencent/WeKnora/internal/types"
+)
+
+// FAQEntryCommentRepository stores the review comments of FAQ entries
+type FAQEntryCommentRepository interface {
+	// CreateFAQEntryComment stores a comment
+	CreateFAQEntryComment(ctx context.Context, comment *types.FAQEntryComment) error
+	// ListFAQEntryComments lists the comments of a FAQ entry, oldest first
+	ListFAQEntryComments(ctx context.Context, tenantID uint64, chunkID string) ([]*types.FAQEntryComment, error)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"io"
 	"mime/multipart"
+	"time"
 
 	"github.com/Tencent/WeKnora/internal/types"
 	"github.com/hibiken/asynq"
@@ -13,6 +14,7 @@ import (
 type KnowledgeService interface {
 	// CreateKnowledgeFromFile creates knowledge from a file.
 	// tagID is optional - when provided, the file will be assigned to the specified tag/category.
+	// customMetadata is optional - it is indexed with the chunks and can be used in metadata filters.
 	CreateKnowledgeFromFile(
 		ctx context.Context,
 		kbID string,
@@ -21,9 +23,20 @@ type KnowledgeService interface {
 		enableMultimodel *bool,
 		customFileName string,
 		tagID string,
+		customMetadata map[string]any,
 	) (*types.Knowledge, error)
+	// PreviewChunks splits a file with candidate chunking configs without persisting anything.
+	// Without configs the knowledge base config is previewed, at most two configs are compared.
+	PreviewChunks(
+		ctx context.Context,
+		kbID string,
+		file *multipart.FileHeader,
+		configs []types.ChunkingConfig,
+		enableMultimodel bool,
+	) ([]*types.ChunkPreview, error)
 	// CreateKnowledgeFromURL creates knowledge from a URL.
 	// tagID is optional - when provided, the knowledge will be assigned to the specified tag/category.
+	// customMetadata is optional - it is indexed with the chunks and can be used in metadata filters.
 	CreateKnowledgeFromURL(
 		ctx context.Context,
 		kbID string,
@@ -31,6 +44,7 @@ type KnowledgeService interface {
 		enableMultimodel *bool,
 		title string,
 		tagID string,
+		customMetadata map[string]any,
 	) (*types.Knowledge, error)
 	// CreateKnowledgeFromPassage creates knowledge from text passages.
 	CreateKnowledgeFromPassage(ctx context.Context, kbID string, passage []string) (*types.Knowledge, error)
@@ -82,6 +96,7 @@ type KnowledgeService interface {
 	// When tagSeqID is non-zero, results are filtered by tag seq_id on FAQ chunks.
 	// searchField: specifies which field to search in ("standard_question", "similar_questions", "answers", "" for all)
 	// sortOrder: "asc" for time ascending (updated_at ASC), default is time descending (updated_at DESC)
+	// reviewStatus: filters entries by review status, "" for all
 	ListFAQEntries(
 		ctx context.Context,
 		kbID string,
@@ -90,6 +105,7 @@ type KnowledgeService interface {
 		keyword string,
 		searchField string,
 		sortOrder string,
+		reviewStatus types.FAQReviewStatus,
 	) (*types.PageResult, error)
 	// UpsertFAQEntries imports or appends FAQ entries asynchronously.
 	// When DryRun is true, only validates entries without actually importing.
@@ -103,6 +119,14 @@ type KnowledgeService interface {
 	UpdateFAQEntry(ctx context.Context, kbID string, entrySeqID int64, payload *types.FAQEntryPayload) (*types.FAQEntry, error)
 	// AddSimilarQuestions adds similar questions to a FAQ entry.
 	AddSimilarQuestions(ctx context.Context, kbID string, entrySeqID int64, questions []string) (*types.FAQEntry, error)
+	// ReviewFAQEntry moves a FAQ entry through the review workflow; only published entries are indexed.
+	ReviewFAQEntry(ctx context.Context, kbID string, entrySeqID int64, req *types.FAQReviewRequest) (*types.FAQEntry, error)
+	// AssignFAQEntryReviewer assigns the user who approves the wording of a FAQ entry.
+	AssignFAQEntryReviewer(ctx context.Context, kbID string, entrySeqID int64, reviewerID string) (*types.FAQEntry, error)
+	// AddFAQEntryComment adds a review comment to a FAQ entry.
+	AddFAQEntryComment(ctx context.Context, kbID string, entrySeqID int64, content string) (*types.FAQEntryComment, error)
+	// ListFAQEntryComments lists the review comments and actions of a FAQ entry, oldest first.
+	ListFAQEntryComments(ctx context.Context, kbID string, entrySeqID int64) ([]*types.FAQEntryComment, error)
 	// UpdateFAQEntryFieldsBatch updates multiple fields for FAQ entries in batch.
 	// Supports updating is_enabled, is_recommended, tag_id, and other fields in a single call.
 	UpdateFAQEntryFieldsBatch(ctx context.Context, kbID string, req *types.FAQEntryFieldsBatchUpdate) error
@@ -110,6 +134,10 @@ type KnowledgeService interface {
 	DeleteFAQEntries(ctx context.Context, kbID string, entrySeqIDs []int64) error
 	// SearchFAQEntries searches FAQ entries using hybrid search.
 	SearchFAQEntries(ctx context.Context, kbID string, req *types.FAQSearchRequest) ([]*types.FAQEntry, error)
+	// StartFAQConflictScan starts scanning a FAQ knowledge base for conflicting entries, returns the task ID.
+	StartFAQConflictScan(ctx context.Context, kbID string, req *types.FAQConflictScanRequest) (string, error)
+	// GetFAQConflictReport returns the latest conflict report of a FAQ knowledge base.
+	GetFAQConflictReport(ctx context.Context, kbID string) (*types.FAQConflictReport, error)
 	// ExportFAQEntries exports all FAQ entries for a knowledge base as CSV data.
 	ExportFAQEntries(ctx context.Context, kbID string) ([]byte, error)
 	// UpdateKnowledgeTagBatch updates tag for document knowledge items in batch.
@@ -117,16 +145,48 @@ type KnowledgeService interface {
 	// UpdateFAQEntryTagBatch updates tag for FAQ entries in batch.
 	// Key: entry seq_id, Value: tag seq_id (nil to remove tag)
 	UpdateFAQEntryTagBatch(ctx context.Context, kbID string, updates map[int64]*int64) error
+	// ListKnowledgeStages lists the processing stages of a knowledge with their status and timings.
+	ListKnowledgeStages(ctx context.Context, knowledgeID string) ([]*types.KnowledgeStage, error)
+	// RetryKnowledgeStage re-runs a failed processing stage of a knowledge.
+	RetryKnowledgeStage(ctx context.Context, knowledgeID string, stage types.ProcessingStage) error
+	// ListFailedKnowledge lists the knowledge of a knowledge base with failed processing stages.
+	ListFailedKnowledge(ctx context.Context, kbID string) ([]*types.FailedKnowledge, error)
+	// RetryFailedKnowledge retries the failed processing stages of the knowledge in a knowledge base.
+	RetryFailedKnowledge(
+		ctx context.Context,
+		kbID string,
+		req *types.KnowledgeRetryRequest,
+	) (*types.KnowledgeRetryResult, error)
+	// UpdateKnowledgeValidity sets the validity period and review date of a knowledge.
+	UpdateKnowledgeValidity(
+		ctx context.Context,
+		knowledgeID string,
+		req *types.KnowledgeValidityRequest,
+	) (*types.Knowledge, error)
+	// ListExpiringKnowledge lists the knowledge of a knowledge base that expires or is due for review within days.
+	ListExpiringKnowledge(ctx context.Context, kbID string, days int) ([]*types.Knowledge, error)
+	// ProcessKnowledgeExpirySweep handles the scheduled Asynq knowledge expiry sweep
+	ProcessKnowledgeExpirySweep(ctx context.Context, t *asynq.Task) error
+	// GetDuplicateReport groups the knowledge of a knowledge base into clusters of near-duplicates.
+	GetDuplicateReport(ctx context.Context, kbID string, threshold float64) (*types.DuplicateReport, error)
+	// DisableDuplicateKnowledge disables redundant near-duplicate copies in a knowledge base.
+	DisableDuplicateKnowledge(ctx context.Context, kbID string, req *types.DuplicateDisableRequest) ([]string, error)
+	// UpdateKnowledgeEnabled enables or disables a knowledge in retrieval.
+	UpdateKnowledgeEnabled(ctx context.Context, knowledgeID string, enabled bool) (*types.Knowledge, error)
 	// GetRepository gets the knowledge repository
 	GetRepository() KnowledgeRepository
 	// ProcessDocument handles Asynq document processing tasks
 	ProcessDocument(ctx context.Context, t *asynq.Task) error
 	// ProcessFAQImport handles Asynq FAQ import tasks
 	ProcessFAQImport(ctx context.Context, t *asynq.Task) error
+	// ProcessFAQConflictScan handles Asynq FAQ conflict scan tasks
+	ProcessFAQConflictScan(ctx context.Context, t *asynq.Task) error
 	// ProcessQuestionGeneration handles Asynq question generation tasks
 	ProcessQuestionGeneration(ctx context.Context, t *asynq.Task) error
 	// ProcessSummaryGeneration handles Asynq summary generation tasks
 	ProcessSummaryGeneration(ctx context.Context, t *asynq.Task) error
+	// ProcessKnowledgeReindex handles Asynq knowledge re-indexing tasks
+	ProcessKnowledgeReindex(ctx context.Context, t *asynq.Task) error
 	// ProcessKBClone handles Asynq knowledge base clone tasks
 	ProcessKBClone(ctx context.Context, t *asynq.Task) error
 	// ProcessKnowledgeListDelete handles Asynq knowledge list delete tasks
@@ -184,4 +244,28 @@ type KnowledgeRepository interface {
 	SearchKnowledge(ctx context.Context, tenantID uint64, keyword string, offset, limit int, fileTypes []string) ([]*types.Knowledge, bool, error)
 	// ListIDsByTagID returns all knowledge IDs that have the specified tag ID.
 	ListIDsByTagID(ctx context.Context, tenantID uint64, kbID, tagID string) ([]string, error)
+	// ListKnowledgeToExpire lists enabled knowledge of all tenants whose validity period does not include now.
+	ListKnowledgeToExpire(ctx context.Context, now time.Time) ([]*types.Knowledge, error)
+	// ListKnowledgeToRestore lists expired knowledge of all tenants whose validity period includes now.
+	ListKnowledgeToRestore(ctx context.Context, now time.Time) ([]*types.Knowledge, error)
+	// ListExpiringKnowledge lists knowledge that expires or is due for review before the given time.
+	ListExpiringKnowledge(ctx context.Context, tenantID uint64, kbID string, before time.Time) ([]*types.Knowledge, error)
+}
+
+// KnowledgeStageRepository stores the processing stages of knowledge.
+type KnowledgeStageRepository interface {
+	// StartStage marks a stage running and counts an attempt, creating its record if needed.
+	StartStage(ctx context.Context, stage *types.KnowledgeStage) error
+	// FinishStage records the outcome of the running attempt of a stage.
+	FinishStage(ctx context.Context, knowledgeID string, stage types.ProcessingStage, status string, errorMessage string) error
+	// MarkStagePending records a stage queued to run, without counting an attempt.
+	MarkStagePending(ctx context.Context, stage *types.KnowledgeStage) error
+	// CompleteStageItem counts a finished work item of a running stage, completing the stage with its last item.
+	CompleteStageItem(ctx context.Context, knowledgeID string, stage types.ProcessingStage) error
+	// ListStages lists the stages of a knowledge.
+	ListStages(ctx context.Context, tenantID uint64, knowledgeID string) ([]*types.KnowledgeStage, error)
+	// ListFailedStages lists the failed stages of the knowledge in a knowledge base.
+	ListFailedStages(ctx context.Context, tenantID uint64, kbID string) ([]*types.KnowledgeStage, error)
+	// DeleteStages deletes the stages of the given knowledge.
+	DeleteStages(ctx context.Context, tenantID uint64, knowledgeIDs []string) error
 }
@@ -38,4 +38,7 @@ type MessageRepository interface {
 	MessageService
 	// GetFirstMessageOfUser gets the first message of a user
 	GetFirstMessageOfUser(ctx context.Context, sessionID string) (*types.Message, error)
+	// ListMessagesForFAQMining lists the questions of a tenant asked since a time with the answers they got, newest first
+	ListMessagesForFAQMining(ctx context.Context, tenantID uint64, since time.Time, limit int,
+	) ([]*types.FAQMiningMessage, error)
 }
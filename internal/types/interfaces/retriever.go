@@ -60,6 +60,10 @@ type RetrieveEngineRepository interface {
 	// chunkTagMap: map of chunk ID to tag ID (empty string means no tag)
 	BatchUpdateChunkTagID(ctx context.Context, chunkTagMap map[string]string) error
 
+	// BatchUpdateKnowledgeMetadata replaces the custom metadata of all indices of the given knowledge in batch
+	// knowledgeMetadataMap: map of knowledge ID to custom metadata (nil or empty means no metadata)
+	BatchUpdateKnowledgeMetadata(ctx context.Context, knowledgeMetadataMap map[string]map[string]any) error
+
 	// RetrieveEngine retrieves the engine
 	RetrieveEngine
 }
@@ -127,6 +131,10 @@ type RetrieveEngineService interface {
 	// chunkTagMap: map of chunk ID to tag ID (empty string means no tag)
 	BatchUpdateChunkTagID(ctx context.Context, chunkTagMap map[string]string) error
 
+	// BatchUpdateKnowledgeMetadata replaces the custom metadata of all indices of the given knowledge in batch
+	// knowledgeMetadataMap: map of knowledge ID to custom metadata (nil or empty means no metadata)
+	BatchUpdateKnowledgeMetadata(ctx context.Context, knowledgeMetadataMap map[string]map[string]any) error
+
 	// RetrieveEngine retrieves the engine
 	RetrieveEngine
 }
@@ -33,11 +33,13 @@ type SessionService interface {
 	// knowledgeIDs: list of specific knowledge (file) IDs to search
 	// summaryModelID: optional summary model ID override (if empty, uses session/KB default)
 	// webSearchEnabled: whether to enable web search to supplement knowledge base results
+	// answerPreference: locale and channel selecting the FAQ answer variants
 	// customAgent: optional custom agent for config override (multiTurnEnabled, historyTurns)
 	// Events are emitted through eventBus (references, answer chunks, completion)
 	KnowledgeQA(ctx context.Context,
 		session *types.Session, query string, knowledgeBaseIDs []string, knowledgeIDs []string,
-		assistantMessageID string, summaryModelID string, webSearchEnabled bool, eventBus *event.EventBus,
+		assistantMessageID string, summaryModelID string, webSearchEnabled bool,
+		answerPreference types.FAQAnswerPreference, eventBus *event.EventBus,
 		customAgent *types.CustomAgent,
 	) error
 	// KnowledgeQAByEvent performs knowledge-based question answering by event
@@ -45,7 +47,9 @@ type SessionService interface {
 	// SearchKnowledge performs knowledge-based search, without summarization
 	// knowledgeBaseIDs: list of knowledge base IDs to search (supports multi-KB)
 	// knowledgeIDs: list of specific knowledge (file) IDs to search
-	SearchKnowledge(ctx context.Context, knowledgeBaseIDs []string, knowledgeIDs []string, query string) ([]*types.SearchResult, error)
+	SearchKnowledge(ctx context.Context, knowledgeBaseIDs []string, knowledgeIDs []string, query string,
+		metadataFilters types.MetadataFilters,
+	) ([]*types.SearchResult, error)
 	// AgentQA performs agent-based question answering with conversation history and streaming support
 	// eventBus is optional - if nil, uses service's default EventBus
 	// customAgent is optional - if provided, uses custom agent configuration instead of tenant defaults
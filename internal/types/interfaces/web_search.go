@@ -26,3 +26,11 @@ type WebSearchService interface {
 		seenURLs map[string]bool, knowledgeIDs []string,
 	) (compressed []*types.WebSearchResult, kbID string, newSeen map[string]bool, newIDs []string, err error)
 }
+
+// WebKnowledgeService saves web search findings of chat sessions as permanent knowledge
+type WebKnowledgeService interface {
+	// SaveWebReferences fetches the selected web references of a message and saves them into a knowledge base
+	SaveWebReferences(ctx context.Context, sessionID string, messageID string,
+		req *types.SaveWebReferencesRequest,
+	) ([]*types.SavedWebReference, error)
+}
@@ -44,6 +44,17 @@ const (
 	SummaryStatusFailed = "failed"
 )
 
+// Knowledge enable status constants
+const (
+	// KnowledgeEnableStatusEnabled indicates the knowledge is available in retrieval
+	KnowledgeEnableStatusEnabled = "enabled"
+	// KnowledgeEnableStatusDisabled indicates the knowledge is not available in retrieval
+	KnowledgeEnableStatusDisabled = "disabled"
+	// KnowledgeEnableStatusExpired indicates the knowledge is outside its validity period
+	// and disabled in retrieval until it is valid again
+	KnowledgeEnableStatusExpired = "expired"
+)
+
 // ManualKnowledgeFormat represents the format of the manual knowledge
 const (
 	ManualKnowledgeFormatMarkdown = "markdown"
@@ -93,6 +104,8 @@ type Knowledge struct {
 	StorageSize int64 `json:"storage_size"`
 	// Metadata of the knowledge
 	Metadata JSON `json:"metadata"           gorm:"type:json"`
+	// Custom metadata of the knowledge, propagated to the index for metadata filtering
+	CustomMetadata JSON `json:"custom_metadata"    gorm:"type:json"`
 	// Last FAQ import result (for FAQ type knowledge only)
 	LastFAQImportResult JSON `json:"last_faq_import_result" gorm:"type:json"`
 	// Creation time of the knowledge
@@ -101,6 +114,18 @@ type Knowledge struct {
 	UpdatedAt time.Time `json:"updated_at"`
 	// Processed time of the knowledge
 	ProcessedAt *time.Time `json:"processed_at"`
+	// Time from which the knowledge is valid, it is disabled in retrieval before
+	ValidFrom *time.Time `json:"valid_from"`
+	// Time until which the knowledge is valid, it is disabled in retrieval after
+	ValidUntil *time.Time `json:"valid_until"`
+	// Time by which the knowledge should be reviewed
+	ReviewBy *time.Time `json:"review_by"`
+	// MinHash signature of the text of the knowledge, used for near-duplicate detection
+	ContentSignature MinHashSignature `json:"-"                  gorm:"type:jsonb"`
+	// ID of the most similar existing knowledge when the knowledge is a near-duplicate
+	DuplicateOf string `json:"duplicate_of"       gorm:"type:varchar(36)"`
+	// Estimated similarity to the knowledge it duplicates
+	DuplicateSimilarity float64 `json:"duplicate_similarity"`
 	// Error message of the knowledge
 	ErrorMessage string `json:"error_message"`
 	// Deletion time of the knowledge
@@ -109,6 +134,14 @@ type Knowledge struct {
 	KnowledgeBaseName string `json:"knowledge_base_name" gorm:"-"`
 }
 
+// IsValidAt reports whether t is within the validity period of the knowledge
+func (k *Knowledge) IsValidAt(t time.Time) bool {
+	if k.ValidFrom != nil && t.Before(*k.ValidFrom) {
+		return false
+	}
+	return k.ValidUntil == nil || t.Before(*k.ValidUntil)
+}
+
 // GetMetadata returns the metadata as a map[string]string.
 func (k *Knowledge) GetMetadata() map[string]string {
 	metadata := make(map[string]string)
@@ -122,6 +155,18 @@ func (k *Knowledge) GetMetadata() map[string]string {
 	return metadata
 }
 
+// GetCustomMetadata returns the custom metadata as a map, or nil if it is empty or invalid.
+func (k *Knowledge) GetCustomMetadata() map[string]any {
+	if len(k.CustomMetadata) == 0 {
+		return nil
+	}
+	metadata, err := k.CustomMetadata.Map()
+	if err != nil || len(metadata) == 0 {
+		return nil
+	}
+	return metadata
+}
+
 // BeforeCreate hook generates a UUID for new Knowledge entities before they are created.
 func (k *Knowledge) BeforeCreate(tx *gorm.DB) (err error) {
 	if k.ID == "" {
@@ -145,6 +190,10 @@ type ManualKnowledgePayload struct {
 	Content string `json:"content"`
 	Status  string `json:"status"`
 	TagID   string `json:"tag_id"`
+	// CustomMetadata is only applied on creation, use UpdateKnowledge to change it afterwards
+	CustomMetadata map[string]any `json:"custom_metadata,omitempty"`
+	// Source overrides the recorded source on creation, e.g. the URL of a saved web page
+	Source string `json:"-"`
 }
 
 // NewManualKnowledgeMetadata creates a new ManualKnowledgeMetadata instance.
@@ -282,3 +331,30 @@ type KnowledgeCheckParams struct {
 	// Knowledge type
 	Type string
 }
+
+// ChunkPreviewItem is a chunk produced by an ingestion dry run
+type ChunkPreviewItem struct {
+	Seq        int         `json:"seq"`
+	Content    string      `json:"content"`
+	StartAt    int         `json:"start_at"`
+	EndAt      int         `json:"end_at"`
+	TokenCount int         `json:"token_count"`
+	Images     []ImageInfo `json:"images,omitempty"`
+	// Children are indexed in place of the chunk when parent-child chunking is enabled
+	Children []*ChunkPreviewItem `json:"children,omitempty"`
+}
+
+// ChunkPreview is the result of splitting a document with one chunking config, nothing is persisted
+type ChunkPreview struct {
+	ChunkingConfig ChunkingConfig `json:"chunking_config"`
+	// Parser is "native" when the document is split in Go, "docreader" otherwise
+	Parser      string              `json:"parser"`
+	Chunks      []*ChunkPreviewItem `json:"chunks"`
+	ChunkCount  int                 `json:"chunk_count"`
+	ChildCount  int                 `json:"child_count"`
+	ImageCount  int                 `json:"image_count"`
+	TotalTokens int                 `json:"total_tokens"`
+	MinTokens   int                 `json:"min_tokens"`
+	MaxTokens   int                 `json:"max_tokens"`
+	AvgTokens   int                 `json:"avg_tokens"`
+}
@@ -0,0 +1,164 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// DefaultDuplicateThreshold is the default similarity above which documents are near-duplicates
+const DefaultDuplicateThreshold = 0.8
+
+// MinHashSignature is a MinHash signature of the text of a document. The share of equal
+// positions of two signatures estimates the Jaccard similarity of the texts.
+type MinHashSignature []uint32
+
+// Similarity estimates the Jaccard similarity of the texts of two signatures
+func (s MinHashSignature) Similarity(other MinHashSignature) float64 {
+	if len(s) == 0 || len(s) != len(other) {
+		return 0
+	}
+	equal := 0
+	for i := range s {
+		if s[i] == other[i] {
+			equal++
+		}
+	}
+	return float64(equal) / float64(len(s))
+}
+
+// Value implements driver.Valuer
+func (s MinHashSignature) Value() (driver.Value, error) {
+	if s == nil {
+		return nil, nil
+	}
+	return json.Marshal([]uint32(s))
+}
+
+// Scan implements sql.Scanner
+func (s *MinHashSignature) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(b, (*[]uint32)(s))
+}
+
+// DuplicateDetectionMode selects what happens when a new document is a near-duplicate of an existing one
+type DuplicateDetectionMode string
+
+const (
+	// DuplicateDetectionOff skips near-duplicate detection at ingest
+	DuplicateDetectionOff DuplicateDetectionMode = "off"
+	// DuplicateDetectionWarn records the most similar document on the new one (default)
+	DuplicateDetectionWarn DuplicateDetectionMode = "warn"
+	// DuplicateDetectionBlock fails the processing of the new document
+	DuplicateDetectionBlock DuplicateDetectionMode = "block"
+)
+
+// DuplicateDetectionConfig configures near-duplicate detection of documents at ingest
+type DuplicateDetectionConfig struct {
+	// Mode is what happens to near-duplicates, defaults to warn
+	Mode DuplicateDetectionMode `yaml:"mode"      json:"mode"`
+	// Threshold is the similarity from which documents are near-duplicates, defaults to 0.8
+	Threshold float64 `yaml:"threshold" json:"threshold,omitempty"`
+}
+
+// Value implements driver.Valuer
+func (c DuplicateDetectionConfig) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
+
+// Scan implements sql.Scanner
+func (c *DuplicateDetectionConfig) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(b, c)
+}
+
+// Validate checks that the duplicate detection config is well-formed, zero values are allowed and mean default
+func (c *DuplicateDetectionConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	switch c.Mode {
+	case "", DuplicateDetectionOff, DuplicateDetectionWarn, DuplicateDetectionBlock:
+	default:
+		return fmt.Errorf("unsupported duplicate detection mode %q", c.Mode)
+	}
+	if c.Threshold < 0 || c.Threshold > 1 {
+		return fmt.Errorf("duplicate threshold must be between 0 and 1")
+	}
+	return nil
+}
+
+// WithDefaults returns a copy of the config with zero values replaced by defaults.
+// A nil config yields the default warn mode.
+func (c *DuplicateDetectionConfig) WithDefaults() DuplicateDetectionConfig {
+	var cfg DuplicateDetectionConfig
+	if c != nil {
+		cfg = *c
+	}
+	if cfg.Mode == "" {
+		cfg.Mode = DuplicateDetectionWarn
+	}
+	if cfg.Threshold == 0 {
+		cfg.Threshold = DefaultDuplicateThreshold
+	}
+	return cfg
+}
+
+// DuplicateKnowledge is a document of a duplicate cluster
+type DuplicateKnowledge struct {
+	// Knowledge ID
+	ID string `json:"id"`
+	// Title of the knowledge
+	Title string `json:"title"`
+	// File name of the knowledge
+	FileName string `json:"file_name"`
+	// Enable status of the knowledge
+	EnableStatus string `json:"enable_status"`
+	// Creation time of the knowledge
+	CreatedAt time.Time `json:"created_at"`
+	// Estimated similarity to the kept document of the cluster, 1 for the kept document
+	Similarity float64 `json:"similarity"`
+}
+
+// DuplicateCluster is a group of documents that are near-duplicates of each other
+type DuplicateCluster struct {
+	// ID of the document to keep, the most recently created one
+	KeepID string `json:"keep_id"`
+	// Documents of the cluster, the kept one first
+	Knowledge []*DuplicateKnowledge `json:"knowledge"`
+}
+
+// DuplicateReport lists the near-duplicate documents of a knowledge base
+type DuplicateReport struct {
+	// Similarity from which documents are near-duplicates
+	Threshold float64 `json:"threshold"`
+	// Clusters of near-duplicate documents, largest first
+	Clusters []*DuplicateCluster `json:"clusters"`
+}
+
+// DuplicateDisableRequest selects the redundant copies to disable in a knowledge base
+type DuplicateDisableRequest struct {
+	// Similarity threshold of the clusters, the knowledge base threshold when 0
+	Threshold float64 `json:"threshold"`
+	// Knowledge to disable, every document but the kept one of each cluster when empty
+	KnowledgeIDs []string `json:"knowledge_ids"`
+}
+
+// KnowledgeEnableRequest enables or disables a knowledge in retrieval
+type KnowledgeEnableRequest struct {
+	// Whether the knowledge is enabled
+	Enabled bool `json:"enabled"`
+}
@@ -0,0 +1,95 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// DefaultExpiryDecayMinWeight is the default weight of search results of knowledge at expiry
+const DefaultExpiryDecayMinWeight = 0.5
+
+// KnowledgeValidityRequest sets the validity period and review date of a knowledge.
+// A null or missing field clears it.
+type KnowledgeValidityRequest struct {
+	// Time from which the knowledge is valid
+	ValidFrom *time.Time `json:"valid_from"`
+	// Time until which the knowledge is valid
+	ValidUntil *time.Time `json:"valid_until"`
+	// Time by which the knowledge should be reviewed
+	ReviewBy *time.Time `json:"review_by"`
+}
+
+// Validate checks that the validity period is not empty
+func (r *KnowledgeValidityRequest) Validate() error {
+	if r.ValidFrom != nil && r.ValidUntil != nil && !r.ValidFrom.Before(*r.ValidUntil) {
+		return fmt.Errorf("valid_from must be before valid_until")
+	}
+	return nil
+}
+
+// ExpiryDecayConfig down-weights search results of knowledge close to the end of its validity period.
+// The weight decreases linearly from 1 at the start of the window to MinWeight at expiry.
+type ExpiryDecayConfig struct {
+	// WindowDays is the number of days before expiry over which results are down-weighted, 0 disables it
+	WindowDays int `yaml:"window_days" json:"window_days"`
+	// MinWeight is the weight of results at expiry, defaults to 0.5
+	MinWeight float64 `yaml:"min_weight"  json:"min_weight,omitempty"`
+}
+
+// Value implements driver.Valuer
+func (c ExpiryDecayConfig) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
+
+// Scan implements sql.Scanner
+func (c *ExpiryDecayConfig) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(b, c)
+}
+
+// Validate checks that the expiry decay config is well-formed, zero values are allowed and mean default
+func (c *ExpiryDecayConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if c.WindowDays < 0 {
+		return fmt.Errorf("window_days must not be negative")
+	}
+	if c.MinWeight < 0 || c.MinWeight > 1 {
+		return fmt.Errorf("min_weight must be between 0 and 1")
+	}
+	return nil
+}
+
+// Enabled reports whether results are down-weighted
+func (c *ExpiryDecayConfig) Enabled() bool {
+	return c != nil && c.WindowDays > 0
+}
+
+// Weight returns the factor applied to the score of a result of knowledge valid until validUntil
+func (c *ExpiryDecayConfig) Weight(validUntil *time.Time, now time.Time) float64 {
+	if !c.Enabled() || validUntil == nil {
+		return 1
+	}
+	minWeight := c.MinWeight
+	if minWeight == 0 {
+		minWeight = DefaultExpiryDecayMinWeight
+	}
+	window := time.Duration(c.WindowDays) * 24 * time.Hour
+	remaining := validUntil.Sub(now)
+	switch {
+	case remaining >= window:
+		return 1
+	case remaining <= 0:
+		return minWeight
+	}
+	return minWeight + (1-minWeight)*float64(remaining)/float64(window)
+}
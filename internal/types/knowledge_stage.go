@@ -0,0 +1,107 @@
+package types
+
+import "time"
+
+// ProcessingStage is a step of the knowledge processing pipeline
+type ProcessingStage string
+
+const (
+	// ProcessingStageParse reads the document and splits it into chunks
+	ProcessingStageParse ProcessingStage = "parse"
+	// ProcessingStageIndex stores the chunks and embeds them into the retrieval index
+	ProcessingStageIndex ProcessingStage = "index"
+	// ProcessingStageQuestionGeneration generates and indexes questions for the chunks
+	ProcessingStageQuestionGeneration ProcessingStage = "question_generation"
+	// ProcessingStageSummaryGeneration summarizes the document
+	ProcessingStageSummaryGeneration ProcessingStage = "summary_generation"
+	// ProcessingStageGraphExtraction extracts the knowledge graph from the chunks
+	ProcessingStageGraphExtraction ProcessingStage = "graph_extraction"
+)
+
+// ProcessingStages lists the pipeline stages in execution order
+var ProcessingStages = []ProcessingStage{
+	ProcessingStageParse,
+	ProcessingStageIndex,
+	ProcessingStageQuestionGeneration,
+	ProcessingStageSummaryGeneration,
+	ProcessingStageGraphExtraction,
+}
+
+// IsValid reports whether the stage is a known pipeline stage
+func (s ProcessingStage) IsValid() bool {
+	for _, stage := range ProcessingStages {
+		if s == stage {
+			return true
+		}
+	}
+	return false
+}
+
+// Processing stage statuses
+const (
+	StageStatusPending   = "pending"
+	StageStatusRunning   = "running"
+	StageStatusCompleted = "completed"
+	StageStatusFailed    = "failed"
+)
+
+// KnowledgeStage records the latest run of one processing stage of a knowledge
+type KnowledgeStage struct {
+	// Unique identifier of the stage record
+	ID string `json:"id"                gorm:"type:varchar(36);primaryKey"`
+	// Tenant ID
+	TenantID uint64 `json:"tenant_id"`
+	// Knowledge base ID
+	KnowledgeBaseID string `json:"knowledge_base_id" gorm:"type:varchar(36)"`
+	// Knowledge ID
+	KnowledgeID string `json:"knowledge_id"      gorm:"type:varchar(36)"`
+	// Stage name
+	Stage ProcessingStage `json:"stage"             gorm:"type:varchar(32)"`
+	// Status: pending, running, completed or failed
+	Status string `json:"status"            gorm:"type:varchar(32)"`
+	// Number of times the stage has been started
+	Attempts int `json:"attempts"`
+	// Error of the last failed attempt
+	ErrorMessage string `json:"error_message"`
+	// Work items of stages fanned out into several tasks, such as graph extraction per chunk
+	TotalItems int `json:"total_items"`
+	// Work items finished successfully
+	DoneItems int `json:"done_items"`
+	// Start time of the last attempt
+	StartedAt *time.Time `json:"started_at"`
+	// Finish time of the last attempt
+	FinishedAt *time.Time `json:"finished_at"`
+	// Duration of the last attempt in milliseconds
+	DurationMs int64 `json:"duration_ms"`
+	// Creation time
+	CreatedAt time.Time `json:"created_at"`
+	// Last updated time
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName returns the table name of KnowledgeStage
+func (KnowledgeStage) TableName() string {
+	return "knowledge_processing_stages"
+}
+
+// FailedKnowledge is a knowledge with at least one failed processing stage
+type FailedKnowledge struct {
+	Knowledge *Knowledge        `json:"knowledge"`
+	Stages    []*KnowledgeStage `json:"stages"`
+}
+
+// KnowledgeRetryRequest selects the failed stages to retry in a knowledge base
+type KnowledgeRetryRequest struct {
+	// Knowledge to retry, all knowledge with failed stages when empty
+	KnowledgeIDs []string `json:"knowledge_ids"`
+	// Stage to retry, every failed stage when empty
+	Stage ProcessingStage `json:"stage"`
+}
+
+// KnowledgeRetryResult reports a bulk retry
+type KnowledgeRetryResult struct {
+	// Number of stages retried
+	Retried int `json:"retried"`
+	// Errors by knowledge ID of the stages that could not be retried
+	Errors map[string]string `json:"errors,omitempty"`
+}
@@ -3,6 +3,7 @@ package types
 import (
 	"database/sql/driver"
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"gorm.io/gorm"
@@ -67,6 +68,14 @@ type KnowledgeBase struct {
 	FAQConfig *FAQConfig `yaml:"faq_config"              json:"faq_config"              gorm:"column:faq_config;type:json"`
 	// QuestionGenerationConfig stores question generation configuration for document knowledge bases
 	QuestionGenerationConfig *QuestionGenerationConfig `yaml:"question_generation_config" json:"question_generation_config" gorm:"column:question_generation_config;type:json"`
+	// FusionConfig stores how hybrid search merges vector and keyword results
+	FusionConfig *FusionConfig `yaml:"fusion_config"           json:"fusion_config"           gorm:"column:fusion_config;type:json"`
+	// ExpiryDecayConfig stores how search results of knowledge close to expiry are down-weighted
+	ExpiryDecayConfig *ExpiryDecayConfig `yaml:"expiry_decay_config" json:"expiry_decay_config" gorm:"column:expiry_decay_config;type:json"`
+	// DuplicateDetectionConfig stores how near-duplicate documents are handled at ingest
+	DuplicateDetectionConfig *DuplicateDetectionConfig `yaml:"duplicate_detection_config" json:"duplicate_detection_config" gorm:"column:duplicate_detection_config;type:json"`
+	// MultilingualConfig stores the languages of the documents and whether queries are translated into them
+	MultilingualConfig *MultilingualConfig `yaml:"multilingual_config" json:"multilingual_config" gorm:"column:multilingual_config;type:json"`
 	// Creation time of the knowledge base
 	CreatedAt time.Time `yaml:"created_at"              json:"created_at"`
 	// Last updated time of the knowledge base
@@ -91,8 +100,30 @@ type KnowledgeBaseConfig struct {
 	ImageProcessingConfig ImageProcessingConfig `yaml:"image_processing_config" json:"image_processing_config"`
 	// FAQ configuration (only for FAQ type knowledge bases)
 	FAQConfig *FAQConfig `yaml:"faq_config"              json:"faq_config"`
+	// Hybrid search fusion configuration
+	FusionConfig *FusionConfig `yaml:"fusion_config"           json:"fusion_config"`
+	// Down-weighting of search results close to expiry
+	ExpiryDecayConfig *ExpiryDecayConfig `yaml:"expiry_decay_config" json:"expiry_decay_config"`
+	// Near-duplicate document detection configuration
+	DuplicateDetectionConfig *DuplicateDetectionConfig `yaml:"duplicate_detection_config" json:"duplicate_detection_config"`
+	// Cross-lingual retrieval configuration
+	MultilingualConfig *MultilingualConfig `yaml:"multilingual_config" json:"multilingual_config"`
 }
 
+// ChunkingStrategy selects how documents are split into chunks
+type ChunkingStrategy string
+
+const (
+	// ChunkingStrategyCharacter splits by separators with sizes counted in characters (default)
+	ChunkingStrategyCharacter ChunkingStrategy = "character"
+	// ChunkingStrategyToken splits by separators with sizes counted in tokens of the embedding model
+	ChunkingStrategyToken ChunkingStrategy = "token"
+	// ChunkingStrategySentence packs whole sentences into chunks
+	ChunkingStrategySentence ChunkingStrategy = "sentence"
+	// ChunkingStrategySemantic packs sentences and splits where the embedding similarity of adjacent sentences drops
+	ChunkingStrategySemantic ChunkingStrategy = "semantic"
+)
+
 // ChunkingConfig represents the document splitting configuration
 type ChunkingConfig struct {
 	// Chunk size
@@ -103,6 +134,59 @@ type ChunkingConfig struct {
 	Separators []string `yaml:"separators"    json:"separators"`
 	// EnableMultimodal (deprecated, kept for backward compatibility with old data)
 	EnableMultimodal bool `yaml:"enable_multimodal,omitempty" json:"enable_multimodal,omitempty"`
+	// EnableParentChild stores chunks as parents and indexes small child chunks split from them,
+	// retrieval returns the parent of a matched child (small-to-big)
+	EnableParentChild bool `yaml:"enable_parent_child,omitempty" json:"enable_parent_child,omitempty"`
+	// ChildChunkSize is the size of child chunks, defaults to a quarter of the chunk size
+	ChildChunkSize int `yaml:"child_chunk_size,omitempty" json:"child_chunk_size,omitempty"`
+	// ChildChunkOverlap is the overlap of child chunks, defaults to an eighth of the child chunk size
+	ChildChunkOverlap int `yaml:"child_chunk_overlap,omitempty" json:"child_chunk_overlap,omitempty"`
+	// Strategy selects how text and Markdown are split, defaults to character.
+	// Other document types are split by docreader by characters.
+	Strategy ChunkingStrategy `yaml:"strategy,omitempty" json:"strategy,omitempty"`
+	// SemanticThreshold is the similarity of adjacent sentences below which the semantic strategy
+	// starts a new chunk, defaults to breaking at the most dissimilar tenth of sentence pairs
+	SemanticThreshold float64 `yaml:"semantic_threshold,omitempty" json:"semantic_threshold,omitempty"`
+}
+
+// GetStrategy returns the chunking strategy with its default applied
+func (c ChunkingConfig) GetStrategy() ChunkingStrategy {
+	if c.Strategy == "" {
+		return ChunkingStrategyCharacter
+	}
+	return c.Strategy
+}
+
+// Validate checks the chunking strategy and its parameters
+func (c ChunkingConfig) Validate() error {
+	switch c.Strategy {
+	case "", ChunkingStrategyCharacter, ChunkingStrategyToken, ChunkingStrategySentence, ChunkingStrategySemantic:
+	default:
+		return fmt.Errorf("unsupported chunking strategy %q", c.Strategy)
+	}
+	if c.SemanticThreshold < 0 || c.SemanticThreshold >= 1 {
+		return fmt.Errorf("semantic_threshold must be in [0, 1)")
+	}
+	return nil
+}
+
+// minChildChunkSize is the smallest default child chunk size
+const minChildChunkSize = 64
+
+// GetChildChunkSize returns the child chunk size with its default applied
+func (c ChunkingConfig) GetChildChunkSize() int {
+	if c.ChildChunkSize > 0 {
+		return c.ChildChunkSize
+	}
+	return max(c.ChunkSize/4, minChildChunkSize)
+}
+
+// GetChildChunkOverlap returns the child chunk overlap with its default applied
+func (c ChunkingConfig) GetChildChunkOverlap() int {
+	if c.ChildChunkOverlap > 0 {
+		return c.ChildChunkOverlap
+	}
+	return c.GetChildChunkSize() / 8
 }
 
 // COSConfig represents the COS configuration
@@ -282,6 +366,19 @@ func (e *ExtractConfig) Scan(value interface{}) error {
 type FAQConfig struct {
 	IndexMode         FAQIndexMode         `yaml:"index_mode"          json:"index_mode"`
 	QuestionIndexMode FAQQuestionIndexMode `yaml:"question_index_mode" json:"question_index_mode"`
+	// RequireReview keeps new and reworded entries as drafts until their reviewer approves them
+	RequireReview bool `yaml:"require_review" json:"require_review"`
+	// AutoMining opts in to the scheduled mining of FAQ candidates from chat history with these
+	// settings, nil leaves mining to manual runs
+	AutoMining *FAQMiningRequest `yaml:"auto_mining" json:"auto_mining,omitempty"`
+}
+
+// Validate checks the FAQ config
+func (f *FAQConfig) Validate() error {
+	if f == nil || f.AutoMining == nil {
+		return nil
+	}
+	return f.AutoMining.Validate()
 }
 
 // Value implements driver.Valuer
@@ -0,0 +1,173 @@
+package types
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// MetadataFilterOp represents the operator of a metadata filter condition
+type MetadataFilterOp string
+
+const (
+	// MetadataFilterOpEq matches when the metadata value equals Value
+	MetadataFilterOpEq MetadataFilterOp = "eq"
+	// MetadataFilterOpIn matches when the metadata value equals any of Values
+	MetadataFilterOpIn MetadataFilterOp = "in"
+	// MetadataFilterOpRange matches when the metadata value is within the Gt/Gte/Lt/Lte bounds
+	MetadataFilterOpRange MetadataFilterOp = "range"
+	// MetadataFilterOpExists matches when the metadata key is present
+	MetadataFilterOpExists MetadataFilterOp = "exists"
+)
+
+// maxCustomMetadataKeys limits the number of custom metadata keys on a knowledge
+const maxCustomMetadataKeys = 32
+
+// metadataKeyPattern restricts metadata keys to characters that are safe in every engine's field path
+var metadataKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// MetadataFilter is a condition on the custom metadata of knowledge.
+// Values are strings, numbers or booleans. Range bounds are either all numbers or
+// all strings, strings are compared lexically so ISO 8601 dates work as expected.
+// Qdrant has no lexical range, so string bounds must be dates there.
+type MetadataFilter struct {
+	Key    string           `json:"key"`
+	Op     MetadataFilterOp `json:"op"`
+	Value  any              `json:"value,omitempty"`  // Used by eq
+	Values []any            `json:"values,omitempty"` // Used by in
+	Gt     any              `json:"gt,omitempty"`     // Used by range
+	Gte    any              `json:"gte,omitempty"`    // Used by range
+	Lt     any              `json:"lt,omitempty"`     // Used by range
+	Lte    any              `json:"lte,omitempty"`    // Used by range
+}
+
+// MetadataFilters is a list of metadata filter conditions combined with AND
+type MetadataFilters []MetadataFilter
+
+// Normalize checks that every condition is well-formed and returns a copy with numeric values
+// converted to float64. The receiver is left untouched so it can be shared across goroutines.
+func (f MetadataFilters) Normalize() (MetadataFilters, error) {
+	if len(f) == 0 {
+		return nil, nil
+	}
+	normalized := make(MetadataFilters, len(f))
+	for i := range f {
+		normalized[i] = f[i]
+		normalized[i].Values = append([]any(nil), f[i].Values...)
+		if err := normalized[i].normalize(); err != nil {
+			return nil, fmt.Errorf("metadata_filters[%d]: %w", i, err)
+		}
+	}
+	return normalized, nil
+}
+
+// normalize checks and normalizes a single condition in place
+func (f *MetadataFilter) normalize() error {
+	if !metadataKeyPattern.MatchString(f.Key) {
+		return fmt.Errorf("invalid key %q, only letters, digits, '_' and '-' are allowed", f.Key)
+	}
+	switch f.Op {
+	case MetadataFilterOpEq:
+		value, ok := NormalizeMetadataValue(f.Value)
+		if !ok {
+			return fmt.Errorf("eq requires a string, number or boolean value")
+		}
+		f.Value = value
+	case MetadataFilterOpIn:
+		if len(f.Values) == 0 {
+			return fmt.Errorf("in requires at least one value")
+		}
+		for i, v := range f.Values {
+			value, ok := NormalizeMetadataValue(v)
+			if !ok {
+				return fmt.Errorf("in values must be strings, numbers or booleans")
+			}
+			f.Values[i] = value
+		}
+	case MetadataFilterOpRange:
+		kind := ""
+		for _, bound := range []*any{&f.Gt, &f.Gte, &f.Lt, &f.Lte} {
+			if *bound == nil {
+				continue
+			}
+			value, ok := NormalizeMetadataValue(*bound)
+			boundKind := metadataValueKind(value)
+			if !ok || boundKind == "bool" || (kind != "" && kind != boundKind) {
+				return fmt.Errorf("range bounds must be all numbers or all strings")
+			}
+			kind = boundKind
+			*bound = value
+		}
+		if kind == "" {
+			return fmt.Errorf("range requires at least one of gt, gte, lt, lte")
+		}
+	case MetadataFilterOpExists:
+	default:
+		return fmt.Errorf("unsupported op %q", f.Op)
+	}
+	return nil
+}
+
+// IsNumericRange reports whether the range bounds are numbers (as opposed to strings)
+func (f *MetadataFilter) IsNumericRange() bool {
+	for _, bound := range []any{f.Gt, f.Gte, f.Lt, f.Lte} {
+		if bound != nil {
+			return metadataValueKind(bound) == "number"
+		}
+	}
+	return false
+}
+
+// NormalizeMetadataValue converts a scalar metadata value to string, float64 or bool.
+// It returns false for nil, arrays, objects and other unsupported values.
+func NormalizeMetadataValue(v any) (any, bool) {
+	switch value := v.(type) {
+	case string, bool, float64:
+		return value, true
+	case float32:
+		return float64(value), true
+	case int:
+		return float64(value), true
+	case int32:
+		return float64(value), true
+	case int64:
+		return float64(value), true
+	case uint:
+		return float64(value), true
+	case uint32:
+		return float64(value), true
+	case uint64:
+		return float64(value), true
+	}
+	return nil, false
+}
+
+// metadataValueKind returns "string", "number" or "bool" for a normalized value
+func metadataValueKind(v any) string {
+	switch v.(type) {
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "bool"
+	}
+	return ""
+}
+
+// ValidateCustomMetadata checks custom knowledge metadata before it is stored and indexed
+func ValidateCustomMetadata(metadata map[string]any) error {
+	if len(metadata) > maxCustomMetadataKeys {
+		return fmt.Errorf("custom metadata cannot have more than %d keys", maxCustomMetadataKeys)
+	}
+	for key, value := range metadata {
+		if !metadataKeyPattern.MatchString(key) {
+			return fmt.Errorf("invalid custom metadata key %q, only letters, digits, '_' and '-' are allowed", key)
+		}
+		normalized, ok := NormalizeMetadataValue(value)
+		if !ok {
+			return fmt.Errorf("custom metadata %q must be a string, number or boolean", key)
+		}
+		metadata[key] = normalized
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// MaxKnowledgeBaseLanguages is the largest number of languages a knowledge base can be configured with
+const MaxKnowledgeBaseLanguages = 5
+
+// languageCodePattern matches ISO 639-1 or 639-2 language codes
+var languageCodePattern = regexp.MustCompile(`^[a-z]{2,3}$`)
+
+// MultilingualConfig configures cross-lingual retrieval of a knowledge base
+type MultilingualConfig struct {
+	// Languages are the ISO 639-1 codes of the languages of the documents, such as en or ko
+	Languages []string `yaml:"languages"       json:"languages"`
+	// TranslateQuery translates chat queries in other languages into Languages for an extra keyword retrieval
+	TranslateQuery bool `yaml:"translate_query" json:"translate_query"`
+}
+
+// Value implements driver.Valuer
+func (c MultilingualConfig) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
+
+// Scan implements sql.Scanner
+func (c *MultilingualConfig) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(b, c)
+}
+
+// Validate checks that the multilingual config is well-formed and lowercases its language codes
+func (c *MultilingualConfig) Validate() error {
+	if c == nil {
+		return nil
+	}
+	if len(c.Languages) > MaxKnowledgeBaseLanguages {
+		return fmt.Errorf("at most %d languages can be configured", MaxKnowledgeBaseLanguages)
+	}
+	seen := make(map[string]bool, len(c.Languages))
+	languages := make([]string, 0, len(c.Languages))
+	for _, language := range c.Languages {
+		language = strings.ToLower(strings.TrimSpace(language))
+		if !languageCodePattern.MatchString(language) {
+			return fmt.Errorf("invalid language code %q, use ISO 639-1 codes such as en or ko", language)
+		}
+		if !seen[language] {
+			seen[language] = true
+			languages = append(languages, language)
+		}
+	}
+	c.Languages = languages
+	if c.TranslateQuery && len(c.Languages) == 0 {
+		return fmt.Errorf("translate_query requires languages")
+	}
+	return nil
+}
+
+// TranslationLanguages returns the languages a query in queryLanguage is translated into,
+// none when translation is off or the query language is unknown
+func (c *MultilingualConfig) TranslationLanguages(queryLanguage string) []string {
+	if c == nil || !c.TranslateQuery || queryLanguage == "" {
+		return nil
+	}
+	var languages []string
+	for _, language := range c.Languages {
+		if language != queryLanguage {
+			languages = append(languages, language)
+		}
+	}
+	return languages
+}
@@ -10,6 +10,7 @@ const (
 	InfinityRetrieverEngineType      RetrieverEngineType = "infinity"
 	ElasticFaissRetrieverEngineType  RetrieverEngineType = "elasticfaiss"
 	QdrantRetrieverEngineType        RetrieverEngineType = "qdrant"
+	MilvusRetrieverEngineType        RetrieverEngineType = "milvus"
 )
 
 // RetrieverType represents the type of retriever
@@ -44,6 +45,8 @@ type RetrieveParams struct {
 	Threshold float64
 	// Knowledge type (e.g., "faq", "manual") - determines which index to use
 	KnowledgeType string
+	// Filters on the custom metadata of knowledge, combined with AND
+	MetadataFilters MetadataFilters
 	// Additional parameters, different retrievers may require different parameters
 	AdditionalParams map[string]interface{}
 	// Retriever type
@@ -82,6 +85,8 @@ type IndexWithScore struct {
 	MatchType MatchType
 	// IsEnabled
 	IsEnabled bool
+	// ScoreDetails records per-retriever ranks and scores, set by hybrid search fusion
+	ScoreDetails *ScoreDetails
 }
 
 // GetScore returns the score for ScoreComparable interface
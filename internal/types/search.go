@@ -91,6 +91,9 @@ type SearchResult struct {
 	// MatchedContent is the actual content that was matched in vector search
 	// For FAQ: this is the matched question text (standard or similar question)
 	MatchedContent string `json:"matched_content,omitempty"`
+
+	// ScoreDetails records the per-retriever ranks and scores behind Score
+	ScoreDetails *ScoreDetails `json:"score_details,omitempty"`
 }
 
 // SearchParams represents the search parameters
@@ -104,6 +107,10 @@ type SearchParams struct {
 	KnowledgeIDs         []string `json:"knowledge_ids"`
 	TagIDs               []string `json:"tag_ids"` // Tag IDs for filtering (used for FAQ priority filtering)
 	OnlyRecommended      bool     `json:"only_recommended"`
+	// Filters on the custom metadata of knowledge, combined with AND
+	MetadataFilters MetadataFilters `json:"metadata_filters,omitempty"`
+	// Fusion overrides the fusion config of the knowledge base for this request
+	Fusion *FusionConfig `json:"fusion,omitempty"`
 }
 
 // Value implements the driver.Valuer interface, used to convert SearchResult to database value
@@ -27,6 +27,9 @@ var retrieverEngineMapping = map[string][]RetrieverEngineParams{
 		{RetrieverType: KeywordsRetrieverType, RetrieverEngineType: QdrantRetrieverEngineType},
 		{RetrieverType: VectorRetrieverType, RetrieverEngineType: QdrantRetrieverEngineType},
 	},
+	"milvus": {
+		{RetrieverType: VectorRetrieverType, RetrieverEngineType: MilvusRetrieverEngineType},
+	},
 }
 
 // GetRetrieverEngineMapping returns the retriever engine mapping
@@ -3,6 +3,8 @@ package types
 import (
 	"database/sql/driver"
 	"encoding/json"
+	"fmt"
+	"maps"
 	"time"
 )
 
@@ -14,6 +16,12 @@ type WebSearchConfig struct {
 	IncludeDate       bool     `json:"include_date"`       // Whether to include date
 	CompressionMethod string   `json:"compression_method"` // Compression method: none, summary, extract, rag
 	Blacklist         []string `json:"blacklist"`          // Blacklist rule list
+	// Allowlist rule list, only results matching a rule are kept when set
+	Allowlist []string `json:"allowlist,omitempty"`
+	// Per-domain trust weights, above 1 boosts and below 1 demotes results of the domain and its subdomains, 0 drops them
+	TrustWeights map[string]float64 `json:"trust_weights,omitempty"`
+	// Whether to skip cached results and always query the provider, fresh results still refresh the cache
+	BypassCache bool `json:"bypass_cache,omitempty"`
 	// RAG compression related configuration
 	EmbeddingModelID   string `json:"embedding_model_id,omitempty"`  // Embedding model ID (for RAG compression)
 	EmbeddingDimension int    `json:"embedding_dimension,omitempty"` // Embedding dimension (for RAG compression)
@@ -38,6 +46,31 @@ func (c *WebSearchConfig) Scan(value interface{}) error {
 	return json.Unmarshal(b, c)
 }
 
+// WithOverrides returns a copy of the config with an allowlist replacing the configured one when set,
+// and trust weights merged over the configured ones
+func (c WebSearchConfig) WithOverrides(allowlist []string, trustWeights map[string]float64) *WebSearchConfig {
+	if len(allowlist) > 0 {
+		c.Allowlist = allowlist
+	}
+	if len(trustWeights) > 0 {
+		merged := make(map[string]float64, len(c.TrustWeights)+len(trustWeights))
+		maps.Copy(merged, c.TrustWeights)
+		maps.Copy(merged, trustWeights)
+		c.TrustWeights = merged
+	}
+	return &c
+}
+
+// ValidateWebSearchTrustWeights checks that trust weights are non-negative
+func ValidateWebSearchTrustWeights(trustWeights map[string]float64) error {
+	for domain, weight := range trustWeights {
+		if weight < 0 {
+			return fmt.Errorf("trust weight of %s must be non-negative", domain)
+		}
+	}
+	return nil
+}
+
 // WebSearchResult represents a single web search result
 type WebSearchResult struct {
 	Title       string     `json:"title"`                  // Search result title
@@ -46,6 +79,8 @@ type WebSearchResult struct {
 	Content     string     `json:"content"`                // Full content (optional, requires additional fetching)
 	Source      string     `json:"source"`                 // Source (e.g., duckduckgo, etc.)
 	PublishedAt *time.Time `json:"published_at,omitempty"` // Publication date (if available)
+	TrustWeight float64    `json:"trust_weight,omitempty"` // Trust weight of the result domain (if configured)
+	CachedAt    *time.Time `json:"cached_at,omitempty"`    // Time the result was cached (if served from cache)
 }
 
 // WebSearchProviderInfo represents information about a web search provider
@@ -57,3 +92,19 @@ type WebSearchProviderInfo struct {
 	Description    string `json:"description"`       // Description
 	APIURL         string `json:"api_url,omitempty"` // API URL (optional)
 }
+
+// SaveWebReferencesRequest selects web references of a chat message to save as knowledge
+type SaveWebReferencesRequest struct {
+	KnowledgeBaseID string   `json:"knowledge_base_id" binding:"required"`       // Target knowledge base ID
+	URLs            []string `json:"urls"              binding:"required,min=1"` // URLs of the web references to save
+	TagID           string   `json:"tag_id,omitempty"`                           // Tag assigned to the saved knowledge (optional)
+}
+
+// SavedWebReference is the outcome of saving one web reference
+type SavedWebReference struct {
+	URL         string `json:"url"`                    // URL of the web reference
+	KnowledgeID string `json:"knowledge_id,omitempty"` // ID of the created knowledge
+	// Whether the full page was fetched, otherwise the content of the reference was saved
+	FullContent bool   `json:"full_content"`
+	Error       string `json:"error,omitempty"` // Reason the reference was not saved
+}
@@ -0,0 +1,10 @@
+-- Remove custom metadata from knowledges and embeddings tables
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.tables WHERE table_name = 'embeddings') THEN
+        DROP INDEX IF EXISTS idx_embeddings_metadata;
+        ALTER TABLE embeddings DROP COLUMN IF EXISTS metadata;
+    END IF;
+    ALTER TABLE knowledges DROP COLUMN IF EXISTS custom_metadata;
+    RAISE NOTICE '[Migration 000012 Rollback] Removed custom metadata columns';
+END $$;
@@ -0,0 +1,14 @@
+-- Add custom metadata to knowledges and propagate it to the embeddings table for metadata filtering
+DO $$
+BEGIN
+    ALTER TABLE knowledges ADD COLUMN IF NOT EXISTS custom_metadata JSONB;
+    RAISE NOTICE '[Migration 000012] Added custom_metadata column to knowledges table';
+
+    IF EXISTS (SELECT 1 FROM information_schema.tables WHERE table_name = 'embeddings') THEN
+        ALTER TABLE embeddings ADD COLUMN IF NOT EXISTS metadata JSONB;
+        CREATE INDEX IF NOT EXISTS idx_embeddings_metadata ON embeddings USING GIN (metadata jsonb_path_ops);
+        RAISE NOTICE '[Migration 000012] Added metadata column and GIN index to embeddings table';
+    ELSE
+        RAISE NOTICE '[Migration 000012] embeddings table does not exist, skipping';
+    END IF;
+END $$;
@@ -0,0 +1,6 @@
+-- Remove hybrid search fusion config from knowledge bases
+DO $$
+BEGIN
+    ALTER TABLE knowledge_bases DROP COLUMN IF EXISTS fusion_config;
+    RAISE NOTICE '[Migration 000013 Rollback] Removed fusion_config column';
+END $$;
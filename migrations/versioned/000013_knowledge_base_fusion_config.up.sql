@@ -0,0 +1,6 @@
+-- Add hybrid search fusion config to knowledge bases
+DO $$
+BEGIN
+    ALTER TABLE knowledge_bases ADD COLUMN IF NOT EXISTS fusion_config JSONB;
+    RAISE NOTICE '[Migration 000013] Added fusion_config column to knowledge_bases table';
+END $$;
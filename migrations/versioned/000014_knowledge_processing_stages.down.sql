@@ -0,0 +1,5 @@
+-- Migration: 000014_knowledge_processing_stages (rollback)
+DO $$ BEGIN RAISE NOTICE '[Migration 000014 DOWN] Dropping table: knowledge_processing_stages'; END $$;
+DROP INDEX IF EXISTS idx_knowledge_processing_stages_kb_status;
+DROP INDEX IF EXISTS idx_knowledge_processing_stages_knowledge_stage;
+DROP TABLE IF EXISTS knowledge_processing_stages;
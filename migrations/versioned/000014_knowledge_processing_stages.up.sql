@@ -0,0 +1,28 @@
+-- Migration: 000014_knowledge_processing_stages
+-- Description: Track the status, timing and attempts of each knowledge processing stage
+DO $$ BEGIN RAISE NOTICE '[Migration 000014] Creating table: knowledge_processing_stages'; END $$;
+
+CREATE TABLE IF NOT EXISTS knowledge_processing_stages (
+    id VARCHAR(36) PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id INTEGER NOT NULL,
+    knowledge_base_id VARCHAR(36) NOT NULL,
+    knowledge_id VARCHAR(36) NOT NULL,
+    stage VARCHAR(32) NOT NULL,
+    status VARCHAR(32) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    error_message TEXT NOT NULL DEFAULT '',
+    total_items INTEGER NOT NULL DEFAULT 0,
+    done_items INTEGER NOT NULL DEFAULT 0,
+    started_at TIMESTAMP WITH TIME ZONE,
+    finished_at TIMESTAMP WITH TIME ZONE,
+    duration_ms BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_knowledge_processing_stages_knowledge_stage
+    ON knowledge_processing_stages(knowledge_id, stage);
+CREATE INDEX IF NOT EXISTS idx_knowledge_processing_stages_kb_status
+    ON knowledge_processing_stages(tenant_id, knowledge_base_id, status);
+
+DO $$ BEGIN RAISE NOTICE '[Migration 000014] knowledge_processing_stages setup completed'; END $$;
@@ -0,0 +1,6 @@
+-- Migration: 000015_chunk_edits (rollback)
+DO $$ BEGIN RAISE NOTICE '[Migration 000015 DOWN] Dropping table: chunk_edits'; END $$;
+DROP INDEX IF EXISTS idx_chunk_edits_related_chunk;
+DROP INDEX IF EXISTS idx_chunk_edits_chunk;
+DROP INDEX IF EXISTS idx_chunk_edits_knowledge;
+DROP TABLE IF EXISTS chunk_edits;
@@ -0,0 +1,23 @@
+-- Migration: 000015_chunk_edits
+-- Description: Keep the history of manual chunk edits, splits and merges so they survive re-ingestion
+DO $$ BEGIN RAISE NOTICE '[Migration 000015] Creating table: chunk_edits'; END $$;
+
+CREATE TABLE IF NOT EXISTS chunk_edits (
+    id VARCHAR(36) PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id INTEGER NOT NULL,
+    knowledge_base_id VARCHAR(36) NOT NULL,
+    knowledge_id VARCHAR(36) NOT NULL,
+    chunk_id VARCHAR(36) NOT NULL,
+    related_chunk_id VARCHAR(36) NOT NULL DEFAULT '',
+    operation VARCHAR(16) NOT NULL,
+    contents_before JSONB NOT NULL DEFAULT '[]',
+    contents_after JSONB NOT NULL DEFAULT '[]',
+    orphaned BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_chunk_edits_knowledge ON chunk_edits(tenant_id, knowledge_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_chunk_edits_chunk ON chunk_edits(chunk_id);
+CREATE INDEX IF NOT EXISTS idx_chunk_edits_related_chunk ON chunk_edits(related_chunk_id);
+
+DO $$ BEGIN RAISE NOTICE '[Migration 000015] chunk_edits setup completed'; END $$;
@@ -0,0 +1,16 @@
+-- Rollback: 000016_knowledge_expiry
+DO $$ BEGIN RAISE NOTICE '[Migration 000016 Rollback] Removing knowledge validity period...'; END $$;
+
+ALTER TABLE knowledge_bases DROP COLUMN IF EXISTS expiry_decay_config;
+
+DROP INDEX IF EXISTS idx_knowledges_review_by;
+DROP INDEX IF EXISTS idx_knowledges_valid_from;
+DROP INDEX IF EXISTS idx_knowledges_valid_until;
+ALTER TABLE knowledges DROP COLUMN IF EXISTS review_by;
+ALTER TABLE knowledges DROP COLUMN IF EXISTS valid_until;
+ALTER TABLE knowledges DROP COLUMN IF EXISTS valid_from;
+
+-- Knowledge disabled by expiry is enabled again in the status, its index entries stay disabled
+UPDATE knowledges SET enable_status = 'enabled' WHERE enable_status = 'expired';
+
+DO $$ BEGIN RAISE NOTICE '[Migration 000016 Rollback] Completed'; END $$;
@@ -0,0 +1,14 @@
+-- Migration: 000016_knowledge_expiry
+-- Description: Add validity period and review date to knowledge, and expiry down-weighting to knowledge bases
+DO $$ BEGIN RAISE NOTICE '[Migration 000016] Adding knowledge validity period...'; END $$;
+
+ALTER TABLE knowledges ADD COLUMN IF NOT EXISTS valid_from TIMESTAMP WITH TIME ZONE;
+ALTER TABLE knowledges ADD COLUMN IF NOT EXISTS valid_until TIMESTAMP WITH TIME ZONE;
+ALTER TABLE knowledges ADD COLUMN IF NOT EXISTS review_by TIMESTAMP WITH TIME ZONE;
+CREATE INDEX IF NOT EXISTS idx_knowledges_valid_until ON knowledges(valid_until) WHERE valid_until IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_knowledges_valid_from ON knowledges(valid_from) WHERE valid_from IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_knowledges_review_by ON knowledges(review_by) WHERE review_by IS NOT NULL;
+
+ALTER TABLE knowledge_bases ADD COLUMN IF NOT EXISTS expiry_decay_config JSONB;
+
+DO $$ BEGIN RAISE NOTICE '[Migration 000016] Knowledge validity period setup completed'; END $$;
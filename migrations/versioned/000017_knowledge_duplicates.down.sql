@@ -0,0 +1,11 @@
+-- Rollback: 000017_knowledge_duplicates
+DO $$ BEGIN RAISE NOTICE '[Migration 000017 Rollback] Removing near-duplicate detection...'; END $$;
+
+ALTER TABLE knowledge_bases DROP COLUMN IF EXISTS duplicate_detection_config;
+
+DROP INDEX IF EXISTS idx_knowledges_duplicate_of;
+ALTER TABLE knowledges DROP COLUMN IF EXISTS duplicate_similarity;
+ALTER TABLE knowledges DROP COLUMN IF EXISTS duplicate_of;
+ALTER TABLE knowledges DROP COLUMN IF EXISTS content_signature;
+
+DO $$ BEGIN RAISE NOTICE '[Migration 000017 Rollback] Completed'; END $$;
@@ -0,0 +1,12 @@
+-- Migration: 000017_knowledge_duplicates
+-- Description: Add content signatures for near-duplicate detection to knowledge, and duplicate detection config to knowledge bases
+DO $$ BEGIN RAISE NOTICE '[Migration 000017] Adding near-duplicate detection...'; END $$;
+
+ALTER TABLE knowledges ADD COLUMN IF NOT EXISTS content_signature JSONB;
+ALTER TABLE knowledges ADD COLUMN IF NOT EXISTS duplicate_of VARCHAR(36);
+ALTER TABLE knowledges ADD COLUMN IF NOT EXISTS duplicate_similarity DOUBLE PRECISION NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_knowledges_duplicate_of ON knowledges(duplicate_of) WHERE duplicate_of IS NOT NULL AND duplicate_of <> '';
+
+ALTER TABLE knowledge_bases ADD COLUMN IF NOT EXISTS duplicate_detection_config JSONB;
+
+DO $$ BEGIN RAISE NOTICE '[Migration 000017] Near-duplicate detection setup completed'; END $$;
@@ -0,0 +1,5 @@
+-- Migration: 000018_faq_mining (rollback)
+DO $$ BEGIN RAISE NOTICE '[Migration 000018 DOWN] Removing FAQ mining...'; END $$;
+DROP INDEX IF EXISTS idx_faq_candidates_kb_status;
+DROP TABLE IF EXISTS faq_candidates;
+ALTER TABLE messages DROP COLUMN IF EXISTS is_fallback;
@@ -0,0 +1,29 @@
+-- Migration: 000018_faq_mining
+-- Description: Flag fallback answers on messages and add the review queue of FAQ candidates mined from chat history
+DO $$ BEGIN RAISE NOTICE '[Migration 000018] Adding FAQ mining...'; END $$;
+
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS is_fallback BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS faq_candidates (
+    id VARCHAR(36) PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id INTEGER NOT NULL,
+    knowledge_base_id VARCHAR(36) NOT NULL,
+    standard_question TEXT NOT NULL,
+    similar_questions JSONB NOT NULL DEFAULT '[]',
+    answer TEXT NOT NULL DEFAULT '',
+    source_chunk_ids JSONB NOT NULL DEFAULT '[]',
+    message_ids JSONB NOT NULL DEFAULT '[]',
+    question_count INTEGER NOT NULL DEFAULT 0,
+    fallback_count INTEGER NOT NULL DEFAULT 0,
+    max_score DOUBLE PRECISION NOT NULL DEFAULT 0,
+    last_asked_at TIMESTAMP WITH TIME ZONE,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    faq_entry_id BIGINT NOT NULL DEFAULT 0,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_faq_candidates_kb_status ON faq_candidates(tenant_id, knowledge_base_id, status, created_at);
+
+DO $$ BEGIN RAISE NOTICE '[Migration 000018] FAQ mining setup completed'; END $$;
@@ -0,0 +1,4 @@
+-- Migration: 000019_faq_review (rollback)
+DO $$ BEGIN RAISE NOTICE '[Migration 000019 DOWN] Removing FAQ review comments...'; END $$;
+DROP INDEX IF EXISTS idx_faq_entry_comments_chunk;
+DROP TABLE IF EXISTS faq_entry_comments;
@@ -0,0 +1,21 @@
+-- Migration: 000019_faq_review
+-- Description: Add review comments of FAQ entries; the review status and reviewer live in the FAQ chunk metadata
+DO $$ BEGIN RAISE NOTICE '[Migration 000019] Adding FAQ review comments...'; END $$;
+
+CREATE TABLE IF NOT EXISTS faq_entry_comments (
+    id VARCHAR(36) PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id INTEGER NOT NULL,
+    knowledge_base_id VARCHAR(36) NOT NULL,
+    chunk_id VARCHAR(36) NOT NULL,
+    entry_id BIGINT NOT NULL DEFAULT 0,
+    user_id VARCHAR(36) NOT NULL DEFAULT '',
+    username VARCHAR(100) NOT NULL DEFAULT '',
+    action VARCHAR(16) NOT NULL DEFAULT '',
+    review_status VARCHAR(16) NOT NULL DEFAULT '',
+    content TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_faq_entry_comments_chunk ON faq_entry_comments(tenant_id, chunk_id, created_at);
+
+DO $$ BEGIN RAISE NOTICE '[Migration 000019] FAQ review setup completed'; END $$;
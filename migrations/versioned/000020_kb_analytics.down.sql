@@ -0,0 +1,12 @@
+-- Migration: 000020_kb_analytics (rollback)
+DO $$ BEGIN RAISE NOTICE '[Migration 000020 DOWN] Removing knowledge base analytics...'; END $$;
+DROP INDEX IF EXISTS idx_message_feedback_chunks_chunk;
+DROP INDEX IF EXISTS idx_message_feedback_chunks_kb;
+DROP TABLE IF EXISTS message_feedback_chunks;
+DROP INDEX IF EXISTS idx_message_feedbacks_message;
+DROP TABLE IF EXISTS message_feedbacks;
+DROP INDEX IF EXISTS idx_query_logs_kb_type;
+DROP TABLE IF EXISTS query_logs;
+DROP INDEX IF EXISTS idx_knowledge_hit_stats_knowledge;
+DROP INDEX IF EXISTS idx_knowledge_hit_stats_kb_date;
+DROP TABLE IF EXISTS knowledge_hit_stats;
@@ -0,0 +1,64 @@
+-- Migration: 000020_kb_analytics
+-- Description: Add daily chunk hit counters, logs of poorly answered chat queries and feedback on assistant messages
+DO $$ BEGIN RAISE NOTICE '[Migration 000020] Adding knowledge base analytics...'; END $$;
+
+CREATE TABLE IF NOT EXISTS knowledge_hit_stats (
+    tenant_id INTEGER NOT NULL,
+    knowledge_base_id VARCHAR(36) NOT NULL,
+    knowledge_id VARCHAR(36) NOT NULL DEFAULT '',
+    chunk_id VARCHAR(36) NOT NULL,
+    chunk_type VARCHAR(20) NOT NULL DEFAULT '',
+    stat_date DATE NOT NULL,
+    hit_count BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (chunk_id, stat_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_knowledge_hit_stats_kb_date ON knowledge_hit_stats(tenant_id, knowledge_base_id, stat_date);
+CREATE INDEX IF NOT EXISTS idx_knowledge_hit_stats_knowledge ON knowledge_hit_stats(knowledge_id, stat_date);
+
+CREATE TABLE IF NOT EXISTS query_logs (
+    id VARCHAR(36) PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id INTEGER NOT NULL,
+    knowledge_base_id VARCHAR(36) NOT NULL,
+    session_id VARCHAR(36) NOT NULL DEFAULT '',
+    message_id VARCHAR(36) NOT NULL DEFAULT '',
+    query TEXT NOT NULL DEFAULT '',
+    rewrite_query TEXT NOT NULL DEFAULT '',
+    type VARCHAR(20) NOT NULL,
+    result_count INTEGER NOT NULL DEFAULT 0,
+    max_score DOUBLE PRECISION NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_query_logs_kb_type ON query_logs(tenant_id, knowledge_base_id, type, created_at);
+
+CREATE TABLE IF NOT EXISTS message_feedbacks (
+    id VARCHAR(36) PRIMARY KEY DEFAULT uuid_generate_v4(),
+    tenant_id INTEGER NOT NULL,
+    session_id VARCHAR(36) NOT NULL,
+    message_id VARCHAR(36) NOT NULL,
+    user_id VARCHAR(36) NOT NULL DEFAULT '',
+    rating VARCHAR(8) NOT NULL,
+    comment TEXT NOT NULL DEFAULT '',
+    chunk_ids JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_message_feedbacks_message ON message_feedbacks(message_id);
+
+CREATE TABLE IF NOT EXISTS message_feedback_chunks (
+    feedback_id VARCHAR(36) NOT NULL,
+    chunk_id VARCHAR(36) NOT NULL,
+    tenant_id INTEGER NOT NULL,
+    knowledge_base_id VARCHAR(36) NOT NULL,
+    knowledge_id VARCHAR(36) NOT NULL DEFAULT '',
+    rating VARCHAR(8) NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (feedback_id, chunk_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_message_feedback_chunks_kb ON message_feedback_chunks(tenant_id, knowledge_base_id, updated_at);
+CREATE INDEX IF NOT EXISTS idx_message_feedback_chunks_chunk ON message_feedback_chunks(chunk_id);
+
+DO $$ BEGIN RAISE NOTICE '[Migration 000020] Knowledge base analytics setup completed'; END $$;
@@ -0,0 +1,6 @@
+-- Rollback: 000021_kb_multilingual
+DO $$ BEGIN RAISE NOTICE '[Migration 000021 Rollback] Removing multilingual config...'; END $$;
+
+ALTER TABLE knowledge_bases DROP COLUMN IF EXISTS multilingual_config;
+
+DO $$ BEGIN RAISE NOTICE '[Migration 000021 Rollback] Completed'; END $$;
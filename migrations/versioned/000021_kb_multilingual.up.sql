@@ -0,0 +1,7 @@
+-- Migration: 000021_kb_multilingual
+-- Description: Add multilingual config (document languages and query translation) to knowledge bases
+DO $$ BEGIN RAISE NOTICE '[Migration 000021] Adding multilingual config to knowledge bases...'; END $$;
+
+ALTER TABLE knowledge_bases ADD COLUMN IF NOT EXISTS multilingual_config JSONB;
+
+DO $$ BEGIN RAISE NOTICE '[Migration 000021] Multilingual config setup completed'; END $$;